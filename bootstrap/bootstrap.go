@@ -50,12 +50,14 @@ import (
 
 	_ "github.com/viant/endly/deployment/build"
 	_ "github.com/viant/endly/deployment/deploy"
+	_ "github.com/viant/endly/deployment/publish"
 	_ "github.com/viant/endly/deployment/sdk"
 	_ "github.com/viant/endly/deployment/vc"
 	_ "github.com/viant/endly/deployment/vc/git"
 
 	_ "github.com/viant/endly/notify/slack"
 	_ "github.com/viant/endly/notify/smtp"
+	_ "github.com/viant/endly/notify/summary"
 
 	_ "github.com/viant/endly/system/cloud/aws/apigateway"
 	_ "github.com/viant/endly/system/cloud/aws/cloudwatch"
@@ -97,13 +99,18 @@ import (
 	_ "github.com/viant/endly/system/kubernetes/settings"
 	_ "github.com/viant/endly/system/kubernetes/storage"
 
+	_ "github.com/viant/endly/system/chaos"
+	_ "github.com/viant/endly/system/clock"
 	_ "github.com/viant/endly/system/daemon"
 	_ "github.com/viant/endly/system/docker"
 	_ "github.com/viant/endly/system/docker/ssh"
+	_ "github.com/viant/endly/system/eval"
 	_ "github.com/viant/endly/system/exec"
+	_ "github.com/viant/endly/system/inventory"
 	_ "github.com/viant/endly/system/network"
 	_ "github.com/viant/endly/system/process"
 	_ "github.com/viant/endly/system/storage"
+	_ "github.com/viant/endly/system/workspace"
 
 	"bufio"
 	"errors"
@@ -112,6 +119,8 @@ import (
 	"github.com/viant/endly/gen/web"
 	"github.com/viant/endly/meta"
 	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/tracing"
+	"github.com/viant/endly/server"
 	"github.com/viant/endly/workflow"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/cred"
@@ -133,6 +142,24 @@ import (
 	"time"
 )
 
+//defaultServerPort represents endly server default port
+const defaultServerPort = "8070"
+
+//paramsFlag represents repeated key=value workflow param overrides
+type paramsFlag []string
+
+func (f *paramsFlag) String() string {
+	return strings.Join(*f, " ")
+}
+
+//Set adds param override
+func (f *paramsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+var paramOverrides = &paramsFlag{}
+
 func init() {
 
 	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
@@ -142,19 +169,49 @@ func init() {
 	flag.String("i", "", "<coma separated tagID list> to filter")
 
 	flag.String("t", "*", "<task/s to run>, t='?' to list all tasks for selected workflow")
-
-	flag.String("l", "logs", "<log directory>")
+	flag.String("tasks", "", "<task/s to run> alias for -t option")
+	flag.Var(paramOverrides, "param", "<key=value> workflow param override, can be repeated, i.e. -param=app=myapp -param=db.host=127.0.0.1")
+	flag.String("env", "", "<profile> environment profile from run request profiles merged over params, i.e. -env=staging")
+	flag.String("metrics", "", "<file> to write workflow run metrics (actions/tasks duration, sleep time, retries) in Prometheus text format")
+	flag.Bool("dry", false, "dry run: validate workflow services, actions, requests and variables without executing any action")
+	flag.Bool("strict", false, "fail fast: abort workflow on the first validation failure")
+	flag.String("otel", "", "<OTLP HTTP endpoint> i.e. http://127.0.0.1:4318 to export workflow, task and action OpenTelemetry spans")
+
+	flag.String("l", "logs", "<log directory> for logs, reports and checkpoints")
+	flag.Int("lsize", 0, "<max total size in MB> of all session log directories, the oldest sessions are removed first, works only with -d option")
+	flag.Int("lage", 0, "<max age in hours> of session log directory, works only with -d option")
+	flag.Int("lseg", 0, "<max segment size in MB> of session event log, once exceeded written events are rolled into a segment, works only with -d option")
+	flag.Bool("lgz", false, "gzip rolled session event log segments, works only with -lseg option")
+	flag.String("lfmt", "", "<event log format> files (default) - JSON file per event, ndjson - newline delimited JSON with stable schema, works only with -d option")
 	flag.Bool("d", false, "enable logging")
+	flag.String("audit", "", "<audit log file> to record every executed action expanded request")
+	flag.String("session", "", "<session ID> to persist state, opened targets and log listeners at exit and re-attach them in subsequent run")
+	flag.Bool("sdiff", false, "publish state diff at each task boundary")
+	flag.Bool("trace", false, "publish redacted state snapshots and diff for each action that changed state")
+	flag.Bool("resume", false, "resume previously failed workflow from checkpoint in log directory")
+	flag.String("rerun-failed", "", "previous run session ID or JSON report file, only failed actions and their dependencies are run")
+	flag.Bool("no-cache", false, "ignore cached responses of actions with cache option, responses are cached again")
+	flag.Bool("keep-workspace", false, "keep session workspace directories for troubleshooting")
+	flag.String("stream", "", "<address> to stream workflow events as Server-Sent Events on /v1/endly/events, i.e. -stream=:8072")
+	flag.Bool("debug", false, "start workflow paused and step through actions: enter runs the next action, c continues")
+	flag.String("format", "", "<output format> tree|plain|json, tree with live progress on terminal, plain key=value lines when piped by default")
+	flag.String("report", "", "<coma separated report formats> junit,json written to log directory once workflow completes")
 
 	flag.Bool("p", false, "print workflow  as JSON or YAML")
 	flag.String("f", "json", "<workflow or request format>, json or yaml")
 
 	flag.Bool("h", false, "print help")
 	flag.Bool("v", false, "print version")
+	flag.String("update", "", "<version|latest> update endly binary, i.e. endly update [version], version pinned in .endly-version file is used by default")
+	flag.String("new", "", "<project template> generate starter project: rest-app|docker-app|bigdata, i.e. endly init [template] [app]")
+	flag.String("app", "", "<application name> for generated project, works only with -new option")
+	flag.String("server", "", "<port> start endly server exposing workflow run REST API, i.e. endly server [port], default port "+defaultServerPort)
+	flag.Bool("console", false, "start interactive console running service:action with JSON/YAML request, recorded actions can be saved as workflow, i.e. endly console")
 
 	flag.Bool("j", false, "list user defined function (UDF)")
 	flag.String("s", "", "<serviceID> print service details, -s='*' prints all service IDs")
 	flag.String("a", "", "<action> prints service action request/response detail")
+	flag.String("schema", "", "<serviceID|serviceID:action|*> print service actions request/response JSON schema, i.e. endly schema [exec:run] > endly.schema.json")
 
 	flag.String("c", "", "<credentials>, generate secret credentials file: ~/.secret/<credentials>.json")
 	flag.String("k", "", "<private key path>,  works only with -c options, i.e -k="+path.Join(os.Getenv("HOME"), ".secret/id_rsa"))
@@ -168,6 +225,7 @@ func init() {
 	flag.Int("e", 5, "max number of failures CLI reported per validation, 0 - all failures reported")
 	flag.String("run", "", "run specified service action it expect valid service:action to run")
 	flag.String("req", "", "optional request URL when run option is specified")
+	flag.String("plugins", "", "<third party service manifest URL>, default ~/.endly/services.yaml or ENDLY_SERVICE_MANIFEST env")
 	_ = mysql.SetLogger(&emptyLogger{})
 
 }
@@ -180,6 +238,46 @@ func detectFirstArguments(flagset map[string]string) {
 	if strings.Contains(candidate, "=") {
 		return
 	}
+	if candidate == "update" {
+		flagset["update"] = "latest"
+		if len(os.Args) > 2 && !strings.HasPrefix(os.Args[2], "-") {
+			flagset["update"] = os.Args[2]
+		}
+		os.Args = os.Args[:1]
+		return
+	}
+	if candidate == "init" {
+		flagset["new"] = ""
+		if len(os.Args) > 2 && !strings.HasPrefix(os.Args[2], "-") {
+			flagset["new"] = os.Args[2]
+		}
+		if len(os.Args) > 3 && !strings.HasPrefix(os.Args[3], "-") {
+			flagset["app"] = os.Args[3]
+		}
+		os.Args = os.Args[:1]
+		return
+	}
+	if candidate == "server" {
+		flagset["server"] = defaultServerPort
+		if len(os.Args) > 2 && !strings.HasPrefix(os.Args[2], "-") {
+			flagset["server"] = os.Args[2]
+		}
+		os.Args = os.Args[:1]
+		return
+	}
+	if candidate == "schema" {
+		flagset["schema"] = "*"
+		if len(os.Args) > 2 && !strings.HasPrefix(os.Args[2], "-") {
+			flagset["schema"] = os.Args[2]
+		}
+		os.Args = os.Args[:1]
+		return
+	}
+	if candidate == "console" {
+		flagset["console"] = "true"
+		os.Args = os.Args[:1]
+		return
+	}
 	if strings.Contains(candidate, ":") {
 		flagset["run"] = os.Args[1]
 	} else {
@@ -209,6 +307,39 @@ func Bootstrap() {
 			flagset[f.Name] = f.Value.String()
 		}
 	})
+	if value, ok := flagset["tasks"]; ok {
+		flagset["t"] = value
+	}
+	if manifestURL := serviceManifestURL(flagset); manifestURL != "" {
+		if err := LoadServiceManifest(manifestURL); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if version, ok := flagset["update"]; ok {
+		if err := Update(version); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if template, ok := flagset["new"]; ok {
+		if err := scaffold(template, flagset["app"]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if port, ok := flagset["server"]; ok {
+		if err := server.New(port).Start(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if toolbox.AsBoolean(flagset["console"]) {
+		if err := cli.NewConsole(os.Stdin, os.Stdout).Start(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	warnIfNotPinnedVersion()
 	_, shouldQuit := flagset["v"]
 	flagset["v"] = flag.Lookup("v").Value.String()
 
@@ -243,6 +374,11 @@ func Bootstrap() {
 		return
 	}
 
+	if selector, ok := flagset["schema"]; ok {
+		printServiceSchema(selector)
+		return
+	}
+
 	if _, ok := flagset["s"]; ok {
 		printServiceActions()
 		return
@@ -299,7 +435,7 @@ func Bootstrap() {
 		return
 	}
 	interactive, ok := flagset["m"]
-	runWorkflow(request, ok && toolbox.AsBoolean(interactive))
+	runWorkflow(request, ok && toolbox.AsBoolean(interactive), flagset["format"])
 }
 
 func runAction(run string, flagset map[string]string) error {
@@ -334,12 +470,13 @@ func runAction(run string, flagset map[string]string) error {
 		return nil
 	}
 	interactive, ok := flagset["m"]
-	runWorkflow(request, ok && toolbox.AsBoolean(interactive))
+	runWorkflow(request, ok && toolbox.AsBoolean(interactive), flagset["format"])
 	return nil
 }
 
-func runWorkflow(request *workflow.RunRequest, interactive bool) {
+func runWorkflow(request *workflow.RunRequest, interactive bool, format string) {
 	runner := cli.New()
+	runner.Format = format
 	request.Interactive = interactive
 	err := runner.Run(request)
 	if err != nil {
@@ -355,7 +492,7 @@ func runWorkflow(request *workflow.RunRequest, interactive bool) {
 func printUDFs() {
 	manager := endly.New()
 	context := manager.NewContext(nil)
-	state := context.State()
+	state := context.SafeState().Clone()
 	var udfs = make([]string, 0)
 	for k, v := range state {
 		if toolbox.IsFunc(v) {
@@ -599,6 +736,18 @@ func printServiceActionRequest() {
 	printStructMeta(renderer, "green", meta.ResponseMeta)
 }
 
+func printServiceSchema(selector string) {
+	schema, err := meta.New().Schema(selector)
+	if err != nil {
+		log.Fatal(err)
+	}
+	text, err := toolbox.AsIndentJSONText(schema)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(text)
+}
+
 func printServiceActions() {
 	manager := endly.New()
 	context := manager.NewContext(toolbox.NewContext())
@@ -647,7 +796,7 @@ func getWorkflow(request *workflow.RunRequest) (*model.Workflow, error) {
 	manager := endly.New()
 	context := manager.NewContext(nil)
 	var response = &workflow.LoadResponse{}
-	var source = workflow.GetResource(workflow.NewDao(), context.State(), request.URL)
+	var source = workflow.GetResource(workflow.NewDao(), context.SafeState().Clone(), request.URL)
 	if err := endly.Run(context, &workflow.LoadRequest{Source: source}, response); err != nil {
 		return nil, err
 	}
@@ -789,21 +938,92 @@ func updateBaseRunWithOptions(request *workflow.RunRequest, flagset map[string]s
 		return err
 	}
 	if request != nil {
+		if value, ok := flagset["env"]; ok {
+			request.Env = value
+		}
+		if err = request.ApplyProfile(); err != nil { //command line params take precedence over profile
+			return err
+		}
 		if len(request.Params) == 0 {
 			request.Params = params
 		}
 		for k, v := range params {
 			request.Params[k] = v
 		}
+		if len(*paramOverrides) > 0 {
+			if request.Params == nil {
+				request.Params = make(map[string]interface{})
+			}
+			if err = util.ApplyArguments(request.Params, *paramOverrides, parentURL); err != nil {
+				return err
+			}
+		}
 		if value, ok := flagset["d"]; ok {
 			go enableDiagnostics()
 			request.EnableLogging = toolbox.AsBoolean(value)
 			request.LogDirectory = flag.Lookup("l").Value.String()
+			maxSize := toolbox.AsInt(flag.Lookup("lsize").Value.String())
+			maxAge := toolbox.AsInt(flag.Lookup("lage").Value.String())
+			if maxSize > 0 || maxAge > 0 {
+				request.LogRetention = &workflow.LogRetention{MaxSizeMb: maxSize, MaxAgeHours: maxAge}
+			}
+			if maxSegmentSize := toolbox.AsInt(flag.Lookup("lseg").Value.String()); maxSegmentSize > 0 {
+				request.LogRotation = &workflow.LogRotation{MaxSegmentSizeMb: maxSegmentSize, Compress: toolbox.AsBoolean(flag.Lookup("lgz").Value.String())}
+			}
+			request.LogFormat = flag.Lookup("lfmt").Value.String()
 		}
 	}
 	if value, ok := flagset["e"]; ok {
 		request.FailureCount = toolbox.AsInt(value)
 	}
+	if value, ok := flagset["audit"]; ok {
+		request.AuditLog = value
+	}
+	if value, ok := flagset["session"]; ok {
+		request.Session = value
+	}
+	if value, ok := flagset["sdiff"]; ok {
+		request.StateDiff = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["trace"]; ok {
+		request.TraceState = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["resume"]; ok {
+		request.Resume = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["rerun-failed"]; ok {
+		request.RerunFailed = value
+	}
+	if value, ok := flagset["no-cache"]; ok {
+		request.NoCache = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["keep-workspace"]; ok {
+		request.KeepWorkspace = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["stream"]; ok {
+		request.StreamAddress = value
+	}
+	if value, ok := flagset["debug"]; ok {
+		request.Debug = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["report"]; ok {
+		request.Report = value
+	}
+	if value, ok := flagset["l"]; ok {
+		request.LogDirectory = value
+	}
+	if value, ok := flagset["metrics"]; ok {
+		request.MetricsFile = value
+	}
+	if value, ok := flagset["dry"]; ok {
+		request.DryRun = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["strict"]; ok {
+		request.FailFast = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["otel"]; ok {
+		request.Tracing = &tracing.Config{Endpoint: value}
+	}
 	return nil
 }
 
@@ -0,0 +1,89 @@
+package bootstrap
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"os"
+	"path"
+	"plugin"
+)
+
+//EndlyServiceManifest env key name to customize third party service manifest location
+const EndlyServiceManifest = "ENDLY_SERVICE_MANIFEST"
+
+const defaultServiceProviderSymbol = "ServiceProvider"
+
+//ServicePlugin represents a third party service Go plugin
+type ServicePlugin struct {
+	Name     string `description:"service plugin name"`
+	Location string `description:"Go plugin (.so) location"`
+	Symbol   string `description:"exported symbol of endly.ServiceProvider or func() endly.Service type, default ServiceProvider"`
+}
+
+//ServiceManifest represents third party services manifest
+type ServiceManifest struct {
+	Services []*ServicePlugin
+}
+
+func (p *ServicePlugin) load() (endly.ServiceProvider, error) {
+	if p.Symbol == "" {
+		p.Symbol = defaultServiceProviderSymbol
+	}
+	aPlugin, err := plugin.Open(p.Location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open service plugin %v, %v", p.Location, err)
+	}
+	symbol, err := aPlugin.Lookup(p.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup %v in service plugin %v, %v", p.Symbol, p.Location, err)
+	}
+	switch provider := symbol.(type) {
+	case func() endly.Service:
+		return provider, nil
+	case endly.ServiceProvider:
+		return provider, nil
+	case *endly.ServiceProvider:
+		return *provider, nil
+	}
+	return nil, fmt.Errorf("unsupported service plugin %v symbol %v type: %T", p.Location, p.Symbol, symbol)
+}
+
+//LoadServiceManifest loads and registers third party services defined in the manifest
+func LoadServiceManifest(URL string) error {
+	resource := url.NewResource(URL)
+	manifest := &ServiceManifest{}
+	if err := resource.Decode(manifest); err != nil {
+		return fmt.Errorf("failed to decode service manifest %v, %v", URL, err)
+	}
+	baseURL, _ := toolbox.URLSplit(resource.URL)
+	for _, servicePlugin := range manifest.Services {
+		if !path.IsAbs(servicePlugin.Location) {
+			servicePlugin.Location = path.Join(url.NewResource(baseURL).ParsedURL.Path, servicePlugin.Location)
+		}
+		provider, err := servicePlugin.load()
+		if err != nil {
+			return err
+		}
+		if err = endly.RegisterServiceProvider(provider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//serviceManifestURL returns manifest location from flag, env or default ~/.endly/services.yaml
+func serviceManifestURL(flagset map[string]string) string {
+	if value, ok := flagset["plugins"]; ok {
+		return value
+	}
+	if value := os.Getenv(EndlyServiceManifest); value != "" {
+		return value
+	}
+	defaultLocation := path.Join(os.Getenv("HOME"), ".endly", "services.yaml")
+	if toolbox.FileExists(defaultLocation) {
+		return defaultLocation
+	}
+	return ""
+}
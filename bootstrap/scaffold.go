@@ -0,0 +1,49 @@
+package bootstrap
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/gen/web"
+	"github.com/viant/toolbox"
+	"golang.org/x/crypto/ssh/terminal"
+	"os"
+	"strings"
+)
+
+//scaffold generates starter project into the current directory, missing template or app name are prompted in a terminal
+func scaffold(template, app string) error {
+	interactive := terminal.IsTerminal(int(os.Stdin.Fd()))
+	reader := bufio.NewReader(os.Stdin)
+	if template == "" && interactive {
+		template = prompt(reader, fmt.Sprintf("Project template [%v]", strings.Join(web.ProjectTemplates(), "|")), web.ProjectRESTApp)
+	}
+	if template == "" {
+		template = web.ProjectRESTApp
+	}
+	if app == "" && interactive {
+		app = prompt(reader, "Application name", "myapp")
+	}
+	directory, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	baseURL := fmt.Sprintf("mem://%v", endly.Namespace)
+	service := web.NewService(toolbox.URLPathJoin(baseURL, "template"), toolbox.URLPathJoin(baseURL, "asset"))
+	files, err := service.Scaffold(template, app, directory)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("generated %v project: %v files\n", template, len(files))
+	fmt.Printf("update credentials in e2e/secret, copy them to ~/.secret or generate with endly -c=<name>, then run:\n\tcd e2e && endly\n")
+	return nil
+}
+
+func prompt(reader *bufio.Reader, label, defaultValue string) string {
+	fmt.Printf("%v (%v): ", label, defaultValue)
+	value, _ := reader.ReadString('\n')
+	if value = strings.TrimSpace(value); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,285 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/util"
+	"github.com/viant/toolbox"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+)
+
+//EndlyReleaseURL env key name to customize release channel URL
+const EndlyReleaseURL = "ENDLY_RELEASE_URL"
+
+//EndlyUnsignedUpdate env key name to allow update with endly built without release public key, checksum signature is then not verified
+const EndlyUnsignedUpdate = "ENDLY_UNSIGNED_UPDATE"
+
+//PinnedVersionFile represents per project endly version pinning file
+const PinnedVersionFile = ".endly-version"
+
+//ReleaseURL represents default release channel URL
+var ReleaseURL = "https://api.github.com/repos/viant/endly/releases"
+
+//checksumAssets represents published checksum asset names
+var checksumAssets = []string{"checksums.txt", "SHA256SUMS"}
+
+//ReleasePublicKey represents base64 encoded ed25519 public key verifying checksum asset detached signature (<asset>.sig),
+//it is embedded at build time with -ldflags "-X github.com/viant/endly/bootstrap.ReleasePublicKey=..."
+var ReleasePublicKey = ""
+
+//ReleaseAsset represents a release asset
+type ReleaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+//Release represents release channel release
+type Release struct {
+	TagName string          `json:"tag_name"`
+	Assets  []*ReleaseAsset `json:"assets"`
+}
+
+//Version returns release version
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+//Asset returns asset for supplied name or nil
+func (r *Release) Asset(name string) *ReleaseAsset {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset
+		}
+	}
+	return nil
+}
+
+func releaseURL() string {
+	if URL := os.Getenv(EndlyReleaseURL); URL != "" {
+		return URL
+	}
+	return ReleaseURL
+}
+
+func download(URL string) ([]byte, error) {
+	response, err := http.Get(URL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %v, status: %v", URL, response.Status)
+	}
+	return ioutil.ReadAll(response.Body)
+}
+
+//FetchRelease fetches release for supplied version, empty or latest version returns the latest release
+func FetchRelease(version string) (*Release, error) {
+	URL := releaseURL() + "/latest"
+	if version != "" && version != "latest" {
+		URL = fmt.Sprintf("%v/tags/v%v", releaseURL(), strings.TrimPrefix(version, "v"))
+	}
+	content, err := download(URL)
+	if err != nil {
+		return nil, err
+	}
+	var release = &Release{}
+	if err = json.Unmarshal(content, release); err != nil {
+		return nil, fmt.Errorf("failed to decode release %v, %v", URL, err)
+	}
+	return release, nil
+}
+
+//PinnedVersion returns version pinned with .endly-version file in current or any parent directory
+func PinnedVersion() string {
+	directory, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		if content, err := ioutil.ReadFile(path.Join(directory, PinnedVersionFile)); err == nil {
+			return strings.TrimSpace(string(content))
+		}
+		parent, _ := path.Split(strings.TrimSuffix(directory, "/"))
+		if parent == "" || parent == directory {
+			return ""
+		}
+		directory = parent
+	}
+}
+
+//verifySignature verifies checksum asset ed25519 detached signature published as <asset>.sig with embedded release public key,
+//without public key it fails unless unsigned update is explicitly allowed with ENDLY_UNSIGNED_UPDATE env
+func verifySignature(release *Release, asset *ReleaseAsset, content []byte) error {
+	if ReleasePublicKey == "" {
+		if !toolbox.AsBoolean(os.Getenv(EndlyUnsignedUpdate)) {
+			return fmt.Errorf("unable to verify %v signature, %v was built without release public key, set %v=true to update without signature verification", asset.Name, endly.AppName, EndlyUnsignedUpdate)
+		}
+		fmt.Fprintf(os.Stderr, "WARNING: %v signature was not verified, %v was built without release public key\n", asset.Name, endly.AppName)
+		return nil
+	}
+	publicKey, err := base64.StdEncoding.DecodeString(ReleasePublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release public key: %v", ReleasePublicKey)
+	}
+	signatureAsset := release.Asset(asset.Name + ".sig")
+	if signatureAsset == nil {
+		return fmt.Errorf("signature for %v was not published with release %v", asset.Name, release.TagName)
+	}
+	signature, err := download(signatureAsset.URL)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(publicKey), content, signature) {
+		return fmt.Errorf("invalid %v signature", asset.Name)
+	}
+	return nil
+}
+
+//downloadChecksums downloads and verifies signed checksum asset
+func downloadChecksums(release *Release, asset *ReleaseAsset) ([]byte, error) {
+	data, err := download(asset.URL)
+	if err != nil {
+		return nil, err
+	}
+	return data, verifySignature(release, asset, data)
+}
+
+//verifyChecksum verifies binary archive SHA256 checksum with a signed checksum asset published with the release
+func verifyChecksum(release *Release, name string, content []byte) error {
+	var checksums = make(map[string]string)
+	if asset := release.Asset(name + ".sha256"); asset != nil {
+		data, err := downloadChecksums(release, asset)
+		if err != nil {
+			return err
+		}
+		checksums[name] = strings.Fields(string(data) + " ")[0]
+	}
+	for _, candidate := range checksumAssets {
+		if len(checksums) > 0 {
+			break
+		}
+		asset := release.Asset(candidate)
+		if asset == nil {
+			continue
+		}
+		data, err := downloadChecksums(release, asset)
+		if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			if fields := strings.Fields(scanner.Text()); len(fields) == 2 {
+				checksums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+			}
+		}
+	}
+	expected, ok := checksums[name]
+	if !ok {
+		return fmt.Errorf("checksum for %v was not published with release %v", name, release.TagName)
+	}
+	digest := sha256.Sum256(content)
+	if actual := hex.EncodeToString(digest[:]); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch for %v, expected: %v, but had: %v", name, expected, actual)
+	}
+	return nil
+}
+
+//extractBinary returns endly binary from release tar.gz archive
+func extractBinary(archive []byte) ([]byte, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	reader := tar.NewReader(gzipReader)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("endly binary was not found in archive")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, name := path.Split(header.Name); name == "endly" || name == "endly.exe" {
+			return ioutil.ReadAll(reader)
+		}
+	}
+}
+
+//replaceExecutable atomically replaces running executable
+func replaceExecutable(binary []byte) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	updated := executable + ".new"
+	if err = ioutil.WriteFile(updated, binary, 0755); err != nil {
+		return err
+	}
+	return os.Rename(updated, executable)
+}
+
+//Update updates endly binary to supplied version, if version is empty pinned or the latest one is used
+func Update(version string) error {
+	if version == "" || version == "latest" {
+		if pinned := PinnedVersion(); pinned != "" {
+			version = pinned
+		}
+	}
+	release, err := FetchRelease(version)
+	if err != nil {
+		return err
+	}
+	current := endly.GetVersion()
+	if util.CompareVersion(current, release.Version()) == 0 {
+		fmt.Fprintf(os.Stdout, "%v %v is up to date\n", endly.AppName, current)
+		return nil
+	}
+	name := fmt.Sprintf("endly_%v_%v_%v.tar.gz", runtime.GOOS, release.Version(), runtime.GOARCH)
+	asset := release.Asset(name)
+	if asset == nil {
+		return fmt.Errorf("release %v has no %v asset", release.TagName, name)
+	}
+	archive, err := download(asset.URL)
+	if err != nil {
+		return err
+	}
+	if err = verifyChecksum(release, name, archive); err != nil {
+		return err
+	}
+	binary, err := extractBinary(archive)
+	if err != nil {
+		return err
+	}
+	if err = replaceExecutable(binary); err != nil {
+		return fmt.Errorf("failed to replace endly executable, %v", err)
+	}
+	fmt.Fprintf(os.Stdout, "%v updated: %v -> %v\n", endly.AppName, current, release.Version())
+	return nil
+}
+
+//warnIfNotPinnedVersion warns when running endly version differs from project pinned version
+func warnIfNotPinnedVersion() {
+	pinned := PinnedVersion()
+	if pinned == "" {
+		return
+	}
+	if current := endly.GetVersion(); util.CompareVersion(current, pinned) != 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: project pinned %v version %v (%v), but running %v, run 'endly update' to install pinned version\n", endly.AppName, pinned, PinnedVersionFile, current)
+	}
+}
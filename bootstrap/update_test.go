@@ -0,0 +1,141 @@
+package bootstrap
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	const name = "endly_linux_0.66.0_amd64.tar.gz"
+	archive := []byte("endly archive")
+	digest := sha256.Sum256(archive)
+	checksums := []byte(fmt.Sprintf("%v  %v\n", hex.EncodeToString(digest[:]), name))
+	tampered := []byte(fmt.Sprintf("%v  %v\n", strings.Repeat("0", 64), name))
+	encodedKey := base64.StdEncoding.EncodeToString(publicKey)
+
+	var mux = &sync.Mutex{}
+	var assets map[string][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mux.Lock()
+		defer mux.Unlock()
+		content, ok := assets[strings.TrimPrefix(request.URL.Path, "/")]
+		if !ok {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = writer.Write(content)
+	}))
+	defer server.Close()
+
+	var useCases = []struct {
+		description string
+		publicKey   string
+		unsigned    string
+		assets      map[string][]byte
+		content     []byte
+		expectError string
+	}{
+		{
+			description: "valid signed checksums",
+			publicKey:   encodedKey,
+			assets:      map[string][]byte{"checksums.txt": checksums, "checksums.txt.sig": ed25519.Sign(privateKey, checksums)},
+			content:     archive,
+		},
+		{
+			description: "valid signed archive checksum",
+			publicKey:   encodedKey,
+			assets:      map[string][]byte{name + ".sha256": checksums, name + ".sha256.sig": ed25519.Sign(privateKey, checksums)},
+			content:     archive,
+		},
+		{
+			description: "tampered checksums",
+			publicKey:   encodedKey,
+			assets:      map[string][]byte{"checksums.txt": tampered, "checksums.txt.sig": ed25519.Sign(privateKey, checksums)},
+			content:     archive,
+			expectError: "invalid checksums.txt signature",
+		},
+		{
+			description: "tampered archive",
+			publicKey:   encodedKey,
+			assets:      map[string][]byte{"checksums.txt": checksums, "checksums.txt.sig": ed25519.Sign(privateKey, checksums)},
+			content:     []byte("tampered archive"),
+			expectError: "checksum mismatch",
+		},
+		{
+			description: "signature with other key",
+			publicKey:   encodedKey,
+			assets:      map[string][]byte{"checksums.txt": checksums, "checksums.txt.sig": ed25519.Sign(otherKey, checksums)},
+			content:     archive,
+			expectError: "invalid checksums.txt signature",
+		},
+		{
+			description: "missing signature",
+			publicKey:   encodedKey,
+			assets:      map[string][]byte{"checksums.txt": checksums},
+			content:     archive,
+			expectError: "signature for checksums.txt was not published",
+		},
+		{
+			description: "missing checksum",
+			publicKey:   encodedKey,
+			assets:      map[string][]byte{},
+			content:     archive,
+			expectError: "checksum for " + name + " was not published",
+		},
+		{
+			description: "invalid public key",
+			publicKey:   "invalid",
+			assets:      map[string][]byte{"checksums.txt": checksums, "checksums.txt.sig": ed25519.Sign(privateKey, checksums)},
+			content:     archive,
+			expectError: "invalid release public key",
+		},
+		{
+			description: "missing public key",
+			assets:      map[string][]byte{"checksums.txt": checksums, "checksums.txt.sig": ed25519.Sign(privateKey, checksums)},
+			content:     archive,
+			expectError: "built without release public key",
+		},
+		{
+			description: "missing public key with unsigned update",
+			unsigned:    "true",
+			assets:      map[string][]byte{"checksums.txt": checksums},
+			content:     archive,
+		},
+	}
+
+	defer func(publicKey string) { ReleasePublicKey = publicKey }(ReleasePublicKey)
+	for _, useCase := range useCases {
+		t.Setenv(EndlyUnsignedUpdate, useCase.unsigned)
+		ReleasePublicKey = useCase.publicKey
+		mux.Lock()
+		assets = useCase.assets
+		mux.Unlock()
+		release := &Release{TagName: "v0.66.0"}
+		for assetName := range useCase.assets {
+			release.Assets = append(release.Assets, &ReleaseAsset{Name: assetName, URL: server.URL + "/" + assetName})
+		}
+		err := verifyChecksum(release, name, useCase.content)
+		if useCase.expectError == "" {
+			assert.Nil(t, err, useCase.description)
+			continue
+		}
+		if assert.NotNil(t, err, useCase.description) {
+			assert.Contains(t, err.Error(), useCase.expectError, useCase.description)
+		}
+	}
+}
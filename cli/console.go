@@ -0,0 +1,400 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/cli/xunit"
+	"github.com/viant/endly/system/exec"
+	"github.com/viant/endly/testing/runner/selenium"
+	"github.com/viant/toolbox"
+	"golang.org/x/crypto/ssh/terminal"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	consolePrompt  = "endly> "
+	consoleHeredoc = "<<"
+)
+
+var consoleCommands = []string{"actions", "exit", "help", "reset", "save", "services", "set", "state", "workflow"}
+
+var stepNameExpr = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+//Console represents interactive command line console, it runs ad-hoc service actions and records them as workflow pipeline
+type Console struct {
+	*Runner
+	reader      io.Reader
+	writer      io.Writer
+	defaultKeys map[string]bool
+	init        yaml.MapSlice
+	pipeline    yaml.MapSlice
+}
+
+//Start reads and executes console commands until exit command or end of input
+func (c *Console) Start() error {
+	defer c.context.Close()
+	readLine := c.lineReader()
+	c.Println(c.ColorText("endly console, type help for available commands, tab completes services and actions", c.InputColor))
+	for {
+		line, err := readLine(consolePrompt)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if line == "exit" || line == "quit" {
+			return nil
+		}
+		if strings.HasSuffix(line, consoleHeredoc) { //multiline YAML request ends with an empty line
+			var payload = make([]string, 0)
+			for {
+				next, err := readLine("... ")
+				if err != nil || strings.TrimSpace(next) == "" {
+					break
+				}
+				payload = append(payload, next)
+			}
+			line = strings.TrimSpace(strings.TrimSuffix(line, consoleHeredoc)) + " " + strings.Join(payload, "\n")
+		}
+		if err = c.Execute(line); err != nil {
+			c.printError(err.Error())
+		}
+	}
+}
+
+//lineReader returns line reader, terminal input supports line editing, history and tab completion
+func (c *Console) lineReader() func(prompt string) (string, error) {
+	if file, ok := c.reader.(*os.File); ok && terminal.IsTerminal(int(file.Fd())) {
+		term := terminal.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{file, c.writer}, consolePrompt)
+		term.AutoCompleteCallback = c.autoComplete
+		return func(prompt string) (string, error) {
+			state, err := terminal.MakeRaw(int(file.Fd()))
+			if err != nil {
+				return "", err
+			}
+			defer terminal.Restore(int(file.Fd()), state)
+			term.SetPrompt(prompt)
+			return term.ReadLine()
+		}
+	}
+	scanner := bufio.NewScanner(c.reader)
+	return func(prompt string) (string, error) {
+		c.Print(prompt)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return scanner.Text(), nil
+	}
+}
+
+func (c *Console) autoComplete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' || pos != len(line) {
+		return "", 0, false
+	}
+	candidates := c.Complete(line)
+	if len(candidates) == 0 {
+		return "", 0, false
+	}
+	completed := candidates[0]
+	for _, candidate := range candidates[1:] {
+		for !strings.HasPrefix(candidate, completed) {
+			completed = completed[:len(completed)-1]
+		}
+	}
+	if len(candidates) == 1 && !strings.HasSuffix(completed, ":") {
+		completed += " "
+	}
+	if len(completed) <= len(line) {
+		return "", 0, false
+	}
+	return completed, len(completed), true
+}
+
+//Complete returns sorted line completion candidates for console commands, registered services and service actions
+func (c *Console) Complete(line string) []string {
+	var candidates = make([]string, 0)
+	if strings.HasPrefix(line, "actions ") {
+		for _, serviceID := range c.serviceIDs() {
+			candidates = append(candidates, "actions "+serviceID)
+		}
+	} else if strings.Contains(line, " ") {
+		return candidates
+	} else if index := strings.Index(line, ":"); index != -1 {
+		if service, err := c.context.Service(line[:index]); err == nil {
+			for _, action := range service.Actions() {
+				candidates = append(candidates, line[:index]+":"+action)
+			}
+		}
+	} else {
+		candidates = append(candidates, consoleCommands...)
+		for _, serviceID := range c.serviceIDs() {
+			candidates = append(candidates, serviceID+":")
+		}
+	}
+	var result = make([]string, 0)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, line) {
+			result = append(result, candidate)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (c *Console) serviceIDs() []string {
+	var result = make([]string, 0)
+	for serviceID := range endly.Services(c.manager) {
+		result = append(result, serviceID)
+	}
+	sort.Strings(result)
+	return result
+}
+
+//Execute executes console command or service:action with optional JSON or YAML request
+func (c *Console) Execute(line string) error {
+	command, argument := line, ""
+	if index := strings.Index(line, " "); index != -1 {
+		command, argument = line[:index], strings.TrimSpace(line[index+1:])
+	}
+	switch command {
+	case "":
+		return nil
+	case "help":
+		c.printHelp()
+	case "services":
+		c.Println(strings.Join(c.serviceIDs(), "\n"))
+	case "actions":
+		return c.printActions(argument)
+	case "state":
+		return c.printState(argument)
+	case "set":
+		return c.setVariable(argument)
+	case "workflow":
+		workflow, err := c.Workflow()
+		if err != nil {
+			return err
+		}
+		c.Print(workflow)
+	case "save":
+		return c.save(argument)
+	case "reset":
+		c.init = yaml.MapSlice{}
+		c.pipeline = yaml.MapSlice{}
+	default:
+		if !strings.Contains(command, ":") {
+			return fmt.Errorf("unknown command: %v, type help for available commands", command)
+		}
+		return c.runAction(command, argument)
+	}
+	return nil
+}
+
+func (c *Console) printHelp() {
+	for _, usage := range [][]string{
+		{"service:action [request]", "run service action, request is JSON or YAML, i.e. workflow:print {message: hello}"},
+		{"service:action <<", "run service action with multiline YAML request terminated by an empty line"},
+		{"services", "list registered services"},
+		{"actions <service>", "list service actions"},
+		{"state [key]", "print state variables set in console or state key value"},
+		{"set <key> <value>", "set state variable, value is JSON or YAML"},
+		{"workflow", "print workflow built from successfully executed actions"},
+		{"save <file>", "save workflow to YAML file"},
+		{"reset", "discard recorded workflow"},
+		{"exit", "exit console"},
+	} {
+		c.Println(c.ColorText(fmt.Sprintf("%-26v", usage[0]), c.InputColor) + " " + usage[1])
+	}
+}
+
+func (c *Console) printActions(serviceID string) error {
+	service, err := c.context.Service(serviceID)
+	if err != nil {
+		return err
+	}
+	for _, action := range service.Actions() {
+		route, _ := service.Route(action)
+		description := ""
+		if route != nil && route.RequestInfo != nil {
+			description = route.RequestInfo.Description
+		}
+		c.Println(fmt.Sprintf("%v - %v", c.ColorText(serviceID+":"+action, c.InputColor), description))
+	}
+	return nil
+}
+
+func (c *Console) printState(key string) error {
+	state := c.context.SafeState().Clone()
+	if key != "" {
+		value, ok := state.GetValue(key)
+		if !ok {
+			return fmt.Errorf("%v was not found in state", key)
+		}
+		return c.printValue(value)
+	}
+	var result = make(map[string]interface{})
+	for k, v := range state {
+		if c.defaultKeys[k] || toolbox.IsFunc(v) {
+			continue
+		}
+		result[k] = v
+	}
+	return c.printValue(result)
+}
+
+func (c *Console) printValue(value interface{}) error {
+	if !toolbox.IsMap(value) && !toolbox.IsSlice(value) && !toolbox.IsStruct(value) {
+		c.Println(c.ColorText(toolbox.AsString(value), c.OutputColor))
+		return nil
+	}
+	text, err := toolbox.AsYamlText(value)
+	if err != nil || text == "{}\n" {
+		return err
+	}
+	c.Print(c.ColorText(text, c.OutputColor))
+	return nil
+}
+
+func (c *Console) setVariable(argument string) error {
+	pair := strings.SplitN(argument, " ", 2)
+	if len(pair) != 2 || pair[0] == "" {
+		return fmt.Errorf("invalid set command, expected: set <key> <value>")
+	}
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(pair[1]), &value); err != nil {
+		return fmt.Errorf("invalid %v value: %v", pair[0], err)
+	}
+	normalized, err := toolbox.NormalizeKVPairs(value)
+	if err != nil {
+		return err
+	}
+	c.context.SafeState().SetValue(pair[0], normalized)
+	c.init = append(c.init, yaml.MapItem{Key: pair[0], Value: value})
+	return nil
+}
+
+func (c *Console) runAction(selector, payload string) error {
+	pair := strings.SplitN(selector, ":", 2)
+	var request = make(map[string]interface{})
+	var recorded = yaml.MapSlice{}
+	if payload != "" {
+		if err := yaml.Unmarshal([]byte(payload), &recorded); err != nil {
+			return fmt.Errorf("invalid %v request, expected JSON or YAML object: %v", selector, err)
+		}
+		normalized, err := toolbox.NormalizeKVPairs(recorded)
+		if err != nil {
+			return err
+		}
+		request = toolbox.AsMap(normalized)
+	}
+	service, err := c.context.Service(pair[0])
+	if err != nil {
+		return err
+	}
+	serviceRequest, err := c.context.AsRequest(pair[0], pair[1], request)
+	if err != nil {
+		return err
+	}
+	response := service.Run(c.context, serviceRequest)
+	if response.Err != nil {
+		return response.Err
+	}
+	if response.Response != nil {
+		if err = c.printValue(response.Response); err != nil {
+			return err
+		}
+	}
+	c.addStep(selector, recorded)
+	return nil
+}
+
+//addStep records executed action as pipeline step, step name is derived from the action selector
+func (c *Console) addStep(selector string, request yaml.MapSlice) {
+	name := strings.Trim(stepNameExpr.ReplaceAllString(selector, "_"), "_")
+	var names = make(map[string]bool)
+	for _, step := range c.pipeline {
+		names[toolbox.AsString(step.Key)] = true
+	}
+	for i := 2; names[name]; i++ {
+		name = fmt.Sprintf("%v%v", strings.TrimRight(name, "0123456789"), i)
+	}
+	var step = yaml.MapSlice{{Key: "action", Value: selector}}
+	step = append(step, request...)
+	c.pipeline = append(c.pipeline, yaml.MapItem{Key: name, Value: step})
+}
+
+//Workflow returns YAML inline workflow with variables and actions recorded in this console session
+func (c *Console) Workflow() (string, error) {
+	var workflow = yaml.MapSlice{}
+	if len(c.init) > 0 {
+		workflow = append(workflow, yaml.MapItem{Key: "init", Value: c.init})
+	}
+	workflow = append(workflow, yaml.MapItem{Key: "pipeline", Value: c.pipeline})
+	content, err := yaml.Marshal(workflow)
+	return string(content), err
+}
+
+func (c *Console) save(filename string) error {
+	if filename == "" {
+		return fmt.Errorf("invalid save command, expected: save <file>")
+	}
+	if !strings.HasSuffix(filename, ".yaml") && !strings.HasSuffix(filename, ".yml") {
+		filename += ".yaml"
+	}
+	workflow, err := c.Workflow()
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(filename, []byte(workflow), 0644); err != nil {
+		return err
+	}
+	c.Println(c.ColorText("saved "+filename, c.OutputColor))
+	return nil
+}
+
+//NewConsole creates a new interactive console reading commands from reader
+func NewConsole(reader io.Reader, writer io.Writer) *Console {
+	runner := &Runner{
+		manager:      endly.New(),
+		Events:       NewEventTags(),
+		Renderer:     NewRenderer(writer, 120),
+		group:        &MessageGroup{},
+		xUnitSummary: xunit.NewTestsuite(),
+		Style:        NewStyle(),
+		report:       &ReportSummaryEvent{},
+		filter:       DefaultFilter(),
+	}
+	runner.context = runner.manager.NewContext(toolbox.NewContext())
+	runner.context.CLIEnabled = true
+	runner.SetMask(runner.context.MaskSecrets)
+	exec.TerminalSessions(runner.context)
+	exec.SetDefaultTarget(runner.context, nil)
+	selenium.Sessions(runner.context)
+	runner.context.SetListener(runner.AsListener())
+	var defaultKeys = make(map[string]bool)
+	for k := range runner.context.SafeState().Clone() {
+		defaultKeys[k] = true
+	}
+	return &Console{
+		Runner:      runner,
+		reader:      reader,
+		writer:      writer,
+		defaultKeys: defaultKeys,
+		init:        yaml.MapSlice{},
+		pipeline:    yaml.MapSlice{},
+	}
+}
@@ -0,0 +1,89 @@
+package cli_test
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/cli"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestConsole_Complete(t *testing.T) {
+	console := cli.NewConsole(strings.NewReader(""), new(bytes.Buffer))
+	var useCases = []struct {
+		description string
+		line        string
+		expect      []string
+	}{
+		{
+			description: "command and service prefix",
+			line:        "workf",
+			expect:      []string{"workflow", "workflow:"},
+		},
+		{
+			description: "service action",
+			line:        "workflow:pri",
+			expect:      []string{"workflow:print"},
+		},
+		{
+			description: "actions service argument",
+			line:        "actions workfl",
+			expect:      []string{"actions workflow"},
+		},
+		{
+			description: "request payload",
+			line:        "workflow:print {",
+			expect:      []string{},
+		},
+	}
+	for _, useCase := range useCases {
+		assert.EqualValues(t, useCase.expect, console.Complete(useCase.line), useCase.description)
+	}
+}
+
+func TestConsole_Start(t *testing.T) {
+	directory, err := ioutil.TempDir("", "endly_console")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(directory)
+	workflowFile := path.Join(directory, "adhoc.yaml")
+	input := strings.Join([]string{
+		"set app {name: demo}",
+		"workflow:print {message: 'deploying $app.name'}",
+		"workflow:print <<",
+		"message: done",
+		"",
+		"workflow:unknown {}",
+		"foo",
+		"state app.name",
+		"save " + workflowFile,
+		"exit",
+		"workflow:print {message: skipped}",
+	}, "\n")
+	output := new(bytes.Buffer)
+	console := cli.NewConsole(strings.NewReader(input), output)
+	assert.Nil(t, console.Start())
+	assert.Contains(t, output.String(), "deploying demo")
+	assert.Contains(t, output.String(), "unknown command: foo")
+	assert.NotContains(t, output.String(), "skipped")
+
+	content, err := ioutil.ReadFile(workflowFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, `init:
+  app:
+    name: demo
+pipeline:
+  workflow_print:
+    action: workflow:print
+    message: deploying $app.name
+  workflow_print2:
+    action: workflow:print
+    message: done
+`, string(content))
+}
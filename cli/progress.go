@@ -0,0 +1,399 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/lunixbochs/vtclean"
+	"github.com/viant/endly/model"
+	"golang.org/x/crypto/ssh/terminal"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//FormatTree represents hierarchical workflow, task and action output, live with spinners on terminal
+	FormatTree = "tree"
+	//FormatPlain represents machine-parsable key=value output lines
+	FormatPlain = "plain"
+	//FormatJSON represents newline delimited JSON output records
+	FormatJSON = "json"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+//progress represents workflow progress output, it wraps renderer writer to interleave other runner output
+type progress interface {
+	io.Writer
+	//start reports activity start
+	start(activity *model.Activity)
+	//end reports activity end
+	end(activity *model.Activity)
+	//close flushes pending output
+	close()
+}
+
+//IsTerminal returns true if writer is a terminal
+func IsTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	return ok && terminal.IsTerminal(int(file.Fd()))
+}
+
+//newProgress returns progress output for supplied format, empty format defaults to tree on terminal, plain otherwise
+func newProgress(format string, writer io.Writer, renderer *Renderer) (progress, error) {
+	tty := IsTerminal(writer)
+	if format == "" {
+		format = FormatPlain
+		if tty {
+			format = FormatTree
+		}
+	}
+	switch format {
+	case FormatTree:
+		renderer.noColor = !tty
+		return newTreeProgress(writer, renderer, tty), nil
+	case FormatPlain, FormatJSON:
+		renderer.noColor = true
+		return &lineProgress{writer: writer, json: format == FormatJSON, mux: &sync.Mutex{}}, nil
+	}
+	return nil, fmt.Errorf("unsupported output format: %v, supported: %v, %v, %v", format, FormatTree, FormatPlain, FormatJSON)
+}
+
+func activityLabel(activity *model.Activity) string {
+	var label = activity.Service + "." + activity.Action
+	if activity.TagIndex != "" {
+		label = activity.FormatTag() + " " + label
+	}
+	description := activity.Description
+	if description == "" {
+		description = activity.Comments
+	}
+	if description != "" {
+		label += " " + description
+	}
+	return label
+}
+
+func formatDuration(duration time.Duration) string {
+	if duration < time.Second {
+		return fmt.Sprintf("%vms", int(duration/time.Millisecond))
+	}
+	return fmt.Sprintf("%.1fs", duration.Seconds())
+}
+
+//treeNode represents running activity
+type treeNode struct {
+	activity  *model.Activity
+	level     int
+	committed bool
+}
+
+//treeProgress renders workflow → task → action tree, on terminal running action is rendered as live line with spinner
+type treeProgress struct {
+	writer   io.Writer
+	renderer *Renderer
+	tty      bool
+	mux      *sync.Mutex
+	stack    []*treeNode
+	callers  map[int]string
+	tasks    map[int]string
+	live     *treeNode
+	drawn    bool
+	pending  bool
+	midLine  bool
+	frame    int
+	done     chan bool
+}
+
+func (p *treeProgress) indent(level int) string {
+	return strings.Repeat("  ", level)
+}
+
+func (p *treeProgress) clearLive() {
+	if p.drawn {
+		_, _ = io.WriteString(p.writer, "\r\033[K")
+		p.drawn = false
+	}
+}
+
+func (p *treeProgress) drawLive() {
+	if p.live == nil || p.pending {
+		return
+	}
+	activity := p.live.activity
+	spinner := p.renderer.ColorText(spinnerFrames[p.frame%len(spinnerFrames)], "cyan")
+	elapsed := p.renderer.ColorText(formatDuration(time.Since(activity.StartTime)), "gray")
+	_, _ = io.WriteString(p.writer, "\r\033[K"+p.indent(p.live.level+2)+spinner+" "+activityLabel(activity)+" "+elapsed)
+	p.drawn = true
+}
+
+func (p *treeProgress) println(text string) {
+	p.clearLive()
+	if p.midLine {
+		text = "\n" + text
+		p.midLine = false
+	}
+	_, _ = io.WriteString(p.writer, text+"\n")
+}
+
+//Write writes other runner output above live line, output is indented under the running action
+func (p *treeProgress) Write(data []byte) (int, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.clearLive()
+	var indent = ""
+	if count := len(p.stack); count > 0 {
+		indent = p.indent(p.stack[count-1].level + 4)
+	}
+	var text = new(strings.Builder)
+	var escaped = false
+	for _, r := range string(data) {
+		switch {
+		case r == '\x1b':
+			escaped = true
+		case escaped:
+			escaped = !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z')
+		case r == '\n' || r == '\r':
+			p.midLine = false
+		case !p.midLine:
+			text.WriteString(indent)
+			p.midLine = true
+		}
+		text.WriteRune(r)
+	}
+	if _, err := io.WriteString(p.writer, text.String()); err != nil {
+		return 0, err
+	}
+	p.pending = len(data) > 0 && data[len(data)-1] != '\n'
+	p.drawLive()
+	return len(data), nil
+}
+
+func (p *treeProgress) start(activity *model.Activity) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	level := 0
+	if count := len(p.stack); count > 0 {
+		parent := p.stack[count-1]
+		level = parent.level + 3
+		if !parent.committed {
+			parent.committed = true
+			p.println(p.indent(parent.level+2) + p.renderer.ColorText("▸ ", "cyan") + activityLabel(parent.activity))
+		}
+	}
+	if caller, ok := p.callers[level]; !ok || caller != activity.Caller {
+		p.callers[level] = activity.Caller
+		delete(p.tasks, level)
+		if activity.Caller != "" {
+			p.println(p.indent(level) + p.renderer.ColorText(activity.Caller, "bold"))
+		}
+	}
+	if task, ok := p.tasks[level]; !ok || task != activity.Task {
+		p.tasks[level] = activity.Task
+		p.println(p.indent(level+1) + p.renderer.ColorText(activity.Task, "brown"))
+	}
+	node := &treeNode{activity: activity, level: level}
+	p.stack = append(p.stack, node)
+	if p.tty {
+		p.live = node
+		p.clearLive()
+		p.drawLive()
+	}
+}
+
+func (p *treeProgress) end(activity *model.Activity) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	var node *treeNode
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if p.stack[i].activity == activity {
+			node = p.stack[i]
+			p.stack = p.stack[:i]
+			break
+		}
+	}
+	if node == nil {
+		return
+	}
+	for level := range p.callers {
+		if level > node.level {
+			delete(p.callers, level)
+			delete(p.tasks, level)
+		}
+	}
+	status, color := "✔", "green"
+	if activity.Error != "" {
+		status, color = "✘", "red"
+	}
+	elapsed := p.renderer.ColorText(formatDuration(time.Since(activity.StartTime)), "gray")
+	p.live = nil
+	p.println(p.indent(node.level+2) + p.renderer.ColorText(status, color) + " " + activityLabel(activity) + " " + elapsed)
+	if activity.Error != "" && !node.committed { //parent error wraps already reported child error
+		p.println(p.indent(node.level+4) + p.renderer.ColorText(activity.Error, "red"))
+	}
+}
+
+func (p *treeProgress) spin() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.mux.Lock()
+			p.frame++
+			if p.drawn {
+				p.drawLive()
+			}
+			p.mux.Unlock()
+		}
+	}
+}
+
+func (p *treeProgress) close() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.clearLive()
+	p.live = nil
+	if p.tty {
+		close(p.done)
+	}
+}
+
+func newTreeProgress(writer io.Writer, renderer *Renderer, tty bool) *treeProgress {
+	result := &treeProgress{
+		writer:   writer,
+		renderer: renderer,
+		tty:      tty,
+		mux:      &sync.Mutex{},
+		callers:  make(map[int]string),
+		tasks:    make(map[int]string),
+		done:     make(chan bool),
+	}
+	if tty {
+		go result.spin()
+	}
+	return result
+}
+
+//ProgressRecord represents plain or JSON progress output record
+type ProgressRecord struct {
+	Time       time.Time `json:"time"`
+	Event      string    `json:"event"`
+	Workflow   string    `json:"workflow,omitempty"`
+	Task       string    `json:"task,omitempty"`
+	Action     string    `json:"action,omitempty"`
+	TagID      string    `json:"tagID,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	DurationMs int       `json:"durationMs,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Text       string    `json:"text,omitempty"`
+}
+
+//lineProgress writes one plain key=value or JSON record per activity start, end and other runner output line
+type lineProgress struct {
+	writer  io.Writer
+	json    bool
+	mux     *sync.Mutex
+	partial string
+}
+
+func (p *lineProgress) emit(record *ProgressRecord) {
+	if p.json {
+		encoded, _ := json.Marshal(record)
+		_, _ = p.writer.Write(append(encoded, '\n'))
+		return
+	}
+	var fields = []string{"time=" + record.Time.Format(time.RFC3339Nano), "event=" + record.Event}
+	var durationMs = ""
+	if record.Event == "end" {
+		durationMs = strconv.Itoa(record.DurationMs)
+	}
+	for _, pair := range [][]string{
+		{"workflow", record.Workflow},
+		{"task", record.Task},
+		{"action", record.Action},
+		{"tagID", record.TagID},
+		{"status", record.Status},
+		{"durationMs", durationMs},
+		{"error", record.Error},
+		{"text", record.Text},
+	} {
+		if pair[1] == "" {
+			continue
+		}
+		value := pair[1]
+		if strings.ContainsAny(value, " \t\"=") {
+			value = strconv.Quote(value)
+		}
+		fields = append(fields, pair[0]+"="+value)
+	}
+	_, _ = io.WriteString(p.writer, strings.Join(fields, " ")+"\n")
+}
+
+func (p *lineProgress) activityRecord(event string, activity *model.Activity) *ProgressRecord {
+	return &ProgressRecord{
+		Time:     time.Now(),
+		Event:    event,
+		Workflow: activity.Caller,
+		Task:     activity.Task,
+		Action:   activity.Service + "." + activity.Action,
+		TagID:    activity.TagID,
+	}
+}
+
+//Write emits each complete output line as output record, colors and terminal control sequences are removed
+func (p *lineProgress) Write(data []byte) (int, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	text := p.partial + string(data)
+	lines := strings.Split(text, "\n")
+	p.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		p.emitOutput(line)
+	}
+	return len(data), nil
+}
+
+func (p *lineProgress) emitOutput(line string) {
+	if index := strings.LastIndex(line, "\r"); index != -1 {
+		line = line[index+1:]
+	}
+	line = strings.TrimSpace(vtclean.Clean(line, false))
+	if line == "" {
+		return
+	}
+	p.emit(&ProgressRecord{Time: time.Now(), Event: "output", Text: line})
+}
+
+func (p *lineProgress) start(activity *model.Activity) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.emit(p.activityRecord("start", activity))
+}
+
+func (p *lineProgress) end(activity *model.Activity) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	record := p.activityRecord("end", activity)
+	record.Status = "passed"
+	if activity.Error != "" {
+		record.Status = "failed"
+		record.Error = activity.Error
+	}
+	record.DurationMs = int(time.Since(activity.StartTime) / time.Millisecond)
+	p.emit(record)
+}
+
+func (p *lineProgress) close() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.partial != "" {
+		p.emitOutput(p.partial)
+		p.partial = ""
+	}
+}
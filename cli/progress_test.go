@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestActivity(caller, task, action, err string) *model.Activity {
+	return &model.Activity{
+		MetaTag:   &model.MetaTag{TagID: caller + "_" + task},
+		Caller:    caller,
+		Task:      task,
+		Service:   "workflow",
+		Action:    action,
+		Error:     err,
+		StartTime: time.Now(),
+	}
+}
+
+func TestNewProgress(t *testing.T) {
+	renderer := NewRenderer(new(bytes.Buffer), 120)
+	_, err := newProgress("xml", new(bytes.Buffer), renderer)
+	assert.NotNil(t, err)
+	output, err := newProgress("", new(bytes.Buffer), renderer)
+	if assert.Nil(t, err) {
+		_, ok := output.(*lineProgress)
+		assert.True(t, ok, "non terminal output defaults to plain")
+		assert.True(t, renderer.noColor)
+	}
+}
+
+func TestTreeProgress(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	renderer := NewRenderer(buffer, 120)
+	output, err := newProgress(FormatTree, buffer, renderer)
+	if !assert.Nil(t, err) {
+		return
+	}
+	renderer.writer = output
+	build := newTestActivity("app", "build", "print", "")
+	output.start(build)
+	renderer.Printf("%v\n", renderer.ColorText("compiled", "green"))
+	output.end(build)
+	run := newTestActivity("app", "test", "run", "")
+	output.start(run)
+	check := newTestActivity("", "check", "fail", "failed check")
+	output.start(check)
+	output.end(check)
+	run.Error = "check: failed check"
+	output.end(run)
+	output.close()
+	expect := []string{
+		"app",
+		"  build",
+		"        compiled",
+		"    ✔ workflow.print 0ms",
+		"  test",
+		"    ▸ workflow.run",
+		"        check",
+		"          ✘ workflow.fail 0ms",
+		"              failed check",
+		"    ✘ workflow.run 0ms",
+		"",
+	}
+	assert.Equal(t, strings.Join(expect, "\n"), buffer.String())
+}
+
+func TestLineProgress(t *testing.T) {
+	for _, format := range []string{FormatPlain, FormatJSON} {
+		buffer := new(bytes.Buffer)
+		output, err := newProgress(format, buffer, NewRenderer(buffer, 120))
+		if !assert.Nil(t, err) {
+			return
+		}
+		activity := newTestActivity("app", "build", "print", "")
+		output.start(activity)
+		_, _ = output.Write([]byte("\x1b[32mhello\x1b[0m\nworld"))
+		activity.Error = "failed"
+		output.end(activity)
+		output.close()
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		if !assert.Equal(t, 4, len(lines), format) {
+			continue
+		}
+		if format == FormatPlain {
+			assert.Contains(t, lines[0], "event=start workflow=app task=build action=workflow.print tagID=app_build")
+			assert.Contains(t, lines[1], "event=output text=hello")
+			assert.Contains(t, lines[2], "event=end workflow=app task=build action=workflow.print tagID=app_build status=failed durationMs=0 error=failed")
+			assert.Contains(t, lines[3], "event=output text=world")
+			continue
+		}
+		var records = make([]*ProgressRecord, 0)
+		for _, line := range lines {
+			record := &ProgressRecord{}
+			assert.Nil(t, json.Unmarshal([]byte(line), record), line)
+			records = append(records, record)
+		}
+		assert.Equal(t, "start", records[0].Event)
+		assert.Equal(t, "hello", records[1].Text)
+		assert.Equal(t, "failed", records[2].Status)
+		assert.Equal(t, "world", records[3].Text)
+	}
+}
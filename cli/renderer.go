@@ -34,6 +34,13 @@ type Renderer struct {
 	minColumns     int
 	lines          int
 	pendingNewLine bool
+	noColor        bool
+	mask           func(text string) string
+}
+
+//SetMask sets function masking secrets in printed text
+func (r *Renderer) SetMask(mask func(text string) string) {
+	r.mask = mask
 }
 
 //Printf formats and print supplied text with arguments
@@ -66,11 +73,17 @@ func (r *Renderer) Println(text string) {
 
 //Print prints supplied message
 func (r *Renderer) Print(message string) {
+	if r.mask != nil {
+		message = r.mask(message)
+	}
 	_, _ = r.writer.Write([]byte(message))
 }
 
 //ColorText returns text with ANCI color
 func (r *Renderer) ColorText(text string, textColors ...string) string {
+	if r.noColor {
+		return text
+	}
 	for _, color := range textColors {
 		if color, has := colors[color]; has {
 			text = aurora.Sprintf("%v", color(text))
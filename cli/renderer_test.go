@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/endly/cli"
+	"strings"
 	"testing"
 )
 
@@ -23,3 +24,14 @@ func Test_Renderer_PrintTable(t *testing.T) {
 	render.PrintTable("table1", []string{"name", "required", "description"}, data, 80)
 	assert.True(t, len(buf.String()) > 0)
 }
+
+func Test_Renderer_Mask(t *testing.T) {
+	var buf = new(bytes.Buffer)
+	render := cli.NewRenderer(buf, 80)
+	render.SetMask(func(text string) string {
+		return strings.Replace(text, "pass123", "***", -1)
+	})
+	render.Printf("password: %v", "pass123")
+	render.Println(" dsn: user:pass123@tcp")
+	assert.Equal(t, "password: *** dsn: user:***@tcp\n", buf.String())
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
@@ -20,8 +21,10 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"os/signal"
 	"path"
 	"strings"
+	"syscall"
 	"time"
 )
 
@@ -48,6 +51,8 @@ type Runner struct {
 	*Style
 	*Renderer
 	*Events
+	Format                string //output format: tree, plain or json, tree on terminal and plain otherwise if empty
+	progress              progress
 	request               *workflow.RunRequest
 	xUnitSummary          *xunit.Testsuite
 	context               *endly.Context
@@ -88,6 +93,9 @@ func (r *Runner) printMessage(contextMessage string, messageType int, message st
 }
 
 func (r *Runner) formatMessage(contextMessage string, messageType int, message string, messageInfoType int, messageInfo string) string {
+	if r.progress != nil {
+		return r.formatCompactMessage(contextMessage, messageType, message, messageInfoType, messageInfo)
+	}
 	var columns = r.Columns() - 5
 	var infoLength = len(messageInfo)
 	var messageLength = columns - len(vtclean.Clean(contextMessage, false)) - infoLength
@@ -114,7 +122,34 @@ func (r *Runner) formatMessage(contextMessage string, messageType int, message s
 	return fmt.Sprintf("[%v %v %v]", contextMessage, message, messageInfo)
 }
 
+//formatCompactMessage formats message without padding, used with progress output where path is rendered by the tree
+func (r *Runner) formatCompactMessage(contextMessage string, messageType int, message string, messageInfoType int, messageInfo string) string {
+	var result = make([]string, 0)
+	if contextMessage = strings.TrimSpace(contextMessage); contextMessage != "" {
+		result = append(result, contextMessage)
+	}
+	if messageInfo = strings.TrimSpace(messageInfo); messageInfo != "" {
+		if strings.TrimSpace(message) != "" {
+			messageInfo += ":"
+		}
+		if messageInfoColor, ok := r.MessageStyleColor[messageInfoType]; ok {
+			messageInfo = r.ColorText(messageInfo, messageInfoColor)
+		}
+		result = append(result, r.ColorText(messageInfo, "bold"))
+	}
+	if message = strings.TrimSpace(message); message != "" {
+		if messageColor, ok := r.MessageStyleColor[messageType]; ok {
+			message = r.ColorText(message, messageColor)
+		}
+		result = append(result, message)
+	}
+	return strings.Join(result, " ")
+}
+
 func (r *Runner) formatShortMessage(messageType int, message string, messageInfoType int, messageInfo string) string {
+	if r.progress != nil {
+		return r.formatCompactMessage("", messageType, message, messageInfoType, messageInfo)
+	}
 	var fullPath = !(messageType == messageTypeTagDescription || messageInfoType == messageTypeAction)
 	var path = "[/]"
 	if r.Len() > 0 {
@@ -278,6 +313,13 @@ func (r *Runner) processActivityStart(event msg.Event) bool {
 	if activity.Logging != nil && !*activity.Logging {
 		return true
 	}
+	if r.progress != nil {
+		if activity.TagIndex != "" && activity.TagDescription != "" {
+			r.EventTag().Description = activity.TagDescription
+		}
+		r.progress.start(activity)
+		return true
+	}
 	if activity.TagIndex != "" {
 		r.repeated.Reset()
 		r.printShortMessage(messageTypeAction, activity.TagID, messageTypeAction, "tag.id")
@@ -297,9 +339,16 @@ func (r *Runner) processActivityStart(event msg.Event) bool {
 }
 
 func (r *Runner) processActivityEnd(event msg.Event) {
-	if _, ended := event.Value().(*model.ActivityEndEvent); ended {
-		r.activityEnded = ended
-		event.SetLoggable(true)
+	endEvent, ended := event.Value().(*model.ActivityEndEvent)
+	if !ended {
+		return
+	}
+	r.activityEnded = ended
+	event.SetLoggable(true)
+	if activity, ok := endEvent.Response.(*model.Activity); ok && r.progress != nil {
+		if activity.Logging == nil || *activity.Logging {
+			r.progress.end(activity)
+		}
 	}
 }
 
@@ -318,6 +367,9 @@ func (r *Runner) processEvent(event msg.Event, filter map[string]bool) {
 	if r.processAssertable(event) {
 		return
 	}
+	if r.processPausedEvent(event) {
+		return
+	}
 	if !event.IsLoggable() {
 		return
 	}
@@ -666,6 +718,7 @@ func (r *Runner) printSummary() {
 func (r *Runner) Run(request *workflow.RunRequest) (err error) {
 	r.request = request
 	r.context = r.manager.NewContext(toolbox.NewContext())
+	r.SetMask(r.context.MaskSecrets)
 	//init shared session
 	exec.TerminalSessions(r.context)
 	exec.SetDefaultTarget(r.context, nil)
@@ -673,15 +726,23 @@ func (r *Runner) Run(request *workflow.RunRequest) (err error) {
 
 	r.report = &ReportSummaryEvent{}
 	r.context.CLIEnabled = true
+	if r.progress, err = newProgress(r.Format, r.Renderer.writer, r.Renderer); err != nil {
+		return err
+	}
+	r.Renderer.writer = r.progress
 	r.filter = request.EventFilter
 	if len(r.filter) == 0 {
 		r.filter = DefaultFilter()
 	}
 	defer func() {
 		r.onCallerEnd()
+		r.progress.close()
 		if r.err != nil {
 			err = r.err
 		}
+		if e := endly.SaveSession(r.context); e != nil {
+			r.context.Publish(msg.NewErrorEvent(e.Error()))
+		}
 		if !request.Interactive {
 			r.context.Close()
 		}
@@ -690,6 +751,13 @@ func (r *Runner) Run(request *workflow.RunRequest) (err error) {
 		}
 	}()
 	r.context.SetListener(r.AsListener())
+	if request.Session != "" {
+		if err = endly.AttachSession(r.context, request.Session); err != nil {
+			return err
+		}
+	}
+	stopOnInterrupt := r.cancelOnInterrupt()
+	defer stopOnInterrupt()
 	request.Async = true
 	var response = &workflow.RunResponse{}
 	err = endly.Run(r.context, request, response)
@@ -702,6 +770,26 @@ func (r *Runner) Run(request *workflow.RunRequest) (err error) {
 	return err
 }
 
+//cancelOnInterrupt cancels running context on the first ctrl-c, second one terminates process
+func (r *Runner) cancelOnInterrupt() func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-signals; !ok {
+			return
+		}
+		r.context.Publish(msg.NewErrorEvent("interrupted, canceling run ..."))
+		r.context.Cancel()
+		if _, ok := <-signals; ok {
+			os.Exit(1)
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
+
 func (r *Runner) processErrorEvent(event msg.Event) bool {
 
 	if _, ok := event.Value().(*msg.ResetError); ok {
@@ -723,6 +811,28 @@ func (r *Runner) processErrorEvent(event msg.Event) bool {
 	return false
 }
 
+//processPausedEvent prints paused action, in debug mode it reads step or continue command from stdin
+func (r *Runner) processPausedEvent(event msg.Event) bool {
+	pausedEvent, ok := event.Value().(*workflow.PausedEvent)
+	if !ok {
+		return false
+	}
+	r.processMessages(pausedEvent)
+	if r.request == nil || !r.request.Debug {
+		return true
+	}
+	r.Printf("%v", r.ColorText("debug [enter: step, c: continue]> ", r.InputColor))
+	command, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	var request interface{} = &workflow.StepRequest{SessionID: pausedEvent.SessionID, Count: 1}
+	if strings.ToLower(strings.TrimSpace(command)) == "c" {
+		request = &workflow.ContinueRequest{SessionID: pausedEvent.SessionID}
+	}
+	if err := endly.Run(r.context, request, nil); err != nil {
+		r.printError(err.Error())
+	}
+	return true
+}
+
 //New creates a new command line runner
 func New() *Runner {
 	return &Runner{
@@ -31,6 +31,7 @@ var deferFunctionsKey = (*[]func())(nil)
 //Context represents a workflow session context/state
 type Context struct {
 	background      context.Context
+	cancel          context.CancelFunc
 	SessionID       string
 	CLIEnabled      bool
 	HasLogger       bool
@@ -42,18 +43,111 @@ type Context struct {
 	state           data.Map
 	Logging         *bool
 	toolbox.Context
-	cloned []*Context
-	closed int32
+	cloned       []*Context
+	closed       int32
+	heartbeat    *Heartbeat
+	secretValues *SecretValues
+	stateMux     *sync.RWMutex
+	cancelMux    sync.Mutex
+	loggingMux   sync.Mutex
+	heartbeatMux sync.Mutex
 }
 
+//Background returns standard context, it is canceled once this context is canceled or closed
 func (c *Context) Background() context.Context {
+	c.cancelMux.Lock()
+	defer c.cancelMux.Unlock()
+	return c.backgroundContext()
+}
+
+func (c *Context) backgroundContext() context.Context {
 	if c.background != nil {
 		return c.background
 	}
-	c.background = context.Background()
+	c.background, c.cancel = context.WithCancel(context.Background())
 	return c.background
 }
 
+//WithContext sets parent standard context, so its deadline and cancellation propagate to this context
+func (c *Context) WithContext(parent context.Context) {
+	c.cancelMux.Lock()
+	defer c.cancelMux.Unlock()
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.background, c.cancel = context.WithCancel(parent)
+}
+
+//WithTimeout sets deadline on this context, it returns function releasing timeout resources
+func (c *Context) WithTimeout(timeout time.Duration) context.CancelFunc {
+	c.cancelMux.Lock()
+	defer c.cancelMux.Unlock()
+	ctx, cancel := context.WithTimeout(c.backgroundContext(), timeout)
+	var parentCancel = c.cancel
+	c.background = ctx
+	c.cancel = func() {
+		cancel()
+		parentCancel()
+	}
+	return cancel
+}
+
+//ScopedTimeout sets deadline on this context till returned restore function is called
+func (c *Context) ScopedTimeout(timeout time.Duration) func() {
+	c.cancelMux.Lock()
+	defer c.cancelMux.Unlock()
+	background := c.backgroundContext()
+	cancel := c.cancel
+	timed, timedCancel := context.WithTimeout(background, timeout)
+	c.background = timed
+	c.cancel = func() {
+		timedCancel()
+		cancel()
+	}
+	return func() {
+		timedCancel()
+		c.cancelMux.Lock()
+		defer c.cancelMux.Unlock()
+		c.background, c.cancel = background, cancel
+	}
+}
+
+//Detach replaces canceled or timed out background with a new one till returned restore function is called, so that cleanup can still run
+func (c *Context) Detach() func() {
+	c.cancelMux.Lock()
+	defer c.cancelMux.Unlock()
+	if c.backgroundContext().Err() == nil {
+		return func() {}
+	}
+	background, cancel := c.background, c.cancel
+	c.background, c.cancel = context.WithCancel(context.Background())
+	return func() {
+		c.cancelMux.Lock()
+		defer c.cancelMux.Unlock()
+		c.cancel()
+		c.background, c.cancel = background, cancel
+	}
+}
+
+//Cancel cancels this context and all its clones
+func (c *Context) Cancel() {
+	c.cancelMux.Lock()
+	c.backgroundContext()
+	cancel := c.cancel
+	c.cancelMux.Unlock()
+	cancel()
+}
+
+//Done returns a channel that is closed when this context is canceled, timed out or closed
+func (c *Context) Done() <-chan struct{} {
+	return c.Background().Done()
+}
+
+//Err returns non nil error if this context was canceled or timed out
+func (c *Context) Err() error {
+	return c.Background().Err()
+}
+
 //Publish publishes event to listeners, it updates current run details like activity workflow name etc ...
 func (c *Context) Publish(value interface{}) msg.Event {
 	event, ok := value.(msg.Event)
@@ -77,6 +171,35 @@ func (c *Context) PublishWithStartEvent(value interface{}, init msg.Event) msg.E
 	return event
 }
 
+//Heartbeat returns currently running action heartbeat tracker (nil safe)
+func (c *Context) Heartbeat() *Heartbeat {
+	c.heartbeatMux.Lock()
+	defer c.heartbeatMux.Unlock()
+	return c.heartbeat
+}
+
+//beginHeartbeat starts running action heartbeat tracker nested in the current one
+func (c *Context) beginHeartbeat(service, action string) *Heartbeat {
+	c.heartbeatMux.Lock()
+	heartbeat := newHeartbeat(c.heartbeat, service, action)
+	c.heartbeat = heartbeat
+	c.heartbeatMux.Unlock()
+	heartbeat.start(c)
+	return heartbeat
+}
+
+//endHeartbeat stops heartbeat tracker, the closest running tracker becomes current, so that concurrently running actions never restore already stopped one
+func (c *Context) endHeartbeat(heartbeat *Heartbeat) {
+	heartbeat.stop()
+	c.heartbeatMux.Lock()
+	defer c.heartbeatMux.Unlock()
+	current := c.heartbeat
+	for current != nil && current.isStopped() {
+		current = current.parent
+	}
+	c.heartbeat = current
+}
+
 //SetListener sets context event Listener
 func (c *Context) SetListener(listener msg.Listener) {
 	c.Listener = listener
@@ -93,14 +216,20 @@ func (c *Context) Clone() *Context {
 		c.cloned = make([]*Context, 0)
 	}
 	result := &Context{}
+	result.background, result.cancel = context.WithCancel(c.Background())
 	result.Wait = &sync.WaitGroup{}
 	result.Context = c.Context.Clone()
 	result.state = NewDefaultState(c)
-	result.state.Apply(c.state)
+	c.SafeState().Read(func(state data.Map) {
+		result.state.Apply(state)
+	})
+	result.stateMux = c.stateMux
 	result.SessionID = c.SessionID
 	result.Listener = c.Listener
 	result.CLIEnabled = c.CLIEnabled
 	result.Secrets = c.Secrets
+	result.heartbeat = c.Heartbeat()
+	result.secretValues = c.SecretValues()
 	result.AsyncUnsafeKeys = make(map[interface{}]bool)
 	for k, v := range c.AsyncUnsafeKeys {
 		result.AsyncUnsafeKeys[k] = v
@@ -109,6 +238,46 @@ func (c *Context) Clone() *Context {
 	return result
 }
 
+//AsyncClone clones the context with deep copied state guarded by its own mutex, so that concurrently running actions
+//never share state maps with the parent or sibling contexts, changes have to be explicitly merged back.
+func (c *Context) AsyncClone() *Context {
+	result := c.Clone()
+	result.state = c.SafeState().DeepClone()
+	result.stateMux = &sync.RWMutex{}
+	return result
+}
+
+//deepCopy returns a copy of maps and slices, other values are returned as is
+func deepCopy(value interface{}) interface{} {
+	switch actual := value.(type) {
+	case data.Map:
+		var result = data.NewMap()
+		for k, v := range actual {
+			result[k] = deepCopy(v)
+		}
+		return result
+	case map[string]interface{}:
+		var result = make(map[string]interface{}, len(actual))
+		for k, v := range actual {
+			result[k] = deepCopy(v)
+		}
+		return result
+	case map[interface{}]interface{}:
+		var result = make(map[interface{}]interface{}, len(actual))
+		for k, v := range actual {
+			result[k] = deepCopy(v)
+		}
+		return result
+	case []interface{}:
+		var result = make([]interface{}, len(actual))
+		for i, v := range actual {
+			result[i] = deepCopy(v)
+		}
+		return result
+	}
+	return value
+}
+
 func (c *Context) parentURLCandidates() []string {
 	var result = make([]string, 0)
 	if c.Source != nil {
@@ -125,6 +294,8 @@ func (c *Context) parentURLCandidates() []string {
 
 //IsLoggingEnabled returns tru if logging is enabled
 func (c *Context) IsLoggingEnabled() bool {
+	c.loggingMux.Lock()
+	defer c.loggingMux.Unlock()
 	if c.Logging == nil {
 		return true
 	}
@@ -133,9 +304,24 @@ func (c *Context) IsLoggingEnabled() bool {
 
 //SetLogging set logging on and off
 func (c *Context) SetLogging(flag bool) {
+	c.loggingMux.Lock()
+	defer c.loggingMux.Unlock()
 	c.Logging = &flag
 }
 
+//ScopedLogging sets logging flag till returned restore function is called, nil flag enables logging
+func (c *Context) ScopedLogging(logging *bool) func() {
+	c.loggingMux.Lock()
+	defer c.loggingMux.Unlock()
+	original := c.Logging
+	c.Logging = logging
+	return func() {
+		c.loggingMux.Lock()
+		defer c.loggingMux.Unlock()
+		c.Logging = original
+	}
+}
+
 //ExpandResource substitutes any $ expression with the key value from the state map if it is present.
 func (c *Context) ExpandResource(resource *url.Resource) (*url.Resource, error) {
 	if resource == nil {
@@ -204,37 +390,36 @@ func (c *Context) Deffer(functions ...func()) []func() {
 	return *result
 }
 
-//State returns a context state map.
+//State returns a context state map, state is shared with concurrently running actions, use SafeState to access it.
 func (c *Context) State() data.Map {
-	if c.state == nil {
-		c.state = NewDefaultState(c)
-	}
 	return c.state
 }
 
 //SetState sets a new state map
 func (c *Context) SetState(state data.Map) {
+	c.stateMux.Lock()
+	defer c.stateMux.Unlock()
 	c.state = state
 }
 
 //Expand substitute $ expression if present in the text and state map.
 func (c *Context) Expand(text string) string {
-	state := c.State()
-	return state.ExpandAsText(text)
+	return c.SafeState().ExpandAsText(text)
 }
 
 //PublishAndRestore sets supplied value and returns func restoring original values
 func (s *Context) PublishAndRestore(values map[string]interface{}) func() {
 	var backup = map[string]interface{}{}
+	state := s.SafeState()
 	for k, v := range values {
-		if value, has := s.state.GetValue(k); has {
+		if value, has := state.GetValue(k); has {
 			backup[k] = value
 		}
-		s.state.SetValue(k, v)
+		state.SetValue(k, v)
 	}
 	return func() {
 		for k, v := range backup {
-			s.state.SetValue(k, v)
+			state.SetValue(k, v)
 		}
 	}
 }
@@ -277,7 +462,7 @@ func (c *Context) NewRequest(serviceName, action string, rawRequest map[string]i
 //AsRequest converts a source map into request for provided service and action.
 func (c *Context) AsRequest(serviceName, action string, source map[string]interface{}) (request interface{}, err error) {
 
-	expanded := c.state.Expand(source)
+	expanded := c.SafeState().Expand(source)
 	source = toolbox.AsMap(expanded)
 	if request, err = c.NewRequest(serviceName, action, source); err != nil {
 		return request, fmt.Errorf("unable to create %v request %v", serviceName+":"+action, err)
@@ -289,6 +474,12 @@ func (c *Context) AsRequest(serviceName, action string, source map[string]interf
 //Close closes this context, it executes all deferred function and set closed flag.
 func (c *Context) Close() {
 	atomic.StoreInt32(&c.closed, 1)
+	c.cancelMux.Lock()
+	cancel := c.cancel
+	c.cancelMux.Unlock()
+	if cancel != nil {
+		cancel()
+	}
 	for _, context := range c.cloned {
 		context.Close()
 	}
@@ -328,6 +519,7 @@ It comes with the following registered keys:
 	* uuid.next - generate unique id
 	* uuid.Get - returns previously generated unique id, or generate new
 	*.env.XXX where XXX is the ID of the env variable to return
+	* secret.scheme:key - secret resolved with registered SecretProvider i.e. secret.env:DB_PASSWORD
 	* all UFD registry functions
 */
 
@@ -441,11 +633,19 @@ func NewDefaultState(ctx *Context) data.Map {
 	})
 
 	if ctx != nil {
+		//returns secret provider resolved value i.e. ${secret.env:DB_PASSWORD}
+		result.Put("secret", func(key string) interface{} {
+			value, err := ctx.Secret(key)
+			if err != nil {
+				return nil
+			}
+			return value
+		})
 		result.Put("secrets", func(key string) interface{} {
 			if ctx.Secrets == nil {
 				return ""
 			}
-			config, err := ctx.Secrets.GetCredentials(key)
+			config, err := ctx.Credentials(key)
 			if err == nil {
 				var result = make(map[string]interface{})
 				if err = toolbox.DefaultConverter.AssignConverted(&result, config); err == nil {
@@ -458,8 +658,10 @@ func NewDefaultState(ctx *Context) data.Map {
 	}
 
 	neatly.AddStandardUdf(result)
+	udfRegistryMux.RLock()
 	for k, v := range UdfRegistry {
 		result.Put(k, v)
 	}
+	udfRegistryMux.RUnlock()
 	return result
 }
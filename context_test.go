@@ -4,10 +4,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/endly"
 	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
 	"github.com/viant/toolbox/url"
+	"os"
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNewDefaultState(t *testing.T) {
@@ -106,3 +109,104 @@ func TestContext_Clone(t *testing.T) {
 	}
 
 }
+
+func TestContext_AsyncClone(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	state := context.State()
+	state.Put("app", map[string]interface{}{"name": "myapp", "ports": []interface{}{8080}})
+	state.Put("meta", data.Map{"version": 1})
+
+	cloned := context.AsyncClone()
+	cloned.SetValue("app.name", "other")
+	cloned.SetValue("meta.version", 2)
+	ports, _ := cloned.GetValue("app.ports")
+	ports.([]interface{})[0] = 9090
+	cloned.SetValue("added", true)
+
+	assert.Equal(t, map[string]interface{}{"name": "myapp", "ports": []interface{}{8080}}, state.Get("app"))
+	version, _ := state.GetValue("meta.version")
+	assert.EqualValues(t, 1, version)
+	assert.False(t, state.Has("added"))
+	value, _ := cloned.GetValue("app.name")
+	assert.Equal(t, "other", value)
+	assert.False(t, cloned.IsClosed())
+	context.Close()
+	assert.True(t, cloned.IsClosed())
+}
+
+func TestContext_Cancel(t *testing.T) {
+	manager := endly.New()
+	{
+		context := manager.NewContext(toolbox.NewContext())
+		cloned := context.Clone()
+		assert.Nil(t, cloned.Err())
+		context.Cancel()
+		assert.NotNil(t, context.Err())
+		assert.NotNil(t, cloned.Err())
+		response := newService().Run(cloned, &TestRequest{Data: "test"})
+		assert.Equal(t, "error", response.Status)
+	}
+	{
+		context := manager.NewContext(toolbox.NewContext())
+		release := context.WithTimeout(time.Millisecond)
+		defer release()
+		select {
+		case <-context.Done():
+		case <-time.After(time.Second):
+			assert.Fail(t, "expected context deadline")
+		}
+		assert.NotNil(t, context.Err())
+	}
+}
+
+func TestContext_Secret(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	_ = os.Setenv("ENDLY_TEST_SECRET", "p@ssw0rd")
+	defer os.Unsetenv("ENDLY_TEST_SECRET")
+
+	assert.Equal(t, "pass: p@ssw0rd", context.Expand("pass: ${secret.env:ENDLY_TEST_SECRET}"))
+	value, err := context.Secret("env:ENDLY_TEST_SECRET")
+	assert.Nil(t, err)
+	assert.Equal(t, "p@ssw0rd", value)
+	cloned := context.Clone()
+	assert.Equal(t, "echo *****", cloned.MaskSecrets("echo p@ssw0rd"))
+
+	_, err = context.Secret("unknown:abc")
+	assert.NotNil(t, err)
+	_, err = context.Secret("env:ENDLY_UNDEFINED_SECRET")
+	assert.NotNil(t, err)
+}
+
+func TestIsAmbientCredentials(t *testing.T) {
+	assert.True(t, endly.IsAmbientCredentials(""))
+	assert.True(t, endly.IsAmbientCredentials(endly.AmbientCredentials))
+	assert.False(t, endly.IsAmbientCredentials("aws-e2e"))
+}
+
+func TestContext_Credentials(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	_ = os.Setenv("ENDLY_TEST_CREDENTIALS", `{"Username":"bob","Password":"s3cr3tPass"}`)
+	defer os.Unsetenv("ENDLY_TEST_CREDENTIALS")
+
+	config, err := context.Credentials("env://ENDLY_TEST_CREDENTIALS")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "bob", config.Username)
+		assert.Equal(t, "s3cr3tPass", config.Password)
+	}
+	assert.Equal(t, "password: *****", context.MaskSecrets("password: s3cr3tPass"))
+
+	value, err := context.Secret("env:ENDLY_TEST_CREDENTIALS#Username")
+	assert.Nil(t, err)
+	assert.Equal(t, "bob", value)
+	_, err = context.Secret("env:ENDLY_TEST_CREDENTIALS#Token")
+	assert.NotNil(t, err)
+
+	assert.True(t, endly.IsSecretReference("env:ENDLY_TEST_CREDENTIALS"))
+	assert.False(t, endly.IsSecretReference("file:///tmp/cred.json"))
+	assert.False(t, endly.IsSecretReference("mem://localhost/cred.json"))
+	_, err = context.Credentials("env:ENDLY_UNDEFINED_CREDENTIALS")
+	assert.NotNil(t, err)
+}
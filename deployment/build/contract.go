@@ -8,6 +8,13 @@ import (
 	"github.com/viant/endly/system/storage"
 	"github.com/viant/toolbox/secret"
 	"github.com/viant/toolbox/url"
+	"strings"
+)
+
+const (
+	defaultVersionVar = "main.Version"
+	defaultBuildDir   = "/tmp/endly/build"
+	manifestFile      = "manifest.json"
 )
 
 //Spec represents build specification.
@@ -101,3 +108,88 @@ func (m *Meta) Validate() error {
 	}
 	return nil
 }
+
+//CrossCompileRequest represents a go project build request for a matrix of GOOS/GOARCH platforms
+type CrossCompileRequest struct {
+	Source     *url.Resource     `required:"true" description:"go project location, host and path"`
+	Package    string            `description:"package to build, default ."`
+	Name       string            `required:"true" description:"binary name, artifacts are named name_os_arch"`
+	Version    string            `description:"version injected with ldflags -X"`
+	VersionVar string            `description:"version variable, default main.Version"`
+	LdFlags    string            `description:"additional ldflags"`
+	Platforms  []string          `required:"true" description:"GOOS/GOARCH pairs, i.e. linux/amd64, darwin/arm64, windows/amd64"`
+	Env        map[string]string `description:"build environment variables, default CGO_ENABLED=0"`
+	BuildDir   string            `description:"build output directory on source host, default /tmp/endly/build/name"`
+	Dest       *url.Resource     `required:"true" description:"artifacts storage URL, manifest.json is published along the artifacts"`
+}
+
+//Artifact represents a published build artifact
+type Artifact struct {
+	OS     string
+	Arch   string
+	Name   string
+	URL    string
+	Size   int
+	SHA256 string
+}
+
+//Manifest represents published build artifacts
+type Manifest struct {
+	Name      string
+	Version   string
+	Artifacts []*Artifact
+}
+
+//CrossCompileResponse represents a cross compile response
+type CrossCompileResponse struct {
+	*Manifest
+	ManifestURL string
+}
+
+//Init initialises request
+func (r *CrossCompileRequest) Init() error {
+	if r.Package == "" {
+		r.Package = "."
+	}
+	if r.VersionVar == "" {
+		r.VersionVar = defaultVersionVar
+	}
+	if len(r.Env) == 0 {
+		r.Env = map[string]string{"CGO_ENABLED": "0"}
+	}
+	if r.BuildDir == "" && r.Name != "" {
+		r.BuildDir = defaultBuildDir + "/" + r.Name
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *CrossCompileRequest) Validate() error {
+	if r.Source == nil {
+		return errors.New("source was empty")
+	}
+	if r.Name == "" {
+		return errors.New("name was empty")
+	}
+	if r.Dest == nil {
+		return errors.New("dest was empty")
+	}
+	if len(r.Platforms) == 0 {
+		return errors.New("platforms were empty")
+	}
+	for _, platform := range r.Platforms {
+		if _, _, err := parsePlatform(platform); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//parsePlatform returns GOOS and GOARCH for os/arch platform
+func parsePlatform(platform string) (string, string, error) {
+	pair := strings.Split(strings.TrimSpace(platform), "/")
+	if len(pair) != 2 || pair[0] == "" || pair[1] == "" {
+		return "", "", fmt.Errorf("invalid platform: %v, expected os/arch", platform)
+	}
+	return pair[0], pair[1], nil
+}
@@ -0,0 +1,134 @@
+package build
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/exec"
+	"github.com/viant/endly/system/storage"
+	"github.com/viant/endly/system/storage/copy"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"path"
+	"strings"
+)
+
+//artifactName returns platform specific binary name
+func artifactName(name, goOS, goArch string) string {
+	result := fmt.Sprintf("%v_%v_%v", name, goOS, goArch)
+	if goOS == "windows" {
+		result += ".exe"
+	}
+	return result
+}
+
+//buildCommand returns go build command with version ldflags
+func buildCommand(request *CrossCompileRequest, output string) string {
+	var ldFlags = make([]string, 0)
+	if request.Version != "" {
+		ldFlags = append(ldFlags, fmt.Sprintf("-X %v=%v", request.VersionVar, request.Version))
+	}
+	if request.LdFlags != "" {
+		ldFlags = append(ldFlags, request.LdFlags)
+	}
+	command := "go build"
+	if len(ldFlags) > 0 {
+		command += fmt.Sprintf(` -ldflags "%v"`, strings.Join(ldFlags, " "))
+	}
+	return fmt.Sprintf("%v -o %v %v", command, output, request.Package)
+}
+
+//hostResource returns resource for supplied path on the source host
+func hostResource(source *url.Resource, location string) *url.Resource {
+	if source.Credentials == "" || source.ParsedURL == nil {
+		return url.NewResource(location)
+	}
+	return url.NewResource(fmt.Sprintf("%v://%v%v", source.ParsedURL.Scheme, source.ParsedURL.Host, location), source.Credentials)
+}
+
+//crossCompile builds go project for each requested platform and publishes artifacts with manifest to dest
+func (s *service) crossCompile(context *endly.Context, request *CrossCompileRequest) (*CrossCompileResponse, error) {
+	source, err := context.ExpandResource(request.Source)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := context.ExpandResource(request.Dest)
+	if err != nil {
+		return nil, err
+	}
+	target := exec.GetServiceTarget(source)
+	var response = &CrossCompileResponse{
+		Manifest: &Manifest{Name: request.Name, Version: request.Version, Artifacts: make([]*Artifact, 0)},
+	}
+	if err = endly.Run(context, exec.NewRunRequest(target, false, "mkdir -p "+request.BuildDir), nil); err != nil {
+		return nil, err
+	}
+	for _, platform := range request.Platforms {
+		goOS, goArch, _ := parsePlatform(platform)
+		name := artifactName(request.Name, goOS, goArch)
+		output := path.Join(request.BuildDir, name)
+		runRequest := exec.NewRunRequest(target, false, buildCommand(request, output))
+		runRequest.Directory = source.ParsedURL.Path
+		runRequest.CheckError = true
+		for key, value := range request.Env {
+			runRequest.Env[key] = value
+		}
+		runRequest.Env["GOOS"] = goOS
+		runRequest.Env["GOARCH"] = goArch
+		if err = endly.Run(context, runRequest, nil); err != nil {
+			return nil, fmt.Errorf("failed to build %v: %v", platform, err)
+		}
+		artifactURL := toolbox.URLPathJoin(dest.URL, name)
+		if _, err = storage.Copy(context, copy.New(hostResource(source, output), url.NewResource(artifactURL, dest.Credentials), false, false, nil)); err != nil {
+			return nil, fmt.Errorf("failed to publish %v: %v", name, err)
+		}
+		artifact := &Artifact{OS: goOS, Arch: goArch, Name: name, URL: artifactURL}
+		if err = s.updateChecksum(context, url.NewResource(artifactURL, dest.Credentials), artifact); err != nil {
+			return nil, err
+		}
+		response.Artifacts = append(response.Artifacts, artifact)
+	}
+	response.ManifestURL = toolbox.URLPathJoin(dest.URL, manifestFile)
+	err = s.publishManifest(context, url.NewResource(response.ManifestURL, dest.Credentials), response.Manifest)
+	return response, err
+}
+
+//updateChecksum sets published artifact size and sha256 checksum
+func (s *service) updateChecksum(context *endly.Context, resource *url.Resource, artifact *Artifact) error {
+	fs, err := storage.StorageService(context, resource)
+	if err != nil {
+		return err
+	}
+	resource, storageOptions, err := storage.GetResourceWithOptions(context, resource)
+	if err != nil {
+		return err
+	}
+	data, err := fs.DownloadWithURL(context.Background(), resource.URL, storageOptions...)
+	if err != nil {
+		return err
+	}
+	checksum := sha256.Sum256(data)
+	artifact.Size = len(data)
+	artifact.SHA256 = hex.EncodeToString(checksum[:])
+	return nil
+}
+
+//publishManifest uploads JSON manifest to supplied resource
+func (s *service) publishManifest(context *endly.Context, resource *url.Resource, manifest *Manifest) error {
+	fs, err := storage.StorageService(context, resource)
+	if err != nil {
+		return err
+	}
+	resource, storageOptions, err := storage.GetResourceWithOptions(context, resource)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fs.Upload(context.Background(), resource.URL, 0644, bytes.NewReader(data), storageOptions...)
+}
@@ -0,0 +1,41 @@
+package build
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/url"
+	"testing"
+)
+
+func TestCrossCompileRequest_Init(t *testing.T) {
+	request := &CrossCompileRequest{
+		Source:    url.NewResource("/tmp/app"),
+		Name:      "app",
+		Version:   "1.2.0",
+		Platforms: []string{"linux/amd64", "windows/amd64"},
+		Dest:      url.NewResource("mem://localhost/dist/1.2.0"),
+	}
+	assert.Nil(t, request.Init())
+	assert.Nil(t, request.Validate())
+	assert.Equal(t, "/tmp/endly/build/app", request.BuildDir)
+	assert.Equal(t, map[string]string{"CGO_ENABLED": "0"}, request.Env)
+	assert.Equal(t, `go build -ldflags "-X main.Version=1.2.0" -o /tmp/endly/build/app/app_linux_amd64 .`, buildCommand(request, "/tmp/endly/build/app/app_linux_amd64"))
+
+	request.Version = ""
+	request.LdFlags = "-s -w"
+	request.Package = "./cmd/app"
+	assert.Equal(t, `go build -ldflags "-s -w" -o app_windows_amd64.exe ./cmd/app`, buildCommand(request, artifactName("app", "windows", "amd64")))
+
+	request.LdFlags = ""
+	assert.Equal(t, `go build -o app_darwin_arm64 ./cmd/app`, buildCommand(request, artifactName("app", "darwin", "arm64")))
+
+	request.Platforms = []string{"linux"}
+	assert.NotNil(t, request.Validate())
+	assert.NotNil(t, (&CrossCompileRequest{}).Validate())
+}
+
+func TestHostResource(t *testing.T) {
+	assert.Equal(t, "file:///tmp/build/app", hostResource(url.NewResource("/tmp/src"), "/tmp/build/app").URL)
+	resource := hostResource(url.NewResource("scp://10.0.0.1:22/src", "dev"), "/tmp/build/app")
+	assert.Equal(t, "scp://10.0.0.1:22/tmp/build/app", resource.URL)
+	assert.Equal(t, "dev", resource.Credentials)
+}
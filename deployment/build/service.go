@@ -29,7 +29,7 @@ func (s *service) getMeta(context *endly.Context, request *Request) (*Meta, erro
 	s.mutex.RLock()
 	result, hasMeta := s.registry[request.BuildSpec.Name]
 	s.mutex.RUnlock()
-	var state = context.State()
+	var state = context.SafeState().Clone()
 	if !hasMeta {
 		var metaURL = request.MetaURL
 		if metaURL == "" {
@@ -132,7 +132,7 @@ func (s *service) setSdkIfNeeded(context *endly.Context, request *Request) error
 
 func (s *service) build(context *endly.Context, request *Request) (*Response, error) {
 	var result = &Response{}
-	state := context.State()
+	state := context.SafeState()
 	target, err := context.ExpandResource(request.Target)
 	if err != nil {
 		return nil, err
@@ -302,6 +302,25 @@ func (s *service) registerRoutes() {
 		},
 	})
 
+	s.Register(&endly.Route{
+		Action: "crossCompile",
+		RequestInfo: &endly.ActionInfo{
+			Description: "build go project for GOOS/GOARCH matrix and publish artifacts with manifest",
+		},
+		RequestProvider: func() interface{} {
+			return &CrossCompileRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &CrossCompileResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*CrossCompileRequest); ok {
+				return s.crossCompile(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
 }
 
 //New creates a new build service
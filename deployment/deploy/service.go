@@ -145,7 +145,7 @@ func (s *service) updateSessionDeployment(context *endly.Context, target *url.Re
 
 //TODO break it down - too large and messy
 func (s *service) discoverTransfer(context *endly.Context, request *Request, meta *Meta, deploymentTarget *TargetMeta) (*copy.Rule, error) {
-	var state = context.State()
+	var state = context.SafeState()
 	transfer := deploymentTarget.Deployment.Transfer
 	if meta.Versioning == "" || request.Version == "" {
 		return transfer, nil
@@ -265,14 +265,14 @@ func (s *service) updateOperatingSystem(context *endly.Context, target *url.Reso
 		osMap.Put("Arch", operatingSystem.Arch)
 		osMap.Put("Version", operatingSystem.Version)
 		osMap.Put("Hardware", operatingSystem.Hardware)
-		var state = context.State()
+		var state = context.SafeState()
 		state.Put("os", osMap)
 	}
 }
 
 func (s *service) updateDeployState(context *endly.Context, target *url.Resource) {
 
-	state := context.State()
+	state := context.SafeState()
 
 	deploySetting := data.NewMap()
 	state.Put("deploy", deploySetting)
@@ -292,7 +292,7 @@ func (s *service) deploy(context *endly.Context, request *Request) (*Response, e
 	}
 
 	s.updateDeployState(context, target)
-	state := context.State()
+	state := context.SafeState()
 
 	var response = &Response{}
 	if s.checkIfDeployedOnSession(context, target, request) {
@@ -336,8 +336,8 @@ func (s *service) deploy(context *endly.Context, request *Request) (*Response, e
 	if err != nil {
 		return nil, err
 	}
-	var artifact = state.GetMap(artifactKey)
-	if artifact != nil {
+	if value := state.Get(artifactKey); value != nil && toolbox.IsMap(value) {
+		var artifact = data.Map(toolbox.AsMap(value))
 		response.Version = artifact.GetString("")
 	}
 	defer state.Delete(artifactKey)
@@ -396,7 +396,7 @@ func (s *service) getMeta(context *endly.Context, request *Request) (*Meta, erro
 	s.mutex.RLock()
 	result, hasMeta := s.registry[request.AppName]
 	s.mutex.RUnlock()
-	var state = context.State()
+	var state = context.SafeState().Clone()
 	if !hasMeta {
 		var metaURL = request.MetaURL
 		if metaURL == "" {
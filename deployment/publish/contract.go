@@ -0,0 +1,104 @@
+package publish
+
+import (
+	"errors"
+	"github.com/viant/toolbox/url"
+	"time"
+)
+
+const (
+	//DefaultManifest represents default release manifest file name
+	DefaultManifest = "manifest.json"
+	//ChecksumFile represents release checksums file name, each line has sha256 checksum followed by artifact name
+	ChecksumFile = "SHA256SUMS"
+)
+
+//ImageRequest represents docker image publish request, source image is tagged and pushed with each target tag
+type ImageRequest struct {
+	Source      string   `required:"true" description:"local image i.e. myapp:latest"`
+	Tags        []string `required:"true" description:"target image tags, i.e. registry.example.com/team/myapp:1.2.0"`
+	Credentials string   `description:"registry credentials name or location from secrets, i.e. dockerHub"`
+}
+
+//Validate checks if request is valid
+func (r *ImageRequest) Validate() error {
+	if r.Source == "" {
+		return errors.New("source was empty")
+	}
+	if len(r.Tags) == 0 {
+		return errors.New("tags were empty")
+	}
+	return nil
+}
+
+//Image represents published image
+type Image struct {
+	Tag       string
+	Digest    string `json:",omitempty"`
+	Reference string `description:"image reference pinned with digest, i.e. registry.example.com/team/myapp@sha256:..."`
+}
+
+//ImageResponse represents docker image publish response
+type ImageResponse struct {
+	Images []*Image
+}
+
+//ArtifactRequest represents versioned artifacts publish request
+type ArtifactRequest struct {
+	Source    *url.Resource `required:"true" description:"artifact file or directory location"`
+	Assets    []string      `description:"asset paths relative to source directory, all source directory files are published if empty"`
+	Dest      *url.Resource `required:"true" description:"release location, i.e. s3://releases/myapp or gs://releases/myapp, artifacts are published to dest/version/"`
+	Name      string        `required:"true" description:"release name"`
+	Version   string        `required:"true" description:"release version"`
+	Manifest  string        `description:"manifest file name, manifest.json by default"`
+	Overwrite bool          `description:"flag to overwrite already published version, by default publishing existing version fails"`
+}
+
+//Init initialises request
+func (r *ArtifactRequest) Init() error {
+	if r.Manifest == "" {
+		r.Manifest = DefaultManifest
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *ArtifactRequest) Validate() error {
+	if r.Source == nil {
+		return errors.New("source was empty")
+	}
+	if r.Dest == nil {
+		return errors.New("dest was empty")
+	}
+	if r.Name == "" {
+		return errors.New("name was empty")
+	}
+	if r.Version == "" {
+		return errors.New("version was empty")
+	}
+	return nil
+}
+
+//Artifact represents published artifact
+type Artifact struct {
+	Name   string
+	URL    string
+	Size   int64
+	SHA256 string
+}
+
+//Manifest represents published release manifest
+type Manifest struct {
+	Name      string
+	Version   string
+	Published time.Time
+	Artifacts []*Artifact
+}
+
+//ArtifactResponse represents versioned artifacts publish response
+type ArtifactResponse struct {
+	BaseURL      string
+	ManifestURL  string
+	ChecksumsURL string
+	Artifacts    []*Artifact
+}
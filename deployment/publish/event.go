@@ -0,0 +1,19 @@
+package publish
+
+import (
+	"fmt"
+	"github.com/viant/endly/model/msg"
+	"strings"
+)
+
+//Messages returns messages
+func (r *ImageRequest) Messages() []*msg.Message {
+	return []*msg.Message{msg.NewMessage(msg.NewStyled(fmt.Sprintf("%v -> %v", r.Source, strings.Join(r.Tags, ", ")), msg.MessageStyleGeneric),
+		msg.NewStyled("publish", msg.MessageStyleGeneric))}
+}
+
+//Messages returns messages
+func (r *ArtifactResponse) Messages() []*msg.Message {
+	return []*msg.Message{msg.NewMessage(msg.NewStyled(r.ManifestURL, msg.MessageStyleGeneric),
+		msg.NewStyled("publish", msg.MessageStyleGeneric))}
+}
@@ -0,0 +1,9 @@
+package publish
+
+import "github.com/viant/endly"
+
+func init() {
+	_ = endly.Registry.Register(func() endly.Service {
+		return New()
+	})
+}
@@ -0,0 +1,284 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/storage"
+	aurl "github.com/viant/afs/url"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/docker"
+	estorage "github.com/viant/endly/system/storage"
+	"github.com/viant/toolbox/url"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	//ServiceID represents artifact publishing service id
+	ServiceID = "deployment/publish"
+)
+
+var digestExpr = regexp.MustCompile(`digest: (sha256:[a-f0-9]{64})`)
+
+type service struct {
+	*endly.AbstractService
+}
+
+//imageTag returns docker tag, registry port is not mistaken for image version, i.e. localhost:5000/myapp:1.0
+func imageTag(image string) *docker.Tag {
+	var result = &docker.Tag{Image: image}
+	if index := strings.LastIndex(image, ":"); index > strings.LastIndex(image, "/") {
+		result.Image, result.Version = image[:index], image[index+1:]
+	}
+	if index := strings.LastIndex(result.Image, "/"); index != -1 {
+		result.Registry, result.Image = result.Image[:index], result.Image[index+1:]
+	}
+	return result
+}
+
+//imageDigest returns pushed image digest from push output
+func imageDigest(stdout []string) string {
+	for i := len(stdout) - 1; i >= 0; i-- {
+		if matched := digestExpr.FindStringSubmatch(stdout[i]); len(matched) > 1 {
+			return matched[1]
+		}
+	}
+	return ""
+}
+
+func (s *service) image(context *endly.Context, request *ImageRequest) (*ImageResponse, error) {
+	var response = &ImageResponse{Images: make([]*Image, 0)}
+	source := imageTag(context.Expand(request.Source))
+	for _, tag := range request.Tags {
+		target := imageTag(context.Expand(tag))
+		if target.String() != source.String() {
+			if err := endly.Run(context, &docker.TagRequest{SourceTag: source, TargetTag: target}, nil); err != nil {
+				return nil, fmt.Errorf("failed to tag %v as %v, %v", source, target, err)
+			}
+		}
+		pushResponse := &docker.PushResponse{}
+		if err := endly.Run(context, &docker.PushRequest{Credentials: request.Credentials, Tag: target}, pushResponse); err != nil {
+			return nil, fmt.Errorf("failed to push %v, %v", target, err)
+		}
+		image := &Image{Tag: target.String(), Digest: imageDigest(pushResponse.Stdout), Reference: target.String()}
+		if image.Digest != "" {
+			untagged := *target
+			untagged.Version = ""
+			image.Reference = untagged.String() + "@" + image.Digest
+		}
+		response.Images = append(response.Images, image)
+	}
+	return response, nil
+}
+
+//artifactAssets returns source asset URLs keyed by artifact name
+func (s *service) artifactAssets(ctx context.Context, fs afs.Service, source *url.Resource, assets []string, options []storage.Option) (map[string]string, error) {
+	var result = make(map[string]string)
+	if len(assets) > 0 {
+		for _, asset := range assets {
+			result[asset] = aurl.Join(source.URL, asset)
+		}
+		return result, nil
+	}
+	object, err := fs.Object(ctx, source.URL, options...)
+	if err != nil {
+		return nil, err
+	}
+	if !object.IsDir() {
+		result[object.Name()] = object.URL()
+		return result, nil
+	}
+	objects, err := fs.List(ctx, source.URL, options...)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range objects {
+		if candidate.IsDir() {
+			continue
+		}
+		result[candidate.Name()] = candidate.URL()
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no artifacts found in %v", source.URL)
+	}
+	return result, nil
+}
+
+//publishArtifact uploads artifact computing its checksum and size while it is transferred
+func (s *service) publishArtifact(ctx context.Context, fs afs.Service, name, sourceURL, destURL string, sourceOptions, destOptions []storage.Option) (*Artifact, error) {
+	reader, err := fs.OpenURL(ctx, sourceURL, sourceOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open artifact %v, %v", sourceURL, err)
+	}
+	defer reader.Close()
+	hasher := sha256.New()
+	counter := &byteCounter{}
+	if err = fs.Upload(ctx, destURL, 0644, io.TeeReader(reader, io.MultiWriter(hasher, counter)), destOptions...); err != nil {
+		return nil, fmt.Errorf("failed to upload artifact %v, %v", destURL, err)
+	}
+	return &Artifact{Name: name, URL: destURL, Size: counter.count, SHA256: hex.EncodeToString(hasher.Sum(nil))}, nil
+}
+
+func (s *service) artifact(context *endly.Context, request *ArtifactRequest) (*ArtifactResponse, error) {
+	source, err := context.ExpandResource(request.Source)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := context.ExpandResource(request.Dest)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := estorage.StorageService(context, source, dest)
+	if err != nil {
+		return nil, err
+	}
+	sourceOptions, err := estorage.StorageOptions(context, source)
+	if err != nil {
+		return nil, err
+	}
+	destOptions, err := estorage.StorageOptions(context, dest)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	manifest := &Manifest{Name: context.Expand(request.Name), Version: context.Expand(request.Version), Published: time.Now().UTC()}
+	response := &ArtifactResponse{BaseURL: aurl.Join(dest.URL, manifest.Version)}
+	response.ManifestURL = aurl.Join(response.BaseURL, request.Manifest)
+	response.ChecksumsURL = aurl.Join(response.BaseURL, ChecksumFile)
+	if !request.Overwrite {
+		if exists, _ := fs.Exists(ctx, response.ManifestURL, destOptions...); exists {
+			return nil, fmt.Errorf("%v %v has been already published: %v", manifest.Name, manifest.Version, response.ManifestURL)
+		}
+	}
+	assets, err := s.artifactAssets(ctx, fs, source, request.Assets, sourceOptions)
+	if err != nil {
+		return nil, err
+	}
+	var names = make([]string, 0, len(assets))
+	for name := range assets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checksums := new(bytes.Buffer)
+	for _, name := range names {
+		artifact, err := s.publishArtifact(ctx, fs, name, assets[name], aurl.Join(response.BaseURL, name), sourceOptions, destOptions)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Artifacts = append(manifest.Artifacts, artifact)
+		checksums.WriteString(fmt.Sprintf("%v  %v\n", artifact.SHA256, path.Clean(name)))
+	}
+	if err = fs.Upload(ctx, response.ChecksumsURL, 0644, checksums, destOptions...); err != nil {
+		return nil, err
+	}
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	//manifest is uploaded last, so that its presence marks a complete release
+	if err = fs.Upload(ctx, response.ManifestURL, 0644, bytes.NewReader(encoded), destOptions...); err != nil {
+		return nil, err
+	}
+	response.Artifacts = manifest.Artifacts
+	return response, nil
+}
+
+type byteCounter struct {
+	count int64
+}
+
+func (c *byteCounter) Write(data []byte) (int, error) {
+	c.count += int64(len(data))
+	return len(data), nil
+}
+
+const (
+	publishImageExample = `{
+  "Source": "myapp:latest",
+  "Tags": ["registry.example.com/team/myapp:1.2.0", "registry.example.com/team/myapp:latest"],
+  "Credentials": "registry"
+}`
+
+	publishArtifactExample = `{
+  "Source": {
+    "URL": "/build/myapp/dist/"
+  },
+  "Dest": {
+    "URL": "s3://releases/myapp",
+    "Credentials": "aws"
+  },
+  "Name": "myapp",
+  "Version": "1.2.0"
+}`
+)
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "image",
+		RequestInfo: &endly.ActionInfo{
+			Description: "tag and push docker image to registries",
+			Examples: []*endly.UseCase{
+				{
+					Description: "image publish",
+					Data:        publishImageExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &ImageRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ImageResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ImageRequest); ok {
+				return s.image(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "artifact",
+		RequestInfo: &endly.ActionInfo{
+			Description: "publish versioned artifacts with checksums and manifest to release location",
+			Examples: []*endly.UseCase{
+				{
+					Description: "artifact publish",
+					Data:        publishArtifactExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &ArtifactRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ArtifactResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ArtifactRequest); ok {
+				return s.artifact(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+//New creates a new artifact publishing service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
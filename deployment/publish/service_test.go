@@ -0,0 +1,96 @@
+package publish
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestImageTag(t *testing.T) {
+	var useCases = []struct {
+		image  string
+		expect string
+	}{
+		{image: "myapp", expect: "myapp"},
+		{image: "myapp:latest", expect: "myapp:latest"},
+		{image: "registry.example.com/team/myapp:1.2.0", expect: "registry.example.com/team/myapp:1.2.0"},
+		{image: "localhost:5000/myapp", expect: "localhost:5000/myapp"},
+		{image: "localhost:5000/myapp:1.0", expect: "localhost:5000/myapp:1.0"},
+	}
+	for _, useCase := range useCases {
+		assert.Equal(t, useCase.expect, imageTag(useCase.image).String(), useCase.image)
+	}
+	assert.Equal(t, "1.0", imageTag("localhost:5000/myapp:1.0").Version)
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	assert.Equal(t, digest, imageDigest([]string{"Pushed", "1.0: digest: " + digest + " size: 1570"}))
+	assert.Equal(t, "", imageDigest([]string{"Pushed"}))
+}
+
+func TestService_Artifact(t *testing.T) {
+	source, err := ioutil.TempDir("", "endly_publish_source")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(source)
+	dest, err := ioutil.TempDir("", "endly_publish_dest")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dest)
+	for name, content := range map[string]string{"app.tar.gz": "binary", "config.yaml": "port: 8080"} {
+		assert.Nil(t, ioutil.WriteFile(path.Join(source, name), []byte(content), 0644))
+	}
+
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	defer context.Close()
+	request := &ArtifactRequest{
+		Source:  url.NewResource(source),
+		Dest:    url.NewResource(path.Join(dest, "myapp")),
+		Name:    "myapp",
+		Version: "$version",
+	}
+	context.SafeState().SetValue("version", "1.2.0")
+	response := &ArtifactResponse{}
+	if !assert.Nil(t, endly.Run(context, request, response)) {
+		return
+	}
+	assert.Equal(t, 2, len(response.Artifacts))
+	checksum := sha256.Sum256([]byte("binary"))
+	assert.Equal(t, "app.tar.gz", response.Artifacts[0].Name)
+	assert.Equal(t, hex.EncodeToString(checksum[:]), response.Artifacts[0].SHA256)
+	assert.EqualValues(t, 6, response.Artifacts[0].Size)
+
+	published, err := ioutil.ReadFile(path.Join(dest, "myapp/1.2.0/app.tar.gz"))
+	assert.Nil(t, err)
+	assert.Equal(t, "binary", string(published))
+	checksums, err := ioutil.ReadFile(path.Join(dest, "myapp/1.2.0", ChecksumFile))
+	assert.Nil(t, err)
+	assert.Contains(t, string(checksums), hex.EncodeToString(checksum[:])+"  app.tar.gz\n")
+	encoded, err := ioutil.ReadFile(path.Join(dest, "myapp/1.2.0", DefaultManifest))
+	if assert.Nil(t, err) {
+		manifest := &Manifest{}
+		assert.Nil(t, json.Unmarshal(encoded, manifest))
+		assert.Equal(t, "1.2.0", manifest.Version)
+		assert.Equal(t, 2, len(manifest.Artifacts))
+	}
+
+	err = endly.Run(context, request, nil)
+	if assert.NotNil(t, err, "version already published") {
+		assert.Contains(t, err.Error(), "already published")
+	}
+	request.Overwrite = true
+	request.Assets = []string{"config.yaml"}
+	response = &ArtifactResponse{}
+	if assert.Nil(t, endly.Run(context, request, response)) {
+		assert.Equal(t, 1, len(response.Artifacts))
+	}
+}
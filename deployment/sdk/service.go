@@ -48,7 +48,7 @@ func (s *service) deploySdk(context *endly.Context, request *SetRequest) error {
 	}
 
 	ctx := context.Clone()
-	state := ctx.State()
+	state := ctx.SafeState()
 	state.Put("buildHost", target.ParsedURL.Host)
 	state.Put("buildHostCredential", target.Credentials)
 	serviceResponse := deploymentService.Run(ctx, &deploy.Request{
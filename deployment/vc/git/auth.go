@@ -7,8 +7,9 @@ import (
 	ssh2 "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
 )
 
-func getAuth(context *endly.Context, credentials string) (transport.AuthMethod, error) {
-	credConifg, err := context.Secrets.GetCredentials(credentials)
+//GetAuth returns git transport auth method for supplied credentials, ssh public keys if private key path is set, basic auth otherwise
+func GetAuth(context *endly.Context, credentials string) (transport.AuthMethod, error) {
+	credConifg, err := context.Credentials(credentials)
 	if err != nil {
 		return nil, err
 	}
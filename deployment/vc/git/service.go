@@ -25,7 +25,7 @@ func (s *service) clone(context *endly.Context, request *CheckoutRequest) (*git.
 	}
 	var err error
 	if request.Origin.Credentials != "" {
-		if options.Auth, err = getAuth(context, request.Origin.Credentials); err != nil {
+		if options.Auth, err = GetAuth(context, request.Origin.Credentials); err != nil {
 			return nil, err
 		}
 	}
@@ -59,7 +59,7 @@ func (s *service) checkout(context *endly.Context, request *CheckoutRequest) (*C
 
 	pullOptions := &git.PullOptions{RemoteName: "origin", Progress: os.Stdout}
 	if request.Origin.Credentials != "" {
-		if pullOptions.Auth, err = getAuth(context, request.Origin.Credentials); err != nil {
+		if pullOptions.Auth, err = GetAuth(context, request.Origin.Credentials); err != nil {
 			return nil, err
 		}
 	}
@@ -204,7 +204,7 @@ func (s *service) author(context *endly.Context, credentials string) *object.Sig
 	if credentials == "" {
 		return author
 	}
-	credConfig, err := context.Secrets.GetCredentials(credentials)
+	credConfig, err := context.Credentials(credentials)
 	if err != nil {
 		return author
 	}
@@ -242,7 +242,7 @@ func (s *service) commit(context *endly.Context, request *CommitRequest) (*Commi
 	}
 	pushOptions := &git.PushOptions{}
 	if request.Credentials != "" {
-		if pushOptions.Auth, err = getAuth(context, request.Credentials); err != nil {
+		if pushOptions.Auth, err = GetAuth(context, request.Credentials); err != nil {
 			return nil, err
 		}
 	}
@@ -0,0 +1,187 @@
+package web
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"github.com/viant/toolbox"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	//ProjectRESTApp represents web/rest app with MySQL datastore project template
+	ProjectRESTApp = "rest-app"
+	//ProjectDockerApp represents dockerized web app with MySQL datastore project template
+	ProjectDockerApp = "docker-app"
+	//ProjectBigData represents external app with BigQuery and MongoDB datastores project template
+	ProjectBigData = "bigdata"
+)
+
+//projectTemplates represents starter project templates
+var projectTemplates = map[string]func(app string) *RunRequest{
+	ProjectRESTApp: func(app string) *RunRequest {
+		return &RunRequest{
+			Build: &Build{Sdk: "go:1.12", App: app, TemplateApp: "go/webdb"},
+			Datastore: []*Datastore{
+				{Driver: "mysql", Name: "db1", Config: true},
+			},
+			Testing: &Testing{Regression: inlineWorkflowFormat, REST: true, HTTP: true, UseCaseData: "test", DataValidation: true, LogValidation: true},
+		}
+	},
+	ProjectDockerApp: func(app string) *RunRequest {
+		return &RunRequest{
+			Build: &Build{Sdk: "go:1.12", App: app, TemplateApp: "go/web", Docker: true, Dockerfile: true, DockerCompose: true,
+				Tag: &Tag{Image: app, Version: "0.1"}},
+			Datastore: []*Datastore{
+				{Driver: "mysql", Name: "db1", Config: true},
+			},
+			Testing: &Testing{Regression: inlineWorkflowFormat, HTTP: true, UseCaseData: "test", DataValidation: true, LogValidation: true},
+		}
+	},
+	ProjectBigData: func(app string) *RunRequest {
+		return &RunRequest{
+			Build: &Build{Sdk: "jdk:1.8", App: app, TemplateApp: "default"},
+			Datastore: []*Datastore{
+				{Driver: "bigquery", Name: "db1"},
+				{Driver: "mongo", Name: "db2"},
+			},
+			Testing: &Testing{Regression: inlineWorkflowFormat, UseCaseData: "preload", DataValidation: true, LogValidation: true},
+		}
+	},
+}
+
+//ProjectTemplates returns starter project template names
+func ProjectTemplates() []string {
+	var result = make([]string, 0, len(projectTemplates))
+	for name := range projectTemplates {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+//Scaffold generates starter project for supplied template (run request, workflow, credentials, log validation and datastore setup) into directory, it returns written files
+func (s *Service) Scaffold(template, app, directory string) ([]string, error) {
+	provider, ok := projectTemplates[template]
+	if !ok {
+		return nil, fmt.Errorf("unknown project template: %v, supported: %v", template, strings.Join(ProjectTemplates(), ","))
+	}
+	if app == "" {
+		app = "myapp"
+	}
+	response, err := s.Run(provider(app))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %v project, %v", template, err)
+	}
+	files, err := extract(response.Data, directory)
+	if err != nil {
+		return files, err
+	}
+	credentials, err := s.credentialTemplates(provider(app))
+	if err != nil {
+		return files, err
+	}
+	for name, content := range credentials {
+		filename := filepath.Join(directory, "e2e", "secret", name+".json")
+		if toolbox.FileExists(filename) {
+			continue
+		}
+		if err = os.MkdirAll(filepath.Dir(filename), 0744); err == nil {
+			err = ioutil.WriteFile(filename, []byte(content), 0644)
+		}
+		if err != nil {
+			return files, err
+		}
+		files = append(files, filename)
+	}
+	return files, nil
+}
+
+//credentialTemplates returns datastore credentials templates keyed by secret name referenced by generated run.yaml, i.e. mysql for $mysqlCredentials
+func (s *Service) credentialTemplates(request *RunRequest) (map[string]string, error) {
+	var result = make(map[string]string)
+	for _, datastore := range request.Datastore {
+		assets, err := DownloadAll(toolbox.URLPathJoin(s.baseTemplateURL, fmt.Sprintf("datastore/%v", datastore.Driver)))
+		if err != nil {
+			return nil, err
+		}
+		meta, err := s.loadDbMeta("meta.yaml", assets)
+		if err != nil {
+			return nil, err
+		}
+		if meta.Credentials == "" {
+			continue
+		}
+		secret := strings.ToLower(strings.Replace(strings.Replace(meta.Credentials, "$", "", 1), "Credentials", "", 1))
+		result[secret] = usernamePasswordTemplate
+		if meta.Id == "bigquery" || meta.Id == "firebase" || meta.Id == "firestore" {
+			result[secret] = serviceAccountTemplate
+		}
+	}
+	return result, nil
+}
+
+const serviceAccountTemplate = `{
+  "type": "service_account",
+  "project_id": "<project ID>",
+  "private_key_id": "<private key ID>",
+  "private_key": "<private key>",
+  "client_email": "<service account email>"
+}
+`
+
+const usernamePasswordTemplate = `{
+  "Username": "<username>",
+  "Password": "<password>"
+}
+`
+
+//extract writes zip archive into directory, it fails without writing anything if any file already exists
+func extract(archive []byte, directory string) ([]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	var files = make(map[string]*zip.File)
+	for _, file := range reader.File {
+		name := path.Clean(strings.TrimLeft(file.Name, "/"))
+		if strings.HasPrefix(name, "..") || file.FileInfo().IsDir() {
+			continue
+		}
+		filename := filepath.Join(directory, filepath.FromSlash(name))
+		if toolbox.FileExists(filename) {
+			return nil, fmt.Errorf("file already exists: %v", filename)
+		}
+		files[filename] = file
+	}
+	var result = make([]string, 0)
+	for filename, file := range files {
+		if err = os.MkdirAll(filepath.Dir(filename), 0744); err != nil {
+			return result, err
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return result, err
+		}
+		if err = ioutil.WriteFile(filename, content, 0644); err != nil {
+			return result, err
+		}
+		result = append(result, filename)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
@@ -0,0 +1,34 @@
+package web
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestService_Scaffold(t *testing.T) {
+	var templateURL = toolbox.URLPathJoin(url.NewResource("../").URL, "template")
+	var assetURL = toolbox.URLPathJoin(url.NewResource("../").URL, "asset")
+	srv := NewService(templateURL, assetURL)
+	for _, template := range ProjectTemplates() {
+		directory, err := ioutil.TempDir("", "scaffold")
+		if !assert.Nil(t, err) {
+			return
+		}
+		files, err := srv.Scaffold(template, "myapp", directory)
+		if assert.Nil(t, err, template) {
+			assert.True(t, len(files) > 0, template)
+			assert.True(t, toolbox.FileExists(path.Join(directory, "e2e/run.yaml")), template)
+			assert.True(t, toolbox.FileExists(path.Join(directory, "e2e/secret/mysql.json")) || toolbox.FileExists(path.Join(directory, "e2e/secret/bq.json")), template)
+			_, err = srv.Scaffold(template, "myapp", directory)
+			assert.NotNil(t, err, template)
+		}
+		_ = os.RemoveAll(directory)
+	}
+	_, err := srv.Scaffold("unknown", "myapp", os.TempDir())
+	assert.NotNil(t, err)
+}
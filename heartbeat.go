@@ -0,0 +1,138 @@
+package endly
+
+import (
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//EndlyHeartbeatThreshold env key name to customize heartbeat threshold in ms, export ENDLY_HEARTBEAT_THRESHOLD_MS=10000
+const EndlyHeartbeatThreshold = "ENDLY_HEARTBEAT_THRESHOLD_MS"
+
+//HeartbeatThreshold represents elapsed time after which running action starts emitting heartbeat events
+var HeartbeatThreshold = 30 * time.Second
+
+//HeartbeatInterval represents heartbeat events frequency
+var HeartbeatInterval = 10 * time.Second
+
+const maxHeartbeatOutputLength = 80
+
+//Heartbeat represents a running action progress tracker
+type Heartbeat struct {
+	parent  *Heartbeat
+	service string
+	action  string
+	started time.Time
+	nested  int32
+	bytes   int64
+	mux     sync.Mutex
+	output  string
+	done    chan bool
+}
+
+//Output records the last output snippet
+func (h *Heartbeat) Output(output string) {
+	if h == nil {
+		return
+	}
+	output = strings.TrimSpace(output)
+	if index := strings.LastIndex(output, "\n"); index != -1 {
+		output = strings.TrimSpace(output[index+1:])
+	}
+	if output == "" {
+		return
+	}
+	if len(output) > maxHeartbeatOutputLength {
+		output = output[len(output)-maxHeartbeatOutputLength:]
+	}
+	h.mux.Lock()
+	h.output = output
+	h.mux.Unlock()
+}
+
+//AddBytes adds transferred bytes
+func (h *Heartbeat) AddBytes(count int64) {
+	if h == nil {
+		return
+	}
+	atomic.AddInt64(&h.bytes, count)
+}
+
+//Event returns heartbeat event
+func (h *Heartbeat) Event() *msg.HeartbeatEvent {
+	h.mux.Lock()
+	output := h.output
+	h.mux.Unlock()
+	return msg.NewHeartbeatEvent(h.service, h.action, time.Since(h.started), output, atomic.LoadInt64(&h.bytes))
+}
+
+//hasNested returns true if there is nested action running, only the innermost action emits heartbeats
+func (h *Heartbeat) hasNested() bool {
+	return atomic.LoadInt32(&h.nested) > 0
+}
+
+func (h *Heartbeat) start(context *Context) {
+	if h.parent != nil {
+		atomic.AddInt32(&h.parent.nested, 1)
+	}
+	if context.Listener == nil {
+		return
+	}
+	go func() {
+		select {
+		case <-h.done:
+			return
+		case <-time.After(heartbeatThreshold()):
+		}
+		ticker := time.NewTicker(HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			if !h.hasNested() && !context.IsClosed() {
+				context.Publish(h.Event())
+			}
+			select {
+			case <-h.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (h *Heartbeat) stop() {
+	close(h.done)
+	if h.parent != nil {
+		atomic.AddInt32(&h.parent.nested, -1)
+	}
+}
+
+//isStopped returns true if tracked action has completed
+func (h *Heartbeat) isStopped() bool {
+	select {
+	case <-h.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func heartbeatThreshold() time.Duration {
+	if value := os.Getenv(EndlyHeartbeatThreshold); value != "" {
+		return time.Duration(toolbox.AsInt(value)) * time.Millisecond
+	}
+	return HeartbeatThreshold
+}
+
+func newHeartbeat(parent *Heartbeat, service, action string) *Heartbeat {
+	return &Heartbeat{
+		parent:  parent,
+		service: service,
+		action:  action,
+		started: time.Now(),
+		done:    make(chan bool),
+	}
+}
@@ -0,0 +1,84 @@
+package endly_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"sync"
+	"testing"
+	"time"
+)
+
+type slowRequest struct {
+	Output string
+}
+
+func newSlowService() *service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService("slow"),
+	}
+	result.AbstractService.Service = result
+	result.Register(&endly.Route{
+		Action: "run",
+		RequestProvider: func() interface{} {
+			return &slowRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return struct{}{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			req := request.(*slowRequest)
+			context.Heartbeat().Output("line 1\n" + req.Output)
+			context.Heartbeat().AddBytes(10)
+			time.Sleep(150 * time.Millisecond)
+			return struct{}{}, nil
+		},
+	})
+	return result
+}
+
+func TestAbstractService_Heartbeat(t *testing.T) {
+	threshold, interval := endly.HeartbeatThreshold, endly.HeartbeatInterval
+	endly.HeartbeatThreshold, endly.HeartbeatInterval = 20*time.Millisecond, 20*time.Millisecond
+	defer func() {
+		endly.HeartbeatThreshold, endly.HeartbeatInterval = threshold, interval
+	}()
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	events := msg.NewEvents()
+	context.SetListener(events.AsListener())
+	response := newSlowService().Run(context, &slowRequest{Output: "working ..."})
+	assert.Equal(t, "ok", response.Status)
+	assert.Nil(t, context.Heartbeat())
+
+	var heartbeats = make([]*msg.HeartbeatEvent, 0)
+	for _, event := range events.Events {
+		if heartbeat, ok := event.Value().(*msg.HeartbeatEvent); ok {
+			heartbeats = append(heartbeats, heartbeat)
+		}
+	}
+	if !assert.True(t, len(heartbeats) > 0) {
+		return
+	}
+	assert.Equal(t, "slow", heartbeats[0].Service)
+	assert.Equal(t, "run", heartbeats[0].Action)
+	assert.Equal(t, "working ...", heartbeats[0].Output)
+	assert.EqualValues(t, 10, heartbeats[0].Bytes)
+}
+
+func TestAbstractService_ConcurrentHeartbeat(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	service := newSlowService()
+	var waitGroup = &sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			response := service.Run(context, &slowRequest{Output: "working ..."})
+			assert.Equal(t, "ok", response.Status)
+		}()
+	}
+	waitGroup.Wait()
+	assert.Nil(t, context.Heartbeat())
+}
@@ -14,11 +14,8 @@ import (
 
 //RunWithoutLogging runs action with logging disabled for supplied context request and response. Response has to be pointer or nil
 func RunWithoutLogging(context *Context, request, result interface{}) error {
-	logging := context.Logging
-	context.SetLogging(false)
-	defer func() {
-		context.Logging = logging
-	}()
+	var logging = false
+	defer context.ScopedLogging(&logging)()
 	return Run(context, request, result)
 }
 
@@ -114,7 +111,11 @@ func (m *manager) NewContext(ctx toolbox.Context) *Context {
 		Wait:            &sync.WaitGroup{},
 		AsyncUnsafeKeys: make(map[interface{}]bool),
 		Secrets:         secret.New("", false),
+		secretValues:    NewSecretValues(),
+		stateMux:        &sync.RWMutex{},
 	}
+	result.state = NewDefaultState(result)
+	result.Background()
 	_ = result.Put(serviceManagerKey, m)
 	return result
 }
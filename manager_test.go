@@ -106,3 +106,19 @@ func Test_GetVersion(t *testing.T) {
 	version := endly.GetVersion()
 	assert.True(t, version != "")
 }
+
+func TestRegisterServiceProvider(t *testing.T) {
+	err := endly.RegisterServiceProvider(func() endly.Service {
+		var result = &testService{
+			AbstractService: endly.NewAbstractService("thirdParty"),
+		}
+		result.AbstractService.Service = result
+		return result
+	})
+	assert.Nil(t, err)
+	manager := endly.New()
+	service, err := manager.Service("thirdParty")
+	assert.Nil(t, err)
+	assert.NotNil(t, service)
+	assert.NotNil(t, endly.RegisterServiceProvider(nil))
+}
@@ -0,0 +1,218 @@
+package meta
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+//JSONSchemaVersion represents emitted JSON schema draft
+const JSONSchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+//ActionDefinition represents pipeline action definition name, it validates action payload by action field value
+const ActionDefinition = "action"
+
+//JSONSchema represents JSON schema node
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Const                string                 `json:"const,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	AllOf                []*JSONSchema          `json:"allOf,omitempty"`
+	If                   *JSONSchema            `json:"if,omitempty"`
+	Then                 *JSONSchema            `json:"then,omitempty"`
+	Definitions          map[string]*JSONSchema `json:"definitions,omitempty"`
+}
+
+//DefinitionRef returns definition reference
+func DefinitionRef(name string) string {
+	return "#/definitions/" + name
+}
+
+//schemaBuilder builds JSON schema from go types, named structs are shared as definitions
+type schemaBuilder struct {
+	definitions map[string]*JSONSchema
+	names       map[reflect.Type]string
+}
+
+func (b *schemaBuilder) definitionName(aType reflect.Type) string {
+	if name, ok := b.names[aType]; ok {
+		return name
+	}
+	var name = aType.String()
+	if _, ok := b.definitions[name]; ok { //the same type name in different packages
+		name = strings.Replace(aType.PkgPath(), "/", ".", -1) + "." + aType.Name()
+	}
+	b.names[aType] = name
+	return name
+}
+
+func (b *schemaBuilder) build(aType reflect.Type) *JSONSchema {
+	for aType.Kind() == reflect.Ptr {
+		aType = aType.Elem()
+	}
+	if aType == reflect.TypeOf(time.Time{}) {
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	}
+	switch aType.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if aType.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string"}
+		}
+		return &JSONSchema{Type: "array", Items: b.build(aType.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: b.build(aType.Elem())}
+	case reflect.Struct:
+		if aType.Name() == "" {
+			return b.buildStruct(aType)
+		}
+		name := b.definitionName(aType)
+		if _, ok := b.definitions[name]; !ok {
+			b.definitions[name] = &JSONSchema{} //placeholder for recursive types
+			b.definitions[name] = b.buildStruct(aType)
+		}
+		return &JSONSchema{Ref: DefinitionRef(name)}
+	}
+	return &JSONSchema{}
+}
+
+func (b *schemaBuilder) buildStruct(aType reflect.Type) *JSONSchema {
+	var result = &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+	b.addFields(result, aType)
+	return result
+}
+
+func (b *schemaBuilder) addFields(schema *JSONSchema, aType reflect.Type) {
+	for i := 0; i < aType.NumField(); i++ {
+		field := aType.Field(i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			b.addFields(schema, fieldType)
+			continue
+		}
+		if field.PkgPath != "" || fieldType.Kind() == reflect.Func || fieldType.Kind() == reflect.Chan {
+			continue
+		}
+		name := field.Name
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		property := b.build(field.Type)
+		if description := field.Tag.Get("description"); description != "" {
+			if property.Ref != "" { //$ref siblings are ignored by draft-07
+				property = &JSONSchema{AllOf: []*JSONSchema{property}}
+			}
+			property.Description = description
+		}
+		schema.Properties[name] = property
+		if field.Tag.Get("required") == "true" {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+//Schema returns JSON schema document with request and response definitions of the matched service actions,
+//selector is either empty or '*' for all services, serviceID or serviceID:action.
+//Each action has serviceID:action.request and serviceID:action.response definition,
+//'action' definition validates pipeline action payload by its action field value.
+func (m *Service) Schema(selector string) (*JSONSchema, error) {
+	var serviceID, actionName = selector, ""
+	if index := strings.Index(selector, ":"); index != -1 {
+		serviceID, actionName = selector[:index], selector[index+1:]
+	}
+	if serviceID == "*" {
+		serviceID = ""
+	}
+	services := endly.Services(m.Manager)
+	if serviceID != "" {
+		if _, ok := services[serviceID]; !ok {
+			return nil, fmt.Errorf("failed to lookup service: '%v'", serviceID)
+		}
+	}
+	var ids = make([]string, 0)
+	for id := range services {
+		if serviceID == "" || id == serviceID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	builder := &schemaBuilder{definitions: make(map[string]*JSONSchema), names: make(map[reflect.Type]string)}
+	var actions = make([]string, 0)
+	var conditions = make([]*JSONSchema, 0)
+	for _, id := range ids {
+		service := services[id]
+		var names = service.Actions()
+		if actionName != "" {
+			if _, err := service.Route(actionName); err != nil {
+				return nil, err
+			}
+			names = []string{actionName}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			route, err := service.Route(name)
+			if err != nil {
+				return nil, err
+			}
+			action := id + ":" + name
+			request := m.routeSchema(builder, route.RequestProvider, route.RequestInfo)
+			builder.definitions[action+".request"] = request
+			builder.definitions[action+".response"] = m.routeSchema(builder, route.ResponseProvider, route.ResponseInfo)
+			actions = append(actions, action)
+			conditions = append(conditions, &JSONSchema{
+				If:   &JSONSchema{Properties: map[string]*JSONSchema{"action": {Const: action}}},
+				Then: &JSONSchema{Ref: DefinitionRef(action + ".request")},
+			})
+		}
+	}
+	builder.definitions[ActionDefinition] = &JSONSchema{
+		Description: "pipeline action, action payload is validated with the action request schema",
+		Type:        "object",
+		Properties:  map[string]*JSONSchema{"action": {Type: "string", Enum: actions}},
+		Required:    []string{"action"},
+		AllOf:       conditions,
+	}
+	return &JSONSchema{
+		Schema:      JSONSchemaVersion,
+		Title:       "endly service actions",
+		Definitions: builder.definitions,
+	}, nil
+}
+
+func (m *Service) routeSchema(builder *schemaBuilder, provider func() interface{}, info *endly.ActionInfo) *JSONSchema {
+	var result = &JSONSchema{}
+	if provider != nil {
+		if value := provider(); value != nil {
+			result.AllOf = []*JSONSchema{builder.build(reflect.TypeOf(value))}
+		}
+	}
+	if info != nil {
+		result.Description = info.Description
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package meta
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	_ "github.com/viant/endly/system/exec"
+	_ "github.com/viant/endly/workflow"
+	"testing"
+)
+
+func TestService_Schema(t *testing.T) {
+	meta := New()
+	schema, err := meta.Schema("*")
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = json.Marshal(schema)
+	assert.Nil(t, err)
+	assert.Contains(t, schema.Definitions, "exec:run.request")
+	assert.Contains(t, schema.Definitions[ActionDefinition].Properties["action"].Enum, "workflow:run")
+
+	schema, err = meta.Schema("workflow:print")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, JSONSchemaVersion, schema.Schema)
+	assert.Equal(t, []string{"workflow:print"}, schema.Definitions[ActionDefinition].Properties["action"].Enum)
+	request := schema.Definitions["workflow:print.request"]
+	if assert.NotNil(t, request) && assert.Equal(t, 1, len(request.AllOf)) {
+		definition := schema.Definitions[request.AllOf[0].Ref[len(DefinitionRef("")):]]
+		if assert.NotNil(t, definition) {
+			assert.Equal(t, "object", definition.Type)
+			assert.Equal(t, "string", definition.Properties["Message"].Type)
+		}
+	}
+	assert.NotNil(t, schema.Definitions["workflow:print.response"])
+
+	_, err = meta.Schema("abc")
+	assert.NotNil(t, err)
+	_, err = meta.Schema("workflow:abc")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,70 @@
+package endly
+
+import "sync"
+
+//RouteHandler represents service action route handler
+type RouteHandler func(context *Context, request interface{}) (interface{}, error)
+
+//Middleware represents service action route interceptor, it wraps next handler to run pre/post logic, mutate request or response
+type Middleware func(service Service, route *Route, next RouteHandler) RouteHandler
+
+//Interceptor represents pre/post hooks middleware
+type Interceptor struct {
+	//Before runs before route handler, returned error stops action execution
+	Before func(context *Context, service Service, route *Route, request interface{}) error
+	//After runs after route handler, it can replace response or error
+	After func(context *Context, service Service, route *Route, request, response interface{}, err error) (interface{}, error)
+}
+
+//Middleware returns interceptor middleware
+func (i *Interceptor) Middleware() Middleware {
+	return func(service Service, route *Route, next RouteHandler) RouteHandler {
+		return func(context *Context, request interface{}) (interface{}, error) {
+			if i.Before != nil {
+				if err := i.Before(context, service, route, request); err != nil {
+					return nil, err
+				}
+			}
+			response, err := next(context, request)
+			if i.After != nil {
+				return i.After(context, service, route, request, response, err)
+			}
+			return response, err
+		}
+	}
+}
+
+var middlewares = make([]Middleware, 0)
+var middlewaresMux = &sync.RWMutex{}
+
+//RegisterMiddleware registers middleware applied to all services action routes
+func RegisterMiddleware(middleware ...Middleware) {
+	middlewaresMux.Lock()
+	defer middlewaresMux.Unlock()
+	middlewares = append(middlewares, middleware...)
+}
+
+//ResetMiddlewares removes all global middlewares
+func ResetMiddlewares() {
+	middlewaresMux.Lock()
+	defer middlewaresMux.Unlock()
+	middlewares = make([]Middleware, 0)
+}
+
+//chain wraps handler with supplied middlewares, the first middleware is the outermost one
+func chain(service Service, route *Route, handler RouteHandler, chain ...[]Middleware) RouteHandler {
+	var all = make([]Middleware, 0)
+	for _, items := range chain {
+		all = append(all, items...)
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		handler = all[i](service, route, handler)
+	}
+	return handler
+}
+
+func globalMiddlewares() []Middleware {
+	middlewaresMux.RLock()
+	defer middlewaresMux.RUnlock()
+	return middlewares
+}
@@ -0,0 +1,63 @@
+package endly_test
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"testing"
+)
+
+func TestAbstractService_Use(t *testing.T) {
+	manager := endly.New()
+	var trace = make([]string, 0)
+	srv := newService()
+	srv.Use(func(service endly.Service, route *endly.Route, next endly.RouteHandler) endly.RouteHandler {
+		return func(context *endly.Context, request interface{}) (interface{}, error) {
+			trace = append(trace, "outer:"+service.ID()+"."+route.Action)
+			return next(context, request)
+		}
+	}, (&endly.Interceptor{
+		Before: func(context *endly.Context, service endly.Service, route *endly.Route, request interface{}) error {
+			req := request.(*TestRequest)
+			if req.Data == "deny" {
+				return fmt.Errorf("access denied")
+			}
+			req.Data += "!"
+			trace = append(trace, "before")
+			return nil
+		},
+		After: func(context *endly.Context, service endly.Service, route *endly.Route, request, response interface{}, err error) (interface{}, error) {
+			trace = append(trace, "after")
+			return response, err
+		},
+	}).Middleware())
+
+	context := manager.NewContext(nil)
+	response := srv.Run(context, &TestRequest{Data: "test"})
+	assert.Equal(t, "ok", response.Status)
+	assert.Equal(t, []string{"outer:test.test", "before", "after"}, trace)
+	testResponse, ok := response.Response.(*TestResponse)
+	if assert.True(t, ok) {
+		assert.Equal(t, "test!", testResponse.Request.(*TestRequest).Data)
+	}
+
+	response = srv.Run(context, &TestRequest{Data: "deny"})
+	assert.Equal(t, "error", response.Status)
+	assert.Contains(t, response.Error, "access denied")
+}
+
+func TestRegisterMiddleware(t *testing.T) {
+	defer endly.ResetMiddlewares()
+	var count = 0
+	endly.RegisterMiddleware(func(service endly.Service, route *endly.Route, next endly.RouteHandler) endly.RouteHandler {
+		return func(context *endly.Context, request interface{}) (interface{}, error) {
+			count++
+			return next(context, request)
+		}
+	})
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	response := newService().Run(context, &TestRequest{Data: "test"})
+	assert.Equal(t, "ok", response.Status)
+	assert.Equal(t, 1, count)
+}
@@ -8,5 +8,6 @@ type AbstractNode struct {
 	Post        Variables `description:"post execution state update instruction"`
 	When        string    `description:"run criteria"`
 	SleepTimeMs int       //optional Sleep time
+	TimeoutMs   int       `description:"optional timeout, when exceeded node run is canceled and fails with timeout error"`
 	Logging     *bool     `description:"optional flag to disable logging, enabled by default"`
 }
@@ -6,8 +6,16 @@ type Action struct {
 	*ServiceRequest
 	*MetaTag
 	*Repeater
-	Async bool   `description:"flag to run action async"`
-	Skip  string `description:"criteria to skip current TagID"`
+	Async     bool         `description:"flag to run action async"`
+	Skip      string       `description:"criteria to skip current TagID"`
+	ForEach   string       `description:"state collection key/expression or count, action runs for each item with $index and $item state keys"`
+	Cache     *ActionCache `description:"opt-in response cache for idempotent actions, i.e. cache: true"`
+	DependsOn []string     `description:"TagIDs or names of actions re-run together with this action when only failed actions are re-run"`
+}
+
+//ActionCache represents action response cache policy, response is cached by expanded request hash across runs
+type ActionCache struct {
+	TTLMs int `description:"cached response time to live, unlimited if empty"`
 }
 
 //NewActivity returns pipeline activity
@@ -65,6 +73,8 @@ func (a *Action) Clone() *Action {
 		Repeater:       &repeater,
 		Async:          a.Async,
 		Skip:           a.Skip,
+		ForEach:        a.ForEach,
+		Cache:          a.Cache,
 	}
 }
 
@@ -50,8 +50,9 @@ func Evaluate(context *endly.Context, state data.Map, expression, eventType stri
 	return result, err
 }
 
-//Assert validates expected against actual
+//Assert validates expected against actual, time window field references are resolved with actual values
 func Assert(context *endly.Context, root string, expected, actual interface{}) (*assertly.Validation, error) {
+	expected = resolveTimeWindowFields(expected, actual)
 	ctx := assertly.NewDefaultContext()
 	ctx.Context = context.Context
 	var rootPath = assertly.NewDataPath(root)
@@ -0,0 +1,240 @@
+package criteria
+
+import (
+	"fmt"
+	"github.com/viant/assertly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//TimeWindowDirective represents time window validation directive name, used as <ds:time_window[base, toleranceSec, dateFormat, timezone]>
+const TimeWindowDirective = "time_window"
+
+//timeWindowFieldPrefix represents time window base reference to sibling actual field, i.e. <ds:time_window["@createdAt", 5]>
+const timeWindowFieldPrefix = "@"
+
+var timeWindowMacro = "<ds:" + TimeWindowDirective + "["
+
+//fallbackTimeLayouts represents layouts tried when no date format was specified
+var fallbackTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.000", "2006-01-02 15:04:05", "2006-01-02T15:04:05", time.RFC1123, time.RFC1123Z}
+
+//timeWindowPredicate represents predicate checking that time is within tolerance of base time
+type timeWindowPredicate struct {
+	base      time.Time
+	tolerance time.Duration
+	layout    string
+	location  *time.Location
+}
+
+//Apply returns true if value is within tolerance of base time
+func (p *timeWindowPredicate) Apply(value interface{}) bool {
+	actual, err := parseWindowTime(value, p.layout, p.location)
+	if err != nil {
+		return false
+	}
+	diff := actual.Sub(p.base)
+	if diff < 0 {
+		diff *= -1
+	}
+	return diff <= p.tolerance
+}
+
+//String returns predicate description used in failure report
+func (p *timeWindowPredicate) String() string {
+	return fmt.Sprintf("time within %v of %v", p.tolerance, p.base.Format(time.RFC3339Nano))
+}
+
+//timeWindowProvider represents time window predicate provider
+type timeWindowProvider struct{}
+
+//Get returns time window predicate, it takes base time (now, time literal i.e. 5 minutes ago, or timestamp), tolerance in seconds or duration (i.e. 1.5, "2m"),
+//optional date format (java or Go layout) and optional timezone for timestamps without zone
+func (p *timeWindowProvider) Get(context toolbox.Context, arguments ...interface{}) (interface{}, error) {
+	if len(arguments) < 2 {
+		return nil, fmt.Errorf("expected at least 2 arguments <ds:%v[base, toleranceSec, dateFormat, timezone]>, but had %v", TimeWindowDirective, len(arguments))
+	}
+	var result = &timeWindowPredicate{location: time.UTC}
+	if len(arguments) > 2 {
+		result.layout = asTimeLayout(toolbox.AsString(arguments[2]))
+	}
+	if len(arguments) > 3 && toolbox.AsString(arguments[3]) != "" {
+		location, err := time.LoadLocation(toolbox.AsString(arguments[3]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v timezone: %v, %v", TimeWindowDirective, arguments[3], err)
+		}
+		result.location = location
+	}
+	tolerance, err := asTolerance(arguments[1])
+	if err != nil {
+		return nil, err
+	}
+	result.tolerance = tolerance
+	if text, ok := arguments[0].(string); ok && strings.HasPrefix(text, timeWindowFieldPrefix) {
+		return nil, fmt.Errorf("unresolved %v field reference: %v", TimeWindowDirective, text)
+	}
+	base, err := asBaseTime(arguments[0], result.layout, result.location)
+	if err != nil {
+		return nil, err
+	}
+	result.base = *base
+	return result, nil
+}
+
+//asTimeLayout returns Go layout for java style (i.e. yyyy-MM-dd HH:mm:ss) or Go date format
+func asTimeLayout(dateFormat string) string {
+	if dateFormat == "" || strings.Contains(dateFormat, "2006") {
+		return dateFormat
+	}
+	return toolbox.DateFormatToLayout(dateFormat)
+}
+
+//asTolerance returns tolerance for seconds or duration literal
+func asTolerance(value interface{}) (time.Duration, error) {
+	if text, ok := value.(string); ok {
+		if duration, err := time.ParseDuration(text); err == nil {
+			return duration, nil
+		}
+	}
+	seconds, err := toolbox.ToFloat(value)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid %v tolerance: %v", TimeWindowDirective, value)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+//asBaseTime returns base time for now, time literal or timestamp
+func asBaseTime(value interface{}, layout string, location *time.Location) (*time.Time, error) {
+	if text, ok := value.(string); ok && !isNumericText(text) {
+		if result, err := toolbox.TimeAt(text); err == nil {
+			return result, nil
+		}
+	}
+	result, err := parseWindowTime(value, layout, location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v base time: %v, %v", TimeWindowDirective, value, err)
+	}
+	return result, nil
+}
+
+//parseWindowTime parses time with layout, then epoch (sec, ms, ns) and common layouts are tried
+func parseWindowTime(value interface{}, layout string, location *time.Location) (*time.Time, error) {
+	text, ok := value.(string)
+	if !ok {
+		return toolbox.ToTime(value, layout)
+	}
+	if layout != "" {
+		if result, err := time.ParseInLocation(layout, text, location); err == nil {
+			return &result, nil
+		}
+	}
+	if isNumericText(text) {
+		return toolbox.ToTime(text, "")
+	}
+	for _, candidate := range fallbackTimeLayouts {
+		if result, err := time.ParseInLocation(candidate, text, location); err == nil {
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to parse time: %v", text)
+}
+
+//resolveTimeWindowFields returns expected with time window field references replaced by corresponding actual sibling values
+func resolveTimeWindowFields(expected, actual interface{}) interface{} {
+	switch expectedValue := expected.(type) {
+	case string:
+		return expectedValue
+	case map[string]interface{}:
+		if !hasTimeWindowField(expectedValue) {
+			return expected
+		}
+		actualMap, ok := asDataMap(actual)
+		var result = make(map[string]interface{})
+		for key, value := range expectedValue {
+			var actualValue interface{}
+			if ok {
+				actualValue = actualMap[key]
+			}
+			if text, isText := value.(string); isText && ok {
+				value = resolveTimeWindowField(text, actualMap)
+			} else {
+				value = resolveTimeWindowFields(value, actualValue)
+			}
+			result[key] = value
+		}
+		return result
+	case []interface{}:
+		if !hasTimeWindowField(expectedValue) {
+			return expected
+		}
+		actualSlice, _ := actual.([]interface{})
+		var result = make([]interface{}, len(expectedValue))
+		for i, value := range expectedValue {
+			var actualValue interface{}
+			if i < len(actualSlice) {
+				actualValue = actualSlice[i]
+			}
+			result[i] = resolveTimeWindowFields(value, actualValue)
+		}
+		return result
+	}
+	return expected
+}
+
+//resolveTimeWindowField replaces "@field" base reference with actual field value
+func resolveTimeWindowField(expected string, actual data.Map) string {
+	index := strings.Index(expected, timeWindowMacro)
+	if index == -1 {
+		return expected
+	}
+	args := expected[index+len(timeWindowMacro):]
+	if !strings.HasPrefix(strings.TrimSpace(args), `"`+timeWindowFieldPrefix) {
+		return expected
+	}
+	begin := strings.Index(args, `"`)
+	end := strings.Index(args[begin+1:], `"`)
+	if end == -1 {
+		return expected
+	}
+	field := args[begin+2 : begin+1+end]
+	value, ok := actual.GetValue(field)
+	if !ok {
+		return expected
+	}
+	var base = toolbox.AsString(value)
+	switch actualValue := value.(type) {
+	case time.Time:
+		base = actualValue.Format(time.RFC3339Nano)
+	case *time.Time:
+		base = actualValue.Format(time.RFC3339Nano)
+	default:
+		if toolbox.IsNumber(value) {
+			base = fmt.Sprintf("%v", int64(math.Round(toolbox.AsFloat(value))))
+		}
+	}
+	return expected[:index+len(timeWindowMacro)] + args[:begin] + fmt.Sprintf("%q", base) + args[begin+2+end:]
+}
+
+//hasTimeWindowField returns true if expected contains time window field reference
+func hasTimeWindowField(expected interface{}) bool {
+	return strings.Contains(fmt.Sprintf("%v", expected), timeWindowMacro+`"`+timeWindowFieldPrefix)
+}
+
+func isNumericText(text string) bool {
+	_, err := strconv.ParseFloat(text, 64)
+	return err == nil
+}
+
+func asDataMap(value interface{}) (data.Map, bool) {
+	if value == nil || !toolbox.IsMap(value) {
+		return nil, false
+	}
+	return data.Map(toolbox.AsMap(value)), true
+}
+
+func init() {
+	assertly.ValueProviderRegistry.Register(TimeWindowDirective, &timeWindowProvider{})
+}
@@ -0,0 +1,93 @@
+package criteria
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"testing"
+	"time"
+)
+
+func TestAssert_TimeWindow(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	now := time.Now().UTC()
+
+	var useCases = []struct {
+		Description string
+		Expected    interface{}
+		Actual      interface{}
+		Passed      bool
+	}{
+		{
+			Description: "RFC3339 within now",
+			Expected:    map[string]interface{}{"ts": `<ds:time_window["now", 5]>`},
+			Actual:      map[string]interface{}{"ts": now.Add(-2 * time.Second).Format(time.RFC3339Nano)},
+			Passed:      true,
+		},
+		{
+			Description: "RFC3339 outside now",
+			Expected:    map[string]interface{}{"ts": `<ds:time_window["now", 5]>`},
+			Actual:      map[string]interface{}{"ts": now.Add(-time.Minute).Format(time.RFC3339)},
+		},
+		{
+			Description: "epoch ms with duration tolerance",
+			Expected:    map[string]interface{}{"ts": `<ds:time_window["now", "1m"]>`},
+			Actual:      map[string]interface{}{"ts": now.Add(-30*time.Second).UnixNano() / int64(time.Millisecond)},
+			Passed:      true,
+		},
+		{
+			Description: "java date format with timezone",
+			Expected:    map[string]interface{}{"ts": `<ds:time_window["now", 5, "yyyy-MM-dd HH:mm:ss", "America/New_York"]>`},
+			Actual:      map[string]interface{}{"ts": now.In(mustLoadLocation("America/New_York")).Format("2006-01-02 15:04:05")},
+			Passed:      true,
+		},
+		{
+			Description: "time literal base",
+			Expected:    map[string]interface{}{"ts": `<ds:time_window["1 hour ago", 10]>`},
+			Actual:      map[string]interface{}{"ts": now.Add(-time.Hour).Format(time.RFC3339)},
+			Passed:      true,
+		},
+		{
+			Description: "sibling field reference",
+			Expected:    map[string]interface{}{"updated": `<ds:time_window["@created", 3]>`},
+			Actual:      map[string]interface{}{"created": "2021-03-01 10:00:00", "updated": "2021-03-01 10:00:02"},
+			Passed:      true,
+		},
+		{
+			Description: "sibling field reference outside window",
+			Expected:    []interface{}{map[string]interface{}{"updated": `<ds:time_window["@created", 3]>`}},
+			Actual:      []interface{}{map[string]interface{}{"created": "2021-03-01 10:00:00", "updated": "2021-03-01 10:01:00"}},
+		},
+		{
+			Description: "nested sibling field reference",
+			Expected:    map[string]interface{}{"audit": map[string]interface{}{"updated": `<ds:time_window["@meta.created", 1]>`}},
+			Actual:      map[string]interface{}{"audit": map[string]interface{}{"meta": map[string]interface{}{"created": 1614592800}, "updated": "2021-03-01T10:00:00Z"}},
+			Passed:      true,
+		},
+		{
+			Description: "invalid time",
+			Expected:    map[string]interface{}{"ts": `<ds:time_window["now", 5]>`},
+			Actual:      map[string]interface{}{"ts": "abc"},
+		},
+	}
+
+	for _, useCase := range useCases {
+		validation, err := Assert(context, "/", useCase.Expected, useCase.Actual)
+		if !assert.Nil(t, err, useCase.Description) {
+			continue
+		}
+		assert.Equal(t, useCase.Passed, !validation.HasFailure(), useCase.Description+" "+validation.Report())
+	}
+
+	_, err := Assert(context, "/", map[string]interface{}{"ts": `<ds:time_window["now"]>`}, map[string]interface{}{"ts": "abc"})
+	assert.NotNil(t, err)
+}
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
@@ -0,0 +1,240 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	//ExtractFormatRegExpr represents regular expression extraction (default)
+	ExtractFormatRegExpr = "regexp"
+	//ExtractFormatJSON represents JSON output extraction
+	ExtractFormatJSON = "json"
+	//ExtractFormatYAML represents YAML output extraction
+	ExtractFormatYAML = "yaml"
+	//ExtractFormatTable represents whitespace or delimiter separated table output extraction
+	ExtractFormatTable = "table"
+)
+
+func (e *Extract) isStructured() bool {
+	format := strings.ToLower(e.Format)
+	return format != "" && format != ExtractFormatRegExpr
+}
+
+//matchedValue returns extracted value: named groups map, the first capture group or the entire match
+func (e *Extract) matchedValue(compiledExpression *regexp.Regexp, matched []string) (interface{}, error) {
+	var groups = make(map[string]interface{})
+	for i, name := range compiledExpression.SubexpNames() {
+		if name == "" || i >= len(matched) {
+			continue
+		}
+		value, err := convertExtracted(name, matched[i], e.Types[name])
+		if err != nil {
+			return nil, err
+		}
+		groups[name] = value
+	}
+	if len(groups) > 0 {
+		return groups, nil
+	}
+	// if there is a capture group use that as the extracted value, otherwise use the entire match
+	matchIndex := 0
+	if len(matched) > 1 {
+		matchIndex = 1
+	}
+	return convertExtracted(e.Key, matched[matchIndex], e.Type)
+}
+
+//extractStructured parses json, yaml or table output
+func (e *Extract) extractStructured(context *endly.Context, extracted map[string]interface{}, output string) error {
+	var fragment = output
+	if e.RegExpr != "" {
+		compiledExpression, err := regexp.Compile(e.RegExpr)
+		if err != nil {
+			return fmt.Errorf("failed to extract data - invlid regexpr: %v,  %v", e.RegExpr, err)
+		}
+		matched := compiledExpression.FindStringSubmatch(output)
+		if len(matched) == 0 {
+			if e.Required {
+				return fmt.Errorf("failed to extract required data - no match found for regexpr: %v,  %v", e.RegExpr, output)
+			}
+			return nil
+		}
+		fragment = matched[0]
+		if len(matched) > 1 {
+			fragment = matched[1]
+		}
+	}
+	var value interface{}
+	var err error
+	switch strings.ToLower(e.Format) {
+	case ExtractFormatJSON:
+		value, err = parseJSONOutput(fragment)
+	case ExtractFormatYAML:
+		value, err = parseYAMLOutput(fragment)
+	case ExtractFormatTable:
+		value, err = e.parseTable(fragment)
+	default:
+		return fmt.Errorf("unsupported extract format: %v, supported: %v, %v, %v, %v", e.Format, ExtractFormatRegExpr, ExtractFormatJSON, ExtractFormatYAML, ExtractFormatTable)
+	}
+	if err != nil {
+		if e.Required {
+			return fmt.Errorf("failed to extract required %v data: %v, %v", e.Format, err, output)
+		}
+		return nil
+	}
+	if e.Path != "" {
+		var has bool
+		if value, has = pathValue(value, e.Path); !has {
+			if e.Required {
+				return fmt.Errorf("failed to extract required data - path %v not found in %v output", e.Path, e.Format)
+			}
+			return nil
+		}
+	}
+	if e.Type != "" && !toolbox.IsMap(value) && !toolbox.IsSlice(value) {
+		if value, err = convertExtracted(e.Key, toolbox.AsString(value), e.Type); err != nil {
+			return err
+		}
+	}
+	setExtracted(context, e.Key, value, extracted)
+	return nil
+}
+
+//parseTable parses table output into rows, when columns are supplied all lines are treated as rows, otherwise the first line is a header
+func (e *Extract) parseTable(output string) (interface{}, error) {
+	var lines = make([]string, 0)
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	columns := e.Columns
+	if len(columns) == 0 {
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("table header was empty")
+		}
+		columns = e.splitRow(lines[0], -1)
+		lines = lines[1:]
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table columns were empty")
+	}
+	var rows = make([]interface{}, 0, len(lines))
+	for _, line := range lines {
+		fields := e.splitRow(line, len(columns))
+		var row = make(map[string]interface{})
+		for i, column := range columns {
+			var field string
+			if i < len(fields) {
+				field = fields[i]
+			}
+			value, err := convertExtracted(column, field, e.Types[column])
+			if err != nil {
+				return nil, err
+			}
+			row[column] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+//splitRow splits row into fields, the last column holds the remaining fields
+func (e *Extract) splitRow(line string, columns int) []string {
+	if e.Delimiter != "" {
+		fields := strings.SplitN(line, e.Delimiter, columns)
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		return fields
+	}
+	if columns <= 0 {
+		return strings.Fields(line)
+	}
+	var fields = make([]string, 0, columns)
+	rest := strings.TrimSpace(line)
+	for len(fields) < columns-1 {
+		index := strings.IndexAny(rest, " \t")
+		if index == -1 {
+			break
+		}
+		fields = append(fields, rest[:index])
+		rest = strings.TrimSpace(rest[index:])
+	}
+	if rest != "" {
+		fields = append(fields, rest)
+	}
+	return fields
+}
+
+func parseJSONOutput(output string) (interface{}, error) {
+	var result interface{}
+	text := strings.TrimSpace(output)
+	err := json.Unmarshal([]byte(text), &result)
+	if err == nil {
+		return result, nil
+	}
+	//command output may have text around JSON document
+	start := strings.IndexAny(text, "{[")
+	end := strings.LastIndexAny(text, "}]")
+	if start == -1 || end <= start {
+		return nil, err
+	}
+	if err = json.Unmarshal([]byte(text[start:end+1]), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func parseYAMLOutput(output string) (interface{}, error) {
+	var result interface{}
+	if err := yaml.Unmarshal([]byte(output), &result); err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("yaml output was empty")
+	}
+	return toolbox.NormalizeKVPairs(result)
+}
+
+func pathValue(value interface{}, path string) (interface{}, bool) {
+	if !toolbox.IsMap(value) {
+		return nil, false
+	}
+	aMap, err := toolbox.ToMap(value)
+	if err != nil {
+		return nil, false
+	}
+	var state = data.Map(aMap)
+	return state.GetValue(path)
+}
+
+//convertExtracted converts extracted text to supplied type
+func convertExtracted(name, value, typeName string) (interface{}, error) {
+	var result interface{}
+	var err error
+	switch strings.ToLower(typeName) {
+	case "", "string":
+		return value, nil
+	case "int":
+		result, err = toolbox.ToInt(strings.TrimSpace(value))
+	case "float":
+		result, err = toolbox.ToFloat(strings.TrimSpace(value))
+	case "bool":
+		result, err = toolbox.ToBoolean(strings.TrimSpace(value))
+	default:
+		return nil, fmt.Errorf("unsupported %v extract type: %v, supported: string, int, float, bool", name, typeName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %v extracted value '%v' to %v: %v", name, value, typeName, err)
+	}
+	return result, nil
+}
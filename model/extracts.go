@@ -34,12 +34,24 @@ func (d *Extracts) Extract(context *endly.Context, extracted map[string]interfac
 	multiLines := strings.Join(inputs, "\n")
 
 	for _, extract := range *d {
+		if extract.isStructured() {
+			if err := extract.extractStructured(context, extracted, cleanMultiLines); err != nil {
+				return err
+			}
+			continue
+		}
 		compiledExpression, err := regexp.Compile(extract.RegExpr)
 		if err != nil {
 			return fmt.Errorf("failed to extract data - invlid regexpr: %v,  %v", extract.RegExpr, err)
 		}
-		if !matchExpression(compiledExpression, multiLines, extract, context, extracted) {
-			if matchExpression(compiledExpression, cleanMultiLines, extract, context, extracted) {
+		hasMatch, err := matchExpression(compiledExpression, multiLines, extract, context, extracted)
+		if err != nil {
+			return err
+		}
+		if !hasMatch {
+			if hasMatch, err = matchExpression(compiledExpression, cleanMultiLines, extract, context, extracted); err != nil {
+				return err
+			} else if hasMatch {
 				continue
 			}
 		}
@@ -48,14 +60,8 @@ func (d *Extracts) Extract(context *endly.Context, extracted map[string]interfac
 			if len(line) == 0 {
 				continue
 			}
-			if matchExpression(compiledExpression, line, extract, context, extracted) {
-				matched = true
-				continue
-			}
-			cleanedLine := vtclean.Clean(line, false)
-			if matchExpression(compiledExpression, cleanedLine, extract, context, extracted) {
-				matched = true
-				continue
+			if matched, err = matchLine(compiledExpression, line, extract, context, extracted, matched); err != nil {
+				return err
 			}
 		}
 		if extract.Required && !matched {
@@ -85,10 +91,16 @@ func NewExtracts() Extracts {
 
 //Extract represents a data extraction
 type Extract struct {
-	RegExpr  string `description:"regular expression with oval bracket to extract match pattern"`            //regular expression
-	Key      string `description:"state key to store a match"`                                               //state key to store a match
-	Reset    bool   `description:"reset the key in the context before evaluating this data extraction rule"` //reset the key in the context before evaluating this data extraction rule
-	Required bool   `description:"require that at least one pattern match is returned"`                      //require that at least one pattern match is returned
+	RegExpr   string            `description:"regular expression with oval bracket to extract match pattern, named groups are extracted as map"` //regular expression
+	Key       string            `description:"state key to store a match"`                                                                       //state key to store a match
+	Reset     bool              `description:"reset the key in the context before evaluating this data extraction rule"`                         //reset the key in the context before evaluating this data extraction rule
+	Required  bool              `description:"require that at least one pattern match is returned"`                                              //require that at least one pattern match is returned
+	Format    string            `description:"output format: regexp (default), json, yaml or table"`
+	Type      string            `description:"extracted value type: string (default), int, float or bool"`
+	Types     map[string]string `description:"named group or table column types, i.e. {\"count\":\"int\"}"`
+	Path      string            `description:"path of value to extract from parsed json or yaml output, i.e. status.replicas"`
+	Columns   []string          `description:"table column names, the first output line is used as header if empty"`
+	Delimiter string            `description:"table column delimiter, whitespace by default"`
 }
 
 //NewExtract creates a new data extraction
@@ -117,20 +129,36 @@ func NewExtractEvent(output string, structuredOutput, extracted interface{}) *Ex
 	}
 }
 
-func matchExpression(compiledExpression *regexp.Regexp, line string, extract *Extract, context *endly.Context, extracted map[string]interface{}) bool {
-	if compiledExpression.MatchString(line) {
-		matched := compiledExpression.FindStringSubmatch(line)
-		// if there is a capture group use that as the extracted value, otherwise use the entire match
-		matchIndex := 0
-		if len(matched) > 1 {
-			matchIndex = 1
-		}
-		if extract.Key != "" {
-			var state = context.State()
-			var keyFragments = strings.Split(extract.Key, ".")
+func matchLine(compiledExpression *regexp.Regexp, line string, extract *Extract, context *endly.Context, extracted map[string]interface{}, matched bool) (bool, error) {
+	hasMatch, err := matchExpression(compiledExpression, line, extract, context, extracted)
+	if err != nil || hasMatch {
+		return matched || hasMatch, err
+	}
+	hasMatch, err = matchExpression(compiledExpression, vtclean.Clean(line, false), extract, context, extracted)
+	return matched || hasMatch, err
+}
+
+func matchExpression(compiledExpression *regexp.Regexp, line string, extract *Extract, context *endly.Context, extracted map[string]interface{}) (bool, error) {
+	if !compiledExpression.MatchString(line) {
+		return false, nil
+	}
+	matched := compiledExpression.FindStringSubmatch(line)
+	value, err := extract.matchedValue(compiledExpression, matched)
+	if err != nil {
+		return false, err
+	}
+	setExtracted(context, extract.Key, value, extracted)
+	return true, nil
+}
+
+//setExtracted places extracted value to the context state and extracted map
+func setExtracted(context *endly.Context, key string, value interface{}, extracted map[string]interface{}) {
+	if key != "" {
+		_ = context.SafeState().Update(func(state data.Map) error {
+			var keyFragments = strings.Split(key, ".")
 			for i, keyFragment := range keyFragments {
 				if i+1 == len(keyFragments) {
-					state.Put(extract.Key, matched[matchIndex])
+					state.Put(key, value)
 					continue
 				}
 				if !state.Has(keyFragment) {
@@ -139,9 +167,8 @@ func matchExpression(compiledExpression *regexp.Regexp, line string, extract *Ex
 				state = state.GetMap(keyFragment)
 
 			}
-		}
-		extracted[extract.Key] = matched[matchIndex]
-		return true
+			return nil
+		})
 	}
-	return false
+	extracted[key] = value
 }
@@ -189,6 +189,133 @@ versionId: '2'`, "\n"),
 				"status": "running",
 			},
 		},
+		{
+			desription: "named groups with types",
+			extracts: []*Extract{
+				{
+					Key:     "stats",
+					RegExpr: `(?P<passed>\d+) passed, (?P<failed>\d+) failed in (?P<elapsed>[\d.]+)s`,
+					Types:   map[string]string{"passed": "int", "failed": "int", "elapsed": "float"},
+				},
+			},
+			inputs: []string{"ok", "12 passed, 1 failed in 3.5s"},
+			expected: map[string]interface{}{
+				"stats": map[string]interface{}{"passed": 12, "failed": 1, "elapsed": 3.5},
+			},
+		},
+		{
+			desription: "typed capture group",
+			extracts: []*Extract{
+				{
+					Key:     "count",
+					RegExpr: `count: (\d+)`,
+					Type:    "int",
+				},
+			},
+			inputs: []string{"count: 42"},
+			expected: map[string]interface{}{
+				"count": 42,
+			},
+		},
+		{
+			desription: "invalid typed capture group",
+			extracts: []*Extract{
+				{
+					Key:     "count",
+					RegExpr: `count: (\w+)`,
+					Type:    "int",
+				},
+			},
+			inputs:   []string{"count: abc"},
+			hasError: true,
+		},
+		{
+			desription: "json output with path",
+			extracts: []*Extract{
+				{
+					Key:    "replicas",
+					Format: "json",
+					Path:   "status.replicas",
+					Type:   "int",
+				},
+				{
+					Key:    "deployment",
+					Format: "json",
+				},
+			},
+			inputs: []string{"Fetching deployment...", `{"name":"app", "status":{"replicas":3}}`},
+			expected: map[string]interface{}{
+				"replicas":   3,
+				"deployment": map[string]interface{}{"name": "app", "status": map[string]interface{}{"replicas": float64(3)}},
+			},
+		},
+		{
+			desription: "yaml output with fragment expression",
+			extracts: []*Extract{
+				{
+					Key:     "version",
+					Format:  "yaml",
+					RegExpr: `(?s)---\n(.+)`,
+					Path:    "app.version",
+				},
+			},
+			inputs: strings.Split("building...\n---\napp:\n  version: 1.2.0\n  ready: true", "\n"),
+			expected: map[string]interface{}{
+				"version": "1.2.0",
+			},
+		},
+		{
+			desription: "required invalid json output",
+			extracts: []*Extract{
+				{
+					Key:      "data",
+					Format:   "json",
+					Required: true,
+				},
+			},
+			inputs:   []string{"command not found"},
+			hasError: true,
+		},
+		{
+			desription: "whitespace table with header",
+			extracts: []*Extract{
+				{
+					Key:    "pods",
+					Format: "table",
+					Types:  map[string]string{"RESTARTS": "int"},
+				},
+			},
+			inputs: []string{
+				"NAME    STATUS    RESTARTS   AGE",
+				"app-1   Running   0          2 days",
+				"app-2   Pending   3          5m",
+			},
+			expected: map[string]interface{}{
+				"pods": []interface{}{
+					map[string]interface{}{"NAME": "app-1", "STATUS": "Running", "RESTARTS": 0, "AGE": "2 days"},
+					map[string]interface{}{"NAME": "app-2", "STATUS": "Pending", "RESTARTS": 3, "AGE": "5m"},
+				},
+			},
+		},
+		{
+			desription: "delimiter table with columns",
+			extracts: []*Extract{
+				{
+					Key:       "users",
+					Format:    "table",
+					Columns:   []string{"name", "uid", "shell"},
+					Delimiter: ":",
+					Types:     map[string]string{"uid": "int"},
+				},
+			},
+			inputs: []string{"root:0:/bin/bash", "app:1000:/bin/sh"},
+			expected: map[string]interface{}{
+				"users": []interface{}{
+					map[string]interface{}{"name": "root", "uid": 0, "shell": "/bin/bash"},
+					map[string]interface{}{"name": "app", "uid": 1000, "shell": "/bin/sh"},
+				},
+			},
+		},
 	}
 
 	for _, useCase := range useCases {
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"github.com/viant/endly/util"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
@@ -19,6 +20,10 @@ const (
 
 	requestKey     = "request"
 	failKey        = "fail"
+	groupKey       = "group"
+	onSuccessKey   = "onsuccess"
+	onErrorKey     = "onerror"
+	switchKey      = "switch"
 	parentKey      = "parent"
 	actionKey      = "action"
 	whenKey        = "when"
@@ -26,6 +31,7 @@ const (
 	workflowKey    = "workflow"
 	skipKey        = "skip"
 	loggingKey     = "logging"
+	cacheKey       = "cache"
 	descriptionKey = "description"
 	commentsKey    = "comments"
 	initKey        = "init"
@@ -53,11 +59,15 @@ type InlineWorkflow struct {
 	Data       map[string]interface{}
 	Pipeline   []*MapEntry
 	State      data.Map
+	Contract   StateContract
+	Inputs     StateContract
+	Outputs    StateContract
+	Endly      string    //minimum required endly version
 	workflow   *Workflow //inline workflow from pipeline
 }
 
 func (p InlineWorkflow) updateReservedAttributes(aMap map[string]interface{}) {
-	for _, key := range []string{actionKey, workflowKey, skipKey, whenKey, postKey, initKey, commentsKey, descriptionKey, failKey} {
+	for _, key := range []string{actionKey, workflowKey, skipKey, whenKey, postKey, initKey, commentsKey, descriptionKey, failKey, cacheKey} {
 		if val, ok := aMap[key]; ok {
 			if _, has := aMap[ExplicitActionAttributePrefix+key]; has {
 				continue
@@ -205,10 +215,33 @@ func (p InlineWorkflow) groupAttributes(source interface{}, state data.Map) (map
 	if value, ok := actionAttributes[loggingKey]; ok {
 		actionAttributes[loggingKey] = toolbox.AsBoolean(value)
 	}
+	if value, ok := actionAttributes[cacheKey]; ok {
+		if cache := asActionCache(value); cache != nil {
+			actionAttributes[cacheKey] = cache
+		} else {
+			delete(actionAttributes, cacheKey)
+		}
+	}
 	err = p.loadVariables(actionAttributes, state)
 	return actionAttributes, actionRequest, err
 }
 
+//asActionCache returns action cache attribute, cache: true and cache: ttlMs shorthands are supported
+func asActionCache(value interface{}) interface{} {
+	switch {
+	case value == nil:
+		return nil
+	case toolbox.IsMap(value):
+		return value
+	case toolbox.IsBool(value) || toolbox.IsString(value):
+		if !toolbox.AsBoolean(value) {
+			return nil
+		}
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{"TTLMs": value}
+}
+
 func (p *InlineWorkflow) loadVariables(actionAttributes map[string]interface{}, state data.Map) error {
 	for _, key := range []string{initKey, postKey} {
 		value, ok := actionAttributes[key]
@@ -243,8 +276,12 @@ func (p *InlineWorkflow) AsWorkflow(name string, baseURL string) (*Workflow, err
 		TasksNode: &TasksNode{
 			Tasks: []*Task{},
 		},
-		Data:   p.Data,
-		Source: url.NewResource(toolbox.URLPathJoin(baseURL, name+".yaml")),
+		Data:     p.Data,
+		Contract: p.Contract,
+		Inputs:   p.Inputs,
+		Outputs:  p.Outputs,
+		Endly:    p.Endly,
+		Source:   url.NewResource(toolbox.URLPathJoin(baseURL, name+".yaml")),
 	}
 	var err error
 	if p.Init != nil {
@@ -443,6 +480,16 @@ func (p *InlineWorkflow) buildWorkflowNodes(name string, source interface{}, par
 		if reset, ok := actionAttributes[failKey]; ok {
 			task.Fail = toolbox.AsBoolean(reset)
 		}
+		if !parentTask.multiAction {
+			if group, ok := actionAttributes[groupKey]; ok {
+				task.Group = toolbox.AsString(group)
+			}
+			for key, value := range actionAttributes {
+				if err = setTaskBranch(task, strings.ToLower(key), value); err != nil {
+					return err
+				}
+			}
+		}
 		return nil
 	}
 
@@ -467,6 +514,13 @@ func (p *InlineWorkflow) buildWorkflowNodes(name string, source interface{}, par
 			nodeAttributes[textKey] = value
 		}
 		flagAsMultiActionIfMatched(textKey, task, value)
+		if textKey == groupKey && task != nil && !toolbox.IsSlice(value) {
+			task.Group = toolbox.AsString(value)
+		}
+		if isTaskBranchKey(textKey) && task != nil {
+			buildErr = setTaskBranch(task, textKey, value)
+			return buildErr == nil
+		}
 		if value == nil || !toolbox.IsSlice(value) {
 			return true
 		}
@@ -502,6 +556,30 @@ func (p *InlineWorkflow) buildWorkflowNodes(name string, source interface{}, par
 	return buildErr
 }
 
+func isTaskBranchKey(key string) bool {
+	return key == onSuccessKey || key == onErrorKey || key == switchKey
+}
+
+//setTaskBranch sets task branching attribute if supplied key is a branch key
+func setTaskBranch(task *Task, key string, value interface{}) error {
+	switch key {
+	case onSuccessKey:
+		task.OnSuccess = toolbox.AsString(value)
+	case onErrorKey:
+		task.OnError = toolbox.AsString(value)
+	case switchKey:
+		aMap, err := util.NormalizeMap(value, true)
+		if err != nil {
+			return fmt.Errorf("invalid %v switch: %v", task.Name, err)
+		}
+		task.Switch = &TaskSwitch{}
+		if err = toolbox.DefaultConverter.AssignConverted(task.Switch, aMap); err != nil {
+			return fmt.Errorf("invalid %v switch: %v", task.Name, err)
+		}
+	}
+	return nil
+}
+
 func flagAsMultiActionIfMatched(textKey string, task *Task, value interface{}) {
 	for _, key := range multiActionKeys {
 		if textKey == key && toolbox.IsBool(value) {
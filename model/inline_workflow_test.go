@@ -395,6 +395,117 @@ pipeline:
 			"Name": "aero"
 		}
 	]
+}`,
+		},
+		{
+			Description: "concurrent task group",
+			YAMLData: `pipeline:
+  build:
+    group: g1
+    app1:
+      action: print
+      message: app1
+  test:
+    action: print
+    group: g1
+    message: test
+  deploy:
+    action: print
+    message: deploy
+ `,
+			Expected: `{
+	"Tasks": [
+		{
+			"Name": "build",
+			"Group": "g1"
+		},
+		{
+			"Name": "test",
+			"Group": "g1"
+		},
+		{
+			"Name": "deploy",
+			"Group": ""
+		}
+	]
+}`,
+		},
+		{
+			Description: "task branching",
+			YAMLData: `pipeline:
+  build:
+    onError: cleanup
+    app1:
+      action: print
+      message: app1
+  check:
+    action: print
+    message: check
+    switch:
+      sourceKey: env
+      cases:
+        - value: prod
+          task: deploy
+      default: cleanup
+  deploy:
+    action: print
+    message: deploy
+    onSuccess: cleanup
+  cleanup:
+    action: print
+    message: cleanup
+ `,
+			Expected: `{
+	"Tasks": [
+		{
+			"Name": "build",
+			"OnError": "cleanup"
+		},
+		{
+			"Name": "check",
+			"Switch": {
+				"SourceKey": "env",
+				"Cases": [
+					{
+						"Value": "prod",
+						"Task": "deploy"
+					}
+				],
+				"Default": "cleanup"
+			}
+		},
+		{
+			"Name": "deploy",
+			"OnSuccess": "cleanup"
+		},
+		{
+			"Name": "cleanup"
+		}
+	]
+}`,
+		},
+		{
+			Description: "action loop",
+			YAMLData: `pipeline:
+  users:
+    action: print
+    forEach: $users
+    message: $index $item.name
+ `,
+			Expected: `{
+	"Tasks": [
+		{
+			"Name": "users",
+			"Actions": [
+				{
+					"ForEach": "$users",
+					"Request": {
+						"message": "$index $item.name"
+					}
+				}
+			]
+		}
+	]
 }`,
 		},
 	}
@@ -429,3 +540,20 @@ pipeline:
 	}
 
 }
+
+func TestAsActionCache(t *testing.T) {
+	var useCases = []struct {
+		description string
+		value       interface{}
+		expect      interface{}
+	}{
+		{description: "enabled", value: true, expect: map[string]interface{}{}},
+		{description: "enabled text", value: "true", expect: map[string]interface{}{}},
+		{description: "disabled", value: false, expect: nil},
+		{description: "ttl", value: 60000, expect: map[string]interface{}{"TTLMs": 60000}},
+		{description: "policy", value: map[string]interface{}{"ttlMs": 1000}, expect: map[string]interface{}{"ttlMs": 1000}},
+	}
+	for _, useCase := range useCases {
+		assert.EqualValues(t, useCase.expect, asActionCache(useCase.value), useCase.description)
+	}
+}
@@ -0,0 +1,228 @@
+package inventory
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	//Scheme represents group reference URL scheme, i.e. inventory://web
+	Scheme = "inventory"
+	//GroupAll represents group with all inventory hosts
+	GroupAll = "all"
+	//HostKey represents state key with current host in fan-out action request, i.e. ${host.Name}
+	HostKey = "host"
+)
+
+var registryKey = (*registry)(nil)
+
+//Host represents inventory host
+type Host struct {
+	Name        string                 `required:"true" description:"unique host name"`
+	URL         string                 `required:"true" description:"host URL, i.e. ssh://10.0.0.1:22"`
+	Credentials string                 `description:"host credentials"`
+	Roles       []string               `description:"host roles, each role is a group name"`
+	Vars        map[string]interface{} `description:"host variables, accessible in fan-out action request as $host.[Key]"`
+}
+
+//Resource returns host resource with optional URI path
+func (h *Host) Resource(URIPath string) *url.Resource {
+	URL := h.URL
+	if URIPath != "" && URIPath != "/" {
+		URL = toolbox.URLPathJoin(URL, URIPath)
+	}
+	return url.NewResource(URL, h.Credentials)
+}
+
+//AsMap returns host variables with name, URL and hostname
+func (h *Host) AsMap() map[string]interface{} {
+	var result = make(map[string]interface{})
+	for k, v := range h.Vars {
+		result[k] = v
+	}
+	result["Name"] = h.Name
+	result["URL"] = h.URL
+	result["Credentials"] = h.Credentials
+	result["Hostname"] = url.NewResource(h.URL).ParsedURL.Hostname()
+	return result
+}
+
+//Inventory represents hosts grouped by role
+type Inventory struct {
+	Hosts  []*Host             `description:"inventory hosts"`
+	Groups map[string][]string `description:"optional group name to host names map, hosts are grouped by roles too"`
+}
+
+//Init initialises inventory
+func (i *Inventory) Init() error {
+	for _, host := range i.Hosts {
+		if host.Name == "" && host.URL != "" {
+			host.Name = url.NewResource(host.URL).ParsedURL.Hostname()
+		}
+	}
+	return nil
+}
+
+//Validate checks if inventory is valid
+func (i *Inventory) Validate() error {
+	var names = make(map[string]bool)
+	for j, host := range i.Hosts {
+		if host.URL == "" {
+			return fmt.Errorf("hosts[%d].URL was empty", j)
+		}
+		if host.Name == "" {
+			return fmt.Errorf("hosts[%d].Name was empty", j)
+		}
+		if names[host.Name] {
+			return fmt.Errorf("duplicate host: %v", host.Name)
+		}
+		names[host.Name] = true
+	}
+	for group, hosts := range i.Groups {
+		for _, name := range hosts {
+			if !names[name] {
+				return fmt.Errorf("group %v: unknown host: %v", group, name)
+			}
+		}
+	}
+	return nil
+}
+
+//Group returns group hosts, group is matched by name or host role
+func (i *Inventory) Group(name string) []*Host {
+	var result = make([]*Host, 0)
+	var members = make(map[string]bool)
+	for _, hostName := range i.Groups[name] {
+		members[hostName] = true
+	}
+	for _, host := range i.Hosts {
+		if name == GroupAll || members[host.Name] || toolbox.HasSliceAnyElements(host.Roles, name) {
+			result = append(result, host)
+		}
+	}
+	return result
+}
+
+//GroupNames returns sorted group names with their host names
+func (i *Inventory) GroupNames() map[string][]string {
+	var result = make(map[string][]string)
+	for group, hosts := range i.Groups {
+		result[group] = append(result[group], hosts...)
+	}
+	for _, host := range i.Hosts {
+		for _, role := range host.Roles {
+			if !toolbox.HasSliceAnyElements(result[role], host.Name) {
+				result[role] = append(result[role], host.Name)
+			}
+		}
+	}
+	for _, hosts := range result {
+		sort.Strings(hosts)
+	}
+	return result
+}
+
+//Merge adds or replaces hosts and groups, hosts are matched by name so source has to be initialised first
+func (i *Inventory) Merge(source *Inventory) {
+	var index = make(map[string]int)
+	for j, host := range i.Hosts {
+		index[host.Name] = j
+	}
+	for _, host := range source.Hosts {
+		if j, ok := index[host.Name]; ok {
+			i.Hosts[j] = host
+			continue
+		}
+		index[host.Name] = len(i.Hosts)
+		i.Hosts = append(i.Hosts, host)
+	}
+	if len(source.Groups) > 0 && i.Groups == nil {
+		i.Groups = make(map[string][]string)
+	}
+	for group, hosts := range source.Groups {
+		i.Groups[group] = hosts
+	}
+}
+
+//registry represents context inventory shared by cloned contexts
+type registry struct {
+	mux       *sync.RWMutex
+	inventory *Inventory
+}
+
+func registryFor(context *endly.Context) *registry {
+	var result *registry
+	if !context.Contains(registryKey) {
+		result = &registry{mux: &sync.RWMutex{}, inventory: &Inventory{}}
+		_ = context.Put(registryKey, result)
+		return result
+	}
+	context.GetInto(registryKey, &result)
+	return result
+}
+
+//Load merges supplied inventory into context inventory, context inventory is left unchanged if merged inventory is invalid
+func Load(context *endly.Context, inventory *Inventory) (*Inventory, error) {
+	if err := inventory.Init(); err != nil {
+		return nil, err
+	}
+	registry := registryFor(context)
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+	var merged = &Inventory{}
+	merged.Merge(registry.inventory)
+	merged.Merge(inventory)
+	if err := merged.Validate(); err != nil {
+		return nil, err
+	}
+	registry.inventory = merged
+	return merged, nil
+}
+
+//Hosts returns context inventory group hosts
+func Hosts(context *endly.Context, group string) ([]*Host, error) {
+	registry := registryFor(context)
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+	result := registry.inventory.Group(group)
+	if len(result) == 0 {
+		return nil, fmt.Errorf("unknown inventory group: %v, use inventory:load to register hosts", group)
+	}
+	return result, nil
+}
+
+//GroupReference returns group name and URI path if supplied target references inventory group,
+//target can be URL text or resource map, i.e. inventory://web or {URL: inventory://web/opt/app}
+func GroupReference(target interface{}) (string, string, bool) {
+	var URL string
+	switch value := target.(type) {
+	case string:
+		URL = value
+	case *url.Resource:
+		URL = value.URL
+	default:
+		if !toolbox.IsMap(target) {
+			return "", "", false
+		}
+		for k, v := range toolbox.AsMap(target) {
+			if strings.ToLower(k) == "url" {
+				URL = toolbox.AsString(v)
+			}
+		}
+	}
+	prefix := Scheme + "://"
+	if !strings.HasPrefix(URL, prefix) {
+		return "", "", false
+	}
+	location := URL[len(prefix):]
+	group, URIPath := location, ""
+	if index := strings.Index(location, "/"); index != -1 {
+		group, URIPath = location[:index], location[index:]
+	}
+	return group, URIPath, group != ""
+}
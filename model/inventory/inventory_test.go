@@ -0,0 +1,80 @@
+package inventory
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox/url"
+	"testing"
+)
+
+func TestGroupReference(t *testing.T) {
+	var useCases = []struct {
+		description string
+		target      interface{}
+		group       string
+		URIPath     string
+		ok          bool
+	}{
+		{description: "URL", target: "inventory://web", group: "web", ok: true},
+		{description: "resource map", target: map[string]interface{}{"url": "inventory://web/opt/app"}, group: "web", URIPath: "/opt/app", ok: true},
+		{description: "resource", target: url.NewResource("inventory://db"), group: "db", ok: true},
+		{description: "host", target: map[string]interface{}{"URL": "ssh://127.0.0.1:22"}},
+		{description: "empty group", target: "inventory://"},
+	}
+	for _, useCase := range useCases {
+		group, URIPath, ok := GroupReference(useCase.target)
+		assert.Equal(t, useCase.ok, ok, useCase.description)
+		assert.Equal(t, useCase.group, group, useCase.description)
+		assert.Equal(t, useCase.URIPath, URIPath, useCase.description)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+	_, err := Load(context, &Inventory{
+		Hosts: []*Host{
+			{Name: "web1", URL: "ssh://10.0.0.1:22", Roles: []string{"web"}},
+			{Name: "web2", URL: "ssh://10.0.0.2:22", Roles: []string{"web"}},
+			{URL: "ssh://10.0.0.3:22", Roles: []string{"db"}},
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	merged, err := Load(context, &Inventory{
+		Hosts:  []*Host{{Name: "web2", URL: "ssh://10.0.0.4:22", Roles: []string{"web"}}},
+		Groups: map[string][]string{"canary": {"web1"}},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, map[string][]string{"web": {"web1", "web2"}, "db": {"10.0.0.3"}, "canary": {"web1"}}, merged.GroupNames())
+
+	_, err = Load(context, &Inventory{Groups: map[string][]string{"broken": {"web3"}}})
+	assert.NotNil(t, err)
+	_, err = Load(context, &Inventory{Hosts: []*Host{{URL: "file:///tmp/app"}}})
+	assert.NotNil(t, err, "empty host name")
+
+	var useCases = []struct {
+		group string
+		URLs  []string
+	}{
+		{group: "web", URLs: []string{"ssh://10.0.0.1:22", "ssh://10.0.0.4:22"}},
+		{group: "canary", URLs: []string{"ssh://10.0.0.1:22"}},
+		{group: GroupAll, URLs: []string{"ssh://10.0.0.1:22", "ssh://10.0.0.4:22", "ssh://10.0.0.3:22"}},
+		{group: "broken"},
+	}
+	for _, useCase := range useCases {
+		hosts, err := Hosts(context, useCase.group)
+		if len(useCase.URLs) == 0 {
+			assert.NotNil(t, err, useCase.group)
+			continue
+		}
+		var URLs = make([]string, 0)
+		for _, host := range hosts {
+			URLs = append(URLs, host.URL)
+		}
+		assert.Equal(t, useCase.URLs, URLs, useCase.group)
+	}
+}
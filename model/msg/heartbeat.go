@@ -0,0 +1,40 @@
+package msg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//HeartbeatEvent represents periodic long-running action heartbeat
+type HeartbeatEvent struct {
+	Service string
+	Action  string
+	Elapsed time.Duration
+	Output  string `json:",omitempty"`
+	Bytes   int64  `json:",omitempty"`
+}
+
+//Message returns heartbeat message
+func (e *HeartbeatEvent) Message(repeated *Repeated) *Message {
+	var tag = NewStyled("heartbeat", MessageStyleGeneric)
+	var info = []string{fmt.Sprintf("%v.%v running for %v", e.Service, e.Action, e.Elapsed.Truncate(time.Second))}
+	if e.Bytes > 0 {
+		info = append(info, fmt.Sprintf("transferred: %v bytes", e.Bytes))
+	}
+	if e.Output != "" {
+		info = append(info, fmt.Sprintf("last output: %v", e.Output))
+	}
+	return NewMessage(NewStyled(strings.Join(info, ", "), MessageStyleGeneric), tag)
+}
+
+//NewHeartbeatEvent creates a new heartbeat event
+func NewHeartbeatEvent(service, action string, elapsed time.Duration, output string, bytes int64) *HeartbeatEvent {
+	return &HeartbeatEvent{
+		Service: service,
+		Action:  action,
+		Elapsed: elapsed,
+		Output:  output,
+		Bytes:   bytes,
+	}
+}
@@ -21,7 +21,10 @@ type Process struct {
 	State      data.Map
 	Terminated int32
 	Scheduled  *Task
+	Completed  map[string]bool
 	*ExecutionError
+	taskMux    *sync.Mutex
+	taskScopes [][]func()
 }
 
 //Terminate flags current workflow as terminated
@@ -38,6 +41,50 @@ func (p *Process) SetTask(task *Task) {
 	}
 }
 
+//BeginTask starts task scope, returned function runs functions registered with OnTaskEnd in reverse order
+func (p *Process) BeginTask() func() {
+	p.taskMux.Lock()
+	p.taskScopes = append(p.taskScopes, make([]func(), 0))
+	index := len(p.taskScopes) - 1
+	p.taskMux.Unlock()
+	return func() {
+		p.taskMux.Lock()
+		functions := p.taskScopes[index]
+		p.taskScopes = p.taskScopes[:index]
+		p.taskMux.Unlock()
+		for i := len(functions) - 1; i >= 0; i-- {
+			functions[i]()
+		}
+	}
+}
+
+//OnTaskEnd registers function to run once the current task ends, it returns false if no task is running
+func (p *Process) OnTaskEnd(function func()) bool {
+	p.taskMux.Lock()
+	defer p.taskMux.Unlock()
+	if len(p.taskScopes) == 0 {
+		return false
+	}
+	index := len(p.taskScopes) - 1
+	p.taskScopes[index] = append(p.taskScopes[index], function)
+	return true
+}
+
+//Release temporarily clears process termination and scheduled task, so that a cleanup task can still run, returned function restores it
+func (p *Process) Release() func() {
+	terminated := atomic.SwapInt32(&p.Terminated, 0)
+	scheduled := p.Scheduled
+	p.Scheduled = nil
+	return func() {
+		if terminated == 1 {
+			p.Terminate()
+		}
+		if p.Scheduled == nil {
+			p.Scheduled = scheduled
+		}
+	}
+}
+
 //CanRun returns true if current workflow can run
 func (p *Process) CanRun() bool {
 	return !(p.IsTerminated() || p.Scheduled != nil)
@@ -53,9 +100,46 @@ func (p *Process) Push(activity *Activity) {
 	if p.Workflow != nil {
 		activity.Caller = p.Workflow.Name
 	}
+	if p.Task != nil {
+		activity.Task = p.Task.Name
+	}
 	p.Activities.Push(activity)
 }
 
+//Fork creates a process copy with its own activities, execution state, process state and task scopes, so that a task can run concurrently
+func (p *Process) Fork() *Process {
+	var result = *p
+	result.Activities = NewActivities()
+	result.ExecutionError = &ExecutionError{}
+	result.Scheduled = nil
+	result.Completed = make(map[string]bool)
+	for k, v := range p.Completed {
+		result.Completed[k] = v
+	}
+	if p.State != nil {
+		result.State = p.State.Clone()
+	}
+	result.taskMux = &sync.Mutex{}
+	result.taskScopes = nil
+	return &result
+}
+
+//Join merges forked process execution state back into this process
+func (p *Process) Join(fork *Process) {
+	for k, v := range fork.Completed {
+		p.Completed[k] = v
+	}
+	if fork.IsTerminated() {
+		p.Terminate()
+	}
+	if p.Scheduled == nil {
+		p.Scheduled = fork.Scheduled
+	}
+	if p.ExecutionError.Error == "" && fork.ExecutionError.Error != "" {
+		*p.ExecutionError = *fork.ExecutionError
+	}
+}
+
 //Push adds a workflow to the workflow stack.
 func (p *Process) AddTagIDs(tagIDs ...string) {
 	for _, tagID := range tagIDs {
@@ -70,6 +154,8 @@ func NewProcess(source *url.Resource, workflow *Workflow, upstream *Process) *Pr
 		ExecutionError: &ExecutionError{},
 		Workflow:       workflow,
 		Activities:     NewActivities(),
+		Completed:      map[string]bool{},
+		taskMux:        &sync.Mutex{},
 	}
 	if source != nil {
 		_, process.Owner = toolbox.URLSplit(source.URL)
@@ -166,6 +252,15 @@ func (p *Processes) First() *Process {
 	return nil
 }
 
+//Clone returns a process stack copy, so that concurrently running workflows can push and pop their own processes.
+func (p *Processes) Clone() *Processes {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	var result = NewProcesses()
+	result.processes = append(result.processes, p.processes...)
+	return result
+}
+
 //NewProcesses creates a new processes
 func NewProcesses() *Processes {
 	return &Processes{
@@ -0,0 +1,273 @@
+package ratelimit
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	minBackoff = 10 * time.Millisecond
+	maxBackoff = 500 * time.Millisecond
+)
+
+var limitersKey = (*limiters)(nil)
+
+func init() {
+	endly.RegisterMiddleware(Middleware)
+}
+
+//Limit represents service actions rate limit and quota guard, limit budget is shared by all matched services actions
+type Limit struct {
+	Service     string   `required:"true" description:"service id or service id prefix, i.e. gcp/compute, aws/ec2, storage, or aws for all aws services"`
+	Actions     []string `description:"optional limited actions, all service actions are limited if empty"`
+	Rate        float64  `description:"max actions per second"`
+	Burst       int      `description:"max actions burst, default 1"`
+	Concurrency int      `description:"max concurrently running actions, nested matched actions count too"`
+	MaxWaitMs   int      `description:"max queue wait time, once exceeded action fails, by default action waits until workflow is canceled"`
+}
+
+//Init initialises limit
+func (l *Limit) Init() error {
+	if l.Burst <= 0 {
+		l.Burst = 1
+	}
+	return nil
+}
+
+//Validate checks if limit is valid
+func (l *Limit) Validate() error {
+	if l.Service == "" {
+		return fmt.Errorf("rate limit service was empty")
+	}
+	if l.Rate < 0 || l.Concurrency < 0 || l.MaxWaitMs < 0 {
+		return fmt.Errorf("invalid %v rate limit: rate: %v, concurrency: %v, maxWaitMs: %v", l.Service, l.Rate, l.Concurrency, l.MaxWaitMs)
+	}
+	if l.Rate == 0 && l.Concurrency == 0 {
+		return fmt.Errorf("%v rate limit rate and concurrency were empty", l.Service)
+	}
+	return nil
+}
+
+//Matches returns true if limit applies to supplied service action
+func (l *Limit) Matches(serviceID, action string) bool {
+	if serviceID != l.Service && !strings.HasPrefix(serviceID, l.Service+"/") {
+		return false
+	}
+	if len(l.Actions) == 0 {
+		return true
+	}
+	for _, candidate := range l.Actions {
+		if candidate == action {
+			return true
+		}
+	}
+	return false
+}
+
+//WaitEvent represents throttled action event with wait time metrics
+type WaitEvent struct {
+	Service     string
+	Action      string
+	Limit       string
+	WaitMs      int
+	Queued      int `description:"number of actions waiting for the limit when this action was released"`
+	Throttled   int `description:"number of throttled limit actions so far"`
+	TotalWaitMs int `description:"total wait time of throttled limit actions so far"`
+}
+
+//Messages returns messages
+func (e *WaitEvent) Messages() []*msg.Message {
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("%v.%v", e.Service, e.Action), msg.MessageStyleGeneric), msg.NewStyled("throttled", msg.MessageStyleGeneric),
+			msg.NewStyled(fmt.Sprintf("limit: %v, waited: %v ms, queued: %v, throttled: %v, total wait: %v ms", e.Limit, e.WaitMs, e.Queued, e.Throttled, e.TotalWaitMs), msg.MessageStyleOutput),
+		),
+	}
+}
+
+//limiter represents token bucket with concurrency guard
+type limiter struct {
+	*Limit
+	mux       *sync.Mutex
+	tokens    float64
+	updated   time.Time
+	running   int
+	waiting   int
+	throttled int
+	totalWait time.Duration
+}
+
+//tryAcquire takes a token and concurrency slot if available, otherwise it returns time to the next token
+func (l *limiter) tryAcquire(now time.Time) (time.Duration, bool) {
+	if l.Rate > 0 {
+		l.tokens += now.Sub(l.updated).Seconds() * l.Rate
+		if l.tokens > float64(l.Burst) {
+			l.tokens = float64(l.Burst)
+		}
+		l.updated = now
+	}
+	if l.Concurrency > 0 && l.running >= l.Concurrency {
+		return 0, false
+	}
+	if l.Rate > 0 {
+		if l.tokens < 1 {
+			return time.Duration((1 - l.tokens) / l.Rate * float64(time.Second)), false
+		}
+		l.tokens--
+	}
+	l.running++
+	return 0, true
+}
+
+//acquire waits with backoff till the limit budget is available, it returns wait event if action was throttled
+func (l *limiter) acquire(context *endly.Context) (*WaitEvent, error) {
+	started := time.Now()
+	backoff := minBackoff
+	queued := false
+	maxWait := time.Duration(l.MaxWaitMs) * time.Millisecond
+	for {
+		l.mux.Lock()
+		delay, ok := l.tryAcquire(time.Now())
+		if ok {
+			var event *WaitEvent
+			if queued {
+				waited := time.Since(started)
+				l.waiting--
+				l.throttled++
+				l.totalWait += waited
+				event = &WaitEvent{Limit: l.Service, WaitMs: int(waited / time.Millisecond), Queued: l.waiting, Throttled: l.throttled, TotalWaitMs: int(l.totalWait / time.Millisecond)}
+			}
+			l.mux.Unlock()
+			return event, nil
+		}
+		if !queued {
+			queued = true
+			l.waiting++
+		}
+		l.mux.Unlock()
+		if delay < backoff {
+			delay = backoff
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		if maxWait > 0 {
+			remaining := maxWait - time.Since(started)
+			if remaining <= 0 {
+				l.dequeue()
+				return nil, fmt.Errorf("%v rate limit exceeded, waited: %v, max wait: %v ms", l.Service, time.Since(started).Round(time.Millisecond), l.MaxWaitMs)
+			}
+			if delay > remaining {
+				delay = remaining
+			}
+		}
+		select {
+		case <-context.Done():
+			l.dequeue()
+			return nil, context.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (l *limiter) dequeue() {
+	l.mux.Lock()
+	l.waiting--
+	l.mux.Unlock()
+}
+
+func (l *limiter) release() {
+	l.mux.Lock()
+	l.running--
+	l.mux.Unlock()
+}
+
+func newLimiter(limit *Limit) *limiter {
+	return &limiter{
+		Limit:   limit,
+		mux:     &sync.Mutex{},
+		tokens:  float64(limit.Burst),
+		updated: time.Now(),
+	}
+}
+
+//limiters represents context limiters shared by cloned contexts
+type limiters struct {
+	items []*limiter
+}
+
+func (l *limiters) match(serviceID, action string) []*limiter {
+	var result = make([]*limiter, 0)
+	for _, candidate := range l.items {
+		if candidate.Matches(serviceID, action) {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+func limitersFor(context *endly.Context) *limiters {
+	var result *limiters
+	if !context.Contains(limitersKey) {
+		return nil
+	}
+	context.GetInto(limitersKey, &result)
+	return result
+}
+
+//Enable enables supplied limits for the context and its clones, returned function removes limits,
+//if limits were already enabled by the upstream workflow or are empty, it is no-op
+func Enable(context *endly.Context, limits []*Limit) (func(), error) {
+	if len(limits) == 0 || limitersFor(context) != nil {
+		return func() {}, nil
+	}
+	var result = &limiters{items: make([]*limiter, 0, len(limits))}
+	for _, limit := range limits {
+		if err := limit.Init(); err != nil {
+			return nil, err
+		}
+		if err := limit.Validate(); err != nil {
+			return nil, err
+		}
+		result.items = append(result.items, newLimiter(limit))
+	}
+	if err := context.Put(limitersKey, result); err != nil {
+		return nil, err
+	}
+	return func() {
+		context.Remove(limitersKey)
+	}, nil
+}
+
+//Middleware queues matched service actions till all matched limits budget is available
+func Middleware(service endly.Service, route *endly.Route, next endly.RouteHandler) endly.RouteHandler {
+	return func(context *endly.Context, request interface{}) (interface{}, error) {
+		registry := limitersFor(context)
+		if registry == nil {
+			return next(context, request)
+		}
+		matched := registry.match(service.ID(), route.Action)
+		for i, limiter := range matched {
+			event, err := limiter.acquire(context)
+			if err != nil {
+				for _, acquired := range matched[:i] {
+					acquired.release()
+				}
+				return nil, err
+			}
+			if event != nil {
+				event.Service, event.Action = service.ID(), route.Action
+				context.Publish(event)
+			}
+		}
+		defer func() {
+			for _, limiter := range matched {
+				limiter.release()
+			}
+		}()
+		return next(context, request)
+	}
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimit_Matches(t *testing.T) {
+	var useCases = []struct {
+		description string
+		limit       *Limit
+		service     string
+		action      string
+		expect      bool
+	}{
+		{description: "exact service", limit: &Limit{Service: "aws/ec2"}, service: "aws/ec2", action: "describeInstances", expect: true},
+		{description: "service prefix", limit: &Limit{Service: "aws"}, service: "aws/ec2", action: "describeInstances", expect: true},
+		{description: "partial prefix", limit: &Limit{Service: "aws/ec"}, service: "aws/ec2", action: "describeInstances", expect: false},
+		{description: "matched action", limit: &Limit{Service: "storage", Actions: []string{"copy"}}, service: "storage", action: "copy", expect: true},
+		{description: "unmatched action", limit: &Limit{Service: "storage", Actions: []string{"copy"}}, service: "storage", action: "list", expect: false},
+	}
+	for _, useCase := range useCases {
+		assert.Equal(t, useCase.expect, useCase.limit.Matches(useCase.service, useCase.action), useCase.description)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	manager := endly.New()
+	service, err := manager.Service("nop")
+	if !assert.Nil(t, err) {
+		return
+	}
+	route, err := service.Route("nop")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	t.Run("rate", func(t *testing.T) {
+		context := manager.NewContext(nil)
+		var events = make([]*WaitEvent, 0)
+		var mux = &sync.Mutex{}
+		context.SetListener(func(event msg.Event) {
+			if waitEvent, ok := event.Value().(*WaitEvent); ok {
+				mux.Lock()
+				events = append(events, waitEvent)
+				mux.Unlock()
+			}
+		})
+		release, err := Enable(context, []*Limit{{Service: "nop", Rate: 20}})
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer release()
+		handler := Middleware(service, route, func(context *endly.Context, request interface{}) (interface{}, error) {
+			return request, nil
+		})
+		started := time.Now()
+		for i := 0; i < 3; i++ {
+			response, err := handler(context, i)
+			assert.Nil(t, err)
+			assert.Equal(t, i, response)
+		}
+		assert.True(t, time.Since(started) >= 90*time.Millisecond, time.Since(started).String())
+		if assert.Equal(t, 2, len(events)) {
+			assert.Equal(t, "nop", events[1].Service)
+			assert.Equal(t, "nop", events[1].Action)
+			assert.Equal(t, 2, events[1].Throttled)
+			assert.True(t, events[1].TotalWaitMs >= events[1].WaitMs)
+		}
+	})
+
+	t.Run("concurrency with max wait", func(t *testing.T) {
+		context := manager.NewContext(nil)
+		release, err := Enable(context, []*Limit{{Service: "nop", Concurrency: 1, MaxWaitMs: 50}})
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer release()
+		var running = make(chan bool)
+		var done = make(chan bool)
+		handler := Middleware(service, route, func(context *endly.Context, request interface{}) (interface{}, error) {
+			if request == "block" {
+				running <- true
+				<-done
+			}
+			return request, nil
+		})
+		go func() {
+			_, _ = handler(context.Clone(), "block")
+		}()
+		<-running
+		_, err = handler(context, "test")
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), "nop rate limit exceeded")
+		}
+		close(done)
+		response, err := handler(context, "test")
+		assert.Nil(t, err)
+		assert.Equal(t, "test", response)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		context := manager.NewContext(nil)
+		release, err := Enable(context, []*Limit{{Service: "nop", Rate: 0.1}})
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer release()
+		handler := Middleware(service, route, func(context *endly.Context, request interface{}) (interface{}, error) {
+			return request, nil
+		})
+		_, err = handler(context, "test")
+		assert.Nil(t, err)
+		time.AfterFunc(20*time.Millisecond, context.Cancel)
+		_, err = handler(context, "test")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		context := manager.NewContext(nil)
+		_, err := Enable(context, []*Limit{{Service: "nop"}})
+		assert.NotNil(t, err)
+	})
+}
@@ -35,7 +35,7 @@ func (r *Repeater) Init() *Repeater {
 
 //EvaluateExitCriteria check is exit criteria is met.
 func (r *Repeater) EvaluateExitCriteria(callerInfo string, context *endly.Context, extracted map[string]interface{}) (bool, error) {
-	var state = context.State()
+	var state = context.SafeState()
 	var extractedState = state.Clone()
 	for k, v := range extracted {
 		extractedState[k] = v
@@ -42,6 +42,11 @@ func (s WorkflowSelector) split() (URL, name, tasks string) {
 		URL = string(URL[:taskPosition])
 
 	}
+	var query = ""
+	if index := strings.Index(URL, "?"); index != -1 {
+		query = string(URL[index:])
+		URL = string(URL[:index])
+	}
 	var ext = path.Ext(URL)
 	if ext == "" {
 		_, name = path.Split(URL)
@@ -49,7 +54,7 @@ func (s WorkflowSelector) split() (URL, name, tasks string) {
 	} else {
 		_, name = path.Split(string(URL[:len(URL)-len(ext)]))
 	}
-	return URL, name, tasks
+	return URL + query, name, tasks
 }
 
 //Name returns selector workflow name
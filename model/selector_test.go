@@ -63,6 +63,14 @@ func TestWorkflowSelector(t *testing.T) {
 			ExpectedTaks:     "task1",
 			ExpectedRelative: false,
 		},
+		{
+			Description:      "git URL selector with ref parameter",
+			Selector:         "https://github.com/org/repo.git/workflow/build.yaml?ref=v1.2.0:task1",
+			ExpectedURL:      "https://github.com/org/repo.git/workflow/build.yaml?ref=v1.2.0",
+			ExpectedName:     "build",
+			ExpectedTaks:     "task1",
+			ExpectedRelative: false,
+		},
 	}
 
 	for _, useCase := range useCases {
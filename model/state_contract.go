@@ -0,0 +1,81 @@
+package model
+
+import (
+	"fmt"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"strings"
+)
+
+//StateField represents declared workflow state key
+type StateField struct {
+	Name     string `required:"true" description:"state key or path i.e. app.name"`
+	Type     string `description:"expected type: string|int|float|bool|map|slice, any if empty"`
+	Required bool   `description:"flag to require the key presence"`
+	Task     string `description:"task after which required key has to be present, if empty the key is required from the workflow start"`
+}
+
+//validateType checks if supplied value matches declared type
+func (f *StateField) validateType(value interface{}) error {
+	var err error
+	switch strings.ToLower(f.Type) {
+	case "", "any":
+		return nil
+	case "string":
+		if !toolbox.IsString(value) {
+			err = fmt.Errorf("expected string")
+		}
+	case "int":
+		if toolbox.IsFloat(value) && toolbox.AsFloat(value) != float64(toolbox.AsInt(value)) {
+			err = fmt.Errorf("expected int")
+		} else {
+			_, err = toolbox.ToInt(value)
+		}
+	case "float":
+		_, err = toolbox.ToFloat(value)
+	case "bool":
+		_, err = toolbox.ToBoolean(value)
+	case "map":
+		if !toolbox.IsMap(value) && !toolbox.IsStruct(value) {
+			err = fmt.Errorf("expected map")
+		}
+	case "slice", "array":
+		if !toolbox.IsSlice(value) {
+			err = fmt.Errorf("expected slice")
+		}
+	default:
+		return fmt.Errorf("unsupported %v type: %v", f.Name, f.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %v: expected %v but had %T(%v)", f.Name, f.Type, value, value)
+	}
+	return nil
+}
+
+//StateContract represents declared workflow state keys and types, validated at task boundaries
+type StateContract []*StateField
+
+//Validate checks supplied state against this contract, completed represents already run task names
+func (c StateContract) Validate(state data.Map, completed map[string]bool) error {
+	if violations := c.violations(state, completed); len(violations) > 0 {
+		return fmt.Errorf("state contract violation: %v", strings.Join(violations, ", "))
+	}
+	return nil
+}
+
+func (c StateContract) violations(state data.Map, completed map[string]bool) []string {
+	var result = make([]string, 0)
+	for _, field := range c {
+		value, has := state.GetValue(field.Name)
+		if !has || value == nil {
+			if field.Required && (field.Task == "" || completed[field.Task]) {
+				result = append(result, fmt.Sprintf("%v was empty", field.Name))
+			}
+			continue
+		}
+		if err := field.validateType(value); err != nil {
+			result = append(result, err.Error())
+		}
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/data"
+	"testing"
+)
+
+func TestStateContract_Validate(t *testing.T) {
+	var contract = StateContract{
+		{Name: "app.name", Type: "string", Required: true},
+		{Name: "app.port", Type: "int"},
+		{Name: "buildPath", Type: "string", Required: true, Task: "build"},
+	}
+	var useCases = []struct {
+		description string
+		state       map[string]interface{}
+		completed   map[string]bool
+		hasError    bool
+	}{
+		{
+			description: "valid state",
+			state:       map[string]interface{}{"app": map[string]interface{}{"name": "myapp", "port": "8080"}},
+		},
+		{
+			description: "missing required key",
+			state:       map[string]interface{}{"app": map[string]interface{}{"port": 8080}},
+			hasError:    true,
+		},
+		{
+			description: "invalid type",
+			state:       map[string]interface{}{"app": map[string]interface{}{"name": "myapp", "port": "abc"}},
+			hasError:    true,
+		},
+		{
+			description: "required after completed task",
+			state:       map[string]interface{}{"app": map[string]interface{}{"name": "myapp"}},
+			completed:   map[string]bool{"build": true},
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		err := contract.Validate(data.Map(useCase.state), useCase.completed)
+		assert.Equal(t, useCase.hasError, err != nil, useCase.description)
+	}
+}
@@ -1,11 +1,17 @@
 package model
 
+import "github.com/viant/toolbox"
+
 //Task represents a group of action
 type Task struct {
 	*AbstractNode
 	Actions []*Action //actions
 	*TasksNode
-	Fail bool //controls if return fail status workflow on catch task
+	Fail      bool        //controls if return fail status workflow on catch task
+	Group     string      `description:"concurrent group name, consecutive tasks sharing the same group run in parallel with a join barrier"`
+	OnSuccess string      `description:"task to jump to once this task completes successfully"`
+	OnError   string      `description:"task to jump to if this task fails, the error is stored in state 'error' key"`
+	Switch    *TaskSwitch `description:"selects a task to jump to by state value once this task completes successfully"`
 
 	//internal only for inline workflow meta data
 
@@ -19,6 +25,48 @@ type Task struct {
 	subpath  string
 }
 
+//TaskSwitchCase represents task switch matching case
+type TaskSwitchCase struct {
+	Value interface{} `required:"true" description:"matching source value"`
+	Task  string      `required:"true" description:"task to jump to if matched"`
+}
+
+//TaskSwitch represents task branching by state value
+type TaskSwitch struct {
+	SourceKey string            `required:"true" description:"state key for matching value"`
+	Cases     []*TaskSwitchCase `required:"true" description:"matching value cases"`
+	Default   string            `description:"task to jump to if no case was matched"`
+}
+
+//Match returns task name matching supplied value or default task
+func (s *TaskSwitch) Match(source interface{}) string {
+	for _, candidate := range s.Cases {
+		if toolbox.AsString(candidate.Value) == toolbox.AsString(source) {
+			return candidate.Task
+		}
+	}
+	return s.Default
+}
+
+//Targets returns all tasks this task can jump to
+func (t *Task) Targets() []string {
+	var result = make([]string, 0)
+	for _, candidate := range []string{t.OnSuccess, t.OnError} {
+		if candidate != "" {
+			result = append(result, candidate)
+		}
+	}
+	if t.Switch != nil {
+		for _, switchCase := range t.Switch.Cases {
+			result = append(result, switchCase.Task)
+		}
+		if t.Switch.Default != "" {
+			result = append(result, t.Switch.Default)
+		}
+	}
+	return result
+}
+
 func (t *Task) init() error {
 	if len(t.Actions) == 0 {
 		t.Actions = []*Action{}
@@ -0,0 +1,177 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/viant/endly"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	//EndpointEnv represents OTLP exporter endpoint env variable enabling tracing without request config
+	EndpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	//TracesEndpointEnv represents OTLP traces exporter endpoint env variable
+	TracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	//TraceParentHeader represents W3C trace context header
+	TraceParentHeader = "traceparent"
+
+	defaultServiceName  = "endly"
+	instrumentationName = "github.com/viant/endly"
+	shutdownTimeout     = 5 * time.Second
+)
+
+var providerKey = (*provider)(nil)
+var spanKey = (*spanContext)(nil)
+
+var propagator = propagation.TraceContext{}
+
+//Config represents OpenTelemetry tracing config
+type Config struct {
+	Endpoint    string            `description:"OTLP HTTP exporter endpoint, host:port or URL i.e. http://127.0.0.1:4318, if empty OTEL_EXPORTER_OTLP_ENDPOINT env is used"`
+	Insecure    bool              `description:"flag to use http instead of https for host:port endpoint"`
+	ServiceName string            `description:"service.name resource attribute, default endly"`
+	Headers     map[string]string `description:"optional exporter headers, i.e. authorization"`
+}
+
+//IsEnabled returns true if tracing endpoint is configured by config or env
+func (c *Config) IsEnabled() bool {
+	if c != nil && c.Endpoint != "" {
+		return true
+	}
+	return os.Getenv(EndpointEnv) != "" || os.Getenv(TracesEndpointEnv) != ""
+}
+
+func (c *Config) options() ([]otlptracehttp.Option, error) {
+	var result = make([]otlptracehttp.Option, 0)
+	if c == nil {
+		return result, nil
+	}
+	if len(c.Headers) > 0 {
+		result = append(result, otlptracehttp.WithHeaders(c.Headers))
+	}
+	if c.Endpoint == "" {
+		return result, nil
+	}
+	endpoint := c.Endpoint
+	insecure := c.Insecure
+	if strings.Contains(endpoint, "://") {
+		URL, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tracing endpoint: %v, %v", endpoint, err)
+		}
+		endpoint = URL.Host
+		insecure = URL.Scheme == "http"
+		if URL.Path != "" && URL.Path != "/" {
+			result = append(result, otlptracehttp.WithURLPath(URL.Path))
+		}
+	}
+	result = append(result, otlptracehttp.WithEndpoint(endpoint))
+	if insecure {
+		result = append(result, otlptracehttp.WithInsecure())
+	}
+	return result, nil
+}
+
+//provider represents context tracer provider
+type provider struct {
+	tracer trace.Tracer
+}
+
+//spanContext represents current span go context
+type spanContext struct {
+	ctx context.Context
+}
+
+func providerFor(context *endly.Context) *provider {
+	var result *provider
+	if !context.Contains(providerKey) {
+		return nil
+	}
+	context.GetInto(providerKey, &result)
+	return result
+}
+
+//currentContext returns go context with the current span
+func currentContext(ctx *endly.Context) context.Context {
+	var result *spanContext
+	if !ctx.Contains(spanKey) {
+		return context.Background()
+	}
+	ctx.GetInto(spanKey, &result)
+	return result.ctx
+}
+
+//Enable enables tracing with OTLP HTTP exporter for the supplied context, returned function flushes pending spans and releases exporter,
+//if tracing was already enabled by the upstream workflow or is not configured, it is no-op
+func Enable(context *endly.Context, config *Config) (func(), error) {
+	if !config.IsEnabled() || providerFor(context) != nil {
+		return func() {}, nil
+	}
+	options, err := config.options()
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing exporter: %v", err)
+	}
+	serviceName := defaultServiceName
+	if config != nil && config.ServiceName != "" {
+		serviceName = config.ServiceName
+	}
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	if err = context.Put(providerKey, &provider{tracer: tracerProvider.Tracer(instrumentationName)}); err != nil {
+		return nil, err
+	}
+	return func() {
+		ctx, cancel := contextWithTimeout(shutdownTimeout)
+		defer cancel()
+		_ = tracerProvider.Shutdown(ctx)
+		context.Remove(providerKey)
+	}, nil
+}
+
+func contextWithTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+//Start starts a span as a child of the current context span, returned function ends the span with the supplied error status
+func Start(context *endly.Context, name string, attributes ...attribute.KeyValue) func(err error) {
+	tracing := providerFor(context)
+	if tracing == nil {
+		return func(err error) {}
+	}
+	parent := currentContext(context)
+	ctx, span := tracing.tracer.Start(parent, name, trace.WithAttributes(attributes...))
+	_ = context.Replace(spanKey, &spanContext{ctx: ctx})
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		_ = context.Replace(spanKey, &spanContext{ctx: parent})
+	}
+}
+
+//Inject sets W3C trace context headers for the current span, headers already set are preserved
+func Inject(context *endly.Context, header http.Header) {
+	if header.Get(TraceParentHeader) != "" || !context.Contains(spanKey) {
+		return
+	}
+	propagator.Inject(currentContext(context), propagation.HeaderCarrier(header))
+}
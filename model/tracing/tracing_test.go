@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	context := endly.New().NewContext(nil)
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	_ = context.Put(providerKey, &provider{tracer: tracerProvider.Tracer(instrumentationName)})
+
+	endWorkflow := Start(context, "workflow test")
+	endAction := Start(context, "action http/runner.send")
+	var header = http.Header{}
+	Inject(context, header)
+	endAction(errors.New("test error"))
+	endWorkflow(nil)
+
+	spans := recorder.Ended()
+	if !assert.Equal(t, 2, len(spans)) {
+		return
+	}
+	action, workflow := spans[0], spans[1]
+	assert.Equal(t, "action http/runner.send", action.Name())
+	assert.Equal(t, workflow.SpanContext().SpanID(), action.Parent().SpanID())
+	assert.Equal(t, workflow.SpanContext().TraceID(), action.SpanContext().TraceID())
+	assert.Equal(t, codes.Error, action.Status().Code)
+	assert.Equal(t, codes.Unset, workflow.Status().Code)
+
+	traceParent := header.Get(TraceParentHeader)
+	assert.True(t, strings.Contains(traceParent, action.SpanContext().TraceID().String()), traceParent)
+	assert.True(t, strings.Contains(traceParent, action.SpanContext().SpanID().String()), traceParent)
+
+	header = http.Header{}
+	header.Set(TraceParentHeader, "custom")
+	Inject(context, header)
+	assert.Equal(t, "custom", header.Get(TraceParentHeader))
+}
+
+func TestEnable(t *testing.T) {
+	var exported int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/v1/traces" {
+			atomic.AddInt32(&exported, 1)
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	context := endly.New().NewContext(nil)
+	release, err := Enable(context, &Config{Endpoint: server.URL})
+	if !assert.Nil(t, err) {
+		return
+	}
+	nested, err := Enable(context, &Config{Endpoint: server.URL})
+	assert.Nil(t, err)
+	nested()
+	assert.NotNil(t, providerFor(context), "nested enable should not release upstream tracing")
+
+	Start(context, "workflow test")(nil)
+	release()
+	assert.Nil(t, providerFor(context))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&exported))
+
+	release, err = Enable(endly.New().NewContext(nil), nil)
+	if assert.Nil(t, err) {
+		release()
+	}
+}
@@ -24,6 +24,9 @@ type Variable struct {
 	Required          bool              `description:"flag that validates that from returns non empty value or error is generated"`
 	EmptyIfUnexpanded bool              `description:"threat variable value empty if it was not expanded"`
 	Replace           map[string]string `description:"replacements map, if key if specified substitute variable value with corresponding value. This will work only for string replacements"`
+	Secret            bool              `description:"flag to mask variable value in all emitted events, logs and CLI output"`
+	Type              string            `description:"optional value type: string|int|float|bool|map|slice, mismatched value fails variable application, int, float and bool values are converted"`
+	Default           interface{}       `description:"value used when variable value is empty or not expanded"`
 }
 
 func (v *Variable) tempfile() string {
@@ -141,6 +144,48 @@ func (v *Variable) validate(value interface{}, in data.Map) error {
 	return nil
 }
 
+//isUnresolved returns true if value is empty or value reference was not expanded
+func (v *Variable) isUnresolved(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	text, ok := value.(string)
+	if !ok {
+		return false
+	}
+	if text == "" {
+		return true
+	}
+	reference, ok := v.Value.(string)
+	return ok && text == reference && strings.Contains(reference, "$")
+}
+
+//normalize applies default value and checks and converts value to declared type
+func (v *Variable) normalize(value interface{}, in data.Map) (interface{}, error) {
+	if v.Default != nil && v.isUnresolved(value) {
+		value = in.Expand(v.Default)
+	}
+	if v.Type == "" || value == nil {
+		return value, nil
+	}
+	field := &StateField{Name: v.Name, Type: v.Type}
+	if err := field.validateType(value); err != nil {
+		if source := in.GetString(neatly.OwnerURL); source != "" {
+			return nil, fmt.Errorf("variable '%v' declared by %v: %v", v.Name, source, err)
+		}
+		return nil, fmt.Errorf("variable '%v': %v", v.Name, err)
+	}
+	switch strings.ToLower(v.Type) {
+	case "int":
+		value = toolbox.AsInt(value)
+	case "float":
+		value = toolbox.AsFloat(value)
+	case "bool":
+		value = toolbox.AsBoolean(value)
+	}
+	return value, nil
+}
+
 func (v *Variable) canApply(in, out data.Map) bool {
 	var state data.Map = map[string]interface{}{
 		"in":  in,
@@ -184,18 +229,24 @@ func (v *Variable) replaceValue(value interface{}) interface{} {
 	return text
 }
 
-func (v *Variable) applyElse(in, out data.Map) interface{} {
-	value := v.getElse(in)
+func (v *Variable) applyElse(in, out data.Map) (interface{}, error) {
+	value, err := v.normalize(v.getElse(in), in)
+	if err != nil {
+		return nil, err
+	}
 	if v.Name != "" {
 		out.SetValue(v.Name, value)
 	}
-	return value
+	return value, nil
 }
 
 func (v *Variable) Apply(in, out data.Map) error {
 	if v.When != "" {
 		if !v.canApply(in, out) {
-			value := v.applyElse(in, out)
+			value, err := v.applyElse(in, out)
+			if err != nil {
+				return err
+			}
 			return v.validate(value, in)
 		}
 	}
@@ -207,7 +258,9 @@ func (v *Variable) Apply(in, out data.Map) error {
 	if value == nil || (v.Required && toolbox.AsString(value) == "") {
 		value = v.getValue(in)
 	}
-
+	if value, err = v.normalize(value, in); err != nil {
+		return err
+	}
 	if err := v.validate(value, in); err != nil {
 		return err
 	}
@@ -236,6 +289,59 @@ func NewVariable(name, form, when string, required bool, value, elseValue interf
 //Variables a slice of variables
 type Variables []*Variable
 
+//SecretValues returns applied secret variables text values, nested map and slice values are included
+func (v Variables) SecretValues(out data.Map) []string {
+	var result = make([]string, 0)
+	for _, variable := range v {
+		if variable == nil || !variable.Secret || variable.Name == "" {
+			continue
+		}
+		if value, ok := out.GetValue(variable.Name); ok {
+			result = appendSecretValues(result, value)
+		}
+	}
+	return result
+}
+
+//secretLiterals returns secret variables values that do not reference state
+func (v Variables) secretLiterals() []string {
+	var result = make([]string, 0)
+	for _, variable := range v {
+		if variable == nil || !variable.Secret {
+			continue
+		}
+		for _, value := range appendSecretValues(nil, variable.Value) {
+			if !strings.Contains(value, "$") {
+				result = append(result, value)
+			}
+		}
+	}
+	return result
+}
+
+func appendSecretValues(result []string, value interface{}) []string {
+	switch actual := value.(type) {
+	case nil:
+	case string:
+		result = append(result, actual)
+	default:
+		if toolbox.IsMap(value) {
+			_ = toolbox.ProcessMap(value, func(key, item interface{}) bool {
+				result = appendSecretValues(result, item)
+				return true
+			})
+		} else if toolbox.IsSlice(value) {
+			toolbox.ProcessSlice(value, func(item interface{}) bool {
+				result = appendSecretValues(result, item)
+				return true
+			})
+		} else {
+			result = append(result, toolbox.AsString(value))
+		}
+	}
+	return result
+}
+
 //Apply evaluates all variable from in map to out map
 func (v *Variables) Apply(in, out data.Map) error {
 	if out == nil {
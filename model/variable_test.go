@@ -6,6 +6,7 @@ import (
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
 	"io/ioutil"
+	"sort"
 	"testing"
 )
 
@@ -176,6 +177,78 @@ func TestVariable_Apply(t *testing.T) {
 
 }
 
+func TestVariable_ApplyTyped(t *testing.T) {
+	var useCases = []struct {
+		description string
+		variable    *Variable
+		input       map[string]interface{}
+		expect      interface{}
+		hasError    string
+	}{
+		{
+			description: "converted int",
+			variable:    &Variable{Name: "port", Value: "$params.port", Type: "int"},
+			input:       map[string]interface{}{"params": map[string]interface{}{"port": "8080"}},
+			expect:      8080,
+		},
+		{
+			description: "default for unexpanded value",
+			variable:    &Variable{Name: "port", Value: "$params.port", Type: "int", Default: 8080},
+			input:       map[string]interface{}{"params": map[string]interface{}{}},
+			expect:      8080,
+		},
+		{
+			description: "expanded default for missing from",
+			variable:    &Variable{Name: "host", From: "params.host", Default: "$defaultHost"},
+			input:       map[string]interface{}{"defaultHost": "127.0.0.1"},
+			expect:      "127.0.0.1",
+		},
+		{
+			description: "default for else value",
+			variable:    &Variable{Name: "debug", When: "$mode = debug", Value: true, Type: "bool", Default: false},
+			input:       map[string]interface{}{"mode": "prod"},
+			expect:      false,
+		},
+		{
+			description: "type mismatch",
+			variable:    &Variable{Name: "port", Value: "$params.port", Type: "int"},
+			input:       map[string]interface{}{"params": map[string]interface{}{"port": "abc"}},
+			hasError:    "variable 'port'",
+		},
+		{
+			description: "map type mismatch",
+			variable:    &Variable{Name: "app", From: "params.app", Type: "map"},
+			input:       map[string]interface{}{"params": map[string]interface{}{"app": "myapp"}},
+			hasError:    "expected map",
+		},
+		{
+			description: "required without default",
+			variable:    &Variable{Name: "host", From: "params.host", Type: "string", Required: true},
+			input:       map[string]interface{}{"params": map[string]interface{}{}},
+			hasError:    "variable 'host' is required",
+		},
+		{
+			description: "unsupported type",
+			variable:    &Variable{Name: "host", Value: "localhost", Type: "text"},
+			hasError:    "unsupported host type",
+		},
+	}
+	for _, useCase := range useCases {
+		var output = data.NewMap()
+		err := useCase.variable.Apply(data.Map(useCase.input), output)
+		if useCase.hasError != "" {
+			if assert.NotNil(t, err, useCase.description) {
+				assert.Contains(t, err.Error(), useCase.hasError, useCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.expect, output.Get(useCase.variable.Name), useCase.description)
+	}
+}
+
 func TestVariable_PersistValue(t *testing.T) {
 
 	var var1 = NewVariable("key1", "", "", false, "123", nil, nil, false)
@@ -373,3 +446,23 @@ func TestGetVariables(t *testing.T) {
 	}
 
 }
+
+func TestVariables_SecretValues(t *testing.T) {
+	var variables Variables = []*Variable{
+		{Name: "password", Secret: true},
+		{Name: "user"},
+		{Name: "keys", Secret: true},
+		{Name: "missing", Secret: true},
+	}
+	var state = data.Map(map[string]interface{}{
+		"password": "p@ss",
+		"user":     "tester",
+		"keys": map[string]interface{}{
+			"primary":   "key1",
+			"secondary": []interface{}{"key2", 3},
+		},
+	})
+	values := variables.SecretValues(state)
+	sort.Strings(values)
+	assert.EqualValues(t, []string{"3", "key1", "key2", "p@ss"}, values)
+}
@@ -1,19 +1,53 @@
 package model
 
 import (
+	"fmt"
 	"github.com/pkg/errors"
 	"github.com/viant/toolbox/data"
 	"github.com/viant/toolbox/url"
+	"strings"
 )
 
 //Workflow represents a workflow
 type Workflow struct {
-	Source *url.Resource //source definition of the workflow
-	Data   data.Map      //workflow data
+	Source   *url.Resource //source definition of the workflow
+	Data     data.Map      //workflow data
+	Contract StateContract //optional declared state contract validated at task boundaries
+	Inputs   StateContract //optional declared workflow parameters validated before the workflow runs
+	Outputs  StateContract //optional declared workflow outputs, only declared outputs are published to the caller
+	Endly    string        //optional minimum required endly version
 	*AbstractNode
 	*TasksNode //workflow tasks
 }
 
+//SecretLiterals returns secret variables literal values defined in the workflow, task and action init and post instructions
+func (w *Workflow) SecretLiterals() []string {
+	var result = make([]string, 0)
+	var appendNode = func(node *AbstractNode) {
+		if node == nil {
+			return
+		}
+		result = append(result, node.Init.secretLiterals()...)
+		result = append(result, node.Post.secretLiterals()...)
+	}
+	var appendTasks func(node *TasksNode)
+	appendTasks = func(node *TasksNode) {
+		if node == nil {
+			return
+		}
+		for _, task := range node.Tasks {
+			appendNode(task.AbstractNode)
+			for _, action := range task.Actions {
+				appendNode(action.AbstractNode)
+			}
+			appendTasks(task.TasksNode)
+		}
+	}
+	appendNode(w.AbstractNode)
+	appendTasks(w.TasksNode)
+	return result
+}
+
 //Validate validates this workflow
 func (w *Workflow) Init() error {
 	for _, task := range w.Tasks {
@@ -42,6 +76,55 @@ func (w *Workflow) Validate() error {
 			return err
 		}
 	}
+	return w.validateTargets(w.TasksNode)
+}
+
+//ValidateInputs checks supplied parameters against declared workflow inputs
+func (w *Workflow) ValidateInputs(params map[string]interface{}) error {
+	if len(w.Inputs) == 0 {
+		return nil
+	}
+	if violations := w.Inputs.violations(data.Map(params), nil); len(violations) > 0 {
+		return fmt.Errorf("invalid %v workflow inputs: %v", w.Name, strings.Join(violations, ", "))
+	}
+	return nil
+}
+
+//SelectOutputs returns declared outputs from supplied workflow post data, all data is returned if outputs were not declared
+func (w *Workflow) SelectOutputs(out map[string]interface{}) (map[string]interface{}, error) {
+	if len(w.Outputs) == 0 {
+		return out, nil
+	}
+	var source = data.Map(out)
+	var result = data.NewMap()
+	for _, field := range w.Outputs {
+		if value, has := source.GetValue(field.Name); has {
+			result.SetValue(field.Name, value)
+		}
+	}
+	if violations := w.Outputs.violations(source, nil); len(violations) > 0 {
+		return result, fmt.Errorf("invalid %v workflow outputs: %v", w.Name, strings.Join(violations, ", "))
+	}
+	return result, nil
+}
+
+func (w *Workflow) validateTargets(node *TasksNode) error {
+	if node == nil {
+		return nil
+	}
+	for _, task := range node.Tasks {
+		for _, target := range task.Targets() {
+			if _, err := w.Task(target); err != nil {
+				return fmt.Errorf("invalid %v task branch: %v", task.Name, err)
+			}
+		}
+		if task.Switch != nil && task.Switch.SourceKey == "" {
+			return fmt.Errorf("invalid %v task switch: sourceKey was empty", task.Name)
+		}
+		if err := w.validateTargets(task.TasksNode); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -193,10 +193,8 @@ func (s *service) listenInTheBackground(context *endly.Context, request *ListenR
 				return
 
 			}
-		case <-time.After(time.Second):
-			if context.IsClosed() {
-				return
-			}
+		case <-context.Done():
+			return
 		}
 	}
 }
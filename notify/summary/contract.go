@@ -0,0 +1,165 @@
+package summary
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	//ChannelSlack represents slack channel kind
+	ChannelSlack = "slack"
+	//ChannelWebhook represents generic webhook channel kind
+	ChannelWebhook = "webhook"
+	//ChannelEmail represents email channel kind
+	ChannelEmail = "email"
+
+	//WhenAlways notifies regardless run status
+	WhenAlways = "always"
+	//WhenSuccess notifies only successful run
+	WhenSuccess = "success"
+	//WhenFailure notifies only failed run
+	WhenFailure = "failure"
+
+	//StatusSuccess represents successful run status
+	StatusSuccess = "success"
+	//StatusFailure represents failed run status
+	StatusFailure = "failure"
+
+	//RunSummaryKey represents state key with top level workflow run summary published by workflow completion hooks
+	RunSummaryKey = "runSummary"
+)
+
+//FailedValidation represents failed validations by TagID
+type FailedValidation struct {
+	TagID    string
+	Failed   int
+	Failures []string `json:",omitempty"`
+}
+
+//Summary represents workflow run summary
+type Summary struct {
+	Workflow          string
+	SessionID         string
+	Status            string
+	Error             string `json:",omitempty"`
+	StartTime         time.Time
+	DurationMs        int
+	Passed            int
+	Failed            int
+	FailedValidations []*FailedValidation `json:",omitempty"`
+	EventLogURL       string              `json:",omitempty"`
+}
+
+//Succeeded returns true if run did not fail
+func (s *Summary) Succeeded() bool {
+	return s.Status != StatusFailure && s.Error == "" && s.Failed == 0
+}
+
+//Channel represents notification channel
+type Channel struct {
+	Kind        string            `required:"true" description:"channel kind: slack, webhook or email"`
+	When        string            `description:"always (default), success or failure"`
+	Credentials string            `description:"slack or SMTP credentials"`
+	Channel     string            `description:"slack channel"`
+	URL         string            `description:"webhook URL or SMTP endpoint, i.e. smtp://smtp.gmail.com:465"`
+	Headers     map[string]string `description:"optional webhook HTTP headers"`
+	From        string            `description:"email sender"`
+	To          []string          `description:"email recipients"`
+}
+
+//Matches returns true if channel should be notified for supplied summary
+func (c *Channel) Matches(summary *Summary) bool {
+	switch c.When {
+	case WhenSuccess:
+		return summary.Succeeded()
+	case WhenFailure:
+		return !summary.Succeeded()
+	}
+	return true
+}
+
+//Init initialises channel
+func (c *Channel) Init() error {
+	c.Kind = strings.ToLower(c.Kind)
+	c.When = strings.ToLower(c.When)
+	if c.When == "" {
+		c.When = WhenAlways
+	}
+	return nil
+}
+
+//Validate checks if channel is valid
+func (c *Channel) Validate() error {
+	switch c.When {
+	case WhenAlways, WhenSuccess, WhenFailure:
+	default:
+		return fmt.Errorf("unsupported %v channel when: %v", c.Kind, c.When)
+	}
+	switch c.Kind {
+	case ChannelSlack:
+		if c.Credentials == "" {
+			return errors.New("slack credentials were empty")
+		}
+		if c.Channel == "" {
+			return errors.New("slack channel was empty")
+		}
+	case ChannelWebhook:
+		if c.URL == "" {
+			return errors.New("webhook URL was empty")
+		}
+	case ChannelEmail:
+		if c.URL == "" {
+			return errors.New("email SMTP URL was empty")
+		}
+		if c.Credentials == "" {
+			return errors.New("email credentials were empty")
+		}
+		if c.From == "" {
+			return errors.New("email from was empty")
+		}
+		if len(c.To) == 0 {
+			return errors.New("email to was empty")
+		}
+	default:
+		return fmt.Errorf("unsupported channel kind: '%v'", c.Kind)
+	}
+	return nil
+}
+
+//SendRequest represents run summary notification request
+type SendRequest struct {
+	Title    string     `description:"optional notification title, by default it is built from workflow name and run status"`
+	Summary  *Summary   `description:"run summary, $runSummary published by workflow completion hook is used by default"`
+	Channels []*Channel `required:"true" description:"notification channels"`
+}
+
+//SendResponse represents run summary notification response
+type SendResponse struct {
+	Notified []string `description:"notified channel kinds"`
+	Skipped  []string `description:"channel kinds skipped by when condition"`
+}
+
+//Init initialises request
+func (r *SendRequest) Init() error {
+	for _, channel := range r.Channels {
+		if err := channel.Init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *SendRequest) Validate() error {
+	if len(r.Channels) == 0 {
+		return errors.New("channels were empty")
+	}
+	for i, channel := range r.Channels {
+		if err := channel.Validate(); err != nil {
+			return fmt.Errorf("channels[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
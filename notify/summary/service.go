@@ -0,0 +1,230 @@
+package summary
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/notify/slack"
+	"github.com/viant/endly/notify/smtp"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	//ServiceID represents run summary notification service id
+	ServiceID = "notify"
+
+	maxReportedFailures = 3
+	webhookTimeout      = 30 * time.Second
+)
+
+//service represents run summary notification service
+type service struct {
+	*endly.AbstractService
+}
+
+//title returns notification title
+func title(request *SendRequest, summary *Summary) string {
+	if request.Title != "" {
+		return request.Title
+	}
+	status := "succeeded"
+	if !summary.Succeeded() {
+		status = "failed"
+	}
+	return fmt.Sprintf("endly workflow %v %v", summary.Workflow, status)
+}
+
+//Text returns plain text run summary
+func (s *Summary) Text(title string) string {
+	var lines = []string{
+		fmt.Sprintf("%v in %v", title, time.Duration(s.DurationMs)*time.Millisecond),
+		fmt.Sprintf("session: %v", s.SessionID),
+	}
+	if s.Error != "" {
+		lines = append(lines, fmt.Sprintf("error: %v", s.Error))
+	}
+	lines = append(lines, fmt.Sprintf("validations: passed %v, failed %v", s.Passed, s.Failed))
+	for _, validation := range s.FailedValidations {
+		lines = append(lines, fmt.Sprintf(" - %v: %v failed", validation.TagID, validation.Failed))
+		for i, failure := range validation.Failures {
+			if i == maxReportedFailures {
+				lines = append(lines, fmt.Sprintf("   ... %v more", len(validation.Failures)-i))
+				break
+			}
+			lines = append(lines, "   "+failure)
+		}
+	}
+	if s.EventLogURL != "" {
+		lines = append(lines, fmt.Sprintf("event log: %v", s.EventLogURL))
+	}
+	return strings.Join(lines, "\n")
+}
+
+//runSummary returns request summary or run summary published by workflow completion hook
+func (s *service) runSummary(context *endly.Context, request *SendRequest) (*Summary, error) {
+	if request.Summary != nil {
+		return request.Summary, nil
+	}
+	state := context.SafeState()
+	value, ok := state.GetValue(RunSummaryKey)
+	if !ok || value == nil {
+		return nil, fmt.Errorf("summary was empty and %v was not found in state", RunSummaryKey)
+	}
+	var result = &Summary{}
+	if err := toolbox.DefaultConverter.AssignConverted(result, value); err != nil {
+		return nil, fmt.Errorf("invalid %v: %v", RunSummaryKey, err)
+	}
+	return result, nil
+}
+
+func (s *service) notifySlack(context *endly.Context, channel *Channel, title string, summary *Summary) error {
+	request := &slack.PostRequest{
+		Credentials: channel.Credentials,
+		Channel:     channel.Channel,
+		Messages:    []*slack.Message{{Text: summary.Text(title)}},
+	}
+	return endly.Run(context, request, &slack.PostResponse{})
+}
+
+func (s *service) notifyWebhook(context *endly.Context, channel *Channel, title string, summary *Summary) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"text":    summary.Text(title),
+		"title":   title,
+		"summary": summary,
+	})
+	if err != nil {
+		return err
+	}
+	httpRequest, err := http.NewRequest(http.MethodPost, context.Expand(channel.URL), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	httpRequest = httpRequest.WithContext(context.Background())
+	httpRequest.Header.Set("Content-Type", "application/json")
+	for key, value := range channel.Headers {
+		httpRequest.Header.Set(key, context.Expand(value))
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	httpResponse, err := client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+	if httpResponse.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(httpResponse.Body)
+		return fmt.Errorf("webhook responded with %v: %s", httpResponse.Status, body)
+	}
+	return nil
+}
+
+func (s *service) notifyEmail(context *endly.Context, channel *Channel, title string, summary *Summary) error {
+	request := &smtp.SendRequest{
+		Target: url.NewResource(channel.URL, channel.Credentials),
+		Mail: &smtp.Message{
+			From:        channel.From,
+			To:          channel.To,
+			Subject:     title,
+			Body:        summary.Text(title),
+			ContentType: "text/plain",
+		},
+	}
+	return endly.Run(context, request, &smtp.SendResponse{})
+}
+
+func (s *service) send(context *endly.Context, request *SendRequest) (*SendResponse, error) {
+	summary, err := s.runSummary(context, request)
+	if err != nil {
+		return nil, err
+	}
+	var response = &SendResponse{Notified: make([]string, 0), Skipped: make([]string, 0)}
+	title := title(request, summary)
+	var failed = make([]string, 0)
+	for _, channel := range request.Channels {
+		if !channel.Matches(summary) {
+			response.Skipped = append(response.Skipped, channel.Kind)
+			continue
+		}
+		var notify = s.notifyWebhook
+		switch channel.Kind {
+		case ChannelSlack:
+			notify = s.notifySlack
+		case ChannelEmail:
+			notify = s.notifyEmail
+		}
+		if err := notify(context, channel, title, summary); err != nil {
+			failed = append(failed, fmt.Sprintf("%v: %v", channel.Kind, err))
+			continue
+		}
+		response.Notified = append(response.Notified, channel.Kind)
+	}
+	if len(failed) > 0 {
+		return response, fmt.Errorf("failed to notify %v", strings.Join(failed, "; "))
+	}
+	return response, nil
+}
+
+const sendExample = `{
+  "Channels": [
+    {
+      "Kind": "slack",
+      "When": "failure",
+      "Credentials": "slack",
+      "Channel": "#e2e"
+    },
+    {
+      "Kind": "webhook",
+      "URL": "https://ci.mycompany.com/hooks/endly"
+    },
+    {
+      "Kind": "email",
+      "When": "failure",
+      "URL": "smtp://smtp.gmail.com:465",
+      "Credentials": "smtp",
+      "From": "e2e@mycompany.com",
+      "To": ["team@mycompany.com"]
+    }
+  ]
+}`
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "send",
+		RequestInfo: &endly.ActionInfo{
+			Description: "send workflow run summary to slack, webhook or email channels",
+			Examples: []*endly.UseCase{
+				{
+					Description: "run summary notification",
+					Data:        sendExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &SendRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &SendResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*SendRequest); ok {
+				return s.send(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+//New creates a new run summary notification service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
@@ -0,0 +1,94 @@
+package summary
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestService_Send(t *testing.T) {
+	var payloads = make([]map[string]interface{}, 0)
+	var headers = make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		var payload = map[string]interface{}{}
+		_ = json.Unmarshal(body, &payload)
+		payloads = append(payloads, payload)
+		headers = append(headers, request.Header.Get("X-Token"))
+		if request.URL.Path == "/fail" {
+			writer.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	context := endly.New().NewContext(nil)
+	context.SafeState().SetValue("token", "abc")
+	context.SafeState().SetValue(RunSummaryKey, map[string]interface{}{
+		"Workflow":   "e2e",
+		"SessionID":  "s1",
+		"Status":     StatusFailure,
+		"DurationMs": 61000,
+		"Passed":     3,
+		"Failed":     1,
+		"FailedValidations": []interface{}{
+			map[string]interface{}{"TagID": "Test2", "Failed": 1, "Failures": []interface{}{"/id: expected 1 but had 2"}},
+		},
+		"EventLogURL": "file:///tmp/logs/s1",
+	})
+
+	response := &SendResponse{}
+	err := endly.Run(context, &SendRequest{Channels: []*Channel{
+		{Kind: "webhook", When: "failure", URL: server.URL, Headers: map[string]string{"X-Token": "$token"}},
+		{Kind: "webhook", When: "success", URL: server.URL},
+	}}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []string{ChannelWebhook}, response.Notified)
+	assert.Equal(t, []string{ChannelWebhook}, response.Skipped)
+	if assert.Equal(t, 1, len(payloads)) {
+		assert.Equal(t, "abc", headers[0])
+		assert.Equal(t, "endly workflow e2e failed", payloads[0]["title"])
+		assert.Equal(t, "endly workflow e2e failed in 1m1s\nsession: s1\nvalidations: passed 3, failed 1\n - Test2: 1 failed\n   /id: expected 1 but had 2\nevent log: file:///tmp/logs/s1", payloads[0]["text"])
+		summary, _ := payloads[0]["summary"].(map[string]interface{})
+		assert.EqualValues(t, 1, summary["Failed"])
+	}
+
+	err = endly.Run(context, &SendRequest{Title: "nightly", Summary: &Summary{Workflow: "e2e", Passed: 1}, Channels: []*Channel{
+		{Kind: "webhook", When: "success", URL: server.URL},
+		{Kind: "webhook", URL: server.URL + "/fail"},
+	}}, response)
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{ChannelWebhook}, response.Notified)
+	if assert.Equal(t, 3, len(payloads)) {
+		assert.Equal(t, "nightly", payloads[1]["title"])
+	}
+}
+
+func TestSendRequest_Validate(t *testing.T) {
+	var useCases = []struct {
+		description string
+		channel     *Channel
+		hasError    bool
+	}{
+		{description: "webhook", channel: &Channel{Kind: "Webhook", URL: "http://localhost/"}},
+		{description: "webhook without URL", channel: &Channel{Kind: "webhook"}, hasError: true},
+		{description: "slack", channel: &Channel{Kind: "slack", Credentials: "slack", Channel: "#e2e", When: "failure"}},
+		{description: "slack without channel", channel: &Channel{Kind: "slack", Credentials: "slack"}, hasError: true},
+		{description: "email", channel: &Channel{Kind: "email", URL: "smtp://smtp.gmail.com:465", Credentials: "smtp", From: "e2e@localhost", To: []string{"team@localhost"}}},
+		{description: "email without recipients", channel: &Channel{Kind: "email", URL: "smtp://smtp.gmail.com:465", Credentials: "smtp", From: "e2e@localhost"}, hasError: true},
+		{description: "unsupported when", channel: &Channel{Kind: "webhook", URL: "http://localhost/", When: "never"}, hasError: true},
+		{description: "unsupported kind", channel: &Channel{Kind: "pager"}, hasError: true},
+	}
+	for _, useCase := range useCases {
+		request := &SendRequest{Channels: []*Channel{useCase.channel}}
+		assert.Nil(t, request.Init(), useCase.description)
+		err := request.Validate()
+		assert.Equal(t, useCase.hasError, err != nil, useCase.description)
+	}
+	assert.NotNil(t, (&SendRequest{}).Validate())
+}
@@ -3,11 +3,29 @@ package endly
 import (
 	"github.com/pkg/errors"
 	"github.com/viant/toolbox/data"
+	"sync"
 )
 
 //UdfRegistry represents a udf registry
 var UdfRegistry = make(map[string]func(source interface{}, state data.Map) (interface{}, error))
 
+var udfRegistryMux = &sync.RWMutex{}
+
+//RegisterUdf registers udf at run time, it is safe for concurrent use
+func RegisterUdf(id string, udf func(source interface{}, state data.Map) (interface{}, error)) {
+	udfRegistryMux.Lock()
+	defer udfRegistryMux.Unlock()
+	UdfRegistry[id] = udf
+}
+
+//LookupUdf returns registered udf for supplied id
+func LookupUdf(id string) (func(source interface{}, state data.Map) (interface{}, error), bool) {
+	udfRegistryMux.RLock()
+	defer udfRegistryMux.RUnlock()
+	udf, ok := UdfRegistry[id]
+	return udf, ok
+}
+
 //UdfRegistryProvider represents udf registry provider (i.e. to register parameterized udf dynamically)
 var UdfRegistryProvider = make(map[string]func(args ...interface{}) (func(source interface{}, state data.Map) (interface{}, error), error))
 
@@ -36,3 +54,9 @@ var registry ServiceRegistry = make([]ServiceProvider, 0)
 
 //Registry global service provider registry
 var Registry = &registry
+
+//RegisterServiceProvider registers third party service provider, it has to be called before manager is created (i.e. in package init),
+//service with already registered ID overrides the previous one
+func RegisterServiceProvider(provider ServiceProvider) error {
+	return Registry.Register(provider)
+}
@@ -0,0 +1,361 @@
+package endly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/cred"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+//AmbientCredentials represents credentials name resolved from the cloud environment (i.e. EC2/ECS/EKS role, GKE workload identity)
+//instead of a credentials file, empty credentials resolve the same way for cloud services and storage
+const AmbientCredentials = "ambient"
+
+//IsAmbientCredentials returns true if supplied credentials should be resolved from the cloud environment
+func IsAmbientCredentials(credentials string) bool {
+	return credentials == "" || credentials == AmbientCredentials
+}
+
+//MaskedValue represents masked secret or sensitive value placeholder
+const MaskedValue = "*****"
+
+//SensitiveKeys represents lower case key fragments identifying sensitive values
+var SensitiveKeys = []string{"password", "passwd", "secret", "token", "apikey", "privatekey"}
+
+//IsSensitiveKey returns true if key looks like a sensitive one
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, candidate := range SensitiveKeys {
+		if strings.Contains(key, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+//SecretProvider represents a pluggable secret value provider (i.e. env, file, vault, cloud secret manager)
+type SecretProvider interface {
+	//Scheme returns provider scheme used in secret reference i.e. env:DB_PASSWORD
+	Scheme() string
+	//Secret returns secret value for supplied key
+	Secret(ctx context.Context, key string) (string, error)
+}
+
+var secretProviders = make(map[string]SecretProvider)
+var secretProvidersMux = &sync.RWMutex{}
+
+//RegisterSecretProvider registers secret provider, typically called from provider package init
+func RegisterSecretProvider(provider SecretProvider) {
+	secretProvidersMux.Lock()
+	defer secretProvidersMux.Unlock()
+	secretProviders[provider.Scheme()] = provider
+}
+
+//LookupSecretProvider returns secret provider for supplied scheme
+func LookupSecretProvider(scheme string) (SecretProvider, error) {
+	secretProvidersMux.RLock()
+	defer secretProvidersMux.RUnlock()
+	if provider, ok := secretProviders[scheme]; ok {
+		return provider, nil
+	}
+	return nil, fmt.Errorf("failed to lookup secret provider: %v", scheme)
+}
+
+//SecretValues represents resolved secret values tracker, used to mask secrets uniformly across events, stdin/stdout echo and logs
+type SecretValues struct {
+	mux    *sync.RWMutex
+	values map[string]bool
+	masked map[string]bool
+	sorted []string
+}
+
+//escapedSecretForms returns secret value with its JSON escaped forms, so that secrets are also masked in already marshalled JSON
+func escapedSecretForms(value string) []string {
+	var result = []string{value}
+	for _, escapeHTML := range []bool{true, false} {
+		buffer := new(bytes.Buffer)
+		encoder := json.NewEncoder(buffer)
+		encoder.SetEscapeHTML(escapeHTML)
+		if err := encoder.Encode(value); err == nil {
+			result = append(result, strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(buffer.String()), `"`), `"`))
+		}
+	}
+	var ascii = new(strings.Builder)
+	for _, code := range utf16.Encode([]rune(result[len(result)-1])) {
+		if code < utf8.RuneSelf {
+			ascii.WriteByte(byte(code))
+			continue
+		}
+		ascii.WriteString(fmt.Sprintf(`\u%04x`, code))
+	}
+	return append(result, ascii.String())
+}
+
+//Add tracks supplied secret values
+func (s *SecretValues) Add(values ...string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, value := range values {
+		if len(value) < 3 || s.values[value] {
+			continue
+		}
+		s.values[value] = true
+		for _, form := range escapedSecretForms(value) {
+			if s.masked[form] {
+				continue
+			}
+			s.masked[form] = true
+			s.sorted = append(s.sorted, form)
+		}
+	}
+	//replace the longest values first
+	sort.Slice(s.sorted, func(i, j int) bool {
+		return len(s.sorted[i]) > len(s.sorted[j])
+	})
+}
+
+//Mask replaces tracked secret values and their JSON escaped forms with masked placeholder
+func (s *SecretValues) Mask(text string) string {
+	if s == nil || text == "" {
+		return text
+	}
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	for _, value := range s.sorted {
+		text = strings.Replace(text, value, MaskedValue, -1)
+	}
+	return text
+}
+
+//MaskValue returns a copy of source with tracked secret values and values under sensitive keys masked, maps and slices are masked recursively
+func (s *SecretValues) MaskValue(source interface{}) interface{} {
+	switch value := source.(type) {
+	case nil:
+		return nil
+	case string:
+		return s.Mask(value)
+	case map[string]interface{}:
+		var result = make(map[string]interface{}, len(value))
+		for k, v := range value {
+			if IsSensitiveKey(k) && v != nil && !toolbox.IsMap(v) && !toolbox.IsSlice(v) {
+				result[k] = MaskedValue
+				continue
+			}
+			result[k] = s.MaskValue(v)
+		}
+		return result
+	case []interface{}:
+		var result = make([]interface{}, len(value))
+		for i, v := range value {
+			result[i] = s.MaskValue(v)
+		}
+		return result
+	}
+	if toolbox.IsMap(source) {
+		return s.MaskValue(toolbox.AsMap(source))
+	}
+	if toolbox.IsSlice(source) {
+		return s.MaskValue(toolbox.AsSlice(source))
+	}
+	return source
+}
+
+//Redact returns a copy of source without tracked secret values and values under sensitive keys, nested maps are redacted recursively and slices are masked
+func (s *SecretValues) Redact(source map[string]interface{}) map[string]interface{} {
+	var result = make(map[string]interface{}, len(source))
+	for k, v := range source {
+		switch {
+		case toolbox.IsMap(v):
+			result[k] = s.Redact(toolbox.AsMap(v))
+		case toolbox.IsSlice(v):
+			result[k] = s.MaskValue(v)
+		case v != nil && IsSensitiveKey(k):
+		case toolbox.IsString(v) && s.Mask(toolbox.AsString(v)) != toolbox.AsString(v):
+		default:
+			result[k] = v
+		}
+	}
+	return result
+}
+
+//Len returns number of tracked secrets
+func (s *SecretValues) Len() int {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return len(s.values)
+}
+
+//NewSecretValues creates a secret values tracker
+func NewSecretValues() *SecretValues {
+	return &SecretValues{
+		mux:    &sync.RWMutex{},
+		values: make(map[string]bool),
+		masked: make(map[string]bool),
+		sorted: make([]string, 0),
+	}
+}
+
+//parseSecretReference splits reference into provider scheme, key and optional JSON field i.e. env:DB_PASSWORD, vault://secret/db#password
+func parseSecretReference(reference string) (string, string, string, error) {
+	index := strings.Index(reference, ":")
+	if index == -1 {
+		return "", "", "", fmt.Errorf("invalid secret reference: %v, expected scheme:key", reference)
+	}
+	scheme := reference[:index]
+	key := strings.TrimPrefix(reference[index+1:], "//")
+	var field string
+	if index = strings.LastIndex(key, "#"); index != -1 {
+		key, field = key[:index], key[index+1:]
+	}
+	return scheme, key, field, nil
+}
+
+//secretField returns field of JSON secret value
+func secretField(reference, value, field string) (string, error) {
+	var aMap = make(map[string]interface{})
+	if err := json.Unmarshal([]byte(value), &aMap); err != nil {
+		return "", fmt.Errorf("failed to decode secret %v, %v", reference, err)
+	}
+	fieldValue, ok := aMap[field]
+	if !ok {
+		return "", fmt.Errorf("failed to lookup secret %v field", reference)
+	}
+	return toolbox.AsString(fieldValue), nil
+}
+
+//IsSecretReference returns true if supplied text is a registered secret provider reference, file scheme is excluded as it is also used for credentials file location
+func IsSecretReference(text string) bool {
+	scheme, _, _, err := parseSecretReference(text)
+	if err != nil || scheme == "file" {
+		return false
+	}
+	_, err = LookupSecretProvider(scheme)
+	return err == nil
+}
+
+//Secret resolves secret reference (scheme:key[#field]) with registered provider, resolved value is tracked for masking
+func (c *Context) Secret(reference string) (string, error) {
+	scheme, key, field, err := parseSecretReference(reference)
+	if err != nil {
+		return "", err
+	}
+	provider, err := LookupSecretProvider(scheme)
+	if err != nil {
+		return "", err
+	}
+	value, err := provider.Secret(c.Background(), key)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %v, %v", reference, err)
+	}
+	c.SecretValues().Add(value)
+	if field != "" {
+		if value, err = secretField(reference, value, field); err != nil {
+			return "", err
+		}
+		c.SecretValues().Add(value)
+	}
+	return value, nil
+}
+
+//Credentials returns credentials config, secret provider reference (i.e. vault://secret/db, awssm://db, gcpsm://project/db) is resolved with registered provider, otherwise credentials are loaded by secrets service
+func (c *Context) Credentials(credentials string) (*cred.Config, error) {
+	if !IsSecretReference(credentials) {
+		return c.trackCredentials(c.Secrets.GetCredentials(credentials))
+	}
+	return c.referencedCredentials(credentials)
+}
+
+//GetOrCreateCredentials returns credentials config, if credentials file does not exist and CLI is interactive, it is created
+func (c *Context) GetOrCreateCredentials(credentials string) (*cred.Config, error) {
+	if !IsSecretReference(credentials) {
+		return c.trackCredentials(c.Secrets.GetOrCreate(credentials))
+	}
+	return c.referencedCredentials(credentials)
+}
+
+func (c *Context) referencedCredentials(reference string) (*cred.Config, error) {
+	value, err := c.Secret(reference)
+	if err != nil {
+		return nil, err
+	}
+	var result = &cred.Config{Data: value}
+	if err = result.LoadFromReader(strings.NewReader(value), ".json"); err != nil {
+		return nil, fmt.Errorf("failed to decode %v credentials, %v", reference, err)
+	}
+	return c.trackCredentials(result, nil)
+}
+
+//trackCredentials tracks credentials sensitive values for masking
+func (c *Context) trackCredentials(config *cred.Config, err error) (*cred.Config, error) {
+	if err != nil || config == nil {
+		return config, err
+	}
+	c.SecretValues().Add(config.Password, config.Secret, config.Token, config.PrivateKey, config.PrivateKeyPassword)
+	return config, nil
+}
+
+//SecretValues returns resolved secret values tracker
+func (c *Context) SecretValues() *SecretValues {
+	if c.secretValues == nil {
+		c.secretValues = NewSecretValues()
+	}
+	return c.secretValues
+}
+
+//MaskSecrets replaces any resolved secret value in supplied text
+func (c *Context) MaskSecrets(text string) string {
+	return c.secretValues.Mask(text)
+}
+
+//MaskValue returns a copy of supplied value with resolved secret values and values under sensitive keys masked
+func (c *Context) MaskValue(value interface{}) interface{} {
+	return c.secretValues.MaskValue(value)
+}
+
+type envSecretProvider struct{}
+
+func (p *envSecretProvider) Scheme() string {
+	return "env"
+}
+
+func (p *envSecretProvider) Secret(ctx context.Context, key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("env variable %v was not defined", key)
+	}
+	return value, nil
+}
+
+type fileSecretProvider struct{}
+
+func (p *fileSecretProvider) Scheme() string {
+	return "file"
+}
+
+//Secret returns file content, relative key is resolved from ~/.secret directory
+func (p *fileSecretProvider) Secret(ctx context.Context, key string) (string, error) {
+	location := key
+	if !strings.HasPrefix(location, "/") {
+		location = path.Join(os.Getenv("HOME"), ".secret", key)
+	}
+	content, err := ioutil.ReadFile(location)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func init() {
+	RegisterSecretProvider(&envSecretProvider{})
+	RegisterSecretProvider(&fileSecretProvider{})
+}
@@ -0,0 +1,73 @@
+package endly_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"strings"
+	"testing"
+)
+
+func TestSecretValues_MaskValue(t *testing.T) {
+	var source = map[string]interface{}{
+		"URL": "ssh://127.0.0.1",
+		"Credentials": map[string]interface{}{
+			"Username": "bob",
+			"Password": "dev",
+		},
+		"Commands": []interface{}{
+			map[string]interface{}{"Command": "ls", "AuthToken": "abc"},
+			"mysql -p s3cr3t",
+		},
+	}
+	secrets := endly.NewSecretValues()
+	secrets.Add("s3cr3t")
+	actual := secrets.MaskValue(source)
+	assert.EqualValues(t, map[string]interface{}{
+		"URL": "ssh://127.0.0.1",
+		"Credentials": map[string]interface{}{
+			"Username": "bob",
+			"Password": endly.MaskedValue,
+		},
+		"Commands": []interface{}{
+			map[string]interface{}{"Command": "ls", "AuthToken": endly.MaskedValue},
+			"mysql -p *****",
+		},
+	}, actual)
+	assert.Equal(t, "dev", source["Credentials"].(map[string]interface{})["Password"])
+
+	var untracked *endly.SecretValues
+	assert.EqualValues(t, map[string]interface{}{"Token": endly.MaskedValue, "Command": "mysql -p s3cr3t"},
+		untracked.MaskValue(map[string]interface{}{"Token": "abc", "Command": "mysql -p s3cr3t"}))
+}
+
+func TestSecretValues_MaskJSON(t *testing.T) {
+	var secret = `p"a\ss<w>&rd-ö`
+	secrets := endly.NewSecretValues()
+	secrets.Add(secret)
+	assert.Equal(t, 1, secrets.Len())
+	var value = map[string]interface{}{"Output": "mysql -p " + secret}
+
+	compact, _ := json.Marshal(value)
+	indented, _ := json.MarshalIndent(value, "", "\t")
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	var useCases = []struct {
+		description string
+		text        string
+	}{
+		{description: "plain", text: "mysql -p " + secret},
+		{description: "marshalled", text: string(compact)},
+		{description: "indented", text: string(indented)},
+		{description: "without HTML escaping", text: buffer.String()},
+		{description: "ASCII escaped", text: `{"Output":"mysql -p p\"a\\ss<w>&rd-\u00f6"}`},
+	}
+	for _, useCase := range useCases {
+		masked := secrets.Mask(useCase.text)
+		assert.True(t, strings.Contains(masked, "mysql -p "+endly.MaskedValue), useCase.description+": "+masked)
+		assert.False(t, strings.Contains(masked, "ss"), useCase.description+": "+masked)
+	}
+}
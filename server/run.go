@@ -0,0 +1,198 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/workflow"
+	"github.com/viant/toolbox"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//RunURI represents asynchronous workflow run endpoint URI
+	RunURI = "/v1/workflow/run"
+	//RunStatusURI represents workflow run endpoints base URI, i.e. /v1/run/{sessionID}/status, /v1/run/{sessionID}/events
+	RunStatusURI = "/v1/run/"
+
+	//RunStatusRunning represents running workflow status
+	RunStatusRunning = "running"
+	//RunStatusSucceeded represents successfully completed workflow status
+	RunStatusSucceeded = "succeeded"
+	//RunStatusFailed represents failed workflow status
+	RunStatusFailed = "failed"
+
+	runEventHistory = 1000
+	runRetention    = time.Hour
+)
+
+//RunStatus represents workflow run status
+type RunStatus struct {
+	SessionID string
+	Workflow  string
+	Status    string
+	Error     string                 `json:",omitempty"`
+	StartTime time.Time
+	EndTime   *time.Time             `json:",omitempty"`
+	Data      map[string]interface{} `json:",omitempty"` //workflow run response data with secret values masked
+	Actions   []*RunActionStatus     `json:",omitempty"` //completed actions status in completion order
+}
+
+//RunActionStatus represents workflow run completed action status
+type RunActionStatus struct {
+	Workflow string
+	Task     string
+	TagID    string `json:",omitempty"`
+	Service  string
+	Action   string
+	Status   string
+	Error    string `json:",omitempty"`
+}
+
+//run represents workflow run started with the server
+type run struct {
+	mux     *sync.RWMutex
+	status  *RunStatus
+	stream  *workflow.EventStream
+	secrets *endly.SecretValues
+}
+
+func (r *run) Status() *RunStatus {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	var result = *r.status
+	result.Actions = make([]*RunActionStatus, len(r.status.Actions))
+	copy(result.Actions, r.status.Actions)
+	return &result
+}
+
+//track records completed action status from activity end event
+func (r *run) track(event msg.Event) {
+	endEvent, ok := event.Value().(*model.ActivityEndEvent)
+	if !ok {
+		return
+	}
+	activity, ok := endEvent.Response.(*model.Activity)
+	if !ok || activity.MetaTag == nil {
+		return
+	}
+	var status = &RunActionStatus{Workflow: activity.Caller, Task: activity.Task, TagID: activity.TagID, Service: activity.Service, Action: activity.Action, Status: RunStatusSucceeded}
+	if activity.Error != "" {
+		status.Status = RunStatusFailed
+		status.Error = r.secrets.Mask(strings.TrimSpace(activity.Error))
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.status.Actions = append(r.status.Actions, status)
+}
+
+func (r *run) complete(response *workflow.RunResponse, err error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	var now = time.Now()
+	r.status.EndTime = &now
+	r.status.Status = RunStatusSucceeded
+	if err != nil {
+		r.status.Status = RunStatusFailed
+		r.status.Error = r.secrets.Mask(err.Error())
+	}
+	if response != nil && response.Data != nil {
+		r.status.Data = toolbox.AsMap(r.secrets.MaskValue(response.Data))
+	}
+}
+
+func (r *run) expired() bool {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.status.EndTime != nil && time.Since(*r.status.EndTime) > runRetention
+}
+
+//startRun starts workflow asynchronously, its events are recorded for the run events endpoint
+func (s *Server) startRun(request *workflow.RunRequest) *RunStatus {
+	context := s.manager.NewContext(toolbox.NewContext())
+	stream := workflow.NewEventStream(context.Listener, context.MaskSecrets).WithHistory(runEventHistory)
+	var workflowName = request.Name
+	if workflowName == "" {
+		workflowName = request.URL
+	}
+	var result = &run{
+		mux:     &sync.RWMutex{},
+		status:  &RunStatus{SessionID: context.SessionID, Workflow: workflowName, Status: RunStatusRunning, StartTime: time.Now()},
+		stream:  stream,
+		secrets: context.SecretValues(),
+	}
+	streamListener := stream.AsEventListener()
+	context.SetListener(func(event msg.Event) {
+		result.track(event)
+		streamListener(event)
+	})
+	s.mux.Lock()
+	for sessionID, candidate := range s.runs {
+		if candidate.expired() {
+			delete(s.runs, sessionID)
+		}
+	}
+	s.runs[context.SessionID] = result
+	s.mux.Unlock()
+	go func() {
+		defer context.Close()
+		defer stream.Close()
+		var response = &workflow.RunResponse{}
+		err := endly.Run(context, request, response)
+		result.complete(response, err)
+	}()
+	return result.Status()
+}
+
+func (s *Server) lookupRun(sessionID string) *run {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.runs[sessionID]
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(value)
+}
+
+//handleRun starts workflow with POST JSON workflow.RunRequest, it responds with run status
+func (s *Server) handleRun(writer http.ResponseWriter, httpRequest *http.Request) {
+	if httpRequest.Method != http.MethodPost {
+		writeJSON(writer, http.StatusMethodNotAllowed, &Response{Status: "error", Error: fmt.Sprintf("unsupported method: %v", httpRequest.Method)})
+		return
+	}
+	var request = &workflow.RunRequest{PublishParameters: true}
+	if err := json.NewDecoder(httpRequest.Body).Decode(request); err != nil {
+		writeJSON(writer, http.StatusBadRequest, &Response{Status: "error", Error: fmt.Sprintf("invalid run request: %v", err)})
+		return
+	}
+	writeJSON(writer, http.StatusAccepted, s.startRun(request))
+}
+
+//handleRunStatus handles GET /v1/run/{sessionID}/status and GET /v1/run/{sessionID}/events
+func (s *Server) handleRunStatus(writer http.ResponseWriter, httpRequest *http.Request) {
+	fragments := strings.Split(strings.Trim(strings.TrimPrefix(httpRequest.URL.Path, RunStatusURI), "/"), "/")
+	if len(fragments) != 2 || httpRequest.Method != http.MethodGet {
+		writeJSON(writer, http.StatusNotFound, &Response{Status: "error", Error: fmt.Sprintf("unsupported: %v %v", httpRequest.Method, httpRequest.URL.Path)})
+		return
+	}
+	run := s.lookupRun(fragments[0])
+	if run == nil {
+		writeJSON(writer, http.StatusNotFound, &Response{Status: "error", Error: fmt.Sprintf("unknown session: %v", fragments[0])})
+		return
+	}
+	switch fragments[1] {
+	case "status":
+		writeJSON(writer, http.StatusOK, run.Status())
+	case "events":
+		run.stream.ServeHTTP(writer, httpRequest)
+	default:
+		writeJSON(writer, http.StatusNotFound, &Response{Status: "error", Error: fmt.Sprintf("unsupported: %v %v", httpRequest.Method, httpRequest.URL.Path)})
+	}
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/workflow"
+	"github.com/viant/toolbox"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer_Run(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	server := New("")
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	var useCases = []struct {
+		description   string
+		request       *workflow.RunRequest
+		status        string
+		expectError   string
+		expectData    map[string]interface{}
+		expectEvent   string
+		expectActions []*RunActionStatus
+	}{
+		{
+			description: "succeeded workflow",
+			request: &workflow.RunRequest{
+				URL:               path.Join(parent, "test/hello.yaml"),
+				Params:            map[string]interface{}{"name": "endly"},
+				PublishParameters: true,
+			},
+			status:      RunStatusSucceeded,
+			expectData:  map[string]interface{}{"greeting": "hello endly"},
+			expectEvent: "hello endly",
+		},
+		{
+			description: "failed workflow",
+			request: &workflow.RunRequest{
+				URL: path.Join(parent, "test/fail.yaml"),
+			},
+			status:      RunStatusFailed,
+			expectError: "expected failure",
+			expectEvent: "expected failure",
+		},
+		{
+			description: "masked secret data",
+			request: &workflow.RunRequest{
+				URL:               path.Join(parent, "test/secret.yaml"),
+				PublishParameters: true,
+			},
+			status:      RunStatusSucceeded,
+			expectData:  map[string]interface{}{"connection": "tester:*****", "apiToken": "*****"},
+			expectEvent: "calling with *****",
+		},
+		{
+			description: "masked secret error",
+			request: &workflow.RunRequest{
+				URL: path.Join(parent, "test/secret_fail.yaml"),
+			},
+			status:      RunStatusFailed,
+			expectError: "access denied for *****",
+			expectEvent: "access denied for *****",
+			expectActions: []*RunActionStatus{
+				{Workflow: "secret_fail", Task: "fail", Service: "workflow", Action: "fail", Status: RunStatusFailed, Error: "access denied for *****"},
+			},
+		},
+		{
+			description: "failed async action",
+			request: &workflow.RunRequest{
+				URL: path.Join(parent, "test/async_fail.yaml"),
+			},
+			status:      RunStatusFailed,
+			expectError: "migration failed",
+			expectEvent: "migration failed",
+			expectActions: []*RunActionStatus{
+				{Workflow: "async_fail", Task: "batch", Service: "workflow", Action: "print", Status: RunStatusSucceeded},
+				{Workflow: "async_fail", Task: "batch", Service: "workflow", Action: "fail", Status: RunStatusFailed, Error: "migration failed"},
+			},
+		},
+	}
+
+	for _, useCase := range useCases {
+		payload, err := json.Marshal(useCase.request)
+		assert.Nil(t, err, useCase.description)
+		httpResponse, err := http.Post(httpServer.URL+RunURI, "application/json", bytes.NewReader(payload))
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, http.StatusAccepted, httpResponse.StatusCode, useCase.description)
+		var status = &RunStatus{}
+		err = json.NewDecoder(httpResponse.Body).Decode(status)
+		_ = httpResponse.Body.Close()
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, RunStatusRunning, status.Status, useCase.description)
+		if !assert.NotEmpty(t, status.SessionID, useCase.description) {
+			continue
+		}
+
+		//events stream ends once workflow completes, events published before subscription are replayed
+		httpResponse, err = http.Get(httpServer.URL + RunStatusURI + status.SessionID + "/events")
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, "text/event-stream", httpResponse.Header.Get("Content-Type"), useCase.description)
+		events, err := ioutil.ReadAll(httpResponse.Body)
+		_ = httpResponse.Body.Close()
+		assert.Nil(t, err, useCase.description)
+		assert.True(t, strings.Contains(string(events), useCase.expectEvent), useCase.description)
+
+		for i := 0; i < 50; i++ {
+			if status = server.lookupRun(status.SessionID).Status(); status.Status != RunStatusRunning {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		httpResponse, err = http.Get(httpServer.URL + RunStatusURI + status.SessionID + "/status")
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		status = &RunStatus{}
+		err = json.NewDecoder(httpResponse.Body).Decode(status)
+		_ = httpResponse.Body.Close()
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.status, status.Status, useCase.description)
+		assert.NotNil(t, status.EndTime, useCase.description)
+		if useCase.expectError != "" {
+			assert.True(t, strings.Contains(status.Error, useCase.expectError), useCase.description+" "+status.Error)
+		}
+		for key, value := range useCase.expectData {
+			assert.Equal(t, value, status.Data[key], useCase.description)
+		}
+		if len(useCase.expectActions) > 0 {
+			assert.Equal(t, len(useCase.expectActions), len(status.Actions), useCase.description)
+		}
+		for _, expected := range useCase.expectActions {
+			var actual *RunActionStatus
+			for _, candidate := range status.Actions {
+				if candidate.Task == expected.Task && candidate.Action == expected.Action {
+					actual = candidate
+				}
+			}
+			if !assert.NotNil(t, actual, useCase.description+" "+expected.Action) {
+				continue
+			}
+			assert.Equal(t, expected.Workflow, actual.Workflow, useCase.description)
+			assert.Equal(t, expected.Service, actual.Service, useCase.description)
+			assert.Equal(t, expected.Status, actual.Status, useCase.description)
+			assert.True(t, strings.HasPrefix(actual.Error, expected.Error), useCase.description+" "+actual.Error)
+		}
+		assert.False(t, strings.Contains(string(events)+status.Error+toolbox.AsString(status.Data), "k3y-abc123"), useCase.description)
+	}
+
+	httpResponse, err := http.Get(httpServer.URL + RunStatusURI + "unknown/status")
+	if assert.Nil(t, err) {
+		assert.Equal(t, http.StatusNotFound, httpResponse.StatusCode)
+		_ = httpResponse.Body.Close()
+	}
+	httpResponse, err = http.Post(httpServer.URL+RunURI, "application/json", strings.NewReader("{"))
+	if assert.Nil(t, err) {
+		assert.Equal(t, http.StatusBadRequest, httpResponse.StatusCode)
+		_ = httpResponse.Body.Close()
+	}
+}
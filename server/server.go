@@ -7,6 +7,7 @@ import (
 	"github.com/viant/toolbox"
 	"log"
 	"net/http"
+	"sync"
 )
 
 //Request represents service request.
@@ -27,6 +28,8 @@ type Response struct {
 type Server struct {
 	port    string
 	manager endly.Manager
+	mux     *sync.RWMutex
+	runs    map[string]*run
 }
 
 func (s *Server) requestService(serviceName, action string, httpRequest *http.Request, httpResponse http.ResponseWriter) (*Response, error) {
@@ -52,14 +55,15 @@ func (s *Server) requestService(serviceName, action string, httpRequest *http.Re
 	}
 
 	defer context.Close()
-	state := context.State()
+	state := context.SafeState()
 	state.Apply(request.Data)
 	serviceResponse := service.Run(context, request.ServiceRequest)
+	var data = state.Clone()
 	var response = &Response{
 		Status:   serviceResponse.Status,
 		Error:    serviceResponse.Error,
 		Response: serviceResponse.Response,
-		Data:     state.AsEncodableMap(),
+		Data:     data.AsEncodableMap(),
 	}
 	return response, nil
 }
@@ -98,9 +102,8 @@ func (s *Server) routeHandler(serviceRouting *toolbox.ServiceRouting, httpReques
 
 }
 
-//Start starts server
-func (s *Server) Start() error {
-
+//Handler returns server HTTP handler
+func (s *Server) Handler() http.Handler {
 	router := toolbox.NewServiceRouter(
 		toolbox.ServiceRouting{
 			HTTPMethod:     "POST",
@@ -109,15 +112,22 @@ func (s *Server) Start() error {
 			HandlerInvoker: s.routeHandler,
 			Parameters:     []string{"service", "action", "@httpRequest", "@httpResponseWriter"},
 		})
-
-	http.HandleFunc("/v1/", func(response http.ResponseWriter, reader *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/", func(response http.ResponseWriter, reader *http.Request) {
 		err := router.Route(response, reader)
 		if err != nil {
 			response.WriteHeader(http.StatusInternalServerError)
 		}
 	})
-	fmt.Printf("Started test server on port %v\n", s.port)
-	log.Fatal(http.ListenAndServe(":"+s.port, nil))
+	mux.HandleFunc(RunURI, s.handleRun)
+	mux.HandleFunc(RunStatusURI, s.handleRunStatus)
+	return mux
+}
+
+//Start starts server
+func (s *Server) Start() error {
+	fmt.Printf("Started endly server on port %v\n", s.port)
+	log.Fatal(http.ListenAndServe(":"+s.port, s.Handler()))
 	return nil
 }
 
@@ -126,5 +136,7 @@ func New(port string) *Server {
 	return &Server{
 		port:    port,
 		manager: endly.New(),
+		mux:     &sync.RWMutex{},
+		runs:    make(map[string]*run),
 	}
 }
@@ -22,6 +22,12 @@ type AbstractService struct {
 	actions        []string
 	id             string
 	state          data.Map
+	middlewares    []Middleware
+}
+
+//Use adds middleware applied to this service action routes
+func (s *AbstractService) Use(middleware ...Middleware) {
+	s.middlewares = append(s.middlewares, middleware...)
 }
 
 //Mutex returns a mutex.
@@ -88,6 +94,14 @@ func (s *AbstractService) Run(context *Context, request interface{}) (response *
 		}
 	}
 
+	if err = context.Err(); err != nil {
+		err = NewError(s.ID(), service.Action, err)
+		return response
+	}
+
+	heartbeat := context.beginHeartbeat(s.ID(), service.Action)
+	defer context.endHeartbeat(heartbeat)
+
 	if initializer, ok := request.(Initializer); ok {
 		if err = initializer.Init(); err != nil {
 			err = NewError(s.ID(), service.Action, fmt.Errorf("init %T failed: %v", request, err))
@@ -102,7 +116,8 @@ func (s *AbstractService) Run(context *Context, request interface{}) (response *
 		}
 	}
 
-	response.Response, err = service.Handler(context, request)
+	handler := chain(s.Service, service, service.Handler, globalMiddlewares(), s.middlewares)
+	response.Response, err = handler(context, request)
 	if err != nil {
 		var previous = err
 		err = NewError(s.ID(), service.Action, err)
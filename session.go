@@ -0,0 +1,188 @@
+package endly
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+//SessionDirectory represents persistent sessions directory
+var SessionDirectory = path.Join(os.Getenv("HOME"), ".endly", "session")
+
+var persistentSessionKey = (*PersistentSession)(nil)
+
+//SessionResource represents re-attachable session resource (i.e. opened target, registered log listener) with its request
+type SessionResource struct {
+	Service string
+	Action  string
+	Request interface{}
+}
+
+//PersistentSession represents context state and resources persisted across CLI invocations
+type PersistentSession struct {
+	ID        string
+	Updated   time.Time
+	State     map[string]interface{}
+	Resources []*SessionResource
+	mux       *sync.Mutex
+}
+
+//Track records re-attachable resource request
+func (s *PersistentSession) Track(service, action string, request interface{}) {
+	if s == nil {
+		return
+	}
+	var aMap = map[string]interface{}{}
+	if err := toolbox.DefaultConverter.AssignConverted(&aMap, request); err != nil {
+		return
+	}
+	requestMap := toolbox.DeleteEmptyKeys(aMap)
+	requestJSON, _ := toolbox.AsJSONText(requestMap)
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, resource := range s.Resources {
+		if resource.Service != service || resource.Action != action {
+			continue
+		}
+		if candidate, _ := toolbox.AsJSONText(resource.Request); candidate == requestJSON {
+			return
+		}
+	}
+	s.Resources = append(s.Resources, &SessionResource{Service: service, Action: action, Request: requestMap})
+}
+
+//Update updates tracked service action resource requests, resource is removed if update returns false
+func (s *PersistentSession) Update(service, action string, update func(request map[string]interface{}) bool) {
+	if s == nil {
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var resources = make([]*SessionResource, 0)
+	for _, resource := range s.Resources {
+		if resource.Service == service && resource.Action == action {
+			request := toolbox.AsMap(resource.Request)
+			if !update(request) {
+				continue
+			}
+			resource.Request = request
+		}
+		resources = append(resources, resource)
+	}
+	s.Resources = resources
+}
+
+//PersistentSession returns attached persistent session or nil
+func (c *Context) PersistentSession() *PersistentSession {
+	if !c.Contains(persistentSessionKey) {
+		return nil
+	}
+	var result *PersistentSession
+	c.GetInto(persistentSessionKey, &result)
+	return result
+}
+
+func sessionFilename(ID string) string {
+	return path.Join(SessionDirectory, ID+".json")
+}
+
+//validateSessionID returns an error if session ID can not be used as session file name
+func validateSessionID(ID string) error {
+	if ID == "" {
+		return fmt.Errorf("session ID was empty")
+	}
+	if strings.ContainsAny(ID, `/\`) || strings.Contains(ID, "..") {
+		return fmt.Errorf("invalid session ID: %v, path separators and '..' are not allowed", ID)
+	}
+	return nil
+}
+
+//AttachSession attaches context to persistent session, if session was saved before its state is restored and resources re-attached
+func AttachSession(context *Context, ID string) error {
+	if err := validateSessionID(ID); err != nil {
+		return err
+	}
+	var session = &PersistentSession{ID: ID, Resources: make([]*SessionResource, 0), mux: &sync.Mutex{}}
+	context.SessionID = ID
+	filename := sessionFilename(ID)
+	if toolbox.FileExists(filename) {
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(content, session); err != nil {
+			return fmt.Errorf("failed to decode session %v, %v", ID, err)
+		}
+		if session.Resources == nil {
+			session.Resources = make([]*SessionResource, 0)
+		}
+	}
+	if err := context.Replace(persistentSessionKey, session); err != nil {
+		return err
+	}
+	context.SafeState().Apply(session.State)
+	for _, resource := range session.Resources {
+		if err := reattachResource(context, resource); err != nil {
+			context.Publish(msg.NewErrorEvent(fmt.Sprintf("failed to re-attach %v.%v: %v", resource.Service, resource.Action, err)))
+		}
+	}
+	return nil
+}
+
+func reattachResource(context *Context, resource *SessionResource) error {
+	service, err := context.Service(resource.Service)
+	if err != nil {
+		return err
+	}
+	request, err := context.NewRequest(resource.Service, resource.Action, toolbox.AsMap(resource.Request))
+	if err != nil {
+		return err
+	}
+	response := service.Run(context, request)
+	return response.Err
+}
+
+//SaveSession persists attached session state and resources, default state keys, functions and secret values are excluded
+func SaveSession(context *Context) error {
+	session := context.PersistentSession()
+	if session == nil {
+		return nil
+	}
+	if err := validateSessionID(session.ID); err != nil {
+		return err
+	}
+	var defaults = NewDefaultState(context)
+	var state = make(map[string]interface{})
+	snapshot := context.SafeState().Clone()
+	for key, value := range snapshot.AsEncodableMap() {
+		if defaults.Has(key) || value == "func()" {
+			continue
+		}
+		state[key] = value
+	}
+	secrets := context.SecretValues()
+	session.mux.Lock()
+	session.State = secrets.Redact(state)
+	session.Updated = time.Now()
+	var saved = &PersistentSession{ID: session.ID, Updated: session.Updated, State: session.State, Resources: make([]*SessionResource, 0)}
+	for _, resource := range session.Resources { //resource requests are saved redacted, tracked requests keep values to re-run within this session
+		request := secrets.Redact(toolbox.AsMap(resource.Request))
+		saved.Resources = append(saved.Resources, &SessionResource{Service: resource.Service, Action: resource.Action, Request: request})
+	}
+	session.mux.Unlock()
+	content, err := json.Marshal(saved)
+	if err != nil {
+		return fmt.Errorf("failed to encode session %v, %v", session.ID, err)
+	}
+	if err = os.MkdirAll(SessionDirectory, 0744); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(sessionFilename(session.ID), content, 0600)
+}
@@ -0,0 +1,85 @@
+package endly_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestAttachSession(t *testing.T) {
+	directory, err := ioutil.TempDir("", "endly_session")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(directory)
+	sessionDirectory := endly.SessionDirectory
+	endly.SessionDirectory = directory
+	defer func() {
+		endly.SessionDirectory = sessionDirectory
+	}()
+
+	manager := endly.New()
+	{
+		context := manager.NewContext(nil)
+		assert.Nil(t, context.PersistentSession())
+		assert.Nil(t, endly.SaveSession(context))
+		assert.Nil(t, endly.AttachSession(context, "debug"))
+		assert.Equal(t, "debug", context.SessionID)
+		context.SetValue("app.name", "myapp")
+		context.SetValue("app.dbPassword", "dev")
+		context.SecretValues().Add("s3cr3t")
+		context.SetValue("apiKey", "s3cr3t")
+		context.PersistentSession().Track("nop", "nop", map[string]interface{}{"In": 1})
+		context.PersistentSession().Track("nop", "nop", map[string]interface{}{"In": 1})
+		context.PersistentSession().Track("nop", "open", map[string]interface{}{"Target": map[string]interface{}{"URL": "ssh://127.0.0.1", "Password": "dev"}, "Command": "mysql -p s3cr3t"})
+		assert.Equal(t, 2, len(context.PersistentSession().Resources))
+		assert.Nil(t, endly.SaveSession(context))
+		context.Close()
+	}
+	{
+		context := manager.NewContext(nil)
+		assert.Nil(t, endly.AttachSession(context, "debug"))
+		name, err := context.GetString("app.name")
+		assert.Nil(t, err)
+		assert.Equal(t, "myapp", name)
+		content, err := ioutil.ReadFile(path.Join(directory, "debug.json"))
+		assert.Nil(t, err)
+		assert.False(t, strings.Contains(string(content), "s3cr3t"))
+		assert.False(t, strings.Contains(string(content), "dbPassword"))
+		assert.False(t, strings.Contains(string(content), `"Password"`))
+		assert.True(t, strings.Contains(string(content), "ssh://127.0.0.1"))
+		_, err = context.GetString("apiKey")
+		assert.NotNil(t, err)
+		assert.Equal(t, 2, len(context.PersistentSession().Resources))
+	}
+}
+
+func TestAttachSession_InvalidID(t *testing.T) {
+	directory, err := ioutil.TempDir("", "endly_session")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(directory)
+	sessionDirectory := endly.SessionDirectory
+	endly.SessionDirectory = path.Join(directory, "session")
+	defer func() {
+		endly.SessionDirectory = sessionDirectory
+	}()
+	manager := endly.New()
+	for _, ID := range []string{"", "../debug", "a/b", `a\b`, "..", "/tmp/debug"} {
+		context := manager.NewContext(nil)
+		assert.NotNil(t, endly.AttachSession(context, ID), ID)
+		assert.Nil(t, context.PersistentSession(), ID)
+		context.Close()
+	}
+	context := manager.NewContext(nil)
+	defer context.Close()
+	assert.Nil(t, endly.AttachSession(context, "debug-1.2"))
+	assert.Nil(t, endly.SaveSession(context))
+	assert.True(t, toolbox.FileExists(path.Join(directory, "session", "debug-1.2.json")))
+}
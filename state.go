@@ -0,0 +1,88 @@
+package endly
+
+import (
+	"fmt"
+	"github.com/viant/toolbox"
+)
+
+//GetValue returns state value for supplied path i.e. params.app.name
+func (c *Context) GetValue(path string) (interface{}, bool) {
+	value, has := c.SafeState().GetValue(path)
+	if !has || value == nil {
+		return nil, false
+	}
+	return value, true
+}
+
+//SetValue sets state value for supplied path, intermediate maps are created if needed
+func (c *Context) SetValue(path string, value interface{}) {
+	c.SafeState().SetValue(path, value)
+}
+
+//GetString returns state string value for supplied path or error if value is missing
+func (c *Context) GetString(path string) (string, error) {
+	value, err := c.getRequired(path)
+	if err != nil {
+		return "", err
+	}
+	return toolbox.AsString(value), nil
+}
+
+//GetInt returns state int value for supplied path or error if value is missing or is not numeric
+func (c *Context) GetInt(path string) (int, error) {
+	value, err := c.getRequired(path)
+	if err != nil {
+		return 0, err
+	}
+	result, err := toolbox.ToInt(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid state %v: expected int but had %T(%v)", path, value, value)
+	}
+	return result, nil
+}
+
+//GetFloat returns state float value for supplied path or error if value is missing or is not numeric
+func (c *Context) GetFloat(path string) (float64, error) {
+	value, err := c.getRequired(path)
+	if err != nil {
+		return 0, err
+	}
+	result, err := toolbox.ToFloat(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid state %v: expected float but had %T(%v)", path, value, value)
+	}
+	return result, nil
+}
+
+//GetBool returns state bool value for supplied path or error if value is missing or is not boolean
+func (c *Context) GetBool(path string) (bool, error) {
+	value, err := c.getRequired(path)
+	if err != nil {
+		return false, err
+	}
+	result, err := toolbox.ToBoolean(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid state %v: expected bool but had %T(%v)", path, value, value)
+	}
+	return result, nil
+}
+
+//GetStruct converts state value for supplied path into target pointer or returns error if value is missing
+func (c *Context) GetStruct(path string, target interface{}) error {
+	value, err := c.getRequired(path)
+	if err != nil {
+		return err
+	}
+	if err = toolbox.DefaultConverter.AssignConverted(target, value); err != nil {
+		return fmt.Errorf("invalid state %v: unable to convert %T into %T, %v", path, value, target, err)
+	}
+	return nil
+}
+
+func (c *Context) getRequired(path string) (interface{}, error) {
+	value, has := c.GetValue(path)
+	if !has {
+		return nil, fmt.Errorf("state %v was empty", path)
+	}
+	return value, nil
+}
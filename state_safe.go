@@ -0,0 +1,109 @@
+package endly
+
+import (
+	"github.com/viant/toolbox/data"
+	"sync"
+)
+
+//SafeState represents concurrency-safe context state view, it exposes data.Map API guarded by a mutex shared by all cloned contexts.
+//Note that UDFs called during expansion are given the underlying state map and must not use context state accessors.
+type SafeState struct {
+	mux   *sync.RWMutex
+	state data.Map
+}
+
+//Get returns state value for supplied key
+func (s *SafeState) Get(key string) interface{} {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.state.Get(key)
+}
+
+//Has returns true if state has supplied key
+func (s *SafeState) Has(key string) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.state.Has(key)
+}
+
+//Put sets state value for supplied key
+func (s *SafeState) Put(key string, value interface{}) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.state.Put(key, value)
+}
+
+//Delete removes supplied keys
+func (s *SafeState) Delete(keys ...string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.state.Delete(keys...)
+}
+
+//GetValue returns state value for supplied path i.e. params.app.name
+func (s *SafeState) GetValue(path string) (interface{}, bool) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.state.GetValue(path)
+}
+
+//SetValue sets state value for supplied path
+func (s *SafeState) SetValue(path string, value interface{}) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.state.SetValue(path, value)
+}
+
+//Expand substitutes $ expressions in supplied source
+func (s *SafeState) Expand(source interface{}) interface{} {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.state.Expand(source)
+}
+
+//ExpandAsText substitutes $ expressions in supplied text
+func (s *SafeState) ExpandAsText(text string) string {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.state.ExpandAsText(text)
+}
+
+//Apply copies all supplied source keys into the state
+func (s *SafeState) Apply(source data.Map) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.state.Apply(source)
+}
+
+//Clone returns a state shallow copy
+func (s *SafeState) Clone() data.Map {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.state.Clone()
+}
+
+//DeepClone returns a state copy with nested maps and slices copied too
+func (s *SafeState) DeepClone() data.Map {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return deepCopy(s.state).(data.Map)
+}
+
+//Read runs supplied function with read locked state
+func (s *SafeState) Read(fn func(state data.Map)) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	fn(s.state)
+}
+
+//Update runs supplied function with write locked state
+func (s *SafeState) Update(fn func(state data.Map) error) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return fn(s.state)
+}
+
+//SafeState returns concurrency-safe state view
+func (c *Context) SafeState() *SafeState {
+	return &SafeState{mux: c.stateMux, state: c.state}
+}
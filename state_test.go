@@ -0,0 +1,85 @@
+package endly_test
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"sync"
+	"testing"
+)
+
+func TestContext_TypedState(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	context.SetValue("app.name", "myapp")
+	context.SetValue("app.port", "8080")
+	context.SetValue("app.debug", "true")
+
+	name, err := context.GetString("app.name")
+	assert.Nil(t, err)
+	assert.Equal(t, "myapp", name)
+
+	port, err := context.GetInt("app.port")
+	assert.Nil(t, err)
+	assert.Equal(t, 8080, port)
+
+	debug, err := context.GetBool("app.debug")
+	assert.Nil(t, err)
+	assert.True(t, debug)
+
+	_, err = context.GetInt("app.name")
+	assert.NotNil(t, err)
+
+	_, err = context.GetString("app.missing")
+	assert.NotNil(t, err)
+
+	var app = struct {
+		Name string
+		Port int
+	}{}
+	err = context.GetStruct("app", &app)
+	assert.Nil(t, err)
+	assert.Equal(t, "myapp", app.Name)
+	assert.Equal(t, 8080, app.Port)
+}
+
+func TestContext_SafeState(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	context.SetValue("app.name", "myapp")
+	var group = &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		group.Add(1)
+		go func(i int, cloned *endly.Context) {
+			defer group.Done()
+			for j := 0; j < 50; j++ {
+				key := fmt.Sprintf("app.key%v", i)
+				cloned.SetValue(key, j)
+				_, _ = context.GetValue(key)
+				_ = cloned.Expand("$app.name")
+			}
+		}(i, context.Clone())
+	}
+	group.Wait()
+	assert.Equal(t, "myapp", context.Expand("$app.name"))
+	value, has := context.SafeState().GetValue("app.key1")
+	assert.True(t, has)
+	assert.EqualValues(t, 49, value)
+}
+
+func TestContext_SafeState_FirstAccess(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	assert.NotNil(t, context.State())
+	var group = &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		group.Add(1)
+		go func(i int) {
+			defer group.Done()
+			context.SafeState().Put(fmt.Sprintf("key%v", i), i)
+			_ = context.Expand("$key1")
+		}(i)
+	}
+	group.Wait()
+	assert.Equal(t, 9, context.SafeState().Get("key9"))
+}
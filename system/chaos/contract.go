@@ -0,0 +1,197 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/toolbox/url"
+	"strings"
+	"time"
+)
+
+const (
+	//FaultNetwork represents network latency/packet loss fault kind
+	FaultNetwork = "network"
+	//FaultProcess represents process kill/pause fault kind
+	FaultProcess = "process"
+	//FaultContainer represents docker container kill/pause/stop fault kind
+	FaultContainer = "container"
+	//FaultStress represents cpu/disk stress fault kind
+	FaultStress = "stress"
+
+	//ModePause suspends process or container till fault is reverted
+	ModePause = "pause"
+	//ModeStop stops container till fault is reverted
+	ModeStop = "stop"
+	//ModeKill kills process or container, killed container is started once fault is reverted
+	ModeKill = "kill"
+
+	//ResourceCPU represents cpu stress
+	ResourceCPU = "cpu"
+	//ResourceDisk represents disk fill stress
+	ResourceDisk = "disk"
+
+	defaultInterface = "eth0"
+	defaultFillPath  = "/tmp/endly-chaos.fill"
+)
+
+//Fault represents injected fault, it is reverted once duration elapses, the current task ends, revert action is run or context closes
+type Fault struct {
+	ID         string
+	Kind       string
+	Target     string
+	Inject     []string
+	Revert     []string `json:",omitempty"`
+	DurationMs int      `json:",omitempty"`
+	Started    time.Time
+	Reverted   *time.Time `json:",omitempty"`
+}
+
+//NetworkRequest represents tc/netem latency and packet loss injection request
+type NetworkRequest struct {
+	Target     *url.Resource `required:"true" description:"host where fault is injected"`
+	Interface  string        `description:"network interface, eth0 by default"`
+	LatencyMs  int           `description:"added latency"`
+	JitterMs   int           `description:"optional latency jitter"`
+	LossPct    float64       `description:"packet loss percentage"`
+	DurationMs int           `description:"optional fault duration, fault is reverted at the latest when the current task ends"`
+}
+
+//ProcessRequest represents process kill or pause request
+type ProcessRequest struct {
+	Target     *url.Resource `required:"true" description:"host where fault is injected"`
+	Pid        int           `description:"process id"`
+	Name       string        `description:"process command line pattern, used if pid is not specified"`
+	Mode       string        `description:"pause (default) or kill, killed process is not restarted"`
+	DurationMs int           `description:"optional fault duration, fault is reverted at the latest when the current task ends"`
+}
+
+//ContainerRequest represents docker container kill, pause or stop request
+type ContainerRequest struct {
+	Target     *url.Resource `required:"true" description:"docker host where fault is injected"`
+	Name       string        `required:"true" description:"container name or id"`
+	Mode       string        `description:"pause (default), stop or kill, stopped or killed container is started on revert"`
+	DurationMs int           `description:"optional fault duration, fault is reverted at the latest when the current task ends"`
+}
+
+//StressRequest represents cpu or disk stress request
+type StressRequest struct {
+	Target     *url.Resource `required:"true" description:"host where fault is injected"`
+	Resource   string        `required:"true" description:"cpu or disk"`
+	Workers    int           `description:"cpu busy loop workers, 1 by default"`
+	Path       string        `description:"disk fill file, /tmp/endly-chaos.fill by default"`
+	SizeMb     int           `description:"disk fill size"`
+	DurationMs int           `description:"optional fault duration, fault is reverted at the latest when the current task ends"`
+}
+
+//FaultResponse represents fault injection response
+type FaultResponse struct {
+	Fault *Fault
+}
+
+//RevertRequest represents revert request
+type RevertRequest struct {
+	IDs []string `description:"fault IDs to revert, all active faults are reverted if empty"`
+}
+
+//RevertResponse represents revert response
+type RevertResponse struct {
+	Reverted []*Fault
+}
+
+//Init initialises request
+func (r *NetworkRequest) Init() error {
+	if r.Interface == "" {
+		r.Interface = defaultInterface
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *NetworkRequest) Validate() error {
+	if r.Target == nil {
+		return errors.New("target was empty")
+	}
+	if r.LatencyMs <= 0 && r.LossPct <= 0 {
+		return errors.New("latencyMs and lossPct were empty")
+	}
+	if r.LossPct > 100 {
+		return fmt.Errorf("invalid lossPct: %v", r.LossPct)
+	}
+	return nil
+}
+
+//Init initialises request
+func (r *ProcessRequest) Init() error {
+	r.Mode = strings.ToLower(r.Mode)
+	if r.Mode == "" {
+		r.Mode = ModePause
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *ProcessRequest) Validate() error {
+	if r.Target == nil {
+		return errors.New("target was empty")
+	}
+	if r.Pid == 0 && r.Name == "" {
+		return errors.New("pid and name were empty")
+	}
+	if r.Mode != ModePause && r.Mode != ModeKill {
+		return fmt.Errorf("unsupported process mode: %v", r.Mode)
+	}
+	return nil
+}
+
+//Init initialises request
+func (r *ContainerRequest) Init() error {
+	r.Mode = strings.ToLower(r.Mode)
+	if r.Mode == "" {
+		r.Mode = ModePause
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *ContainerRequest) Validate() error {
+	if r.Target == nil {
+		return errors.New("target was empty")
+	}
+	if r.Name == "" {
+		return errors.New("name was empty")
+	}
+	switch r.Mode {
+	case ModePause, ModeStop, ModeKill:
+		return nil
+	}
+	return fmt.Errorf("unsupported container mode: %v", r.Mode)
+}
+
+//Init initialises request
+func (r *StressRequest) Init() error {
+	r.Resource = strings.ToLower(r.Resource)
+	if r.Workers == 0 {
+		r.Workers = 1
+	}
+	if r.Path == "" {
+		r.Path = defaultFillPath
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *StressRequest) Validate() error {
+	if r.Target == nil {
+		return errors.New("target was empty")
+	}
+	switch r.Resource {
+	case ResourceCPU:
+		return nil
+	case ResourceDisk:
+		if r.SizeMb <= 0 {
+			return errors.New("sizeMb was empty")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported stress resource: '%v'", r.Resource)
+}
@@ -0,0 +1,27 @@
+package chaos
+
+import (
+	"fmt"
+	"github.com/viant/endly/model/msg"
+	"strings"
+)
+
+//FaultEvent represents fault injection or revert event
+type FaultEvent struct {
+	Fault    *Fault
+	Reverted bool
+}
+
+//Messages returns messages
+func (e *FaultEvent) Messages() []*msg.Message {
+	var tag, commands, style = "inject", e.Fault.Inject, msg.MessageStyleError
+	if e.Reverted {
+		tag, commands, style = "revert", e.Fault.Revert, msg.MessageStyleSuccess
+	}
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("chaos %v", e.Fault.ID), msg.MessageStyleGeneric),
+			msg.NewStyled(tag, msg.MessageStyleGeneric),
+			msg.NewStyled(fmt.Sprintf("%v: %v", e.Fault.Target, strings.Join(commands, "; ")), style),
+		),
+	}
+}
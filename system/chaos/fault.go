@@ -0,0 +1,138 @@
+package chaos
+
+import (
+	"fmt"
+	"github.com/viant/toolbox/url"
+	"strings"
+	"sync"
+)
+
+var faultsKey = (*faults)(nil)
+
+//activeFault represents injected fault with its revert state
+type activeFault struct {
+	*Fault
+	target    *url.Resource
+	superUser bool
+	mux       *sync.Mutex
+}
+
+//faults represents context active faults registry
+type faults struct {
+	mux    *sync.Mutex
+	seq    int
+	active []*activeFault
+}
+
+func (f *faults) add(fault *activeFault) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.seq++
+	fault.ID = fmt.Sprintf("%v-%v", fault.Kind, f.seq)
+	f.active = append(f.active, fault)
+}
+
+func (f *faults) remove(fault *activeFault) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	for i, candidate := range f.active {
+		if candidate == fault {
+			f.active = append(f.active[:i], f.active[i+1:]...)
+			return
+		}
+	}
+}
+
+//matched returns active faults with matching IDs or all active faults if IDs are empty
+func (f *faults) matched(IDs []string) []*activeFault {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	var result = make([]*activeFault, 0)
+	for _, fault := range f.active {
+		if len(IDs) == 0 {
+			result = append(result, fault)
+			continue
+		}
+		for _, ID := range IDs {
+			if fault.ID == ID {
+				result = append(result, fault)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func newFaults() *faults {
+	return &faults{mux: &sync.Mutex{}, active: make([]*activeFault, 0)}
+}
+
+func quote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+//networkFault returns tc/netem fault
+func networkFault(request *NetworkRequest) *Fault {
+	var netem = make([]string, 0)
+	if request.LatencyMs > 0 {
+		netem = append(netem, fmt.Sprintf("delay %vms", request.LatencyMs))
+		if request.JitterMs > 0 {
+			netem = append(netem, fmt.Sprintf("%vms", request.JitterMs))
+		}
+	}
+	if request.LossPct > 0 {
+		netem = append(netem, fmt.Sprintf("loss %v%%", request.LossPct))
+	}
+	return &Fault{
+		Kind:   FaultNetwork,
+		Inject: []string{fmt.Sprintf("tc qdisc replace dev %v root netem %v", request.Interface, strings.Join(netem, " "))},
+		Revert: []string{fmt.Sprintf("tc qdisc del dev %v root", request.Interface)},
+	}
+}
+
+//processFault returns process kill or pause fault
+func processFault(request *ProcessRequest) *Fault {
+	var signal = func(signal string) string {
+		if request.Pid > 0 {
+			return fmt.Sprintf("kill -%v %v", signal, request.Pid)
+		}
+		return fmt.Sprintf("pkill -%v -f %v", signal, quote(request.Name))
+	}
+	var result = &Fault{Kind: FaultProcess}
+	if request.Mode == ModeKill {
+		result.Inject = []string{signal("9")}
+		return result
+	}
+	result.Inject = []string{signal("STOP")}
+	result.Revert = []string{signal("CONT")}
+	return result
+}
+
+//containerFault returns docker container fault
+func containerFault(request *ContainerRequest) *Fault {
+	var result = &Fault{
+		Kind:   FaultContainer,
+		Inject: []string{fmt.Sprintf("docker %v %v", request.Mode, request.Name)},
+		Revert: []string{fmt.Sprintf("docker start %v", request.Name)},
+	}
+	if request.Mode == ModePause {
+		result.Revert = []string{fmt.Sprintf("docker unpause %v", request.Name)}
+	}
+	return result
+}
+
+//stressFault returns cpu busy loop or disk fill fault, marker identifies cpu workers to stop
+func stressFault(request *StressRequest, marker string) *Fault {
+	if request.Resource == ResourceDisk {
+		return &Fault{
+			Kind:   FaultStress,
+			Inject: []string{fmt.Sprintf("fallocate -l %vM %v || dd if=/dev/zero of=%v bs=1M count=%v", request.SizeMb, request.Path, request.Path, request.SizeMb)},
+			Revert: []string{fmt.Sprintf("rm -f %v", request.Path)},
+		}
+	}
+	return &Fault{
+		Kind:   FaultStress,
+		Inject: []string{fmt.Sprintf("for i in $(seq 1 %v); do nohup sh -c 'while :; do :; done' %v > /dev/null 2>&1 & done", request.Workers, marker)},
+		Revert: []string{fmt.Sprintf("pkill -f %v", marker)},
+	}
+}
@@ -0,0 +1,275 @@
+package chaos
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/system/exec"
+	"github.com/viant/endly/workflow"
+	"github.com/viant/toolbox/url"
+	"sync"
+	"time"
+)
+
+const (
+	//ServiceID represents chaos service id
+	ServiceID = "chaos"
+)
+
+type service struct {
+	*endly.AbstractService
+	run func(context *endly.Context, target *url.Resource, superUser bool, commands []string) error
+}
+
+//runCommands runs fault commands on target host
+func (s *service) runCommands(context *endly.Context, target *url.Resource, superUser bool, commands []string) error {
+	runRequest := exec.NewRunRequest(target, superUser, commands...)
+	runRequest.CheckError = true
+	return endly.Run(context, runRequest, &exec.RunResponse{})
+}
+
+func (s *service) faults(context *endly.Context) *faults {
+	var result *faults
+	if !context.Contains(faultsKey) {
+		result = newFaults()
+		_ = context.Put(faultsKey, result)
+		return result
+	}
+	context.GetInto(faultsKey, &result)
+	return result
+}
+
+//inject injects fault and schedules its revert once duration elapses, the current task ends or context closes
+func (s *service) inject(context *endly.Context, target *url.Resource, superUser bool, fault *Fault, durationMs int) (*FaultResponse, error) {
+	target, err := context.ExpandResource(target)
+	if err != nil {
+		return nil, err
+	}
+	fault.Target = target.URL
+	fault.DurationMs = durationMs
+	if err = s.run(context, target, superUser, fault.Inject); err != nil {
+		return nil, fmt.Errorf("failed to inject %v fault: %v", fault.Kind, err)
+	}
+	fault.Started = time.Now()
+	active := &activeFault{Fault: fault, target: target, superUser: superUser, mux: &sync.Mutex{}}
+	registry := s.faults(context)
+	registry.add(active)
+	context.Publish(&FaultEvent{Fault: fault})
+	revert := func() {
+		if err := s.revertFault(context, registry, active); err != nil {
+			context.Publish(msg.NewErrorEvent(fmt.Sprintf("failed to revert %v: %v", active.ID, err)))
+		}
+	}
+	if process := workflow.Last(context); process != nil {
+		process.OnTaskEnd(revert)
+	}
+	context.Deffer(revert)
+	if durationMs > 0 {
+		time.AfterFunc(time.Duration(durationMs)*time.Millisecond, revert)
+	}
+	return &FaultResponse{Fault: fault}, nil
+}
+
+//revertFault reverts active fault only once
+func (s *service) revertFault(context *endly.Context, registry *faults, fault *activeFault) error {
+	fault.mux.Lock()
+	defer fault.mux.Unlock()
+	if fault.Reverted != nil {
+		return nil
+	}
+	if len(fault.Revert) > 0 {
+		if err := s.run(context, fault.target, fault.superUser, fault.Revert); err != nil {
+			return err
+		}
+	}
+	reverted := time.Now()
+	fault.Reverted = &reverted
+	registry.remove(fault)
+	context.Publish(&FaultEvent{Fault: fault.Fault, Reverted: true})
+	return nil
+}
+
+func (s *service) revert(context *endly.Context, request *RevertRequest) (*RevertResponse, error) {
+	var response = &RevertResponse{Reverted: make([]*Fault, 0)}
+	registry := s.faults(context)
+	for _, fault := range registry.matched(request.IDs) {
+		if err := s.revertFault(context, registry, fault); err != nil {
+			return response, fmt.Errorf("failed to revert %v: %v", fault.ID, err)
+		}
+		response.Reverted = append(response.Reverted, fault.Fault)
+	}
+	return response, nil
+}
+
+const (
+	networkExample = `{
+  "Target": {
+    "URL": "ssh://127.0.0.1/",
+    "Credentials": "localhost"
+  },
+  "Interface": "eth0",
+  "LatencyMs": 300,
+  "JitterMs": 50,
+  "LossPct": 5,
+  "DurationMs": 60000
+}`
+	processExample = `{
+  "Target": {
+    "URL": "ssh://127.0.0.1/",
+    "Credentials": "localhost"
+  },
+  "Name": "myapp",
+  "Mode": "pause",
+  "DurationMs": 10000
+}`
+	containerExample = `{
+  "Target": {
+    "URL": "ssh://127.0.0.1/",
+    "Credentials": "localhost"
+  },
+  "Name": "db",
+  "Mode": "stop"
+}`
+	stressExample = `{
+  "Target": {
+    "URL": "ssh://127.0.0.1/",
+    "Credentials": "localhost"
+  },
+  "Resource": "disk",
+  "Path": "/data/endly-chaos.fill",
+  "SizeMb": 2048
+}`
+)
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "network",
+		RequestInfo: &endly.ActionInfo{
+			Description: "inject network latency or packet loss with tc/netem",
+			Examples: []*endly.UseCase{
+				{
+					Description: "latency and packet loss",
+					Data:        networkExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &NetworkRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &FaultResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*NetworkRequest); ok {
+				return s.inject(context, req.Target, true, networkFault(req), req.DurationMs)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "process",
+		RequestInfo: &endly.ActionInfo{
+			Description: "pause or kill process",
+			Examples: []*endly.UseCase{
+				{
+					Description: "pause process",
+					Data:        processExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &ProcessRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &FaultResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ProcessRequest); ok {
+				return s.inject(context, req.Target, true, processFault(req), req.DurationMs)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "container",
+		RequestInfo: &endly.ActionInfo{
+			Description: "pause, stop or kill docker container",
+			Examples: []*endly.UseCase{
+				{
+					Description: "stop container",
+					Data:        containerExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &ContainerRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &FaultResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ContainerRequest); ok {
+				return s.inject(context, req.Target, false, containerFault(req), req.DurationMs)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "stress",
+		RequestInfo: &endly.ActionInfo{
+			Description: "run cpu busy loop or fill disk",
+			Examples: []*endly.UseCase{
+				{
+					Description: "fill disk",
+					Data:        stressExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &StressRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &FaultResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*StressRequest); ok {
+				marker := fmt.Sprintf("endly-chaos-cpu-%v", time.Now().UnixNano())
+				return s.inject(context, req.Target, false, stressFault(req, marker), req.DurationMs)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "revert",
+		RequestInfo: &endly.ActionInfo{
+			Description: "revert active faults",
+		},
+		RequestProvider: func() interface{} {
+			return &RevertRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &RevertResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*RevertRequest); ok {
+				return s.revert(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+//New creates a new chaos service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.run = result.runCommands
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
@@ -0,0 +1,170 @@
+package chaos
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/workflow"
+	"github.com/viant/toolbox/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//commandRecorder records commands instead of running them on a target host
+type commandRecorder struct {
+	mux      *sync.Mutex
+	commands []string
+}
+
+func (r *commandRecorder) run(context *endly.Context, target *url.Resource, superUser bool, commands []string) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.commands = append(r.commands, commands...)
+	return nil
+}
+
+func (r *commandRecorder) recorded() []string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]string{}, r.commands...)
+}
+
+func newTestService() (*service, *commandRecorder) {
+	recorder := &commandRecorder{mux: &sync.Mutex{}}
+	chaos := New().(*service)
+	chaos.run = recorder.run
+	return chaos, recorder
+}
+
+func TestFaults(t *testing.T) {
+	var useCases = []struct {
+		description string
+		request     interface {
+			Init() error
+			Validate() error
+		}
+		fault  func(request interface{}) *Fault
+		inject string
+		revert string
+	}{
+		{
+			description: "network latency with loss",
+			request:     &NetworkRequest{Target: url.NewResource("ssh://127.0.0.1"), LatencyMs: 200, JitterMs: 20, LossPct: 5},
+			fault:       func(request interface{}) *Fault { return networkFault(request.(*NetworkRequest)) },
+			inject:      "tc qdisc replace dev eth0 root netem delay 200ms 20ms loss 5%",
+			revert:      "tc qdisc del dev eth0 root",
+		},
+		{
+			description: "pause process by name",
+			request:     &ProcessRequest{Target: url.NewResource("ssh://127.0.0.1"), Name: "my app"},
+			fault:       func(request interface{}) *Fault { return processFault(request.(*ProcessRequest)) },
+			inject:      "pkill -STOP -f 'my app'",
+			revert:      "pkill -CONT -f 'my app'",
+		},
+		{
+			description: "kill process",
+			request:     &ProcessRequest{Target: url.NewResource("ssh://127.0.0.1"), Pid: 123, Mode: "kill"},
+			fault:       func(request interface{}) *Fault { return processFault(request.(*ProcessRequest)) },
+			inject:      "kill -9 123",
+		},
+		{
+			description: "stop container",
+			request:     &ContainerRequest{Target: url.NewResource("ssh://127.0.0.1"), Name: "db", Mode: "stop"},
+			fault:       func(request interface{}) *Fault { return containerFault(request.(*ContainerRequest)) },
+			inject:      "docker stop db",
+			revert:      "docker start db",
+		},
+		{
+			description: "pause container",
+			request:     &ContainerRequest{Target: url.NewResource("ssh://127.0.0.1"), Name: "db"},
+			fault:       func(request interface{}) *Fault { return containerFault(request.(*ContainerRequest)) },
+			inject:      "docker pause db",
+			revert:      "docker unpause db",
+		},
+		{
+			description: "fill disk",
+			request:     &StressRequest{Target: url.NewResource("ssh://127.0.0.1"), Resource: "disk", SizeMb: 10},
+			fault:       func(request interface{}) *Fault { return stressFault(request.(*StressRequest), "marker") },
+			inject:      "fallocate -l 10M /tmp/endly-chaos.fill || dd if=/dev/zero of=/tmp/endly-chaos.fill bs=1M count=10",
+			revert:      "rm -f /tmp/endly-chaos.fill",
+		},
+		{
+			description: "cpu busy loop",
+			request:     &StressRequest{Target: url.NewResource("ssh://127.0.0.1"), Resource: "CPU", Workers: 2},
+			fault:       func(request interface{}) *Fault { return stressFault(request.(*StressRequest), "marker") },
+			inject:      "for i in $(seq 1 2); do nohup sh -c 'while :; do :; done' marker > /dev/null 2>&1 & done",
+			revert:      "pkill -f marker",
+		},
+	}
+	for _, useCase := range useCases {
+		assert.Nil(t, useCase.request.Init(), useCase.description)
+		if !assert.Nil(t, useCase.request.Validate(), useCase.description) {
+			continue
+		}
+		fault := useCase.fault(useCase.request)
+		assert.Equal(t, useCase.inject, strings.Join(fault.Inject, ";"), useCase.description)
+		assert.Equal(t, useCase.revert, strings.Join(fault.Revert, ";"), useCase.description)
+	}
+
+	target := url.NewResource("ssh://127.0.0.1")
+	assert.NotNil(t, (&NetworkRequest{Target: target}).Validate())
+	assert.NotNil(t, (&ProcessRequest{Target: target, Mode: ModePause}).Validate())
+	assert.NotNil(t, (&ContainerRequest{Target: target, Name: "db", Mode: "restart"}).Validate())
+	assert.NotNil(t, (&StressRequest{Target: target, Resource: ResourceDisk}).Validate())
+}
+
+func TestService_Revert(t *testing.T) {
+	manager := endly.New()
+	target := url.NewResource("ssh://127.0.0.1")
+
+	t.Run("task end", func(t *testing.T) {
+		chaos, recorder := newTestService()
+		context := manager.NewContext(nil)
+		process := model.NewProcess(nil, &model.Workflow{}, nil)
+		workflow.Push(context, process)
+		endTask := process.BeginTask()
+		response, err := chaos.inject(context, target, false, containerFault(&ContainerRequest{Name: "db", Mode: ModePause}), 0)
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, "container-1", response.Fault.ID)
+		assert.Equal(t, []string{"docker pause db"}, recorder.recorded())
+		endTask()
+		assert.Equal(t, []string{"docker pause db", "docker unpause db"}, recorder.recorded())
+		assert.NotNil(t, response.Fault.Reverted)
+		context.Close()
+		assert.Equal(t, 2, len(recorder.recorded()), "reverted only once")
+	})
+
+	t.Run("duration", func(t *testing.T) {
+		chaos, recorder := newTestService()
+		context := manager.NewContext(nil)
+		_, err := chaos.inject(context, target, true, networkFault(&NetworkRequest{Interface: "eth0", LatencyMs: 100}), 20)
+		if !assert.Nil(t, err) {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, []string{"tc qdisc replace dev eth0 root netem delay 100ms", "tc qdisc del dev eth0 root"}, recorder.recorded())
+	})
+
+	t.Run("revert action", func(t *testing.T) {
+		chaos, recorder := newTestService()
+		context := manager.NewContext(nil)
+		for _, name := range []string{"db", "cache"} {
+			if _, err := chaos.inject(context, target, false, containerFault(&ContainerRequest{Name: name, Mode: ModeKill}), 0); !assert.Nil(t, err) {
+				return
+			}
+		}
+		response, err := chaos.revert(context, &RevertRequest{IDs: []string{"container-2"}})
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(response.Reverted)) {
+			assert.Equal(t, "container-2", response.Reverted[0].ID)
+		}
+		response, err = chaos.revert(context, &RevertRequest{})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response.Reverted))
+		assert.Equal(t, []string{"docker kill db", "docker kill cache", "docker start cache", "docker start db"}, recorder.recorded())
+	})
+}
@@ -0,0 +1,151 @@
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/toolbox/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	//ModeFaketime writes libfaketime timestamp file on target host
+	ModeFaketime = "faketime"
+	//ModeContainer writes libfaketime timestamp file in docker container
+	ModeContainer = "container"
+	//ModeEnv exports offset or time env variable in target exec session for supported apps
+	ModeEnv = "env"
+
+	defaultTimestampFile = "/tmp/endly.faketimerc"
+	defaultLibrary       = "/usr/lib/x86_64-linux-gnu/faketime/libfaketime.so.1"
+	faketimeLayout       = "2006-01-02 15:04:05"
+)
+
+var offsetExpr = regexp.MustCompile(`^[+-]\d+(\.\d+)?[smhdy]?$`)
+
+//Shift represents active time shift
+type Shift struct {
+	ID       string
+	Mode     string
+	Target   string
+	Spec     string            `description:"libfaketime spec or env variable value"`
+	Env      map[string]string `json:",omitempty" description:"env to start app with, so that libfaketime picks up the timestamp file"`
+	Set      []string
+	Restore  []string
+	Started  time.Time
+	Restored *time.Time `json:",omitempty"`
+	target   *url.Resource
+}
+
+//SetRequest represents time shift or freeze request
+type SetRequest struct {
+	Target    *url.Resource `required:"true" description:"host or docker host"`
+	Mode      string        `description:"faketime (default), container or env"`
+	Offset    string        `description:"relative offset, i.e. +1d, -2h, +30m, +90s, +1y"`
+	At        string        `description:"absolute time, i.e. 2024-12-31 23:59:50 or RFC3339, clock starts from it unless freeze is set"`
+	Freeze    bool          `description:"flag to freeze clock at 'at' time"`
+	Path      string        `description:"libfaketime timestamp file, /tmp/endly.faketimerc by default"`
+	Library   string        `description:"libfaketime library path used to build response env"`
+	Container string        `description:"docker container name for container mode"`
+	Env       string        `description:"env variable name for env mode"`
+}
+
+//SetResponse represents time shift response
+type SetResponse struct {
+	Shift *Shift
+}
+
+//RestoreRequest represents restore request
+type RestoreRequest struct {
+	IDs []string `description:"shift IDs to restore, all active shifts are restored if empty"`
+}
+
+//RestoreResponse represents restore response
+type RestoreResponse struct {
+	Restored []*Shift
+}
+
+//Init initialises request
+func (r *SetRequest) Init() error {
+	r.Mode = strings.ToLower(r.Mode)
+	if r.Mode == "" {
+		r.Mode = ModeFaketime
+	}
+	if r.Path == "" {
+		r.Path = defaultTimestampFile
+	}
+	if r.Library == "" {
+		r.Library = defaultLibrary
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *SetRequest) Validate() error {
+	if r.Target == nil {
+		return errors.New("target was empty")
+	}
+	switch r.Mode {
+	case ModeFaketime:
+	case ModeContainer:
+		if r.Container == "" {
+			return errors.New("container was empty")
+		}
+	case ModeEnv:
+		if r.Env == "" {
+			return errors.New("env was empty")
+		}
+	default:
+		return fmt.Errorf("unsupported mode: %v", r.Mode)
+	}
+	if (r.Offset == "") == (r.At == "") {
+		return errors.New("either offset or at has to be specified")
+	}
+	if r.Offset != "" && !offsetExpr.MatchString(r.Offset) {
+		return fmt.Errorf("invalid offset: %v, expected i.e. +1d, -2h", r.Offset)
+	}
+	if r.Freeze && r.At == "" {
+		return errors.New("freeze requires at")
+	}
+	if r.At != "" {
+		if _, err := r.atTime(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//atTime returns parsed absolute time
+func (r *SetRequest) atTime() (time.Time, error) {
+	for _, layout := range []string{faketimeLayout, time.RFC3339, "2006-01-02"} {
+		if result, err := time.Parse(layout, r.At); err == nil {
+			return result, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid at: %v, expected i.e. 2024-12-31 23:59:50", r.At)
+}
+
+//offsetDuration returns offset as duration
+func (r *SetRequest) offsetDuration() time.Duration {
+	unit := r.Offset[len(r.Offset)-1:]
+	value := r.Offset
+	var multiplier = time.Second
+	switch unit {
+	case "s":
+	case "m":
+		multiplier = time.Minute
+	case "h":
+		multiplier = time.Hour
+	case "d":
+		multiplier = 24 * time.Hour
+	case "y":
+		multiplier = 365 * 24 * time.Hour
+	default:
+		unit = ""
+	}
+	value = strings.TrimSuffix(value, unit)
+	var amount float64
+	_, _ = fmt.Sscanf(value, "%g", &amount)
+	return time.Duration(amount * float64(multiplier))
+}
@@ -0,0 +1,195 @@
+package clock
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/system/exec"
+	"github.com/viant/toolbox/url"
+	"strings"
+	"time"
+)
+
+const (
+	//ServiceID represents clock service id
+	ServiceID = "clock"
+)
+
+//ShiftEvent represents time shift set or restore event
+type ShiftEvent struct {
+	Shift    *Shift
+	Restored bool
+}
+
+//Messages returns messages
+func (e *ShiftEvent) Messages() []*msg.Message {
+	var tag, commands = "set", e.Shift.Set
+	if e.Restored {
+		tag, commands = "restore", e.Shift.Restore
+	}
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("clock %v", e.Shift.ID), msg.MessageStyleGeneric),
+			msg.NewStyled(tag, msg.MessageStyleGeneric),
+			msg.NewStyled(fmt.Sprintf("%v: %v", e.Shift.Target, strings.Join(commands, "; ")), msg.MessageStyleInput),
+		),
+	}
+}
+
+type service struct {
+	*endly.AbstractService
+	run func(context *endly.Context, target *url.Resource, commands []string) error
+}
+
+//runCommands runs clock commands on target host
+func (s *service) runCommands(context *endly.Context, target *url.Resource, commands []string) error {
+	runRequest := exec.NewRunRequest(target, false, commands...)
+	runRequest.CheckError = true
+	return endly.Run(context, runRequest, &exec.RunResponse{})
+}
+
+func (s *service) shifts(context *endly.Context) *shifts {
+	var result *shifts
+	if !context.Contains(shiftsKey) {
+		result = newShifts()
+		_ = context.Put(shiftsKey, result)
+		return result
+	}
+	context.GetInto(shiftsKey, &result)
+	return result
+}
+
+//set shifts or freezes time, active shifts are restored with restore action or once context closes
+func (s *service) set(context *endly.Context, request *SetRequest) (*SetResponse, error) {
+	target, err := context.ExpandResource(request.Target)
+	if err != nil {
+		return nil, err
+	}
+	shift := newShift(request)
+	shift.Target, shift.target = target.URL, target
+	if err = s.run(context, target, shift.Set); err != nil {
+		return nil, fmt.Errorf("failed to set %v time: %v", request.Mode, err)
+	}
+	shift.Started = time.Now()
+	registry := s.shifts(context)
+	registry.add(shift)
+	context.Publish(&ShiftEvent{Shift: shift})
+	context.Deffer(func() {
+		if _, err := s.restore(context, &RestoreRequest{IDs: []string{shift.ID}}); err != nil {
+			context.Publish(msg.NewErrorEvent(err.Error()))
+		}
+	})
+	return &SetResponse{Shift: shift}, nil
+}
+
+func (s *service) restore(context *endly.Context, request *RestoreRequest) (*RestoreResponse, error) {
+	var response = &RestoreResponse{Restored: make([]*Shift, 0)}
+	var failed = make([]string, 0)
+	for _, shift := range s.shifts(context).take(request.IDs) {
+		if err := s.run(context, shift.target, shift.Restore); err != nil {
+			failed = append(failed, fmt.Sprintf("%v: %v", shift.ID, err))
+			continue
+		}
+		restored := time.Now()
+		shift.Restored = &restored
+		context.Publish(&ShiftEvent{Shift: shift, Restored: true})
+		response.Restored = append(response.Restored, shift)
+	}
+	if len(failed) > 0 {
+		return response, fmt.Errorf("failed to restore time: %v", strings.Join(failed, "; "))
+	}
+	return response, nil
+}
+
+const (
+	setOffsetExample = `{
+  "Target": {
+    "URL": "ssh://127.0.0.1/",
+    "Credentials": "localhost"
+  },
+  "Offset": "+1d"
+}`
+	setFreezeExample = `{
+  "Target": {
+    "URL": "ssh://127.0.0.1/",
+    "Credentials": "localhost"
+  },
+  "Mode": "container",
+  "Container": "scheduler",
+  "At": "2024-12-31 23:59:50",
+  "Freeze": true
+}`
+	setEnvExample = `{
+  "Target": {
+    "URL": "ssh://127.0.0.1/",
+    "Credentials": "localhost"
+  },
+  "Mode": "env",
+  "Env": "APP_CLOCK_OFFSET_MS",
+  "Offset": "+2h"
+}`
+)
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "set",
+		RequestInfo: &endly.ActionInfo{
+			Description: "shift or freeze time with libfaketime or env offset",
+			Examples: []*endly.UseCase{
+				{
+					Description: "shift host time by one day",
+					Data:        setOffsetExample,
+				},
+				{
+					Description: "freeze container time",
+					Data:        setFreezeExample,
+				},
+				{
+					Description: "env offset",
+					Data:        setEnvExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &SetRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &SetResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*SetRequest); ok {
+				return s.set(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "restore",
+		RequestInfo: &endly.ActionInfo{
+			Description: "restore shifted time",
+		},
+		RequestProvider: func() interface{} {
+			return &RestoreRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &RestoreResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*RestoreRequest); ok {
+				return s.restore(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+//New creates a new clock service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.run = result.runCommands
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
@@ -0,0 +1,121 @@
+package clock
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox/url"
+	"testing"
+)
+
+func TestSetRequest(t *testing.T) {
+	target := url.NewResource("ssh://127.0.0.1")
+	var useCases = []struct {
+		description string
+		request     *SetRequest
+		spec        string
+		set         string
+		restore     string
+		hasEnv      bool
+		hasError    bool
+	}{
+		{
+			description: "faketime offset",
+			request:     &SetRequest{Target: target, Offset: "+1d"},
+			spec:        "+1d",
+			set:         "echo '+1d' > /tmp/endly.faketimerc",
+			restore:     "echo '+0' > /tmp/endly.faketimerc",
+			hasEnv:      true,
+		},
+		{
+			description: "faketime start at",
+			request:     &SetRequest{Target: target, At: "2024-12-31T23:59:50Z"},
+			spec:        "@2024-12-31 23:59:50",
+			set:         "echo '@2024-12-31 23:59:50' > /tmp/endly.faketimerc",
+			restore:     "echo '+0' > /tmp/endly.faketimerc",
+			hasEnv:      true,
+		},
+		{
+			description: "container freeze",
+			request:     &SetRequest{Target: target, Mode: "container", Container: "scheduler", At: "2024-12-31 23:59:50", Freeze: true, Path: "/etc/faketimerc"},
+			spec:        "2024-12-31 23:59:50",
+			set:         `docker exec scheduler sh -c 'echo '\''2024-12-31 23:59:50'\'' > /etc/faketimerc'`,
+			restore:     `docker exec scheduler sh -c 'echo '\''+0'\'' > /etc/faketimerc'`,
+			hasEnv:      true,
+		},
+		{
+			description: "env offset",
+			request:     &SetRequest{Target: target, Mode: "env", Env: "APP_CLOCK_OFFSET_MS", Offset: "-1.5h"},
+			spec:        "-5400000",
+			set:         "export APP_CLOCK_OFFSET_MS='-5400000'",
+			restore:     "unset APP_CLOCK_OFFSET_MS",
+		},
+		{
+			description: "env time",
+			request:     &SetRequest{Target: target, Mode: "env", Env: "APP_NOW", At: "2024-02-29"},
+			spec:        "2024-02-29T00:00:00Z",
+			set:         "export APP_NOW='2024-02-29T00:00:00Z'",
+			restore:     "unset APP_NOW",
+		},
+		{description: "invalid offset", request: &SetRequest{Target: target, Offset: "1 day"}, hasError: true},
+		{description: "offset and at", request: &SetRequest{Target: target, Offset: "+1d", At: "2024-02-29"}, hasError: true},
+		{description: "freeze without at", request: &SetRequest{Target: target, Offset: "+1d", Freeze: true}, hasError: true},
+		{description: "invalid at", request: &SetRequest{Target: target, At: "tomorrow"}, hasError: true},
+		{description: "container without name", request: &SetRequest{Target: target, Mode: "container", Offset: "+1d"}, hasError: true},
+	}
+	for _, useCase := range useCases {
+		assert.Nil(t, useCase.request.Init(), useCase.description)
+		err := useCase.request.Validate()
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		shift := newShift(useCase.request)
+		assert.Equal(t, useCase.spec, shift.Spec, useCase.description)
+		assert.Equal(t, []string{useCase.set}, shift.Set, useCase.description)
+		assert.Equal(t, []string{useCase.restore}, shift.Restore, useCase.description)
+		assert.Equal(t, useCase.hasEnv, len(shift.Env) > 0, useCase.description)
+	}
+}
+
+func TestService_Restore(t *testing.T) {
+	var commands = make([]string, 0)
+	clock := New().(*service)
+	clock.run = func(context *endly.Context, target *url.Resource, runCommands []string) error {
+		commands = append(commands, runCommands...)
+		return nil
+	}
+	context := endly.New().NewContext(nil)
+	target := url.NewResource("ssh://127.0.0.1")
+	var IDs = make([]string, 0)
+	for _, request := range []*SetRequest{
+		{Target: target, Offset: "+1d"},
+		{Target: target, Mode: ModeEnv, Env: "APP_CLOCK_OFFSET_MS", Offset: "+1m"},
+		{Target: target, Offset: "+2d"},
+	} {
+		_ = request.Init()
+		response, err := clock.set(context, request)
+		if !assert.Nil(t, err) {
+			return
+		}
+		IDs = append(IDs, response.Shift.ID)
+	}
+	assert.Equal(t, []string{"faketime-1", "env-2", "faketime-3"}, IDs)
+
+	response, err := clock.restore(context, &RestoreRequest{IDs: []string{"env-2"}})
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(response.Restored)) {
+		assert.NotNil(t, response.Restored[0].Restored)
+	}
+	context.Close()
+	assert.Equal(t, []string{
+		"echo '+1d' > /tmp/endly.faketimerc",
+		"export APP_CLOCK_OFFSET_MS='60000'",
+		"echo '+2d' > /tmp/endly.faketimerc",
+		"unset APP_CLOCK_OFFSET_MS",
+		"echo '+0' > /tmp/endly.faketimerc",
+		"echo '+0' > /tmp/endly.faketimerc",
+	}, commands)
+}
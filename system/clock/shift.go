@@ -0,0 +1,106 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var shiftsKey = (*shifts)(nil)
+
+//shifts represents context active time shifts
+type shifts struct {
+	mux    *sync.Mutex
+	seq    int
+	active []*Shift
+}
+
+func (s *shifts) add(shift *Shift) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.seq++
+	shift.ID = fmt.Sprintf("%v-%v", shift.Mode, s.seq)
+	s.active = append(s.active, shift)
+}
+
+//take removes and returns active shifts with matching IDs or all active shifts if IDs are empty, the latest shift goes first
+func (s *shifts) take(IDs []string) []*Shift {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var result = make([]*Shift, 0)
+	var remaining = make([]*Shift, 0)
+	for _, shift := range s.active {
+		if matchesID(shift.ID, IDs) {
+			result = append([]*Shift{shift}, result...)
+			continue
+		}
+		remaining = append(remaining, shift)
+	}
+	s.active = remaining
+	return result
+}
+
+func matchesID(ID string, IDs []string) bool {
+	if len(IDs) == 0 {
+		return true
+	}
+	for _, candidate := range IDs {
+		if candidate == ID {
+			return true
+		}
+	}
+	return false
+}
+
+func newShifts() *shifts {
+	return &shifts{mux: &sync.Mutex{}, active: make([]*Shift, 0)}
+}
+
+func quote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+//spec returns libfaketime spec or env variable value
+func (r *SetRequest) spec() string {
+	if r.Offset != "" {
+		if r.Mode == ModeEnv {
+			return fmt.Sprintf("%v", int64(r.offsetDuration()/time.Millisecond))
+		}
+		return r.Offset
+	}
+	at, _ := r.atTime()
+	if r.Mode == ModeEnv {
+		return at.Format(time.RFC3339)
+	}
+	if r.Freeze {
+		return at.Format(faketimeLayout)
+	}
+	return "@" + at.Format(faketimeLayout)
+}
+
+//newShift returns time shift with set and restore commands
+func newShift(request *SetRequest) *Shift {
+	var result = &Shift{Mode: request.Mode, Spec: request.spec()}
+	writeSpec := func(spec string) string {
+		return fmt.Sprintf("echo %v > %v", quote(spec), request.Path)
+	}
+	switch request.Mode {
+	case ModeEnv:
+		result.Set = []string{fmt.Sprintf("export %v=%v", request.Env, quote(result.Spec))}
+		result.Restore = []string{fmt.Sprintf("unset %v", request.Env)}
+		return result
+	case ModeContainer:
+		result.Set = []string{fmt.Sprintf("docker exec %v sh -c %v", request.Container, quote(writeSpec(result.Spec)))}
+		result.Restore = []string{fmt.Sprintf("docker exec %v sh -c %v", request.Container, quote(writeSpec("+0")))}
+	default:
+		result.Set = []string{writeSpec(result.Spec)}
+		result.Restore = []string{writeSpec("+0")}
+	}
+	result.Env = map[string]string{
+		"LD_PRELOAD":              request.Library,
+		"FAKETIME_TIMESTAMP_FILE": request.Path,
+		"FAKETIME_NO_CACHE":       "1",
+	}
+	return result
+}
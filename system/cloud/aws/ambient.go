@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/viant/toolbox/cred"
+	"net/http"
+	"time"
+)
+
+//metadataTimeout limits instance metadata region lookup outside EC2
+var metadataTimeout = time.Second
+
+const defaultSTSRegion = "us-east-1"
+
+//GetAmbientConfig returns *aws.Config with credentials resolved by AWS default chain: env variables, shared config,
+//web identity token (EKS IRSA), ECS task role or EC2 instance profile, supplied config region and account ID are updated
+func GetAmbientConfig(config *cred.Config) (*aws.Config, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aws session: %v", err)
+	}
+	if _, err = sess.Config.Credentials.Get(); err != nil {
+		return nil, fmt.Errorf("failed to get aws credentials: %v", err)
+	}
+	if config.Region == "" {
+		config.Region = aws.StringValue(sess.Config.Region)
+	}
+	if config.Region == "" {
+		metadata := ec2metadata.New(sess, aws.NewConfig().WithHTTPClient(&http.Client{Timeout: metadataTimeout}).WithMaxRetries(0))
+		if metadata.Available() {
+			config.Region, _ = metadata.Region()
+		}
+	}
+	awsConfig := aws.NewConfig().WithRegion(config.Region).WithCredentials(sess.Config.Credentials)
+	if config.AccountID == "" {
+		stsConfig := awsConfig.Copy()
+		if config.Region == "" {
+			stsConfig.Region = aws.String(defaultSTSRegion)
+		}
+		output, err := sts.New(sess, stsConfig).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get aws caller identity: %v", err)
+		}
+		config.AccountID = aws.StringValue(output.Account)
+	}
+	return awsConfig, nil
+}
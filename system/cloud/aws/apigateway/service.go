@@ -311,8 +311,7 @@ func (s *service) setupResource(context *endly.Context, setup *SetupResourceInpu
 }
 
 func (s *service) setupResourceMethod(context *endly.Context, api *apigateway.RestApi, resource *apigateway.Resource, resourceMethod *ResourceMethod) (*apigateway.Method, error) {
-	var state = context.State()
-	state = state.Clone()
+	var state = context.SafeState().Clone()
 	if resourceMethod.FunctionName != "" {
 		function, err := aws.GetFunctionConfiguration(context, resourceMethod.FunctionName)
 		if err != nil {
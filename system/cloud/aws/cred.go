@@ -68,11 +68,22 @@ func InitCredentials(context *endly.Context, rawRequest map[string]interface{},
 				return awsConfig, nil
 			}
 		}
-		return nil, fmt.Errorf("unable to create clinet %T, credentials attribute was empty", key)
 	}
-	config, err := context.Secrets.GetCredentials(secrets.Credentials)
-	if err != nil {
-		return nil, err
+	var config *cred.Config
+	var awsConfig *aws.Config
+	var err error
+	if endly.IsAmbientCredentials(secrets.Credentials) {
+		config = &cred.Config{}
+		if awsConfig, err = GetAmbientConfig(config); err != nil {
+			return nil, fmt.Errorf("unable to create clinet %T, credentials attribute was empty and ambient credentials were not available: %v", key, err)
+		}
+	} else {
+		if config, err = context.Credentials(secrets.Credentials); err != nil {
+			return nil, err
+		}
+		if awsConfig, err = GetAWSCredentialConfig(config); err != nil {
+			return nil, err
+		}
 	}
 	if context.Contains(key) {
 		context.Remove(key)
@@ -80,11 +91,6 @@ func InitCredentials(context *endly.Context, rawRequest map[string]interface{},
 	if context.Contains(configKey) {
 		context.Remove(configKey)
 	}
-
-	awsConfig, err := GetAWSCredentialConfig(config)
-	if err != nil {
-		return nil, err
-	}
 	if config.RoleARN != "" {
 		region := config.Region
 		if region == "" {
@@ -107,7 +113,7 @@ func InitCredentials(context *endly.Context, rawRequest map[string]interface{},
 		}
 		awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(*result.Credentials.AccessKeyId, *result.Credentials.SecretAccessKey, *result.Credentials.SessionToken))
 	}
-	state := context.State()
+	state := context.SafeState()
 	awsMap := data.NewMap()
 	awsMap.Put("region", awsConfig.Region)
 	awsMap.Put("accountID", config.AccountID)
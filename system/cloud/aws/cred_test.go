@@ -6,6 +6,7 @@ import (
 	"github.com/viant/endly"
 
 	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/cred"
 	"github.com/viant/toolbox/secret"
 	"os"
 	"path"
@@ -47,3 +48,24 @@ func Test_GetClient(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, len(output.Users) > 0)
 }
+
+func Test_GetAmbientConfig(t *testing.T) {
+	for key, value := range map[string]string{"AWS_ACCESS_KEY_ID": "AKIDTEST", "AWS_SECRET_ACCESS_KEY": "secret", "AWS_SHARED_CREDENTIALS_FILE": "/dev/null", "AWS_CONFIG_FILE": "/dev/null"} {
+		if previous, ok := os.LookupEnv(key); ok {
+			defer os.Setenv(key, previous)
+		} else {
+			defer os.Unsetenv(key)
+		}
+		_ = os.Setenv(key, value)
+	}
+	config := &cred.Config{Region: "us-west-2", AccountID: "123456789012"}
+	awsConfig, err := GetAmbientConfig(config)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "us-west-2", *awsConfig.Region)
+	value, err := awsConfig.Credentials.Get()
+	if assert.Nil(t, err) {
+		assert.Equal(t, "AKIDTEST", value.AccessKeyID)
+	}
+}
@@ -0,0 +1,308 @@
+package ec2
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/cloud/aws"
+	"time"
+)
+
+const (
+	defaultInstanceType     = "t3.micro"
+	defaultStatusTimeoutMs  = 300000
+	defaultStatusIntervalMs = 5000
+)
+
+//WaitOptions represents instance status wait options
+type WaitOptions struct {
+	TimeoutMs  int `description:"max wait time, default 300000"`
+	IntervalMs int `description:"status check interval, default 5000"`
+}
+
+//RunInstanceInput represents a request to run a new instance
+type RunInstanceInput struct {
+	Name             string `description:"Name tag"`
+	ImageID          string `required:"true" description:"AMI id"`
+	InstanceType     string `description:"instance type, default t3.micro"`
+	KeyName          string
+	SubnetID         string
+	SecurityGroupIDs []string
+	Tags             map[string]string
+	UserData         string `description:"startup script, base64 encoded when needed"`
+	Count            int    `description:"number of instances, default 1"`
+	Wait             bool   `description:"wait until instances are running"`
+	WaitOptions
+}
+
+//RunInstanceOutput represents run instance response
+type RunInstanceOutput struct {
+	Instances []*ec2.Instance
+}
+
+//InstanceInput represents an instance lifecycle request: start, stop or terminate
+type InstanceInput struct {
+	Filter
+	Wait bool `description:"wait until instance reaches target status"`
+	WaitOptions
+}
+
+//InstanceOutput represents an instance lifecycle response
+type InstanceOutput struct {
+	InstanceID string
+	State      string
+}
+
+//WaitForStatusInput represents a request to wait until instance reaches expected status
+type WaitForStatusInput struct {
+	Filter
+	Status string `required:"true" description:"expected instance state: pending, running, stopping, stopped, shutting-down, terminated"`
+	WaitOptions
+}
+
+//WaitForStatusOutput represents wait for status response
+type WaitForStatusOutput struct {
+	*ec2.Instance
+	ElapsedMs int
+}
+
+//Init initialises wait options
+func (o *WaitOptions) Init() {
+	if o.TimeoutMs == 0 {
+		o.TimeoutMs = defaultStatusTimeoutMs
+	}
+	if o.IntervalMs == 0 {
+		o.IntervalMs = defaultStatusIntervalMs
+	}
+}
+
+//Init initialises request
+func (i *RunInstanceInput) Init() error {
+	if i.InstanceType == "" {
+		i.InstanceType = defaultInstanceType
+	}
+	if i.Count == 0 {
+		i.Count = 1
+	}
+	if len(i.Tags) == 0 {
+		i.Tags = make(map[string]string)
+	}
+	if i.Name != "" {
+		i.Tags["Name"] = i.Name
+	}
+	if i.UserData != "" {
+		if _, err := base64.StdEncoding.DecodeString(i.UserData); err != nil {
+			i.UserData = base64.StdEncoding.EncodeToString([]byte(i.UserData))
+		}
+	}
+	i.WaitOptions.Init()
+	return nil
+}
+
+//Validate checks if request is valid
+func (i *RunInstanceInput) Validate() error {
+	if i.ImageID == "" {
+		return errors.New("imageID was empty")
+	}
+	return nil
+}
+
+//Init initialises request
+func (i *InstanceInput) Init() error {
+	i.WaitOptions.Init()
+	return i.Filter.Init()
+}
+
+//Validate checks if request is valid
+func (i *InstanceInput) Validate() error {
+	return validateInstanceFilter(&i.Filter)
+}
+
+//Init initialises request
+func (i *WaitForStatusInput) Init() error {
+	i.WaitOptions.Init()
+	return i.Filter.Init()
+}
+
+//Validate checks if request is valid
+func (i *WaitForStatusInput) Validate() error {
+	if i.Status == "" {
+		return errors.New("status was empty")
+	}
+	return validateInstanceFilter(&i.Filter)
+}
+
+//validateInstanceFilter checks if filter identifies an instance
+func validateInstanceFilter(f *Filter) error {
+	if f.ID == "" && len(f.Tags) == 0 {
+		return errors.New("instance ID and name/tags were empty")
+	}
+	return nil
+}
+
+//instanceState returns instance state name
+func instanceState(instance *ec2.Instance) string {
+	if instance == nil || instance.State == nil || instance.State.Name == nil {
+		return ""
+	}
+	return *instance.State.Name
+}
+
+func (s *service) runInstance(context *endly.Context, input *RunInstanceInput) (*RunInstanceOutput, error) {
+	client, err := GetClient(context)
+	if err != nil {
+		return nil, err
+	}
+	request := &ec2.RunInstancesInput{
+		ImageId:      awssdk.String(input.ImageID),
+		InstanceType: awssdk.String(input.InstanceType),
+		MinCount:     awssdk.Int64(int64(input.Count)),
+		MaxCount:     awssdk.Int64(int64(input.Count)),
+	}
+	if input.KeyName != "" {
+		request.KeyName = awssdk.String(input.KeyName)
+	}
+	if input.SubnetID != "" {
+		request.SubnetId = awssdk.String(input.SubnetID)
+	}
+	if len(input.SecurityGroupIDs) > 0 {
+		request.SecurityGroupIds = awssdk.StringSlice(input.SecurityGroupIDs)
+	}
+	if input.UserData != "" {
+		request.UserData = awssdk.String(input.UserData)
+	}
+	if len(input.Tags) > 0 {
+		var tags = make([]*ec2.Tag, 0)
+		for key, value := range input.Tags {
+			tags = append(tags, &ec2.Tag{Key: awssdk.String(key), Value: awssdk.String(value)})
+		}
+		request.TagSpecifications = []*ec2.TagSpecification{{ResourceType: awssdk.String(ec2.ResourceTypeInstance), Tags: tags}}
+	}
+	reservation, err := client.RunInstances(request)
+	if err != nil {
+		return nil, err
+	}
+	var output = &RunInstanceOutput{Instances: reservation.Instances}
+	if !input.Wait {
+		return output, nil
+	}
+	for i, instance := range output.Instances {
+		waitOutput, err := s.waitForStatus(context, &WaitForStatusInput{Filter: Filter{ID: *instance.InstanceId}, Status: ec2.InstanceStateNameRunning, WaitOptions: input.WaitOptions})
+		if err != nil {
+			return output, err
+		}
+		output.Instances[i] = waitOutput.Instance
+	}
+	return output, nil
+}
+
+//lookupInstance returns instance matched by ID or tags
+func (s *service) lookupInstance(context *endly.Context, filter *Filter) (*ec2.Instance, error) {
+	client, err := GetClient(context)
+	if err != nil {
+		return nil, err
+	}
+	if filter.ID == "" {
+		output, err := s.getInstance(context, &GetInstanceInput{Filter: *filter})
+		if err != nil {
+			return nil, err
+		}
+		if output.Instance == nil {
+			return nil, fmt.Errorf("instance was not found: %v", filter.Tags)
+		}
+		return output.Instance, nil
+	}
+	output, err := client.DescribeInstances(&ec2.DescribeInstancesInput{InstanceIds: []*string{awssdk.String(filter.ID)}})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "InvalidInstanceID.NotFound" {
+			return nil, fmt.Errorf("instance was not found: %v", filter.ID)
+		}
+		return nil, err
+	}
+	for _, reservation := range output.Reservations {
+		for _, instance := range reservation.Instances {
+			return instance, nil
+		}
+	}
+	return nil, fmt.Errorf("instance was not found: %v", filter.ID)
+}
+
+func (s *service) waitForStatus(context *endly.Context, input *WaitForStatusInput) (*WaitForStatusOutput, error) {
+	var output = &WaitForStatusOutput{}
+	startTime := time.Now()
+	timeout := time.Duration(input.TimeoutMs) * time.Millisecond
+	state := ""
+	for {
+		instance, err := s.lookupInstance(context, &input.Filter)
+		if err != nil {
+			return nil, err
+		}
+		output.Instance = instance
+		if state != instanceState(instance) {
+			state = instanceState(instance)
+			context.Publish(aws.NewOutputEvent(fmt.Sprintf("%v: %v", *instance.InstanceId, state), "waitForStatus", nil))
+		}
+		output.ElapsedMs = int(time.Since(startTime) / time.Millisecond)
+		if state == input.Status {
+			return output, nil
+		}
+		if state == ec2.InstanceStateNameTerminated {
+			return output, fmt.Errorf("instance %v was terminated, expected: %v", *instance.InstanceId, input.Status)
+		}
+		if time.Since(startTime) > timeout {
+			return output, fmt.Errorf("instance %v status timeout exceeded, expected: %v, but had: %v", *instance.InstanceId, input.Status, state)
+		}
+		s.Sleep(context, input.IntervalMs)
+	}
+}
+
+//changeState runs state change operation on matched instance, and optionally waits for target status
+func (s *service) changeState(context *endly.Context, input *InstanceInput, targetStatus string, change func(client *ec2.EC2, ids []*string) error) (*InstanceOutput, error) {
+	client, err := GetClient(context)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := s.lookupInstance(context, &input.Filter)
+	if err != nil {
+		return nil, err
+	}
+	var output = &InstanceOutput{InstanceID: *instance.InstanceId, State: instanceState(instance)}
+	if output.State != targetStatus {
+		if err = change(client, []*string{instance.InstanceId}); err != nil {
+			return nil, err
+		}
+	}
+	if !input.Wait {
+		return output, nil
+	}
+	waitOutput, err := s.waitForStatus(context, &WaitForStatusInput{Filter: Filter{ID: output.InstanceID}, Status: targetStatus, WaitOptions: input.WaitOptions})
+	if waitOutput != nil {
+		output.State = instanceState(waitOutput.Instance)
+	}
+	return output, err
+}
+
+func (s *service) startInstance(context *endly.Context, input *InstanceInput) (*InstanceOutput, error) {
+	return s.changeState(context, input, ec2.InstanceStateNameRunning, func(client *ec2.EC2, ids []*string) error {
+		_, err := client.StartInstances(&ec2.StartInstancesInput{InstanceIds: ids})
+		return err
+	})
+}
+
+func (s *service) stopInstance(context *endly.Context, input *InstanceInput) (*InstanceOutput, error) {
+	return s.changeState(context, input, ec2.InstanceStateNameStopped, func(client *ec2.EC2, ids []*string) error {
+		_, err := client.StopInstances(&ec2.StopInstancesInput{InstanceIds: ids})
+		return err
+	})
+}
+
+func (s *service) terminateInstance(context *endly.Context, input *InstanceInput) (*InstanceOutput, error) {
+	return s.changeState(context, input, ec2.InstanceStateNameTerminated, func(client *ec2.EC2, ids []*string) error {
+		_, err := client.TerminateInstances(&ec2.TerminateInstancesInput{InstanceIds: ids})
+		return err
+	})
+}
@@ -0,0 +1,93 @@
+package ec2
+
+import (
+	"fmt"
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+//fakeEC2 emulates EC2 query API for a single instance, each describe call advances pending state to the target state
+type fakeEC2 struct {
+	mux     *sync.Mutex
+	state   string
+	target  string
+	actions []string
+}
+
+func (f *fakeEC2) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	_ = request.ParseForm()
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	action := request.Form.Get("Action")
+	f.actions = append(f.actions, action)
+	instance := func() string {
+		return fmt.Sprintf(`<instancesSet><item><instanceId>i-1</instanceId><instanceState><code>0</code><name>%v</name></instanceState></item></instancesSet>`, f.state)
+	}
+	var body string
+	switch action {
+	case "RunInstances":
+		f.state, f.target = ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning
+		body = `<reservationId>r-1</reservationId>` + instance()
+	case "StopInstances":
+		f.state, f.target = ec2.InstanceStateNameStopping, ec2.InstanceStateNameStopped
+	case "StartInstances":
+		f.state, f.target = ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning
+	case "DescribeInstances":
+		body = `<reservationSet><item><reservationId>r-1</reservationId>` + instance() + `</item></reservationSet>`
+		f.state = f.target
+	}
+	writer.Header().Set("Content-Type", "text/xml")
+	_, _ = writer.Write([]byte(fmt.Sprintf(`<%vResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><requestId>1</requestId>%v</%vResponse>`, action, body, action)))
+}
+
+func TestService_InstanceLifecycle(t *testing.T) {
+	fake := &fakeEC2{mux: &sync.Mutex{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+	sess := session.Must(session.NewSession(&awssdk.Config{
+		Region:      awssdk.String("us-east-1"),
+		Endpoint:    awssdk.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	if !assert.Nil(t, context.Put(clientKey, ec2.New(sess))) {
+		return
+	}
+
+	runOutput := &RunInstanceOutput{}
+	err := endly.Run(context, &RunInstanceInput{Name: "e2e", ImageID: "ami-1", UserData: "#!/bin/bash", Wait: true, WaitOptions: WaitOptions{IntervalMs: 10, TimeoutMs: 1000}}, runOutput)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(runOutput.Instances)) {
+		assert.Equal(t, "running", instanceState(runOutput.Instances[0]))
+	}
+
+	service := New().(*service)
+	stopInput := &InstanceInput{Filter: Filter{ID: "i-1"}, Wait: true, WaitOptions: WaitOptions{IntervalMs: 10, TimeoutMs: 1000}}
+	assert.Nil(t, stopInput.Init())
+	output, err := service.stopInstance(context, stopInput)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "i-1", output.InstanceID)
+		assert.Equal(t, "stopped", output.State)
+	}
+	output, err = service.stopInstance(context, stopInput)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "stopped", output.State)
+	}
+	assert.Equal(t, []string{"RunInstances", "DescribeInstances", "DescribeInstances", "DescribeInstances", "StopInstances", "DescribeInstances", "DescribeInstances", "DescribeInstances", "DescribeInstances"}, fake.actions)
+
+	waitInput := &WaitForStatusInput{Filter: Filter{ID: "i-1"}, Status: "running", WaitOptions: WaitOptions{IntervalMs: 10, TimeoutMs: 50}}
+	assert.Nil(t, waitInput.Init())
+	_, err = service.waitForStatus(context, waitInput)
+	assert.NotNil(t, err)
+
+	assert.NotNil(t, (&WaitForStatusInput{}).Validate())
+	assert.NotNil(t, (&InstanceInput{}).Validate())
+}
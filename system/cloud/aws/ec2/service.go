@@ -165,6 +165,121 @@ func (s *service) registerRoutes() {
 			return nil, fmt.Errorf("unsupported request type: %T", request)
 		},
 	})
+
+	s.Register(&endly.Route{
+		Action: "runInstance",
+		RequestInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T.%v(%T)", s, "runInstance", &RunInstanceInput{}),
+		},
+		ResponseInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T", &RunInstanceOutput{}),
+		},
+		RequestProvider: func() interface{} {
+			return &RunInstanceInput{}
+		},
+		ResponseProvider: func() interface{} {
+			return &RunInstanceOutput{}
+		},
+		OnRawRequest: setClient,
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*RunInstanceInput); ok {
+				return s.runInstance(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "startInstance",
+		RequestInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T.%v(%T)", s, "startInstance", &InstanceInput{}),
+		},
+		ResponseInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T", &InstanceOutput{}),
+		},
+		RequestProvider: func() interface{} {
+			return &InstanceInput{}
+		},
+		ResponseProvider: func() interface{} {
+			return &InstanceOutput{}
+		},
+		OnRawRequest: setClient,
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*InstanceInput); ok {
+				return s.startInstance(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "stopInstance",
+		RequestInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T.%v(%T)", s, "stopInstance", &InstanceInput{}),
+		},
+		ResponseInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T", &InstanceOutput{}),
+		},
+		RequestProvider: func() interface{} {
+			return &InstanceInput{}
+		},
+		ResponseProvider: func() interface{} {
+			return &InstanceOutput{}
+		},
+		OnRawRequest: setClient,
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*InstanceInput); ok {
+				return s.stopInstance(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "terminateInstance",
+		RequestInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T.%v(%T)", s, "terminateInstance", &InstanceInput{}),
+		},
+		ResponseInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T", &InstanceOutput{}),
+		},
+		RequestProvider: func() interface{} {
+			return &InstanceInput{}
+		},
+		ResponseProvider: func() interface{} {
+			return &InstanceOutput{}
+		},
+		OnRawRequest: setClient,
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*InstanceInput); ok {
+				return s.terminateInstance(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "waitForStatus",
+		RequestInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T.%v(%T)", s, "waitForStatus", &WaitForStatusInput{}),
+		},
+		ResponseInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T", &WaitForStatusOutput{}),
+		},
+		RequestProvider: func() interface{} {
+			return &WaitForStatusInput{}
+		},
+		ResponseProvider: func() interface{} {
+			return &WaitForStatusOutput{}
+		},
+		OnRawRequest: setClient,
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*WaitForStatusInput); ok {
+				return s.waitForStatus(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
 }
 
 func (s *service) getVpcConfig(context *endly.Context, input *GetVpcConfigInput) (*GetVpcConfigOutput, error) {
@@ -78,7 +78,7 @@ func (s *service) recreateRole(context *endly.Context, request *RecreateRoleInpu
 			return nil, err
 		}
 	}
-	state := context.State()
+	state := context.SafeState()
 	if request.AssumeRolePolicyDocument != nil {
 		*request.AssumeRolePolicyDocument = state.ExpandAsText(*request.AssumeRolePolicyDocument)
 	}
@@ -95,7 +95,7 @@ func (s *service) setupRole(context *endly.Context, request *SetupRolePolicyInpu
 		RoleName: request.RoleName,
 	})
 
-	state := context.State()
+	state := context.SafeState()
 	if request.AssumeRolePolicyDocument != nil {
 		*request.AssumeRolePolicyDocument = state.ExpandAsText(*request.AssumeRolePolicyDocument)
 	}
@@ -143,7 +143,7 @@ func (s *service) setupRolePolicy(context *endly.Context, role *iam.Role, reques
 	if err != nil {
 		return false, err
 	}
-	state := context.State()
+	state := context.SafeState()
 	if len(request.Define) == 0 {
 		return false, err
 	}
@@ -0,0 +1,138 @@
+package lambda
+
+import (
+	"encoding/json"
+	"fmt"
+	aaws "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/endly/system/cloud/aws/logs"
+	"github.com/viant/endly/testing/validator"
+	"github.com/viant/toolbox"
+	"time"
+)
+
+//logGroupTemplate represents lambda CloudWatch log group name template
+const logGroupTemplate = "/aws/lambda/%v"
+
+//encodePayload returns invocation payload, structured payload is JSON encoded
+func encodePayload(payload interface{}) ([]byte, error) {
+	switch value := payload.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return value, nil
+	case string:
+		return []byte(value), nil
+	}
+	return json.Marshal(payload)
+}
+
+//decodePayload returns JSON decoded response payload or text
+func decodePayload(payload []byte) interface{} {
+	if len(payload) == 0 {
+		return nil
+	}
+	text := toolbox.AsString(payload)
+	if toolbox.IsStructuredJSON(text) {
+		var result interface{}
+		if err := json.Unmarshal(payload, &result); err == nil {
+			return result
+		}
+	}
+	return text
+}
+
+func (s *service) assert(context *endly.Context, request *AssertInput) (*AssertOutput, error) {
+	client, err := GetClient(context)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := encodePayload(request.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %v payload: %v", request.FunctionName, err)
+	}
+	input := &lambda.InvokeInput{FunctionName: aaws.String(request.FunctionName), Payload: payload}
+	if request.Qualifier != "" {
+		input.Qualifier = aaws.String(request.Qualifier)
+	}
+	startTime := time.Now().Add(-time.Second)
+	invokeOutput, err := client.Invoke(input)
+	if err != nil {
+		return nil, err
+	}
+	var response = &AssertOutput{Response: decodePayload(invokeOutput.Payload)}
+	if invokeOutput.StatusCode != nil {
+		response.StatusCode = *invokeOutput.StatusCode
+	}
+	if invokeOutput.FunctionError != nil {
+		response.FunctionError = *invokeOutput.FunctionError
+	}
+	if request.Expect != nil {
+		if response.Assert, err = validator.Assert(context, request, request.Expect, response.Response, "lambda.response", "assert function response"); err != nil {
+			return response, err
+		}
+	}
+	if request.Logs != nil {
+		err = s.assertLogs(context, request, startTime, response)
+	}
+	return response, err
+}
+
+//assertLogs fetches function log messages since invocation until all expected records are matched or retries are exhausted
+func (s *service) assertLogs(context *endly.Context, request *AssertInput, startTime time.Time, response *AssertOutput) error {
+	filter := &logs.FilterLogEventMessagesInput{Include: request.Logs.Include}
+	filter.LogGroupName = aaws.String(fmt.Sprintf(logGroupTemplate, request.FunctionName))
+	filter.StartTime = aaws.Int64(startTime.UnixNano() / int64(time.Millisecond))
+	var matched []interface{}
+	for i := 0; i < request.Logs.LogWaitRetryCount; i++ {
+		if i > 0 {
+			s.Sleep(context, request.Logs.LogWaitTimeMs)
+		}
+		filterOutput := &logs.FilterLogEventMessagesOutput{}
+		if err := endly.Run(context, filter, filterOutput); err != nil {
+			return err
+		}
+		response.Logs = filterOutput.Messages
+		var err error
+		if matched, err = matchLogRecords(context, request.Logs.Records, response.Logs); err != nil {
+			return err
+		}
+		if len(matched) == len(request.Logs.Records) {
+			break
+		}
+	}
+	var err error
+	response.LogAssert, err = validator.Assert(context, request, request.Logs.Records, matched, "lambda.logs", "assert function logs")
+	return err
+}
+
+//matchLogRecords matches expected records with log messages in order, it returns matched messages
+func matchLogRecords(context *endly.Context, expected []interface{}, messages []interface{}) ([]interface{}, error) {
+	var result = make([]interface{}, 0)
+	position := 0
+	for _, expectedRecord := range expected {
+		found := false
+		for ; position < len(messages); position++ {
+			actual := messages[position]
+			if text, ok := actual.(*string); ok && text != nil {
+				actual = *text
+			}
+			validation, err := criteria.Assert(context, fmt.Sprintf("logs[%v]", position), expectedRecord, actual)
+			if err != nil {
+				return nil, err
+			}
+			if !validation.HasFailure() {
+				result = append(result, actual)
+				position++
+				found = true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package lambda
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestService_Assert(t *testing.T) {
+	var logGroup string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		if strings.HasSuffix(request.Header.Get("X-Amz-Target"), "FilterLogEvents") {
+			filter := map[string]interface{}{}
+			_ = json.Unmarshal(body, &filter)
+			logGroup, _ = filter["logGroupName"].(string)
+			writer.Header().Set("Content-Type", "application/x-amz-json-1.1")
+			_, _ = writer.Write([]byte(`{"events":[{"message":"START RequestId: 1"},{"message":"{\"level\":\"info\",\"msg\":\"greeting Endly\"}"},{"message":"END RequestId: 1"}]}`))
+			return
+		}
+		var payload map[string]interface{}
+		_ = json.Unmarshal(body, &payload)
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = writer.Write([]byte(`{"message":"Hello ` + payload["name"].(string) + `"}`))
+	}))
+	defer server.Close()
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+	assert.Nil(t, context.Put(clientKey, lambda.New(sess)))
+	assert.Nil(t, context.Put((*cloudwatchlogs.CloudWatchLogs)(nil), cloudwatchlogs.New(sess)))
+
+	service := New().(*service)
+	request := &AssertInput{
+		FunctionName: "HelloWorld",
+		Payload:      map[string]interface{}{"name": "Endly"},
+		Expect:       map[string]interface{}{"message": "/Endly/"},
+		Logs: &LogExpect{
+			Records: []interface{}{
+				"/START/",
+				map[string]interface{}{"msg": "greeting Endly"},
+			},
+			LogWaitTimeMs: 1,
+		},
+	}
+	assert.Nil(t, request.Init())
+	assert.Nil(t, request.Validate())
+	response, err := service.assert(context, request)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "/aws/lambda/HelloWorld", logGroup)
+	assert.EqualValues(t, map[string]interface{}{"message": "Hello Endly"}, response.Response)
+	assert.Equal(t, 3, len(response.Logs))
+	assert.False(t, response.Assert.HasFailure())
+	assert.False(t, response.LogAssert.HasFailure())
+
+	request.Logs.Records = []interface{}{"/END/", "/START/"}
+	request.Logs.LogWaitRetryCount = 2
+	response, err = service.assert(context, request)
+	if assert.Nil(t, err) {
+		assert.True(t, response.LogAssert.HasFailure())
+	}
+	assert.NotNil(t, (&AssertInput{}).Validate())
+}
+
+func TestPackageSource(t *testing.T) {
+	baseDir := path.Join(os.TempDir(), "test", "endly", "lambda")
+	_ = os.RemoveAll(baseDir)
+	_ = os.MkdirAll(baseDir, 0755)
+	defer os.RemoveAll(baseDir)
+	_ = ioutil.WriteFile(path.Join(baseDir, "handler.py"), []byte("def handle(event, context):\n    return event\n"), 0644)
+	_ = ioutil.WriteFile(path.Join(baseDir, "old.zip"), []byte("stale"), 0644)
+
+	data, err := packageSource(url.NewResource(baseDir))
+	if !assert.Nil(t, err) {
+		return
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if !assert.Nil(t, err) {
+		return
+	}
+	var names = make([]string, 0)
+	for _, file := range archive.File {
+		names = append(names, path.Base(file.Name))
+	}
+	assert.Equal(t, []string{"handler.py"}, names)
+
+	data, err = packageSource(url.NewResource(path.Join(baseDir, "handler.py")))
+	if assert.Nil(t, err) {
+		archive, err = zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if assert.Nil(t, err) && assert.Equal(t, 1, len(archive.File)) {
+			assert.Equal(t, "handler.py", archive.File[0].Name)
+		}
+	}
+}
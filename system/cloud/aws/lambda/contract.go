@@ -6,9 +6,16 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/viant/endly/system/cloud/aws/ec2"
 	ciam "github.com/viant/endly/system/cloud/aws/iam"
+	"github.com/viant/endly/testing/validator"
+	"github.com/viant/toolbox/url"
 	"time"
 )
 
+const (
+	defaultLogWaitTimeMs     = 3000
+	defaultLogWaitRetryCount = 10
+)
+
 //RecreateFunctionInput drops function if exist to create a new one
 type RecreateFunctionInput lambda.CreateFunctionInput
 
@@ -29,7 +36,8 @@ type EventSourceMapping struct {
 type DeployInput struct {
 	lambda.CreateFunctionInput `yaml:",inline" json:",inline"`
 	ciam.SetupRolePolicyInput  ` json:",inline"`
-	PresetRoleName             string `description:"in case that role is set - deployment skip permission setup"`
+	PresetRoleName             string        `description:"in case that role is set - deployment skip permission setup"`
+	Source                     *url.Resource `description:"function source location, directory content is zipped into code.zipFile"`
 	VpcMatcher                 *ec2.GetVpcConfigInput
 	Triggers                   []*EventSourceMapping
 	Http                       *lambda.CreateFunctionUrlConfigInput
@@ -67,8 +75,65 @@ type CallOutput struct {
 	Response interface{}
 }
 
+//AssertInput represents a function invocation with JSON payload, expected response and log records
+type AssertInput struct {
+	FunctionName string      `required:"true"`
+	Qualifier    string      `description:"function version or alias"`
+	Payload      interface{} `description:"invocation payload, map or slice is JSON encoded"`
+	Expect       interface{} `description:"expected response"`
+	Logs         *LogExpect  `description:"expected CloudWatch log records"`
+}
+
+//LogExpect represents expected function CloudWatch log records
+type LogExpect struct {
+	Records           []interface{} `description:"ordered expected log records, JSON messages are matched as map"`
+	Include           []string      `description:"fetch only log messages containing any of the fragments"`
+	LogWaitTimeMs     int           `description:"wait time between log fetch attempts, default 3000"`
+	LogWaitRetryCount int           `description:"max log fetch attempts, default 10"`
+}
+
+//AssertOutput represents a function invocation assertion response
+type AssertOutput struct {
+	StatusCode    int64
+	FunctionError string `json:",omitempty"`
+	Response      interface{}
+	Logs          []interface{}             `json:",omitempty"`
+	Assert        *validator.AssertResponse `json:",omitempty"`
+	LogAssert     *validator.AssertResponse `json:",omitempty"`
+}
+
+//Init initializes assert request
+func (i *AssertInput) Init() error {
+	if i.Logs == nil {
+		return nil
+	}
+	if i.Logs.LogWaitTimeMs == 0 {
+		i.Logs.LogWaitTimeMs = defaultLogWaitTimeMs
+	}
+	if i.Logs.LogWaitRetryCount == 0 {
+		i.Logs.LogWaitRetryCount = defaultLogWaitRetryCount
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (i *AssertInput) Validate() error {
+	if i.FunctionName == "" {
+		return errors.New("functionName was empty")
+	}
+	if i.Logs != nil && len(i.Logs.Records) == 0 {
+		return errors.New("logs.records were empty")
+	}
+	return nil
+}
+
 //Init initializes deploy request
 func (i *DeployInput) Init() error {
+	if i.Source != nil {
+		if err := i.Source.Init(); err != nil {
+			return err
+		}
+	}
 	if i.DefaultPolicyDocument == nil {
 		policyDocument := string(DefaultTrustPolicy)
 		i.DefaultPolicyDocument = &policyDocument
@@ -98,8 +163,8 @@ func (i *DeployInput) Validate() error {
 	if i.CreateFunctionInput.FunctionName == nil {
 		return fmt.Errorf("functionName was empty")
 	}
-	if i.CreateFunctionInput.Code == nil {
-		return fmt.Errorf("code was empty")
+	if i.CreateFunctionInput.Code == nil && i.Source == nil {
+		return fmt.Errorf("code/source was empty")
 	}
 
 	if i.SetupRolePolicyInput.RoleName == nil && i.PresetRoleName == "" {
@@ -0,0 +1,51 @@
+package lambda
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/viant/toolbox/storage"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"strings"
+)
+
+//packageSource returns zipped function source, file source is returned as is when it is already a zip archive
+func packageSource(resource *url.Resource) ([]byte, error) {
+	storageService, err := storage.NewServiceForURL(resource.URL, resource.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	object, err := storageService.StorageObject(resource.URL)
+	if err != nil {
+		return nil, err
+	}
+	writer := new(bytes.Buffer)
+	archive := zip.NewWriter(writer)
+	if object.IsContent() {
+		reader, err := storageService.Download(object)
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		content, err := ioutil.ReadAll(reader)
+		if err != nil || strings.HasSuffix(resource.URL, ".zip") {
+			return content, err
+		}
+		fileWriter, err := archive.Create(object.FileInfo().Name())
+		if err != nil {
+			return nil, err
+		}
+		if _, err = fileWriter.Write(content); err != nil {
+			return nil, err
+		}
+	} else {
+		err = storage.ArchiveWithFilter(storageService, resource.URL, archive, func(candidate storage.Object) bool {
+			return !strings.HasSuffix(candidate.FileInfo().Name(), ".zip")
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = archive.Close()
+	return writer.Bytes(), err
+}
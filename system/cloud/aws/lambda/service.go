@@ -163,7 +163,7 @@ func (s *service) deploy(context *endly.Context, request *DeployInput) (output *
 }
 
 func (s *service) expand(context *endly.Context, values ...*string) {
-	state := context.State()
+	state := context.SafeState()
 	for i := range values {
 		if values[i] == nil {
 			continue
@@ -178,6 +178,13 @@ func (s *service) deployFunctionInBackground(context *endly.Context, request *De
 	if err != nil {
 		return nil, err
 	}
+	if request.Source != nil {
+		zipFile, err := packageSource(request.Source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to package %v", request.Source.URL)
+		}
+		request.Code = &lambda.FunctionCode{ZipFile: zipFile}
+	}
 	if request.VpcMatcher != nil {
 		vpcOutput := &ec2.GetVpcConfigOutput{}
 		if err = endly.Run(context, request.VpcMatcher, vpcOutput); err != nil {
@@ -610,6 +617,33 @@ func (s *service) registerRoutes() {
 			return nil, fmt.Errorf("unsupported request type: %T", request)
 		},
 	})
+
+	s.Register(&endly.Route{
+		Action: "assert",
+		RequestInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T.%v(%T)", s, "assert", &AssertInput{}),
+		},
+		ResponseInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T", &AssertOutput{}),
+		},
+		RequestProvider: func() interface{} {
+			return &AssertInput{}
+		},
+		ResponseProvider: func() interface{} {
+			return &AssertOutput{}
+		},
+		OnRawRequest: setClient,
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*AssertInput); ok {
+				response, err := s.assert(context, req)
+				if err == nil {
+					context.Publish(aws.NewOutputEvent("assert", "lambda", response))
+				}
+				return response, err
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
 }
 
 //New creates a new AWS Ec2 service.
@@ -91,8 +91,7 @@ func (s *service) setupLambdaNotification(ctx *endly.Context, currentConfig *s3.
 	var result = make([]*s3.LambdaFunctionConfiguration, 0)
 	configuredLambdaFunctions := currentConfig.LambdaFunctionConfigurations
 	existingFunction := indexLambdaFunction(configuredLambdaFunctions)
-	var state = ctx.State()
-	state = state.Clone()
+	var state = ctx.SafeState().Clone()
 
 	for _, config := range request.NotificationConfiguration.LambdaFunctionConfigurations {
 		funcName := *config.FunctionName
@@ -129,7 +128,7 @@ func (s *service) setupLambdaNotification(ctx *endly.Context, currentConfig *s3.
 
 func (s *service) setupQueueNotification(context *endly.Context, configuration *s3.NotificationConfiguration, input *SetupBucketNotificationInput, output *SetupBucketNotificationOutput) ([]*s3.QueueConfiguration, error) {
 	var err error
-	state := context.State()
+	state := context.SafeState()
 	var result = make([]*s3.QueueConfiguration, 0)
 
 	//getBucketPolicy
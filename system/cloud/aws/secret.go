@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/viant/endly"
+	"regexp"
+	"strings"
+)
+
+var regionExpr = regexp.MustCompile(`^[a-z]{2}(-gov)?-[a-z]+-\d$`)
+
+//secretsManagerProvider resolves secrets with AWS Secrets Manager using default credentials chain, i.e. awssm://db, awssm://us-west-2/db
+type secretsManagerProvider struct{}
+
+func (p *secretsManagerProvider) Scheme() string {
+	return "awssm"
+}
+
+func (p *secretsManagerProvider) Secret(ctx context.Context, key string) (string, error) {
+	region, name := secretRegionAndName(key)
+	config := aws.NewConfig()
+	if region != "" {
+		config = config.WithRegion(region)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{Config: *config, SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return "", err
+	}
+	output, err := secretsmanager.New(sess).GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)})
+	if err != nil {
+		return "", err
+	}
+	if output.SecretString != nil {
+		return *output.SecretString, nil
+	}
+	return string(output.SecretBinary), nil
+}
+
+//secretRegionAndName splits optional region prefix from secret name
+func secretRegionAndName(key string) (string, string) {
+	key = strings.Trim(key, "/")
+	if index := strings.Index(key, "/"); index != -1 && regionExpr.MatchString(key[:index]) {
+		return key[:index], key[index+1:]
+	}
+	return "", key
+}
+
+func init() {
+	endly.RegisterSecretProvider(&secretsManagerProvider{})
+}
@@ -0,0 +1,24 @@
+package aws
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSecretRegionAndName(t *testing.T) {
+	var useCases = []struct {
+		key    string
+		region string
+		name   string
+	}{
+		{key: "db", name: "db"},
+		{key: "us-west-2/db", region: "us-west-2", name: "db"},
+		{key: "us-gov-east-1/app/db", region: "us-gov-east-1", name: "app/db"},
+		{key: "app/db", name: "app/db"},
+	}
+	for _, useCase := range useCases {
+		region, name := secretRegionAndName(useCase.key)
+		assert.Equal(t, useCase.region, region, useCase.key)
+		assert.Equal(t, useCase.name, name, useCase.key)
+	}
+}
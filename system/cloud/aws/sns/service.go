@@ -165,7 +165,7 @@ func (s *service) setupSubscription(context *endly.Context, request *SetupSubscr
 			TopicArn:        request.TopicArn,
 		}
 	}
-	state := context.State()
+	state := context.SafeState()
 	if *subscription.Protocol == "lambda" {
 		permissionInput := &lambda.SetupPermissionInput{}
 		functionName, _ := aws.ArnName(*request.Endpoint)
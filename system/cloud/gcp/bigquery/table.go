@@ -57,7 +57,7 @@ func (s *service) Table(context *endly.Context, request *TableRequest) (*TableRe
 			return nil, err
 		}
 		key := fmt.Sprintf("%v_%v", tableKey+request.TableId)
-		state := context.State()
+		state := context.SafeState()
 		state.Put(key, data)
 		if err = endly.Run(context, &storage.UploadRequest{SourceKey: key, Dest: dest}, nil); err != nil {
 			return nil, err
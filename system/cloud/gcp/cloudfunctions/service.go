@@ -36,7 +36,7 @@ type service struct {
 }
 
 func (s *service) expandWithContext(context *endly.Context, credConfig *cred.Config, region, text string) string {
-	state := context.State()
+	state := context.SafeState()
 	gcpNode := data.NewMap()
 	gcpNode.Put("projectID", credConfig.ProjectID)
 	gcpNode.Put("region", region)
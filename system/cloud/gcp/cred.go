@@ -113,9 +113,11 @@ func InitCredentials(context *endly.Context, rawRequest map[string]interface{})
 		}
 	}
 
-	credConfig, err := context.Secrets.GetCredentials(secrets.Credentials)
-	if err != nil {
-		credConfig = &cred.Config{}
+	credConfig := &cred.Config{} //ambient credentials are resolved with application default credentials, i.e. GKE workload identity
+	if !endly.IsAmbientCredentials(secrets.Credentials) {
+		if config, err := context.Credentials(secrets.Credentials); err == nil {
+			credConfig = config
+		}
 	}
 
 	config := &gcpCredConfig{Config: credConfig}
@@ -0,0 +1,60 @@
+package gcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/viant/endly"
+	"google.golang.org/api/secretmanager/v1"
+	"strings"
+)
+
+//secretManagerProvider resolves secrets with GCP Secret Manager using application default credentials, i.e. gcpsm://myproject/db, gcpsm://projects/myproject/secrets/db/versions/2
+type secretManagerProvider struct{}
+
+func (p *secretManagerProvider) Scheme() string {
+	return "gcpsm"
+}
+
+func (p *secretManagerProvider) Secret(ctx context.Context, key string) (string, error) {
+	name, err := secretVersionName(key)
+	if err != nil {
+		return "", err
+	}
+	service, err := secretmanager.NewService(ctx)
+	if err != nil {
+		return "", err
+	}
+	response, err := service.Projects.Secrets.Versions.Access(name).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	if response.Payload == nil {
+		return "", fmt.Errorf("secret %v payload was empty", name)
+	}
+	payload, err := base64.StdEncoding.DecodeString(response.Payload.Data)
+	return string(payload), err
+}
+
+//secretVersionName returns secret version resource name for project/secret[/version] or full resource name key
+func secretVersionName(key string) (string, error) {
+	key = strings.Trim(key, "/")
+	if strings.HasPrefix(key, "projects/") {
+		if !strings.Contains(key, "/versions/") {
+			key += "/versions/latest"
+		}
+		return key, nil
+	}
+	fragments := strings.Split(key, "/")
+	switch len(fragments) {
+	case 2:
+		return fmt.Sprintf("projects/%v/secrets/%v/versions/latest", fragments[0], fragments[1]), nil
+	case 3:
+		return fmt.Sprintf("projects/%v/secrets/%v/versions/%v", fragments[0], fragments[1], fragments[2]), nil
+	}
+	return "", fmt.Errorf("invalid secret key: %v, expected project/secret[/version]", key)
+}
+
+func init() {
+	endly.RegisterSecretProvider(&secretManagerProvider{})
+}
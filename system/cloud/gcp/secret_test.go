@@ -0,0 +1,28 @@
+package gcp
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSecretVersionName(t *testing.T) {
+	var useCases = []struct {
+		key      string
+		expect   string
+		hasError bool
+	}{
+		{key: "myproject/db", expect: "projects/myproject/secrets/db/versions/latest"},
+		{key: "myproject/db/2", expect: "projects/myproject/secrets/db/versions/2"},
+		{key: "projects/myproject/secrets/db", expect: "projects/myproject/secrets/db/versions/latest"},
+		{key: "projects/myproject/secrets/db/versions/3", expect: "projects/myproject/secrets/db/versions/3"},
+		{key: "db", hasError: true},
+	}
+	for _, useCase := range useCases {
+		name, err := secretVersionName(useCase.key)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.key)
+			continue
+		}
+		assert.Equal(t, useCase.expect, name, useCase.key)
+	}
+}
@@ -22,6 +22,7 @@ const (
 	serviceTypeLaunchCtl
 	serviceTypeStdService
 	serviceTypeSystemctl
+	serviceTypeWindows
 )
 
 type service struct {
@@ -74,6 +75,10 @@ func (s *service) determineServiceType(context *endly.Context, service, exclusio
 	if session.DaemonType > 0 {
 		return session.DaemonType, nil
 	}
+	if session.System() == exec.WindowsSystem {
+		session.DaemonType = serviceTypeWindows
+		return session.DaemonType, nil
+	}
 
 	var systemTypeCommands = []struct {
 		systemType int
@@ -196,6 +201,9 @@ func (s *service) checkService(context *endly.Context, request *StatusRequest) (
 		Type:    serviceType,
 	}
 
+	if serviceType == serviceTypeWindows {
+		return s.checkWindowsService(context, target, info)
+	}
 	if serviceType == serviceTypeLaunchCtl {
 		err = s.getDarwinLaunchServiceInfo(context, target, request, info)
 		if err != nil {
@@ -235,6 +243,22 @@ func (s *service) checkService(context *endly.Context, request *StatusRequest) (
 
 }
 
+//checkWindowsService checks windows service state with powershell
+func (s *service) checkWindowsService(context *endly.Context, target *url.Resource, info *Info) (*Info, error) {
+	command := fmt.Sprintf(`$service = Get-CimInstance Win32_Service -Filter "Name='%v'"; if ($service) { "state: $($service.State)"; "pid: $($service.ProcessId)"; "path: $($service.PathName)" }`,
+		strings.Replace(info.Service, "'", "\\'", -1))
+	commandResult, err := s.executeCommand(context, serviceTypeWindows, target, exec.NewExtractRequest(
+		target, exec.DefaultOptions(), exec.NewExtractCommand(command, "", nil, nil,
+			model.NewExtract("pid", "pid: (\\d+)", false, false),
+			model.NewExtract("state", "state: (\\S+)", false, false),
+			model.NewExtract("path", "path: (.+)", false, false))))
+	if err != nil {
+		return nil, err
+	}
+	extractServiceInfo(commandResult.Stdout(), commandResult.Data, info)
+	return info, nil
+}
+
 func (s *service) stopService(context *endly.Context, request *StopRequest) (*StopResponse, error) {
 	serviceInfo, err := s.checkService(context, &StatusRequest{
 		Target:    request.Target,
@@ -263,6 +287,8 @@ func (s *service) stopService(context *endly.Context, request *StopRequest) (*St
 		command = fmt.Sprintf("service %v stop", serviceInfo.Service)
 	case serviceTypeInitDaemon:
 		command = fmt.Sprintf("%v stop", serviceInfo.Service)
+	case serviceTypeWindows:
+		command = fmt.Sprintf("Stop-Service -Name '%v'", serviceInfo.Service)
 	}
 	commandResult, err := s.executeCommand(context, serviceInfo.Type, target,
 		exec.NewExtractRequest(target, exec.DefaultOptions(), exec.NewExtractCommand(command, "", nil, nil)))
@@ -321,6 +347,8 @@ func (s *service) startService(context *endly.Context, request *StartRequest) (*
 		command = fmt.Sprintf("service %v start", serviceInfo.Service)
 	case serviceTypeInitDaemon:
 		command = fmt.Sprintf("%v start", serviceInfo.Service)
+	case serviceTypeWindows:
+		command = fmt.Sprintf("Start-Service -Name '%v'", serviceInfo.Service)
 	}
 	commandResult, err := s.executeCommand(context, serviceInfo.Type, target,
 		exec.NewExtractRequest(target, exec.DefaultOptions(), exec.NewExtractCommand(command, "", nil, nil)))
@@ -19,7 +19,7 @@ func authConfigToken(authConfig *types.AuthConfig) (string, error) {
 
 //authCredentialsToken returns auth token
 func authCredentialsToken(context *endly.Context, credentials string) (string, error) {
-	cred, err := context.Secrets.GetCredentials(credentials)
+	cred, err := context.Credentials(credentials)
 	if err != nil {
 		return "", err
 	}
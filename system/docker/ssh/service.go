@@ -588,7 +588,7 @@ func (s *service) login(context *endly.Context, request *LoginRequest) (*LoginRe
 
 	var response = &LoginResponse{}
 	credentials := context.Expand(request.Credentials)
-	credConfig, err := context.Secrets.GetCredentials(credentials)
+	credConfig, err := context.Credentials(credentials)
 	if err != nil {
 		return nil, err
 	}
@@ -1389,7 +1389,7 @@ func (s *service) copy(context *endly.Context, request *CopyRequest) (*CopyRespo
 		return nil, err
 	}
 
-	state := context.State()
+	state := context.SafeState()
 	for k, v := range request.Assets {
 		sourcePath := state.ExpandAsText(k)
 		dest := url.NewResource(state.ExpandAsText(v)).ParsedURL.Path
@@ -0,0 +1,71 @@
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/toolbox/url"
+	"strings"
+)
+
+const (
+	//LanguageJavaScript represents JavaScript (ECMAScript 5.1) language
+	LanguageJavaScript = "js"
+	defaultTimeoutMs   = 30000
+)
+
+//RunRequest represents an eval request
+type RunRequest struct {
+	Language  string                 `description:"script language, js (default)"`
+	Code      string                 `description:"inline script, note that endly expands $ expressions in inline code before evaluation"`
+	Source    *url.Resource          `description:"script location, used when code is empty, source content is not expanded"`
+	Input     map[string]interface{} `description:"additional script variables"`
+	Output    string                 `description:"state key (or path i.e. app.config) to store the script result, if empty and the result is an object, its keys are merged into the state"`
+	TimeoutMs int                    `description:"max script execution time"`
+}
+
+//Init initialises request
+func (r *RunRequest) Init() error {
+	if r.Language == "" {
+		r.Language = LanguageJavaScript
+	}
+	r.Language = strings.ToLower(r.Language)
+	if r.Language == "javascript" {
+		r.Language = LanguageJavaScript
+	}
+	if r.TimeoutMs == 0 {
+		r.TimeoutMs = defaultTimeoutMs
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *RunRequest) Validate() error {
+	if r.Code == "" && r.Source == nil {
+		return errors.New("code and source were empty")
+	}
+	if r.Language != LanguageJavaScript {
+		return fmt.Errorf("unsupported language: %v", r.Language)
+	}
+	return nil
+}
+
+//RunResponse represents an eval response
+type RunResponse struct {
+	Result interface{}
+	Keys   []string `description:"state keys updated with the script result"`
+}
+
+//NewRunRequest creates a new eval request
+func NewRunRequest(code string, output string) *RunRequest {
+	return &RunRequest{
+		Code:   code,
+		Output: output,
+	}
+}
+
+//NewRunRequestFromURL creates a new request from URL
+func NewRunRequestFromURL(URL string) (*RunRequest, error) {
+	var request = &RunRequest{}
+	resource := url.NewResource(URL)
+	return request, resource.Decode(request)
+}
@@ -0,0 +1,124 @@
+package eval
+
+import (
+	"fmt"
+	"github.com/dop251/goja"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox/data"
+	"sort"
+	"time"
+)
+
+//ServiceID represents eval service id
+const ServiceID = "eval"
+
+type service struct {
+	*endly.AbstractService
+}
+
+func (s *service) run(context *endly.Context, request *RunRequest) (*RunResponse, error) {
+	code := request.Code
+	if code == "" {
+		resource, err := context.ExpandResource(request.Source)
+		if err != nil {
+			return nil, err
+		}
+		if code, err = resource.DownloadText(); err != nil {
+			return nil, fmt.Errorf("failed to load script %v: %v", resource.URL, err)
+		}
+	}
+	state := context.SafeState()
+	result, err := s.runJavaScript(context, code, state.DeepClone(), request)
+	if err != nil {
+		return nil, err
+	}
+	response := &RunResponse{Result: result}
+	if request.Output != "" {
+		state.SetValue(request.Output, result)
+		response.Keys = []string{request.Output}
+		return response, nil
+	}
+	if aMap, ok := result.(map[string]interface{}); ok {
+		for key := range aMap {
+			response.Keys = append(response.Keys, key)
+		}
+		sort.Strings(response.Keys)
+		state.Apply(aMap)
+	}
+	return response, nil
+}
+
+//runJavaScript evaluates code with state and input keys defined as global variables, the last expression value is returned
+func (s *service) runJavaScript(context *endly.Context, code string, state data.Map, request *RunRequest) (interface{}, error) {
+	vm := goja.New()
+	global := vm.GlobalObject()
+	define := func(key string, value interface{}) error {
+		if global.Get(key) != nil {
+			return nil //do not shadow builtins
+		}
+		return vm.Set(key, value)
+	}
+	if err := vm.Set("state", map[string]interface{}(state)); err != nil {
+		return nil, err
+	}
+	for key, value := range state {
+		if err := define(key, value); err != nil {
+			return nil, err
+		}
+	}
+	for key, value := range request.Input {
+		if err := vm.Set(key, value); err != nil {
+			return nil, err
+		}
+	}
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		select {
+		case <-time.After(time.Duration(request.TimeoutMs) * time.Millisecond):
+			vm.Interrupt(fmt.Sprintf("timeout after %v ms", request.TimeoutMs))
+		case <-context.Done():
+			vm.Interrupt(context.Err())
+		case <-done:
+		}
+	}()
+	value, err := vm.RunString(code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate script: %v", err)
+	}
+	if goja.IsUndefined(value) || goja.IsNull(value) {
+		return nil, nil
+	}
+	return value.Export(), nil
+}
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "run",
+		RequestInfo: &endly.ActionInfo{
+			Description: "evaluate script against the state and store its result",
+		},
+		RequestProvider: func() interface{} {
+			return &RunRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &RunResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*RunRequest); ok {
+				return s.run(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+//New creates a new eval service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
@@ -0,0 +1,120 @@
+package eval_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/eval"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"path"
+	"testing"
+)
+
+func TestService_Run(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	var useCases = []struct {
+		description string
+		request     *eval.RunRequest
+		expectKeys  []string
+		expect      map[string]interface{}
+		hasError    bool
+	}{
+		{
+			description: "join with output key",
+			request: eval.NewRunRequest(`users.map(function(u) {
+				var user = {name: u.name, orders: 0};
+				orders.forEach(function(o) { if (o.user == u.id) user.orders++; });
+				return user;
+			})`, "report.users"),
+			expectKeys: []string{"report.users"},
+			expect: map[string]interface{}{
+				"report.users": []interface{}{
+					map[string]interface{}{"name": "Bob", "orders": int64(2)},
+					map[string]interface{}{"name": "Ann", "orders": int64(1)},
+				},
+			},
+		},
+		{
+			description: "object result merged into state",
+			request: eval.NewRunRequest(`({
+				count: orders.length,
+				payload: env == "prod" ? {mode: "live"} : {mode: "dry", limit: limit}
+			})`, ""),
+			expectKeys: []string{"count", "payload"},
+			expect: map[string]interface{}{
+				"count":         int64(3),
+				"payload.mode":  "dry",
+				"payload.limit": int64(5),
+			},
+		},
+		{
+			description: "script source with input",
+			request: &eval.RunRequest{
+				Source: url.NewResource(path.Join(parent, "test/total.js")),
+				Input:  map[string]interface{}{"rate": 2},
+				Output: "total",
+			},
+			expectKeys: []string{"total"},
+			expect:     map[string]interface{}{"total": int64(70)},
+		},
+		{
+			description: "nested state is not modified in place",
+			request:     eval.NewRunRequest(`users[0].name = "Tom"; state.users[1].name = "Joe"; users.length`, "userCount"),
+			expectKeys:  []string{"userCount"},
+			expect: map[string]interface{}{
+				"userCount":     int64(2),
+				"users[0].name": "Bob",
+				"users[1].name": "Ann",
+			},
+		},
+		{
+			description: "script error",
+			request:     eval.NewRunRequest(`undefinedFn()`, "x"),
+			hasError:    true,
+		},
+		{
+			description: "timeout",
+			request:     &eval.RunRequest{Code: `while(true) {}`, TimeoutMs: 10},
+			hasError:    true,
+		},
+		{
+			description: "unsupported language",
+			request:     &eval.RunRequest{Code: `1`, Language: "cel"},
+			hasError:    true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		context := manager.NewContext(toolbox.NewContext())
+		state := context.State()
+		state.Put("env", "test")
+		state.Put("limit", 5)
+		state.Put("users", []interface{}{
+			map[string]interface{}{"id": 1, "name": "Bob"},
+			map[string]interface{}{"id": 2, "name": "Ann"},
+		})
+		state.Put("orders", []interface{}{
+			map[string]interface{}{"user": 1, "amount": 10},
+			map[string]interface{}{"user": 2, "amount": 20},
+			map[string]interface{}{"user": 1, "amount": 5},
+		})
+		serviceResponse, err := manager.Run(context, useCase.request)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		response, ok := serviceResponse.(*eval.RunResponse)
+		if !assert.True(t, ok, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.expectKeys, response.Keys, useCase.description)
+		for key, expect := range useCase.expect {
+			actual, _ := state.GetValue(key)
+			assert.EqualValues(t, expect, actual, useCase.description+": "+key)
+		}
+	}
+}
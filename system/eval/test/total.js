@@ -0,0 +1,5 @@
+var total = 0;
+for (var i = 0; i < orders.length; i++) {
+    total += orders[i].amount * rate;
+}
+total;
@@ -265,10 +265,11 @@ func NewRunRequestFromURL(URL string) (*RunRequest, error) {
 	return result, resource.Decode(result)
 }
 
-//Log represents an executed command with Stdin, Stdout or Error
+//Log represents an executed command with Stdin, Stdout, Stderr or Error, Stderr is only set for sessions keeping it separately (WinRM)
 type Log struct {
 	Stdin  string
 	Stdout string
+	Stderr string
 	Error  string
 }
 
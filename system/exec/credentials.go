@@ -27,7 +27,7 @@ func SetDefaultTarget(context *endly.Context, target *url.Resource) {
 	if target == nil {
 		return
 	}
-	state := context.State()
+	state := context.SafeState()
 	state.Put("execTarget", map[string]interface{}{
 		"URL":         target.URL,
 		"Credentials": target.Credentials,
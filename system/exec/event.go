@@ -50,3 +50,19 @@ func NewStdoutEvent(sessionID string, stdout string, err error) *StdoutEvent {
 		Stdout:    stdout,
 	}
 }
+
+//ProgressEvent represents progress pattern match in a running command output
+type ProgressEvent struct {
+	SessionID string
+	Name      string
+	Value     string
+	Line      string
+}
+
+//Messages returns messages
+func (e *ProgressEvent) Messages() []*msg.Message {
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("%v", e.SessionID), msg.MessageStyleGeneric), msg.NewStyled("progress", msg.MessageStyleGeneric),
+			msg.NewStyled(fmt.Sprintf("%v: %v", e.Name, e.Value), msg.MessageStyleOutput)),
+	}
+}
@@ -31,7 +31,7 @@ func TerminalSessions(context *endly.Context) model.Sessions {
 //SessionID returns session I
 func SessionID(context *endly.Context, target *url.Resource) string {
 	username := ""
-	if config, _ := context.Secrets.GetCredentials(target.Credentials); config != nil {
+	if config, _ := context.Credentials(target.Credentials); config != nil {
 		username = config.Username
 	}
 	return username + "@" + target.Host()
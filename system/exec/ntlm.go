@@ -0,0 +1,166 @@
+package exec
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"golang.org/x/crypto/md4"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	ntlmNegotiateUnicode                 = 0x00000001
+	ntlmRequestTarget                    = 0x00000004
+	ntlmNegotiateNTLM                    = 0x00000200
+	ntlmNegotiateAlwaysSign              = 0x00008000
+	ntlmNegotiateExtendedSessionSecurity = 0x00080000
+	ntlmNegotiateTargetInfo              = 0x00800000
+	ntlmNegotiate128                     = 0x20000000
+	ntlmNegotiate56                      = 0x80000000
+	ntlmNegotiateFlags                   = ntlmNegotiateUnicode | ntlmRequestTarget | ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign |
+		ntlmNegotiateExtendedSessionSecurity | ntlmNegotiateTargetInfo | ntlmNegotiate128 | ntlmNegotiate56
+
+	ntlmAvTimestamp     = 7
+	ntlmFileTimeEpoch   = 116444736000000000
+	ntlmAuthenticateLen = 64
+)
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+//ntlmChallenge represents NTLM server challenge message
+type ntlmChallenge struct {
+	flags           uint32
+	serverChallenge []byte
+	targetInfo      []byte
+}
+
+//utf16LittleEndian returns UTF16LE encoded text
+func utf16LittleEndian(text string) []byte {
+	var encoded = utf16.Encode([]rune(text))
+	var result = make([]byte, 2*len(encoded))
+	for i, code := range encoded {
+		binary.LittleEndian.PutUint16(result[2*i:], code)
+	}
+	return result
+}
+
+func hmacMD5(key []byte, data ...[]byte) []byte {
+	hash := hmac.New(md5.New, key)
+	for _, item := range data {
+		hash.Write(item)
+	}
+	return hash.Sum(nil)
+}
+
+//ntlmNegotiateMessage returns NTLM negotiate message
+func ntlmNegotiateMessage() []byte {
+	message := make([]byte, 32)
+	copy(message, ntlmSignature)
+	binary.LittleEndian.PutUint32(message[8:], 1)
+	binary.LittleEndian.PutUint32(message[12:], ntlmNegotiateFlags)
+	return message
+}
+
+//parseNTLMChallenge parses NTLM challenge message
+func parseNTLMChallenge(message []byte) (*ntlmChallenge, error) {
+	if len(message) < 48 || !bytes.Equal(message[:8], ntlmSignature) || binary.LittleEndian.Uint32(message[8:]) != 2 {
+		return nil, errors.New("invalid NTLM challenge message")
+	}
+	result := &ntlmChallenge{
+		flags:           binary.LittleEndian.Uint32(message[20:]),
+		serverChallenge: message[24:32],
+	}
+	length := int(binary.LittleEndian.Uint16(message[40:]))
+	offset := int(binary.LittleEndian.Uint32(message[44:]))
+	if offset+length > len(message) {
+		return nil, errors.New("invalid NTLM challenge target info")
+	}
+	result.targetInfo = message[offset : offset+length]
+	return result, nil
+}
+
+//ntlmTimestamp returns server timestamp from target info or current time as windows file time
+func ntlmTimestamp(targetInfo []byte) []byte {
+	for offset := 0; offset+4 <= len(targetInfo); {
+		id := binary.LittleEndian.Uint16(targetInfo[offset:])
+		length := int(binary.LittleEndian.Uint16(targetInfo[offset+2:]))
+		offset += 4
+		if id == 0 || offset+length > len(targetInfo) {
+			break
+		}
+		if id == ntlmAvTimestamp && length == 8 {
+			return targetInfo[offset : offset+8]
+		}
+		offset += length
+	}
+	var result = make([]byte, 8)
+	binary.LittleEndian.PutUint64(result, uint64(time.Now().UnixNano()/100+ntlmFileTimeEpoch))
+	return result
+}
+
+//ntlmResponseKey returns NTOWFv2 response key
+func ntlmResponseKey(username, domain, password string) []byte {
+	hash := md4.New()
+	hash.Write(utf16LittleEndian(password))
+	return hmacMD5(hash.Sum(nil), utf16LittleEndian(strings.ToUpper(username)+domain))
+}
+
+//ntlmV2Response returns NTLMv2 and LMv2 challenge responses
+func ntlmV2Response(username, domain, password string, serverChallenge, clientChallenge, timestamp, targetInfo []byte) ([]byte, []byte) {
+	responseKey := ntlmResponseKey(username, domain, password)
+	var temp = []byte{1, 1, 0, 0, 0, 0, 0, 0}
+	temp = append(temp, timestamp...)
+	temp = append(temp, clientChallenge...)
+	temp = append(temp, 0, 0, 0, 0)
+	temp = append(temp, targetInfo...)
+	temp = append(temp, 0, 0, 0, 0)
+	ntResponse := append(hmacMD5(responseKey, serverChallenge, temp), temp...)
+	lmResponse := append(hmacMD5(responseKey, serverChallenge, clientChallenge), clientChallenge...)
+	return ntResponse, lmResponse
+}
+
+//ntlmDomainUser splits DOMAIN\user username, user principal name (user@domain) is used as is
+func ntlmDomainUser(username string) (string, string) {
+	if index := strings.Index(username, `\`); index != -1 {
+		return username[:index], username[index+1:]
+	}
+	return "", username
+}
+
+//ntlmAuthenticateMessage returns NTLMv2 authenticate message for supplied challenge message.
+//Only authentication is implemented (MS-NLMP 3.3.2), session key exchange, message signing and sealing are not,
+//so WinRM traffic is not encrypted by NTLM: use HTTPS or allow unencrypted traffic on the target.
+func ntlmAuthenticateMessage(challengeMessage []byte, username, password string) ([]byte, error) {
+	challenge, err := parseNTLMChallenge(challengeMessage)
+	if err != nil {
+		return nil, err
+	}
+	domain, user := ntlmDomainUser(username)
+	clientChallenge := make([]byte, 8)
+	if _, err = rand.Read(clientChallenge); err != nil {
+		return nil, err
+	}
+	ntResponse, lmResponse := ntlmV2Response(user, domain, password, challenge.serverChallenge, clientChallenge, ntlmTimestamp(challenge.targetInfo), challenge.targetInfo)
+	var fields = [][]byte{lmResponse, ntResponse, utf16LittleEndian(domain), utf16LittleEndian(user), nil, nil}
+	message := make([]byte, ntlmAuthenticateLen)
+	copy(message, ntlmSignature)
+	binary.LittleEndian.PutUint32(message[8:], 3)
+	offset := ntlmAuthenticateLen
+	for i, field := range fields {
+		position := 12 + 8*i
+		binary.LittleEndian.PutUint16(message[position:], uint16(len(field)))
+		binary.LittleEndian.PutUint16(message[position+2:], uint16(len(field)))
+		binary.LittleEndian.PutUint32(message[position+4:], uint32(offset))
+		offset += len(field)
+	}
+	binary.LittleEndian.PutUint32(message[60:], challenge.flags&ntlmNegotiateFlags)
+	for _, field := range fields {
+		message = append(message, field...)
+	}
+	return message, nil
+}
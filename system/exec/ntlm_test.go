@@ -0,0 +1,59 @@
+package exec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNTLMResponseKey(t *testing.T) {
+	//MS-NLMP 4.2.4.1.1 NTOWFv2 test vector
+	assert.Equal(t, "0c868a403bfd7a93a3001ef22ef02e3f", hex.EncodeToString(ntlmResponseKey("User", "Domain", "Password")))
+	assert.Equal(t, ntlmResponseKey("USER", "Domain", "Password"), ntlmResponseKey("user", "Domain", "Password"))
+	assert.NotEqual(t, ntlmResponseKey("User", "DOMAIN", "Password"), ntlmResponseKey("User", "Domain", "Password"))
+}
+
+func TestNTLMV2Response(t *testing.T) {
+	//MS-NLMP 4.2.4 NTLMv2 authentication test vector
+	serverChallenge, _ := hex.DecodeString("0123456789abcdef")
+	clientChallenge := bytes.Repeat([]byte{0xaa}, 8)
+	targetInfo, _ := hex.DecodeString("02000c0044006f006d00610069006e0001000c0053006500720076006500720000000000")
+	ntResponse, lmResponse := ntlmV2Response("User", "Domain", "Password", serverChallenge, clientChallenge, make([]byte, 8), targetInfo)
+	assert.Equal(t, "68cd0ab851e51c96aabc927bebef6a1c", hex.EncodeToString(ntResponse[:16]))
+	assert.Equal(t, "01010000000000000000000000000000aaaaaaaaaaaaaaaa00000000"+hex.EncodeToString(targetInfo)+"00000000", hex.EncodeToString(ntResponse[16:]))
+	assert.Equal(t, "86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa", hex.EncodeToString(lmResponse))
+}
+
+func TestNTLMAuthenticateMessage(t *testing.T) {
+	challenge := make([]byte, 48)
+	copy(challenge, ntlmSignature)
+	binary.LittleEndian.PutUint32(challenge[8:], 2)
+	binary.LittleEndian.PutUint32(challenge[20:], ntlmNegotiateFlags)
+	copy(challenge[24:], "\x01\x23\x45\x67\x89\xab\xcd\xef")
+	targetInfo := []byte{ntlmAvTimestamp, 0, 8, 0, 1, 2, 3, 4, 5, 6, 7, 8, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint16(challenge[40:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint32(challenge[44:], 48)
+	challenge = append(challenge, targetInfo...)
+
+	message, err := ntlmAuthenticateMessage(challenge, `CORP\tester`, "secret")
+	if !assert.Nil(t, err) {
+		return
+	}
+	field := func(index int) []byte {
+		position := 12 + 8*index
+		length := binary.LittleEndian.Uint16(message[position:])
+		offset := binary.LittleEndian.Uint32(message[position+4:])
+		return message[offset : offset+uint32(length)]
+	}
+	assert.Equal(t, utf16LittleEndian("CORP"), field(2))
+	assert.Equal(t, utf16LittleEndian("tester"), field(3))
+	ntResponse := field(1)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, ntResponse[24:32])
+	expected, _ := ntlmV2Response("tester", "CORP", "secret", challenge[24:32], ntResponse[32:40], ntResponse[24:32], targetInfo)
+	assert.Equal(t, expected, ntResponse)
+
+	_, err = ntlmAuthenticateMessage([]byte("invalid"), "tester", "secret")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,264 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"github.com/viant/toolbox/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultScriptInterpreter        = "bash"
+	defaultWindowsScriptInterpreter = "powershell -NoProfile -ExecutionPolicy Bypass -File"
+	windowsScriptDir                = `C:\Windows\Temp`
+	defaultMaxOutputSize            = 1024 * 1024
+)
+
+//ProgressPattern represents a pattern matched against command output lines while command is still running
+type ProgressPattern struct {
+	Name    string `required:"true" description:"progress name"`
+	Pattern string `required:"true" description:"regular expression, the first sub match (or whole match) is used as progress value, i.e. (\\d+)% done"`
+	expr    *regexp.Regexp
+}
+
+//ScriptRequest represents a request to upload local script and run it on the target host
+type ScriptRequest struct {
+	Target *url.Resource `required:"true" description:"host where script runs"`
+	*Options
+	Source        *url.Resource      `description:"script location"`
+	Script        string             `description:"inline script content, used when source is empty"`
+	Dest          string             `description:"remote script path, /tmp/<script name> (C:\\Windows\\Temp\\<script name> on windows) by default"`
+	Interpreter   string             `description:"script interpreter, default bash (powershell on windows)"`
+	Args          []string           `description:"script arguments"`
+	MaxOutputSize int                `description:"max captured output size in bytes, output tail is kept, default 1MB"`
+	Progress      []*ProgressPattern `description:"patterns matched against output lines as the script runs, each match is published as progress event"`
+	Extract       model.Extracts     `description:"stdout data extraction instruction"`
+}
+
+//ScriptResponse represents a script response
+type ScriptResponse struct {
+	Session   string
+	Dest      string
+	Command   string
+	Output    string
+	Truncated bool              `description:"true if output exceeded max output size"`
+	Progress  map[string]string `description:"last progress value by pattern name"`
+	Data      data.Map
+}
+
+//Init initialises request
+func (r *ScriptRequest) Init() error {
+	if r.Options == nil {
+		r.Options = DefaultOptions()
+	}
+	r.Target = GetServiceTarget(r.Target)
+	if r.MaxOutputSize == 0 {
+		r.MaxOutputSize = defaultMaxOutputSize
+	}
+	for _, progress := range r.Progress {
+		var err error
+		if progress.expr, err = regexp.Compile(progress.Pattern); err != nil {
+			return fmt.Errorf("invalid progress %v pattern: %v, %v", progress.Name, progress.Pattern, err)
+		}
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *ScriptRequest) Validate() error {
+	if r.Target == nil {
+		return errors.New("target was empty")
+	}
+	if (r.Source == nil || r.Source.URL == "") && r.Script == "" {
+		return errors.New("source and script were empty")
+	}
+	return nil
+}
+
+//scriptDest returns remote script path for supplied target system
+func (r *ScriptRequest) scriptDest(system string) string {
+	if r.Dest != "" {
+		return r.Dest
+	}
+	name := fmt.Sprintf("endly_script_%v.sh", time.Now().UnixNano())
+	if system == WindowsSystem {
+		name = fmt.Sprintf("endly_script_%v.ps1", time.Now().UnixNano())
+	}
+	if r.Source != nil && r.Source.URL != "" {
+		if _, sourceName := toolbox.URLSplit(r.Source.URL); sourceName != "" {
+			name = sourceName
+		}
+	}
+	if system == WindowsSystem {
+		return windowsScriptDir + `\` + name
+	}
+	return path.Join("/tmp", name)
+}
+
+//scriptInterpreter returns request or default interpreter for supplied target system
+func (r *ScriptRequest) scriptInterpreter(system string) string {
+	if r.Interpreter != "" {
+		return r.Interpreter
+	}
+	if system == WindowsSystem {
+		return defaultWindowsScriptInterpreter
+	}
+	return defaultScriptInterpreter
+}
+
+//progressTracker matches progress patterns against streamed output lines
+type progressTracker struct {
+	mux      *sync.Mutex
+	patterns []*ProgressPattern
+	fragment string
+	values   map[string]string
+	notify   func(event *ProgressEvent)
+}
+
+//Write matches complete lines of supplied output chunk
+func (t *progressTracker) Write(chunk string) {
+	if len(t.patterns) == 0 {
+		return
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	lines := strings.Split(t.fragment+chunk, "\n")
+	t.fragment = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		t.match(line)
+	}
+}
+
+//Flush matches remaining incomplete line
+func (t *progressTracker) Flush() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if t.fragment != "" {
+		t.match(t.fragment)
+		t.fragment = ""
+	}
+}
+
+func (t *progressTracker) match(line string) {
+	line = strings.TrimRight(line, "\r")
+	for _, pattern := range t.patterns {
+		matched := pattern.expr.FindStringSubmatch(line)
+		if len(matched) == 0 {
+			continue
+		}
+		value := matched[0]
+		if len(matched) > 1 {
+			value = matched[1]
+		}
+		t.values[pattern.Name] = value
+		if t.notify != nil {
+			t.notify(&ProgressEvent{Name: pattern.Name, Value: value, Line: line})
+		}
+	}
+}
+
+func newProgressTracker(patterns []*ProgressPattern, notify func(event *ProgressEvent)) *progressTracker {
+	return &progressTracker{
+		mux:      &sync.Mutex{},
+		patterns: patterns,
+		values:   make(map[string]string),
+		notify:   notify,
+	}
+}
+
+//outputTail returns output tail limited to max size, and true if output was truncated
+func outputTail(output string, maxSize int) (string, bool) {
+	if maxSize <= 0 || len(output) <= maxSize {
+		return output, false
+	}
+	return output[len(output)-maxSize:], true
+}
+
+func (s *execService) runScript(context *endly.Context, request *ScriptRequest) (*ScriptResponse, error) {
+	target, err := context.ExpandResource(request.Target)
+	if err != nil {
+		return nil, err
+	}
+	var content = []byte(request.Script)
+	if request.Source != nil && request.Source.URL != "" {
+		source, err := context.ExpandResource(request.Source)
+		if err != nil {
+			return nil, err
+		}
+		if content, err = source.Download(); err != nil {
+			return nil, fmt.Errorf("failed to load script %v: %v", source.URL, err)
+		}
+	}
+	session, err := s.openSession(context, &OpenSessionRequest{Target: target})
+	if err != nil {
+		return nil, err
+	}
+	if err = s.applyCommandOptions(context, request.Options, session, NewRunResponse(session.ID)); err != nil {
+		return nil, err
+	}
+	var response = &ScriptResponse{
+		Session: session.ID,
+		Dest:    context.Expand(request.scriptDest(session.System())),
+		Data:    data.NewMap(),
+	}
+	if err = session.Service.Upload(response.Dest, 0755, content); err != nil {
+		return nil, fmt.Errorf("failed to upload script to %v: %v", response.Dest, err)
+	}
+	var args = make([]string, 0)
+	for _, arg := range request.Args {
+		args = append(args, context.Expand(arg))
+	}
+	response.Command = strings.TrimSpace(fmt.Sprintf("%v %v %v", request.scriptInterpreter(session.System()), response.Dest, strings.Join(args, " ")))
+	command, err := context.Secrets.Expand(response.Command, request.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	if request.SuperUser {
+		command = s.commandAsSuperUser(session, command)
+	}
+	s.Begin(context, NewSdtinEvent(session.ID, response.Command))
+
+	var eventMux = &sync.Mutex{} //listener can be called from ssh notification goroutine
+	tracker := newProgressTracker(request.Progress, func(event *ProgressEvent) {
+		event.SessionID = session.ID
+		context.Publish(event)
+	})
+	listener := func(stdout string, hasMore bool) {
+		if stdout == "" {
+			return
+		}
+		stdout = context.MaskSecrets(stdout)
+		eventMux.Lock()
+		context.Heartbeat().Output(stdout)
+		context.Publish(NewStdoutEvent(session.ID, stdout, nil))
+		tracker.Write(stdout)
+		eventMux.Unlock()
+	}
+	terminators := getTerminators(request.Options, session, &ExtractCommand{})
+	stdout, err := s.run(context, session, command, listener, request.TimeoutMs, terminators...)
+	tracker.Flush()
+	response.Progress = tracker.values
+	response.Output, response.Truncated = outputTail(context.MaskSecrets(stdout), request.MaxOutputSize)
+	if err != nil {
+		return response, err
+	}
+	if request.CheckError {
+		if exitCode, err := s.run(context, session, "echo $?", nil, request.TimeoutMs, terminators...); err == nil {
+			if exitStatus := toolbox.AsInt(strings.TrimSpace(exitCode)); exitStatus != 0 {
+				return response, fmt.Errorf("exit code: %v, script: %v", exitStatus, response.Command)
+			}
+		}
+	}
+	if len(request.Extract) > 0 {
+		err = request.Extract.Extract(context, response.Data, strings.Split(response.Output, "\n")...)
+	}
+	return response, err
+}
@@ -0,0 +1,62 @@
+package exec
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/url"
+	"testing"
+)
+
+func TestScriptRequest_Init(t *testing.T) {
+	request := &ScriptRequest{
+		Target:   url.NewResource("ssh://127.0.0.1/", "localhost"),
+		Source:   url.NewResource("test/script/build.sh"),
+		Progress: []*ProgressPattern{{Name: "build", Pattern: `\[(\d+)%\]`}},
+	}
+	if assert.Nil(t, request.Init()) && assert.Nil(t, request.Validate()) {
+		assert.Equal(t, "bash", request.scriptInterpreter("linux"))
+		assert.Equal(t, defaultWindowsScriptInterpreter, request.scriptInterpreter(WindowsSystem))
+		assert.Equal(t, defaultMaxOutputSize, request.MaxOutputSize)
+		assert.Equal(t, "/tmp/build.sh", request.scriptDest("linux"))
+		assert.Equal(t, `C:\Windows\Temp\build.sh`, request.scriptDest(WindowsSystem))
+	}
+	request = &ScriptRequest{Target: url.NewResource("ssh://127.0.0.1/", "localhost"), Progress: []*ProgressPattern{{Name: "build", Pattern: `[(`}}}
+	assert.NotNil(t, request.Init())
+	request = &ScriptRequest{Target: url.NewResource("ssh://127.0.0.1/", "localhost")}
+	assert.Nil(t, request.Init())
+	assert.NotNil(t, request.Validate())
+}
+
+func TestProgressTracker_Write(t *testing.T) {
+	request := &ScriptRequest{Progress: []*ProgressPattern{
+		{Name: "build", Pattern: `\[(\d+)%\]`},
+		{Name: "ready", Pattern: `server started`},
+	}}
+	if !assert.Nil(t, request.Init()) {
+		return
+	}
+	var events = make([]*ProgressEvent, 0)
+	tracker := newProgressTracker(request.Progress, func(event *ProgressEvent) {
+		events = append(events, event)
+	})
+	tracker.Write("[10%] compiling\r\n[5")
+	tracker.Write("0%] linking\nserver st")
+	assert.Equal(t, 2, len(events))
+	tracker.Write("arted")
+	tracker.Flush()
+	if assert.Equal(t, 3, len(events)) {
+		assert.Equal(t, "10", events[0].Value)
+		assert.Equal(t, "[10%] compiling", events[0].Line)
+		assert.Equal(t, "50", events[1].Value)
+		assert.Equal(t, "server started", events[2].Value)
+	}
+	assert.Equal(t, map[string]string{"build": "50", "ready": "server started"}, tracker.values)
+}
+
+func TestOutputTail(t *testing.T) {
+	output, truncated := outputTail("0123456789", 4)
+	assert.Equal(t, "6789", output)
+	assert.True(t, truncated)
+	output, truncated = outputTail("0123", 4)
+	assert.Equal(t, "0123", output)
+	assert.False(t, truncated)
+}
@@ -46,16 +46,23 @@ func (s *execService) openSSHService(context *endly.Context, request *OpenSessio
 	if err != nil {
 		return nil, err
 	}
-	authConfig, err := context.Secrets.GetOrCreate(target.Credentials)
+	authConfig, err := context.GetOrCreateCredentials(target.Credentials)
 	if err != nil {
 		return nil, err
 	}
+	if IsWinRMTarget(target) {
+		return newWinRMService(target, authConfig), nil
+	}
 	hostname, port := s.GetHostAndSSHPort(target)
 	return ssh.NewService(hostname, port, authConfig)
 }
 
 func (s *execService) isSupportedScheme(target *url.Resource) bool {
-	return target.ParsedURL.Scheme == "ssh" || target.ParsedURL.Scheme == "scp" || target.ParsedURL.Scheme == "file"
+	switch target.ParsedURL.Scheme {
+	case "ssh", "scp", "file", WinRMScheme, WinRMSecureScheme:
+		return true
+	}
+	return false
 }
 
 func (s *execService) initSession(context *endly.Context, target *url.Resource, session *model.Session, env map[string]string) error {
@@ -134,6 +141,18 @@ func (s *execService) openSession(context *endly.Context, request *OpenSessionRe
 	s.Lock()
 	sessions[sessionID] = SSHSession
 	s.Unlock()
+	if request.ReplayService == nil && request.Basedir == "" {
+		context.PersistentSession().Track(ServiceID, "open", &OpenSessionRequest{
+			Target:      request.Target,
+			Config:      request.Config,
+			SystemPaths: request.SystemPaths,
+			Env:         request.Env,
+		})
+	}
+	if SSHSession.System() == WindowsSystem {
+		SSHSession.Os, err = s.detectWindowsOperatingSystem(SSHSession)
+		return SSHSession, err
+	}
 	SSHSession.Os, err = s.detectOperatingSystem(SSHSession)
 	if err != nil {
 		return nil, err
@@ -202,10 +221,30 @@ func (s *execService) changeDirectory(context *endly.Context, session *model.Ses
 	return result, err
 }
 
+//stderrSession represents a session keeping the last command stderr separately from stdout
+type stderrSession interface {
+	Stderr() string
+}
+
 func (s *execService) run(context *endly.Context, session *model.Session, command string, listener ssh.Listener, timeoutMs int, terminators ...string) (stdout string, err error) {
+	if err = context.Err(); err != nil {
+		return "", err
+	}
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		select {
+		case <-context.Done():
+			session.Close()
+		case <-done:
+		}
+	}()
 	if stdout, err = session.Run(command, listener, timeoutMs, terminators...); err == nil {
 		return stdout, err
 	}
+	if e := context.Err(); e != nil {
+		return stdout, e
+	}
 	if err == ssh.ErrTerminated {
 		err := session.Reconnect()
 		if err != nil {
@@ -267,7 +306,7 @@ func match(stdout string, candidates ...string) string {
 }
 
 func (s *execService) commandAsSuperUser(session *model.Session, command string) string {
-	if session.Username == "root" {
+	if session.Username == "root" || session.System() == WindowsSystem {
 		return command
 	}
 	if len(command) > 1 && !strings.Contains(command, "sudo") {
@@ -307,13 +346,14 @@ func (s *execService) authSuperUserIfNeeded(stdout string, context *endly.Contex
 }
 
 func (s *execService) buildExecutionState(response *RunResponse, context *endly.Context) data.Map {
-	var state = context.State()
+	var state = context.SafeState()
 	var result = state.Clone()
 	var commands = data.NewCollection()
 	for _, log := range response.Cmd {
 		var cmd = data.NewMap()
 		cmd.Put("stdin", log.Stdin)
 		cmd.Put("stdout", log.Stdout)
+		cmd.Put("stderr", log.Stderr)
 		commands.Push(cmd)
 	}
 	result.Put("cmd", commands)
@@ -340,7 +380,7 @@ func hasTerminator(stdout string, terminators []string) bool {
 }
 
 func (s *execService) executeCommand(context *endly.Context, session *model.Session, extractCommand *ExtractCommand, response *RunResponse, request *ExtractRequest) (err error) {
-	var state = context.State()
+	var state = context.SafeState()
 	state.SetValue("os.user", session.Username)
 	state.SetValue("os.arch", session.Os.Arch)
 	state.SetValue("os.system", session.Os.System)
@@ -384,6 +424,8 @@ func (s *execService) executeCommand(context *endly.Context, session *model.Sess
 	//troubleshooting secrets - DO NOT USE unless really needed
 	if os.Getenv("ENDLY_SECRET_REVEAL") == "true" {
 		securedCommand = insecureCommand
+	} else {
+		securedCommand = context.MaskSecrets(securedCommand)
 	}
 	s.Begin(context, NewSdtinEvent(session.ID, securedCommand))
 
@@ -393,6 +435,8 @@ func (s *execService) executeCommand(context *endly.Context, session *model.Sess
 			return
 		}
 		if stdout != "" {
+			stdout = context.MaskSecrets(stdout)
+			context.Heartbeat().Output(stdout)
 			context.Publish(NewStdoutEvent(session.ID, stdout, err))
 		}
 	}
@@ -423,17 +467,26 @@ func (s *execService) executeCommand(context *endly.Context, session *model.Sess
 		}
 	}
 	response.Output += stdout
+	var stderr string
+	if stderrSession, ok := session.MultiCommandSession.(stderrSession); ok {
+		stderr = context.MaskSecrets(stderrSession.Stderr())
+	}
 
 	if request.CheckError && !hasTerminator(stdout, terminators) {
 		if errorCode, err := s.run(context, session, "echo $?", nil, options.TimeoutMs, terminators...); err == nil {
 			exitStatus := toolbox.AsInt(strings.TrimSpace(errorCode))
 			if exitStatus != 0 {
+				if stderr != "" {
+					return fmt.Errorf("exit code: %v, command: %v, %v", exitStatus, securedCommand, strings.TrimSpace(stderr))
+				}
 				return fmt.Errorf("exit code: %v, command: %v", exitStatus, securedCommand)
 			}
 		}
 	}
 
-	response.Add(NewCommandLog(securedCommand, stdout, err))
+	commandLog := NewCommandLog(securedCommand, stdout, err)
+	commandLog.Stderr = stderr
+	response.Add(commandLog)
 	if err != nil {
 		return err
 	}
@@ -463,7 +516,9 @@ func getTerminators(options *Options, session *model.Session, execution *Extract
 	if strings.Contains(superUserPrompt, "bash") {
 		superUserPrompt = string(superUserPrompt[2:])
 	}
-	terminators = append(terminators, superUserPrompt)
+	if superUserPrompt != "" {
+		terminators = append(terminators, superUserPrompt)
+	}
 	terminators = append(terminators, execution.Errors...)
 	return terminators
 }
@@ -646,6 +701,44 @@ func (s *execService) detectOperatingSystem(session *model.Session) (*model.Oper
 	return operatingSystem, err
 }
 
+//detectWindowsOperatingSystem detects windows version, architecture, user and path with powershell
+func (s *execService) detectWindowsOperatingSystem(session *model.Session) (*model.OperatingSystem, error) {
+	output, err := session.Run("$os = Get-CimInstance Win32_OperatingSystem; $os.Caption; $os.Version; $env:PROCESSOR_ARCHITECTURE; $env:USERNAME; $env:PATH", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.Replace(output, "\r", "", -1), "\n")
+	for len(lines) < 5 {
+		lines = append(lines, "")
+	}
+	operatingSystem := &model.OperatingSystem{
+		System:   WindowsSystem,
+		Name:     strings.ToLower(strings.TrimSpace(lines[0])),
+		Version:  strings.TrimSpace(lines[1]),
+		Hardware: strings.TrimSpace(lines[2]),
+	}
+	switch strings.ToLower(operatingSystem.Hardware) {
+	case "amd64", "x86_64":
+		operatingSystem.Architecture = "amd64"
+		operatingSystem.Arch = "x64"
+	case "arm64":
+		operatingSystem.Architecture = "arm64"
+		operatingSystem.Arch = "aarch64"
+	default:
+		operatingSystem.Architecture = strings.ToLower(operatingSystem.Hardware)
+		operatingSystem.Arch = operatingSystem.Architecture
+	}
+	session.Username = strings.TrimSpace(lines[3])
+	var paths = make([]string, 0)
+	for _, candidate := range strings.Split(strings.TrimSpace(lines[4]), ";") {
+		if candidate != "" {
+			paths = append(paths, candidate)
+		}
+	}
+	session.Path = model.NewPath(paths...)
+	return operatingSystem, nil
+}
+
 func isArm64Architecture(hardware string) bool {
 	return strings.Contains(hardware, "aarch64") || strings.Contains(hardware, "arm64")
 }
@@ -717,6 +810,25 @@ const (
 	]
 }`
 
+	execServiceScriptExample = `{
+  "Target": {
+    "URL": "ssh://127.0.0.1/",
+    "Credentials": "${env.HOME}/.secret/localhost.json"
+  },
+  "Source": {
+    "URL": "script/build.sh"
+  },
+  "Args": ["-v"],
+  "TimeoutMs": 60000,
+  "MaxOutputSize": 65536,
+  "Progress": [
+    {
+      "Name": "build",
+      "Pattern": "\\[(\\d+)%\\]"
+    }
+  ]
+}`
+
 	execServiceManagedCloseExample = `{
   "Target": {
     "URL": "scp://127.0.0.1/",
@@ -803,6 +915,32 @@ func (s *execService) registerRoutes() {
 		},
 	})
 
+	s.Register(&endly.Route{
+		Action: "script",
+		RequestInfo: &endly.ActionInfo{
+			Description: "upload script and run it on the target host, output is streamed as events while the script runs",
+
+			Examples: []*endly.UseCase{
+				{
+					Description: "upload and run script",
+					Data:        execServiceScriptExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &ScriptRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ScriptResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ScriptRequest); ok {
+				return s.runScript(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
 	s.Register(&endly.Route{
 		Action: "close",
 		RequestInfo: &endly.ActionInfo{
@@ -0,0 +1,614 @@
+package exec
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/cred"
+	"github.com/viant/toolbox/ssh"
+	"github.com/viant/toolbox/url"
+	cssh "golang.org/x/crypto/ssh"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//WinRMScheme represents WinRM over HTTP target scheme, i.e. winrm://host:5985
+	WinRMScheme = "winrm"
+	//WinRMSecureScheme represents WinRM over HTTPS target scheme, i.e. winrms://host:5986
+	WinRMSecureScheme = "winrms"
+	//WindowsSystem represents windows operating system name
+	WindowsSystem = "windows"
+
+	winrmHTTPPort           = 5985
+	winrmHTTPSPort          = 5986
+	winrmDefaultTimeoutMs   = 20000
+	winrmMaxReceiveTimeout  = 20 * time.Second
+	winrmUploadChunkSize    = 1500
+	winrmShellURI           = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/cmd"
+	winrmActionCreate       = "http://schemas.xmlsoap.org/ws/2004/09/transfer/Create"
+	winrmActionDelete       = "http://schemas.xmlsoap.org/ws/2004/09/transfer/Delete"
+	winrmActionCommand      = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Command"
+	winrmActionReceive      = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Receive"
+	winrmActionSignal       = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/Signal"
+	winrmSignalTerminate    = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/signal/terminate"
+	winrmCommandStateDone   = "http://schemas.microsoft.com/wbem/wsman/1/windows/shell/CommandState/Done"
+	winrmTimedOutFaultCode  = "2150858793"
+	winrmNoSuchFileOrFolder = "No such file or directory"
+	winrmWindowsPlatform    = "Win32NT"
+
+	//WinRM HTTP authentication schemes, Negotiate uses NTLMv2
+	winrmAuthBasic     = "Basic"
+	winrmAuthNegotiate = "Negotiate"
+	winrmAuthNTLM      = "NTLM"
+)
+
+//IsWinRMTarget returns true if target uses WinRM scheme or WinRM port
+func IsWinRMTarget(target *url.Resource) bool {
+	if target == nil || target.ParsedURL == nil {
+		return false
+	}
+	switch target.ParsedURL.Scheme {
+	case WinRMScheme, WinRMSecureScheme:
+		return true
+	case "ssh", "scp":
+		port := toolbox.AsInt(target.ParsedURL.Port())
+		return port == winrmHTTPPort || port == winrmHTTPSPort
+	}
+	return false
+}
+
+//winrmEndpoint returns WS-Management endpoint for the target
+func winrmEndpoint(target *url.Resource) string {
+	port := toolbox.AsInt(target.ParsedURL.Port())
+	scheme := "http"
+	if target.ParsedURL.Scheme == WinRMSecureScheme || port == winrmHTTPSPort {
+		scheme = "https"
+	}
+	if port == 0 {
+		port = winrmHTTPPort
+		if scheme == "https" {
+			port = winrmHTTPSPort
+		}
+	}
+	hostname := target.ParsedURL.Hostname()
+	if hostname == "" {
+		hostname = "127.0.0.1"
+	}
+	return fmt.Sprintf("%v://%v:%v/wsman", scheme, hostname, port)
+}
+
+//encodePowerShell returns base64 UTF16LE encoded script for powershell -EncodedCommand
+func encodePowerShell(script string) string {
+	return base64.StdEncoding.EncodeToString(utf16LittleEndian(script))
+}
+
+//powerShellQuote returns single quoted powershell literal
+func powerShellQuote(literal string) string {
+	return "'" + strings.Replace(literal, "'", "''", -1) + "'"
+}
+
+type wsmanStream struct {
+	Name  string `xml:"Name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type wsmanEnvelope struct {
+	Body struct {
+		Shell struct {
+			ShellID string `xml:"ShellId"`
+		} `xml:"Shell"`
+		CommandResponse struct {
+			CommandID string `xml:"CommandId"`
+		} `xml:"CommandResponse"`
+		ReceiveResponse struct {
+			Streams      []*wsmanStream `xml:"Stream"`
+			CommandState struct {
+				State    string `xml:"State,attr"`
+				ExitCode int    `xml:"ExitCode"`
+			} `xml:"CommandState"`
+		} `xml:"ReceiveResponse"`
+		Fault *struct {
+			Reason struct {
+				Text string `xml:"Text"`
+			} `xml:"Reason"`
+			Detail struct {
+				WSManFault struct {
+					Code string `xml:"Code,attr"`
+				} `xml:"WSManFault"`
+			} `xml:"Detail"`
+		} `xml:"Fault"`
+	} `xml:"Body"`
+}
+
+//winrmClient represents minimal WS-Management shell client, it authenticates with Basic or Negotiate (NTLMv2) scheme
+type winrmClient struct {
+	endpoint   string
+	username   string
+	password   string
+	auth       string
+	mux        *sync.Mutex
+	httpClient *http.Client
+}
+
+func (c *winrmClient) envelope(action, shellID string, timeout time.Duration, options, body string) string {
+	var selector = ""
+	if shellID != "" {
+		selector = fmt.Sprintf(`<w:SelectorSet><w:Selector Name="ShellId">%v</w:Selector></w:SelectorSet>`, shellID)
+	}
+	return fmt.Sprintf(`<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:w="http://schemas.dmtf.org/wbem/wsman/1/wsman.xsd" xmlns:rsp="http://schemas.microsoft.com/wbem/wsman/1/windows/shell">`+
+		`<env:Header><a:To>%v</a:To><a:ReplyTo><a:Address env:mustUnderstand="true">http://schemas.xmlsoap.org/ws/2004/08/addressing/role/anonymous</a:Address></a:ReplyTo>`+
+		`<w:MaxEnvelopeSize env:mustUnderstand="true">153600</w:MaxEnvelopeSize><a:MessageID>uuid:%v</a:MessageID>`+
+		`<w:Locale xml:lang="en-US" env:mustUnderstand="false"/><w:OperationTimeout>PT%vS</w:OperationTimeout>`+
+		`<w:ResourceURI env:mustUnderstand="true">%v</w:ResourceURI><a:Action env:mustUnderstand="true">%v</a:Action>%v%v</env:Header>`+
+		`<env:Body>%v</env:Body></env:Envelope>`,
+		c.endpoint, toolbox.AsString(time.Now().UnixNano()), int(timeout/time.Second), winrmShellURI, action, selector, options, body)
+}
+
+func (c *winrmClient) newRequest(payload string) (*http.Request, error) {
+	request, err := http.NewRequest(http.MethodPost, c.endpoint, strings.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/soap+xml;charset=UTF-8")
+	return request, nil
+}
+
+//do sends request and discards response body, so that connection can be reused
+func (c *winrmClient) do(request *http.Request) (*http.Response, error) {
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	_ = response.Body.Close()
+	return response, nil
+}
+
+//authScheme returns authentication scheme, if not configured, it is detected from the endpoint challenge, Negotiate is preferred over Basic
+func (c *winrmClient) authScheme() (string, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.auth != "" {
+		return c.auth, nil
+	}
+	request, err := c.newRequest("")
+	if err != nil {
+		return "", err
+	}
+	response, err := c.do(request)
+	if err != nil {
+		return "", err
+	}
+	c.auth = winrmAuthBasic
+	for _, challenge := range response.Header.Values("WWW-Authenticate") {
+		if fields := strings.Fields(challenge); len(fields) > 0 {
+			switch {
+			case strings.EqualFold(fields[0], winrmAuthNegotiate):
+				c.auth = winrmAuthNegotiate
+				return c.auth, nil
+			case strings.EqualFold(fields[0], winrmAuthNTLM):
+				c.auth = winrmAuthNTLM
+			}
+		}
+	}
+	return c.auth, nil
+}
+
+//post posts payload with Basic or NTLM authentication
+func (c *winrmClient) post(payload string) (*http.Response, error) {
+	scheme, err := c.authScheme()
+	if err != nil {
+		return nil, err
+	}
+	request, err := c.newRequest(payload)
+	if err != nil {
+		return nil, err
+	}
+	if scheme == winrmAuthBasic {
+		if !strings.HasPrefix(c.endpoint, "https://") {
+			return nil, fmt.Errorf("WinRM Basic authentication over unencrypted HTTP is not allowed, use %v:// target or NTLM authentication", WinRMSecureScheme)
+		}
+		request.SetBasicAuth(c.username, c.password)
+		return c.httpClient.Do(request)
+	}
+	//NTLM authenticates connection, negotiate and authenticate messages have to be sent over the same connection
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	negotiate, err := c.newRequest("")
+	if err != nil {
+		return nil, err
+	}
+	negotiate.Header.Set("Authorization", scheme+" "+base64.StdEncoding.EncodeToString(ntlmNegotiateMessage()))
+	response, err := c.do(negotiate)
+	if err != nil {
+		return nil, err
+	}
+	var challenge []byte
+	for _, candidate := range response.Header.Values("WWW-Authenticate") {
+		if fields := strings.Fields(candidate); len(fields) == 2 && strings.EqualFold(fields[0], scheme) {
+			if challenge, err = base64.StdEncoding.DecodeString(fields[1]); err != nil {
+				return nil, fmt.Errorf("invalid NTLM challenge: %v", err)
+			}
+		}
+	}
+	if response.StatusCode != http.StatusUnauthorized || len(challenge) == 0 {
+		return nil, fmt.Errorf("WinRM %v authentication failed: %v", scheme, response.Status)
+	}
+	authenticate, err := ntlmAuthenticateMessage(challenge, c.username, c.password)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", scheme+" "+base64.StdEncoding.EncodeToString(authenticate))
+	return c.httpClient.Do(request)
+}
+
+//send posts SOAP envelope, it returns fault code with error for WS-Management fault
+func (c *winrmClient) send(payload string) (*wsmanEnvelope, string, error) {
+	response, err := c.post(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	var envelope = &wsmanEnvelope{}
+	if len(body) > 0 {
+		if err = xml.Unmarshal(body, envelope); err != nil {
+			return nil, "", fmt.Errorf("invalid WinRM response (%v): %v, %s", response.StatusCode, err, body)
+		}
+	}
+	if fault := envelope.Body.Fault; fault != nil {
+		return envelope, fault.Detail.WSManFault.Code, fmt.Errorf("WinRM fault: %v", strings.TrimSpace(fault.Reason.Text))
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("WinRM request failed: %v", response.Status)
+	}
+	return envelope, "", nil
+}
+
+func (c *winrmClient) createShell() (string, error) {
+	options := `<w:OptionSet><w:Option Name="WINRS_NOPROFILE">FALSE</w:Option><w:Option Name="WINRS_CODEPAGE">65001</w:Option></w:OptionSet>`
+	body := `<rsp:Shell><rsp:InputStreams>stdin</rsp:InputStreams><rsp:OutputStreams>stdout stderr</rsp:OutputStreams></rsp:Shell>`
+	envelope, _, err := c.send(c.envelope(winrmActionCreate, "", winrmMaxReceiveTimeout, options, body))
+	if err != nil {
+		return "", err
+	}
+	if envelope.Body.Shell.ShellID == "" {
+		return "", errors.New("WinRM shell id was empty")
+	}
+	return envelope.Body.Shell.ShellID, nil
+}
+
+func (c *winrmClient) deleteShell(shellID string) error {
+	_, _, err := c.send(c.envelope(winrmActionDelete, shellID, winrmMaxReceiveTimeout, "", ""))
+	return err
+}
+
+//command starts powershell script in the shell, it returns command id
+func (c *winrmClient) command(shellID, script string) (string, error) {
+	options := `<w:OptionSet><w:Option Name="WINRS_CONSOLEMODE_STDIN">TRUE</w:Option><w:Option Name="WINRS_SKIP_CMD_SHELL">FALSE</w:Option></w:OptionSet>`
+	body := fmt.Sprintf(`<rsp:CommandLine><rsp:Command>powershell.exe</rsp:Command><rsp:Arguments>-NoProfile -NonInteractive -ExecutionPolicy Bypass -EncodedCommand %v</rsp:Arguments></rsp:CommandLine>`, encodePowerShell(script))
+	envelope, _, err := c.send(c.envelope(winrmActionCommand, shellID, winrmMaxReceiveTimeout, options, body))
+	if err != nil {
+		return "", err
+	}
+	return envelope.Body.CommandResponse.CommandID, nil
+}
+
+//receive reads available command output, it returns true with exit code when command is done
+func (c *winrmClient) receive(shellID, commandID string, timeout time.Duration) (stdout, stderr string, done bool, exitCode int, err error) {
+	body := fmt.Sprintf(`<rsp:Receive><rsp:DesiredStream CommandId="%v">stdout stderr</rsp:DesiredStream></rsp:Receive>`, commandID)
+	envelope, faultCode, err := c.send(c.envelope(winrmActionReceive, shellID, timeout, "", body))
+	if err != nil {
+		if faultCode == winrmTimedOutFaultCode {
+			return "", "", false, 0, nil
+		}
+		return "", "", false, 0, err
+	}
+	response := envelope.Body.ReceiveResponse
+	for _, stream := range response.Streams {
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(stream.Value))
+		if err != nil {
+			return stdout, stderr, false, 0, err
+		}
+		if stream.Name == "stderr" {
+			stderr += string(data)
+			continue
+		}
+		stdout += string(data)
+	}
+	return stdout, stderr, response.CommandState.State == winrmCommandStateDone, response.CommandState.ExitCode, nil
+}
+
+func (c *winrmClient) terminate(shellID, commandID string) error {
+	body := fmt.Sprintf(`<rsp:Signal CommandId="%v"><rsp:Code>%v</rsp:Code></rsp:Signal>`, commandID, winrmSignalTerminate)
+	_, _, err := c.send(c.envelope(winrmActionSignal, shellID, winrmMaxReceiveTimeout, "", body))
+	return err
+}
+
+//run runs powershell script, stdout is passed to listener as it is received, it returns stdout and stderr after command is done or no output was received within timeout
+func (c *winrmClient) run(shellID, script string, listener ssh.Listener, timeoutMs int) (string, string, int, error) {
+	commandID, err := c.command(shellID, script)
+	if err != nil {
+		return "", "", 0, err
+	}
+	if timeoutMs <= 0 {
+		timeoutMs = winrmDefaultTimeoutMs
+	}
+	var timeout = time.Duration(timeoutMs) * time.Millisecond
+	var receiveTimeout = timeout
+	if receiveTimeout > winrmMaxReceiveTimeout {
+		receiveTimeout = winrmMaxReceiveTimeout
+	}
+	if receiveTimeout < time.Second {
+		receiveTimeout = time.Second
+	}
+	var output, errorOutput string
+	var lastOutput = time.Now()
+	for {
+		stdout, stderr, done, exitCode, err := c.receive(shellID, commandID, receiveTimeout)
+		if err != nil {
+			return output, errorOutput, 0, err
+		}
+		if stdout != "" || stderr != "" {
+			lastOutput = time.Now()
+			output += stdout
+			errorOutput += stderr
+			if listener != nil && stdout != "" {
+				listener(stdout, !done)
+			}
+		}
+		if done {
+			_ = c.terminate(shellID, commandID)
+			return output, errorOutput, exitCode, nil
+		}
+		if time.Since(lastOutput) >= timeout {
+			return output, errorOutput, 0, nil
+		}
+	}
+}
+
+//winrmAuth returns authentication scheme for target auth query parameter (basic or ntlm), empty scheme is detected with the first request
+func winrmAuth(target *url.Resource) string {
+	switch strings.ToLower(target.ParsedURL.Query().Get("auth")) {
+	case "basic":
+		return winrmAuthBasic
+	case "ntlm", "negotiate":
+		return winrmAuthNegotiate
+	}
+	return ""
+}
+
+func newWinRMClient(target *url.Resource, config *cred.Config) *winrmClient {
+	return &winrmClient{
+		endpoint: winrmEndpoint(target),
+		username: config.Username,
+		password: config.Password,
+		auth:     winrmAuth(target),
+		mux:      &sync.Mutex{},
+		httpClient: &http.Client{
+			Timeout: winrmMaxReceiveTimeout + 10*time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: target.ParsedURL.Query().Get("insecure") == "true"},
+				MaxConnsPerHost: 1,
+			},
+		},
+	}
+}
+
+//commandError returns command error with stderr or stdout if stderr is empty
+func commandError(message string, stdout, stderr string) error {
+	output := strings.TrimSpace(stderr)
+	if output == "" {
+		output = strings.TrimSpace(stdout)
+	}
+	return fmt.Errorf("%v: %v", message, output)
+}
+
+//winrmService represents WinRM based ssh.Service implementation
+type winrmService struct {
+	client *winrmClient
+}
+
+//Client returns nil, WinRM service does not use SSH client
+func (s *winrmService) Client() *cssh.Client {
+	return nil
+}
+
+//OpenMultiCommandSession opens WinRM shell session
+func (s *winrmService) OpenMultiCommandSession(config *ssh.SessionConfig) (ssh.MultiCommandSession, error) {
+	session := &winrmSession{client: s.client, env: make(map[string]string), mux: &sync.Mutex{}}
+	return session, session.Reconnect()
+}
+
+//Run runs a powershell command
+func (s *winrmService) Run(command string) error {
+	shellID, err := s.client.createShell()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.client.deleteShell(shellID) }()
+	output, errorOutput, exitCode, err := s.client.run(shellID, command, nil, 0)
+	if err == nil && exitCode != 0 {
+		err = commandError(fmt.Sprintf("exit code: %v", exitCode), output, errorOutput)
+	}
+	return err
+}
+
+//Upload uploads content to destination with base64 encoded chunks
+func (s *winrmService) Upload(destination string, mode os.FileMode, content []byte) error {
+	shellID, err := s.client.createShell()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.client.deleteShell(shellID) }()
+	var fileMode = "Create"
+	for offset := 0; offset == 0 || offset < len(content); offset += winrmUploadChunkSize {
+		end := offset + winrmUploadChunkSize
+		if end > len(content) {
+			end = len(content)
+		}
+		script := fmt.Sprintf("$ErrorActionPreference='Stop'; $data=[Convert]::FromBase64String('%v'); $file=[IO.File]::Open(%v, [IO.FileMode]::%v); $file.Write($data, 0, $data.Length); $file.Close()",
+			base64.StdEncoding.EncodeToString(content[offset:end]), powerShellQuote(destination), fileMode)
+		output, errorOutput, exitCode, err := s.client.run(shellID, script, nil, 0)
+		if err != nil {
+			return err
+		}
+		if exitCode != 0 {
+			return commandError(fmt.Sprintf("failed to upload %v", destination), output, errorOutput)
+		}
+		fileMode = "Append"
+	}
+	return nil
+}
+
+//Download downloads file content
+func (s *winrmService) Download(source string) ([]byte, error) {
+	shellID, err := s.client.createShell()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = s.client.deleteShell(shellID) }()
+	output, errorOutput, exitCode, err := s.client.run(shellID, fmt.Sprintf("$ErrorActionPreference='Stop'; [Convert]::ToBase64String([IO.File]::ReadAllBytes(%v))", powerShellQuote(source)), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exitCode != 0 {
+		return nil, commandError(fmt.Sprintf("failed to download %v", source), output, errorOutput)
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(output))
+}
+
+//OpenTunnel returns error, tunneling is not supported with WinRM
+func (s *winrmService) OpenTunnel(localAddress, remoteAddress string) error {
+	return errors.New("tunnel is not supported with WinRM")
+}
+
+//NewSession returns error, SSH session is not supported with WinRM
+func (s *winrmService) NewSession() (*cssh.Session, error) {
+	return nil, errors.New("ssh session is not supported with WinRM")
+}
+
+//Close closes service
+func (s *winrmService) Close() error {
+	return nil
+}
+
+func newWinRMService(target *url.Resource, config *cred.Config) ssh.Service {
+	return &winrmService{client: newWinRMClient(target, config)}
+}
+
+//winrmSession represents WinRM shell session, each command runs with powershell in the session directory and environment
+type winrmSession struct {
+	client    *winrmClient
+	shellID   string
+	system    string
+	directory string
+	env       map[string]string
+	exitCode  int
+	stderr    string
+	mux       *sync.Mutex
+}
+
+//script returns powershell script setting session environment and directory before running the command
+func (s *winrmSession) script(command string) string {
+	var script = new(bytes.Buffer)
+	for key, value := range s.env {
+		script.WriteString(fmt.Sprintf("$env:%v=%v\n", key, powerShellQuote(value)))
+	}
+	if s.directory != "" {
+		script.WriteString(fmt.Sprintf("Set-Location -LiteralPath %v\n", powerShellQuote(s.directory)))
+	}
+	script.WriteString(command)
+	script.WriteString("\nif (-not $?) { if ($LASTEXITCODE) { exit $LASTEXITCODE }; exit 1 }\nexit 0")
+	return script.String()
+}
+
+//Run runs command, cd, export and echo $? commands are handled by the session
+func (s *winrmSession) Run(command string, listener ssh.Listener, timeoutMs int, terminators ...string) (string, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	command = strings.TrimSpace(command)
+	switch {
+	case command == "echo $?":
+		return toolbox.AsString(s.exitCode), nil
+	case strings.HasPrefix(command, "cd ") && !strings.Contains(command, "&&"):
+		directory := strings.Trim(strings.TrimSpace(command[3:]), `'"`)
+		output, errorOutput, _, err := s.client.run(s.shellID, fmt.Sprintf("if (Test-Path -LiteralPath %v -PathType Container) { 'ok' } else { '%v' }", powerShellQuote(directory), winrmNoSuchFileOrFolder), nil, timeoutMs)
+		s.stderr = errorOutput
+		if err == nil && !strings.Contains(output, winrmNoSuchFileOrFolder) {
+			s.directory = directory
+		}
+		return output, err
+	case strings.HasPrefix(command, "export ") && !strings.Contains(command, "&&"):
+		if pair := strings.SplitN(command[7:], "=", 2); len(pair) == 2 {
+			s.env[strings.TrimSpace(pair[0])] = strings.Trim(strings.TrimSpace(pair[1]), `'"`)
+			s.stderr = ""
+			return "", nil
+		}
+	}
+	output, errorOutput, exitCode, err := s.client.run(s.shellID, s.script(command), listener, timeoutMs)
+	s.exitCode = exitCode
+	s.stderr = errorOutput
+	return output, err
+}
+
+//Stderr returns the last command stderr, WinRM keeps stderr separately from stdout
+func (s *winrmSession) Stderr() string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.stderr
+}
+
+//ShellPrompt returns empty prompt, WinRM command completion does not depend on prompt
+func (s *winrmSession) ShellPrompt() string {
+	return ""
+}
+
+//System returns detected target system
+func (s *winrmSession) System() string {
+	return s.system
+}
+
+//Reconnect opens a new WinRM shell and detects target system, only windows targets are supported
+func (s *winrmSession) Reconnect() error {
+	if s.shellID != "" {
+		_ = s.client.deleteShell(s.shellID)
+	}
+	var err error
+	if s.shellID, err = s.client.createShell(); err != nil {
+		return err
+	}
+	if s.system != "" {
+		return nil
+	}
+	output, errorOutput, _, err := s.client.run(s.shellID, "[Environment]::OSVersion.Platform", nil, 0)
+	if err != nil {
+		return err
+	}
+	if platform := strings.TrimSpace(output); platform != winrmWindowsPlatform {
+		return commandError("unsupported WinRM target platform", platform, errorOutput)
+	}
+	s.system = WindowsSystem
+	return nil
+}
+
+//Close deletes WinRM shell
+func (s *winrmSession) Close() {
+	if s.shellID != "" {
+		_ = s.client.deleteShell(s.shellID)
+		s.shellID = ""
+	}
+}
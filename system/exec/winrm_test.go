@@ -0,0 +1,294 @@
+package exec
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox/cred"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf16"
+)
+
+var (
+	wsmanActionExpr  = regexp.MustCompile(`<a:Action[^>]*>([^<]+)</a:Action>`)
+	wsmanCommandExpr = regexp.MustCompile(`-EncodedCommand ([^<]+)</rsp:Arguments>`)
+)
+
+//fakeWinRM emulates WS-Management shell endpoint, handler returns output and exit code for decoded powershell script,
+//output of failed script is written to stderr stream
+type fakeWinRM struct {
+	mux      *sync.Mutex
+	ntlm     bool
+	platform string
+	scripts  []string
+	shells   int
+	handler  func(script string) (string, int)
+	pending  map[string]string
+	errors   map[string]string
+	codes    map[string]int
+}
+
+func decodePowerShell(encoded string) string {
+	data, _ := base64.StdEncoding.DecodeString(encoded)
+	var runes = make([]uint16, len(data)/2)
+	for i := range runes {
+		runes[i] = uint16(data[2*i]) | uint16(data[2*i+1])<<8
+	}
+	return string(utf16.Decode(runes))
+}
+
+//authorized returns true if request is authorized with tester/secret Basic or NTLMv2 credentials, otherwise it writes challenge
+func (f *fakeWinRM) authorized(writer http.ResponseWriter, request *http.Request) bool {
+	if !f.ntlm {
+		if username, password, _ := request.BasicAuth(); username == "tester" && password == "secret" {
+			return true
+		}
+		writer.Header().Set("WWW-Authenticate", `Basic realm="WSMAN"`)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+	var message []byte
+	if fields := strings.Fields(request.Header.Get("Authorization")); len(fields) == 2 && fields[0] == winrmAuthNegotiate {
+		message, _ = base64.StdEncoding.DecodeString(fields[1])
+	}
+	challenge := make([]byte, 48)
+	copy(challenge, ntlmSignature)
+	binary.LittleEndian.PutUint32(challenge[8:], 2)
+	binary.LittleEndian.PutUint32(challenge[20:], ntlmNegotiateFlags)
+	copy(challenge[24:], "server01")
+	binary.LittleEndian.PutUint32(challenge[44:], 48)
+	switch {
+	case len(message) > 12 && binary.LittleEndian.Uint32(message[8:]) == 1:
+		writer.Header().Set("WWW-Authenticate", winrmAuthNegotiate+" "+base64.StdEncoding.EncodeToString(challenge))
+	case len(message) > ntlmAuthenticateLen && binary.LittleEndian.Uint32(message[8:]) == 3:
+		length := binary.LittleEndian.Uint16(message[20:])
+		offset := binary.LittleEndian.Uint32(message[24:])
+		ntResponse := message[offset : offset+uint32(length)]
+		expected, _ := ntlmV2Response("tester", "", "secret", challenge[24:32], ntResponse[32:40], ntResponse[24:32], nil)
+		if bytes.Equal(expected, ntResponse) {
+			return true
+		}
+	default:
+		writer.Header().Set("WWW-Authenticate", winrmAuthNegotiate)
+	}
+	writer.WriteHeader(http.StatusUnauthorized)
+	return false
+}
+
+func (f *fakeWinRM) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if !f.authorized(writer, request) {
+		return
+	}
+	body, _ := ioutil.ReadAll(request.Body)
+	action := wsmanActionExpr.FindStringSubmatch(string(body))[1]
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	var response string
+	switch action {
+	case winrmActionCreate:
+		f.shells++
+		response = fmt.Sprintf(`<rsp:Shell><rsp:ShellId>shell-%v</rsp:ShellId></rsp:Shell>`, f.shells)
+	case winrmActionCommand:
+		script := decodePowerShell(wsmanCommandExpr.FindStringSubmatch(string(body))[1])
+		f.scripts = append(f.scripts, script)
+		commandID := fmt.Sprintf("command-%v", len(f.scripts))
+		if script == "[Environment]::OSVersion.Platform" {
+			f.pending[commandID] = f.platform
+		} else if f.pending[commandID], f.codes[commandID] = f.handler(script); f.codes[commandID] != 0 {
+			f.errors[commandID], f.pending[commandID] = f.pending[commandID], ""
+		}
+		response = fmt.Sprintf(`<rsp:CommandResponse><rsp:CommandId>%v</rsp:CommandId></rsp:CommandResponse>`, commandID)
+	case winrmActionReceive:
+		commandID := regexp.MustCompile(`CommandId="([^"]+)"`).FindStringSubmatch(string(body))[1]
+		response = fmt.Sprintf(`<rsp:ReceiveResponse><rsp:Stream Name="stdout" CommandId="%v">%v</rsp:Stream><rsp:Stream Name="stderr" CommandId="%v">%v</rsp:Stream>`+
+			`<rsp:CommandState CommandId="%v" State="%v"><rsp:ExitCode>%v</rsp:ExitCode></rsp:CommandState></rsp:ReceiveResponse>`,
+			commandID, base64.StdEncoding.EncodeToString([]byte(f.pending[commandID])), commandID, base64.StdEncoding.EncodeToString([]byte(f.errors[commandID])),
+			commandID, winrmCommandStateDone, f.codes[commandID])
+	}
+	writer.Header().Set("Content-Type", "application/soap+xml;charset=UTF-8")
+	_, _ = writer.Write([]byte(`<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:rsp="http://schemas.microsoft.com/wbem/wsman/1/windows/shell"><s:Body>` + response + `</s:Body></s:Envelope>`))
+}
+
+func newFakeWinRM(handler func(script string) (string, int)) (*fakeWinRM, *httptest.Server) {
+	fake := &fakeWinRM{mux: &sync.Mutex{}, platform: winrmWindowsPlatform, handler: handler, pending: make(map[string]string), errors: make(map[string]string), codes: make(map[string]int)}
+	return fake, httptest.NewTLSServer(fake)
+}
+
+//fakeWinRMURL returns HTTPS WinRM target URL for fake endpoint server
+func fakeWinRMURL(server *httptest.Server) string {
+	return strings.Replace(server.URL, "https://", WinRMSecureScheme+"://", 1) + "/?insecure=true"
+}
+
+func TestIsWinRMTarget(t *testing.T) {
+	assert.True(t, IsWinRMTarget(url.NewResource("winrm://10.0.0.1/")))
+	assert.True(t, IsWinRMTarget(url.NewResource("winrms://10.0.0.1/")))
+	assert.True(t, IsWinRMTarget(url.NewResource("ssh://10.0.0.1:5986/")))
+	assert.False(t, IsWinRMTarget(url.NewResource("ssh://10.0.0.1:22/")))
+	assert.Equal(t, "https://10.0.0.1:5986/wsman", winrmEndpoint(url.NewResource("winrms://10.0.0.1/")))
+	assert.Equal(t, "http://10.0.0.1:5985/wsman", winrmEndpoint(url.NewResource("winrm://10.0.0.1/")))
+}
+
+func TestEncodePowerShell(t *testing.T) {
+	assert.Equal(t, "ZQBjAGgAbwAgADEA", encodePowerShell("echo 1"))
+	assert.Equal(t, "echo 1", decodePowerShell(encodePowerShell("echo 1")))
+	assert.Equal(t, "'it''s'", powerShellQuote("it's"))
+}
+
+func TestWinRMService_Upload(t *testing.T) {
+	fake, server := newFakeWinRM(func(script string) (string, int) {
+		if strings.Contains(script, "ReadAllBytes") {
+			return base64.StdEncoding.EncodeToString([]byte("abc")), 0
+		}
+		return "", 0
+	})
+	defer server.Close()
+	service := newWinRMService(url.NewResource(fakeWinRMURL(server)), &cred.Config{Username: "tester", Password: "secret"})
+	content := bytes.Repeat([]byte("x"), winrmUploadChunkSize*2+10)
+	if assert.Nil(t, service.Upload(`C:\Windows\Temp\app.txt`, 0644, content)) {
+		if assert.Equal(t, 3, len(fake.scripts)) {
+			assert.Contains(t, fake.scripts[0], "[IO.FileMode]::Create")
+			assert.Contains(t, fake.scripts[0], `'C:\Windows\Temp\app.txt'`)
+			assert.Contains(t, fake.scripts[2], "[IO.FileMode]::Append")
+		}
+	}
+	downloaded, err := service.Download(`C:\Windows\Temp\app.txt`)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "abc", string(downloaded))
+	}
+	service = newWinRMService(url.NewResource(fakeWinRMURL(server)), &cred.Config{Username: "tester", Password: "invalid"})
+	assert.NotNil(t, service.Upload(`C:\Windows\Temp\app.txt`, 0644, content))
+}
+
+func TestExecService_RunWinRM(t *testing.T) {
+	fake, server := newFakeWinRM(func(script string) (string, int) {
+		switch {
+		case strings.Contains(script, "Win32_OperatingSystem"):
+			return "Microsoft Windows Server 2019 Datacenter\r\n10.0.17763\r\nAMD64\r\nAdministrator\r\nC:\\Windows\\system32;C:\\Windows\r\n", 0
+		case strings.Contains(script, "Test-Path"):
+			if strings.Contains(script, "missing") {
+				return winrmNoSuchFileOrFolder, 0
+			}
+			return "ok", 0
+		case strings.Contains(script, "Get-Service"):
+			return "Running\r\n", 0
+		}
+		return "The term 'Invoke-Failure' is not recognized", 3
+	})
+	defer server.Close()
+	target := url.NewResource(fakeWinRMURL(server), "winrm")
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+	_, err := context.Service(ServiceID)
+	if !assert.Nil(t, err) {
+		return
+	}
+	replayService := newWinRMService(target, &cred.Config{Username: "tester", Password: "secret"})
+	err = endly.Run(context, &OpenSessionRequest{Target: target, ReplayService: replayService}, &OpenSessionResponse{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	operatingSystem := OperatingSystem(context, SessionID(context, target))
+	if assert.NotNil(t, operatingSystem) {
+		assert.Equal(t, WindowsSystem, operatingSystem.System)
+		assert.Equal(t, "10.0.17763", operatingSystem.Version)
+		assert.Equal(t, "amd64", operatingSystem.Architecture)
+	}
+	session := TerminalSessions(context)[SessionID(context, target)]
+	assert.Equal(t, "Administrator", session.Username)
+	assert.Equal(t, []string{`C:\Windows\system32`, `C:\Windows`}, session.Path.Items)
+
+	response := &RunResponse{}
+	err = endly.Run(context, NewRunRequest(target, true, "export APP_ENV=test", "cd C:\\app", "Get-Service app | Select -Expand Status"), response)
+	if assert.Nil(t, err) {
+		assert.Contains(t, response.Stdout(), "Running")
+		script := fake.scripts[len(fake.scripts)-1]
+		assert.Contains(t, script, "$env:APP_ENV='test'")
+		assert.Contains(t, script, "Set-Location -LiteralPath 'C:\\app'")
+		assert.False(t, strings.HasPrefix(strings.TrimSpace(strings.Split(script, "\n")[2]), "sudo"))
+	}
+
+	err = endly.Run(context, NewRunRequest(target, false, "cd C:\\missing"), &RunResponse{})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "C:\\app", session.CurrentDirectory)
+	}
+
+	request := NewRunRequest(target, false, "Invoke-Failure")
+	response = &RunResponse{}
+	err = endly.Run(context, request, response)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(response.Cmd)) {
+		assert.Equal(t, "", response.Cmd[0].Stdout)
+		assert.Equal(t, "The term 'Invoke-Failure' is not recognized", response.Cmd[0].Stderr)
+	}
+	request.CheckError = true
+	err = endly.Run(context, request, &RunResponse{})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "is not recognized")
+	}
+}
+
+func TestWinRMService_NTLM(t *testing.T) {
+	fake, server := newFakeWinRM(func(script string) (string, int) {
+		if strings.Contains(script, "Get-Date") {
+			return "2020", 0
+		}
+		return "Access is denied", 5
+	})
+	fake.ntlm = true
+	defer server.Close()
+	target := url.NewResource(fakeWinRMURL(server))
+	service := newWinRMService(target, &cred.Config{Username: "tester", Password: "secret"})
+	if assert.Nil(t, service.Run("Get-Date")) {
+		assert.Equal(t, winrmAuthNegotiate, service.(*winrmService).client.auth)
+	}
+	err := service.Run("Remove-Item C:\\Windows")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "exit code: 5: Access is denied", err.Error())
+	}
+	service = newWinRMService(target, &cred.Config{Username: "tester", Password: "invalid"})
+	assert.NotNil(t, service.Run("Get-Date"))
+	service = newWinRMService(url.NewResource(target.URL+"&auth=basic"), &cred.Config{Username: "tester", Password: "secret"})
+	assert.NotNil(t, service.Run("Get-Date"))
+}
+
+func TestWinRMService_BasicOverHTTP(t *testing.T) {
+	fake, _ := newFakeWinRM(func(script string) (string, int) {
+		return "2020", 0
+	})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	target := url.NewResource(strings.Replace(server.URL, "http://", WinRMScheme+"://", 1))
+	err := newWinRMService(target, &cred.Config{Username: "tester", Password: "secret"}).Run("Get-Date")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unencrypted HTTP")
+	}
+	assert.Equal(t, 0, len(fake.scripts))
+	fake.ntlm = true
+	assert.Nil(t, newWinRMService(target, &cred.Config{Username: "tester", Password: "secret"}).Run("Get-Date"))
+}
+
+func TestWinRMService_OpenMultiCommandSession(t *testing.T) {
+	fake, server := newFakeWinRM(func(script string) (string, int) {
+		return "", 0
+	})
+	defer server.Close()
+	service := newWinRMService(url.NewResource(fakeWinRMURL(server)), &cred.Config{Username: "tester", Password: "secret"})
+	session, err := service.OpenMultiCommandSession(nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, WindowsSystem, session.System())
+	}
+	fake.platform = "Unix"
+	_, err = service.OpenMultiCommandSession(nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Unix")
+	}
+}
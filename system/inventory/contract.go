@@ -0,0 +1,76 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/endly/model/inventory"
+	"github.com/viant/toolbox/url"
+)
+
+//LoadRequest represents inventory load request, hosts from all sources are merged into context inventory
+type LoadRequest struct {
+	Source *url.Resource `description:"inventory YAML or JSON file with hosts and groups"`
+	*inventory.Inventory
+	Queries []*Query `description:"cloud provider or any service action queries returning hosts"`
+}
+
+//Query represents service action query, i.e. aws/ec2:describeInstances, each item at Items path becomes a host
+type Query struct {
+	Service     string                 `required:"true" description:"service id, i.e. aws/ec2"`
+	Action      string                 `required:"true" description:"service action, i.e. describeInstances"`
+	Request     map[string]interface{} `description:"service action request"`
+	Items       string                 `required:"true" description:"response host items path, slices are flattened, i.e. Reservations.Instances"`
+	Name        string                 `description:"host name template expanded with item, i.e. ${InstanceId}"`
+	URL         string                 `required:"true" description:"host URL template expanded with item, i.e. ssh://${PrivateIpAddress}:22"`
+	Credentials string                 `description:"hosts credentials"`
+	Roles       []string               `description:"hosts roles"`
+	Vars        map[string]interface{} `description:"hosts variables, expanded with item"`
+}
+
+//LoadResponse represents inventory load response
+type LoadResponse struct {
+	Loaded int                 `description:"number of loaded hosts"`
+	Groups map[string][]string `description:"context inventory groups with host names"`
+}
+
+//HostsRequest represents group hosts request
+type HostsRequest struct {
+	Group string `required:"true" description:"group name, role or 'all'"`
+}
+
+//HostsResponse represents group hosts response
+type HostsResponse struct {
+	Hosts []*inventory.Host
+}
+
+//Init initialises request
+func (r *LoadRequest) Init() error {
+	if r.Inventory == nil {
+		r.Inventory = &inventory.Inventory{}
+	}
+	return r.Inventory.Init()
+}
+
+//Validate checks if request is valid
+func (r *LoadRequest) Validate() error {
+	if r.Source == nil && len(r.Hosts) == 0 && len(r.Queries) == 0 {
+		return errors.New("source, hosts and queries were empty")
+	}
+	for i, query := range r.Queries {
+		if query.Service == "" || query.Action == "" {
+			return fmt.Errorf("queries[%d]: service and action were empty", i)
+		}
+		if query.Items == "" || query.URL == "" {
+			return fmt.Errorf("queries[%d]: items and URL were empty", i)
+		}
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *HostsRequest) Validate() error {
+	if r.Group == "" {
+		return errors.New("group was empty")
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/inventory"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"strings"
+)
+
+const (
+	//ServiceID represents inventory service id
+	ServiceID = "inventory"
+)
+
+type service struct {
+	*endly.AbstractService
+}
+
+//load loads hosts from source, request and queries into context inventory
+func (s *service) load(context *endly.Context, request *LoadRequest) (*LoadResponse, error) {
+	var loaded = &inventory.Inventory{}
+	var merge = func(source *inventory.Inventory) error {
+		if err := source.Init(); err != nil {
+			return err
+		}
+		loaded.Merge(source)
+		return nil
+	}
+	if request.Source != nil {
+		source, err := context.ExpandResource(request.Source)
+		if err != nil {
+			return nil, err
+		}
+		var fromSource = &inventory.Inventory{}
+		if err = source.Decode(fromSource); err != nil {
+			return nil, fmt.Errorf("failed to decode inventory %v: %v", source.URL, err)
+		}
+		if err = merge(fromSource); err != nil {
+			return nil, err
+		}
+	}
+	if err := merge(request.Inventory); err != nil {
+		return nil, err
+	}
+	for _, query := range request.Queries {
+		hosts, err := s.query(context, query)
+		if err != nil {
+			return nil, err
+		}
+		if err = merge(&inventory.Inventory{Hosts: hosts}); err != nil {
+			return nil, err
+		}
+	}
+	merged, err := inventory.Load(context, loaded)
+	if err != nil {
+		return nil, err
+	}
+	return &LoadResponse{Loaded: len(loaded.Hosts), Groups: merged.GroupNames()}, nil
+}
+
+//query runs query service action and builds hosts from response items
+func (s *service) query(context *endly.Context, query *Query) ([]*inventory.Host, error) {
+	request, err := context.AsRequest(query.Service, query.Action, query.Request)
+	if err != nil {
+		return nil, err
+	}
+	var serviceResponse = &endly.ServiceResponse{}
+	if err = endly.Run(context, request, serviceResponse); err != nil {
+		return nil, fmt.Errorf("failed to query %v:%v: %v", query.Service, query.Action, err)
+	}
+	response, err := asGeneric(serviceResponse.Response)
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]*inventory.Host, 0)
+	for _, item := range queryItems(response, strings.Split(query.Items, ".")) {
+		if !toolbox.IsMap(item) {
+			continue
+		}
+		var itemState = data.Map(toolbox.AsMap(item))
+		var host = &inventory.Host{
+			Name:        itemState.ExpandAsText(query.Name),
+			URL:         itemState.ExpandAsText(query.URL),
+			Credentials: query.Credentials,
+			Roles:       query.Roles,
+		}
+		if len(query.Vars) > 0 {
+			host.Vars = toolbox.AsMap(itemState.Expand(query.Vars))
+		}
+		if strings.Contains(host.URL, "$") {
+			continue //item without address, i.e. stopped instance
+		}
+		result = append(result, host)
+	}
+	return result, nil
+}
+
+//asGeneric returns response as generic maps and slices
+func asGeneric(response interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	err = json.Unmarshal(encoded, &result)
+	return result, err
+}
+
+//queryItems returns items at supplied path, slices are flattened at each path segment
+func queryItems(source interface{}, path []string) []interface{} {
+	if toolbox.IsSlice(source) {
+		var result = make([]interface{}, 0)
+		for _, item := range toolbox.AsSlice(source) {
+			result = append(result, queryItems(item, path)...)
+		}
+		return result
+	}
+	if len(path) == 0 || path[0] == "" {
+		return []interface{}{source}
+	}
+	if !toolbox.IsMap(source) {
+		return nil
+	}
+	return queryItems(toolbox.AsMap(source)[path[0]], path[1:])
+}
+
+func (s *service) hosts(context *endly.Context, request *HostsRequest) (*HostsResponse, error) {
+	hosts, err := inventory.Hosts(context, request.Group)
+	if err != nil {
+		return nil, err
+	}
+	return &HostsResponse{Hosts: hosts}, nil
+}
+
+const (
+	loadExample = `{
+  "Hosts": [
+    {
+      "Name": "web1",
+      "URL": "ssh://10.0.0.1:22",
+      "Credentials": "web",
+      "Roles": ["web"],
+      "Vars": {"port": 8080}
+    },
+    {
+      "Name": "db1",
+      "URL": "ssh://10.0.0.2:22",
+      "Credentials": "db",
+      "Roles": ["db"]
+    }
+  ]
+}`
+	loadQueryExample = `{
+  "Queries": [
+    {
+      "Service": "aws/ec2",
+      "Action": "describeInstances",
+      "Request": {
+        "Credentials": "aws-e2e",
+        "Filters": [
+          {"Name": "tag:Role", "Values": ["web"]},
+          {"Name": "instance-state-name", "Values": ["running"]}
+        ]
+      },
+      "Items": "Reservations.Instances",
+      "Name": "${InstanceId}",
+      "URL": "ssh://${PrivateIpAddress}:22",
+      "Credentials": "ec2",
+      "Roles": ["web"]
+    }
+  ]
+}`
+)
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "load",
+		RequestInfo: &endly.ActionInfo{
+			Description: "load hosts grouped by role from file, request or service query into context inventory",
+			Examples: []*endly.UseCase{
+				{
+					Description: "inline hosts",
+					Data:        loadExample,
+				},
+				{
+					Description: "aws ec2 query",
+					Data:        loadQueryExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &LoadRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &LoadResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*LoadRequest); ok {
+				return s.load(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "hosts",
+		RequestInfo: &endly.ActionInfo{
+			Description: "list group hosts",
+		},
+		RequestProvider: func() interface{} {
+			return &HostsRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &HostsResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*HostsRequest); ok {
+				return s.hosts(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+//New creates a new inventory service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
@@ -0,0 +1,64 @@
+package inventory
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/inventory"
+	"github.com/viant/toolbox/url"
+	"testing"
+)
+
+func TestService_Load(t *testing.T) {
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+	var response = &LoadResponse{}
+	err := endly.Run(context, &LoadRequest{
+		Source: url.NewResource("test/inventory.yaml"),
+		Queries: []*Query{
+			{
+				Service: "nop",
+				Action:  "nop",
+				Request: map[string]interface{}{
+					"In": map[string]interface{}{
+						"Reservations": []interface{}{
+							map[string]interface{}{"Instances": []interface{}{
+								map[string]interface{}{"InstanceId": "i-1", "PrivateIpAddress": "10.1.0.1"},
+								map[string]interface{}{"InstanceId": "i-2"},
+							}},
+							map[string]interface{}{"Instances": []interface{}{
+								map[string]interface{}{"InstanceId": "i-3", "PrivateIpAddress": "10.1.0.3"},
+							}},
+						},
+					},
+				},
+				Items: "Reservations.Instances",
+				Name:  "${InstanceId}",
+				URL:   "ssh://${PrivateIpAddress}:22",
+				Roles: []string{"worker"},
+				Vars:  map[string]interface{}{"id": "$InstanceId"},
+			},
+		},
+	}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 6, response.Loaded)
+	assert.Equal(t, map[string][]string{
+		"web":    {"web1", "web2"},
+		"db":     {"10.0.0.3", "10.0.0.4"},
+		"canary": {"web1"},
+		"worker": {"i-1", "i-3"},
+	}, response.Groups)
+
+	hosts, err := inventory.Hosts(context, "web")
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(hosts)) {
+		assert.Equal(t, "web", hosts[1].Credentials)
+		assert.EqualValues(t, 8081, hosts[1].Vars["port"])
+	}
+	var hostsResponse = &HostsResponse{}
+	err = endly.Run(context, &HostsRequest{Group: "worker"}, hostsResponse)
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(hostsResponse.Hosts)) {
+		assert.Equal(t, "ssh://10.1.0.3:22", hostsResponse.Hosts[1].URL)
+		assert.Equal(t, "i-3", hostsResponse.Hosts[1].Vars["id"])
+	}
+}
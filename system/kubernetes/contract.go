@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/go-errors/errors"
 	"github.com/viant/endly/system/kubernetes/shared"
+	"github.com/viant/endly/testing/validator"
 	"github.com/viant/toolbox/url"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,7 +13,10 @@ import (
 	"strings"
 )
 
-const defaultWaitTimeoutMs = 60000
+const (
+	defaultWaitTimeoutMs     = 60000
+	defaultRolloutIntervalMs = 2000
+)
 
 //ResourceInfoResponse represents info response
 type ResourceInfoResponse struct {
@@ -318,3 +322,78 @@ func (r *ForwardPortsRequest) AsGetRequest() (*GetRequest, error) {
 	result.LabelSelector = r.LabelSelector
 	return result, result.Init()
 }
+
+//WaitForRolloutRequest represents a request to wait until deployment, stateful set or daemon set rollout completes
+type WaitForRolloutRequest struct {
+	Name            string `required:"true" description:"workload name"`
+	metav1.TypeMeta `json:",inline"`
+	TimeoutMs       int `description:"max wait time, default 60000"`
+	IntervalMs      int `description:"status check interval, default 2000"`
+}
+
+//WaitForRolloutResponse represents wait for rollout response
+type WaitForRolloutResponse struct {
+	*RolloutStatus
+	ElapsedMs int
+}
+
+//Init initialises request
+func (r *WaitForRolloutRequest) Init() error {
+	if r.Kind == "" && strings.Contains(r.Name, "/") {
+		pair := strings.SplitN(r.Name, "/", 2)
+		r.Kind = pair[0]
+		r.Name = pair[1]
+	}
+	if r.Kind == "" {
+		r.Kind = "Deployment"
+	}
+	if r.TimeoutMs == 0 {
+		r.TimeoutMs = defaultWaitTimeoutMs
+	}
+	if r.IntervalMs == 0 {
+		r.IntervalMs = defaultRolloutIntervalMs
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *WaitForRolloutRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name was empty")
+	}
+	return nil
+}
+
+//AssertRequest represents a request to validate retrieved resource(s) manifest with expected data
+type AssertRequest struct {
+	Name string `description:"resource name, if empty all matched resources are validated as a list"`
+	metav1.ListOptions
+	Expect interface{} `required:"true" description:"expected resource manifest (or manifests) fragment, assertly macro and predicates are supported"`
+}
+
+//AssertResponse represents assert response
+type AssertResponse struct {
+	Actual interface{}
+	Assert *validator.AssertResponse
+}
+
+//Init initialises request
+func (r *AssertRequest) Init() error {
+	if r.Kind == "" && strings.Contains(r.Name, "/") {
+		pair := strings.SplitN(r.Name, "/", 2)
+		r.Kind = pair[0]
+		r.Name = pair[1]
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *AssertRequest) Validate() error {
+	if r.Kind == "" {
+		return errors.New("kind was empty")
+	}
+	if r.Expect == nil {
+		return errors.New("expect was empty")
+	}
+	return nil
+}
@@ -17,7 +17,7 @@ func ProcessResource(context *endly.Context, expand bool, resource *url.Resource
 	if err != nil {
 		return err
 	}
-	state := context.State()
+	state := context.SafeState().Clone()
 	meta := &ResourceMeta{}
 	var data = make(map[string]interface{})
 	ext := path.Ext(resource.URL)
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/kubernetes/shared"
+	"github.com/viant/endly/testing/validator"
+	"strings"
+	"time"
+)
+
+//RolloutStatus represents workload rollout progress
+type RolloutStatus struct {
+	Desired   int
+	Updated   int
+	Ready     int
+	Available int
+	Old       int `description:"replicas not yet updated to the current template"`
+	Done      bool
+}
+
+//workloadSpec represents replicas spec shared by workload resources
+type workloadSpec struct {
+	Replicas *int
+}
+
+//workloadStatus represents deployment, stateful set and daemon set status fields
+type workloadStatus struct {
+	ObservedGeneration     int64
+	Replicas               int
+	UpdatedReplicas        int
+	ReadyReplicas          int
+	AvailableReplicas      int
+	DesiredNumberScheduled int
+	UpdatedNumberScheduled int
+	NumberReady            int
+	NumberAvailable        int
+}
+
+//NewRolloutStatus returns rollout status for deployment, stateful set or daemon set resource
+func NewRolloutStatus(info *ResourceInfo) (*RolloutStatus, error) {
+	var spec = &workloadSpec{}
+	var status = &workloadStatus{}
+	if info.Spec != nil {
+		if err := converter.AssignConverted(spec, info.Spec); err != nil {
+			return nil, err
+		}
+	}
+	if info.Status != nil {
+		if err := converter.AssignConverted(status, info.Status); err != nil {
+			return nil, err
+		}
+	}
+	var result = &RolloutStatus{}
+	switch strings.ToLower(info.Kind) {
+	case "deployment", "statefulset":
+		result.Desired = 1
+		if spec.Replicas != nil {
+			result.Desired = *spec.Replicas
+		}
+		result.Updated = status.UpdatedReplicas
+		result.Ready = status.ReadyReplicas
+		result.Available = status.AvailableReplicas
+		result.Old = status.Replicas - status.UpdatedReplicas
+		if strings.ToLower(info.Kind) == "statefulset" {
+			result.Available = status.ReadyReplicas
+		}
+	case "daemonset":
+		result.Desired = status.DesiredNumberScheduled
+		result.Updated = status.UpdatedNumberScheduled
+		result.Ready = status.NumberReady
+		result.Available = status.NumberAvailable
+	default:
+		return nil, fmt.Errorf("unsupported rollout kind: %v", info.Kind)
+	}
+	result.Done = status.ObservedGeneration >= info.Generation &&
+		result.Updated >= result.Desired && result.Ready >= result.Desired && result.Available >= result.Desired && result.Old <= 0
+	return result, nil
+}
+
+//String returns rollout status summary
+func (s *RolloutStatus) String() string {
+	return fmt.Sprintf("%v/%v updated, %v ready, %v available", s.Updated, s.Desired, s.Ready, s.Available)
+}
+
+//WaitForRollout waits until all workload replicas run the current template and are available
+func (s *service) WaitForRollout(context *endly.Context, request *WaitForRolloutRequest) (*WaitForRolloutResponse, error) {
+	ctxClient, err := shared.GetCtxClient(context)
+	if err != nil {
+		return nil, err
+	}
+	var response = &WaitForRolloutResponse{}
+	startTime := time.Now()
+	timeout := time.Duration(request.TimeoutMs) * time.Millisecond
+	summary := ""
+	for {
+		getRequest := &GetRequest{Name: request.Name}
+		getRequest.TypeMeta = request.TypeMeta
+		if err = getRequest.Init(); err != nil {
+			return nil, err
+		}
+		ctxClient.RawRequest = nil
+		var resource *ResourceInfo
+		if err = s.get(context, getRequest, func(item *ResourceInfo) error {
+			resource = item
+			return nil
+		}); err != nil && !shared.IsNotFound(err) {
+			return nil, err
+		}
+		if resource != nil {
+			if response.RolloutStatus, err = NewRolloutStatus(resource); err != nil {
+				return nil, err
+			}
+			if summary != response.String() {
+				summary = response.String()
+				context.Publish(shared.NewOutputEvent(fmt.Sprintf("%v/%v - %v", request.Kind, request.Name, summary), "waitForRollout", nil))
+			}
+			if response.Done {
+				response.ElapsedMs = int(time.Since(startTime) / time.Millisecond)
+				return response, nil
+			}
+		}
+		if time.Since(startTime) > timeout {
+			return response, fmt.Errorf("%v/%v rollout timeout exceeded: %v", request.Kind, request.Name, summary)
+		}
+		s.Sleep(context, request.IntervalMs)
+	}
+}
+
+//normalizeManifest converts raw resource to plain JSON data, so that pointers and typed values can be validated
+func normalizeManifest(raw interface{}) (interface{}, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+//Assert retrieves resource(s) and validates them with expected manifest
+func (s *service) Assert(context *endly.Context, request *AssertRequest) (*AssertResponse, error) {
+	ctxClient, err := shared.GetCtxClient(context)
+	if err != nil {
+		return nil, err
+	}
+	getRequest := &GetRequest{Name: request.Name, ListOptions: request.ListOptions}
+	if err = getRequest.Init(); err != nil {
+		return nil, err
+	}
+	ctxClient.RawRequest = nil
+	var response = &AssertResponse{}
+	var items = make([]interface{}, 0)
+	if err = s.get(context, getRequest, func(item *ResourceInfo) error {
+		manifest, err := normalizeManifest(item.Raw)
+		if err == nil {
+			items = append(items, manifest)
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	var actual interface{} = items
+	if !getRequest.multiItem {
+		if len(items) == 0 {
+			return nil, fmt.Errorf("%v/%v was not found", request.Kind, request.Name)
+		}
+		actual = items[0]
+	}
+	response.Actual = actual
+	response.Assert, err = validator.Assert(context, request, request.Expect, actual, fmt.Sprintf("k8s.%v", request.Kind), "assert k8s resource")
+	return response, err
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestNewRolloutStatus(t *testing.T) {
+	var useCases = []struct {
+		description string
+		kind        string
+		generation  int64
+		spec        map[string]interface{}
+		status      map[string]interface{}
+		expected    *RolloutStatus
+		hasError    bool
+	}{
+		{
+			description: "deployment rolled out",
+			kind:        "Deployment",
+			generation:  2,
+			spec:        map[string]interface{}{"replicas": 2},
+			status:      map[string]interface{}{"observedGeneration": 2, "replicas": 2, "updatedReplicas": 2, "readyReplicas": 2, "availableReplicas": 2},
+			expected:    &RolloutStatus{Desired: 2, Updated: 2, Ready: 2, Available: 2, Done: true},
+		},
+		{
+			description: "deployment with old replicas",
+			kind:        "Deployment",
+			generation:  2,
+			spec:        map[string]interface{}{"replicas": 2},
+			status:      map[string]interface{}{"observedGeneration": 2, "replicas": 3, "updatedReplicas": 2, "readyReplicas": 3, "availableReplicas": 3},
+			expected:    &RolloutStatus{Desired: 2, Updated: 2, Ready: 3, Available: 3, Old: 1},
+		},
+		{
+			description: "deployment generation not observed",
+			kind:        "Deployment",
+			generation:  3,
+			spec:        map[string]interface{}{"replicas": 1},
+			status:      map[string]interface{}{"observedGeneration": 2, "replicas": 1, "updatedReplicas": 1, "readyReplicas": 1, "availableReplicas": 1},
+			expected:    &RolloutStatus{Desired: 1, Updated: 1, Ready: 1, Available: 1},
+		},
+		{
+			description: "daemon set rolled out",
+			kind:        "DaemonSet",
+			generation:  1,
+			status:      map[string]interface{}{"observedGeneration": 1, "desiredNumberScheduled": 3, "updatedNumberScheduled": 3, "numberReady": 3, "numberAvailable": 3},
+			expected:    &RolloutStatus{Desired: 3, Updated: 3, Ready: 3, Available: 3, Done: true},
+		},
+		{
+			description: "unsupported kind",
+			kind:        "Pod",
+			hasError:    true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		info := &ResourceInfo{Spec: useCase.spec, Status: useCase.status}
+		info.Kind = useCase.kind
+		info.Generation = useCase.generation
+		actual, err := NewRolloutStatus(info)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if assert.Nil(t, err, useCase.description) {
+			assert.EqualValues(t, useCase.expected, actual, useCase.description)
+		}
+	}
+}
+
+func TestWaitForRolloutRequest_Init(t *testing.T) {
+	request := &WaitForRolloutRequest{Name: "statefulset/db"}
+	assert.Nil(t, request.Init())
+	assert.Nil(t, request.Validate())
+	assert.Equal(t, "statefulset", request.Kind)
+	assert.Equal(t, "db", request.Name)
+	assert.Equal(t, defaultWaitTimeoutMs, request.TimeoutMs)
+
+	request = &WaitForRolloutRequest{Name: "app"}
+	assert.Nil(t, request.Init())
+	assert.Equal(t, "Deployment", request.Kind)
+
+	assertRequest := &AssertRequest{Name: "deployment/app"}
+	assert.Nil(t, assertRequest.Init())
+	assert.NotNil(t, assertRequest.Validate())
+	assertRequest.Expect = map[string]interface{}{"spec": map[string]interface{}{"replicas": 2}}
+	assert.Nil(t, assertRequest.Validate())
+	assertRequest = &AssertRequest{ListOptions: metav1.ListOptions{LabelSelector: "app=web"}, Expect: []interface{}{}}
+	assert.NotNil(t, assertRequest.Validate())
+}
+
+func TestNormalizeManifest(t *testing.T) {
+	var replicas int32 = 2
+	actual, err := normalizeManifest(map[string]interface{}{"name": "app", "spec": map[string]interface{}{"replicas": &replicas}})
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, map[string]interface{}{"name": "app", "spec": map[string]interface{}{"replicas": 2.0}}, actual)
+	}
+}
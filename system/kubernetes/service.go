@@ -608,6 +608,52 @@ func (s *service) registerRoutes() {
 			return nil, fmt.Errorf("unsupported request type: %T", request)
 		},
 	})
+
+	s.Register(&endly.Route{
+		Action: "waitForRollout",
+		RequestInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T.%v(%T)", s, "waitForRollout", &WaitForRolloutRequest{}),
+		},
+		ResponseInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T", &WaitForRolloutResponse{}),
+		},
+		RequestProvider: func() interface{} {
+			return &WaitForRolloutRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &WaitForRolloutResponse{}
+		},
+		OnRawRequest: shared.Init,
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*WaitForRolloutRequest); ok {
+				return s.WaitForRollout(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "assert",
+		RequestInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T.%v(%T)", s, "assert", &AssertRequest{}),
+		},
+		ResponseInfo: &endly.ActionInfo{
+			Description: fmt.Sprintf("%T", &AssertResponse{}),
+		},
+		RequestProvider: func() interface{} {
+			return &AssertRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &AssertResponse{}
+		},
+		OnRawRequest: shared.Init,
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*AssertRequest); ok {
+				return s.Assert(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
 }
 
 func (s *service) getPod(context *endly.Context, request *GetRequest, timeoutMs int) (*v1.Pod, error) {
@@ -1,8 +1,18 @@
 package process
 
 import (
+	"errors"
 	"github.com/viant/endly/system/exec"
 	"github.com/viant/toolbox/url"
+	"regexp"
+)
+
+const (
+	defaultWaitTimeoutMs     = 30000
+	defaultWaitIntervalMs    = 250
+	defaultWaitMaxIntervalMs = 5000
+	defaultDiagnosticLines   = 20
+	defaultCheckTimeoutMs    = 2000
 )
 
 //StartRequest represents a start request
@@ -12,8 +22,9 @@ type StartRequest struct {
 	*exec.Options
 	Arguments       []string
 	AsSuperUser     bool
-	ImmuneToHangups bool `description:"start process as nohup"`
-	Watch           bool `description:"watch command output, work with nohup mode"`
+	ImmuneToHangups bool            `description:"start process as nohup"`
+	Watch           bool            `description:"watch command output, work with nohup mode"`
+	WaitFor         *WaitForRequest `description:"optional readiness check run after the process was started"`
 }
 
 //NewStartRequestFromURL creates a new request from URL
@@ -29,6 +40,7 @@ type StartResponse struct {
 	Info    []*Info
 	Pid     int
 	Stdout  string
+	WaitFor *WaitForResponse `json:",omitempty"`
 }
 
 //StatusRequest represents a status check request
@@ -68,6 +80,68 @@ type StopResponse struct {
 
 func (r *StartRequest) Init() error {
 	r.Target = exec.GetServiceTarget(r.Target)
+	if r.WaitFor != nil {
+		return r.WaitFor.Init()
+	}
+	return nil
+}
+
+//WaitForRequest represents a request to wait until a process is ready, all specified checks have to pass
+type WaitForRequest struct {
+	TCP             string        `description:"host:port accepting TCP connection when ready"`
+	HTTP            string        `description:"health check URL returning expected status when ready"`
+	ExpectStatus    int           `description:"expected health check HTTP status, any 2xx or 3xx by default"`
+	Log             *url.Resource `description:"log location matched with pattern"`
+	Pattern         string        `description:"regular expression matching log content when ready"`
+	TimeoutMs       int           `description:"max wait time, default 30000"`
+	IntervalMs      int           `description:"initial poll interval, doubled after each attempt up to maxIntervalMs, default 250"`
+	MaxIntervalMs   int           `description:"max poll interval, default 5000"`
+	Diagnostics     *url.Resource `description:"process output location reported when process is not ready, log location by default"`
+	DiagnosticLines int           `description:"number of last output lines reported when process is not ready, default 20"`
+	expr            *regexp.Regexp
+}
+
+//WaitForResponse represents a wait for response
+type WaitForResponse struct {
+	Attempts  int
+	ElapsedMs int
+	Matched   string `description:"log fragment matched by pattern"`
+}
+
+//Init initialises request
+func (r *WaitForRequest) Init() (err error) {
+	if r.TimeoutMs == 0 {
+		r.TimeoutMs = defaultWaitTimeoutMs
+	}
+	if r.IntervalMs == 0 {
+		r.IntervalMs = defaultWaitIntervalMs
+	}
+	if r.MaxIntervalMs == 0 {
+		r.MaxIntervalMs = defaultWaitMaxIntervalMs
+	}
+	if r.MaxIntervalMs < r.IntervalMs {
+		r.MaxIntervalMs = r.IntervalMs
+	}
+	if r.DiagnosticLines == 0 {
+		r.DiagnosticLines = defaultDiagnosticLines
+	}
+	if r.Diagnostics == nil {
+		r.Diagnostics = r.Log
+	}
+	if r.Pattern != "" {
+		r.expr, err = regexp.Compile(r.Pattern)
+	}
+	return err
+}
+
+//Validate checks if request is valid
+func (r *WaitForRequest) Validate() error {
+	if r.TCP == "" && r.HTTP == "" && r.Pattern == "" {
+		return errors.New("tcp, http and pattern were empty")
+	}
+	if r.Pattern != "" && (r.Log == nil || r.Log.URL == "") {
+		return errors.New("log was empty")
+	}
 	return nil
 }
 
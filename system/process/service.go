@@ -8,6 +8,7 @@ import (
 	"github.com/viant/endly/system/exec"
 	"github.com/viant/endly/util"
 	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
 	"io/ioutil"
 	"os"
 	"path"
@@ -200,6 +201,17 @@ func (s *service) startProcess(context *endly.Context, request *StartRequest) (*
 	}
 	response.Info = status.Processes
 	response.Pid = status.Pid
+	if request.WaitFor != nil {
+		if request.WaitFor.Diagnostics == nil && request.ImmuneToHangups {
+			request.WaitFor.Diagnostics = url.NewResource(outputFile)
+		}
+		if err = request.WaitFor.Validate(); err != nil {
+			return nil, err
+		}
+		if response.WaitFor, err = s.waitFor(context, request.WaitFor); err != nil {
+			return nil, err
+		}
+	}
 
 	if request.ImmuneToHangups {
 		stdout, err := s.readOutput(outputFile)
@@ -216,7 +228,7 @@ func (s *service) startProcess(context *endly.Context, request *StartRequest) (*
 }
 
 func (s *service) watchOutput(context *endly.Context, location string, position int) {
-	for !context.IsClosed() {
+	for {
 		stdout, err := s.readOutput(location)
 		if err != nil {
 			return
@@ -226,7 +238,11 @@ func (s *service) watchOutput(context *endly.Context, location string, position
 			context.Publish(msg.NewStdoutEvent("nohoup", output))
 			position = len(stdout)
 		}
-		time.Sleep(time.Second)
+		select {
+		case <-context.Done():
+			return
+		case <-time.After(time.Second):
+		}
 	}
 }
 
@@ -300,6 +316,25 @@ func (s *service) registerRoutes() {
 			return nil, fmt.Errorf("unsupported request type: %T", request)
 		},
 	})
+
+	s.Register(&endly.Route{
+		Action: "waitFor",
+		RequestInfo: &endly.ActionInfo{
+			Description: "wait until process is ready: TCP port accepts connection, HTTP health check returns expected status or log matches pattern",
+		},
+		RequestProvider: func() interface{} {
+			return &WaitForRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &WaitForResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*WaitForRequest); ok {
+				return s.waitFor(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
 }
 
 //New creates new system process service.
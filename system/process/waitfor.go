@@ -0,0 +1,115 @@
+package process
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	estorage "github.com/viant/endly/system/storage"
+	"github.com/viant/toolbox/url"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//readResource returns resource content
+func readResource(context *endly.Context, resource *url.Resource) (string, error) {
+	fs, err := estorage.StorageService(context, resource)
+	if err != nil {
+		return "", err
+	}
+	resource, storageOptions, err := estorage.GetResourceWithOptions(context, resource)
+	if err != nil {
+		return "", err
+	}
+	data, err := fs.DownloadWithURL(context.Background(), resource.URL, storageOptions...)
+	return string(data), err
+}
+
+//lastLines returns up to max last lines of supplied text
+func lastLines(text string, max int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > max {
+		lines = lines[len(lines)-max:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+//checkReadiness runs all request checks, it returns matched log fragment or an error describing the first failed check
+func (s *service) checkReadiness(context *endly.Context, request *WaitForRequest) (string, error) {
+	timeout := time.Duration(defaultCheckTimeoutMs) * time.Millisecond
+	if request.TCP != "" {
+		connection, err := net.DialTimeout("tcp", context.Expand(request.TCP), timeout)
+		if err != nil {
+			return "", err
+		}
+		_ = connection.Close()
+	}
+	if request.HTTP != "" {
+		client := &http.Client{Timeout: timeout}
+		response, err := client.Get(context.Expand(request.HTTP))
+		if err != nil {
+			return "", err
+		}
+		_ = response.Body.Close()
+		if request.ExpectStatus > 0 && response.StatusCode != request.ExpectStatus {
+			return "", fmt.Errorf("expected status %v, but had %v", request.ExpectStatus, response.StatusCode)
+		}
+		if request.ExpectStatus == 0 && (response.StatusCode < 200 || response.StatusCode >= 400) {
+			return "", fmt.Errorf("unexpected status %v", response.StatusCode)
+		}
+	}
+	if request.expr == nil {
+		return "", nil
+	}
+	content, err := readResource(context, request.Log)
+	if err != nil {
+		return "", err
+	}
+	matched := request.expr.FindString(content)
+	if matched == "" {
+		return "", fmt.Errorf("pattern %v was not matched in %v", request.Pattern, request.Log.URL)
+	}
+	return matched, nil
+}
+
+//waitFor polls request checks with backoff until all pass or timeout is reached
+func (s *service) waitFor(context *endly.Context, request *WaitForRequest) (*WaitForResponse, error) {
+	var response = &WaitForResponse{}
+	started := time.Now()
+	deadline := started.Add(time.Duration(request.TimeoutMs) * time.Millisecond)
+	interval := time.Duration(request.IntervalMs) * time.Millisecond
+	maxInterval := time.Duration(request.MaxIntervalMs) * time.Millisecond
+	for {
+		response.Attempts++
+		matched, err := s.checkReadiness(context, request)
+		response.ElapsedMs = int(time.Since(started) / time.Millisecond)
+		if err == nil {
+			response.Matched = matched
+			return response, nil
+		}
+		if !time.Now().Add(interval).Before(deadline) {
+			return response, s.notReadyError(context, request, response, err)
+		}
+		select {
+		case <-context.Done():
+			return response, s.notReadyError(context, request, response, err)
+		case <-time.After(interval):
+		}
+		if interval *= 2; interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+//notReadyError returns readiness error with last process output lines
+func (s *service) notReadyError(context *endly.Context, request *WaitForRequest, response *WaitForResponse, cause error) error {
+	err := fmt.Errorf("process was not ready after %v attempts (%v ms): %v", response.Attempts, response.ElapsedMs, cause)
+	if request.Diagnostics == nil || request.Diagnostics.URL == "" {
+		return err
+	}
+	output, readErr := readResource(context, request.Diagnostics)
+	if readErr != nil || strings.TrimSpace(output) == "" {
+		return err
+	}
+	return fmt.Errorf("%v\nlast %v output lines:\n%v", err, request.DiagnosticLines, lastLines(output, request.DiagnosticLines))
+}
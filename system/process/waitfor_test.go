@@ -0,0 +1,79 @@
+package process_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/process"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProcessService_WaitFor(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer listener.Close()
+	var healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if atomic.LoadInt32(&healthy) == 0 {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	logDir := path.Join(os.TempDir(), "test", "endly", "process")
+	_ = os.MkdirAll(logDir, 0755)
+	logFile := path.Join(logDir, "app.log")
+	_ = ioutil.WriteFile(logFile, []byte("starting\nloading config\n"), 0644)
+	defer os.Remove(logFile)
+
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		atomic.StoreInt32(&healthy, 1)
+		_ = ioutil.WriteFile(logFile, []byte("starting\nloading config\nserver started on port 8080\n"), 0644)
+	}()
+	response := &process.WaitForResponse{}
+	err = endly.Run(context, &process.WaitForRequest{
+		TCP:        listener.Addr().String(),
+		HTTP:       server.URL + "/health",
+		Log:        url.NewResource(logFile),
+		Pattern:    `started on port (\d+)`,
+		IntervalMs: 50,
+		TimeoutMs:  5000,
+	}, response)
+	if assert.Nil(t, err) {
+		assert.True(t, response.Attempts > 1)
+		assert.Equal(t, "started on port 8080", response.Matched)
+	}
+
+	atomic.StoreInt32(&healthy, 0)
+	err = endly.Run(context, &process.WaitForRequest{
+		HTTP:            server.URL + "/health",
+		Diagnostics:     url.NewResource(logFile),
+		DiagnosticLines: 2,
+		IntervalMs:      50,
+		TimeoutMs:       300,
+	}, response)
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "unexpected status 503"), err.Error())
+		assert.True(t, strings.Contains(err.Error(), "loading config\nserver started on port 8080"), err.Error())
+		assert.False(t, strings.Contains(err.Error(), "starting\n"), err.Error())
+	}
+
+	err = endly.Run(context, &process.WaitForRequest{IntervalMs: 50}, response)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,142 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const defaultVaultAddress = "http://127.0.0.1:8200"
+
+const (
+	vaultKVVersion1 = "1"
+	vaultKVVersion2 = "2"
+)
+
+//vaultProvider resolves secrets with HashiCorp Vault HTTP API, VAULT_ADDR and VAULT_TOKEN env variables are used to connect, i.e. vault://secret/db
+type vaultProvider struct {
+	client *http.Client
+}
+
+//vaultMount represents secret engine mount
+type vaultMount struct {
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	Options struct {
+		Version string `json:"version"`
+	} `json:"options"`
+}
+
+func (p *vaultProvider) Scheme() string {
+	return "vault"
+}
+
+//get returns Vault API response body for supplied path
+func (p *vaultProvider) get(ctx context.Context, address, token, path string) ([]byte, int, error) {
+	request, err := http.NewRequest(http.MethodGet, toolbox.URLPathJoin(strings.TrimRight(address, "/"), "v1/"+strings.TrimLeft(path, "/")), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("X-Vault-Token", token)
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+	content, err := ioutil.ReadAll(response.Body)
+	return content, response.StatusCode, err
+}
+
+//mount returns secret mount path and KV engine version, version set with kv option is used as is with the first path segment as mount,
+//otherwise mount is detected with sys/internal/ui/mounts API, version 1 is assumed if mount can not be detected
+func (p *vaultProvider) mount(ctx context.Context, address, token, key string, options url.Values) (string, string) {
+	var version = strings.TrimPrefix(strings.ToLower(options.Get("kv")), "v")
+	if _, ok := options["kv2"]; ok {
+		version = vaultKVVersion2
+	}
+	if version != "" {
+		return strings.SplitN(key, "/", 2)[0] + "/", version
+	}
+	content, status, err := p.get(ctx, address, token, "sys/internal/ui/mounts/"+key)
+	if err != nil || status != http.StatusOK {
+		return "", vaultKVVersion1
+	}
+	var response = struct {
+		Data *vaultMount `json:"data"`
+	}{}
+	if err = json.Unmarshal(content, &response); err != nil || response.Data == nil || response.Data.Type != "kv" {
+		return "", vaultKVVersion1
+	}
+	if response.Data.Options.Version == vaultKVVersion2 {
+		return response.Data.Path, vaultKVVersion2
+	}
+	return response.Data.Path, vaultKVVersion1
+}
+
+//kvPath returns secret API path, KV version 2 secrets are read from <mount>/data/<path>
+func kvPath(key, mountPath, version string) string {
+	if version != vaultKVVersion2 || mountPath == "" || !strings.HasPrefix(key, mountPath) {
+		return key
+	}
+	if secretPath := strings.TrimPrefix(key, mountPath); !strings.HasPrefix(secretPath, "data/") {
+		return mountPath + "data/" + secretPath
+	}
+	return key
+}
+
+//Secret returns secret data as JSON, or data value if secret has only value key, KV version can be set with kv option i.e. vault://secret/db?kv=2
+func (p *vaultProvider) Secret(ctx context.Context, key string) (string, error) {
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return "", fmt.Errorf("VAULT_TOKEN env variable was not defined")
+	}
+	address := os.Getenv("VAULT_ADDR")
+	if address == "" {
+		address = defaultVaultAddress
+	}
+	var options = url.Values{}
+	if index := strings.Index(key, "?"); index != -1 {
+		var err error
+		if options, err = url.ParseQuery(key[index+1:]); err != nil {
+			return "", fmt.Errorf("invalid vault secret options: %v, %v", key, err)
+		}
+		key = key[:index]
+	}
+	key = strings.Trim(key, "/")
+	mountPath, version := p.mount(ctx, address, token, key, options)
+	content, status, err := p.get(ctx, address, token, kvPath(key, mountPath, version))
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("vault returned %v: %s", status, content)
+	}
+	var secret = struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err = json.Unmarshal(content, &secret); err != nil {
+		return "", err
+	}
+	var data = secret.Data
+	//KV version 2 secret engine nests secret under data.data
+	if nested, ok := data["data"].(map[string]interface{}); ok && (version == vaultKVVersion2 || data["metadata"] != nil) {
+		data = nested
+	}
+	if value, ok := data["value"]; ok && len(data) == 1 {
+		return toolbox.AsString(value), nil
+	}
+	encoded, err := json.Marshal(data)
+	return string(encoded), err
+}
+
+func init() {
+	endly.RegisterSecretProvider(&vaultProvider{client: http.DefaultClient})
+}
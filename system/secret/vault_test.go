@@ -0,0 +1,71 @@
+package secret
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestVaultProvider_Secret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("X-Vault-Token") != "test-token" {
+			writer.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch URI := request.URL.Path; {
+		case strings.HasPrefix(URI, "/v1/sys/internal/ui/mounts/secret/"):
+			_, _ = writer.Write([]byte(`{"data":{"path":"secret/","type":"kv","options":{"version":"2"}}}`))
+		case strings.HasPrefix(URI, "/v1/sys/internal/ui/mounts/kv/"):
+			_, _ = writer.Write([]byte(`{"data":{"path":"kv/","type":"kv","options":{"version":"1"}}}`))
+		case strings.HasPrefix(URI, "/v1/sys/internal/ui/mounts/"):
+			writer.WriteHeader(http.StatusForbidden)
+		case URI == "/v1/secret/data/db":
+			_, _ = writer.Write([]byte(`{"data":{"data":{"Username":"bob","Password":"pass"},"metadata":{"version":1}}}`))
+		case URI == "/v1/secret/data/token", URI == "/v1/restricted/data/token":
+			_, _ = writer.Write([]byte(`{"request_id":"1","data":{"data":{"value":"t0k3n"},"metadata":{"created_time":"2020-01-01T00:00:00Z","version":3}}}`))
+		case URI == "/v1/kv/token":
+			_, _ = writer.Write([]byte(`{"data":{"value":"abc123"}}`))
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	_ = os.Setenv("VAULT_ADDR", server.URL)
+	defer os.Unsetenv("VAULT_ADDR")
+	provider := &vaultProvider{client: http.DefaultClient}
+
+	var useCases = []struct {
+		description string
+		token       string
+		key         string
+		expect      string
+		hasError    bool
+	}{
+		{description: "kv v2 secret", token: "test-token", key: "secret/db", expect: `{"Password":"pass","Username":"bob"}`},
+		{description: "kv v2 secret data path", token: "test-token", key: "secret/data/db", expect: `{"Password":"pass","Username":"bob"}`},
+		{description: "kv v2 value secret", token: "test-token", key: "secret/token", expect: "t0k3n"},
+		{description: "kv v2 option", token: "test-token", key: "restricted/token?kv=2", expect: "t0k3n"},
+		{description: "kv2 option", token: "test-token", key: "restricted/token?kv2", expect: "t0k3n"},
+		{description: "undetected kv version", token: "test-token", key: "restricted/token", hasError: true},
+		{description: "kv v1 value secret", token: "test-token", key: "kv/token", expect: "abc123"},
+		{description: "missing secret", token: "test-token", key: "kv/missing", hasError: true},
+		{description: "invalid token", token: "invalid", key: "kv/token", hasError: true},
+		{description: "missing token", key: "kv/token", hasError: true},
+	}
+	for _, useCase := range useCases {
+		_ = os.Setenv("VAULT_TOKEN", useCase.token)
+		value, err := provider.Secret(context.Background(), useCase.key)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if assert.Nil(t, err, useCase.description) {
+			assert.Equal(t, useCase.expect, value, useCase.description)
+		}
+	}
+	_ = os.Unsetenv("VAULT_TOKEN")
+}
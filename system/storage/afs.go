@@ -1,7 +1,6 @@
 package storage
 
 import (
-	"context"
 	"github.com/viant/afs"
 	"github.com/viant/afs/file"
 	"github.com/viant/afs/option"
@@ -26,7 +25,7 @@ var scheduledClosed = uint32(0)
 
 //StorageService return afs storage service
 func StorageService(ctx *endly.Context, resources ...*url.Resource) (afs.Service, error) {
-	var state = ctx.State()
+	var state = ctx.SafeState()
 	if state.Has(useMemoryService) {
 		return fsFaker, nil
 	}
@@ -40,7 +39,7 @@ func StorageService(ctx *endly.Context, resources ...*url.Resource) (afs.Service
 		if err != nil {
 			return nil, err
 		}
-		if err = fs.Init(context.Background(), resource.URL, options...); err != nil {
+		if err = fs.Init(ctx.Background(), resource.URL, options...); err != nil {
 			return nil, err
 		}
 	}
@@ -66,9 +65,9 @@ func StorageOptions(ctx *endly.Context, resource *url.Resource, options ...stora
 		result = append(result, customKey)
 	}
 
-	if resource.Credentials != "" {
+	if !endly.IsAmbientCredentials(resource.Credentials) {
 
-		credConfig, err := ctx.Secrets.GetCredentials(resource.Credentials)
+		credConfig, err := ctx.Credentials(resource.Credentials)
 		if err != nil {
 			return nil, err
 		}
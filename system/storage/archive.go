@@ -0,0 +1,345 @@
+package storage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/storage"
+	arl "github.com/viant/afs/url"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/storage/copy"
+	"github.com/viant/toolbox/url"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	//ArchiveZip represents zip archive format
+	ArchiveZip = "zip"
+	//ArchiveTar represents tar archive format
+	ArchiveTar = "tar"
+	//ArchiveTarGz represents gzip compressed tar archive format
+	ArchiveTarGz = "tar.gz"
+)
+
+//archiveFormat returns archive format for supplied URL extension
+func archiveFormat(URL string) string {
+	URL = strings.ToLower(URL)
+	switch {
+	case strings.HasSuffix(URL, ".tar.gz"), strings.HasSuffix(URL, ".tgz"):
+		return ArchiveTarGz
+	case strings.HasSuffix(URL, ".tar"):
+		return ArchiveTar
+	case strings.HasSuffix(URL, ".zip"), strings.HasSuffix(URL, ".jar"), strings.HasSuffix(URL, ".war"):
+		return ArchiveZip
+	}
+	return ""
+}
+
+func validateArchiveFormat(format string) error {
+	switch format {
+	case ArchiveZip, ArchiveTar, ArchiveTarGz:
+		return nil
+	case "":
+		return errors.New("format was empty and could not be derived from archive URL")
+	}
+	return fmt.Errorf("unsupported archive format: %v, supported: %v, %v, %v", format, ArchiveZip, ArchiveTar, ArchiveTarGz)
+}
+
+//PackRequest represents a request to pack a directory or file into an archive
+type PackRequest struct {
+	Source  *url.Resource `required:"true" description:"source directory or file"`
+	Dest    *url.Resource `required:"true" description:"destination archive URL"`
+	Format  string        `description:"archive format: zip, tar or tar.gz, if empty it derives from destination URL extension"`
+	Matcher *copy.Matcher `description:"optional source assets matcher"`
+}
+
+//PackResponse represents a pack response
+type PackResponse struct {
+	URL    string   //archive URL
+	Assets []string //archived asset names
+}
+
+//UnpackRequest represents a request to expand an archive into a destination directory
+type UnpackRequest struct {
+	Source *url.Resource `required:"true" description:"source archive URL"`
+	Dest   *url.Resource `required:"true" description:"destination directory"`
+	Format string        `description:"archive format: zip, tar or tar.gz, if empty it derives from source URL extension"`
+}
+
+//UnpackResponse represents an unpack response
+type UnpackResponse struct {
+	URLs []string //extracted asset URLs
+}
+
+//Init initialises request
+func (r *PackRequest) Init() error {
+	if r.Format == "" && r.Dest != nil {
+		r.Format = archiveFormat(r.Dest.URL)
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *PackRequest) Validate() error {
+	if r.Source == nil || r.Source.URL == "" {
+		return errors.New("source was empty")
+	}
+	if r.Dest == nil || r.Dest.URL == "" {
+		return errors.New("dest was empty")
+	}
+	return validateArchiveFormat(r.Format)
+}
+
+//Init initialises request
+func (r *UnpackRequest) Init() error {
+	if r.Format == "" && r.Source != nil {
+		r.Format = archiveFormat(r.Source.URL)
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *UnpackRequest) Validate() error {
+	if r.Source == nil || r.Source.URL == "" {
+		return errors.New("source was empty")
+	}
+	if r.Dest == nil || r.Dest.URL == "" {
+		return errors.New("dest was empty")
+	}
+	return validateArchiveFormat(r.Format)
+}
+
+//archiveWriter represents archive format agnostic writer
+type archiveWriter struct {
+	zip   *zip.Writer
+	tar   *tar.Writer
+	gzip  *gzip.Writer
+	names []string
+}
+
+func newArchiveWriter(format string, writer io.Writer) *archiveWriter {
+	var result = &archiveWriter{names: make([]string, 0)}
+	switch format {
+	case ArchiveZip:
+		result.zip = zip.NewWriter(writer)
+	case ArchiveTarGz:
+		result.gzip = gzip.NewWriter(writer)
+		result.tar = tar.NewWriter(result.gzip)
+	default:
+		result.tar = tar.NewWriter(writer)
+	}
+	return result
+}
+
+func (w *archiveWriter) add(name string, info os.FileInfo, reader io.Reader) error {
+	w.names = append(w.names, name)
+	if w.zip != nil {
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		header.Method = zip.Deflate
+		writer, err := w.zip.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(writer, reader)
+		return err
+	}
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{Name: name, Mode: int64(info.Mode().Perm()), Size: int64(len(content)), ModTime: info.ModTime(), Typeflag: tar.TypeReg}
+	if err = w.tar.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = w.tar.Write(content)
+	return err
+}
+
+func (w *archiveWriter) Close() error {
+	if w.zip != nil {
+		return w.zip.Close()
+	}
+	if err := w.tar.Close(); err != nil {
+		return err
+	}
+	if w.gzip != nil {
+		return w.gzip.Close()
+	}
+	return nil
+}
+
+//Pack packs source directory or file into destination archive
+func (s *service) Pack(context *endly.Context, request *PackRequest) (*PackResponse, error) {
+	var response = &PackResponse{}
+	return response, s.pack(context, request, response)
+}
+
+func (s *service) pack(context *endly.Context, request *PackRequest, response *PackResponse) error {
+	var sourceOptions = make([]storage.Option, 0)
+	if request.Matcher != nil {
+		matcher, err := request.Matcher.Matcher()
+		if err != nil {
+			return err
+		}
+		sourceOptions = append(sourceOptions, matcher)
+	}
+	source, sourceOptions, err := GetResourceWithOptions(context, request.Source, sourceOptions...)
+	if err != nil {
+		return err
+	}
+	dest, destOptions, err := GetResourceWithOptions(context, request.Dest)
+	if err != nil {
+		return err
+	}
+	fs, err := StorageService(context, source, dest)
+	if err != nil {
+		return err
+	}
+	object, err := fs.Object(context.Background(), source.URL, sourceOptions...)
+	if err != nil {
+		return fmt.Errorf("%v: source not found, %v", source.URL, err)
+	}
+	reader, writer := io.Pipe()
+	archive := newArchiveWriter(request.Format, writer)
+	go func() {
+		err := writeArchive(context.Background(), fs, archive, source, object, sourceOptions)
+		if closeErr := archive.Close(); err == nil {
+			err = closeErr
+		}
+		_ = writer.CloseWithError(err)
+	}()
+	if err = fs.Upload(context.Background(), dest.URL, 0644, reader, destOptions...); err != nil {
+		_ = reader.CloseWithError(err)
+		return fmt.Errorf("failed to pack %v into %v, %v", source.URL, dest.URL, err)
+	}
+	response.URL = dest.URL
+	response.Assets = archive.names
+	return nil
+}
+
+//writeArchive writes source file or directory files into archive, names are relative to the source directory
+func writeArchive(ctx context.Context, fs afs.Service, archive *archiveWriter, source *url.Resource, object storage.Object, options []storage.Option) error {
+	if !object.IsDir() {
+		reader, err := fs.OpenURL(ctx, object.URL(), options...)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		return archive.add(object.Name(), object, reader)
+	}
+	return fs.Walk(ctx, source.URL, func(ctx context.Context, baseURL string, parent string, info os.FileInfo, reader io.Reader) (bool, error) {
+		if info.IsDir() || reader == nil {
+			return true, nil
+		}
+		err := archive.add(path.Join(parent, info.Name()), info, reader)
+		return err == nil, err
+	}, options...)
+}
+
+//Unpack expands source archive into destination directory
+func (s *service) Unpack(context *endly.Context, request *UnpackRequest) (*UnpackResponse, error) {
+	var response = &UnpackResponse{URLs: make([]string, 0)}
+	return response, s.unpack(context, request, response)
+}
+
+func (s *service) unpack(context *endly.Context, request *UnpackRequest, response *UnpackResponse) error {
+	source, sourceOptions, err := GetResourceWithOptions(context, request.Source)
+	if err != nil {
+		return err
+	}
+	dest, destOptions, err := GetResourceWithOptions(context, request.Dest)
+	if err != nil {
+		return err
+	}
+	fs, err := StorageService(context, source, dest)
+	if err != nil {
+		return err
+	}
+	reader, err := fs.OpenURL(context.Background(), source.URL, sourceOptions...)
+	if err != nil {
+		return fmt.Errorf("%v: archive not found, %v", source.URL, err)
+	}
+	defer reader.Close()
+	var upload = func(name string, mode os.FileMode, reader io.Reader) error {
+		name = path.Clean(strings.TrimLeft(name, "/"))
+		if name == "." || strings.HasPrefix(name, "..") {
+			return fmt.Errorf("invalid archive entry: %v", name)
+		}
+		URL := arl.Join(dest.URL, name)
+		if err := fs.Upload(context.Background(), URL, mode.Perm()|0400, reader, destOptions...); err != nil {
+			return err
+		}
+		response.URLs = append(response.URLs, URL)
+		return nil
+	}
+	if request.Format == ArchiveZip {
+		return unpackZip(reader, upload)
+	}
+	return unpackTar(request.Format, reader, upload)
+}
+
+func unpackZip(reader io.Reader, upload func(name string, mode os.FileMode, reader io.Reader) error) error {
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return err
+	}
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		entry, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = upload(file.Name, file.Mode(), entry)
+		_ = entry.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unpackTar(format string, reader io.Reader, upload func(name string, mode os.FileMode, reader io.Reader) error) error {
+	if format == ArchiveTarGz {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		if err = upload(header.Name, os.FileMode(header.Mode), archive); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs/asset"
+	"github.com/viant/afs/mem"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestService_PackUnpack(t *testing.T) {
+	baseURL := "mem://localhost/data/storage/archive"
+	mgr := mem.Singleton()
+	var files = []*asset.Resource{
+		asset.NewFile("app", []byte("binary"), 0755),
+		asset.NewFile("config/app.json", []byte(`{"port":8080}`), 0644),
+	}
+	err := asset.Create(mgr, baseURL+"/src", files)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	for _, format := range []string{"app.zip", "app.tar", "app.tar.gz"} {
+		packResponse := &PackResponse{}
+		err = endly.Run(nil, &PackRequest{
+			Source: url.NewResource(baseURL + "/src"),
+			Dest:   url.NewResource(baseURL + "/build/" + format),
+		}, packResponse)
+		if !assert.Nil(t, err, format) {
+			continue
+		}
+		sort.Strings(packResponse.Assets)
+		assert.EqualValues(t, []string{"app", "config/app.json"}, packResponse.Assets, format)
+
+		destURL := baseURL + "/deploy/" + strings.Replace(format, ".", "_", -1)
+		unpackResponse := &UnpackResponse{}
+		err = endly.Run(nil, &UnpackRequest{
+			Source: url.NewResource(baseURL + "/build/" + format),
+			Dest:   url.NewResource(destURL),
+		}, unpackResponse)
+		if !assert.Nil(t, err, format) {
+			continue
+		}
+		assert.Equal(t, 2, len(unpackResponse.URLs), format)
+		assets, err := asset.Load(mgr, destURL)
+		if !assert.Nil(t, err, format) {
+			continue
+		}
+		for _, expect := range files {
+			if actual, ok := assets[expect.Name]; assert.True(t, ok, format+" "+expect.Name) {
+				assert.EqualValues(t, string(expect.Data), string(actual.Data), format+" "+expect.Name)
+			}
+		}
+	}
+
+	err = endly.Run(nil, &PackRequest{
+		Source: url.NewResource(baseURL + "/src"),
+		Dest:   url.NewResource(baseURL + "/build/app.rar"),
+	}, &PackResponse{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/option"
+	"github.com/viant/afs/storage"
+	aurl "github.com/viant/afs/url"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/storage/copy"
+	"github.com/viant/toolbox/url"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+//checksumAsset represents source file with expected destination checksum
+type checksumAsset struct {
+	sourceURL string
+	destURL   string
+	size      int64
+	checksum  string
+}
+
+func newHash(algorithm string) hash.Hash {
+	if algorithm == copy.ChecksumSHA256 {
+		return sha256.New()
+	}
+	return md5.New()
+}
+
+func computeChecksum(algorithm string, reader io.Reader) (string, error) {
+	var hasher = newHash(algorithm)
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+//sourceChecksum returns source content checksum, content is modified first if destination modifier is used
+func sourceChecksum(algorithm string, parent string, info os.FileInfo, reader io.Reader, modifier option.Modifier) (string, error) {
+	if modifier != nil {
+		var err error
+		var modified io.ReadCloser
+		if _, modified, err = modifier(parent, info, ioutil.NopCloser(reader)); err != nil {
+			return "", err
+		}
+		defer modified.Close()
+		reader = modified
+	}
+	return computeChecksum(algorithm, reader)
+}
+
+//destChecksum returns destination file checksum or empty string if file does not exist
+func destChecksum(ctx context.Context, fs afs.Service, algorithm, URL string, options []storage.Option) (string, error) {
+	if exists, _ := fs.Exists(ctx, URL, options...); !exists {
+		return "", nil
+	}
+	reader, err := fs.OpenURL(ctx, URL, options...)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	return computeChecksum(algorithm, reader)
+}
+
+//destFileURL returns destination file URL for a file source, it mirrors afs copy destination resolution
+func destFileURL(sourceURL, destURL string) string {
+	_, sourceName := path.Split(aurl.Path(sourceURL))
+	baseURL, destPath := aurl.Base(destURL, file.Scheme)
+	_, destName := path.Split(destPath)
+	if destName == sourceName || path.Ext(destName) == path.Ext(sourceName) {
+		return destURL
+	}
+	if sourceExt := path.Ext(sourceName); sourceExt != "" && len(sourceExt) <= 5 && !strings.Contains(destName, sourceExt) {
+		return aurl.Join(baseURL, path.Join(destPath, sourceName))
+	}
+	return destURL
+}
+
+//checksumAssets returns source files with their expected destination checksum
+func checksumAssets(ctx context.Context, fs afs.Service, rule *copy.Rule, source, dest *url.Resource, object storage.Object, sourceOptions []storage.Option, modifier option.Modifier) ([]*checksumAsset, error) {
+	var result = make([]*checksumAsset, 0)
+	if !object.IsDir() {
+		reader, err := fs.OpenURL(ctx, object.URL(), sourceOptions...)
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		checksum, err := sourceChecksum(rule.Checksum, "", object, reader, modifier)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &checksumAsset{sourceURL: object.URL(), destURL: destFileURL(source.URL, dest.URL), size: object.Size(), checksum: checksum})
+		return result, nil
+	}
+	err := fs.Walk(ctx, source.URL, func(ctx context.Context, baseURL string, parent string, info os.FileInfo, reader io.Reader) (bool, error) {
+		if info.IsDir() || reader == nil {
+			return true, nil
+		}
+		checksum, err := sourceChecksum(rule.Checksum, parent, info, reader, modifier)
+		if err != nil {
+			return false, err
+		}
+		result = append(result, &checksumAsset{
+			sourceURL: aurl.Join(baseURL, path.Join(parent, info.Name())),
+			destURL:   aurl.Join(dest.URL, path.Join(parent, info.Name())),
+			size:      info.Size(),
+			checksum:  checksum,
+		})
+		return true, nil
+	}, sourceOptions...)
+	return result, err
+}
+
+//checksumTransfer copies only files which destination checksum differs from the source one, copied files are verified
+func (s *service) checksumTransfer(context *endly.Context, fs afs.Service, rule *copy.Rule, source, dest *url.Resource, object storage.Object, sourceOpts *option.Source, destOpts *option.Dest, response *CopyResponse) error {
+	var modifier option.Modifier
+	option.Assign([]storage.Option(*destOpts), &modifier)
+	assets, err := checksumAssets(context.Background(), fs, rule, source, dest, object, []storage.Option(*sourceOpts), modifier)
+	if err != nil {
+		return fmt.Errorf("failed to compute %v checksum: %v, %v", rule.Checksum, source.URL, err)
+	}
+	destOptions, err := StorageOptions(context, dest)
+	if err != nil {
+		return err
+	}
+	var mismatches = make([]string, 0)
+	copyOpts, counted := countedDestOptions(context.Heartbeat(), source, dest, destOpts)
+	for _, asset := range assets {
+		checksum, err := destChecksum(context.Background(), fs, rule.Checksum, asset.destURL, destOptions)
+		if err != nil {
+			return err
+		}
+		response.Checksums[asset.destURL] = asset.checksum
+		if checksum == asset.checksum {
+			response.Skipped = append(response.Skipped, asset.destURL)
+			continue
+		}
+		if err = fs.Copy(context.Background(), asset.sourceURL, asset.destURL, sourceOpts, copyOpts); err != nil {
+			return err
+		}
+		if !counted {
+			context.Heartbeat().AddBytes(asset.size)
+		}
+		response.URLs = append(response.URLs, asset.sourceURL)
+		if checksum, err = destChecksum(context.Background(), fs, rule.Checksum, asset.destURL, destOptions); err != nil {
+			return err
+		}
+		if checksum != asset.checksum {
+			mismatches = append(mismatches, fmt.Sprintf("%v: expected %v, but had %v", asset.destURL, asset.checksum, checksum))
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%v checksum verification failed for %v file(s):\n%v", rule.Checksum, len(mismatches), strings.Join(mismatches, "\n"))
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs/asset"
+	"github.com/viant/afs/mem"
+	"github.com/viant/afs/option"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/storage/copy"
+	"github.com/viant/toolbox/data"
+	"github.com/viant/toolbox/url"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestService_ChecksumCopy(t *testing.T) {
+	var runs = 0
+	endly.RegisterUdf("ChecksumCorruption", func(source interface{}, state data.Map) (interface{}, error) {
+		return option.Modifier(func(parent string, info os.FileInfo, reader io.ReadCloser) (os.FileInfo, io.ReadCloser, error) {
+			runs++
+			return info, ioutil.NopCloser(strings.NewReader(fmt.Sprintf("corrupted %v", runs))), nil
+		}), nil
+	})
+	defer delete(endly.UdfRegistry, "ChecksumCorruption")
+
+	baseURL := "mem://localhost/data/storage/checksum"
+	mgr := mem.Singleton()
+	err := asset.Create(mgr, baseURL+"/src", []*asset.Resource{
+		asset.NewFile("f1", []byte("test1"), 0644),
+		asset.NewFile("sub/f2.txt", []byte("test2"), 0644),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var newRequest = func(checksum, udf string) *CopyRequest {
+		var request = &CopyRequest{
+			Rule: &copy.Rule{
+				Source:   url.NewResource(baseURL + "/src"),
+				Dest:     url.NewResource(baseURL + "/dst"),
+				Checksum: checksum,
+			},
+			Udf: udf,
+		}
+		assert.Nil(t, request.Init())
+		return request
+	}
+
+	response := &CopyResponse{}
+	err = endly.Run(nil, newRequest(copy.ChecksumMD5, ""), response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, len(response.URLs))
+	assert.Equal(t, 0, len(response.Skipped))
+	assert.Equal(t, "5a105e8b9d40e1329780d62ea2265d8a", response.Checksums[baseURL+"/dst/f1"])
+
+	err = asset.Modify(mgr, baseURL+"/src", []*asset.Resource{
+		asset.NewFile("f1", []byte("test1 updated"), 0644),
+	})
+	assert.Nil(t, err)
+	response = &CopyResponse{}
+	err = endly.Run(nil, newRequest(copy.ChecksumSHA256, ""), response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, []string{baseURL + "/src/f1"}, response.URLs)
+	assert.EqualValues(t, []string{baseURL + "/dst/sub/f2.txt"}, response.Skipped)
+	assets, err := asset.Load(mgr, baseURL+"/dst")
+	if assert.Nil(t, err) && assert.NotNil(t, assets["f1"]) {
+		assert.EqualValues(t, "test1 updated", string(assets["f1"].Data))
+	}
+
+	err = endly.Run(nil, newRequest(copy.ChecksumMD5, "ChecksumCorruption"), &CopyResponse{})
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "md5 checksum verification failed for 2 file(s)"), err.Error())
+		assert.True(t, strings.Contains(err.Error(), baseURL+"/dst/f1: expected"), err.Error())
+	}
+
+	err = endly.Run(nil, newRequest("crc", ""), &CopyResponse{})
+	assert.NotNil(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/viant/afs/option"
+	"github.com/viant/afs/storage"
 	"github.com/viant/endly"
 	"github.com/viant/endly/system/storage/copy"
 	"github.com/viant/endly/udf"
@@ -12,25 +13,43 @@ import (
 
 //CopyRequest represents a resources Copy request
 type CopyRequest struct {
-	*copy.Rule `description:"if asset uses relative path it will be joined with this URL" json:",inline"`
-	Assets     copy.Assets  `description:"map entry can either represent a transfer struct or simple key is the source and the value destination relative path"` // transfers
-	Transfers  []*copy.Rule `description:"actual transfer assets, if empty it derives from assets or source/desc "`
-	Udf        string       `description:"custom user defined function to return github.com/viant/afs/option.Modifier type to modify copied content"`
+	*copy.Rule  `description:"if asset uses relative path it will be joined with this URL" json:",inline"`
+	Assets      copy.Assets  `description:"map entry can either represent a transfer struct or simple key is the source and the value destination relative path"` // transfers
+	Transfers   []*copy.Rule `description:"actual transfer assets, if empty it derives from assets or source/desc "`
+	Udf         string       `description:"custom user defined function to return github.com/viant/afs/option.Modifier type to modify copied content"`
+	Concurrency int          `description:"max number of assets copied concurrently, large files are also read in concurrent parts if source supports ranged reads (local file, HTTP)"`
+	PartSizeMb  int          `description:"part size in MB for concurrent ranged reads, default 8"`
 }
 
 //CopyResponse represents a resources Copy response
 type CopyResponse struct {
-	URLs []string //transferred URLs
+	URLs      []string          //transferred URLs
+	Skipped   []string          `json:",omitempty"` //unchanged destination URLs skipped with checksum rule
+	Checksums map[string]string `json:",omitempty"` //destination URL checksums with checksum rule
 }
 
 //Copy copy source to dest
 func (s *service) Copy(context *endly.Context, request *CopyRequest) (*CopyResponse, error) {
-	var response = &CopyResponse{
-		URLs: make([]string, 0),
-	}
+	var response = newCopyResponse()
 	return response, s.copy(context, request, response)
 }
 
+func newCopyResponse() *CopyResponse {
+	return &CopyResponse{
+		URLs:      make([]string, 0),
+		Skipped:   make([]string, 0),
+		Checksums: make(map[string]string),
+	}
+}
+
+func (r *CopyResponse) merge(response *CopyResponse) {
+	r.URLs = append(r.URLs, response.URLs...)
+	r.Skipped = append(r.Skipped, response.Skipped...)
+	for k, v := range response.Checksums {
+		r.Checksums[k] = v
+	}
+}
+
 func (s *service) copy(context *endly.Context, request *CopyRequest, response *CopyResponse) error {
 	var udfModifier option.Modifier
 	if request.Udf != "" {
@@ -44,50 +63,107 @@ func (s *service) copy(context *endly.Context, request *CopyRequest, response *C
 			return fmt.Errorf("udf %v does not implement %T", UDF, udfModifier)
 		}
 	}
-	for _, rule := range request.Transfers {
-		if err := s.transfer(context, rule, udfModifier, response); err != nil {
-			return err
+	parallel := newParallelTransfer(request.Concurrency, request.PartSizeMb)
+	if parallel == nil {
+		for _, rule := range request.Transfers {
+			if err := s.transfer(context, rule, udfModifier, nil, response); err != nil {
+				return err
+			}
 		}
+		return nil
 	}
-	return nil
+	var responses = make([]*CopyResponse, len(request.Transfers))
+	var tasks = make([]transferTask, len(request.Transfers))
+	var err error
+	for i, rule := range request.Transfers {
+		responses[i] = newCopyResponse()
+		if tasks[i], err = s.prepareTransfer(context, rule, udfModifier, parallel, responses[i]); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = runConcurrently(parallel.concurrency, len(tasks), func(i int) error {
+			if tasks[i] == nil {
+				return nil
+			}
+			return tasks[i](responses[i])
+		})
+	}
+	for _, transferResponse := range responses {
+		if transferResponse != nil {
+			response.merge(transferResponse)
+		}
+	}
+	return err
+}
+
+//transferTask represents asset transfer which does not access context state, thus can run concurrently
+type transferTask func(response *CopyResponse) error
+
+func (s *service) transfer(context *endly.Context, rule *copy.Rule, udfModifier option.Modifier, parallel *parallelTransfer, response *CopyResponse) error {
+	task, err := s.prepareTransfer(context, rule, udfModifier, parallel, response)
+	if err != nil || task == nil {
+		return err
+	}
+	return task(response)
 }
 
-func (s *service) transfer(context *endly.Context, rule *copy.Rule, udfModifier option.Modifier, response *CopyResponse) error {
+//prepareTransfer resolves transfer resources, checksum verified or compressed transfer runs right away, otherwise it returns transfer task
+func (s *service) prepareTransfer(context *endly.Context, rule *copy.Rule, udfModifier option.Modifier, parallel *parallelTransfer, response *CopyResponse) (transferTask, error) {
 	source, sourceOpts, err := getSourceWithOptions(context, rule)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	dest, destOpts, err := getDestWithOptions(context, rule, udfModifier)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	fs, err := StorageService(context, source, dest)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	useCompression := rule.Compress && IsCompressable(source.ParsedURL.Scheme) && IsCompressable(dest.ParsedURL.Scheme)
 	object, err := fs.Object(context.Background(), source.URL)
 	if err != nil {
-		return errors.Wrapf(err, "%v: source not found", source.URL)
+		return nil, errors.Wrapf(err, "%v: source not found", source.URL)
 	}
-	if useCompression {
-		err = s.compressSource(context, source, dest, object)
-		if err != nil {
-			return err
-		}
-	}
-	err = fs.Copy(context.Background(), source.URL, dest.URL, sourceOpts, destOpts)
-	if err != nil {
-		return err
+	if rule.Checksum != "" {
+		return nil, s.checksumTransfer(context, fs, rule, source, dest, object, sourceOpts, destOpts, response)
 	}
+	copyOpts, counted := countedDestOptions(context.Heartbeat(), source, dest, destOpts)
 	if useCompression {
-		err = s.decompressTarget(context, source, dest, object)
+		if err = s.compressSource(context, source, dest, object); err != nil {
+			return nil, err
+		}
+		if err = fs.Copy(context.Background(), source.URL, dest.URL, sourceOpts, copyOpts); err != nil {
+			return nil, err
+		}
+		if err = s.decompressTarget(context, source, dest, object); err != nil {
+			return nil, err
+		}
+		response.URLs = append(response.URLs, object.URL())
+		return nil, nil
+	}
+	hasModifier := udfModifier != nil || rule.Expand || len(rule.Replace) > 0
+	var rangeRead rangeReader
+	if !hasModifier && parallel.canTransfer(object) {
+		rangeRead = sourceRangeReader(source)
+	}
+	return func(response *CopyResponse) error {
+		if rangeRead != nil {
+			err = parallel.transfer(context, fs, rangeRead, source, dest, object, []storage.Option(*destOpts))
+		} else {
+			err = fs.Copy(context.Background(), source.URL, dest.URL, sourceOpts, copyOpts)
+		}
 		if err != nil {
 			return err
 		}
-	}
-	response.URLs = append(response.URLs, object.URL())
-	return nil
+		if !counted {
+			context.Heartbeat().AddBytes(object.Size())
+		}
+		response.URLs = append(response.URLs, object.URL())
+		return nil
+	}, nil
 }
 
 //CopyRequest creates a new Copy request
@@ -28,6 +28,7 @@ func (t Assets) AsTransfer(base *Rule) []*Rule {
 			Dest:         url.NewResource(dest),
 			Substitution: base.Substitution,
 			Compress:     base.Compress,
+			Checksum:     base.Checksum,
 		}
 		if sourceBase != nil {
 			transfer.Source = JoinIfNeeded(sourceBase, source)
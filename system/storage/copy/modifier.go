@@ -10,11 +10,18 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
 )
 
 var maxExpandableContentSize = int64(1024 * 128)
 
+const (
+	regExprPrefix = "~/"
+	regExprSuffix = "/"
+)
+
 //NewModifier return a new reader that can substitute content with state map, replacement data provided in replacement map.
 func NewModifier(context *endly.Context, when *Matcher, replaceMap map[string]string, expand bool) (option.Modifier, error) {
 
@@ -22,6 +29,10 @@ func NewModifier(context *endly.Context, when *Matcher, replaceMap map[string]st
 	if err != nil {
 		return nil, err
 	}
+	replacements, err := newReplacements(replaceMap)
+	if err != nil {
+		return nil, err
+	}
 	return func(parent string, info os.FileInfo, reader io.ReadCloser) (os.FileInfo, io.ReadCloser, error) {
 		if reader == nil {
 			return nil, nil, fmt.Errorf("reader was empty")
@@ -43,7 +54,7 @@ func NewModifier(context *endly.Context, when *Matcher, replaceMap map[string]st
 			isUpdated = result != string(content)
 		}
 
-		if replaced, substituted := substituteWithMap(result, replaceMap); replaced {
+		if replaced, substituted := substitute(result, replacements); replaced {
 			result = substituted
 			isUpdated = replaced
 		}
@@ -70,17 +81,54 @@ func substitutionMatcher(matcher *Matcher) (result option.Match, err error) {
 	}, err
 }
 
-func substituteWithMap(text string, replaceMap map[string]string) (bool, string) {
+//replacement represents content replacement, either literal or regular expression with capture group references in the value, i.e. ${1}
+type replacement struct {
+	literal string
+	expr    *regexp.Regexp
+	value   string
+}
+
+//newReplacements returns replacements for supplied map, key in ~/expression/ form is used as regular expression
+func newReplacements(replaceMap map[string]string) ([]*replacement, error) {
+	var keys = make([]string, 0, len(replaceMap))
+	for key := range replaceMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var result = make([]*replacement, 0, len(keys))
+	for _, key := range keys {
+		var item = &replacement{literal: key, value: replaceMap[key]}
+		if len(key) > len(regExprPrefix) && strings.HasPrefix(key, regExprPrefix) && strings.HasSuffix(key, regExprSuffix) {
+			expr, err := regexp.Compile(key[len(regExprPrefix) : len(key)-len(regExprSuffix)])
+			if err != nil {
+				return nil, fmt.Errorf("invalid replacement expression: %v, %v", key, err)
+			}
+			item.expr = expr
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func substitute(text string, replacements []*replacement) (bool, string) {
 	isUpdated := false
-	for k, v := range replaceMap {
-		count := strings.Count(text, k)
+	for _, item := range replacements {
+		if item.expr != nil {
+			if !item.expr.MatchString(text) {
+				continue
+			}
+			isUpdated = true
+			text = item.expr.ReplaceAllString(text, item.value)
+			continue
+		}
+		count := strings.Count(text, item.literal)
 		if count == 0 {
 			continue
 		}
 		if !isUpdated {
 			isUpdated = true
 		}
-		text = strings.Replace(text, k, v, count)
+		text = strings.Replace(text, item.literal, item.value, count)
 	}
 	return isUpdated, text
 }
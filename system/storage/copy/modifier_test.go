@@ -150,6 +150,31 @@ func TestNewModifier(t *testing.T) {
 			text:   "foo is great",
 			expect: "bar is great",
 		},
+		{
+			description: "regexp replace with capture group",
+			replacement: map[string]string{
+				`~/version=(\d+)/`: "version=${1}-SNAPSHOT",
+				"name":             "app",
+			},
+			text:   "name\nversion=12\nversion=x",
+			expect: "app\nversion=12-SNAPSHOT\nversion=x",
+		},
+		{
+			description: "regexp key with literal form",
+			replacement: map[string]string{
+				"~/": "home/",
+			},
+			text:   "~/data",
+			expect: "home/data",
+		},
+		{
+			description: "error invalid replacement expression",
+			replacement: map[string]string{
+				"~/version=(\\d+/": "version",
+			},
+			text:        "version=1",
+			expectError: true,
+		},
 		{
 			description: "error invalid after expression",
 			replacement: map[string]string{
@@ -2,6 +2,7 @@ package copy
 
 import (
 	"errors"
+	"fmt"
 	"github.com/viant/afs/option"
 	"github.com/viant/afs/storage"
 	"github.com/viant/endly"
@@ -9,10 +10,18 @@ import (
 	"strings"
 )
 
+const (
+	//ChecksumMD5 represents md5 checksum algorithm
+	ChecksumMD5 = "md5"
+	//ChecksumSHA256 represents sha256 checksum algorithm
+	ChecksumSHA256 = "sha256"
+)
+
 //Rule represents transfer rule
 type Rule struct {
 	Matcher  *Matcher
 	Compress bool `description:"flag to compress asset before sending over wire and to decompress (this option is only supported on scp or file scheme)"` //flag to compress asset before sending over wirte and to decompress (this option is only supported on scp or file proto)
+	Checksum string `description:"checksum algorithm: md5 or sha256, if specified unchanged destination files are skipped and copied files are verified"`
 	Substitution
 	Source *url.Resource `required:"true" description:"source asset or directory"`
 	Dest   *url.Resource `required:"true" description:"destination asset or directory"`
@@ -36,6 +45,7 @@ func (r Rule) Clone() *Rule {
 		Source:   r.Source,
 		Dest:     r.Dest,
 		Compress: r.Compress,
+		Checksum: r.Checksum,
 		Matcher:  r.Matcher,
 		Substitution: Substitution{
 			Expand:   r.Expand,
@@ -106,5 +116,8 @@ func (r *Rule) Validate() error {
 	if r.Dest.URL == "" {
 		return errors.New("dest.URL was empty")
 	}
+	if r.Checksum != "" && r.Checksum != ChecksumMD5 && r.Checksum != ChecksumSHA256 {
+		return fmt.Errorf("unsupported checksum: %v, supported: %v, %v", r.Checksum, ChecksumMD5, ChecksumSHA256)
+	}
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/viant/afs/file"
 	"github.com/viant/afs/storage"
 	"github.com/viant/endly"
+	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/url"
 	"io"
 	"os"
@@ -55,9 +56,9 @@ func (s *service) create(context *endly.Context, request *CreateRequest, respons
 func gerReaderOption(request *CreateRequest, context *endly.Context, response *CreateResponse) []storage.Option {
 	var options = make([]storage.Option, 0)
 	if !request.IsDir {
-		var state = context.State()
+		var state = context.SafeState()
 		if state.Has(request.SourceKey) {
-			data := state.GetString(request.SourceKey)
+			data := toolbox.AsString(state.Get(request.SourceKey))
 			options = append(options, io.Reader(strings.NewReader(data)))
 			response.Size = len(data)
 		}
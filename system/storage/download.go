@@ -2,21 +2,26 @@ package storage
 
 import (
 	"github.com/pkg/errors"
+	"github.com/viant/afs"
+	"github.com/viant/afs/storage"
 	"github.com/viant/endly"
 	"github.com/viant/endly/testing/validator"
 	"github.com/viant/endly/udf"
 	"github.com/viant/endly/util"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/url"
+	"io"
 	"io/ioutil"
 )
 
 //DownloadRequest represents a resources Download request, it downloads source into context.state target key
 type DownloadRequest struct {
-	Source  *url.Resource `required:"true" description:"source asset or directory"`
-	DestKey string        `required:"true" description:"state map key destination"`
-	Udf     string        `description:"name of udf to transform payload before placing into state map"` //name of udf function that will be used to transform payload
-	Expect  interface{}   `description:"if specified expected file content used for validation"`
+	Source      *url.Resource `required:"true" description:"source asset or directory"`
+	DestKey     string        `required:"true" description:"state map key destination"`
+	Udf         string        `description:"name of udf to transform payload before placing into state map"` //name of udf function that will be used to transform payload
+	Expect      interface{}   `description:"if specified expected file content used for validation"`
+	Concurrency int           `description:"number of concurrent ranged reads for large source if supported (local file, HTTP)"`
+	PartSizeMb  int           `description:"part size in MB for concurrent ranged reads, default 8"`
 }
 
 //DownloadResponse represents a Download response
@@ -41,7 +46,7 @@ func (s *service) download(context *endly.Context, request *DownloadRequest, res
 	if err != nil {
 		return err
 	}
-	reader, err := fs.OpenURL(context.Background(), source.URL, storageOpts...)
+	reader, err := s.openSource(context, fs, source, storageOpts, newParallelTransfer(request.Concurrency, request.PartSizeMb))
 	if err != nil {
 		return err
 	}
@@ -64,7 +69,7 @@ func (s *service) download(context *endly.Context, request *DownloadRequest, res
 		payload = response.Payload
 	}
 	if request.DestKey != "" {
-		var state = context.State()
+		var state = context.SafeState()
 		state.Put(request.DestKey, payload)
 	}
 	if request.Expect != nil {
@@ -73,6 +78,22 @@ func (s *service) download(context *endly.Context, request *DownloadRequest, res
 	return err
 }
 
+//openSource returns source reader, large source is read in concurrent parts if parallel transfer is used and source supports ranged reads
+func (s *service) openSource(context *endly.Context, fs afs.Service, source *url.Resource, options []storage.Option, parallel *parallelTransfer) (io.ReadCloser, error) {
+	if parallel != nil {
+		object, err := fs.Object(context.Background(), source.URL, options...)
+		if err != nil {
+			return nil, err
+		}
+		if parallel.canTransfer(object) {
+			if read := sourceRangeReader(source); read != nil {
+				return parallel.partsReader(read, object.Size()), nil
+			}
+		}
+	}
+	return fs.OpenURL(context.Background(), source.URL, options...)
+}
+
 //Validate checks if request is valid
 func (r *DownloadRequest) Validate() error {
 	if r.Source == nil {
@@ -114,12 +114,11 @@ func generateContent(context *endly.Context, request *GenerateRequest) io.Reader
 		}
 		return strings.NewReader(text)
 	}
-	state := context.State()
+	state := context.SafeState().Clone()
 	indexVariable := request.IndexVariable
 	if indexVariable == "" {
 		indexVariable = "i"
 	}
-	state = state.Clone()
 	items := make([]string, repeat)
 	for i := range items {
 		state.Put(indexVariable, request.Index)
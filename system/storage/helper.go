@@ -56,7 +56,7 @@ func GetResourceWithOptions(context *endly.Context, resource *url.Resource, opti
 
 //UseMemoryService sets flag on context to always use memory service (testing only)
 func UseMemoryService(context *endly.Context) afs.Service {
-	state := context.State()
+	state := context.SafeState()
 	state.Put(useMemoryService, true)
 	return fsFaker
 }
@@ -57,7 +57,7 @@ func (s *service) list(context *endly.Context, request *ListRequest, response *L
 }
 
 func listResource(ctx context.Context, URL string, storageOptions []storage.Option, request *ListRequest, response *ListResponse) error {
-	objects, err := fs.List(context.Background(), URL, storageOptions...)
+	objects, err := fs.List(ctx, URL, storageOptions...)
 	if err != nil {
 		return err
 	}
@@ -72,7 +72,7 @@ func listResource(ctx context.Context, URL string, storageOptions []storage.Opti
 			resource = asset.NewFile(object.URL(), nil, object.Mode())
 		}
 		if request.Content && !object.IsDir() {
-			reader, err := fs.Open(context.Background(), object)
+			reader, err := fs.Open(ctx, object)
 			if err != nil {
 				return errors.Wrapf(err, "failed to download listed content %v", object.URL())
 			}
@@ -97,7 +97,7 @@ func listResource(ctx context.Context, URL string, storageOptions []storage.Opti
 			if i == 0 {
 				continue
 			}
-			if err = listResource(context.Background(), object.URL(), storageOptions, request, response); err != nil {
+			if err = listResource(ctx, object.URL(), storageOptions, request, response); err != nil {
 				return err
 			}
 
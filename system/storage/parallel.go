@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/storage"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox/url"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+)
+
+const defaultPartSizeMb = 8
+
+//rangeReader reads length bytes of an asset starting from offset
+type rangeReader func(offset, length int64) ([]byte, error)
+
+//parallelTransfer represents large assets parallel parts transfer options
+type parallelTransfer struct {
+	concurrency int
+	partSize    int64
+}
+
+func newParallelTransfer(concurrency, partSizeMb int) *parallelTransfer {
+	if concurrency <= 1 {
+		return nil
+	}
+	if partSizeMb <= 0 {
+		partSizeMb = defaultPartSizeMb
+	}
+	return &parallelTransfer{concurrency: concurrency, partSize: int64(partSizeMb) * 1024 * 1024}
+}
+
+//fileRangeReader returns local file range reader
+func fileRangeReader(filename string) rangeReader {
+	return func(offset, length int64) ([]byte, error) {
+		file, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		var result = make([]byte, length)
+		_, err = file.ReadAt(result, offset)
+		return result, err
+	}
+}
+
+//httpRangeReader returns HTTP range reader, server has to respond with partial content
+func httpRangeReader(URL string) rangeReader {
+	return func(offset, length int64) ([]byte, error) {
+		request, err := http.NewRequest(http.MethodGet, URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusPartialContent {
+			return nil, fmt.Errorf("ranged read is not supported: %v, status: %v", URL, response.StatusCode)
+		}
+		result, err := ioutil.ReadAll(response.Body)
+		if err == nil && int64(len(result)) != length {
+			err = fmt.Errorf("range error, expected: %v, but had: %v", length, len(result))
+		}
+		return result, err
+	}
+}
+
+//sourceRangeReader returns range reader for backend supporting ranged reads: local file or HTTP with partial content, otherwise nil
+func sourceRangeReader(source *url.Resource) rangeReader {
+	switch source.ParsedURL.Scheme {
+	case "file":
+		return fileRangeReader(source.ParsedURL.Path)
+	case "http", "https":
+		if source.Credentials != "" {
+			return nil
+		}
+		request, err := http.NewRequest(http.MethodHead, source.URL, nil)
+		if err != nil {
+			return nil
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return nil
+		}
+		_ = response.Body.Close()
+		if response.Header.Get("Accept-Ranges") != "bytes" {
+			return nil
+		}
+		return httpRangeReader(source.URL)
+	}
+	return nil
+}
+
+//canTransfer returns true if source object is a file larger than a part size
+func (t *parallelTransfer) canTransfer(object storage.Object) bool {
+	return t != nil && !object.IsDir() && object.Size() > t.partSize
+}
+
+//partsReader returns reader fetching parts concurrently, parts are returned in order with at most concurrency parts in memory
+func (t *parallelTransfer) partsReader(read rangeReader, size int64) io.ReadCloser {
+	reader, writer := io.Pipe()
+	var partCount = int((size + t.partSize - 1) / t.partSize)
+	var parts = make([]chan []byte, partCount)
+	for i := range parts {
+		parts[i] = make(chan []byte, 1)
+	}
+	var errs = make(chan error, partCount)
+	var limiter = make(chan bool, t.concurrency)
+	var done = make(chan bool)
+	go func() {
+		for i := 0; i < partCount; i++ {
+			select {
+			case limiter <- true:
+			case <-done:
+				return
+			}
+			go func(i int) {
+				offset := int64(i) * t.partSize
+				length := t.partSize
+				if offset+length > size {
+					length = size - offset
+				}
+				data, err := read(offset, length)
+				if err != nil {
+					errs <- fmt.Errorf("failed to read part %v: %v", i, err)
+					return
+				}
+				parts[i] <- data
+			}(i)
+		}
+	}()
+	go func() {
+		defer close(done)
+		for i := 0; i < partCount; i++ {
+			select {
+			case data := <-parts[i]:
+				if _, err := writer.Write(data); err != nil {
+					return
+				}
+				<-limiter
+			case err := <-errs:
+				_ = writer.CloseWithError(err)
+				return
+			}
+		}
+		_ = writer.Close()
+	}()
+	return reader
+}
+
+//transfer uploads source file read in parallel parts into dest
+func (t *parallelTransfer) transfer(context *endly.Context, fs afs.Service, read rangeReader, source, dest *url.Resource, object storage.Object, destOptions []storage.Option) error {
+	reader := &countingReader{ReadCloser: t.partsReader(read, object.Size()), heartbeat: context.Heartbeat()}
+	defer reader.Close()
+	return fs.Upload(context.Background(), destFileURL(source.URL, dest.URL), object.Mode(), reader, destOptions...)
+}
+
+//runConcurrently runs tasks with a worker pool of supplied size, it returns the first error
+func runConcurrently(concurrency int, count int, task func(i int) error) error {
+	var waitGroup = &sync.WaitGroup{}
+	var mux = &sync.Mutex{}
+	var result error
+	var limiter = make(chan bool, concurrency)
+	for i := 0; i < count; i++ {
+		limiter <- true
+		waitGroup.Add(1)
+		go func(i int) {
+			defer func() {
+				<-limiter
+				waitGroup.Done()
+			}()
+			if err := task(i); err != nil {
+				mux.Lock()
+				if result == nil {
+					result = err
+				}
+				mux.Unlock()
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+	return result
+}
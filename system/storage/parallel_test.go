@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs/asset"
+	"github.com/viant/afs/mem"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/storage/copy"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_ParallelCopy(t *testing.T) {
+	var content = bytes.Repeat([]byte("0123456789abcdef"), 200*1024) //3.2MB
+	tempDir, err := ioutil.TempDir("", "parallel")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(tempDir)
+	for _, name := range []string{"large1.bin", "large2.bin"} {
+		if !assert.Nil(t, ioutil.WriteFile(path.Join(tempDir, name), content, 0644)) {
+			return
+		}
+	}
+	if !assert.Nil(t, ioutil.WriteFile(path.Join(tempDir, "small.txt"), []byte("small"), 0644)) {
+		return
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		http.ServeContent(writer, request, "large.bin", time.Now(), bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	baseURL := "mem://localhost/data/storage/parallel"
+	response := &CopyResponse{}
+	err = endly.Run(nil, &CopyRequest{
+		Transfers: []*copy.Rule{
+			{Source: url.NewResource(path.Join(tempDir, "large1.bin")), Dest: url.NewResource(baseURL + "/large1.bin")},
+			{Source: url.NewResource(path.Join(tempDir, "large2.bin")), Dest: url.NewResource(baseURL + "/large2.bin")},
+			{Source: url.NewResource(path.Join(tempDir, "small.txt")), Dest: url.NewResource(baseURL + "/small.txt")},
+			{Source: url.NewResource(server.URL + "/large.bin"), Dest: url.NewResource(baseURL + "/http.bin")},
+		},
+		Concurrency: 3,
+		PartSizeMb:  1,
+	}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 4, len(response.URLs))
+	assert.True(t, strings.HasSuffix(response.URLs[0], "large1.bin"))
+	assets, err := asset.Load(mem.Singleton(), baseURL)
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, name := range []string{"large1.bin", "large2.bin", "http.bin"} {
+		if assert.NotNil(t, assets[name], name) {
+			assert.True(t, bytes.Equal(content, assets[name].Data), name)
+		}
+	}
+	if assert.NotNil(t, assets["small.txt"]) {
+		assert.EqualValues(t, "small", string(assets["small.txt"].Data))
+	}
+
+	context := endly.New().NewContext(nil)
+	downloadResponse := &DownloadResponse{}
+	err = endly.Run(context, &DownloadRequest{Source: url.NewResource(server.URL + "/large.bin"), DestKey: "large", Concurrency: 4, PartSizeMb: 1}, downloadResponse)
+	if assert.Nil(t, err) {
+		assert.Equal(t, len(content), len(downloadResponse.Payload))
+		assert.EqualValues(t, string(content), downloadResponse.Payload)
+	}
+}
+
+func TestParallelTransfer_PartsReader(t *testing.T) {
+	var content = []byte("abcdefghijklmnopqrstuvwxyz")
+	transfer := &parallelTransfer{concurrency: 3, partSize: 4}
+	reader := transfer.partsReader(func(offset, length int64) ([]byte, error) {
+		return content[offset : offset+length], nil
+	}, int64(len(content)))
+	data, err := ioutil.ReadAll(reader)
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, string(content), string(data))
+	}
+	reader = transfer.partsReader(func(offset, length int64) ([]byte, error) {
+		if offset > 8 {
+			return nil, os.ErrInvalid
+		}
+		return content[offset : offset+length], nil
+	}, int64(len(content)))
+	_, err = ioutil.ReadAll(reader)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"github.com/viant/afs/option"
+	"github.com/viant/afs/storage"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox/url"
+	"io"
+	"os"
+)
+
+//countingReader reports read bytes to action heartbeat as they pass through
+type countingReader struct {
+	io.ReadCloser
+	heartbeat *endly.Heartbeat
+}
+
+func (r *countingReader) Read(data []byte) (int, error) {
+	n, err := r.ReadCloser.Read(data)
+	r.heartbeat.AddBytes(int64(n))
+	return n, err
+}
+
+//isStreamed returns true if copy streams content through endly, otherwise same scheme cloud storage copies server side
+func isStreamed(source, dest *url.Resource, options *option.Dest) bool {
+	var modifier option.Modifier
+	option.Assign([]storage.Option(*options), &modifier)
+	if modifier != nil || source.ParsedURL.Scheme != dest.ParsedURL.Scheme {
+		return true
+	}
+	switch source.ParsedURL.Scheme {
+	case "", "file", "mem", "scp", "ssh":
+		return true
+	}
+	return false
+}
+
+//countedDestOptions returns dest options with content modifier counting transferred bytes, it returns false if copy runs server side,
+//in that case transferred bytes have to be reported once copy is done
+func countedDestOptions(heartbeat *endly.Heartbeat, source, dest *url.Resource, options *option.Dest) (*option.Dest, bool) {
+	if !isStreamed(source, dest, options) {
+		return options, false
+	}
+	var modifier option.Modifier
+	var result = make(option.Dest, 0, len(*options)+1)
+	for _, candidate := range *options {
+		if _, ok := option.Assign([]storage.Option{candidate}, &modifier); ok {
+			continue
+		}
+		result = append(result, candidate)
+	}
+	result = append(result, option.Modifier(func(parent string, info os.FileInfo, reader io.ReadCloser) (os.FileInfo, io.ReadCloser, error) {
+		reader = &countingReader{ReadCloser: reader, heartbeat: heartbeat}
+		if modifier == nil {
+			return info, reader, nil
+		}
+		return modifier(parent, info, reader)
+	}))
+	return &result, true
+}
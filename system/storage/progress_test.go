@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+	"github.com/viant/afs/option"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox/url"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestCountedDestOptions(t *testing.T) {
+	var content = bytes.Repeat([]byte("0123456789"), 100*1024)
+	tempDir, err := ioutil.TempDir("", "progress")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(tempDir)
+	if !assert.Nil(t, ioutil.WriteFile(path.Join(tempDir, "source.bin"), content, 0644)) {
+		return
+	}
+	source := url.NewResource(path.Join(tempDir, "source.bin"))
+	dest := url.NewResource(path.Join(tempDir, "dest.bin"))
+
+	var transferred int64
+	var upper = option.Modifier(func(parent string, info os.FileInfo, reader io.ReadCloser) (os.FileInfo, io.ReadCloser, error) {
+		data, err := ioutil.ReadAll(reader)
+		return info, ioutil.NopCloser(strings.NewReader(strings.ToUpper(string(data)))), err
+	})
+	for _, modifier := range []option.Modifier{nil, upper} {
+		heartbeat := &endly.Heartbeat{}
+		var destOpts = option.NewDest()
+		if modifier != nil {
+			destOpts = option.NewDest(modifier)
+		}
+		copyOpts, counted := countedDestOptions(heartbeat, source, dest, destOpts)
+		assert.True(t, counted)
+		if !assert.Nil(t, afs.New().Copy(context.Background(), source.URL, dest.URL, option.NewSource(), copyOpts)) {
+			return
+		}
+		transferred = heartbeat.Event().Bytes
+		assert.EqualValues(t, len(content), transferred)
+	}
+	copied, err := ioutil.ReadFile(path.Join(tempDir, "dest.bin"))
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ToUpper(string(content)), string(copied))
+
+	_, counted := countedDestOptions(nil, url.NewResource("s3://bucket/a.bin"), url.NewResource("s3://bucket/b.bin"), option.NewDest())
+	assert.False(t, counted)
+}
@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	as3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/viant/afsc/auth"
+	"github.com/viant/afsc/s3"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/exec"
+	"github.com/viant/toolbox/url"
+	"golang.org/x/oauth2/google"
+	goption "google.golang.org/api/option"
+	gstorage "google.golang.org/api/storage/v1"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const defaultS3Region = "us-east-1"
+
+//NewRangeReader returns function reading length bytes of resource content starting from offset,
+//file, http(s), s3, gs and scp/ssh resources support ranged reads, for other resources nil is returned
+func NewRangeReader(context *endly.Context, resource *url.Resource) (func(offset, length int64) ([]byte, error), error) {
+	var read rangeReader
+	var err error
+	switch resource.ParsedURL.Scheme {
+	case s3.Scheme:
+		read, err = s3RangeReader(context, resource)
+	case "gs":
+		read, err = gsRangeReader(context, resource)
+	case "scp", sshScheme:
+		read, err = sshRangeReader(context, resource)
+	default:
+		read = sourceRangeReader(resource)
+	}
+	if err != nil || read == nil {
+		return nil, err
+	}
+	return read, nil
+}
+
+//readRange reads expected range length from reader
+func readRange(reader io.Reader, length int64) ([]byte, error) {
+	result, err := ioutil.ReadAll(io.LimitReader(reader, length))
+	if err == nil && int64(len(result)) != length {
+		err = fmt.Errorf("range error, expected: %v, but had: %v", length, len(result))
+	}
+	return result, err
+}
+
+func rangeHeader(offset, length int64) string {
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
+func bucketObject(resource *url.Resource) (string, string) {
+	return resource.ParsedURL.Host, strings.TrimLeft(resource.ParsedURL.Path, "/")
+}
+
+//s3RangeReader returns S3 object range reader using GetObject Range requests
+func s3RangeReader(context *endly.Context, resource *url.Resource) (rangeReader, error) {
+	var config = &aws.Config{}
+	if !endly.IsAmbientCredentials(resource.Credentials) {
+		credConfig, err := context.Credentials(resource.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		authConfig, err := s3.NewAuthConfig([]byte(credConfig.Data))
+		if err != nil {
+			return nil, err
+		}
+		if config, err = authConfig.AwsConfig(); err != nil {
+			return nil, err
+		}
+		if config.Region == nil && credConfig.Region != "" {
+			config.Region = aws.String(credConfig.Region)
+		}
+	}
+	bucket, key := bucketObject(resource)
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.Region == nil || *config.Region == "" {
+		region, err := s3manager.GetBucketRegion(context.Background(), sess, bucket, defaultS3Region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %v bucket region: %v", bucket, err)
+		}
+		sess = sess.Copy(&aws.Config{Region: aws.String(region)})
+	}
+	client := as3.New(sess)
+	return func(offset, length int64) ([]byte, error) {
+		output, err := client.GetObject(&as3.GetObjectInput{Bucket: &bucket, Key: &key, Range: aws.String(rangeHeader(offset, length))})
+		if err != nil {
+			return nil, err
+		}
+		defer output.Body.Close()
+		return readRange(output.Body, length)
+	}, nil
+}
+
+//gsRangeReader returns google storage object range reader using media download Range requests
+func gsRangeReader(context *endly.Context, resource *url.Resource) (rangeReader, error) {
+	ctx := context.Background()
+	var client *http.Client
+	if endly.IsAmbientCredentials(resource.Credentials) {
+		var err error
+		if client, err = google.DefaultClient(ctx, gstorage.DevstorageReadOnlyScope); err != nil {
+			return nil, err
+		}
+	} else {
+		credConfig, err := context.Credentials(resource.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		jwtConfig, err := auth.NewJwtConfig([]byte(credConfig.Data))
+		if err != nil {
+			return nil, err
+		}
+		config, _, err := jwtConfig.JWTConfig(gstorage.DevstorageReadOnlyScope)
+		if err != nil {
+			return nil, err
+		}
+		client = config.Client(ctx)
+	}
+	service, err := gstorage.NewService(ctx, goption.WithHTTPClient(client))
+	if err != nil {
+		return nil, err
+	}
+	bucket, object := bucketObject(resource)
+	return func(offset, length int64) ([]byte, error) {
+		call := service.Objects.Get(bucket, object)
+		call.Header().Set("Range", rangeHeader(offset, length))
+		response, err := call.Download()
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusPartialContent {
+			return nil, fmt.Errorf("ranged read is not supported: %v, status: %v", resource.URL, response.StatusCode)
+		}
+		return readRange(response.Body, length)
+	}, nil
+}
+
+//sshRangeReader returns remote file range reader running tail and head over context terminal session SSH connection
+func sshRangeReader(context *endly.Context, resource *url.Resource) (rangeReader, error) {
+	terminal, err := exec.TerminalSession(context, resource)
+	if err != nil {
+		return nil, err
+	}
+	client := terminal.Service.Client()
+	if client == nil {
+		return nil, nil
+	}
+	filename := "'" + strings.Replace(resource.ParsedURL.Path, "'", `'\''`, -1) + "'"
+	return func(offset, length int64) ([]byte, error) {
+		sshSession, err := client.NewSession()
+		if err != nil {
+			return nil, err
+		}
+		defer sshSession.Close()
+		output, err := sshSession.Output(fmt.Sprintf("tail -c +%d %v | head -c %d", offset+1, filename, length))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %v range: %v", resource.URL, err)
+		}
+		if int64(len(output)) != length {
+			return nil, fmt.Errorf("range error, expected: %v, but had: %v", length, len(output))
+		}
+		return output, nil
+	}, nil
+}
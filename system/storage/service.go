@@ -39,6 +39,63 @@ func (s *service) registerRoutes() {
 		},
 	})
 
+	s.Register(&endly.Route{
+		Action: "pack",
+		RequestInfo: &endly.ActionInfo{
+			Description: "pack directory or file into zip, tar or tar.gz archive at destination URL",
+		},
+		RequestProvider: func() interface{} {
+			return &PackRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &PackResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*PackRequest); ok {
+				return s.Pack(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "unpack",
+		RequestInfo: &endly.ActionInfo{
+			Description: "expand zip, tar or tar.gz archive into destination directory",
+		},
+		RequestProvider: func() interface{} {
+			return &UnpackRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &UnpackResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*UnpackRequest); ok {
+				return s.Unpack(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "sync",
+		RequestInfo: &endly.ActionInfo{
+			Description: "mirror source tree to destination, only new or changed files are transferred, optionally files missing in source are deleted",
+		},
+		RequestProvider: func() interface{} {
+			return &SyncRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &SyncResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*SyncRequest); ok {
+				return s.Sync(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
 	s.Register(&endly.Route{
 		Action: "remove",
 		RequestInfo: &endly.ActionInfo{
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/viant/afs"
+	"github.com/viant/afs/option"
+	"github.com/viant/afs/storage"
+	arl "github.com/viant/afs/url"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/storage/copy"
+	"github.com/viant/toolbox/url"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+//SyncRequest represents a request to mirror source tree to destination
+type SyncRequest struct {
+	Source   *url.Resource `required:"true" description:"source directory"`
+	Dest     *url.Resource `required:"true" description:"destination directory"`
+	Include  []string      `description:"glob patterns of relative path, file or directory name to sync, all if empty, i.e. *.go, config"`
+	Exclude  []string      `description:"glob patterns of relative path, file or directory name to skip, excluded destination files are never deleted"`
+	Checksum string        `description:"checksum algorithm: md5 or sha256 to detect changed files, otherwise size and modification time are compared"`
+	Delete   bool          `description:"flag to delete destination files missing in source"`
+	DryRun   bool          `description:"flag to only report changes without transferring or deleting anything"`
+}
+
+//SyncResponse represents a sync response
+type SyncResponse struct {
+	Transferred []string //transferred relative paths
+	Deleted     []string //deleted relative paths
+	Unchanged   int      //unchanged files count
+}
+
+//syncAsset represents sync tree file
+type syncAsset struct {
+	info     os.FileInfo
+	checksum string
+}
+
+//Init initialises request
+func (r *SyncRequest) Init() error {
+	r.Checksum = strings.ToLower(r.Checksum)
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *SyncRequest) Validate() error {
+	if r.Source == nil || r.Source.URL == "" {
+		return errors.New("source was empty")
+	}
+	if r.Dest == nil || r.Dest.URL == "" {
+		return errors.New("dest was empty")
+	}
+	if r.Checksum != "" && r.Checksum != copy.ChecksumMD5 && r.Checksum != copy.ChecksumSHA256 {
+		return fmt.Errorf("unsupported checksum: %v, supported: %v, %v", r.Checksum, copy.ChecksumMD5, copy.ChecksumSHA256)
+	}
+	for _, pattern := range append(append([]string{}, r.Include...), r.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern: %v, %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+//matchesAny returns true if any pattern matches relative path, its file name or any of its parent directories
+func matchesAny(patterns []string, relative string) bool {
+	var candidates = []string{relative, path.Base(relative)}
+	for dir := path.Dir(relative); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		candidates = append(candidates, dir, path.Base(dir))
+	}
+	for _, pattern := range patterns {
+		for _, candidate := range candidates {
+			if matched, _ := path.Match(pattern, candidate); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//isSelected returns true if relative path is included and not excluded
+func (r *SyncRequest) isSelected(relative string) bool {
+	if len(r.Include) > 0 && !matchesAny(r.Include, relative) {
+		return false
+	}
+	return !matchesAny(r.Exclude, relative)
+}
+
+//isChanged returns true if destination file differs from source
+func (r *SyncRequest) isChanged(source, dest *syncAsset) bool {
+	if r.Checksum != "" {
+		return source.checksum != dest.checksum
+	}
+	return source.info.Size() != dest.info.Size() || source.info.ModTime().After(dest.info.ModTime())
+}
+
+//syncAssets returns selected tree files keyed by relative path, missing location has no files
+func syncAssets(ctx context.Context, fs afs.Service, request *SyncRequest, URL string, options []storage.Option) (map[string]*syncAsset, error) {
+	var result = make(map[string]*syncAsset)
+	if exists, _ := fs.Exists(ctx, URL, options...); !exists {
+		return result, nil
+	}
+	err := fs.Walk(ctx, URL, func(ctx context.Context, baseURL string, parent string, info os.FileInfo, reader io.Reader) (bool, error) {
+		relative := path.Join(parent, info.Name())
+		if info.IsDir() || reader == nil || !request.isSelected(relative) {
+			return true, nil
+		}
+		var asset = &syncAsset{info: info}
+		if request.Checksum != "" {
+			checksum, err := computeChecksum(request.Checksum, reader)
+			if err != nil {
+				return false, err
+			}
+			asset.checksum = checksum
+		}
+		result[relative] = asset
+		return true, nil
+	}, options...)
+	return result, err
+}
+
+//Sync mirrors source tree to destination, only new or changed files are transferred
+func (s *service) Sync(context *endly.Context, request *SyncRequest) (*SyncResponse, error) {
+	var response = &SyncResponse{Transferred: make([]string, 0), Deleted: make([]string, 0)}
+	return response, s.sync(context, request, response)
+}
+
+func (s *service) sync(context *endly.Context, request *SyncRequest, response *SyncResponse) error {
+	source, sourceOptions, err := GetResourceWithOptions(context, request.Source)
+	if err != nil {
+		return err
+	}
+	dest, destOptions, err := GetResourceWithOptions(context, request.Dest)
+	if err != nil {
+		return err
+	}
+	fs, err := StorageService(context, source, dest)
+	if err != nil {
+		return err
+	}
+	sourceAssets, err := syncAssets(context.Background(), fs, request, source.URL, sourceOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list source: %v, %v", source.URL, err)
+	}
+	destAssets, err := syncAssets(context.Background(), fs, request, dest.URL, destOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list dest: %v, %v", dest.URL, err)
+	}
+	var relatives = make([]string, 0, len(sourceAssets))
+	for relative := range sourceAssets {
+		relatives = append(relatives, relative)
+	}
+	sort.Strings(relatives)
+	for _, relative := range relatives {
+		sourceAsset := sourceAssets[relative]
+		if destAsset, ok := destAssets[relative]; ok && !request.isChanged(sourceAsset, destAsset) {
+			response.Unchanged++
+			continue
+		}
+		response.Transferred = append(response.Transferred, relative)
+		if request.DryRun {
+			continue
+		}
+		if err = fs.Copy(context.Background(), arl.Join(source.URL, relative), arl.Join(dest.URL, relative), option.NewSource(sourceOptions...), option.NewDest(destOptions...)); err != nil {
+			return fmt.Errorf("failed to sync %v, %v", relative, err)
+		}
+		context.Heartbeat().AddBytes(sourceAsset.info.Size())
+	}
+	if !request.Delete {
+		return nil
+	}
+	relatives = relatives[:0]
+	for relative := range destAssets {
+		if _, ok := sourceAssets[relative]; !ok {
+			relatives = append(relatives, relative)
+		}
+	}
+	sort.Strings(relatives)
+	for _, relative := range relatives {
+		response.Deleted = append(response.Deleted, relative)
+		if request.DryRun {
+			continue
+		}
+		if err = fs.Delete(context.Background(), arl.Join(dest.URL, relative), destOptions...); err != nil {
+			return fmt.Errorf("failed to delete %v, %v", relative, err)
+		}
+	}
+	return nil
+}
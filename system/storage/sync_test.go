@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs/asset"
+	"github.com/viant/afs/mem"
+	"github.com/viant/endly"
+	"github.com/viant/endly/system/storage/copy"
+	"github.com/viant/toolbox/url"
+	"testing"
+)
+
+func TestService_Sync(t *testing.T) {
+	baseURL := "mem://localhost/data/storage/sync"
+	mgr := mem.Singleton()
+	err := asset.Create(mgr, baseURL+"/src", []*asset.Resource{
+		asset.NewFile("main.go", []byte("package main"), 0644),
+		asset.NewFile("config/app.json", []byte(`{"port":8080}`), 0644),
+		asset.NewFile("tmp/cache.bin", []byte("cache"), 0644),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	err = asset.Create(mgr, baseURL+"/dst", []*asset.Resource{
+		asset.NewFile("config/app.json", []byte(`{"port":8080}`), 0644),
+		asset.NewFile("obsolete.txt", []byte("old"), 0644),
+		asset.NewFile("tmp/local.bin", []byte("local"), 0644),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var newRequest = func(dryRun bool) *SyncRequest {
+		return &SyncRequest{
+			Source:   url.NewResource(baseURL + "/src"),
+			Dest:     url.NewResource(baseURL + "/dst"),
+			Exclude:  []string{"tmp"},
+			Checksum: copy.ChecksumMD5,
+			Delete:   true,
+			DryRun:   dryRun,
+		}
+	}
+
+	response := &SyncResponse{}
+	err = endly.Run(nil, newRequest(true), response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, []string{"main.go"}, response.Transferred)
+	assert.EqualValues(t, []string{"obsolete.txt"}, response.Deleted)
+	assert.Equal(t, 1, response.Unchanged)
+	assets, err := asset.Load(mgr, baseURL+"/dst")
+	if assert.Nil(t, err) {
+		assert.Nil(t, assets["main.go"])
+		assert.NotNil(t, assets["obsolete.txt"])
+	}
+
+	response = &SyncResponse{}
+	err = endly.Run(nil, newRequest(false), response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, []string{"main.go"}, response.Transferred)
+	assert.EqualValues(t, []string{"obsolete.txt"}, response.Deleted)
+	assets, err = asset.Load(mgr, baseURL+"/dst")
+	if assert.Nil(t, err) {
+		if assert.NotNil(t, assets["main.go"]) {
+			assert.EqualValues(t, "package main", string(assets["main.go"].Data))
+		}
+		assert.Nil(t, assets["obsolete.txt"])
+		assert.Nil(t, assets["tmp/cache.bin"])
+		assert.NotNil(t, assets["tmp/local.bin"]) //excluded files are never deleted
+	}
+
+	response = &SyncResponse{}
+	err = endly.Run(nil, newRequest(false), response)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 0, len(response.Transferred))
+		assert.Equal(t, 0, len(response.Deleted))
+		assert.Equal(t, 2, response.Unchanged)
+	}
+
+	err = endly.Run(nil, &SyncRequest{Source: url.NewResource(baseURL + "/src"), Dest: url.NewResource(baseURL + "/dst"), Exclude: []string{"["}}, &SyncResponse{})
+	assert.NotNil(t, err)
+}
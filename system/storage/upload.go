@@ -10,6 +10,7 @@ import (
 	"github.com/viant/afs/storage"
 	"github.com/viant/endly"
 	"github.com/viant/endly/udf"
+	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/url"
 	"io"
 	"os"
@@ -51,11 +52,11 @@ func (s *service) upload(context *endly.Context, request *UploadRequest, respons
 	if err != nil {
 		return err
 	}
-	var state = context.State()
+	var state = context.SafeState()
 	if !state.Has(request.SourceKey) {
 		return fmt.Errorf("sourcekey %v value was empty", request.SourceKey)
 	}
-	data := state.GetString(request.SourceKey)
+	data := toolbox.AsString(state.Get(request.SourceKey))
 	data = context.Expand(data)
 	var reader io.Reader = strings.NewReader(data)
 	if request.Udf != "" {
@@ -0,0 +1,35 @@
+package workspace
+
+import (
+	"github.com/viant/toolbox/url"
+)
+
+const defaultBaseDirectory = "/tmp/endly_workspace"
+
+//AllocateRequest represents session workspace allocation request
+type AllocateRequest struct {
+	Target        *url.Resource `description:"target host, local session workspace is returned if empty"`
+	Name          string        `description:"workspace name exposed as $session.workspaces.[Name], target host with non alphanumeric characters replaced by '_' by default"`
+	BaseDirectory string        `description:"remote base directory, workspace is created in [BaseDirectory]/[sessionID], /tmp/endly_workspace by default"`
+}
+
+//AllocateResponse represents allocation response
+type AllocateResponse struct {
+	*Workspace
+}
+
+//CleanupRequest represents session workspaces cleanup request
+type CleanupRequest struct{}
+
+//CleanupResponse represents cleanup response
+type CleanupResponse struct {
+	Removed []*Workspace
+}
+
+//Init initialises request
+func (r *AllocateRequest) Init() error {
+	if r.BaseDirectory == "" {
+		r.BaseDirectory = defaultBaseDirectory
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package workspace
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+)
+
+const (
+	//ServiceID represents workspace service id
+	ServiceID = "workspace"
+)
+
+type service struct {
+	*endly.AbstractService
+}
+
+func (s *service) allocate(context *endly.Context, request *AllocateRequest) (*AllocateResponse, error) {
+	workspace, err := allocate(context, request)
+	if err != nil {
+		return nil, err
+	}
+	return &AllocateResponse{Workspace: workspace}, nil
+}
+
+//cleanup removes session workspaces before workflow ends
+func (s *service) cleanup(context *endly.Context, request *CleanupRequest) (*CleanupResponse, error) {
+	var response = &CleanupResponse{Removed: make([]*Workspace, 0)}
+	registry := registryFor(context)
+	if registry == nil {
+		return response, nil
+	}
+	registry.release()
+	response.Removed = append(response.Removed, registry.removed...)
+	return response, nil
+}
+
+const (
+	allocateExample = `{
+  "Target": {
+    "URL": "ssh://127.0.0.1/",
+    "Credentials": "localhost"
+  },
+  "Name": "app"
+}`
+)
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "allocate",
+		RequestInfo: &endly.ActionInfo{
+			Description: "allocate session workspace on target host, exposed as $session.workspaces.[Name]",
+			Examples: []*endly.UseCase{
+				{
+					Description: "remote workspace",
+					Data:        allocateExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &AllocateRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &AllocateResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*AllocateRequest); ok {
+				return s.allocate(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "cleanup",
+		RequestInfo: &endly.ActionInfo{
+			Description: "remove local and remote session workspaces",
+		},
+		RequestProvider: func() interface{} {
+			return &CleanupRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &CleanupResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*CleanupRequest); ok {
+				return s.cleanup(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+//New creates a new workspace service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
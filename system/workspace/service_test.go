@@ -0,0 +1,74 @@
+package workspace
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"os"
+	"testing"
+)
+
+func TestEnable(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		context := endly.New().NewContext(nil)
+		release, err := Enable(context, keep)
+		if !assert.Nil(t, err) {
+			return
+		}
+		location, ok := context.SafeState().GetValue("session.workspace")
+		assert.True(t, ok)
+		assert.True(t, toolbox.FileExists(toolbox.AsString(location)))
+		nested, err := Enable(context, keep)
+		assert.Nil(t, err)
+		nested()
+		assert.True(t, toolbox.FileExists(toolbox.AsString(location)), "nested release should keep workspace")
+		release()
+		assert.Equal(t, keep, toolbox.FileExists(toolbox.AsString(location)))
+		context.Close()
+		_ = os.RemoveAll(toolbox.AsString(location))
+	}
+}
+
+func TestService_Allocate(t *testing.T) {
+	var commands = make([]string, 0)
+	run = func(context *endly.Context, target *url.Resource, runCommands ...string) error {
+		commands = append(commands, runCommands...)
+		return nil
+	}
+	context := endly.New().NewContext(nil)
+	service := New().(*service)
+	var useCases = []struct {
+		description string
+		request     *AllocateRequest
+		name        string
+		local       bool
+	}{
+		{description: "local workspace", request: &AllocateRequest{}, name: "local", local: true},
+		{description: "remote workspace", request: &AllocateRequest{Target: url.NewResource("ssh://10.0.0.1:22")}, name: "10_0_0_1"},
+		{description: "named workspace", request: &AllocateRequest{Target: url.NewResource("ssh://10.0.0.2:22"), Name: "app", BaseDirectory: "/opt/tmp"}, name: "app"},
+		{description: "allocated workspace", request: &AllocateRequest{Target: url.NewResource("ssh://10.0.0.1:22")}, name: "10_0_0_1"},
+	}
+	for _, useCase := range useCases {
+		_ = useCase.request.Init()
+		response, err := service.allocate(context, useCase.request)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.name, response.Name, useCase.description)
+		assert.Equal(t, useCase.local, response.Local, useCase.description)
+	}
+	location, _ := context.SafeState().GetValue("session.workspaces.app")
+	assert.Equal(t, "/opt/tmp/"+context.SessionID, location)
+
+	response, err := service.cleanup(context, &CleanupRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(response.Removed))
+	context.Close()
+	assert.Equal(t, 4, len(commands))
+	assert.Equal(t, []string{
+		"mkdir -p /tmp/endly_workspace/" + context.SessionID,
+		"mkdir -p /opt/tmp/" + context.SessionID,
+	}, commands[:2])
+	assert.Contains(t, commands, "rm -rf /opt/tmp/"+context.SessionID)
+}
@@ -0,0 +1,189 @@
+package workspace
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/system/exec"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"os"
+	"path"
+	"regexp"
+	"sync"
+)
+
+//StateKey represents state key with session ID, local workspace path and remote workspaces i.e. $session.workspace
+const StateKey = "session"
+
+var registryKey = (*registry)(nil)
+
+var invalidNameExpr = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+//Workspace represents session scoped temp directory
+type Workspace struct {
+	Name   string
+	URL    string
+	Path   string
+	Local  bool
+	target *url.Resource
+}
+
+//Event represents workspace allocation or cleanup event
+type Event struct {
+	Action    string
+	Workspace *Workspace
+}
+
+//Messages returns messages
+func (e *Event) Messages() []*msg.Message {
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("workspace %v", e.Workspace.Name), msg.MessageStyleGeneric),
+			msg.NewStyled(e.Action, msg.MessageStyleGeneric),
+			msg.NewStyled(e.Workspace.URL, msg.MessageStyleOutput),
+		),
+	}
+}
+
+//run runs commands on remote target
+var run = func(context *endly.Context, target *url.Resource, commands ...string) error {
+	runRequest := exec.NewRunRequest(target, false, commands...)
+	runRequest.CheckError = true
+	return endly.Run(context, runRequest, &exec.RunResponse{})
+}
+
+//registry represents session workspaces
+type registry struct {
+	mux     *sync.Mutex
+	keep    bool
+	local   *Workspace
+	remote  map[string]*Workspace
+	removed []*Workspace
+	release func()
+}
+
+//publishState exposes workspaces in context state
+func (r *registry) publishState(context *endly.Context) {
+	r.mux.Lock()
+	var workspaces = make(map[string]interface{})
+	for name, workspace := range r.remote {
+		workspaces[name] = workspace.Path
+	}
+	r.mux.Unlock()
+	context.SafeState().SetValue(StateKey, map[string]interface{}{
+		"id":         context.SessionID,
+		"workspace":  r.local.Path,
+		"workspaces": workspaces,
+	})
+}
+
+//cleanup removes all session workspaces unless keep flag is set
+func (r *registry) cleanup(context *endly.Context) {
+	r.mux.Lock()
+	var workspaces = []*Workspace{r.local}
+	for _, workspace := range r.remote {
+		workspaces = append(workspaces, workspace)
+	}
+	r.mux.Unlock()
+	for _, workspace := range workspaces {
+		if r.keep {
+			context.Publish(&Event{Action: "keep", Workspace: workspace})
+			continue
+		}
+		var err error
+		if workspace.Local {
+			err = os.RemoveAll(workspace.Path)
+		} else {
+			err = run(context, workspace.target, fmt.Sprintf("rm -rf %v", workspace.Path))
+		}
+		if err != nil {
+			context.Publish(msg.NewErrorEvent(fmt.Sprintf("failed to remove workspace %v: %v", workspace.URL, err)))
+			continue
+		}
+		r.removed = append(r.removed, workspace)
+		context.Publish(&Event{Action: "cleanup", Workspace: workspace})
+	}
+}
+
+func registryFor(context *endly.Context) *registry {
+	if !context.Contains(registryKey) {
+		return nil
+	}
+	var result *registry
+	context.GetInto(registryKey, &result)
+	return result
+}
+
+//Enable allocates local session workspace, returned function removes local and remote session workspaces,
+//it also runs if context closes, i.e. aborted run
+func Enable(context *endly.Context, keep bool) (func(), error) {
+	if registryFor(context) != nil {
+		return func() {}, nil
+	}
+	location := path.Join(os.TempDir(), "endly_workspace", context.SessionID)
+	if err := os.MkdirAll(location, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create workspace %v: %v", location, err)
+	}
+	var result = &registry{
+		mux:    &sync.Mutex{},
+		keep:   keep,
+		local:  &Workspace{Name: "local", Path: location, URL: url.NewResource(location).URL, Local: true},
+		remote: make(map[string]*Workspace),
+	}
+	once := &sync.Once{}
+	result.release = func() {
+		once.Do(func() {
+			context.Remove(registryKey)
+			result.cleanup(context)
+		})
+	}
+	if err := context.Put(registryKey, result); err != nil {
+		return nil, err
+	}
+	result.publishState(context)
+	context.Publish(&Event{Action: "allocate", Workspace: result.local})
+	context.Deffer(result.release)
+	return result.release, nil
+}
+
+//allocate returns session workspace for supplied target, remote workspace directory is created once per target host
+func allocate(context *endly.Context, request *AllocateRequest) (*Workspace, error) {
+	registry := registryFor(context)
+	if registry == nil {
+		if _, err := Enable(context, false); err != nil {
+			return nil, err
+		}
+		registry = registryFor(context)
+	}
+	if request.Target == nil {
+		return registry.local, nil
+	}
+	target, err := context.ExpandResource(request.Target)
+	if err != nil {
+		return nil, err
+	}
+	if target.ParsedURL.Scheme == "file" {
+		return registry.local, nil
+	}
+	name := request.Name
+	if name == "" {
+		name = invalidNameExpr.ReplaceAllString(target.ParsedURL.Hostname(), "_")
+	}
+	registry.mux.Lock()
+	workspace, ok := registry.remote[name]
+	registry.mux.Unlock()
+	if ok {
+		return workspace, nil
+	}
+	location := path.Join(request.BaseDirectory, context.SessionID)
+	if err = run(context, target, fmt.Sprintf("mkdir -p %v", location)); err != nil {
+		return nil, fmt.Errorf("failed to create workspace %v on %v: %v", location, target.URL, err)
+	}
+	workspace = &Workspace{Name: name, Path: location, URL: url.NewResource(toolbox.URLPathJoin(target.URL, location)).URL, target: target}
+	registry.mux.Lock()
+	registry.remote[name] = workspace
+	registry.mux.Unlock()
+	registry.publishState(context)
+	context.Publish(&Event{Action: "allocate", Workspace: workspace})
+	return workspace, nil
+}
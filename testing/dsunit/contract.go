@@ -1,8 +1,11 @@
 package dsunit
 
 import (
+	"errors"
+	"fmt"
 	"github.com/viant/assertly"
 	"github.com/viant/dsunit"
+	"github.com/viant/toolbox/url"
 )
 
 //InitRequest represents an init request
@@ -39,13 +42,81 @@ type RunSQLRequest dsunit.RunSQLRequest
 type RunSQLResponse dsunit.RunSQLResponse
 
 //PrepareRequest represents a prepare request
-type PrepareRequest dsunit.PrepareRequest
+type PrepareRequest struct {
+	*dsunit.PrepareRequest
+	Concurrency  int                 `description:"max number of tables populated in parallel, tables are populated by dsunit in one transaction if less than 2"`
+	Dependencies map[string][]string `description:"table foreign key dependencies used with concurrency, a table is populated after its dependencies, i.e. orders: [users, products]"`
+}
 
 //PrepareResponse represents a prepare response
 type PrepareResponse dsunit.PrepareResponse
 
 //ExpectRequest represents an expect request
-type ExpectRequest dsunit.ExpectRequest
+type ExpectRequest struct {
+	*dsunit.ExpectRequest
+	Queries []*QueryExpect `description:"SQL query result assertions, i.e. counts, sums or group-bys, so that large tables can be verified by invariants instead of full table rows comparison"`
+}
+
+//QueryExpect represents SQL query result assertion
+type QueryExpect struct {
+	Datastore   string      `description:"registered datastore name, expect request datastore by default"`
+	Description string      `description:"assertion description, SQL by default"`
+	SQL         string      `required:"true" description:"query SQL, i.e. SELECT status, COUNT(*) AS cnt FROM orders GROUP BY status ORDER BY status"`
+	Expect      interface{} `required:"true" description:"expected records, a record for single row result, or a value for single row and column result, assertly directives are supported"`
+}
+
+//Init initializes request
+func (r *ExpectRequest) Init() error {
+	if r.ExpectRequest == nil {
+		r.ExpectRequest = &dsunit.ExpectRequest{}
+	}
+	if r.DatasetResource == nil {
+		return nil
+	}
+	return r.ExpectRequest.Init()
+}
+
+//Validate checks if request is valid
+func (r *ExpectRequest) Validate() error {
+	if r.hasDatasets() || len(r.Queries) == 0 {
+		if r.ExpectRequest == nil {
+			return errors.New("dataset resource was empty")
+		}
+		if err := r.ExpectRequest.Validate(); err != nil {
+			return err
+		}
+	}
+	for i, query := range r.Queries {
+		if query.SQL == "" {
+			return fmt.Errorf("queries[%v].SQL was empty", i)
+		}
+		if query.Expect == nil {
+			return fmt.Errorf("queries[%v].Expect was empty", i)
+		}
+		if query.Datastore == "" && r.datastore() == "" {
+			return fmt.Errorf("queries[%v].Datastore was empty", i)
+		}
+	}
+	return nil
+}
+
+//hasDatasets returns true if request has datasets to verify
+func (r *ExpectRequest) hasDatasets() bool {
+	if r.ExpectRequest == nil || r.DatasetResource == nil {
+		return false
+	}
+	if r.Resource != nil && r.Resource.URL != "" {
+		return true
+	}
+	return r.DatastoreDatasets != nil && (len(r.Datasets) > 0 || len(r.Data) > 0)
+}
+
+func (r *ExpectRequest) datastore() string {
+	if r.ExpectRequest == nil || r.DatasetResource == nil || r.DatastoreDatasets == nil {
+		return ""
+	}
+	return r.Datastore
+}
 
 //ExpectResponse represent an expect response
 type ExpectResponse dsunit.ExpectResponse
@@ -133,3 +204,88 @@ func (r *CheckSchemaResponse) Assertion() []*assertly.Validation {
 	}
 	return result
 }
+
+//MigrateRequest represents a schema migration request, ordered SQL migration files not recorded in the ledger table are applied
+type MigrateRequest struct {
+	Datastore      string        `required:"true" description:"registered datastore name"`
+	Source         *url.Resource `required:"true" description:"migration directory, file name format: [V]<version>_<name>[.<dialect>].sql, i.e. 001_create_users.sql, 002_add_index.mysql.sql"`
+	Dialect        string        `description:"dialect used to select dialect specific migrations, defaults to datastore driver name"`
+	Table          string        `description:"applied migrations ledger table, default endly_migration"`
+	Target         uint64        `description:"max version to apply, all if empty"`
+	Expand         bool          `description:"expand migration SQL with workflow state"`
+	IgnoreChecksum bool          `description:"skip modified applied migration check"`
+}
+
+//MigrateResponse represents a schema migration response
+type MigrateResponse struct {
+	Dialect string
+	Applied []*Migration
+	Skipped int `description:"already applied migrations count"`
+}
+
+//Init initializes request
+func (r *MigrateRequest) Init() error {
+	if r.Table == "" {
+		r.Table = defaultMigrationTable
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *MigrateRequest) Validate() error {
+	if r.Datastore == "" {
+		return errors.New("datastore was empty")
+	}
+	if r.Source == nil || r.Source.URL == "" {
+		return errors.New("source was empty")
+	}
+	return nil
+}
+
+//SnapshotRequest represents a request to capture tables content to storage, each table is stored as <table>.json dataset
+type SnapshotRequest struct {
+	Datastore  string        `required:"true" description:"registered datastore name"`
+	Tables     []string      `description:"tables to capture, all if empty"`
+	Dest       *url.Resource `required:"true" description:"snapshot destination base URL"`
+	TimeLayout string        `description:"golang time layout to format time values, RFC3339 if empty"`
+}
+
+//SnapshotResponse represents a snapshot response
+type SnapshotResponse struct {
+	URL    string
+	Tables map[string]int `description:"captured rows count by table"`
+}
+
+//Validate checks if request is valid
+func (r *SnapshotRequest) Validate() error {
+	if r.Datastore == "" {
+		return errors.New("datastore was empty")
+	}
+	if r.Dest == nil || r.Dest.URL == "" {
+		return errors.New("dest was empty")
+	}
+	return nil
+}
+
+//RestoreRequest represents a request to restore tables content from snapshot, existing table rows are deleted
+type RestoreRequest struct {
+	Datastore string        `required:"true" description:"registered datastore name"`
+	Source    *url.Resource `required:"true" description:"snapshot base URL"`
+	Tables    []string      `description:"tables to restore, all snapshot tables if empty"`
+}
+
+//RestoreResponse represents a restore response
+type RestoreResponse struct {
+	Modification map[string]*dsunit.ModificationInfo
+}
+
+//Validate checks if request is valid
+func (r *RestoreRequest) Validate() error {
+	if r.Datastore == "" {
+		return errors.New("datastore was empty")
+	}
+	if r.Source == nil || r.Source.URL == "" {
+		return errors.New("source was empty")
+	}
+	return nil
+}
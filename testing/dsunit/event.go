@@ -77,6 +77,9 @@ func (r *SequenceResponse) Messages() []*msg.Message {
 
 //Messages returns messages
 func (r *PrepareRequest) Messages() []*msg.Message {
+	if r.PrepareRequest == nil {
+		return []*msg.Message{}
+	}
 	err := r.Load()
 	if r.DatasetResource == nil || len(r.Datasets) == 0 {
 		return []*msg.Message{}
@@ -99,3 +102,20 @@ func (r *QueryRequest) Messages() []*msg.Message {
 	message := msg.NewMessage(msg.NewStyled(fmt.Sprintf("(%v) %v", r.Datastore, r.SQL), msg.MessageStyleGeneric), msg.NewStyled("query", msg.MessageStyleGeneric))
 	return []*msg.Message{message}
 }
+
+//Messages returns messages
+func (r *MigrateResponse) Messages() []*msg.Message {
+	var result = make([]*msg.Message, 0)
+	for _, migration := range r.Applied {
+		result = append(result,
+			msg.NewMessage(msg.NewStyled(fmt.Sprintf("(%v) %v_%v: %v", r.Dialect, migration.Version, migration.Name, migration.Statements), msg.MessageStyleGeneric), msg.NewStyled("migrate", msg.MessageStyleGeneric)))
+	}
+	return result
+}
+
+//Messages returns messages
+func (e *PrepareTableEvent) Messages() []*msg.Message {
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("(%v) %v: added: %v, modified: %v, deleted: %v, %vms", e.Datastore, e.Table, e.Added, e.Modified, e.Deleted, e.ElapsedMs), msg.MessageStyleGeneric), msg.NewStyled("populate", msg.MessageStyleGeneric)),
+	}
+}
@@ -0,0 +1,83 @@
+package dsunit
+
+import (
+	"github.com/viant/assertly"
+	"github.com/viant/dsunit"
+	"github.com/viant/endly"
+	"github.com/viant/endly/testing/validator"
+	"github.com/viant/toolbox"
+)
+
+//actual returns query records shaped as expected value: records, a single record or a single value
+func (q *QueryExpect) actual(records []map[string]interface{}) interface{} {
+	if toolbox.IsSlice(q.Expect) || len(records) != 1 {
+		return records
+	}
+	if toolbox.IsMap(q.Expect) {
+		return records[0]
+	}
+	if len(records[0]) != 1 {
+		return records
+	}
+	for _, value := range records[0] {
+		return value
+	}
+	return nil
+}
+
+//expectQuery runs query and validates its result
+func (s *service) expectQuery(context *endly.Context, datastore string, query *QueryExpect) (*dsunit.DatasetValidation, error) {
+	if query.Datastore != "" {
+		datastore = query.Datastore
+	}
+	queryResponse := s.Service.Query(&dsunit.QueryRequest{Datastore: datastore, SQL: query.SQL})
+	if err := queryResponse.Error(); err != nil {
+		return nil, err
+	}
+	description := query.Description
+	if description == "" {
+		description = query.SQL
+	}
+	var actual = query.actual(queryResponse.Records)
+	validation, err := assertly.Assert(query.Expect, actual, assertly.NewDataPath(datastore))
+	if err != nil {
+		return nil, err
+	}
+	validation.Description = description
+	return &dsunit.DatasetValidation{
+		Dataset:    description,
+		Validation: validation,
+		Expected:   query.Expect,
+		Actual:     actual,
+	}, nil
+}
+
+//expect verifies datasets and query assertions
+func (s *service) expect(context *endly.Context, request *ExpectRequest) (*ExpectResponse, error) {
+	var response = &ExpectResponse{BaseResponse: dsunit.NewBaseOkResponse()}
+	if request.hasDatasets() {
+		response = (*ExpectResponse)(s.Service.Expect(request.ExpectRequest))
+		if err := response.Error(); err != nil {
+			return response, err
+		}
+	}
+	for _, query := range request.Queries {
+		validation, err := s.expectQuery(context, request.datastore(), query)
+		if err != nil {
+			response.SetError(err)
+			return response, err
+		}
+		response.Validation = append(response.Validation, validation)
+		response.PassedCount += validation.PassedCount
+		response.FailedCount += validation.FailedCount
+	}
+	for _, validation := range response.Validation {
+		context.Publish(&validator.AssertRequest{
+			Description: validation.Description,
+			Expected:    validation.Expected,
+			Actual:      validation.Actual,
+			Source:      validation.Dataset,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,88 @@
+package dsunit
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/dsunit"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestService_ExpectQueries(t *testing.T) {
+	var baseDir = path.Join(os.TempDir(), "test/endly/dsunit/expect")
+	_ = os.RemoveAll(baseDir)
+	_ = toolbox.CreateDirIfNotExist(baseDir)
+	config, err := dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{
+		"url": path.Join(baseDir, "mydb5"),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	context := endly.New().NewContext(nil)
+	registerRequest := RegisterRequest(*dsunit.NewRegisterRequest("mydb5", config))
+	if err = endly.Run(context, &registerRequest, &RegisterResponse{}); !assert.Nil(t, err) {
+		return
+	}
+	err = endly.Run(context, &MigrateRequest{Datastore: "mydb5", Source: url.NewResource("test/migration")}, &MigrateResponse{})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var useCases = []struct {
+		description string
+		queries     []*QueryExpect
+		passed      int
+		failed      int
+		hasError    bool
+	}{
+		{
+			description: "scalar count",
+			queries:     []*QueryExpect{{SQL: "SELECT COUNT(*) AS cnt FROM product", Expect: 2}},
+			passed:      1,
+		},
+		{
+			description: "single row aggregates",
+			queries:     []*QueryExpect{{SQL: "SELECT COUNT(*) AS cnt, SUM(price) AS total FROM product", Expect: map[string]interface{}{"cnt": 2, "total": "<ds:between[10,20]>"}}},
+			passed:      2,
+		},
+		{
+			description: "group by records",
+			queries: []*QueryExpect{{SQL: "SELECT name, SUM(price) AS total FROM product GROUP BY name ORDER BY name", Expect: []interface{}{
+				map[string]interface{}{"name": "p1", "total": 10.5},
+				map[string]interface{}{"name": "p2", "total": 1.0},
+			}}},
+			passed: 3,
+			failed: 1,
+		},
+		{
+			description: "invalid SQL",
+			queries:     []*QueryExpect{{SQL: "SELECT COUNT(*) FROM unknown", Expect: 0}},
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		request := &ExpectRequest{
+			ExpectRequest: &dsunit.ExpectRequest{DatasetResource: &dsunit.DatasetResource{DatastoreDatasets: &dsunit.DatastoreDatasets{Datastore: "mydb5"}}},
+			Queries:       useCase.queries,
+		}
+		response := &ExpectResponse{}
+		err = endly.Run(context, request, response)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.passed, response.PassedCount, useCase.description)
+		assert.Equal(t, useCase.failed, response.FailedCount, useCase.description)
+	}
+
+	err = endly.Run(context, &ExpectRequest{Queries: []*QueryExpect{{SQL: "SELECT 1", Expect: 1}}}, &ExpectResponse{})
+	assert.NotNil(t, err, "missing datastore")
+}
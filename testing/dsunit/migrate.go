@@ -0,0 +1,187 @@
+package dsunit
+
+import (
+	"crypto/md5"
+	"fmt"
+	"github.com/viant/afs/storage"
+	"github.com/viant/dsc"
+	"github.com/viant/dsunit"
+	"github.com/viant/dsunit/script"
+	"github.com/viant/endly"
+	estorage "github.com/viant/endly/system/storage"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultMigrationTable = "endly_migration"
+
+//migrationFileExpr matches migration file name: [V]<version>_<name>[.<dialect>].sql
+var migrationFileExpr = regexp.MustCompile(`^[vV]?(\d+)_+([^.]+)(\.([^.]+))?\.sql$`)
+
+//Migration represents a schema migration
+type Migration struct {
+	Version    uint64
+	Name       string
+	Dialect    string `json:",omitempty" description:"dialect the migration is specific to, empty if generic"`
+	URL        string
+	Checksum   string
+	Statements int
+	object     storage.Object
+	content    []byte
+}
+
+//newMigration returns a migration for supplied object or nil if the object is not a migration file
+func newMigration(object storage.Object) (*Migration, error) {
+	matched := migrationFileExpr.FindStringSubmatch(object.Name())
+	if len(matched) == 0 {
+		return nil, nil
+	}
+	version, err := strconv.ParseUint(matched[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid migration version %v: %v", object.Name(), err)
+	}
+	return &Migration{
+		Version: version,
+		Name:    matched[2],
+		Dialect: strings.ToLower(matched[4]),
+		URL:     object.URL(),
+		object:  object,
+	}, nil
+}
+
+//loadMigrations loads ordered migrations for supplied dialect, a dialect specific migration takes precedence over generic one with the same version
+func (s *service) loadMigrations(context *endly.Context, request *MigrateRequest, dialect string) ([]*Migration, error) {
+	source, err := context.ExpandResource(request.Source)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := estorage.StorageService(context, source)
+	if err != nil {
+		return nil, err
+	}
+	source, storageOptions, err := estorage.GetResourceWithOptions(context, source)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := fs.List(context.Background(), source.URL, storageOptions...)
+	if err != nil {
+		return nil, err
+	}
+	var byVersion = make(map[uint64]*Migration)
+	for _, object := range objects {
+		if object.IsDir() {
+			continue
+		}
+		migration, err := newMigration(object)
+		if err != nil {
+			return nil, err
+		}
+		if migration == nil || (migration.Dialect != "" && migration.Dialect != dialect) {
+			continue
+		}
+		if existing, ok := byVersion[migration.Version]; ok {
+			if existing.Dialect == migration.Dialect {
+				return nil, fmt.Errorf("duplicate migration version %v: %v, %v", migration.Version, existing.URL, migration.URL)
+			}
+			if existing.Dialect != "" {
+				continue
+			}
+		}
+		byVersion[migration.Version] = migration
+	}
+	var result = make([]*Migration, 0)
+	for _, migration := range byVersion {
+		if request.Target > 0 && migration.Version > request.Target {
+			continue
+		}
+		content, err := fs.Download(context.Background(), migration.object, storageOptions...)
+		if err != nil {
+			return nil, err
+		}
+		migration.content = content
+		migration.Checksum = fmt.Sprintf("%x", md5.Sum(content))
+		result = append(result, migration)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+//appliedMigrations returns applied migration checksum by version, it creates ledger table if needed
+func appliedMigrations(manager dsc.Manager, table string) (map[uint64]string, error) {
+	DDL := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %v (version VARCHAR(20) NOT NULL PRIMARY KEY, name VARCHAR(255), checksum VARCHAR(32), applied VARCHAR(32))", table)
+	if _, err := manager.Execute(DDL); err != nil {
+		return nil, fmt.Errorf("failed to create migration table %v: %v", table, err)
+	}
+	var result = make(map[uint64]string)
+	err := manager.ReadAllWithHandler(fmt.Sprintf("SELECT version, checksum FROM %v", table), nil, func(scanner dsc.Scanner) (bool, error) {
+		var version, checksum string
+		if err := scanner.Scan(&version, &checksum); err != nil {
+			return false, err
+		}
+		value, err := strconv.ParseUint(version, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid applied migration version: %v", version)
+		}
+		result[value] = checksum
+		return true, nil
+	})
+	return result, err
+}
+
+func (s *service) migrate(context *endly.Context, request *MigrateRequest) (*MigrateResponse, error) {
+	var response = &MigrateResponse{Applied: make([]*Migration, 0)}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	manager := s.Service.Registry().Get(request.Datastore)
+	if manager == nil {
+		return nil, fmt.Errorf("datastore %v was not registered", request.Datastore)
+	}
+	response.Dialect = strings.ToLower(request.Dialect)
+	if response.Dialect == "" {
+		response.Dialect = strings.ToLower(manager.Config().DriverName)
+	}
+	migrations, err := s.loadMigrations(context, request, response.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(manager, request.Table)
+	if err != nil {
+		return nil, err
+	}
+	var state = context.SafeState()
+	for _, migration := range migrations {
+		if checksum, ok := applied[migration.Version]; ok {
+			if checksum != migration.Checksum && !request.IgnoreChecksum {
+				return response, fmt.Errorf("applied migration %v was modified: %v", migration.Version, migration.URL)
+			}
+			response.Skipped++
+			continue
+		}
+		SQL := script.Parse(string(migration.content))
+		if request.Expand {
+			for i := range SQL {
+				SQL[i] = state.ExpandAsText(SQL[i])
+			}
+		}
+		migration.Statements = len(SQL)
+		if len(SQL) > 0 {
+			sqlResponse := s.Service.RunSQL(&dsunit.RunSQLRequest{Datastore: request.Datastore, SQL: SQL})
+			if err = sqlResponse.Error(); err != nil {
+				return response, fmt.Errorf("failed to apply migration %v: %v", migration.URL, err)
+			}
+		}
+		DML := fmt.Sprintf("INSERT INTO %v(version, name, checksum, applied) VALUES('%d', '%v', '%v', '%v')", request.Table,
+			migration.Version, strings.Replace(migration.Name, "'", "''", -1), migration.Checksum, time.Now().UTC().Format(time.RFC3339))
+		if _, err = manager.Execute(DML); err != nil {
+			return response, fmt.Errorf("failed to record migration %v: %v", migration.Version, err)
+		}
+		response.Applied = append(response.Applied, migration)
+	}
+	return response, nil
+}
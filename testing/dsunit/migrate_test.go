@@ -0,0 +1,67 @@
+package dsunit
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/dsunit"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestService_Migrate(t *testing.T) {
+	var baseDir = path.Join(os.TempDir(), "test/endly/dsunit/migrate")
+	_ = os.RemoveAll(baseDir)
+	_ = toolbox.CreateDirIfNotExist(baseDir)
+	config, err := dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{
+		"url": path.Join(baseDir, "mydb3"),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	context := endly.New().NewContext(nil)
+	registerRequest := RegisterRequest(*dsunit.NewRegisterRequest("mydb3", config))
+	err = endly.Run(context, &registerRequest, &RegisterResponse{})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	request := &MigrateRequest{Datastore: "mydb3", Source: url.NewResource("test/migration"), Target: 2}
+	response := &MigrateResponse{}
+	if err = endly.Run(context, request, response); !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "sqlite3", response.Dialect)
+	if assert.Equal(t, 2, len(response.Applied)) {
+		assert.EqualValues(t, 1, response.Applied[0].Version)
+		assert.Equal(t, "create_product", response.Applied[0].Name)
+		assert.Equal(t, "sqlite3", response.Applied[1].Dialect)
+	}
+
+	request = &MigrateRequest{Datastore: "mydb3", Source: url.NewResource("test/migration")}
+	response = &MigrateResponse{}
+	if err = endly.Run(context, request, response); !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, response.Skipped)
+	if assert.Equal(t, 1, len(response.Applied)) {
+		assert.EqualValues(t, 3, response.Applied[0].Version)
+		assert.Equal(t, 2, response.Applied[0].Statements)
+	}
+
+	queryResponse := &QueryResponse{}
+	err = endly.Run(context, &QueryRequest{Datastore: "mydb3", SQL: "SELECT COUNT(*) AS cnt, SUM(price) AS total FROM product"}, queryResponse)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(queryResponse.Records)) {
+		assert.EqualValues(t, 2, toolbox.AsInt(queryResponse.Records[0]["cnt"]))
+		assert.EqualValues(t, 14.0, toolbox.AsFloat(queryResponse.Records[0]["total"]))
+	}
+
+	err = endly.Run(context, &MigrateRequest{Datastore: "mydb3"}, &MigrateResponse{})
+	assert.NotNil(t, err)
+	err = endly.Run(context, &MigrateRequest{Datastore: "unknown", Source: url.NewResource("test/migration")}, &MigrateResponse{})
+	assert.NotNil(t, err)
+}
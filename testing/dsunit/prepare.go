@@ -0,0 +1,146 @@
+package dsunit
+
+import (
+	"fmt"
+	"github.com/viant/dsunit"
+	dsurl "github.com/viant/dsunit/url"
+	"github.com/viant/endly"
+	"sync"
+	"time"
+)
+
+//PrepareTableEvent represents table data preparation event
+type PrepareTableEvent struct {
+	Datastore string
+	Table     string
+	Added     int
+	Modified  int
+	Deleted   int
+	ElapsedMs int
+}
+
+//tablePreparation represents table datasets with preparation result
+type tablePreparation struct {
+	table    string
+	datasets []*dsunit.Dataset
+	response *dsunit.PrepareResponse
+	elapsed  time.Duration
+}
+
+//dependencyLevels groups table preparations into ordered levels, tables within a level do not depend on each other
+func dependencyLevels(preparations []*tablePreparation, dependencies map[string][]string) ([][]*tablePreparation, error) {
+	var pending = make(map[string]bool)
+	for _, preparation := range preparations {
+		pending[preparation.table] = true
+	}
+	var result = make([][]*tablePreparation, 0)
+	for len(pending) > 0 {
+		var level = make([]*tablePreparation, 0)
+		for _, preparation := range preparations {
+			if !pending[preparation.table] {
+				continue
+			}
+			var isReady = true
+			for _, dependency := range dependencies[preparation.table] {
+				if dependency != preparation.table && pending[dependency] {
+					isReady = false
+					break
+				}
+			}
+			if isReady {
+				level = append(level, preparation)
+			}
+		}
+		if len(level) == 0 {
+			return nil, fmt.Errorf("circular table dependencies: %v", pending)
+		}
+		for _, preparation := range level {
+			delete(pending, preparation.table)
+		}
+		result = append(result, level)
+	}
+	return result, nil
+}
+
+//tablePreparations groups request datasets by table
+func tablePreparations(datasets []*dsunit.Dataset) []*tablePreparation {
+	var result = make([]*tablePreparation, 0)
+	var byTable = make(map[string]*tablePreparation)
+	for _, dataset := range datasets {
+		preparation, ok := byTable[dataset.Table]
+		if !ok {
+			preparation = &tablePreparation{table: dataset.Table}
+			byTable[dataset.Table] = preparation
+			result = append(result, preparation)
+		}
+		preparation.datasets = append(preparation.datasets, dataset)
+	}
+	return result
+}
+
+//prepare populates a table datasets
+func (p *tablePreparation) prepare(service dsunit.Service, datastore string) {
+	started := time.Now()
+	p.response = service.Prepare(&dsunit.PrepareRequest{
+		DatasetResource: &dsunit.DatasetResource{
+			Resource:          &dsurl.Resource{},
+			DatastoreDatasets: &dsunit.DatastoreDatasets{Datastore: datastore, Datasets: p.datasets},
+		},
+	})
+	p.elapsed = time.Since(started)
+}
+
+//prepareConcurrently populates independent tables in parallel, a table is populated once all its dependencies are populated
+func (s *service) prepareConcurrently(context *endly.Context, request *PrepareRequest) (*PrepareResponse, error) {
+	var response = &PrepareResponse{
+		BaseResponse: dsunit.NewBaseOkResponse(),
+		Modification: make(map[string]*dsunit.ModificationInfo),
+	}
+	dsRequest := request.PrepareRequest
+	if err := dsRequest.Init(); err != nil {
+		return nil, err
+	}
+	if err := dsRequest.Validate(); err != nil {
+		return nil, err
+	}
+	if err := dsRequest.Load(); err != nil {
+		return nil, err
+	}
+	if len(dsRequest.Datasets) == 0 {
+		return nil, fmt.Errorf("no dataset: %v/%v", dsRequest.URL, dsRequest.Prefix+"*"+dsRequest.Postfix)
+	}
+	levels, err := dependencyLevels(tablePreparations(dsRequest.Datasets), request.Dependencies)
+	if err != nil {
+		return nil, err
+	}
+	for _, level := range levels {
+		var waitGroup = &sync.WaitGroup{}
+		var limiter = make(chan bool, request.Concurrency)
+		for _, preparation := range level {
+			limiter <- true
+			waitGroup.Add(1)
+			go func(preparation *tablePreparation) {
+				defer func() {
+					<-limiter
+					waitGroup.Done()
+				}()
+				preparation.prepare(s.Service, dsRequest.Datastore)
+			}(preparation)
+		}
+		waitGroup.Wait()
+		for _, preparation := range level {
+			var event = &PrepareTableEvent{Datastore: dsRequest.Datastore, Table: preparation.table, ElapsedMs: int(preparation.elapsed / time.Millisecond)}
+			for table, modification := range preparation.response.Modification {
+				response.Modification[table] = modification
+				event.Added += modification.Added
+				event.Modified += modification.Modified
+				event.Deleted += modification.Deleted
+			}
+			context.Publish(event)
+			if err = preparation.response.Error(); err != nil {
+				return response, fmt.Errorf("failed to prepare %v: %v", preparation.table, err)
+			}
+		}
+	}
+	return response, nil
+}
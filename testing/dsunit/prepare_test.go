@@ -0,0 +1,146 @@
+package dsunit
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/dsunit"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestDependencyLevels(t *testing.T) {
+	preparations := tablePreparations([]*dsunit.Dataset{
+		{Table: "order_items"}, {Table: "orders"}, {Table: "users"}, {Table: "products"}, {Table: "users"},
+	})
+	assert.Equal(t, 4, len(preparations))
+	levels, err := dependencyLevels(preparations, map[string][]string{
+		"orders":      {"users", "products", "accounts"},
+		"order_items": {"orders", "products"},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var actual = make([][]string, 0)
+	for _, level := range levels {
+		var tables = make([]string, 0)
+		for _, preparation := range level {
+			tables = append(tables, preparation.table)
+		}
+		actual = append(actual, tables)
+	}
+	assert.Equal(t, [][]string{{"users", "products"}, {"orders"}, {"order_items"}}, actual)
+
+	_, err = dependencyLevels(preparations, map[string][]string{
+		"orders": {"order_items"}, "order_items": {"orders"},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestService_PrepareConcurrently(t *testing.T) {
+	var baseDir = path.Join(os.TempDir(), "test/endly/dsunit/prepare")
+	_ = os.RemoveAll(baseDir)
+	_ = toolbox.CreateDirIfNotExist(baseDir)
+	config, err := dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{
+		"url": path.Join(baseDir, "mydb5"),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	context := endly.New().NewContext(nil)
+	registerRequest := RegisterRequest(*dsunit.NewRegisterRequest("mydb5", config))
+	if err = endly.Run(context, &registerRequest, &RegisterResponse{}); !assert.Nil(t, err) {
+		return
+	}
+	createRequest := RunSQLRequest{Datastore: "mydb5", SQL: []string{
+		"CREATE TABLE users(id INTEGER NOT NULL PRIMARY KEY, name VARCHAR(255))",
+		"CREATE TABLE products(id INTEGER NOT NULL PRIMARY KEY, name VARCHAR(255))",
+		"CREATE TABLE orders(id INTEGER NOT NULL PRIMARY KEY, user_id INTEGER, product_id INTEGER)",
+	}}
+	if err = endly.Run(context, &createRequest, &RunSQLResponse{}); !assert.Nil(t, err) {
+		return
+	}
+
+	request := &PrepareRequest{PrepareRequest: &dsunit.PrepareRequest{}}
+	err = toolbox.DefaultConverter.AssignConverted(request, map[string]interface{}{
+		"Datastore":   "mydb5",
+		"Concurrency": 2,
+		"Dependencies": map[string]interface{}{
+			"orders": []interface{}{"users", "products"},
+		},
+		"Data": map[string]interface{}{
+			"users":    []interface{}{map[string]interface{}{"id": 1, "name": "u1"}, map[string]interface{}{"id": 2, "name": "u2"}},
+			"products": []interface{}{map[string]interface{}{"id": 1, "name": "p1"}},
+			"orders":   []interface{}{map[string]interface{}{"id": 1, "user_id": 1, "product_id": 1}},
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var events = 0
+	context.SetListener(func(event msg.Event) {
+		if _, ok := event.Value().(*PrepareTableEvent); ok {
+			events++
+		}
+	})
+	response := &PrepareResponse{}
+	if err = endly.Run(context, request, response); !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 3, len(response.Modification))
+	if assert.NotNil(t, response.Modification["users"]) {
+		assert.Equal(t, 2, response.Modification["users"].Added)
+	}
+	assert.Equal(t, 3, events)
+
+	request.Dependencies = map[string][]string{"orders": {"users"}, "users": {"orders"}}
+	assert.NotNil(t, endly.Run(context, request, &PrepareResponse{}))
+}
+
+func TestService_Run_SubstitutionMap(t *testing.T) {
+	var baseDir = path.Join(os.TempDir(), "test/endly/dsunit/substitution")
+	_ = os.RemoveAll(baseDir)
+	_ = toolbox.CreateDirIfNotExist(baseDir)
+	config, err := dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{
+		"url": path.Join(baseDir, "mydb5"),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	context := endly.New().NewContext(nil)
+	context.SafeState().Put("userName", "u-state")
+	registerRequest := RegisterRequest(*dsunit.NewRegisterRequest("mydb5", config))
+	if err = endly.Run(context, &registerRequest, &RegisterResponse{}); !assert.Nil(t, err) {
+		return
+	}
+	createRequest := RunSQLRequest{Datastore: "mydb5", SQL: []string{"CREATE TABLE users(id INTEGER NOT NULL PRIMARY KEY, name VARCHAR(255))"}}
+	if err = endly.Run(context, &createRequest, &RunSQLResponse{}); !assert.Nil(t, err) {
+		return
+	}
+	request := &PrepareRequest{PrepareRequest: &dsunit.PrepareRequest{}}
+	err = toolbox.DefaultConverter.AssignConverted(request, map[string]interface{}{
+		"Datastore": "mydb5",
+		"Data": map[string]interface{}{
+			"users": []interface{}{map[string]interface{}{"id": 1, "name": "$userName"}},
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	if err = endly.Run(context, request, &PrepareResponse{}); !assert.Nil(t, err) {
+		return
+	}
+	response := &ExpectResponse{}
+	err = endly.Run(context, &ExpectRequest{
+		ExpectRequest: &dsunit.ExpectRequest{DatasetResource: &dsunit.DatasetResource{DatastoreDatasets: &dsunit.DatastoreDatasets{Datastore: "mydb5"}}},
+		Queries:       []*QueryExpect{{SQL: "SELECT name FROM users WHERE id = 1", Expect: "u-state"}},
+	}, response)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, response.PassedCount)
+		assert.Equal(t, 0, response.FailedCount)
+	}
+}
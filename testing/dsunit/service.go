@@ -5,7 +5,6 @@ import (
 	"github.com/viant/dsc"
 	"github.com/viant/dsunit"
 	"github.com/viant/endly"
-	"github.com/viant/endly/testing/validator"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
 )
@@ -173,19 +172,51 @@ const (
 		}
 	}`
 
+	dsunitParallelDataPrepareExample = `{
+    "Datastore": "db1",
+    "URL": "datastore/db1/data",
+    "Concurrency": 4,
+    "Dependencies": {
+      "orders": ["users", "products"],
+      "order_items": ["orders"]
+    }
+  }`
+
 	dsunitServiceExpectAction = `{
     "Datastore": "db1",
     "URL": "datastore/db1/use_case2/",
 	"Prefix":"expect_"
   }`
 	dsunitServiceMapping = `{"mappings":{"URL":"regression/db1/mapping.json"}}`
+
+	dsunitServiceMigrateExample = `{
+    "Datastore": "db1",
+    "Source": {
+      "URL": "datastore/db1/migration/"
+    }
+  }`
+
+	dsunitServiceSnapshotExample = `{
+    "Datastore": "db1",
+    "Tables": ["users", "accounts"],
+    "Dest": {
+      "URL": "gs://mybucket/snapshot/db1/"
+    }
+  }`
+
+	dsunitServiceRestoreExample = `{
+    "Datastore": "db1",
+    "Source": {
+      "URL": "gs://mybucket/snapshot/db1/"
+    }
+  }`
 )
 
 func expandTablesIfNeeded(context *endly.Context, req *InitRequest) {
 	if len(req.Tables) == 0 {
 		return
 	}
-	var state = context.State()
+	var state = context.SafeState()
 	for _, table := range req.Tables {
 		table.Table = state.ExpandAsText(table.Table)
 	}
@@ -414,18 +445,27 @@ func (s *service) registerRoutes() {
 					Description: "data prepare",
 					Data:        dsunitDataPrepareExaple,
 				},
+				{
+					Description: "parallel data prepare",
+					Data:        dsunitParallelDataPrepareExample,
+				},
 			},
 		},
 		RequestProvider: func() interface{} {
-			return &PrepareRequest{}
+			return &PrepareRequest{PrepareRequest: &dsunit.PrepareRequest{}}
 		},
 		ResponseProvider: func() interface{} {
 			return &PrepareResponse{}
 		},
 		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
 			if req, ok := request.(*PrepareRequest); ok {
-				var dsRequest = dsunit.PrepareRequest(*req)
-				request = &dsRequest
+				if req.PrepareRequest == nil {
+					req.PrepareRequest = &dsunit.PrepareRequest{}
+				}
+				if req.Concurrency > 1 {
+					return s.prepareConcurrently(context, req)
+				}
+				request = req.PrepareRequest
 			}
 
 			if req, ok := request.(*dsunit.PrepareRequest); ok {
@@ -453,37 +493,23 @@ func (s *service) registerRoutes() {
 			},
 		},
 		RequestProvider: func() interface{} {
-			return &dsunit.ExpectRequest{
-				DatasetResource: &dsunit.DatasetResource{
-					DatastoreDatasets: &dsunit.DatastoreDatasets{},
+			return &ExpectRequest{
+				ExpectRequest: &dsunit.ExpectRequest{
+					DatasetResource: &dsunit.DatasetResource{
+						DatastoreDatasets: &dsunit.DatastoreDatasets{},
+					},
 				},
 			}
 		},
 		ResponseProvider: func() interface{} {
-			return &dsunit.ExpectResponse{}
+			return &ExpectResponse{}
 		},
 		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
-			if req, ok := request.(*ExpectRequest); ok {
-				var dsRequest = dsunit.ExpectRequest(*req)
-				request = &dsRequest
-			}
-
 			if req, ok := request.(*dsunit.ExpectRequest); ok {
-				resp := s.Service.Expect(req)
-				response := ExpectResponse(*resp)
-
-				if len(response.Validation) > 0 {
-					for _, validation := range response.Validation {
-						context.Publish(&validator.AssertRequest{
-							Description: validation.Description,
-							Expected:    validation.Expected,
-							Actual:      validation.Actual,
-							Source:      validation.Dataset,
-						})
-					}
-				}
-
-				return &response, response.Error()
+				request = &ExpectRequest{ExpectRequest: req}
+			}
+			if req, ok := request.(*ExpectRequest); ok {
+				return s.expect(context, req)
 			}
 			return nil, fmt.Errorf("unsupported request type: %T", request)
 		},
@@ -666,20 +692,95 @@ func (s *service) registerRoutes() {
 			return nil, fmt.Errorf("unsupported request type: %T", request)
 		},
 	})
+
+	s.Register(&endly.Route{
+		Action: "migrate",
+		RequestInfo: &endly.ActionInfo{
+			Description: "apply pending schema migrations from SQL files directory",
+			Examples: []*endly.UseCase{
+				{
+					Description: "migrate",
+					Data:        dsunitServiceMigrateExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &MigrateRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &MigrateResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*MigrateRequest); ok {
+				return s.migrate(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "snapshot",
+		RequestInfo: &endly.ActionInfo{
+			Description: "capture tables content to storage URL",
+			Examples: []*endly.UseCase{
+				{
+					Description: "snapshot",
+					Data:        dsunitServiceSnapshotExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &SnapshotRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &SnapshotResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*SnapshotRequest); ok {
+				return s.snapshot(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action: "restore",
+		RequestInfo: &endly.ActionInfo{
+			Description: "restore tables content from snapshot",
+			Examples: []*endly.UseCase{
+				{
+					Description: "restore",
+					Data:        dsunitServiceRestoreExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &RestoreRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &RestoreResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*RestoreRequest); ok {
+				return s.restore(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
 }
 
 func expandConfigParameters(context *endly.Context, params map[string]interface{}) {
 	if len(params) == 0 {
 		return
 	}
-	state := context.State()
+	state := context.SafeState()
 	for i, param := range params {
 		params[i] = state.Expand(param)
 	}
 }
 
 func (s service) publishConfigParameters(context *endly.Context, config *dsc.Config) {
-	state := context.State()
+	state := context.SafeState()
 	var params = config.Parameters
 	if len(params) == 0 {
 		params = make(map[string]interface{})
@@ -688,8 +789,15 @@ func (s service) publishConfigParameters(context *endly.Context, config *dsc.Con
 }
 
 func (s *service) Run(context *endly.Context, request interface{}) *endly.ServiceResponse {
-	var state = context.State()
-	_ = context.Context.Replace(dsunit.SubstitutionMapKey, &state)
+	switch req := request.(type) {
+	case *dsunit.ExpectRequest:
+		request = &ExpectRequest{ExpectRequest: req}
+	case *dsunit.PrepareRequest:
+		request = &PrepareRequest{PrepareRequest: req}
+	}
+	context.SafeState().Read(func(state data.Map) { //dsunit expands datasets with the context state map
+		_ = context.Context.Replace(dsunit.SubstitutionMapKey, &state)
+	})
 	s.Service.SetContext(context.Context)
 	return s.AbstractService.Run(context, request)
 }
@@ -0,0 +1,130 @@
+package dsunit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/dsc"
+	"github.com/viant/dsunit"
+	dsurl "github.com/viant/dsunit/url"
+	"github.com/viant/endly"
+	estorage "github.com/viant/endly/system/storage"
+	"github.com/viant/toolbox"
+	"path"
+	"strings"
+)
+
+const snapshotExt = ".json"
+
+//snapshotTables returns request tables or all datastore tables
+func snapshotTables(manager dsc.Manager, tables []string) ([]string, error) {
+	if len(tables) > 0 {
+		return tables, nil
+	}
+	dialect := dsc.GetDatastoreDialect(manager.Config().DriverName)
+	if dialect == nil {
+		return nil, fmt.Errorf("unsupported dialect: %v", manager.Config().DriverName)
+	}
+	datastore, err := dialect.GetCurrentDatastore(manager)
+	if err != nil {
+		return nil, err
+	}
+	return dialect.GetTables(manager, datastore)
+}
+
+func (s *service) snapshot(context *endly.Context, request *SnapshotRequest) (*SnapshotResponse, error) {
+	var response = &SnapshotResponse{Tables: make(map[string]int)}
+	manager := s.Service.Registry().Get(request.Datastore)
+	if manager == nil {
+		return nil, fmt.Errorf("datastore %v was not registered", request.Datastore)
+	}
+	tables, err := snapshotTables(manager, request.Tables)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := estorage.StorageService(context, request.Dest)
+	if err != nil {
+		return nil, err
+	}
+	dest, storageOptions, err := estorage.GetResourceWithOptions(context, request.Dest)
+	if err != nil {
+		return nil, err
+	}
+	response.URL = dest.URL
+	for _, table := range tables {
+		var records = make([]map[string]interface{}, 0)
+		if err = manager.ReadAll(&records, fmt.Sprintf("SELECT * FROM %v", table), nil, nil); err != nil {
+			return nil, fmt.Errorf("failed to read %v: %v", table, err)
+		}
+		for _, record := range records {
+			for key, value := range record {
+				if bs, ok := value.([]byte); ok {
+					record[key] = string(bs)
+				} else if request.TimeLayout != "" && toolbox.IsTime(value) {
+					record[key] = toolbox.AsTime(value, "").Format(request.TimeLayout)
+				}
+			}
+		}
+		payload, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err = fs.Upload(context.Background(), toolbox.URLPathJoin(dest.URL, table+snapshotExt), 0644, bytes.NewReader(payload), storageOptions...); err != nil {
+			return nil, err
+		}
+		response.Tables[table] = len(records)
+	}
+	return response, nil
+}
+
+func (s *service) restore(context *endly.Context, request *RestoreRequest) (*RestoreResponse, error) {
+	var response = &RestoreResponse{}
+	fs, err := estorage.StorageService(context, request.Source)
+	if err != nil {
+		return nil, err
+	}
+	source, storageOptions, err := estorage.GetResourceWithOptions(context, request.Source)
+	if err != nil {
+		return nil, err
+	}
+	var selected = make(map[string]bool)
+	for _, table := range request.Tables {
+		selected[table] = true
+	}
+	objects, err := fs.List(context.Background(), source.URL, storageOptions...)
+	if err != nil {
+		return nil, err
+	}
+	var tableData = make(map[string][]map[string]interface{})
+	for _, object := range objects {
+		if object.IsDir() || path.Ext(object.Name()) != snapshotExt {
+			continue
+		}
+		table := strings.TrimSuffix(object.Name(), snapshotExt)
+		if len(selected) > 0 && !selected[table] {
+			continue
+		}
+		content, err := fs.Download(context.Background(), object, storageOptions...)
+		if err != nil {
+			return nil, err
+		}
+		var records = make([]map[string]interface{}, 0)
+		if err = json.Unmarshal(content, &records); err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot %v: %v", object.URL(), err)
+		}
+		//empty leading record deletes all table rows before insert
+		tableData[table] = append([]map[string]interface{}{{}}, records...)
+	}
+	if len(tableData) == 0 {
+		return nil, fmt.Errorf("no table snapshot found in %v", source.URL)
+	}
+	prepareRequest := &dsunit.PrepareRequest{
+		DatasetResource: &dsunit.DatasetResource{
+			Resource:          &dsurl.Resource{},
+			DatastoreDatasets: &dsunit.DatastoreDatasets{Datastore: request.Datastore, Data: tableData},
+		},
+	}
+	prepareResponse := s.Service.Prepare(prepareRequest)
+	response.Modification = prepareResponse.Modification
+	return response, prepareResponse.Error()
+}
@@ -0,0 +1,74 @@
+package dsunit
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/dsc"
+	"github.com/viant/dsunit"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestService_SnapshotRestore(t *testing.T) {
+	var baseDir = path.Join(os.TempDir(), "test/endly/dsunit/snapshot")
+	_ = os.RemoveAll(baseDir)
+	_ = toolbox.CreateDirIfNotExist(baseDir)
+	config, err := dsc.NewConfigWithParameters("sqlite3", "[url]", "", map[string]interface{}{
+		"url": path.Join(baseDir, "mydb4"),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	context := endly.New().NewContext(nil)
+	registerRequest := RegisterRequest(*dsunit.NewRegisterRequest("mydb4", config))
+	if err = endly.Run(context, &registerRequest, &RegisterResponse{}); !assert.Nil(t, err) {
+		return
+	}
+	err = endly.Run(context, &MigrateRequest{Datastore: "mydb4", Source: url.NewResource("test/migration")}, &MigrateResponse{})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	createRequest := RunSQLRequest{Datastore: "mydb4", SQL: []string{"CREATE TABLE category(id INTEGER NOT NULL PRIMARY KEY, name VARCHAR(255))"}}
+	if err = endly.Run(context, &createRequest, &RunSQLResponse{}); !assert.Nil(t, err) {
+		return
+	}
+	snapshotResponse := &SnapshotResponse{}
+	err = endly.Run(context, &SnapshotRequest{Datastore: "mydb4", Tables: []string{"product", "category"}, Dest: url.NewResource(path.Join(baseDir, "snapshot"))}, snapshotResponse)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]int{"product": 2, "category": 0}, snapshotResponse.Tables)
+	assert.True(t, toolbox.FileExists(path.Join(baseDir, "snapshot", "product.json")))
+
+	runSQLRequest := RunSQLRequest{Datastore: "mydb4", SQL: []string{"DELETE FROM product WHERE id = 1", "INSERT INTO product(id, name, price) VALUES(3, 'p3', 1.0)", "INSERT INTO category(id, name) VALUES(1, 'c1')"}}
+	if err = endly.Run(context, &runSQLRequest, &RunSQLResponse{}); !assert.Nil(t, err) {
+		return
+	}
+
+	restoreResponse := &RestoreResponse{}
+	err = endly.Run(context, &RestoreRequest{Datastore: "mydb4", Source: url.NewResource(path.Join(baseDir, "snapshot"))}, restoreResponse)
+	if !assert.Nil(t, err) {
+		return
+	}
+	if assert.NotNil(t, restoreResponse.Modification["product"]) {
+		assert.Equal(t, 2, restoreResponse.Modification["product"].Deleted)
+		assert.Equal(t, 2, restoreResponse.Modification["product"].Added)
+	}
+	if assert.NotNil(t, restoreResponse.Modification["category"]) {
+		assert.Equal(t, 1, restoreResponse.Modification["category"].Deleted)
+	}
+	queryResponse := &QueryResponse{}
+	err = endly.Run(context, &QueryRequest{Datastore: "mydb4", SQL: "SELECT id, name FROM product ORDER BY id"}, queryResponse)
+	if assert.Nil(t, err) && assert.Equal(t, 2, len(queryResponse.Records)) {
+		assert.EqualValues(t, 1, toolbox.AsInt(queryResponse.Records[0]["id"]))
+		assert.EqualValues(t, "p2", queryResponse.Records[1]["name"])
+	}
+
+	err = endly.Run(context, &RestoreRequest{Datastore: "mydb4", Source: url.NewResource(path.Join(baseDir, "snapshot")), Tables: []string{"unknown"}}, &RestoreResponse{})
+	assert.NotNil(t, err)
+}
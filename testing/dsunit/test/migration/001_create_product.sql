@@ -0,0 +1,4 @@
+CREATE TABLE product (
+  id INTEGER NOT NULL PRIMARY KEY,
+  name VARCHAR(255)
+);
@@ -0,0 +1 @@
+ALTER TABLE product ADD COLUMN price DECIMAL(7,2) NOT NULL DEFAULT 0 AFTER name;
@@ -0,0 +1 @@
+ALTER TABLE product ADD COLUMN price REAL;
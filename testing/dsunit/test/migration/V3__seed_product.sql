@@ -0,0 +1,2 @@
+INSERT INTO product(id, name, price) VALUES(1, 'p1', 10.5);
+INSERT INTO product(id, name, price) VALUES(2, 'p2', 3.5);
@@ -8,7 +8,7 @@ import (
 func (s *service) append(context *endly.Context, req *AppendRequest) (*AppendResponse, error) {
 	resp := &AppendResponse{}
 	server := s.servers[req.Port]
-	state := context.State()
+	state := context.SafeState()
 	if req.BaseDirectory != "" {
 		req.BaseDirectory = url.NewResource(state.ExpandAsText(req.BaseDirectory)).ParsedURL.Path
 	}
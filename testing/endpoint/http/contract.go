@@ -13,6 +13,7 @@ type ListenRequest struct {
 	ResponseTemplate string   `description:"response file loading template, default: %02d-resp.json"`
 	BaseDirectory    string   `required:"true" description:"location with replay files (could be generate by https://github.com/viant/toolbox/blob/master/bridge/http_bridge_recording_util.go#L81"`
 	IndexKeys        []string `description:"recorded requests matching keys, by default: Method,URL,Body,Cookie,Content-Type"`
+	Routes           []*Route `description:"programmable routes, the first matching route computes response from the request, recorded trips are used if no route matches"`
 }
 
 //ListenResponse represents HTTP endpoint listen response with indexed trips
@@ -35,6 +36,11 @@ func (r ListenRequest) Validate() error {
 	if r.Port == 0 {
 		return errors.New("port was empty")
 	}
+	for _, route := range r.Routes {
+		if err := route.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -48,6 +54,7 @@ func (r ListenRequest) AsHTTPServerTrips() *HTTPServerTrips {
 		BaseDirectory: r.BaseDirectory,
 		Trips:         make(map[string]*HTTPResponses),
 		IndexKeys:     r.IndexKeys,
+		Routes:        r.Routes,
 		Mutex:         &sync.Mutex{},
 	}
 }
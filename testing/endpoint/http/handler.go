@@ -36,6 +36,12 @@ func getServerHandler(httpServer *http.Server, httpHandler *httpHandler, trips *
 		if atomic.LoadInt32(&httpHandler.running) == 0 {
 			return
 		}
+		if handled, err := trips.routeResponse(writer, request); handled || err != nil {
+			if err != nil {
+				http.Error(writer, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			}
+			return
+		}
 
 		var key, err = buildKeyValue(trips.IndexKeys, request)
 		if err != nil {
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+//Route represents programmable mock route, response is computed from the incoming request
+type Route struct {
+	Method   string         `description:"HTTP method, any if empty"`
+	URI      string         `required:"true" description:"request path, {name} segment is extracted to $request.Params.name, trailing * matches any path suffix"`
+	When     string         `description:"criteria evaluated with $request and $counter state, route is used if true, i.e. $request.JSON.qty > 10"`
+	Response *RouteResponse `required:"true" description:"response template"`
+	counter  uint32
+}
+
+//RouteResponse represents route response template, $request, $counter and listen action state are expanded
+type RouteResponse struct {
+	Code     int `description:"status code, default 200"`
+	Header   map[string]string
+	Body     string
+	JSONBody interface{} `description:"body JSON representation, content type defaults to application/json"`
+}
+
+//Validate checks if route is valid
+func (r *Route) Validate() error {
+	if r.URI == "" {
+		return errors.New("uri was empty")
+	}
+	if r.Response == nil {
+		return fmt.Errorf("%v: response was empty", r.URI)
+	}
+	return nil
+}
+
+//match returns path parameters and true if request method and path match the route
+func (r *Route) match(method, URLPath string) (map[string]interface{}, bool) {
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return nil, false
+	}
+	var params = make(map[string]interface{})
+	routeFragments := strings.Split(strings.Trim(r.URI, "/"), "/")
+	pathFragments := strings.Split(strings.Trim(URLPath, "/"), "/")
+	for i, fragment := range routeFragments {
+		if i >= len(pathFragments) {
+			return nil, false
+		}
+		if fragment == "*" && i == len(routeFragments)-1 {
+			return params, true
+		}
+		if strings.HasPrefix(fragment, "{") && strings.HasSuffix(fragment, "}") {
+			params[fragment[1:len(fragment)-1]] = pathFragments[i]
+			continue
+		}
+		if fragment != pathFragments[i] {
+			return nil, false
+		}
+	}
+	return params, len(routeFragments) == len(pathFragments)
+}
+
+//asRouteRequest returns request state representation
+func asRouteRequest(request *http.Request, body []byte, params map[string]interface{}) map[string]interface{} {
+	var query = make(map[string]interface{})
+	for key := range request.URL.Query() {
+		query[key] = request.URL.Query().Get(key)
+	}
+	var header = make(map[string]interface{})
+	for key := range request.Header {
+		header[key] = request.Header.Get(key)
+	}
+	var result = map[string]interface{}{
+		"Method": request.Method,
+		"URL":    request.URL.String(),
+		"Path":   request.URL.Path,
+		"Query":  query,
+		"Header": header,
+		"Body":   string(body),
+		"Params": params,
+	}
+	if toolbox.IsStructuredJSON(string(body)) {
+		result["JSON"], _ = toolbox.JSONToInterface(string(body))
+	}
+	return result
+}
+
+//routeResponse writes response of the first matching route, it returns false if no route matched
+func (t *HTTPServerTrips) routeResponse(writer http.ResponseWriter, request *http.Request) (bool, error) {
+	if len(t.Routes) == 0 {
+		return false, nil
+	}
+	var body []byte
+	if request.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(request.Body); err != nil {
+			return false, err
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	for _, route := range t.Routes {
+		params, ok := route.match(request.Method, request.URL.Path)
+		if !ok {
+			continue
+		}
+		var state = data.NewMap()
+		for key, value := range t.State {
+			state[key] = value
+		}
+		state.Put("request", asRouteRequest(request, body, params))
+		state.Put("counter", int(atomic.AddUint32(&route.counter, 1)))
+		if canUse, err := criteria.Evaluate(nil, state, route.When, "Route.When", true); err != nil {
+			return false, fmt.Errorf("failed to evaluate %v: %v, %v", route.URI, route.When, err)
+		} else if !canUse {
+			continue
+		}
+		return true, writeRouteResponse(writer, route.Response, state)
+	}
+	return false, nil
+}
+
+func writeRouteResponse(writer http.ResponseWriter, response *RouteResponse, state data.Map) error {
+	var body = state.ExpandAsText(response.Body)
+	if response.JSONBody != nil {
+		var err error
+		if body, err = toolbox.AsJSONText(state.Expand(response.JSONBody)); err != nil {
+			return err
+		}
+		body = strings.TrimSpace(body)
+		writer.Header().Set("Content-Type", "application/json")
+	}
+	for key, value := range response.Header {
+		writer.Header().Set(key, state.ExpandAsText(value))
+	}
+	code := response.Code
+	if code == 0 {
+		code = http.StatusOK
+	}
+	writer.WriteHeader(code)
+	_, err := writer.Write([]byte(body))
+	return err
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"fmt"
+	"github.com/viant/toolbox/data"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -18,6 +19,8 @@ type Server struct {
 	http.Server
 	*httpHandler
 	trips            map[string]*HTTPResponses
+	routes           []*Route
+	state            data.Map
 	mux              sync.Mutex
 	rotate           bool
 	indexKeys        []string
@@ -36,6 +39,8 @@ func (s *Server) Append(trips *HTTPServerTrips) {
 			trips.Trips[k] = v
 		}
 	}
+	trips.Routes = s.routes
+	trips.State = s.state
 	s.httpHandler.handler = getServerHandler(&s.Server, s.httpHandler, trips)
 }
 
@@ -55,6 +60,8 @@ func StartServer(port int, trips *HTTPServerTrips, reqTemplate, respTemplate str
 		indexKeys:        trips.IndexKeys,
 		httpHandler:      httpHandler,
 		trips:            trips.Trips,
+		routes:           trips.Routes,
+		state:            trips.State,
 		Server:           http.Server{Addr: fmt.Sprintf(":%v", port), Handler: httpHandler},
 		requestTemplate:  reqTemplate,
 		responseTemplate: respTemplate,
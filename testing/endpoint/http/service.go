@@ -30,7 +30,7 @@ func (s *service) shutdown(context *endly.Context, req *ShutdownRequest) (interf
 }
 
 func (s *service) listen(context *endly.Context, request *ListenRequest) (*ListenResponse, error) {
-	state := context.State()
+	state := context.SafeState()
 	if request.BaseDirectory != "" {
 		request.BaseDirectory = url.NewResource(state.ExpandAsText(request.BaseDirectory)).ParsedURL.Path
 	}
@@ -46,6 +46,7 @@ func (s *service) listen(context *endly.Context, request *ListenRequest) (*Liste
 		}
 	}
 	trips := request.AsHTTPServerTrips()
+	trips.State = state.Clone()
 
 	server, err := StartServer(request.Port, trips, request.RequestTemplate, request.ResponseTemplate)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"github.com/viant/endly"
 	endpoint "github.com/viant/endly/testing/endpoint/http"
 	"github.com/viant/toolbox"
+	"io/ioutil"
 	"net/http"
 	"path"
 	"strings"
@@ -68,3 +69,80 @@ func TestHTTPEndpointService_Run_WithError(t *testing.T) {
 	}
 
 }
+
+func TestHTTPEndpointService_Routes(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	context := endly.New().NewContext(nil)
+	state := context.State()
+	state.Put("version", "v1")
+	err := endly.Run(context, &endpoint.ListenRequest{
+		BaseDirectory:    path.Join(parent, "test", "send"),
+		Port:             7719,
+		RequestTemplate:  "bridge.HttpRequest-%v.json",
+		ResponseTemplate: "bridge.HttpResponse-%v.json",
+		Routes: []*endpoint.Route{
+			{
+				Method: "GET",
+				URI:    "/users/{id}",
+				Response: &endpoint.RouteResponse{
+					Header:   map[string]string{"X-Version": "$version"},
+					JSONBody: map[string]interface{}{"id": "$request.Params.id", "page": "$request.Query.page", "call": "$counter"},
+				},
+			},
+			{
+				Method:   "POST",
+				URI:      "/orders",
+				When:     "$request.JSON.qty > 10",
+				Response: &endpoint.RouteResponse{Code: 422, Body: "too many: $request.JSON.qty"},
+			},
+			{
+				Method:   "POST",
+				URI:      "/orders",
+				When:     "$counter <= 1",
+				Response: &endpoint.RouteResponse{Code: 503, Body: "retry"},
+			},
+			{
+				Method:   "POST",
+				URI:      "/orders/*",
+				Response: &endpoint.RouteResponse{Code: 201, Body: "$request.Body"},
+			},
+			{
+				URI:      "/orders",
+				Response: &endpoint.RouteResponse{Code: 201, Body: "created $request.JSON.qty"},
+			},
+		},
+	}, &endpoint.ListenResponse{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var send = func(method, URL, body string) (int, string, http.Header) {
+		request, _ := http.NewRequest(method, "http://127.0.0.1:7719"+URL, strings.NewReader(body))
+		response, err := http.DefaultClient.Do(request)
+		if !assert.Nil(t, err) {
+			return 0, "", nil
+		}
+		defer response.Body.Close()
+		content, _ := ioutil.ReadAll(response.Body)
+		return response.StatusCode, string(content), response.Header
+	}
+	code, body, header := send("GET", "/users/7?page=2", "")
+	assert.Equal(t, 200, code)
+	assert.EqualValues(t, `{"call":1,"id":"7","page":"2"}`, body)
+	assert.Equal(t, "v1", header.Get("X-Version"))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+
+	code, body, _ = send("POST", "/orders", `{"qty":20}`)
+	assert.Equal(t, 422, code)
+	assert.Equal(t, "too many: 20", body)
+	code, _, _ = send("POST", "/orders", `{"qty":1}`)
+	assert.Equal(t, 503, code)
+	code, body, _ = send("POST", "/orders", `{"qty":1}`)
+	assert.Equal(t, 201, code)
+	assert.Equal(t, "created 1", body)
+	code, body, _ = send("POST", "/orders/1/items", `abc`)
+	assert.Equal(t, 201, code)
+	assert.Equal(t, "abc", body)
+
+	code, _, _ = send("POST", "/send1", "0123456789") //falls back to recorded trips
+	assert.Equal(t, 200, code)
+}
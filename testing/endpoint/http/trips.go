@@ -3,6 +3,7 @@ package http
 import (
 	"fmt"
 	"github.com/viant/toolbox/bridge"
+	"github.com/viant/toolbox/data"
 	"sync"
 )
 
@@ -12,6 +13,8 @@ type HTTPServerTrips struct {
 	Rotate        bool
 	Trips         map[string]*HTTPResponses
 	IndexKeys     []string
+	Routes        []*Route
+	State         data.Map
 	Mutex         *sync.Mutex
 }
 
@@ -13,7 +13,7 @@ type ListenRequest struct {
 	Port         int
 	EnableTLS    bool
 	MaxBodySize  int
-	Users        []*User
+	Users        []*User `description:"authenticated users, if empty endpoint accepts anonymous mails only"`
 	CertLocation string
 	Debug        bool
 }
@@ -46,9 +46,6 @@ func (r *ListenRequest) Init() error {
 }
 
 func (r *ListenRequest) Validate() error {
-	if len(r.Users) == 0 {
-		return fmt.Errorf("users were empty")
-	}
 	for i, user := range r.Users {
 		if user.Password == "" {
 			return fmt.Errorf("users[%d].Password was empty", i)
@@ -62,14 +59,17 @@ type ListenResponse struct{}
 
 //UserMessage represents desired user message
 type UserMessage struct {
-	User    string
-	TagID   string
-	Message interface{}
+	User      string
+	Recipient string `description:"recipient address, matches the first mail sent to the address regardless of the authenticated user"`
+	TagID     string
+	Message   interface{}
 }
 
 //AssertRequest represents a log assert request
 type AssertRequest struct {
 	DescriptionTemplate string
+	WaitTimeMs          int            `description:"wait time before next attempt to find expected mail, default 500"`
+	WaitRetryCount      int            `description:"max number of attempts to find expected mail, default 3"`
 	Expect              []*UserMessage `required:"true" description:"expected user messagesByUser"`
 }
 
@@ -77,6 +77,12 @@ func (r *AssertRequest) Init() error {
 	if r.DescriptionTemplate == "" {
 		r.DescriptionTemplate = "Message Validation: $user"
 	}
+	if r.WaitTimeMs == 0 {
+		r.WaitTimeMs = 500
+	}
+	if r.WaitRetryCount == 0 {
+		r.WaitRetryCount = 3
+	}
 	return nil
 }
 
@@ -47,8 +47,8 @@ func TestListenRequest_Init(t *testing.T) {
 
 func TestListenRequest_Validate(t *testing.T) {
 	{
-		listen := &ListenRequest{}
-		assert.NotNil(t, listen.Validate())
+		listen := &ListenRequest{} //anonymous mails only
+		assert.Nil(t, listen.Validate())
 	}
 	{
 		listen := &ListenRequest{
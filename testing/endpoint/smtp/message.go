@@ -1,22 +1,101 @@
 package smtp
 
 import (
+	"bytes"
+	"encoding/base64"
 	"io"
 	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
 	"strings"
 )
 
 //Message represent an email
 type Message struct {
-	From    string
-	To      []string
-	Subject string
-	Header  map[string]string
-	Raw     string
-	Body    string
+	From        string
+	To          []string
+	Cc          []string `description:"carbon copy recipients from message header"`
+	Subject     string
+	Header      map[string]string
+	Raw         string
+	Body        string `description:"plain text body"`
+	HTML        string `description:"html body if message is multipart"`
+	Attachments []string
+	sequence    int
 }
 
+//Decode decodes raw message header and body, multipart message text and html parts are decoded, other parts are listed as attachments
 func (m *Message) Decode() {
+	message, err := mail.ReadMessage(strings.NewReader(m.Raw))
+	if err != nil {
+		m.decodeLines()
+		return
+	}
+	decoder := new(mime.WordDecoder)
+	for key := range message.Header {
+		value := message.Header.Get(key)
+		if decoded, err := decoder.DecodeHeader(value); err == nil {
+			value = decoded
+		}
+		m.Header[key] = value
+	}
+	m.Subject = m.Header["Subject"]
+	if addresses, err := message.Header.AddressList("Cc"); err == nil {
+		for _, address := range addresses {
+			m.Cc = append(m.Cc, address.Address)
+		}
+	} else if cc := m.Header["Cc"]; cc != "" {
+		for _, address := range strings.FieldsFunc(cc, func(r rune) bool { return r == ',' || r == ';' }) {
+			m.Cc = append(m.Cc, strings.TrimSpace(address))
+		}
+	}
+	m.decodePart(message.Header.Get("Content-Type"), message.Header.Get("Content-Transfer-Encoding"), "", message.Body)
+}
+
+func (m *Message) decodePart(contentType, encoding, disposition string, reader io.Reader) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+		if mediaType == "" {
+			mediaType = "text/plain"
+		}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		parts := multipart.NewReader(reader, params["boundary"])
+		for {
+			part, err := parts.NextPart()
+			if err != nil {
+				return
+			}
+			m.decodePart(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part.Header.Get("Content-Disposition"), part)
+		}
+	}
+	if _, dispositionParams, err := mime.ParseMediaType(disposition); err == nil && strings.HasPrefix(disposition, "attachment") {
+		m.Attachments = append(m.Attachments, dispositionParams["filename"])
+		return
+	}
+	switch strings.ToLower(encoding) {
+	case "quoted-printable":
+		reader = quotedprintable.NewReader(reader)
+	case "base64":
+		reader = base64.NewDecoder(base64.StdEncoding, reader)
+	}
+	content, _ := ioutil.ReadAll(reader)
+	content = bytes.Replace(content, []byte("\r\n"), []byte("\n"), -1)
+	switch {
+	case mediaType == "text/plain" && m.Body == "":
+		m.Body = string(content)
+	case mediaType == "text/html" && m.HTML == "":
+		m.HTML = string(content)
+	default:
+		m.Attachments = append(m.Attachments, params["name"])
+	}
+}
+
+//decodeLines decodes message without valid header
+func (m *Message) decodeLines() {
 	lines := strings.Split(m.Raw, "\n")
 	for i, line := range lines {
 		pair := strings.SplitN(line, ":", 2)
@@ -33,6 +112,19 @@ func (m *Message) Decode() {
 	}
 }
 
+//HasRecipient returns true if address is message envelope or carbon copy recipient
+func (m *Message) HasRecipient(address string) bool {
+	for _, candidate := range append(append([]string{}, m.To...), m.Cc...) {
+		if parsed, err := mail.ParseAddress(candidate); err == nil {
+			candidate = parsed.Address
+		}
+		if strings.EqualFold(candidate, address) {
+			return true
+		}
+	}
+	return false
+}
+
 func NewMessage(from string, to []string, reader io.Reader) (*Message, error) {
 	result := &Message{
 		From:   from,
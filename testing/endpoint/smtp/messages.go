@@ -12,9 +12,10 @@ import (
 //Messages represents a FIFO message collection grouped  by user
 type Messages struct {
 	*sync.Mutex
-	byUser  map[string][]*Message
-	debug   bool
-	context *endly.Context
+	byUser   map[string][]*Message
+	sequence int
+	debug    bool
+	context  *endly.Context
 }
 
 //Push appends a message by user
@@ -28,6 +29,8 @@ func (m *Messages) Push(username string, message *Message) {
 			Message: fmt.Sprintf("push [%v] <- %v", username, info),
 		}, nil)
 	}
+	m.sequence++
+	message.sequence = m.sequence
 	_, ok := m.byUser[username]
 	if !ok {
 		m.byUser[username] = make([]*Message, 0)
@@ -48,6 +51,38 @@ func (m *Messages) Shift(username string) *Message {
 	}
 	message := messages[0]
 	m.byUser[username] = messages[1:]
+	m.printShifted(username, message)
+	return message
+}
+
+//ShiftByRecipient removes first placed message sent to supplied recipient address, regardless of the authenticated user
+func (m *Messages) ShiftByRecipient(address string) *Message {
+	m.Lock()
+	defer m.Unlock()
+	var username string
+	var index = -1
+	for candidate, messages := range m.byUser {
+		for i, message := range messages {
+			if !message.HasRecipient(address) {
+				continue
+			}
+			if index == -1 || message.sequence < m.byUser[username][index].sequence {
+				username, index = candidate, i
+			}
+			break
+		}
+	}
+	if index == -1 {
+		return nil
+	}
+	messages := m.byUser[username]
+	message := messages[index]
+	m.byUser[username] = append(messages[:index:index], messages[index+1:]...)
+	m.printShifted(username, message)
+	return message
+}
+
+func (m *Messages) printShifted(username string, message *Message) {
 	if m.debug {
 		info, _ := toolbox.AsJSONText(message)
 		_ = endly.Run(m.context, &workflow.PrintRequest{
@@ -55,7 +90,6 @@ func (m *Messages) Shift(username string) *Message {
 			Message: fmt.Sprintf("shift [%v] -> %v", username, info),
 		}, nil)
 	}
-	return message
 }
 
 //NewMessages returns a new FIFO message collection by user
@@ -77,8 +77,12 @@ func (s *service) assert(context *endly.Context, request *AssertRequest) (*Asser
 
 	var messageCount = map[string]int{}
 	for _, userMessage := range request.Expect {
+		owner := userMessage.User
+		if userMessage.Recipient != "" {
+			owner = userMessage.Recipient
+		}
 		var aMap = data.NewMap()
-		aMap.Put("user", userMessage.User)
+		aMap.Put("user", owner)
 		aMap.Put("TagID", userMessage.TagID)
 
 		var validation = &assertly.Validation{
@@ -86,10 +90,10 @@ func (s *service) assert(context *endly.Context, request *AssertRequest) (*Asser
 			Description: aMap.ExpandAsText(request.DescriptionTemplate),
 		}
 		response.Validations = append(response.Validations, validation)
-		messageCount[userMessage.User]++
-		actualMessage := s.messages.Shift(userMessage.User)
+		messageCount[owner]++
+		actualMessage := s.shiftMessage(context, userMessage, request)
 		if actualMessage == nil {
-			validation.AddFailure(assertly.NewFailure("", fmt.Sprintf("[%v]", userMessage.TagID), fmt.Sprintf("missing mail,  user %v ", userMessage.User), userMessage.Message, nil))
+			validation.AddFailure(assertly.NewFailure("", fmt.Sprintf("[%v]", userMessage.TagID), fmt.Sprintf("missing mail,  user %v ", owner), userMessage.Message, nil))
 			break
 		}
 		taggedAssert := &validator.TaggedAssert{
@@ -97,7 +101,7 @@ func (s *service) assert(context *endly.Context, request *AssertRequest) (*Asser
 			Expected: userMessage.Message,
 			Actual:   actualMessage,
 		}
-		messageValidation, err := criteria.Assert(context, fmt.Sprintf("mail(%v[%v])", userMessage.User, messageCount[userMessage.User]-1), taggedAssert.Expected, taggedAssert.Actual)
+		messageValidation, err := criteria.Assert(context, fmt.Sprintf("mail(%v[%v])", owner, messageCount[owner]-1), taggedAssert.Expected, taggedAssert.Actual)
 		if err != nil {
 			return nil, err
 		}
@@ -108,6 +112,22 @@ func (s *service) assert(context *endly.Context, request *AssertRequest) (*Asser
 	return response, nil
 }
 
+//shiftMessage returns expected user or recipient mail, it waits for the mail if needed
+func (s *service) shiftMessage(context *endly.Context, userMessage *UserMessage, request *AssertRequest) *Message {
+	for i := 0; ; i++ {
+		var result *Message
+		if userMessage.Recipient != "" {
+			result = s.messages.ShiftByRecipient(userMessage.Recipient)
+		} else {
+			result = s.messages.Shift(userMessage.User)
+		}
+		if result != nil || i+1 >= request.WaitRetryCount {
+			return result
+		}
+		s.Sleep(context, request.WaitTimeMs)
+	}
+}
+
 func (s *service) registerRoutes() {
 	//listen action route
 	s.Register(&endly.Route{
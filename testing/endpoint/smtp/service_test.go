@@ -8,7 +8,9 @@ import (
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/url"
 	"log"
+	nsmtp "net/smtp"
 	"path"
+	"strings"
 	"testing"
 	"time"
 )
@@ -73,3 +75,77 @@ func Test_Listen(t *testing.T) {
 		log.Printf("%v", resp.Validations[0].Report())
 	}
 }
+
+func Test_ListenAnonymous(t *testing.T) {
+	context := endly.New().NewContext(nil)
+	err := endly.Run(context, &ListenRequest{Port: 1588}, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	time.Sleep(500 * time.Millisecond)
+	var mail = strings.Join([]string{
+		"From: app@localhost",
+		"To: alice@localhost",
+		"Cc: Bob <bob@localhost>",
+		"Subject: =?UTF-8?Q?Order_confirmed?=",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/mixed; boundary="b1"`,
+		"",
+		"--b1",
+		`Content-Type: text/plain; charset="UTF-8"`,
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"Your order #123 =3D confirmed",
+		"--b1",
+		"Content-Type: text/html",
+		"",
+		"<b>Your order #123</b>",
+		"--b1",
+		"Content-Type: application/pdf",
+		`Content-Disposition: attachment; filename="invoice.pdf"`,
+		"Content-Transfer-Encoding: base64",
+		"",
+		"JVBERi0=",
+		"--b1--",
+		"",
+	}, "\r\n")
+	go func() {
+		time.Sleep(700 * time.Millisecond) //assert waits for the mail
+		err := nsmtp.SendMail("localhost:1588", nil, "app@localhost", []string{"alice@localhost", "bob@localhost"}, []byte(mail))
+		assert.Nil(t, err)
+	}()
+	resp := &AssertResponse{}
+	err = endly.Run(context, &AssertRequest{
+		WaitTimeMs:     300,
+		WaitRetryCount: 10,
+		Expect: []*UserMessage{
+			{
+				Recipient: "bob@localhost",
+				Message: map[string]interface{}{
+					"Subject":     "Order confirmed",
+					"Cc":          []interface{}{"bob@localhost"},
+					"Body":        "Your order #123 = confirmed",
+					"HTML":        "/order #123/",
+					"Attachments": []interface{}{"invoice.pdf"},
+				},
+			},
+		},
+	}, resp)
+	if !assert.Nil(t, err) || !assert.Equal(t, 1, len(resp.Validations)) {
+		return
+	}
+	assert.Equal(t, 5, resp.Validations[0].PassedCount)
+	if !assert.Equal(t, 0, resp.Validations[0].FailedCount) {
+		log.Printf("%v", resp.Validations[0].Report())
+	}
+
+	resp = &AssertResponse{}
+	err = endly.Run(context, &AssertRequest{
+		WaitTimeMs:     10,
+		WaitRetryCount: 2,
+		Expect:         []*UserMessage{{Recipient: "alice@localhost", Message: map[string]interface{}{"Subject": "Order confirmed"}}},
+	}, resp)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(resp.Validations)) {
+		assert.Equal(t, 1, resp.Validations[0].FailedCount, "mail was already shifted")
+	}
+}
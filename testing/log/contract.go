@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"github.com/viant/assertly"
 	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
 	"github.com/viant/toolbox/url"
 	"regexp"
+	"strings"
 )
 
 //AssertRequest represents a log assert request
@@ -23,6 +25,9 @@ func (r *AssertRequest) Init() error {
 		return nil
 	}
 	for _, expecRecords := range r.Expect {
+		if expecRecords.Match == "" {
+			expecRecords.Match = MatchOrdered
+		}
 		if len(expecRecords.Records) == 0 {
 			continue
 		}
@@ -55,6 +60,14 @@ func (r *AssertRequest) Validate() error {
 		if expecRecords.Type == "" {
 			return fmt.Errorf("Expect[%d].Type was empty", i)
 		}
+		switch expecRecords.Match {
+		case "", MatchOrdered, MatchUnordered, MatchSubset:
+		default:
+			return fmt.Errorf("Expect[%d].Match unsupported policy: %v", i, expecRecords.Match)
+		}
+		if expecRecords.Absent && len(expecRecords.Capture) > 0 {
+			return fmt.Errorf("Expect[%d].Capture is not supported with absent records", i)
+		}
 	}
 	return nil
 }
@@ -64,11 +77,24 @@ type TypedRecord struct {
 	TagID   string `description:"neatly tag id for matching validation summary"`
 	Type    string `required:"true" description:"log type register with listener"`
 	Records []interface{}
+	Absent  bool              `description:"if set, records represent unwanted records that can not appear in the log type within LogWaitTimeMs * LogWaitRetryCount window"`
+	Match   string            `description:"matching policy: ordered (default) - records are matched in arrival order, unordered - any pending record can match, but all pending records have to match, subset - any pending record can match, other pending records are ignored"`
+	Capture map[string]string `description:"optional state variable name to matched record field path map, i.e. txID: transaction.id, field values are captured from matched records parsed with log type format, the last matched record wins"`
 }
 
+const (
+	//MatchOrdered represents arrival order records matching policy
+	MatchOrdered = "ordered"
+	//MatchUnordered represents any order records matching policy, unmatched pending records are reported
+	MatchUnordered = "unordered"
+	//MatchSubset represents any order records matching policy, unmatched pending records are ignored
+	MatchSubset = "subset"
+)
+
 //AssertResponse represents a log assert response
 type AssertResponse struct {
 	Validations []*assertly.Validation
+	Captured    map[string]interface{} `json:",omitempty" description:"values captured from matched records, also placed in workflow state"`
 }
 
 //Assertion returns description with validation slice
@@ -78,22 +104,63 @@ func (r *AssertResponse) Assertion() []*assertly.Validation {
 
 //Type represents  a log type
 type Type struct {
-	Name         string `required:"true" description:"log type name"`
-	Format       string `description:"log format"`
-	Mask         string `description:"expected log file mast"`
-	Exclusion    string `description:"if specified, exclusion fragment can not match log record"`
-	Inclusion    string `description:"if specified, inclusion fragment must match log record"`
-	IndexRegExpr string `description:"provide expression for indexing log messages, in this case position based logging will not apply"` //provide expression for indexing log message, in this case position based logging will not apply
-	indexExpr    *regexp.Regexp
-	UDF          string `description:"registered user defined function to transform content file before applying validation"`
-	Debug        bool   `description:"if set, every record appended to validation queue will be listed"`
+	Name          string   `required:"true" description:"log type name"`
+	Format        string   `description:"log format: json|csv|logfmt|regexp|grok, default json"`
+	Columns       []string `description:"csv format column names"`
+	Delimiter     string   `description:"csv format delimiter, default ','"`
+	Pattern       string   `description:"regexp format expression with named capture groups i.e. (?P<level>\\w+), or grok format pattern i.e. %{LOGLEVEL:level} %{GREEDYDATA:message}"`
+	parser        Parser
+	Mask          string `description:"expected log file mast"`
+	Exclusion     string `description:"if specified, exclusion fragment can not match log record"`
+	Inclusion     string `description:"if specified, inclusion fragment must match log record"`
+	IndexRegExpr  string `description:"provide expression for indexing log messages, in this case position based logging will not apply"` //provide expression for indexing log message, in this case position based logging will not apply
+	indexExpr     *regexp.Regexp
+	IndexRegExprs []string `description:"composite index expressions, each expression first capture group is a part of record index, i.e. EventID and ShardID"`
+	IndexPaths    []string `description:"composite index record field paths, i.e. event.id, applied to records parsed with log type format"`
+	indexExprs    []*regexp.Regexp
+	UDF           string `description:"registered user defined function to transform content file before applying validation"`
+	Debug         bool   `description:"if set, every record appended to validation queue will be listed"`
+	watchdog      *watchdog
 }
 
 //ListenRequest represents listen for a logs request.
 type ListenRequest struct {
 	FrequencyMs int
-	Source      *url.Resource `required:"true" description:"log location"`
-	Types       []*Type       `required:"true" description:"log types"`
+	Source      *url.Resource   `description:"log location"`
+	Sources     []*url.Resource `description:"log locations on multiple hosts, records from all locations are merged into the same log types"`
+	Types       []*Type         `required:"true" description:"log types"`
+	Watchdog    *Watchdog       `description:"optional watchdog failing workflow or publishing event when no new records arrive or too many error records appear"`
+}
+
+//Init initialises request
+func (r *ListenRequest) Init() error {
+	if r.Watchdog != nil {
+		return r.Watchdog.Init()
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *ListenRequest) Validate() error {
+	if r.Source == nil && len(r.Sources) == 0 {
+		return fmt.Errorf("source was empty")
+	}
+	if len(r.Types) == 0 {
+		return fmt.Errorf("types were empty")
+	}
+	if r.Watchdog != nil {
+		return r.Watchdog.Validate()
+	}
+	return nil
+}
+
+//AllSources returns all log locations
+func (r *ListenRequest) AllSources() []*url.Resource {
+	var result = make([]*url.Resource, 0)
+	if r.Source != nil {
+		result = append(result, r.Source)
+	}
+	return append(result, r.Sources...)
 }
 
 //ListenResponse represents a log validation listen response.
@@ -103,7 +170,7 @@ type ListenResponse struct {
 
 //UseIndex returns true if index can be used.
 func (t *Type) UseIndex() bool {
-	return t.IndexRegExpr != ""
+	return t.IndexRegExpr != "" || len(t.IndexRegExprs) > 0 || len(t.IndexPaths) > 0
 }
 
 //GetIndexExpr returns index expression.
@@ -116,6 +183,98 @@ func (t *Type) GetIndexExpr() (*regexp.Regexp, error) {
 	return t.indexExpr, err
 }
 
+//GetIndexExprs returns all index expressions, IndexRegExpr is followed by IndexRegExprs
+func (t *Type) GetIndexExprs() ([]*regexp.Regexp, error) {
+	if t.indexExprs != nil {
+		return t.indexExprs, nil
+	}
+	var result = make([]*regexp.Regexp, 0)
+	if t.IndexRegExpr != "" {
+		expr, err := t.GetIndexExpr()
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v index expression: %v, %v", t.Name, t.IndexRegExpr, err)
+		}
+		result = append(result, expr)
+	}
+	for _, candidate := range t.IndexRegExprs {
+		expr, err := regexp.Compile(candidate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v index expression: %v, %v", t.Name, candidate, err)
+		}
+		result = append(result, expr)
+	}
+	t.indexExprs = result
+	return result, nil
+}
+
+//IndexValue returns record index value, composite index parts are joined with '|', empty value is returned if any part is missing
+func (t *Type) IndexValue(record interface{}) string {
+	exprs, err := t.GetIndexExprs()
+	if err != nil {
+		return ""
+	}
+	var parts = make([]string, 0, len(exprs)+len(t.IndexPaths))
+	if len(exprs) > 0 {
+		text := toolbox.AsString(record)
+		if toolbox.IsMap(record) || toolbox.IsSlice(record) || toolbox.IsStruct(record) {
+			text, _ = toolbox.AsJSONText(record)
+		}
+		for _, expr := range exprs {
+			part := matchLogIndex(expr, text)
+			if part == "" {
+				return ""
+			}
+			parts = append(parts, part)
+		}
+	}
+	if len(t.IndexPaths) > 0 {
+		aMap, err := t.recordMap(record)
+		if err != nil {
+			return ""
+		}
+		for _, indexPath := range t.IndexPaths {
+			value, ok := aMap.GetValue(indexPath)
+			if !ok || value == nil {
+				return ""
+			}
+			parts = append(parts, toolbox.AsString(value))
+		}
+	}
+	return strings.Join(parts, indexSeparator)
+}
+
+//recordMap returns structured record or text record parsed with log type format
+func (t *Type) recordMap(record interface{}) (data.Map, error) {
+	if toolbox.IsMap(record) {
+		return data.Map(toolbox.AsMap(record)), nil
+	}
+	parser, err := t.Parser()
+	if err != nil {
+		return nil, err
+	}
+	result, err := parser(toolbox.AsString(record))
+	if err != nil {
+		return nil, err
+	}
+	return data.Map(result), nil
+}
+
+//Parser returns log format parser
+func (t *Type) Parser() (Parser, error) {
+	if t.parser != nil {
+		return t.parser, nil
+	}
+	parserProvidersMux.RLock()
+	provider, ok := parserProviders[strings.ToLower(t.Format)]
+	parserProvidersMux.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported %v log format: %v", t.Name, t.Format)
+	}
+	var err error
+	t.parser, err = provider(t)
+	return t.parser, err
+}
+
 //ResetRequest represents a log reset request
 type ResetRequest struct {
 	LogTypes []string `required:"true" description:"log types to reset"`
@@ -125,3 +284,13 @@ type ResetRequest struct {
 type ResetResponse struct {
 	LogFiles []string
 }
+
+//StopRequest represents a log listener stop request
+type StopRequest struct {
+	LogTypes []string `required:"true" description:"log types to stop listening, stopped types can be registered again with listen"`
+}
+
+//StopResponse represents a log listener stop response
+type StopResponse struct {
+	LogTypes []string `description:"stopped log types"`
+}
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestType_IndexValue(t *testing.T) {
+	var useCases = []struct {
+		description string
+		logType     *Type
+		record      interface{}
+		expect      string
+	}{
+		{
+			description: "single expression",
+			logType:     &Type{IndexRegExpr: `"EventID":"([^"]+)"`},
+			record:      `{"EventID":"e1","ShardID":"s1"}`,
+			expect:      "e1",
+		},
+		{
+			description: "composite expressions",
+			logType:     &Type{IndexRegExpr: `"EventID":"([^"]+)"`, IndexRegExprs: []string{`"ShardID":"([^"]+)"`}},
+			record:      `{"EventID":"e1","ShardID":"s1"}`,
+			expect:      "e1|s1",
+		},
+		{
+			description: "composite expressions with structured record",
+			logType:     &Type{IndexRegExprs: []string{`"EventID":"([^"]+)"`, `"ShardID":"([^"]+)"`}},
+			record:      map[string]interface{}{"EventID": "e1", "ShardID": "s1"},
+			expect:      "e1|s1",
+		},
+		{
+			description: "missing composite part",
+			logType:     &Type{IndexRegExprs: []string{`"EventID":"([^"]+)"`, `"ShardID":"([^"]+)"`}},
+			record:      `{"EventID":"e1"}`,
+			expect:      "",
+		},
+		{
+			description: "json paths",
+			logType:     &Type{IndexPaths: []string{"event.id", "shard"}},
+			record:      `{"event":{"id":"e1"},"shard":3}`,
+			expect:      "e1|3",
+		},
+		{
+			description: "json paths with structured record",
+			logType:     &Type{IndexPaths: []string{"event.id", "shard"}},
+			record:      map[string]interface{}{"event": map[string]interface{}{"id": "e1"}, "shard": 3},
+			expect:      "e1|3",
+		},
+		{
+			description: "csv paths",
+			logType:     &Type{Format: "csv", Columns: []string{"id", "shard", "message"}, IndexPaths: []string{"id", "shard"}},
+			record:      "e1,s2,started",
+			expect:      "e1|s2",
+		},
+		{
+			description: "expression and path",
+			logType:     &Type{IndexRegExpr: `id=(\w+)`, Format: "logfmt", IndexPaths: []string{"shard"}},
+			record:      "id=e1 shard=s1",
+			expect:      "e1|s1",
+		},
+		{
+			description: "invalid record",
+			logType:     &Type{IndexPaths: []string{"id"}},
+			record:      "abc",
+			expect:      "",
+		},
+	}
+	for _, useCase := range useCases {
+		assert.True(t, useCase.logType.UseIndex(), useCase.description)
+		assert.Equal(t, useCase.expect, useCase.logType.IndexValue(useCase.record), useCase.description)
+	}
+	_, err := (&Type{Name: "t", IndexRegExprs: []string{"("}}).GetIndexExprs()
+	assert.NotNil(t, err)
+	assert.False(t, (&Type{}).UseIndex())
+}
@@ -2,6 +2,8 @@ package log
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"fmt"
 	"github.com/viant/afs/storage"
 	"github.com/viant/endly"
@@ -15,10 +17,13 @@ import (
 	"time"
 )
 
+const maxHeadSize = 256
+
 //File represents a log file
 type File struct {
 	URL     string
-	Content string
+	Host    string
+	Content string //content is only retained for UDF transformed logs
 	Name    string
 	*Type
 	ProcessingState *ProcessingState
@@ -93,13 +98,13 @@ func (f *File) PushLogRecord(record *Record) {
 	indexValue := ""
 	f.Records = append(f.Records, record)
 	if f.UseIndex() {
-		if expr, err := f.GetIndexExpr(); err == nil {
-			indexValue = matchLogIndex(expr, record.Line)
-			if indexValue != "" {
-				f.IndexedRecords[indexValue] = record
-			}
+		if indexValue = f.IndexValue(record.Line); indexValue != "" {
+			f.IndexedRecords[indexValue] = record
 		}
 	}
+	if f.Type.watchdog != nil {
+		f.Type.watchdog.observe(record)
+	}
 	if f.Type.Debug {
 		if indexValue != "" {
 			indexValue = " idx:" + indexValue
@@ -122,6 +127,34 @@ func (f *File) Reset(object storage.Object) {
 	f.ProcessingState.Reset()
 }
 
+//PendingRecords returns pending validation records without removing them
+func (f *File) PendingRecords() []*Record {
+	f.Mutex.RLock()
+	defer f.Mutex.RUnlock()
+	var result = make([]*Record, len(f.Records))
+	copy(result, f.Records)
+	return result
+}
+
+//RemoveRecord removes supplied pending record
+func (f *File) RemoveRecord(record *Record) bool {
+	f.Mutex.Lock()
+	defer f.Mutex.Unlock()
+	for i, candidate := range f.Records {
+		if candidate != record {
+			continue
+		}
+		f.Records = append(f.Records[:i:i], f.Records[i+1:]...)
+		for key, indexed := range f.IndexedRecords {
+			if indexed == record {
+				delete(f.IndexedRecords, key)
+			}
+		}
+		return true
+	}
+	return false
+}
+
 //HasPendingLogs returns true if file has pending validation records
 func (f *File) HasPendingLogs() bool {
 	f.Mutex.Lock()
@@ -129,21 +162,105 @@ func (f *File) HasPendingLogs() bool {
 	return len(f.Records) > 0
 }
 
-func (f *File) readLogRecords(reader io.Reader) error {
-	data, err := ioutil.ReadAll(reader)
+//readIncrementally reads only log content appended since the last processed position, processed content is not retained
+func (f *File) readIncrementally(ctx context.Context, fs Source, object storage.Object) error {
+	size := int(object.Size())
+	if size < f.ProcessingState.Position { //log shrink or rolled over case
+		f.Reset(object)
+	}
+	if rangeSource, ok := fs.(RangeSource); ok {
+		if read, err := f.readRange(ctx, rangeSource, object, size); read || err != nil {
+			return err
+		}
+	}
+	reader, err := fs.Open(ctx, object)
 	if err != nil {
 		return err
 	}
-	if f.ProcessingState.Position > len(data) {
-		return nil
+	defer reader.Close()
+	headSize := maxHeadSize
+	if size < headSize {
+		headSize = size
+	}
+	head := make([]byte, headSize)
+	if headSize, err = io.ReadFull(reader, head); err != nil && err != io.ErrUnexpectedEOF {
+		return err
 	}
+	head = f.updateHead(object, head[:headSize])
+	state := f.ProcessingState
+	var pending io.Reader
+	if state.Position < len(head) {
+		pending = io.MultiReader(bytes.NewReader(head[state.Position:]), reader)
+	} else if seeker, ok := reader.(io.Seeker); ok {
+		if _, err = seeker.Seek(int64(state.Position), io.SeekStart); err != nil {
+			return err
+		}
+		pending = reader
+	} else { //fallback for sources without ranged or seekable reads: processed content is downloaded again and discarded
+		if _, err = io.CopyN(ioutil.Discard, reader, int64(state.Position-len(head))); err != nil {
+			return err
+		}
+		pending = reader
+	}
+	f.Size = size
+	f.LastModified = object.ModTime()
+	return f.readLogRecords(io.LimitReader(pending, int64(size-state.Position)))
+}
+
+//readRange reads log head and appended content with ranged reads, it returns false if source does not support ranged reads for the object
+func (f *File) readRange(ctx context.Context, source RangeSource, object storage.Object, size int) (bool, error) {
+	headSize := maxHeadSize
+	if size < headSize {
+		headSize = size
+	}
+	var head []byte
+	if headSize > 0 {
+		var ok bool
+		var err error
+		if head, ok, err = source.ReadRange(ctx, object, 0, int64(headSize)); !ok || err != nil {
+			return ok, err
+		}
+	}
+	head = f.updateHead(object, head)
+	state := f.ProcessingState
+	offset := state.Position
+	var pending []byte
+	if offset < len(head) {
+		pending = append(pending, head[offset:]...)
+		offset = len(head)
+	}
+	if size > offset {
+		appended, _, err := source.ReadRange(ctx, object, int64(offset), int64(size-offset))
+		if err != nil {
+			return true, err
+		}
+		pending = append(pending, appended...)
+	}
+	f.Size = size
+	f.LastModified = object.ModTime()
+	return true, f.readLogRecords(bytes.NewReader(pending))
+}
+
+//updateHead resets processing state if log head does not match previously read head (log replaced), and returns head
+func (f *File) updateHead(object storage.Object, head []byte) []byte {
+	state := f.ProcessingState
+	if previous := state.Head; previous != "" && (len(previous) > len(head) || string(head[:len(previous)]) != previous) { //log replaced
+		f.Reset(object)
+	}
+	if len(head) > len(state.Head) {
+		state.Head = string(head)
+	}
+	return head
+}
+
+//readLogRecords reads log records from reader positioned at processing state position
+func (f *File) readLogRecords(reader io.Reader) error {
 	var line = ""
-	var startPosition = f.ProcessingState.Position
 	var startLine = f.ProcessingState.Line
 	var lineIndex = startLine
 	var dataProcessed = 0
 
-	r := bufio.NewReader(strings.NewReader(string(data[startPosition:])))
+	r := bufio.NewReader(reader)
 	for {
 		code, size, err := r.ReadRune()
 		if err == io.EOF {
@@ -178,6 +295,7 @@ func (f *File) readLogRecords(reader io.Reader) error {
 		if len(line) > 0 {
 			f.PushLogRecord(&Record{
 				URL:    f.URL,
+				Host:   f.Host,
 				Line:   line,
 				Number: lineIndex,
 			})
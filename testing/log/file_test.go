@@ -0,0 +1,108 @@
+package log
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+	"github.com/viant/endly"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFile_ReadIncrementally(t *testing.T) {
+	directory, err := ioutil.TempDir("", "endly_log")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(directory)
+	server := httptest.NewServer(http.FileServer(http.Dir(directory)))
+	defer server.Close()
+	location := path.Join(directory, "app.log")
+	fs := afs.New()
+	ctx := context.Background()
+
+	var useCases = []struct {
+		description string
+		source      Source
+		URL         string
+	}{
+		{
+			description: "seekable source",
+			source:      fs,
+			URL:         location,
+		},
+		{
+			description: "storage ranged source",
+			source:      &storageSource{Service: fs, context: endly.New().NewContext(nil), readers: make(map[string]func(offset, length int64) ([]byte, error))},
+			URL:         location,
+		},
+		{
+			description: "http ranged source",
+			source:      &httpSource{client: http.DefaultClient},
+			URL:         server.URL + "/app.log",
+		},
+	}
+
+	for _, useCase := range useCases {
+		logFile := &File{
+			Type:            &Type{Name: "app"},
+			URL:             useCase.URL,
+			ProcessingState: &ProcessingState{},
+			Mutex:           &sync.RWMutex{},
+			Records:         make([]*Record, 0),
+			IndexedRecords:  make(map[string]*Record),
+		}
+		read := func(content string, appendMode bool) {
+			flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+			if appendMode {
+				flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+			}
+			file, err := os.OpenFile(location, flag, 0644)
+			if !assert.Nil(t, err, useCase.description) {
+				return
+			}
+			_, _ = file.WriteString(content)
+			_ = file.Close()
+			object, err := useCase.source.Object(ctx, useCase.URL)
+			if !assert.Nil(t, err, useCase.description) {
+				return
+			}
+			assert.Nil(t, logFile.readIncrementally(ctx, useCase.source, object), useCase.description)
+		}
+		lines := func() []string {
+			var result = make([]string, 0)
+			for record := logFile.ShiftLogRecord(); record != nil; record = logFile.ShiftLogRecord() {
+				result = append(result, record.Line)
+			}
+			return result
+		}
+
+		read("line 1\nline 2\n", false)
+		assert.Equal(t, []string{"line 1", "line 2"}, lines(), useCase.description)
+		assert.Equal(t, 14, logFile.ProcessingState.Position, useCase.description)
+		assert.Equal(t, "", logFile.Content, useCase.description)
+
+		read("line 3\nline", true)
+		assert.Equal(t, []string{"line 3"}, lines(), useCase.description)
+		read(" 4\n", true)
+		assert.Equal(t, []string{"line 4"}, lines(), useCase.description)
+		assert.Equal(t, 4, logFile.ProcessingState.Line, useCase.description)
+
+		read(strings.Repeat("x", maxHeadSize)+"\nline 6\n", true)
+		assert.Equal(t, []string{strings.Repeat("x", maxHeadSize), "line 6"}, lines(), useCase.description)
+		read("line 7\n", true)
+		assert.Equal(t, []string{"line 7"}, lines(), useCase.description)
+
+		read("rotated 1\n", false)
+		assert.Equal(t, []string{"rotated 1"}, lines(), useCase.description)
+
+		read("replaced 1\nreplaced 2\n", false)
+		assert.Equal(t, []string{"replaced 1", "replaced 2"}, lines(), useCase.description)
+	}
+}
@@ -0,0 +1,173 @@
+package log
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/viant/toolbox"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//Parser represents log record line parser
+type Parser func(line string) (map[string]interface{}, error)
+
+//ParserProvider represents log format parser provider
+type ParserProvider func(logType *Type) (Parser, error)
+
+var parserProviders = map[string]ParserProvider{
+	"":       newJSONParser,
+	"json":   newJSONParser,
+	"csv":    newCSVParser,
+	"logfmt": newLogfmtParser,
+	"regexp": newRegExprParser,
+	"grok":   newGrokParser,
+}
+var parserProvidersMux = &sync.RWMutex{}
+
+//RegisterFormat registers log format parser provider
+func RegisterFormat(format string, provider ParserProvider) {
+	parserProvidersMux.Lock()
+	defer parserProvidersMux.Unlock()
+	parserProviders[strings.ToLower(format)] = provider
+}
+
+func newJSONParser(logType *Type) (Parser, error) {
+	return func(line string) (map[string]interface{}, error) {
+		var result = make(map[string]interface{})
+		err := toolbox.NewJSONDecoderFactory().Create(strings.NewReader(line)).Decode(&result)
+		return result, err
+	}, nil
+}
+
+func newCSVParser(logType *Type) (Parser, error) {
+	if len(logType.Columns) == 0 {
+		return nil, fmt.Errorf("columns were empty for %v csv log type", logType.Name)
+	}
+	var delimiter = ','
+	if logType.Delimiter != "" {
+		delimiter = []rune(logType.Delimiter)[0]
+	}
+	return func(line string) (map[string]interface{}, error) {
+		reader := csv.NewReader(strings.NewReader(line))
+		reader.Comma = delimiter
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		values, err := reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		var result = make(map[string]interface{})
+		for i, column := range logType.Columns {
+			if i < len(values) {
+				result[column] = values[i]
+			}
+		}
+		return result, nil
+	}, nil
+}
+
+var logfmtExpr = regexp.MustCompile(`([^\s=]+)(?:=("(?:[^"\\]|\\.)*"|\S*))?`)
+
+func newLogfmtParser(logType *Type) (Parser, error) {
+	return func(line string) (map[string]interface{}, error) {
+		var result = make(map[string]interface{})
+		for _, match := range logfmtExpr.FindAllStringSubmatch(line, -1) {
+			value := match[2]
+			if strings.HasPrefix(value, `"`) {
+				value = strings.Replace(value[1:len(value)-1], `\"`, `"`, -1)
+			}
+			result[match[1]] = value
+		}
+		if len(result) == 0 {
+			return nil, fmt.Errorf("invalid logfmt record: %v", line)
+		}
+		return result, nil
+	}, nil
+}
+
+func newRegExprParser(logType *Type) (Parser, error) {
+	if logType.Pattern == "" {
+		return nil, fmt.Errorf("pattern was empty for %v %v log type", logType.Name, logType.Format)
+	}
+	expr, err := regexp.Compile(logType.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v pattern: %v, %v", logType.Name, logType.Pattern, err)
+	}
+	return func(line string) (map[string]interface{}, error) {
+		match := expr.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("record did not match %v pattern: %v", logType.Name, line)
+		}
+		var result = make(map[string]interface{})
+		for i, name := range expr.SubexpNames() {
+			if i > 0 && name != "" {
+				result[name] = match[i]
+			}
+		}
+		return result, nil
+	}, nil
+}
+
+//GrokPatterns represents grok named patterns
+var GrokPatterns = map[string]string{
+	"WORD":              `\b\w+\b`,
+	"NOTSPACE":          `\S+`,
+	"SPACE":             `\s*`,
+	"DATA":              `.*?`,
+	"GREEDYDATA":        `.*`,
+	"INT":               `[+-]?\d+`,
+	"NUMBER":            `[+-]?(?:\d+(?:\.\d+)?|\.\d+)`,
+	"UUID":              `[A-Fa-f0-9]{8}-(?:[A-Fa-f0-9]{4}-){3}[A-Fa-f0-9]{12}`,
+	"IP":                `(?:\d{1,3}\.){3}\d{1,3}|[0-9A-Fa-f:]+`,
+	"HOSTNAME":          `\b[0-9A-Za-z][0-9A-Za-z-]{0,62}(?:\.[0-9A-Za-z][0-9A-Za-z-]{0,62})*\.?\b`,
+	"IPORHOST":          `%{IP}|%{HOSTNAME}`,
+	"USER":              `[a-zA-Z0-9._-]+`,
+	"QUOTEDSTRING":      `"(?:[^"\\]|\\.)*"`,
+	"LOGLEVEL":          `[Tt]race|TRACE|[Dd]ebug|DEBUG|[Ii]nfo|INFO|[Ww]arn(?:ing)?|WARN(?:ING)?|[Ee]rr(?:or)?|ERR(?:OR)?|[Ff]atal|FATAL`,
+	"HTTPDATE":          `\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}`,
+	"TIMESTAMP_ISO8601": `\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+-]\d{2}:?\d{2})?`,
+	"SYSLOGTIMESTAMP":   `\w{3} +\d{1,2} \d{2}:\d{2}:\d{2}`,
+	"URIPATHPARAM":      `\S+`,
+	"COMMONAPACHELOG":   `%{IPORHOST:clientip} %{USER:ident} %{USER:auth} \[%{HTTPDATE:timestamp}\] "(?:%{WORD:verb} %{NOTSPACE:request}(?: HTTP/%{NUMBER:httpversion})?|%{DATA:rawrequest})" %{NUMBER:response} (?:%{NUMBER:bytes}|-)`,
+	"SYSLOGLINE":        `%{SYSLOGTIMESTAMP:timestamp} %{IPORHOST:logsource} %{NOTSPACE:program}(?:\[%{INT:pid}\])?: %{GREEDYDATA:message}`,
+}
+
+var grokExpr = regexp.MustCompile(`%\{(\w+)(?::(\w+))?\}`)
+
+//expandGrok converts grok pattern into regular expression with named groups
+func expandGrok(pattern string, depth int) (string, error) {
+	if depth > 10 {
+		return "", fmt.Errorf("grok pattern recursion limit exceeded: %v", pattern)
+	}
+	var err error
+	result := grokExpr.ReplaceAllStringFunc(pattern, func(matched string) string {
+		match := grokExpr.FindStringSubmatch(matched)
+		expr, ok := GrokPatterns[match[1]]
+		if !ok {
+			err = fmt.Errorf("unknown grok pattern: %v", match[1])
+			return matched
+		}
+		expanded, e := expandGrok(expr, depth+1)
+		if e != nil {
+			err = e
+			return matched
+		}
+		if match[2] != "" {
+			return fmt.Sprintf("(?P<%v>%v)", match[2], expanded)
+		}
+		return "(?:" + expanded + ")"
+	})
+	return result, err
+}
+
+func newGrokParser(logType *Type) (Parser, error) {
+	if logType.Pattern == "" {
+		return nil, fmt.Errorf("pattern was empty for %v grok log type", logType.Name)
+	}
+	expr, err := expandGrok(logType.Pattern, 0)
+	if err != nil {
+		return nil, err
+	}
+	return newRegExprParser(&Type{Name: logType.Name, Format: logType.Format, Pattern: expr})
+}
@@ -0,0 +1,74 @@
+package log_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/testing/log"
+	"testing"
+)
+
+func TestType_Parser(t *testing.T) {
+	var useCases = []struct {
+		description string
+		logType     *log.Type
+		line        string
+		expect      map[string]interface{}
+		hasError    bool
+	}{
+		{
+			description: "json",
+			logType:     &log.Type{Name: "t1"},
+			line:        `{"id":1,"user":"abc"}`,
+			expect:      map[string]interface{}{"id": 1.0, "user": "abc"},
+		},
+		{
+			description: "csv",
+			logType:     &log.Type{Name: "t2", Format: "csv", Columns: []string{"id", "type", "user"}},
+			line:        `1,event1,"user, 1"`,
+			expect:      map[string]interface{}{"id": "1", "type": "event1", "user": "user, 1"},
+		},
+		{
+			description: "logfmt",
+			logType:     &log.Type{Name: "t3", Format: "logfmt"},
+			line:        `level=info msg="user logged in" user=abc debug`,
+			expect:      map[string]interface{}{"level": "info", "msg": "user logged in", "user": "abc", "debug": ""},
+		},
+		{
+			description: "regexp",
+			logType:     &log.Type{Name: "t4", Format: "regexp", Pattern: `^(?P<level>\w+): (?P<message>.+)$`},
+			line:        `ERROR: failed to connect`,
+			expect:      map[string]interface{}{"level": "ERROR", "message": "failed to connect"},
+		},
+		{
+			description: "grok",
+			logType:     &log.Type{Name: "t5", Format: "grok", Pattern: `%{COMMONAPACHELOG}`},
+			line:        `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`,
+			expect: map[string]interface{}{"clientip": "127.0.0.1", "ident": "-", "auth": "frank", "timestamp": "10/Oct/2000:13:55:36 -0700",
+				"verb": "GET", "request": "/apache_pb.gif", "httpversion": "1.0", "rawrequest": "", "response": "200", "bytes": "2326"},
+		},
+		{
+			description: "regexp mismatch",
+			logType:     &log.Type{Name: "t6", Format: "regexp", Pattern: `^(?P<level>\d+)$`},
+			line:        `abc`,
+			hasError:    true,
+		},
+		{
+			description: "unsupported format",
+			logType:     &log.Type{Name: "t7", Format: "xml"},
+			line:        `<a/>`,
+			hasError:    true,
+		},
+	}
+
+	for _, useCase := range useCases {
+		record := &log.Record{URL: "mem://localhost/test.log", Number: 1, Line: useCase.line}
+		actual, err := record.AsMapWithType(useCase.logType)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"regexp"
 )
 
+//indexSeparator represents composite index parts separator
+const indexSeparator = "|"
+
 func matchLogIndex(expr *regexp.Regexp, input string) string {
 	if expr.MatchString(input) {
 		matches := expr.FindStringSubmatch(input)
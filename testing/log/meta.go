@@ -18,26 +18,45 @@ type TypeMeta struct {
 
 //Iterator returns log record iterator
 func (m *TypeMeta) Iterator() toolbox.Iterator {
-	logFileProvider := func() []*File {
-		var result = make([]*File, 0)
-		for _, logFile := range m.LogFiles {
-			result = append(result, logFile)
+	return &logRecordIterator{
+		logFiles:        m.sortedFiles(),
+		logFileProvider: m.sortedFiles,
+	}
+}
+
+//PendingRecords returns all pending validation records in iterator order without removing them
+func (m *TypeMeta) PendingRecords() []*Record {
+	var result = make([]*Record, 0)
+	for _, logFile := range m.sortedFiles() {
+		result = append(result, logFile.PendingRecords()...)
+	}
+	return result
+}
+
+//RemoveRecord removes supplied pending record
+func (m *TypeMeta) RemoveRecord(record *Record) bool {
+	for _, logFile := range m.LogFiles {
+		if logFile.URL == record.URL && logFile.RemoveRecord(record) {
+			return true
 		}
-		sort.Slice(result, func(i, j int) bool {
-			var left = result[i].LastModified
-			var right = result[j].LastModified
-			if !left.After(right) && !right.After(left) {
-				return result[i].URL > result[j].URL
-			}
-			return left.After(right)
-		})
-		return result
 	}
+	return false
+}
 
-	return &logRecordIterator{
-		logFiles:        logFileProvider(),
-		logFileProvider: logFileProvider,
+func (m *TypeMeta) sortedFiles() []*File {
+	var result = make([]*File, 0)
+	for _, logFile := range m.LogFiles {
+		result = append(result, logFile)
 	}
+	sort.Slice(result, func(i, j int) bool {
+		var left = result[i].LastModified
+		var right = result[j].LastModified
+		if !left.After(right) && !right.After(left) {
+			return result[i].URL > result[j].URL
+		}
+		return left.After(right)
+	})
+	return result
 }
 
 //NewTypeMeta creates a nre log type meta.
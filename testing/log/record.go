@@ -1,6 +1,7 @@
 package log
 
 import (
+	"fmt"
 	"github.com/viant/toolbox"
 	"strings"
 )
@@ -8,6 +9,7 @@ import (
 //Record represents a log record
 type Record struct {
 	URL    string
+	Host   string `json:",omitempty"`
 	Number int
 	Line   string
 }
@@ -24,3 +26,16 @@ func (r *Record) AsMap() (map[string]interface{}, error) {
 	err := toolbox.NewJSONDecoderFactory().Create(strings.NewReader(r.Line)).Decode(&result)
 	return result, err
 }
+
+//AsMapWithType returns log records as map parsed with log type format
+func (r *Record) AsMapWithType(logType *Type) (map[string]interface{}, error) {
+	parser, err := logType.Parser()
+	if err != nil {
+		return nil, err
+	}
+	result, err := parser(r.Line)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v:%v, %v", r.URL, r.Number, err)
+	}
+	return result, nil
+}
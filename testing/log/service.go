@@ -18,6 +18,7 @@ import (
 	"io/ioutil"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -47,6 +48,7 @@ func (s *service) reset(context *endly.Context, request *ResetRequest) (*ResetRe
 				logFile.ProcessingState = &ProcessingState{
 					Position: logFile.Size,
 					Line:     len(logFile.Records),
+					Head:     logFile.ProcessingState.Head,
 				}
 				logFile.Records = make([]*Record, 0)
 				response.LogFiles = append(response.LogFiles, logFile.Name)
@@ -56,6 +58,36 @@ func (s *service) reset(context *endly.Context, request *ResetRequest) (*ResetRe
 	return response, nil
 }
 
+func (s *service) stop(context *endly.Context, request *StopRequest) (*StopResponse, error) {
+	var response = &StopResponse{
+		LogTypes: make([]string, 0),
+	}
+	var stopped = make(map[string]bool)
+	s.Mutex().Lock()
+	var state = s.State()
+	for _, logTypeName := range request.LogTypes {
+		var key = logTypeMetaKey(logTypeName)
+		if !state.Has(key) {
+			continue
+		}
+		state.Delete(key)
+		stopped[logTypeName] = true
+		response.LogTypes = append(response.LogTypes, logTypeName)
+	}
+	s.Mutex().Unlock()
+	context.PersistentSession().Update(ServiceID, "listen", func(request map[string]interface{}) bool {
+		var types = make([]interface{}, 0)
+		for _, item := range toolbox.AsSlice(request["Types"]) {
+			if !stopped[toolbox.AsString(toolbox.AsMap(item)["Name"])] {
+				types = append(types, item)
+			}
+		}
+		request["Types"] = types
+		return len(types) > 0
+	})
+	return response, nil
+}
+
 func (s *service) assert(context *endly.Context, request *AssertRequest) (*AssertResponse, error) {
 	var response = &AssertResponse{
 		Validations: make([]*assertly.Validation, 0),
@@ -69,11 +101,28 @@ func (s *service) assert(context *endly.Context, request *AssertRequest) (*Asser
 		if err != nil {
 			return response, err
 		}
-		var recordIterator = typeMeta.Iterator()
-
 		var aMap = data.NewMap()
 		aMap.Put("logType", expectedLogRecords.Type)
 		aMap.Put("tagID", expectedLogRecords.TagID)
+		if expectedLogRecords.Absent {
+			validation, err := s.assertAbsence(context, typeMeta, expectedLogRecords, request)
+			if err != nil {
+				return response, err
+			}
+			validation.Description = aMap.ExpandAsText(request.DescriptionTemplate)
+			response.Validations = append(response.Validations, validation)
+			continue
+		}
+		if expectedLogRecords.Match == MatchUnordered || expectedLogRecords.Match == MatchSubset {
+			validation, err := s.assertUnordered(context, typeMeta, expectedLogRecords, request, response)
+			if err != nil {
+				return response, err
+			}
+			validation.Description = aMap.ExpandAsText(request.DescriptionTemplate)
+			response.Validations = append(response.Validations, validation)
+			continue
+		}
+		var recordIterator = typeMeta.Iterator()
 
 		for _, expectedRecord := range expectedLogRecords.Records {
 			var validation = &assertly.Validation{
@@ -94,7 +143,7 @@ func (s *service) assert(context *endly.Context, request *AssertRequest) (*Asser
 			}
 			var actualLogRecord interface{} = logRecord.Line
 			if isLogStructured := toolbox.IsMap(expectedRecord); isLogStructured {
-				actualLogRecord, err = logRecord.AsMap()
+				actualLogRecord, err = logRecord.AsMapWithType(typeMeta.LogType)
 				if err != nil {
 					return response, err
 				}
@@ -112,11 +161,194 @@ func (s *service) assert(context *endly.Context, request *AssertRequest) (*Asser
 			context.Publish(logRecordsAssert)
 			context.Publish(logValidation)
 			validation.MergeFrom(logValidation)
+			if !logValidation.HasFailure() {
+				s.capture(context, typeMeta, expectedLogRecords, logRecord, validation, response)
+			}
 		}
 	}
 	return response, nil
 }
 
+//capture sets matched record field values into context state, missing field is reported as validation failure
+func (s *service) capture(context *endly.Context, typeMeta *TypeMeta, expectedLogRecords *TypedRecord, logRecord *Record, validation *assertly.Validation, response *AssertResponse) {
+	if len(expectedLogRecords.Capture) == 0 {
+		return
+	}
+	_, filename := toolbox.URLSplit(logRecord.URL)
+	var location = fmt.Sprintf("[%v]%v:%v", expectedLogRecords.TagID, filename, logRecord.Number)
+	record, err := logRecord.AsMapWithType(typeMeta.LogType)
+	if err != nil {
+		validation.AddFailure(assertly.NewFailure("", location, "capture", "structured log record", err.Error()))
+		return
+	}
+	var aMap = data.Map(record)
+	if response.Captured == nil {
+		response.Captured = make(map[string]interface{})
+	}
+	state := context.SafeState()
+	for name, fieldPath := range expectedLogRecords.Capture {
+		value, has := aMap.GetValue(fieldPath)
+		if !has {
+			validation.AddFailure(assertly.NewFailure("", location, "capture", fmt.Sprintf("field %v", fieldPath), logRecord.Line))
+			continue
+		}
+		response.Captured[name] = value
+		state.SetValue(name, value)
+	}
+}
+
+//matchUnordered matches expected records with any pending record, it returns matched pending record index per expected record (-1 if unmatched)
+func (s *service) matchUnordered(context *endly.Context, typeMeta *TypeMeta, expectedRecords []interface{}, pending []*Record) ([]int, []*assertly.Validation, error) {
+	var matches = make([]int, len(expectedRecords))
+	var validations = make([]*assertly.Validation, len(expectedRecords))
+	var used = make(map[int]bool)
+	for i, expectedRecord := range expectedRecords {
+		matches[i] = -1
+		for j, logRecord := range pending {
+			if used[j] {
+				continue
+			}
+			var actual interface{} = logRecord.Line
+			if toolbox.IsMap(expectedRecord) {
+				aMap, err := logRecord.AsMapWithType(typeMeta.LogType)
+				if err != nil {
+					continue
+				}
+				actual = aMap
+			}
+			_, filename := toolbox.URLSplit(logRecord.URL)
+			validation, err := criteria.Assert(context, fmt.Sprintf("%v:%v", filename, logRecord.Number), expectedRecord, actual)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !validation.HasFailure() {
+				used[j] = true
+				matches[i] = j
+				validations[i] = validation
+				break
+			}
+		}
+	}
+	return matches, validations, nil
+}
+
+//assertUnordered matches expected records with pending records regardless of arrival order, matched records are removed
+func (s *service) assertUnordered(context *endly.Context, typeMeta *TypeMeta, expectedLogRecords *TypedRecord, request *AssertRequest, response *AssertResponse) (*assertly.Validation, error) {
+	var validation = &assertly.Validation{
+		TagID: expectedLogRecords.TagID,
+	}
+	var pending []*Record
+	var matches []int
+	var validations []*assertly.Validation
+	var err error
+	for i := 0; ; i++ {
+		pending = typeMeta.PendingRecords()
+		if matches, validations, err = s.matchUnordered(context, typeMeta, expectedLogRecords.Records, pending); err != nil {
+			return nil, err
+		}
+		var matched = 0
+		for _, index := range matches {
+			if index != -1 {
+				matched++
+			}
+		}
+		completed := matched == len(matches)
+		if expectedLogRecords.Match == MatchUnordered {
+			completed = completed && matched == len(pending)
+		}
+		if completed || i >= request.LogWaitRetryCount {
+			break
+		}
+		s.Sleep(context, int(request.LogWaitTimeMs))
+	}
+	var used = make(map[int]bool)
+	for i, index := range matches {
+		if index == -1 {
+			continue
+		}
+		used[index] = true
+		typeMeta.RemoveRecord(pending[index])
+		s.capture(context, typeMeta, expectedLogRecords, pending[index], validation, response)
+		context.Publish(&validator.TaggedAssert{
+			TagID:    expectedLogRecords.TagID,
+			Expected: expectedLogRecords.Records[i],
+			Actual:   pending[index].Line,
+		})
+		validation.MergeFrom(validations[i])
+	}
+	var unmatched = make([]string, 0)
+	for j, logRecord := range pending {
+		if !used[j] {
+			unmatched = append(unmatched, logRecord.Line)
+		}
+	}
+	for i, index := range matches {
+		if index == -1 {
+			validation.AddFailure(assertly.NewFailure("", fmt.Sprintf("[%v]", expectedLogRecords.TagID), "missing log record", expectedLogRecords.Records[i], unmatched))
+		}
+	}
+	if expectedLogRecords.Match == MatchUnordered {
+		for j, logRecord := range pending {
+			if used[j] {
+				continue
+			}
+			_, filename := toolbox.URLSplit(logRecord.URL)
+			validation.AddFailure(assertly.NewFailure("", fmt.Sprintf("[%v]%v:%v", expectedLogRecords.TagID, filename, logRecord.Number), "unexpected log record", nil, logRecord.Line))
+		}
+	}
+	context.Publish(validation)
+	return validation, nil
+}
+
+//assertAbsence waits for the whole wait window, then checks that no pending record matches unwanted records, matched records are not removed
+func (s *service) assertAbsence(context *endly.Context, typeMeta *TypeMeta, expectedLogRecords *TypedRecord, request *AssertRequest) (*assertly.Validation, error) {
+	var validation = &assertly.Validation{
+		TagID: expectedLogRecords.TagID,
+	}
+	s.Sleep(context, request.LogWaitTimeMs*request.LogWaitRetryCount)
+	var files = make([]*File, 0)
+	for _, logFile := range typeMeta.LogFiles {
+		files = append(files, logFile)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].URL < files[j].URL
+	})
+	for _, unwanted := range expectedLogRecords.Records {
+		var matched *Record
+		for _, logFile := range files {
+			for _, logRecord := range logFile.PendingRecords() {
+				var actual interface{} = logRecord.Line
+				if toolbox.IsMap(unwanted) {
+					aMap, err := logRecord.AsMapWithType(typeMeta.LogType)
+					if err != nil {
+						continue
+					}
+					actual = aMap
+				}
+				candidate, err := criteria.Assert(context, "", unwanted, actual)
+				if err != nil {
+					return nil, err
+				}
+				if !candidate.HasFailure() && candidate.PassedCount > 0 {
+					matched = logRecord
+					break
+				}
+			}
+			if matched != nil {
+				break
+			}
+		}
+		if matched == nil {
+			validation.PassedCount++
+			continue
+		}
+		_, filename := toolbox.URLSplit(matched.URL)
+		validation.AddFailure(assertly.NewFailure("", fmt.Sprintf("[%v]%v:%v", expectedLogRecords.TagID, filename, matched.Number), "unexpected log record", unwanted, matched.Line))
+	}
+	context.Publish(validation)
+	return validation, nil
+}
+
 func (s *service) waitForRecord(context *endly.Context, recordIterator toolbox.Iterator, request *AssertRequest) bool {
 	for j := 0; j < request.LogWaitRetryCount; j++ {
 		if recordIterator.HasNext() {
@@ -131,23 +363,15 @@ func (s *service) matchLogRecord(typeMeta *TypeMeta, expectedRecord interface{},
 	var calledNext = false
 	var logRecord *Record
 	if typeMeta.LogType.UseIndex() {
-		if expr, err := typeMeta.LogType.GetIndexExpr(); err == nil {
-			var expectedTextRecord = toolbox.AsString(expectedRecord)
-			if toolbox.IsMap(expectedRecord) || toolbox.IsSlice(expectedRecord) || toolbox.IsStruct(expectedRecord) {
-				expectedTextRecord, _ = toolbox.AsJSONText(expectedRecord)
-			}
-			indexValue := matchLogIndex(expr, expectedTextRecord)
-			if indexValue != "" {
-				indexedLogRecord := &IndexedRecord{
-					IndexValue: indexValue,
-				}
-				err = logRecordIterator.Next(indexedLogRecord)
-				if err != nil {
-					return nil, err
-				}
-				calledNext = true
-				logRecord = indexedLogRecord.Record
+		if indexValue := typeMeta.LogType.IndexValue(expectedRecord); indexValue != "" {
+			indexedLogRecord := &IndexedRecord{
+				IndexValue: indexValue,
 			}
+			if err := logRecordIterator.Next(indexedLogRecord); err != nil {
+				return nil, err
+			}
+			calledNext = true
+			logRecord = indexedLogRecord.Record
 		}
 	}
 
@@ -172,7 +396,7 @@ func (s *service) getLogTypeMeta(expectedLogRecords *TypedRecord) (*TypeMeta, er
 }
 
 //tryReadSnapshot tries to read file snapshot, since file may change any time, this method attempts to get a stable snapshot read withhout actual change in file content while it is read.
-func (s *service) tryReadSnapshot(context *endly.Context, fs afs.Service, object storage.Object, attemptsCount int) (io.Reader, error) {
+func (s *service) tryReadSnapshot(context *endly.Context, fs Source, object storage.Object, attemptsCount int) (io.Reader, error) {
 	fileSize := object.Size()
 	for i := 0; i < attemptsCount; i++ {
 		reader, err := fs.Open(context.Background(), object)
@@ -195,24 +419,34 @@ func (s *service) tryReadSnapshot(context *endly.Context, fs afs.Service, object
 	return nil, nil
 }
 
-func (s *service) readLogFile(context *endly.Context, source *url.Resource, fs afs.Service, candidate storage.Object, logType *Type) (*TypeMeta, error) {
+//readLogFile reads log file, when watching, log type which has been stopped or registered again is skipped
+func (s *service) readLogFile(context *endly.Context, source *url.Resource, fs Source, candidate storage.Object, logType *Type, watching bool) (*TypeMeta, error) {
 	var result *TypeMeta
 	var key = logTypeMetaKey(logType.Name)
 	s.Mutex().Lock()
 
 	var state = s.State()
 	if !state.Has(key) {
+		if watching {
+			s.Mutex().Unlock()
+			return nil, nil
+		}
 		state.Put(key, NewTypeMeta(source, logType))
 	}
 
 	result, ok := state.Get(key).(*TypeMeta)
 	if !ok {
+		s.Mutex().Unlock()
 		return nil, fmt.Errorf("failed to fwtch type meta")
 	}
+	if watching && result.LogType != logType {
+		s.Mutex().Unlock()
+		return nil, nil
+	}
 
 	var isNewLogFile = false
 	_, name := toolbox.URLSplit(candidate.URL())
-	logFile, has := result.LogFiles[name]
+	logFile, has := result.LogFiles[candidate.URL()]
 	fileInfo := candidate
 	if !has {
 		isNewLogFile = true
@@ -221,6 +455,7 @@ func (s *service) readLogFile(context *endly.Context, source *url.Resource, fs a
 			Type:            logType,
 			Name:            name,
 			URL:             candidate.URL(),
+			Host:            sourceHost(source),
 			LastModified:    fileInfo.ModTime(),
 			Size:            int(fileInfo.Size()),
 			ProcessingState: &ProcessingState{},
@@ -228,7 +463,7 @@ func (s *service) readLogFile(context *endly.Context, source *url.Resource, fs a
 			Records:         make([]*Record, 0),
 			IndexedRecords:  make(map[string]*Record),
 		}
-		result.LogFiles[name] = logFile
+		result.LogFiles[candidate.URL()] = logFile
 	}
 	s.Mutex().Unlock()
 
@@ -236,24 +471,26 @@ func (s *service) readLogFile(context *endly.Context, source *url.Resource, fs a
 		return result, nil
 	}
 
+	if logFile.UDF == "" {
+		return result, logFile.readIncrementally(context.Background(), fs, candidate)
+	}
+	//UDF transforms the whole log content, thus transformed content has to be retained to detect changes
 	reader, err := s.tryReadSnapshot(context, fs, candidate, 3)
 	if err != nil || reader == nil {
 		return nil, err
 	}
-	if logFile.UDF != "" {
-		content, err := ioutil.ReadAll(reader)
-		if err != nil {
-			return nil, err
-		}
-		transformed, err := udf.TransformWithUDF(context, logFile.UDF, logFile.UDF, content)
-		switch payload := transformed.(type) {
-		case string:
-			reader = ioutil.NopCloser(strings.NewReader(payload))
-		case []byte:
-			reader = ioutil.NopCloser(bytes.NewReader(payload))
-		default:
-			return nil, fmt.Errorf("unsupported response type expeced string or []byte but had: %T", transformed)
-		}
+	rawContent, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	transformed, err := udf.TransformWithUDF(context, logFile.UDF, logFile.UDF, rawContent)
+	switch payload := transformed.(type) {
+	case string:
+		reader = ioutil.NopCloser(strings.NewReader(payload))
+	case []byte:
+		reader = ioutil.NopCloser(bytes.NewReader(payload))
+	default:
+		return nil, fmt.Errorf("unsupported response type expeced string or []byte but had: %T", transformed)
 	}
 
 	logContent, err := ioutil.ReadAll(reader)
@@ -274,8 +511,8 @@ func (s *service) readLogFile(context *endly.Context, source *url.Resource, fs a
 
 	logFile.Content = content
 	logFile.Size = len(logContent)
-	if len(logContent) > 0 {
-		err = logFile.readLogRecords(bytes.NewReader(logContent))
+	if len(logContent) > logFile.ProcessingState.Position {
+		err = logFile.readLogRecords(bytes.NewReader(logContent[logFile.ProcessingState.Position:]))
 		if err != nil {
 			return nil, err
 		}
@@ -283,7 +520,7 @@ func (s *service) readLogFile(context *endly.Context, source *url.Resource, fs a
 	return result, nil
 }
 
-func (s *service) readLogFiles(context *endly.Context, fs afs.Service, source *url.Resource, logTypes ...*Type) (TypesMeta, error) {
+func (s *service) readLogFiles(context *endly.Context, fs Source, source *url.Resource, watching bool, logTypes ...*Type) (TypesMeta, error) {
 	source, storageOptions, err := estorage.GetResourceWithOptions(context, source)
 	if err != nil {
 		return nil, err
@@ -305,39 +542,73 @@ func (s *service) readLogFiles(context *endly.Context, fs afs.Service, source *u
 			}
 			_, name := toolbox.URLSplit(candidate.URL())
 			if maskExpression.MatchString(name) {
-				logTypeMeta, err := s.readLogFile(context, source, fs, candidate, logType)
+				logTypeMeta, err := s.readLogFile(context, source, fs, candidate, logType, watching)
 				if err != nil {
 					return nil, err
 				}
-				response[logType.Name] = logTypeMeta
+				if logTypeMeta != nil {
+					response[logType.Name] = logTypeMeta
+				}
 			}
 		}
 	}
 	return response, nil
 }
 
-func (s *service) listenForChanges(context *endly.Context, request *ListenRequest) error {
-	var target, err = context.ExpandResource(request.Source)
+//activeTypes returns log types that have not been stopped or registered again
+func (s *service) activeTypes(logTypes []*Type) []*Type {
+	s.Mutex().Lock()
+	defer s.Mutex().Unlock()
+	var state = s.State()
+	var result = make([]*Type, 0)
+	for _, logType := range logTypes {
+		if meta, ok := state.Get(logTypeMetaKey(logType.Name)).(*TypeMeta); ok && meta.LogType == logType {
+			result = append(result, logType)
+		}
+	}
+	return result
+}
+
+func sourceHost(source *url.Resource) string {
+	if source == nil || source.ParsedURL == nil {
+		return ""
+	}
+	return source.ParsedURL.Host
+}
+
+func (s *service) listenForChanges(context *endly.Context, request *ListenRequest, source *url.Resource, monitor *watchdog) error {
+	var target, err = context.ExpandResource(source)
 	if err != nil {
 		return err
 	}
-	fs, err := estorage.StorageService(context, target)
+	fs, err := NewSource(context, target)
 	if err != nil {
 		return err
 	}
 	go func() {
-		defer fs.Close(request.Source.URL)
+		defer fs.Close(source.URL)
 		frequency := time.Duration(request.FrequencyMs) * time.Millisecond
 		if request.FrequencyMs <= 0 {
 			frequency = 400 * time.Millisecond
 		}
-		for !context.IsClosed() {
-			_, err := s.readLogFiles(context, fs, target, request.Types...)
+		for {
+			logTypes := s.activeTypes(request.Types)
+			if len(logTypes) == 0 {
+				return
+			}
+			_, err := s.readLogFiles(context, fs, target, true, logTypes...)
 			if err != nil {
 				log.Printf("failed to load log types %v", err)
 				break
 			}
-			time.Sleep(frequency)
+			if monitor != nil {
+				monitor.check()
+			}
+			select {
+			case <-context.Done():
+				return
+			case <-time.After(frequency):
+			}
 		}
 
 	}()
@@ -345,35 +616,33 @@ func (s *service) listenForChanges(context *endly.Context, request *ListenReques
 }
 
 func (s *service) listen(context *endly.Context, request *ListenRequest) (*ListenResponse, error) {
-	var source, err = context.ExpandResource(request.Source)
-	if err != nil {
-		return nil, err
-	}
 	var state = s.State()
 	for _, logType := range request.Types {
+		if _, err := logType.Parser(); err != nil {
+			return nil, err
+		}
+		if _, err := logType.GetIndexExprs(); err != nil {
+			return nil, err
+		}
 		if state.Has(logTypeMetaKey(logType.Name)) {
 			return nil, fmt.Errorf("listener has been already register for %v", logType.Name)
 		}
 	}
-
-	fs, err := estorage.StorageService(context, source)
-	if err != nil {
-		return nil, err
-	}
-	source, storageOpts, err := estorage.GetResourceWithOptions(context, source)
-	err = fs.Init(context.Background(), source.URL, storageOpts...)
-	if err != nil {
-		return nil, err
-	}
-
-	logTypeMetas, err := s.readLogFiles(context, fs, source, request.Types...)
-	if err != nil {
-		return nil, err
+	var logTypeMetas TypesMeta = make(map[string]*TypeMeta)
+	sources := request.AllSources()
+	for _, source := range sources {
+		metas, err := s.readSource(context, source, request.Types)
+		if err != nil {
+			return nil, err
+		}
+		for name, meta := range metas {
+			logTypeMetas[name] = meta
+		}
 	}
 	for _, logType := range request.Types {
 		logMeta, ok := logTypeMetas[logType.Name]
 		if !ok {
-			logMeta = NewTypeMeta(source, logType)
+			logMeta = NewTypeMeta(sources[0], logType)
 			logTypeMetas[logType.Name] = logMeta
 		}
 		state.Put(logTypeMetaKey(logType.Name), logMeta)
@@ -382,8 +651,44 @@ func (s *service) listen(context *endly.Context, request *ListenRequest) (*Liste
 	response := &ListenResponse{
 		Meta: logTypeMetas,
 	}
-	err = s.listenForChanges(context, request)
-	return response, err
+	var monitor *watchdog
+	if request.Watchdog != nil { //records already present in the log are not observed
+		monitor = newWatchdog(context, request.Watchdog, request.Types)
+		for _, logType := range request.Types {
+			logType.watchdog = monitor
+		}
+	}
+	for _, source := range sources {
+		if err := s.listenForChanges(context, request, source, monitor); err != nil {
+			return nil, err
+		}
+	}
+	context.PersistentSession().Track(ServiceID, "listen", request)
+	return response, nil
+}
+
+//readSource reads log files from supplied source
+func (s *service) readSource(context *endly.Context, source *url.Resource, logTypes []*Type) (TypesMeta, error) {
+	source, err := context.ExpandResource(source)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := NewSource(context, source)
+	if err != nil {
+		return nil, err
+	}
+	if service, ok := fs.(afs.Service); ok {
+		resource, storageOpts, err := estorage.GetResourceWithOptions(context, source)
+		if err != nil {
+			return nil, err
+		}
+		if err = service.Init(context.Background(), resource.URL, storageOpts...); err != nil {
+			return nil, err
+		}
+	} else { //dedicated source connection is only used for the initial read
+		defer fs.Close(source.URL)
+	}
+	return s.readLogFiles(context, fs, source, false, logTypes...)
 }
 
 const (
@@ -498,6 +803,25 @@ func (s *service) registerRoutes() {
 		},
 	})
 
+	s.Register(&endly.Route{
+		Action: "stop",
+		RequestInfo: &endly.ActionInfo{
+			Description: "stop listening for log types changes, discard queued logs",
+		},
+		RequestProvider: func() interface{} {
+			return &StopRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &StopResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*StopRequest); ok {
+				return s.stop(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
 	s.Register(&endly.Route{
 		Action: "reset",
 		RequestInfo: &endly.ActionInfo{
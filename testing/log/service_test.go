@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
 	"github.com/viant/endly/testing/log"
 	"github.com/viant/endly/util"
 	"github.com/viant/toolbox"
@@ -347,3 +348,321 @@ func TestLogWithUTF8ValidatorService_NewRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestLogValidatorService_Stop(t *testing.T) {
+	directory, err := ioutil.TempDir("", "endly_log_stop")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(directory)
+	err = ioutil.WriteFile(path.Join(directory, "app.log"), []byte("{\"id\":1}\n"), 0644)
+	assert.Nil(t, err)
+
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	listen := func(mask string) error {
+		return endly.Run(context, &log.ListenRequest{
+			FrequencyMs: 50,
+			Source:      url.NewResource(directory),
+			Types: []*log.Type{
+				{Name: "app", Mask: mask},
+			},
+		}, nil)
+	}
+	assert.Nil(t, listen("*.log"))
+	assert.NotNil(t, listen("*.log"), "already registered")
+
+	var response = &log.StopResponse{}
+	err = endly.Run(context, &log.StopRequest{LogTypes: []string{"app", "unknown"}}, response)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"app"}, response.LogTypes)
+
+	err = endly.Run(context, &log.AssertRequest{
+		Expect: []*log.TypedRecord{{Type: "app", Records: []interface{}{map[string]interface{}{"id": 1}}}},
+	}, nil)
+	assert.NotNil(t, err, "stopped type")
+	assert.Nil(t, listen("app*"))
+}
+
+func TestLogValidatorService_AssertAbsence(t *testing.T) {
+	directory, err := ioutil.TempDir("", "endly_log_absence")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(directory)
+	err = ioutil.WriteFile(path.Join(directory, "app.log"), []byte("{\"level\":\"info\",\"id\":1}\n{\"level\":\"error\",\"id\":2}\n"), 0644)
+	assert.Nil(t, err)
+
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	err = endly.Run(context, &log.ListenRequest{
+		FrequencyMs: 50,
+		Source:      url.NewResource(directory),
+		Types:       []*log.Type{{Name: "absence", Mask: "*.log"}},
+	}, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	var useCases = []struct {
+		description string
+		records     []interface{}
+		failed      int
+	}{
+		{description: "absent", records: []interface{}{map[string]interface{}{"level": "fatal"}, "/panic/"}, failed: 0},
+		{description: "present map", records: []interface{}{map[string]interface{}{"level": "error"}}, failed: 1},
+		{description: "present text", records: []interface{}{"/error/"}, failed: 1},
+	}
+	for _, useCase := range useCases {
+		var response = &log.AssertResponse{}
+		err = endly.Run(context, &log.AssertRequest{
+			LogWaitTimeMs:     10,
+			LogWaitRetryCount: 1,
+			Expect:            []*log.TypedRecord{{Type: "absence", Absent: true, Records: useCase.records}},
+		}, response)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.failed, response.Validations[0].FailedCount, useCase.description)
+	}
+}
+
+func TestLogValidatorService_AssertMatchPolicy(t *testing.T) {
+	var useCases = []struct {
+		description string
+		match       string
+		records     []interface{}
+		failed      int
+	}{
+		{
+			description: "unordered",
+			match:       log.MatchUnordered,
+			records:     []interface{}{map[string]interface{}{"id": 3}, map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2}},
+			failed:      0,
+		},
+		{
+			description: "unordered with unmatched record",
+			match:       log.MatchUnordered,
+			records:     []interface{}{map[string]interface{}{"id": 3}, map[string]interface{}{"id": 1}},
+			failed:      1,
+		},
+		{
+			description: "subset",
+			match:       log.MatchSubset,
+			records:     []interface{}{map[string]interface{}{"id": 2}},
+			failed:      0,
+		},
+		{
+			description: "subset missing record",
+			match:       log.MatchSubset,
+			records:     []interface{}{map[string]interface{}{"id": 4}},
+			failed:      1,
+		},
+	}
+	for _, useCase := range useCases {
+		directory, err := ioutil.TempDir("", "endly_log_match")
+		if !assert.Nil(t, err) {
+			return
+		}
+		err = ioutil.WriteFile(path.Join(directory, "app.log"), []byte("{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n"), 0644)
+		assert.Nil(t, err)
+		manager := endly.New()
+		context := manager.NewContext(toolbox.NewContext())
+		err = endly.Run(context, &log.ListenRequest{
+			FrequencyMs: 50,
+			Source:      url.NewResource(directory),
+			Types:       []*log.Type{{Name: "match", Mask: "*.log"}},
+		}, nil)
+		if assert.Nil(t, err, useCase.description) {
+			var response = &log.AssertResponse{}
+			err = endly.Run(context, &log.AssertRequest{
+				LogWaitTimeMs:     10,
+				LogWaitRetryCount: 1,
+				Expect:            []*log.TypedRecord{{Type: "match", Match: useCase.match, Records: useCase.records}},
+			}, response)
+			if assert.Nil(t, err, useCase.description) {
+				assert.Equal(t, useCase.failed, response.Validations[0].FailedCount, useCase.description)
+			}
+		}
+		context.Close()
+		_ = os.RemoveAll(directory)
+	}
+}
+
+func TestLogValidatorService_AssertCapture(t *testing.T) {
+	var useCases = []struct {
+		description string
+		match       string
+		records     []interface{}
+		capture     map[string]string
+		expect      map[string]interface{}
+		failed      int
+	}{
+		{
+			description: "ordered capture",
+			records:     []interface{}{map[string]interface{}{"event": "created"}},
+			capture:     map[string]string{"txID": "tx.id"},
+			expect:      map[string]interface{}{"txID": "tx-101"},
+		},
+		{
+			description: "unordered capture",
+			match:       log.MatchSubset,
+			records:     []interface{}{map[string]interface{}{"event": "settled"}},
+			capture:     map[string]string{"txID": "tx.id", "amount": "tx.amount"},
+			expect:      map[string]interface{}{"txID": "tx-102", "amount": 12.5},
+		},
+		{
+			description: "missing field",
+			records:     []interface{}{map[string]interface{}{"event": "created"}},
+			capture:     map[string]string{"txID": "tx.uuid"},
+			failed:      1,
+		},
+	}
+	for _, useCase := range useCases {
+		directory, err := ioutil.TempDir("", "endly_log_capture")
+		if !assert.Nil(t, err) {
+			return
+		}
+		err = ioutil.WriteFile(path.Join(directory, "app.log"), []byte("{\"event\":\"created\",\"tx\":{\"id\":\"tx-101\"}}\n{\"event\":\"settled\",\"tx\":{\"id\":\"tx-102\",\"amount\":12.5}}\n"), 0644)
+		assert.Nil(t, err)
+		manager := endly.New()
+		context := manager.NewContext(toolbox.NewContext())
+		err = endly.Run(context, &log.ListenRequest{
+			FrequencyMs: 50,
+			Source:      url.NewResource(directory),
+			Types:       []*log.Type{{Name: "capture", Mask: "*.log"}},
+		}, nil)
+		if assert.Nil(t, err, useCase.description) {
+			var response = &log.AssertResponse{}
+			err = endly.Run(context, &log.AssertRequest{
+				LogWaitTimeMs:     10,
+				LogWaitRetryCount: 1,
+				Expect:            []*log.TypedRecord{{Type: "capture", Match: useCase.match, Records: useCase.records, Capture: useCase.capture}},
+			}, response)
+			if assert.Nil(t, err, useCase.description) {
+				assert.Equal(t, useCase.failed, response.Validations[0].FailedCount, useCase.description)
+				state := context.State()
+				for name, value := range useCase.expect {
+					assert.EqualValues(t, value, response.Captured[name], useCase.description)
+					assert.EqualValues(t, value, state.Get(name), useCase.description)
+				}
+			}
+		}
+		context.Close()
+		_ = os.RemoveAll(directory)
+	}
+}
+
+func TestLogValidatorService_ListenSources(t *testing.T) {
+	var sources = make([]*url.Resource, 0)
+	for i := 1; i <= 2; i++ {
+		directory, err := ioutil.TempDir("", "endly_log_host")
+		if !assert.Nil(t, err) {
+			return
+		}
+		defer os.RemoveAll(directory)
+		err = ioutil.WriteFile(path.Join(directory, "app.log"), []byte(fmt.Sprintf("{\"node\":%v}\n", i)), 0644)
+		assert.Nil(t, err)
+		sources = append(sources, url.NewResource(directory))
+	}
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	defer context.Close()
+	var listenResponse = &log.ListenResponse{}
+	err := endly.Run(context, &log.ListenRequest{
+		FrequencyMs: 50,
+		Sources:     sources,
+		Types:       []*log.Type{{Name: "cluster", Mask: "*.log"}},
+	}, listenResponse)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, len(listenResponse.Meta["cluster"].LogFiles))
+	var response = &log.AssertResponse{}
+	err = endly.Run(context, &log.AssertRequest{
+		LogWaitTimeMs:     10,
+		LogWaitRetryCount: 1,
+		Expect: []*log.TypedRecord{{Type: "cluster", Match: log.MatchUnordered, Records: []interface{}{
+			map[string]interface{}{"node": 2},
+			map[string]interface{}{"node": 1},
+		}}},
+	}, response)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 0, response.Validations[0].FailedCount)
+	}
+}
+
+func TestLogValidatorService_Watchdog(t *testing.T) {
+	var useCases = []struct {
+		description string
+		watchdog    *log.Watchdog
+		lines       string
+		reason      string
+		canceled    bool
+	}{
+		{
+			description: "idle timeout event",
+			watchdog:    &log.Watchdog{IdleTimeoutMs: 200, Action: log.WatchdogActionEvent},
+			reason:      "no new records",
+		},
+		{
+			description: "error rate fail",
+			watchdog:    &log.Watchdog{ErrorPattern: `"level":"error"`, MaxErrors: 1},
+			lines:       "{\"level\":\"error\",\"id\":2}\n{\"level\":\"info\",\"id\":3}\n{\"level\":\"error\",\"id\":4}\n",
+			reason:      "2 records matched error pattern",
+			canceled:    true,
+		},
+		{
+			description: "error rate within limit",
+			watchdog:    &log.Watchdog{ErrorPattern: `"level":"error"`, MaxErrors: 1},
+			lines:       "{\"level\":\"error\",\"id\":2}\n",
+		},
+	}
+	for _, useCase := range useCases {
+		directory, err := ioutil.TempDir("", "endly_log_watchdog")
+		if !assert.Nil(t, err) {
+			return
+		}
+		logFile := path.Join(directory, "app.log")
+		err = ioutil.WriteFile(logFile, []byte("{\"level\":\"error\",\"id\":1}\n"), 0644)
+		assert.Nil(t, err)
+
+		manager := endly.New()
+		context := manager.NewContext(toolbox.NewContext())
+		var events = make(chan *log.WatchdogEvent, 10)
+		context.SetListener(func(event msg.Event) {
+			if watchdogEvent, ok := event.Value().(*log.WatchdogEvent); ok {
+				events <- watchdogEvent
+			}
+		})
+		err = endly.Run(context, &log.ListenRequest{
+			FrequencyMs: 50,
+			Source:      url.NewResource(directory),
+			Types:       []*log.Type{{Name: "watchdog", Mask: "*.log"}},
+			Watchdog:    useCase.watchdog,
+		}, nil)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		if useCase.lines != "" {
+			err = ioutil.WriteFile(logFile, []byte("{\"level\":\"error\",\"id\":1}\n"+useCase.lines), 0644)
+			assert.Nil(t, err)
+		}
+		select {
+		case event := <-events:
+			if assert.NotEqual(t, "", useCase.reason, useCase.description+": "+event.Reason) {
+				assert.Contains(t, event.Reason, useCase.reason, useCase.description)
+				assert.Equal(t, []string{"watchdog"}, event.Types, useCase.description)
+			}
+		case <-time.After(600 * time.Millisecond):
+			assert.Equal(t, "", useCase.reason, useCase.description)
+		}
+		assert.Equal(t, useCase.canceled, context.Err() != nil, useCase.description)
+		context.Close()
+		os.RemoveAll(directory)
+	}
+	assert.NotNil(t, (&log.Watchdog{Action: log.WatchdogActionFail}).Validate())
+	assert.NotNil(t, (&log.Watchdog{ErrorPattern: "(", Action: log.WatchdogActionFail}).Validate())
+	assert.NotNil(t, (&log.Watchdog{IdleTimeoutMs: 10, Action: "stop"}).Validate())
+}
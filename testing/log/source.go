@@ -0,0 +1,410 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"github.com/jlaffaye/ftp"
+	"github.com/pkg/sftp"
+	"github.com/viant/afs"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/object"
+	"github.com/viant/afs/storage"
+	"github.com/viant/endly"
+	estorage "github.com/viant/endly/system/storage"
+	"github.com/viant/toolbox/cred"
+	"github.com/viant/toolbox/url"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const sourceTimeout = 30 * time.Second
+
+var hrefExpr = regexp.MustCompile(`(?i)href\s*=\s*["']([^"']+)["']`)
+
+//Source represents a log source file system, afs.Service is used unless a source scheme requires dedicated listing
+type Source interface {
+	storage.Lister
+	storage.Getter
+	//Open returns log file reader, reader implementing io.Seeker is used for incremental reads
+	Open(ctx context.Context, object storage.Object, options ...storage.Option) (io.ReadCloser, error)
+	//Close closes source connection
+	Close(URL string) error
+}
+
+//RangeSource represents a source reading file content range, so that only appended log content is transferred
+type RangeSource interface {
+	//ReadRange reads length bytes from offset, it returns false if ranged reads are not supported for the object
+	ReadRange(ctx context.Context, object storage.Object, offset, length int64) ([]byte, bool, error)
+}
+
+//NewSource returns log source for supplied resource, sftp://, ftp:// and http(s):// index page sources are listed with dedicated clients
+func NewSource(context *endly.Context, resource *url.Resource) (Source, error) {
+	switch resource.ParsedURL.Scheme {
+	case "sftp":
+		return newSFTPSource(context, resource)
+	case "ftp":
+		return newFTPSource(context, resource)
+	case "http", "https":
+		return newHTTPSource(context, resource)
+	}
+	service, err := estorage.StorageService(context, resource)
+	if err != nil {
+		return nil, err
+	}
+	return &storageSource{Service: service, context: context, credentials: resource.Credentials, readers: make(map[string]func(offset, length int64) ([]byte, error))}, nil
+}
+
+//storageSource represents afs storage log source, file, s3, gs and scp objects are read with ranged reads
+type storageSource struct {
+	afs.Service
+	context     *endly.Context
+	credentials string
+	mux         sync.Mutex
+	readers     map[string]func(offset, length int64) ([]byte, error)
+}
+
+func (s *storageSource) ReadRange(ctx context.Context, object storage.Object, offset, length int64) ([]byte, bool, error) {
+	s.mux.Lock()
+	read, ok := s.readers[object.URL()]
+	if !ok {
+		var err error
+		if read, err = estorage.NewRangeReader(s.context, url.NewResource(object.URL(), s.credentials)); err != nil {
+			s.mux.Unlock()
+			return nil, false, err
+		}
+		s.readers[object.URL()] = read
+	}
+	s.mux.Unlock()
+	if read == nil {
+		return nil, false, nil
+	}
+	data, err := read(offset, length)
+	return data, true, err
+}
+
+func sourceCredentials(context *endly.Context, resource *url.Resource) (*cred.Config, error) {
+	if resource.Credentials == "" {
+		return &cred.Config{}, nil
+	}
+	return context.Credentials(resource.Credentials)
+}
+
+func hostWithPort(parsed *neturl.URL, defaultPort string) string {
+	if parsed.Port() != "" {
+		return parsed.Host
+	}
+	return parsed.Hostname() + ":" + defaultPort
+}
+
+func urlPath(URL string) string {
+	parsed, err := neturl.Parse(URL)
+	if err != nil || parsed.Path == "" {
+		return "/"
+	}
+	return parsed.Path
+}
+
+func newObject(baseURL string, info os.FileInfo) storage.Object {
+	return object.New(strings.TrimRight(baseURL, "/")+"/"+info.Name(), info, nil)
+}
+
+//sftpSource represents SSH file transfer protocol log source
+type sftpSource struct {
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+func (s *sftpSource) List(ctx context.Context, URL string, options ...storage.Option) ([]storage.Object, error) {
+	infos, err := s.client.ReadDir(urlPath(URL))
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]storage.Object, 0)
+	for _, info := range infos {
+		result = append(result, newObject(URL, info))
+	}
+	return result, nil
+}
+
+func (s *sftpSource) Object(ctx context.Context, URL string, options ...storage.Option) (storage.Object, error) {
+	info, err := s.client.Stat(urlPath(URL))
+	if err != nil {
+		return nil, err
+	}
+	return object.New(URL, info, nil), nil
+}
+
+func (s *sftpSource) Open(ctx context.Context, object storage.Object, options ...storage.Option) (io.ReadCloser, error) {
+	return s.client.Open(urlPath(object.URL()))
+}
+
+func (s *sftpSource) Close(URL string) error {
+	_ = s.client.Close()
+	return s.conn.Close()
+}
+
+func newSFTPSource(context *endly.Context, resource *url.Resource) (Source, error) {
+	credConfig, err := sourceCredentials(context, resource)
+	if err != nil {
+		return nil, err
+	}
+	config, err := credConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = sourceTimeout
+	conn, err := ssh.Dial("tcp", hostWithPort(resource.ParsedURL, "22"), config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %v, %v", resource.URL, err)
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to start sftp session %v, %v", resource.URL, err)
+	}
+	return &sftpSource{conn: conn, client: client}, nil
+}
+
+//ftpSource represents file transfer protocol log source
+type ftpSource struct {
+	conn *ftp.ServerConn
+}
+
+func (s *ftpSource) List(ctx context.Context, URL string, options ...storage.Option) ([]storage.Object, error) {
+	entries, err := s.conn.List(urlPath(URL))
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]storage.Object, 0)
+	for _, entry := range entries {
+		if entry.Name == "." || entry.Name == ".." {
+			continue
+		}
+		isDir := entry.Type == ftp.EntryTypeFolder
+		info := file.NewInfo(path.Base(entry.Name), int64(entry.Size), file.DefaultFileOsMode, entry.Time, isDir)
+		result = append(result, newObject(URL, info))
+	}
+	return result, nil
+}
+
+func (s *ftpSource) Object(ctx context.Context, URL string, options ...storage.Option) (storage.Object, error) {
+	parent, name := path.Split(strings.TrimRight(URL, "/"))
+	objects, err := s.List(ctx, parent, options...)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range objects {
+		if candidate.Name() == name {
+			return candidate, nil
+		}
+	}
+	return nil, fmt.Errorf("%v: not found", URL)
+}
+
+func (s *ftpSource) Open(ctx context.Context, object storage.Object, options ...storage.Option) (io.ReadCloser, error) {
+	reader := &ftpReader{conn: s.conn, path: urlPath(object.URL())}
+	return reader, reader.retrieve(0)
+}
+
+func (s *ftpSource) Close(URL string) error {
+	return s.conn.Quit()
+}
+
+//ftpReader represents ftp file reader, seek restarts transfer from supplied offset
+type ftpReader struct {
+	conn     *ftp.ServerConn
+	path     string
+	response *ftp.Response
+}
+
+func (r *ftpReader) retrieve(offset int64) (err error) {
+	_ = r.Close()
+	r.response, err = r.conn.RetrFrom(r.path, uint64(offset))
+	return err
+}
+
+func (r *ftpReader) Read(data []byte) (int, error) {
+	if r.response == nil {
+		return 0, io.EOF
+	}
+	read, err := r.response.Read(data)
+	if err == io.EOF { //transfer has to be completed before the next ftp command
+		if closeErr := r.Close(); closeErr != nil {
+			return read, closeErr
+		}
+	}
+	return read, err
+}
+
+func (r *ftpReader) Seek(offset int64, whence int) (int64, error) {
+	if whence != io.SeekStart {
+		return 0, fmt.Errorf("unsupported ftp seek whence: %v", whence)
+	}
+	return offset, r.retrieve(offset)
+}
+
+func (r *ftpReader) Close() error {
+	if r.response == nil {
+		return nil
+	}
+	response := r.response
+	r.response = nil
+	return response.Close()
+}
+
+func newFTPSource(context *endly.Context, resource *url.Resource) (Source, error) {
+	credConfig, err := sourceCredentials(context, resource)
+	if err != nil {
+		return nil, err
+	}
+	username, password := credConfig.Username, credConfig.Password
+	if username == "" {
+		username, password = "anonymous", "anonymous"
+	}
+	conn, err := ftp.Dial(hostWithPort(resource.ParsedURL, "21"), ftp.DialWithTimeout(sourceTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %v, %v", resource.URL, err)
+	}
+	if err = conn.Login(username, password); err != nil {
+		_ = conn.Quit()
+		return nil, fmt.Errorf("failed to login to %v, %v", resource.URL, err)
+	}
+	return &ftpSource{conn: conn}, nil
+}
+
+//httpSource represents http(s) log source, a directory is listed with its index page links
+type httpSource struct {
+	client   *http.Client
+	username string
+	password string
+}
+
+func (s *httpSource) do(ctx context.Context, method, URL string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, method, URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.username != "" {
+		request.SetBasicAuth(s.username, s.password)
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("failed to %v %v, status: %v", method, URL, response.Status)
+	}
+	return response, nil
+}
+
+//List returns files linked from the index page, parent, sorting and sub directory links are skipped
+func (s *httpSource) List(ctx context.Context, URL string, options ...storage.Option) ([]storage.Object, error) {
+	baseURL := strings.TrimRight(URL, "/") + "/"
+	base, err := neturl.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.do(ctx, http.MethodGet, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	page, err := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	var result = make([]storage.Object, 0)
+	var listed = make(map[string]bool)
+	for _, match := range hrefExpr.FindAllStringSubmatch(string(page), -1) {
+		link, err := base.Parse(match[1])
+		if err != nil || link.Host != base.Host || link.RawQuery != "" {
+			continue
+		}
+		name := strings.TrimPrefix(link.Path, base.Path)
+		if name == "" || name == link.Path || strings.Contains(name, "/") || listed[name] {
+			continue
+		}
+		listed[name] = true
+		candidate, err := s.Object(ctx, baseURL+name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, candidate)
+	}
+	return result, nil
+}
+
+func (s *httpSource) Object(ctx context.Context, URL string, options ...storage.Option) (storage.Object, error) {
+	response, err := s.do(ctx, http.MethodHead, URL)
+	if err != nil {
+		return nil, err
+	}
+	_ = response.Body.Close()
+	modified, _ := http.ParseTime(response.Header.Get("Last-Modified"))
+	info := file.NewInfo(path.Base(urlPath(URL)), response.ContentLength, file.DefaultFileOsMode, modified, false)
+	return object.New(URL, info, nil), nil
+}
+
+func (s *httpSource) Open(ctx context.Context, object storage.Object, options ...storage.Option) (io.ReadCloser, error) {
+	response, err := s.do(ctx, http.MethodGet, object.URL())
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+//ReadRange reads content range, it returns false if server does not respond with partial content
+func (s *httpSource) ReadRange(ctx context.Context, object storage.Object, offset, length int64) ([]byte, bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, object.URL(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if s.username != "" {
+		request.SetBasicAuth(s.username, s.password)
+	}
+	request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, false, err
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("failed to GET %v, status: %v", object.URL(), response.Status)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(response.Body, length))
+	if err == nil && int64(len(data)) != length {
+		err = fmt.Errorf("range error, expected: %v, but had: %v", length, len(data))
+	}
+	return data, true, err
+}
+
+func (s *httpSource) Close(URL string) error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+func newHTTPSource(context *endly.Context, resource *url.Resource) (Source, error) {
+	credConfig, err := sourceCredentials(context, resource)
+	if err != nil {
+		return nil, err
+	}
+	return &httpSource{
+		client:   &http.Client{Timeout: sourceTimeout},
+		username: credConfig.Username,
+		password: credConfig.Password,
+	}, nil
+}
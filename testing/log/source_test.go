@@ -0,0 +1,91 @@
+package log
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewSource_HTTPIndex(t *testing.T) {
+	var logs = map[string]string{
+		"/logs/app1.log": "line 1\nline 2\n",
+		"/logs/app2.log": "line 3\n",
+	}
+	modified := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/logs/" {
+			_, _ = writer.Write([]byte(`<html><body>
+<a href="?C=N;O=D">Name</a>
+<a href="../">Parent Directory</a>
+<a href="app1.log">app1.log</a>
+<a href='/logs/app2.log'>app2.log</a>
+<a href="/logs/app1.log">app1.log</a>
+<a href="archive/">archive/</a>
+<a href="http://example.com/logs/other.log">other.log</a>
+</body></html>`))
+			return
+		}
+		content, ok := logs[request.URL.Path]
+		if !ok {
+			http.NotFound(writer, request)
+			return
+		}
+		writer.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		http.ServeContent(writer, request, request.URL.Path, modified, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	manager := endly.New()
+	endlyContext := manager.NewContext(toolbox.NewContext())
+	defer endlyContext.Close()
+	source, err := NewSource(endlyContext, url.NewResource(server.URL+"/logs"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer source.Close(server.URL)
+	objects, err := source.List(context.Background(), server.URL+"/logs")
+	if !assert.Nil(t, err) {
+		return
+	}
+	var names = make([]string, 0)
+	for _, object := range objects {
+		names = append(names, object.Name())
+		assert.Equal(t, int64(len(logs["/logs/"+object.Name()])), object.Size(), object.Name())
+		assert.Equal(t, modified.Unix(), object.ModTime().Unix(), object.Name())
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"app1.log", "app2.log"}, names)
+
+	logFile := &File{
+		Type:            &Type{Name: "app"},
+		ProcessingState: &ProcessingState{},
+		Mutex:           &sync.RWMutex{},
+		Records:         make([]*Record, 0),
+		IndexedRecords:  make(map[string]*Record),
+	}
+	object, err := source.Object(context.Background(), server.URL+"/logs/app1.log")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, logFile.readIncrementally(context.Background(), source, object))
+	assert.Equal(t, 2, len(logFile.Records))
+
+	logs["/logs/app1.log"] += "line 4\n"
+	object, err = source.Object(context.Background(), server.URL+"/logs/app1.log")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, logFile.readIncrementally(context.Background(), source, object))
+	if assert.Equal(t, 3, len(logFile.Records)) {
+		assert.Equal(t, "line 4", logFile.Records[2].Line)
+	}
+}
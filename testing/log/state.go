@@ -4,6 +4,7 @@ package log
 type ProcessingState struct {
 	Line     int
 	Position int
+	Head     string //leading file content fingerprint used to detect log rotation
 }
 
 //Update updates processed position and line number
@@ -17,4 +18,5 @@ func (s *ProcessingState) Update(position, lineNumber int) (string, int) {
 func (s *ProcessingState) Reset() {
 	s.Line = 0
 	s.Position = 0
+	s.Head = ""
 }
@@ -0,0 +1,143 @@
+package log
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//WatchdogActionFail represents watchdog action canceling running workflow
+	WatchdogActionFail = "fail"
+	//WatchdogActionEvent represents watchdog action publishing watchdog event only
+	WatchdogActionEvent = "event"
+)
+
+//Watchdog represents log listener watchdog, it turns listener into active monitor
+type Watchdog struct {
+	IdleTimeoutMs int    `description:"max time without new log records, once exceeded watchdog is triggered"`
+	ErrorPattern  string `description:"regular expression matching error log records"`
+	MaxErrors     int    `description:"max number of records matching error pattern, once exceeded watchdog is triggered"`
+	Action        string `description:"triggered watchdog action: fail (default) - publishes watchdog event and cancels running workflow, event - publishes watchdog event only"`
+	errorExpr     *regexp.Regexp
+}
+
+//Init initialises watchdog
+func (w *Watchdog) Init() error {
+	if w.Action == "" {
+		w.Action = WatchdogActionFail
+	}
+	return nil
+}
+
+//Validate checks if watchdog is valid
+func (w *Watchdog) Validate() (err error) {
+	if w.IdleTimeoutMs <= 0 && w.ErrorPattern == "" {
+		return fmt.Errorf("watchdog IdleTimeoutMs and ErrorPattern were empty")
+	}
+	if w.MaxErrors < 0 {
+		return fmt.Errorf("invalid watchdog MaxErrors: %v", w.MaxErrors)
+	}
+	switch w.Action {
+	case WatchdogActionFail, WatchdogActionEvent:
+	default:
+		return fmt.Errorf("unsupported watchdog action: %v, supported: %v, %v", w.Action, WatchdogActionFail, WatchdogActionEvent)
+	}
+	if w.ErrorPattern != "" {
+		if w.errorExpr, err = regexp.Compile(w.ErrorPattern); err != nil {
+			return fmt.Errorf("invalid watchdog ErrorPattern: %v, %v", w.ErrorPattern, err)
+		}
+	}
+	return nil
+}
+
+//WatchdogEvent represents triggered log listener watchdog
+type WatchdogEvent struct {
+	Types  []string
+	Reason string
+	Action string
+	Record *Record `json:",omitempty"` //the last error record
+}
+
+//Messages returns messages
+func (e *WatchdogEvent) Messages() []*msg.Message {
+	var items = []*msg.Styled{msg.NewStyled(e.Reason, msg.MessageStyleError)}
+	if e.Record != nil {
+		items = append(items, msg.NewStyled(fmt.Sprintf("%v:%v %v", e.Record.URL, e.Record.Number, e.Record.Line), msg.MessageStyleOutput))
+	}
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(strings.Join(e.Types, ","), msg.MessageStyleGeneric), msg.NewStyled("watchdog", msg.MessageStyleError), items...),
+	}
+}
+
+//watchdog represents listen request watchdog state shared by all request log types and sources
+type watchdog struct {
+	*Watchdog
+	context    *endly.Context
+	types      []string
+	mux        *sync.Mutex
+	lastRecord time.Time
+	idle       bool
+	errors     int
+	exceeded   bool
+}
+
+//observe counts error records and re-arms idle timeout
+func (w *watchdog) observe(record *Record) {
+	w.mux.Lock()
+	w.lastRecord = time.Now()
+	w.idle = false
+	if w.errorExpr == nil || !w.errorExpr.MatchString(record.Line) {
+		w.mux.Unlock()
+		return
+	}
+	w.errors++
+	if w.exceeded || w.errors <= w.MaxErrors {
+		w.mux.Unlock()
+		return
+	}
+	w.exceeded = true
+	w.mux.Unlock()
+	w.trigger(fmt.Sprintf("%v records matched error pattern %v, max: %v", w.errors, w.ErrorPattern, w.MaxErrors), record)
+}
+
+//check triggers watchdog if no new records arrived within idle timeout
+func (w *watchdog) check() {
+	if w.IdleTimeoutMs <= 0 {
+		return
+	}
+	w.mux.Lock()
+	elapsed := time.Since(w.lastRecord)
+	if w.idle || elapsed < time.Duration(w.IdleTimeoutMs)*time.Millisecond {
+		w.mux.Unlock()
+		return
+	}
+	w.idle = true
+	w.mux.Unlock()
+	w.trigger(fmt.Sprintf("no new records for %v", elapsed.Round(time.Millisecond)), nil)
+}
+
+func (w *watchdog) trigger(reason string, record *Record) {
+	w.context.Publish(&WatchdogEvent{Types: w.types, Reason: reason, Action: w.Action, Record: record})
+	if w.Action == WatchdogActionFail {
+		w.context.Cancel()
+	}
+}
+
+func newWatchdog(context *endly.Context, config *Watchdog, logTypes []*Type) *watchdog {
+	var types = make([]string, 0, len(logTypes))
+	for _, logType := range logTypes {
+		types = append(types, logType.Name)
+	}
+	return &watchdog{
+		Watchdog:   config,
+		context:    context,
+		types:      types,
+		mux:        &sync.Mutex{},
+		lastRecord: time.Now(),
+	}
+}
@@ -379,7 +379,13 @@ func (c *awsClient) Close() error {
 }
 
 func newAwsSqsClient(credConfig *cred.Config, timeout time.Duration) (Client, error) {
-	config, err := eaws.GetAWSCredentialConfig(credConfig)
+	var config *aws.Config
+	var err error
+	if credConfig.Key == "" {
+		config, err = eaws.GetAmbientConfig(credConfig)
+	} else {
+		config, err = eaws.GetAWSCredentialConfig(credConfig)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +32,7 @@ func NewPubSubClient(context *endly.Context, dest *Resource, timeout time.Durati
 	credConfig := &cred.Config{}
 	var err error
 	if dest.Credentials != "" {
-		credConfig, err = context.Secrets.GetCredentials(dest.Credentials)
+		credConfig, err = context.Credentials(dest.Credentials)
 	}
 	if err != nil {
 		return nil, err
@@ -46,7 +46,7 @@ func NewPubSubClient(context *endly.Context, dest *Resource, timeout time.Durati
 		dest.Vendor = inferResourceTypeFromCredentialConfig(credConfig)
 	}
 
-	state := context.State()
+	state := context.SafeState()
 	if credConfig.ProjectID != "" {
 		state.SetValue("msg.projectID", credConfig.ProjectID)
 	}
@@ -148,6 +148,61 @@ type PullResponse struct {
 	Assert   *validator.AssertResponse
 }
 
+//AssertRequest represents a request to pull messages till expected messages arrive and validate them
+type AssertRequest struct {
+	Credentials    string
+	Source         *Resource
+	WaitTimeMs     int           `description:"pull wait time for each attempt, default 500"`
+	WaitRetryCount int           `description:"max number of pull attempts to collect expected messages count, default 3"`
+	Nack           bool          `description:"flag indicates that pulled messages are not acknowledged"`
+	UDF            string        `description:"udf to transform message data, transformed data is validated"`
+	Expect         []interface{} `required:"true" description:"expected messages, JSON data is validated as structured data"`
+}
+
+func (r *AssertRequest) Init() error {
+	if r.WaitTimeMs == 0 {
+		r.WaitTimeMs = 500
+	}
+	if r.WaitRetryCount == 0 {
+		r.WaitRetryCount = 3
+	}
+	if r.Source == nil {
+		return nil
+	}
+	if r.Source.Credentials == "" {
+		r.Source.Credentials = r.Credentials
+	}
+	return r.Source.Init()
+}
+
+func (r *AssertRequest) Validate() error {
+	if r.Source == nil {
+		return fmt.Errorf("source was empty")
+	}
+	if len(r.Expect) == 0 {
+		return fmt.Errorf("expect was empty")
+	}
+	return nil
+}
+
+//expectedCount returns expected messages count, assertly directives are excluded
+func (r *AssertRequest) expectedCount() int {
+	var result = 0
+	for _, expected := range r.Expect {
+		if isDirective(expected) {
+			continue
+		}
+		result++
+	}
+	return result
+}
+
+//AssertResponse represents an assert response
+type AssertResponse struct {
+	Messages []*Message
+	Assert   *validator.AssertResponse
+}
+
 type Message struct {
 	ID          string
 	Subject     string
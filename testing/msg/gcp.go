@@ -9,6 +9,7 @@ import (
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/cred"
 	context2 "golang.org/x/net/context"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/api/option"
 	"log"
 	"strings"
@@ -261,12 +262,19 @@ func (s *gcpClient) getTopic(dest *Resource) (*pubsub.Topic, error) {
 
 func newCloudPubSub(credConfig *cred.Config, URL string, timeout time.Duration) (Client, error) {
 	ctx := context.Background()
-	jwtConfig, err := credConfig.NewJWTConfig(pubsub.ScopePubSub)
-	if err != nil {
-		return nil, err
-	}
-	var opts = []option.ClientOption{
-		option.WithTokenSource(jwtConfig.TokenSource(ctx)),
+	var opts = []option.ClientOption{}
+	if credConfig.ClientEmail != "" {
+		jwtConfig, err := credConfig.NewJWTConfig(pubsub.ScopePubSub)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, option.WithTokenSource(jwtConfig.TokenSource(ctx)))
+	} else if credConfig.ProjectID == "" { //ambient credentials i.e. GKE workload identity
+		credentials, err := google.FindDefaultCredentials(ctx, pubsub.ScopePubSub)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find default credentials: %v", err)
+		}
+		credConfig.ProjectID = credentials.ProjectID
 	}
 	client, err := pubsub.NewClient(ctx, credConfig.ProjectID, opts...)
 	if err != nil {
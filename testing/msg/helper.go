@@ -63,7 +63,7 @@ func inferResourceTypeFromCredentialConfig(credConfig *cred.Config) string {
 }
 
 func expandResource(context *endly.Context, resource *Resource) *Resource {
-	state := context.State()
+	state := context.SafeState()
 	return &Resource{
 		URL:               state.ExpandAsText(resource.URL),
 		Type:              state.ExpandAsText(resource.Type),
@@ -74,10 +74,46 @@ func expandResource(context *endly.Context, resource *Resource) *Resource {
 		Partitions:        resource.Partitions,
 		Partition:         resource.Partition,
 		Offset:            resource.Offset,
+		GroupID:           state.ExpandAsText(resource.GroupID),
 		ReplicationFactor: resource.ReplicationFactor,
 	}
 }
 
+//normalizeData returns message with textual data, structured if message data was JSON
+func normalizeData(message *Message) *Message {
+	var text string
+	switch data := message.Data.(type) {
+	case []byte:
+		text = string(data)
+	case string:
+		text = data
+	default:
+		return message
+	}
+	var result = *message
+	result.Data = text
+	if toolbox.IsStructuredJSON(text) {
+		if decoded, err := toolbox.JSONToInterface(text); err == nil {
+			result.Data = decoded
+		}
+	}
+	return &result
+}
+
+//isDirective returns true if candidate is assertly directive only map, i.e. @indexBy@
+func isDirective(candidate interface{}) bool {
+	if !toolbox.IsMap(candidate) {
+		return false
+	}
+	aMap := toolbox.AsMap(candidate)
+	for key := range aMap {
+		if !(strings.HasPrefix(key, "@") && strings.HasSuffix(key, "@")) {
+			return false
+		}
+	}
+	return len(aMap) > 0
+}
+
 func getAttributeDataType(value interface{}) string {
 	dataType := "String"
 	if toolbox.IsInt(value) || toolbox.IsFloat(value) {
@@ -0,0 +1,50 @@
+package msg
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeData(t *testing.T) {
+	var useCases = []struct {
+		description string
+		data        interface{}
+		expect      interface{}
+	}{
+		{description: "JSON bytes", data: []byte(`{"id":1,"name":"abc"}`), expect: map[string]interface{}{"id": 1.0, "name": "abc"}},
+		{description: "JSON array text", data: `[1,2]`, expect: []interface{}{1.0, 2.0}},
+		{description: "text bytes", data: []byte("this is my 1st message"), expect: "this is my 1st message"},
+		{description: "structured data", data: map[string]interface{}{"id": 1}, expect: map[string]interface{}{"id": 1}},
+	}
+	for _, useCase := range useCases {
+		message := &Message{Data: useCase.data, Attributes: map[string]interface{}{"key": "abc"}}
+		actual := normalizeData(message)
+		assert.EqualValues(t, useCase.expect, actual.Data, useCase.description)
+		assert.EqualValues(t, "abc", actual.Attributes["key"], useCase.description)
+	}
+}
+
+func TestAssertRequest_Init(t *testing.T) {
+	request := &AssertRequest{
+		Credentials: "kafka",
+		Source:      &Resource{URL: "tcp://localhost:9092/myTopic", Vendor: ResourceVendorKafka},
+		Expect: []interface{}{
+			map[string]interface{}{"@indexBy@": "Attributes.key"},
+			map[string]interface{}{"Data": map[string]interface{}{"id": 1}},
+			map[string]interface{}{"Data": map[string]interface{}{"id": 2}},
+		},
+	}
+	if !assert.Nil(t, request.Init()) || !assert.Nil(t, request.Validate()) {
+		return
+	}
+	assert.Equal(t, 500, request.WaitTimeMs)
+	assert.Equal(t, 3, request.WaitRetryCount)
+	assert.Equal(t, 2, request.expectedCount())
+	assert.Equal(t, "myTopic", request.Source.Name)
+	assert.Equal(t, []string{"localhost:9092"}, request.Source.Brokers)
+	assert.Equal(t, "kafka", request.Source.Credentials)
+
+	request = &AssertRequest{Source: &Resource{URL: "tcp://localhost:9092/myTopic"}}
+	assert.Nil(t, request.Init())
+	assert.NotNil(t, request.Validate())
+}
@@ -47,23 +47,33 @@ func (k *kafkaClient) Push(ctx context.Context, dest *Resource, message *Message
 }
 
 func (k *kafkaClient) PullN(ctx context.Context, source *Resource, count int, nack bool) ([]*Message, error) {
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:   source.Brokers,
-		Topic:     source.Name,
-		Partition: source.Partition,
-		MinBytes:  10e3, // 10KB
-		MaxBytes:  10e6, // 10MB
-		MaxWait:   k.timeout,
-	})
-	if source.Offset > 0 {
+	config := kafka.ReaderConfig{
+		Brokers:  source.Brokers,
+		Topic:    source.Name,
+		GroupID:  source.GroupID,
+		MinBytes: 10e3, // 10KB
+		MaxBytes: 10e6, // 10MB
+		MaxWait:  k.timeout,
+	}
+	if source.GroupID == "" {
+		config.Partition = source.Partition
+	}
+	reader := kafka.NewReader(config)
+	defer reader.Close()
+	if source.Offset > 0 && source.GroupID == "" {
 		if err := reader.SetOffset(int64(source.Offset)); err != nil {
 			return nil, errors.Wrapf(err, "failed to set offset: %v", source.Offset)
 		}
 	}
+	ctx, cancel := context.WithTimeout(ctx, k.timeout)
+	defer cancel()
 	var result = make([]*Message, 0)
 	for i := 0; i < count; i++ {
-		message, err := reader.ReadMessage(ctx)
+		message, err := reader.FetchMessage(ctx)
 		if err != nil {
+			if ctx.Err() != nil { //timeout, return already pulled messages
+				break
+			}
 			return nil, err
 		}
 		msg := &Message{
@@ -74,7 +84,7 @@ func (k *kafkaClient) PullN(ctx context.Context, source *Resource, count int, na
 			msg.Attributes[keyAttribute] = string(message.Key)
 		}
 		result = append(result, msg)
-		if !nack {
+		if !nack && source.GroupID != "" {
 			if err = reader.CommitMessages(ctx, message); err != nil {
 				return nil, errors.Wrapf(err, "failed to commit message: %v", msg)
 			}
@@ -8,6 +8,7 @@ import (
 	"github.com/viant/endly/testing/validator"
 	"github.com/viant/endly/udf"
 	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
 )
 
 const (
@@ -57,6 +58,24 @@ func (s *service) registerRoutes() {
 			return nil, fmt.Errorf("unsupported request type: %T", request)
 		},
 	})
+	s.Register(&endly.Route{
+		Action: "assert",
+		RequestInfo: &endly.ActionInfo{
+			Description: "pull messages till expected messages arrive and validate them",
+		},
+		RequestProvider: func() interface{} {
+			return &AssertRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &AssertResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*AssertRequest); ok {
+				return s.assert(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
 	s.Register(&endly.Route{
 		Action: "setupResource",
 		RequestInfo: &endly.ActionInfo{
@@ -117,9 +136,12 @@ func (s *service) push(context *endly.Context, request *PushRequest) (interface{
 	defer client.Close()
 
 	dest := expandResource(context, request.Dest)
-	var state = context.State()
+	var state = context.SafeState()
 	for _, message := range request.Messages {
-		expanded := message.Expand(state)
+		var expanded *Message
+		state.Read(func(state data.Map) {
+			expanded = message.Expand(state)
+		})
 		if request.UDF != "" {
 			expanded.Data, err = udf.TransformWithUDF(context, request.UDF, fmt.Sprintf("%v/%v", request.Dest.Type, request.Dest.Name), expanded.Data)
 			if err != nil {
@@ -162,13 +184,49 @@ func (s *service) pull(context *endly.Context, request *PullRequest) (interface{
 	return response, err
 }
 
+func (s *service) assert(context *endly.Context, request *AssertRequest) (interface{}, error) {
+	response := &AssertResponse{Messages: make([]*Message, 0)}
+	var duration, _ = toolbox.NewDuration(request.WaitTimeMs, toolbox.DurationMillisecond)
+	client, err := NewPubSubClient(context, request.Source, duration)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	source := expandResource(context, request.Source)
+	expectedCount := request.expectedCount()
+	for i := 0; i < request.WaitRetryCount && len(response.Messages) < expectedCount; i++ {
+		messages, err := client.PullN(context.Background(), source, expectedCount-len(response.Messages), request.Nack)
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range messages {
+			message = normalizeData(message)
+			if request.UDF != "" {
+				if message.Transformed, err = udf.TransformWithUDF(context, request.UDF, fmt.Sprintf("%v/%v", source.Type, source.Name), message.Data); err != nil {
+					return nil, err
+				}
+			}
+			response.Messages = append(response.Messages, message)
+		}
+	}
+	var actual = make([]interface{}, 0)
+	for _, message := range response.Messages {
+		if message.Transformed != nil {
+			message = &Message{ID: message.ID, Subject: message.Subject, Attributes: message.Attributes, Data: message.Transformed}
+		}
+		actual = append(actual, message)
+	}
+	response.Assert, err = validator.Assert(context, request, request.Expect, actual, "msg.assert", "assert msg messages")
+	return response, err
+}
+
 func (s *service) setupResource(context *endly.Context, resource *ResourceSetup) (*Resource, error) {
 	var duration, _ = toolbox.NewDuration(defaultTimeoutMs, toolbox.DurationMillisecond)
 	client, err := NewPubSubClient(context, &resource.Resource, duration)
 	if err != nil {
 		return nil, err
 	}
-	var state = context.State()
+	var state = context.SafeState()
 	resource.URL = state.ExpandAsText(resource.URL)
 	resource.projectID = state.ExpandAsText(resource.projectID)
 	if resource.Config != nil {
@@ -211,7 +269,7 @@ func (s *service) deleteResource(context *endly.Context, resource *Resource) err
 		return err
 	}
 	defer client.Close()
-	var state = context.State()
+	var state = context.SafeState()
 	resource.URL = state.ExpandAsText(resource.URL)
 	return client.DeleteResource(resource)
 }
@@ -8,12 +8,16 @@ import (
 	"github.com/viant/toolbox/url"
 )
 
+const defaultLoadDurationMs = 10000
+
 //SendRequest represents a send http request.
 type SendRequest struct {
 	Options     map[string]interface{} `description:"http client httpOptions: key value pairs, where key is one of the following: HTTP httpOptions:RequestTimeoutMs,TimeoutMs,KeepAliveTimeMs,TLSHandshakeTimeoutMs,ResponseHeaderTimeoutMs,MaxIdleConns,FollowRedirects"`
 	httpOptions []*toolbox.HttpOptions
 	Requests    []*Request
 	Expect      map[string]interface{} `description:"If specified it will validated response as actual"`
+	Mode        string                 `description:"record: records live trips into cassette, replay: replays recorded trips without calling actual endpoint, live if empty"`
+	Cassette    string                 `description:"cassette directory with recorded trips, required in record and replay mode"`
 }
 
 //Init initializes send request
@@ -62,6 +66,25 @@ func (s *SendRequest) Init() error {
 	return nil
 }
 
+//Validate checks if request is valid
+func (s *SendRequest) Validate() error {
+	for _, request := range s.Requests {
+		if request.Stream != nil && request.Stream.Format != "" && request.Stream.Format != StreamChunked && request.Stream.Format != StreamSSE {
+			return fmt.Errorf("unsupported stream format: %v, supported: %v, %v", request.Stream.Format, StreamChunked, StreamSSE)
+		}
+	}
+	switch s.Mode {
+	case "":
+		return nil
+	case ModeRecord, ModeReplay:
+		if s.Cassette == "" {
+			return fmt.Errorf("cassette was empty for %v mode", s.Mode)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported mode: %v, supported: %v, %v", s.Mode, ModeRecord, ModeReplay)
+}
+
 //NewSendRequestFromURL create new request from URL
 func NewSendRequestFromURL(URL string) (*SendRequest, error) {
 	resource := url.NewResource(URL)
@@ -112,6 +135,39 @@ type LoadRequest struct {
 	Repeat      int    `description:"defines how many times repeat individual request, default 1"`
 	AssertMod   int    `description:"defines modulo for assertion on repeated request (make sure you have enough memory)"`
 	Message     string `description:"reporting message during stress test, the following is available: $load.[QPS|Count|Elapsed|Timeouts|Errors|Error]"`
+	QPS         int    `description:"target requests per second, when specified requests are sent round robin for the duration instead of repeat count"`
+	DurationMs  int    `description:"load generation duration, default 10000"`
+	RampUpMs    int    `description:"time to linearly increase request rate up to target QPS"`
+	SLA         *SLA   `description:"service level assertion evaluated after load test"`
+}
+
+//SLA represents a load test service level assertion, response times are in ms
+type SLA struct {
+	P50          float64
+	P90          float64
+	P95          float64
+	P99          float64
+	MaxErrorRate *float64 `description:"max ratio of errors, timeouts and 5xx responses to all requests, i.e. 0.01"`
+}
+
+//Expect returns SLA expectation for load response
+func (s *SLA) Expect() map[string]interface{} {
+	var result = make(map[string]interface{})
+	var limits = map[string]float64{
+		"P50ResponseTimeInMs": s.P50,
+		"P90ResponseTimeInMs": s.P90,
+		"P95ResponseTimeInMs": s.P95,
+		"P99ResponseTimeInMs": s.P99,
+	}
+	for key, limit := range limits {
+		if limit > 0 {
+			result[key] = fmt.Sprintf("/[0..%v]/", limit)
+		}
+	}
+	if s.MaxErrorRate != nil {
+		result["ErrorRate"] = fmt.Sprintf("/[0..%v]/", *s.MaxErrorRate)
+	}
+	return result
 }
 
 func (r *LoadRequest) Init() error {
@@ -139,6 +195,9 @@ func (r *LoadRequest) Init() error {
 		r.AssertMod = 1024
 	}
 
+	if r.QPS > 0 && r.DurationMs == 0 {
+		r.DurationMs = defaultLoadDurationMs
+	}
 	if r.Message == "" {
 		r.Message = " $load.Elapsed: Count: $load.Count, QPS: $load.QPS, Timeouts: $load.Timeouts, Errors: $load.Errors, Error: $load.Error"
 	}
@@ -149,6 +208,15 @@ func (r *LoadRequest) Validate() error {
 	if len(r.Requests) == 0 {
 		return fmt.Errorf("requests were empty")
 	}
+	if r.Mode != "" {
+		return fmt.Errorf("%v mode is not supported in stress test mode", r.Mode)
+	}
+	if r.QPS < 0 {
+		return fmt.Errorf("invalid QPS: %v", r.QPS)
+	}
+	if r.QPS > 0 && r.RampUpMs >= r.DurationMs {
+		return fmt.Errorf("rampUpMs: %v has to be shorter than durationMs: %v", r.RampUpMs, r.DurationMs)
+	}
 	for _, request := range r.Requests {
 		if request.When != "" {
 			return fmt.Errorf("conditional execution is not supported in stress test mode")
@@ -159,6 +227,9 @@ func (r *LoadRequest) Validate() error {
 		if len(request.Extract) > 0 {
 			return fmt.Errorf("scraping data is not supported in stress test mode")
 		}
+		if request.Stream != nil {
+			return fmt.Errorf("streaming response is not supported in stress test mode")
+		}
 	}
 
 	return nil
@@ -178,4 +249,21 @@ type LoadResponse struct {
 	MinResponseTimeInMs float64
 	AvgResponseTimeInMs float64
 	MaxResponseTimeInMs float64
+	P50ResponseTimeInMs float64
+	P90ResponseTimeInMs float64
+	P95ResponseTimeInMs float64
+	P99ResponseTimeInMs float64
+	ErrorRate           float64 `description:"ratio of errors, timeouts and 5xx responses to all requests"`
+	SLAAssert           *validator.AssertResponse
+}
+
+//SLAActual returns response metrics validated by SLA, values are text as assertly applies range check to text only
+func (r *LoadResponse) SLAActual() map[string]interface{} {
+	return map[string]interface{}{
+		"P50ResponseTimeInMs": toolbox.AsString(r.P50ResponseTimeInMs),
+		"P90ResponseTimeInMs": toolbox.AsString(r.P90ResponseTimeInMs),
+		"P95ResponseTimeInMs": toolbox.AsString(r.P95ResponseTimeInMs),
+		"P99ResponseTimeInMs": toolbox.AsString(r.P99ResponseTimeInMs),
+		"ErrorRate":           toolbox.AsString(r.ErrorRate),
+	}
 }
@@ -13,7 +13,7 @@ import (
 )
 
 func initializeContext(c *endly.Context) {
-	var state = c.State()
+	var state = c.SafeState()
 	if !state.Has("cookies") {
 		state.Put("cookies", data.NewMap())
 	}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+//expectedLoadCount returns number of requests to be sent after elapsed time for linear ramp up to target QPS
+func expectedLoadCount(QPS int, rampUp, elapsed time.Duration) int {
+	seconds := elapsed.Seconds()
+	if elapsed < rampUp {
+		return int(float64(QPS) * seconds * seconds / (2 * rampUp.Seconds()))
+	}
+	return int(float64(QPS) * (seconds - rampUp.Seconds()/2))
+}
+
+//percentile returns nearest rank percentile of sorted durations in ms
+func percentile(sorted []time.Duration, rank float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(math.Ceil(rank/100*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return float64(sorted[index]) / float64(time.Millisecond)
+}
+
+//setLatencyPercentiles sets response time percentiles and error rate
+func setLatencyPercentiles(trips []*stressTestTrip, response *LoadResponse) {
+	var elapsed = make([]time.Duration, len(trips))
+	var failed = 0
+	for i, trip := range trips {
+		elapsed[i] = trip.elapsed
+		if trip.err != nil || trip.timeout || trip.statusCode >= 500 {
+			failed++
+		}
+	}
+	sort.Slice(elapsed, func(i, j int) bool {
+		return elapsed[i] < elapsed[j]
+	})
+	response.P50ResponseTimeInMs = percentile(elapsed, 50)
+	response.P90ResponseTimeInMs = percentile(elapsed, 90)
+	response.P95ResponseTimeInMs = percentile(elapsed, 95)
+	response.P99ResponseTimeInMs = percentile(elapsed, 99)
+	response.ErrorRate = float64(failed) / float64(len(trips))
+}
+
+//buildLoadTemplates builds one trip template per request, it has to run before load metrics start reading context state
+func buildLoadTemplates(request *LoadRequest, context *endly.Context) ([]*stressTestTrip, error) {
+	var expectedResponses []interface{}
+	if len(request.Expect) > 0 {
+		responses, ok := request.Expect["Responses"]
+		if !ok {
+			responses, ok = request.Expect["responses"]
+		}
+		if ok {
+			expectedResponses = toolbox.AsSlice(responses)
+		}
+	}
+	var sessionCookies = []*http.Cookie{}
+	var templates = make([]*stressTestTrip, 0)
+	for index, req := range request.Requests {
+		context.SafeState().Read(func(state data.Map) {
+			req.Expand(state)
+		})
+		template := &stressTestTrip{index: index, expected: index < len(expectedResponses)}
+		var err error
+		if template.request, template.expectBinary, err = req.Build(context, sessionCookies); err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+//newLoadTrip creates a trip from template with a fresh request body
+func newLoadTrip(template *stressTestTrip, expected bool, waitGroup *sync.WaitGroup) (*stressTestTrip, error) {
+	trip := &stressTestTrip{
+		index:        template.index,
+		expectBinary: template.expectBinary,
+		expected:     expected,
+		waitGroup:    waitGroup,
+		request:      template.request.Clone(template.request.Context()),
+	}
+	if template.request.GetBody != nil {
+		body, err := template.request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		trip.request.Body = body
+	}
+	return trip, nil
+}
+
+//generateLoad sends requests round robin at target QPS with optional ramp up until duration elapses
+func (s *service) generateLoad(request *LoadRequest, templates []*stressTestTrip, sendChannel chan *stressTestTrip, waitGroup *sync.WaitGroup) ([]*stressTestTrip, error) {
+	var trips = make([]*stressTestTrip, 0)
+	duration := time.Duration(request.DurationMs) * time.Millisecond
+	rampUp := time.Duration(request.RampUpMs) * time.Millisecond
+	startTime := time.Now()
+	for {
+		elapsed := time.Since(startTime)
+		if elapsed >= duration {
+			break
+		}
+		for expected := expectedLoadCount(request.QPS, rampUp, elapsed); len(trips) < expected; {
+			sent := len(trips)
+			template := templates[sent%len(templates)]
+			repeat := sent / len(templates)
+			trip, err := newLoadTrip(template, template.expected && (repeat == 0 || repeat%request.AssertMod == 0), waitGroup)
+			if err != nil {
+				return nil, err
+			}
+			trips = append(trips, trip)
+			waitGroup.Add(1)
+			sendChannel <- trip
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if len(trips) == 0 {
+		return nil, fmt.Errorf("no request was sent within %v ms at %v QPS", request.DurationMs, request.QPS)
+	}
+	return trips, nil
+}
@@ -0,0 +1,73 @@
+package http_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	runner "github.com/viant/endly/testing/runner/http"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHttpRunnerService_LoadQPS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		_, _ = writer.Write([]byte("pong"))
+	}))
+	defer server.Close()
+
+	var maxErrorRate = 0.0
+	newRequest := func(SLA *runner.SLA) *runner.LoadRequest {
+		return &runner.LoadRequest{
+			SendRequest: &runner.SendRequest{
+				Requests: []*runner.Request{
+					{Method: "GET", URL: server.URL + "/ping"},
+				},
+				Expect: map[string]interface{}{
+					"Responses": []interface{}{
+						map[string]interface{}{"Code": 200, "Body": "pong"},
+					},
+				},
+			},
+			ThreadCount: 4,
+			QPS:         200,
+			DurationMs:  500,
+			RampUpMs:    200,
+			SLA:         SLA,
+		}
+	}
+
+	response := &runner.LoadResponse{}
+	err := endly.Run(nil, newRequest(&runner.SLA{P99: 1000, MaxErrorRate: &maxErrorRate}), response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	//ramp up 200ms: 20 requests, then 300ms at 200 QPS: 60 requests
+	assert.True(t, response.RequestCount >= 60 && response.RequestCount <= 90, response.RequestCount)
+	assert.True(t, response.P50ResponseTimeInMs > 0)
+	assert.True(t, response.P50ResponseTimeInMs <= response.P99ResponseTimeInMs)
+	assert.EqualValues(t, 0, response.ErrorRate)
+	assert.False(t, response.Assert.HasFailure())
+	if assert.NotNil(t, response.SLAAssert) {
+		assert.False(t, response.SLAAssert.HasFailure(), response.SLAAssert.Validation.Report())
+	}
+
+	response = &runner.LoadResponse{}
+	err = endly.Run(nil, newRequest(&runner.SLA{P50: 0.001}), response)
+	if assert.Nil(t, err) && assert.NotNil(t, response.SLAAssert) {
+		assert.True(t, response.SLAAssert.HasFailure())
+	}
+}
+
+func TestLoadRequest_Validate(t *testing.T) {
+	request := &runner.LoadRequest{
+		SendRequest: &runner.SendRequest{Requests: []*runner.Request{{Method: "GET", URL: "http://127.0.0.1/"}}},
+		QPS:         10,
+		RampUpMs:    20000,
+	}
+	assert.Nil(t, request.Init())
+	assert.NotNil(t, request.Validate())
+	request.RampUpMs = 1000
+	assert.Nil(t, request.Validate())
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"github.com/viant/endly"
 	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/tracing"
 	"github.com/viant/endly/udf"
 	"github.com/viant/endly/util"
 	"github.com/viant/toolbox"
@@ -28,6 +29,7 @@ type Request struct {
 	ResponseUdf string                 `description:"user defined function in context.state key, i,e, protobuf to json"`
 	DataSource  string                 `description:"variable input: response or response.body by default"`
 	Expect      map[string]interface{} `description:"desired http response"`
+	Stream      *Stream                `description:"streaming response option, collected events are available as response Events"`
 }
 
 //Clone substitute request data with matching context map state.
@@ -47,6 +49,7 @@ func (r *Request) Clone(context *endly.Context) *Request {
 		RequestUdf:  r.RequestUdf,
 		ResponseUdf: r.ResponseUdf,
 		DataSource:  r.DataSource,
+		Stream:      r.Stream,
 	}
 }
 
@@ -89,12 +92,13 @@ func (r *Request) Build(context *endly.Context, sessionCookies Cookies) (*http.R
 		reader = bytes.NewReader(body)
 	}
 
-	httpRequest, err := http.NewRequest(strings.ToUpper(request.Method), request.URL, reader)
+	httpRequest, err := http.NewRequestWithContext(context.Background(), strings.ToUpper(request.Method), request.URL, reader)
 	if err != nil {
 		return nil, expectBinary, err
 	}
 
 	copyHeaders(request.Header, httpRequest.Header)
+	tracing.Inject(context, httpRequest.Header)
 	//Set cookies from active session
 	SetCookies(sessionCookies, request.Header)
 	//Set cookies from user http request
@@ -6,6 +6,7 @@ import (
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
 	"net/http"
+	"strings"
 )
 
 //Response represents Http response
@@ -17,6 +18,7 @@ type Response struct {
 	Body        string
 	JSONBody    interface{} `description:"structure data if Body was JSON"`
 	TimeTakenMs int
+	Events      []*StreamEvent `json:",omitempty" description:"collected stream events if request used stream option"`
 	Error       string
 }
 
@@ -53,6 +55,22 @@ func (r *Response) Merge(httpResponse *http.Response, expectBinary bool) {
 	r.Cookies = responseCookies.IndexByName()
 }
 
+//MergeStream merge response from HTTP streaming response, body is set to collected events data separated by new line
+func (r *Response) MergeStream(httpResponse *http.Response, stream *Stream) {
+	r.Code = httpResponse.StatusCode
+	r.Header = make(map[string][]string)
+	copyHeaders(httpResponse.Header, r.Header)
+	r.Events = make([]*StreamEvent, 0)
+	readStream(httpResponse, r, stream)
+	var data = make([]string, 0, len(r.Events))
+	for _, event := range r.Events {
+		data = append(data, event.Data)
+	}
+	r.Body = strings.Join(data, "\n")
+	var responseCookies Cookies = httpResponse.Cookies()
+	r.Cookies = responseCookies.IndexByName()
+}
+
 //NewResponse creates a new response
 func NewResponse() *Response {
 	var response = &Response{}
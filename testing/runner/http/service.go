@@ -10,6 +10,7 @@ import (
 	"github.com/viant/endly/testing/validator"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
+	"github.com/viant/toolbox/url"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -32,6 +33,12 @@ func (s *service) send(context *endly.Context, sendGroupRequest *SendRequest) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to send req: %v", err)
 	}
+	if sendGroupRequest.Mode != "" {
+		cassette := url.NewResource(context.Expand(sendGroupRequest.Cassette)).ParsedURL.Path
+		if client.Transport, err = newVCRTransport(sendGroupRequest.Mode, cassette, client.Transport); err != nil {
+			return nil, err
+		}
+	}
 	initializeContext(context)
 	defer s.resetContext(context, sendGroupRequest)
 
@@ -60,12 +67,15 @@ func (s *service) send(context *endly.Context, sendGroupRequest *SendRequest) (*
 
 func (s *service) sendRequest(context *endly.Context, client *http.Client, request *Request, sessionCookies *Cookies, sendGroupRequest *SendRequest, sendGroupResponse *SendResponse) error {
 	var err error
-	var state = context.State()
-	cookies := state.GetMap("cookies")
-	trips := Trips(state.GetMap(TripsKey))
-	request.Expand(state)
+	var cookies data.Map
+	var trips Trips
+	context.SafeState().Read(func(state data.Map) {
+		cookies = state.GetMap("cookies")
+		trips = Trips(state.GetMap(TripsKey))
+		request.Expand(state)
+	})
 
-	canRun, err := criteria.Evaluate(context, context.State(), request.When, fmt.Sprintf("%v.When", "HttpRequest"), true)
+	canRun, err := criteria.Evaluate(context, context.SafeState().Clone(), request.When, fmt.Sprintf("%v.When", "HttpRequest"), true)
 	if err != nil || !canRun {
 		return err
 	}
@@ -88,7 +98,11 @@ func (s *service) sendRequest(context *endly.Context, client *http.Client, reque
 		if response == nil { //if request is repeated only the allocated one, and keep overriding it to see the last snapshot
 			response = sendGroupResponse.NewResponse()
 		}
-		response.Merge(httpResponse, expectBinary)
+		if request.Stream != nil {
+			response.MergeStream(httpResponse, request.Stream)
+		} else {
+			response.Merge(httpResponse, expectBinary)
+		}
 		response.UpdateCookies(cookies)
 		sessionCookies.AddCookies(httpResponse.Cookies()...)
 		err = response.TransformBodyIfNeeded(context, request)
@@ -136,13 +150,15 @@ func (s *service) applyDefaultTimeoutIfNeeded(options []*toolbox.HttpOptions) []
 
 //resetContext resets context for variables with Reset flag set, and removes PreviousTripStateKey
 func (s *service) resetContext(context *endly.Context, request *SendRequest) {
-	state := context.State()
-	state.Delete(TripsKey)
-	for _, request := range request.Requests {
-		if request.Repeater != nil && len(request.Extract) > 0 {
-			request.Extract.Reset(state)
+	_ = context.SafeState().Update(func(state data.Map) error {
+		state.Delete(TripsKey)
+		for _, request := range request.Requests {
+			if request.Repeater != nil && len(request.Extract) > 0 {
+				request.Extract.Reset(state)
+			}
 		}
-	}
+		return nil
+	})
 }
 
 func (s *service) handleRequest(client *http.Client, metric *runtimeMetric, trip *stressTestTrip) {
@@ -180,6 +196,7 @@ func (s *service) handleRequest(client *http.Client, metric *runtimeMetric, trip
 	if trip.err != nil || trip.timeout || response == nil {
 		return
 	}
+	trip.statusCode = response.StatusCode
 	var content []byte
 	if response.ContentLength > 0 {
 		content, err = ioutil.ReadAll(response.Body)
@@ -227,14 +244,27 @@ func (s *service) stressTest(context *endly.Context, request *LoadRequest) (*Loa
 	var sendChannel = make(chan *stressTestTrip, capacity)
 	var done uint32 = 0
 	metrics := &runtimeMetric{}
-
+	var templates []*stressTestTrip
+	if request.QPS > 0 {
+		var err error
+		if templates, err = buildLoadTemplates(request, context); err != nil {
+			return nil, err
+		}
+	}
 	go s.emitMetrics(context, metrics, &done, request.Message)
 	if _, err := s.initClients(request, sendChannel, metrics, &done); err != nil {
 		return nil, err
 	}
-	partialTrips := newPartialStressTrips(capacity, sendChannel, waitGroup)
-	trips, err := buildStressTestTrip(request, context, partialTrips)
+	var trips []*stressTestTrip
+	var err error
+	if request.QPS > 0 {
+		trips, err = s.generateLoad(request, templates, sendChannel, waitGroup)
+	} else {
+		partialTrips := newPartialStressTrips(capacity, sendChannel, waitGroup)
+		trips, err = buildStressTestTrip(request, context, partialTrips)
+	}
 	if err != nil {
+		atomic.StoreUint32(&done, 1)
 		return nil, err
 	}
 	waitGroup.Wait()
@@ -280,7 +310,12 @@ func (s *service) stressTest(context *endly.Context, request *LoadRequest) (*Loa
 			expected = append(expected, expect)
 
 		}
-		response.Assert, err = validator.Assert(context, request, expected, actual, "HTTP.Responses", "assert http responses")
+		if response.Assert, err = validator.Assert(context, request, expected, actual, "HTTP.Responses", "assert http responses"); err != nil {
+			return response, err
+		}
+	}
+	if request.SLA != nil {
+		response.SLAAssert, err = validator.Assert(context, request, request.SLA.Expect(), response.SLAActual(), "HTTP.SLA", "assert load SLA")
 	}
 	return response, err
 }
@@ -328,6 +363,7 @@ func collectTripResponses(trips []*stressTestTrip, response *LoadResponse, reque
 	response.TestDurationSec = float64(testDuration) / float64(time.Second)
 	response.RequestCount = len(trips)
 	response.QPS = float64(len(trips)) / response.TestDurationSec
+	setLatencyPercentiles(trips, response)
 	return nil
 }
 
@@ -343,6 +379,7 @@ type stressTestTrip struct {
 	requestTime  time.Time
 	responseTime time.Time
 	elapsed      time.Duration
+	statusCode   int
 }
 
 func buildStressTestTrip(request *LoadRequest, context *endly.Context, partials *partialStressTrips) ([]*stressTestTrip, error) {
@@ -362,8 +399,9 @@ func buildStressTestTrip(request *LoadRequest, context *endly.Context, partials
 	}
 
 	for index, req := range request.Requests {
-		var state = context.State()
-		req.Expand(state)
+		context.SafeState().Read(func(state data.Map) {
+			req.Expand(state)
+		})
 		for i := 0; i < req.Repeat; i++ {
 			trip := &stressTestTrip{
 				waitGroup: partials.WaitGroup,
@@ -505,7 +543,7 @@ func (s *service) emitMetrics(context *endly.Context, metric *runtimeMetric, don
 	if message == "" {
 		return
 	}
-	state := context.State()
+	state := context.SafeState()
 	private := state.Clone()
 	for atomic.LoadUint32(done) == 0 {
 		count := atomic.LoadUint32(&metric.count)
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"bufio"
+	"github.com/viant/toolbox"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	//StreamChunked represents chunked response stream, each non empty line is an event
+	StreamChunked = "chunked"
+	//StreamSSE represents server sent events (text/event-stream) response stream
+	StreamSSE = "sse"
+
+	defaultStreamTimeoutMs = 5000
+	maxStreamLineSize      = 1024 * 1024
+)
+
+//Stream represents streaming response collection option
+type Stream struct {
+	Format    string `description:"chunked or sse, if empty it derives from response content type"`
+	TimeoutMs int    `description:"time budget to collect events, default 5000"`
+	MaxEvents int    `description:"max number of events to collect, stops reading stream when reached"`
+}
+
+//StreamEvent represents collected stream event
+type StreamEvent struct {
+	ID       string `json:",omitempty"`
+	Type     string `json:",omitempty"`
+	Data     string
+	JSONData interface{} `json:",omitempty" description:"structure data if Data was JSON"`
+	TimeMs   int         `description:"elapsed time since response headers were received"`
+}
+
+//format returns stream format
+func (s *Stream) format(header http.Header) string {
+	if s.Format != "" {
+		return s.Format
+	}
+	if strings.Contains(header.Get("Content-Type"), "text/event-stream") {
+		return StreamSSE
+	}
+	return StreamChunked
+}
+
+func (s *Stream) timeout() time.Duration {
+	if s.TimeoutMs <= 0 {
+		return defaultStreamTimeoutMs * time.Millisecond
+	}
+	return time.Duration(s.TimeoutMs) * time.Millisecond
+}
+
+func newStreamEvent(started time.Time, ID, eventType, data string) *StreamEvent {
+	var result = &StreamEvent{ID: ID, Type: eventType, Data: data, TimeMs: int(time.Since(started) / time.Millisecond)}
+	if toolbox.IsStructuredJSON(data) {
+		result.JSONData, _ = toolbox.JSONToInterface(data)
+	}
+	return result
+}
+
+//scanEvents scans reader for events till EOF or stop is closed
+func scanEvents(format string, reader io.Reader, started time.Time, events chan<- *StreamEvent, stop <-chan bool) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+	var emit = func(event *StreamEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-stop:
+			return false
+		}
+	}
+	var ID, eventType string
+	var data = make([]string, 0)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if format != StreamSSE {
+			if strings.TrimSpace(line) != "" && !emit(newStreamEvent(started, "", "", line)) {
+				return nil
+			}
+			continue
+		}
+		if line == "" {
+			if len(data) > 0 && !emit(newStreamEvent(started, ID, eventType, strings.Join(data, "\n"))) {
+				return nil
+			}
+			eventType, data = "", data[:0]
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value := line, ""
+		if index := strings.Index(line, ":"); index != -1 {
+			field, value = line[:index], strings.TrimPrefix(line[index+1:], " ")
+		}
+		switch field {
+		case "id":
+			ID = value
+		case "event":
+			eventType = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	return scanner.Err()
+}
+
+//readStream collects response stream events till stream ends, time budget elapses or max events are collected
+func readStream(httpResponse *http.Response, response *Response, stream *Stream) {
+	defer httpResponse.Body.Close()
+	var started = time.Now()
+	var events = make(chan *StreamEvent)
+	var stop = make(chan bool)
+	var done = make(chan error, 1)
+	defer close(stop)
+	go func() {
+		done <- scanEvents(stream.format(httpResponse.Header), httpResponse.Body, started, events, stop)
+	}()
+	timer := time.NewTimer(stream.timeout())
+	defer timer.Stop()
+	for {
+		select {
+		case event := <-events:
+			response.Events = append(response.Events, event)
+			if stream.MaxEvents > 0 && len(response.Events) >= stream.MaxEvents {
+				return
+			}
+		case err := <-done:
+			if err != nil {
+				response.Error = err.Error()
+			}
+			return
+		case <-timer.C:
+			return
+		}
+	}
+}
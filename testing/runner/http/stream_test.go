@@ -0,0 +1,81 @@
+package http_test
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	runner "github.com/viant/endly/testing/runner/http"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHttpRunnerService_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		flusher := writer.(http.Flusher)
+		switch request.URL.Path {
+		case "/sse":
+			writer.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(writer, ": keep alive\n\nid: 1\nevent: created\ndata: {\"id\":1}\n\nid: 2\ndata: line1\ndata: line2\n\n")
+			flusher.Flush()
+			select { //stream stays open, client time budget ends it
+			case <-request.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		case "/chunked":
+			for i := 1; i <= 10; i++ {
+				_, _ = fmt.Fprintf(writer, "{\"seq\":%v}\n", i)
+				flusher.Flush()
+			}
+		}
+	}))
+	defer server.Close()
+
+	response := &runner.SendResponse{}
+	err := endly.Run(nil, &runner.SendRequest{
+		Requests: []*runner.Request{
+			{
+				Method: "GET",
+				URL:    server.URL + "/sse",
+				Stream: &runner.Stream{TimeoutMs: 300},
+				Expect: map[string]interface{}{
+					"Code": 200,
+					"Events": []interface{}{
+						map[string]interface{}{"ID": "1", "Type": "created", "JSONData": map[string]interface{}{"id": 1}},
+						map[string]interface{}{"ID": "2", "Data": "line1\nline2"},
+					},
+				},
+			},
+			{
+				Method: "GET",
+				URL:    server.URL + "/chunked",
+				Stream: &runner.Stream{Format: runner.StreamChunked, MaxEvents: 3},
+				Expect: map[string]interface{}{
+					"Events": []interface{}{
+						map[string]interface{}{"Data": `{"seq":1}`},
+						map[string]interface{}{"Data": `{"seq":2}`},
+						map[string]interface{}{"Data": `{"seq":3}`},
+					},
+				},
+			},
+		},
+	}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	if assert.NotNil(t, response.Assert) {
+		assert.Equal(t, 0, response.Assert.FailedCount, response.Assert.Report())
+		assert.True(t, response.Assert.PassedCount > 0)
+	}
+	if assert.Equal(t, 2, len(response.Responses)) {
+		assert.Equal(t, 2, len(response.Responses[0].Events))
+		assert.Equal(t, 3, len(response.Responses[1].Events))
+		assert.EqualValues(t, "{\"seq\":1}\n{\"seq\":2}\n{\"seq\":3}", response.Responses[1].Body)
+	}
+
+	err = endly.Run(nil, &runner.SendRequest{
+		Requests: []*runner.Request{{Method: "GET", URL: server.URL + "/sse", Stream: &runner.Stream{Format: "ws"}}},
+	}, &runner.SendResponse{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly/util"
+	"github.com/viant/toolbox/bridge"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	//ModeRecord represents a mode recording live HTTP trips into cassette directory
+	ModeRecord = "record"
+	//ModeReplay represents a mode replaying HTTP trips from cassette directory without calling actual endpoint
+	ModeReplay = "replay"
+
+	cassetteRequestTemplate  = "bridge.HttpRequest-%v.json"
+	cassetteResponseTemplate = "bridge.HttpResponse-%v.json"
+)
+
+//vcrTransport represents recording or replaying round tripper, cassette uses HTTP bridge recording format
+type vcrTransport struct {
+	mode      string
+	directory string
+	transport http.RoundTripper
+	trips     []*bridge.RecordedHttpTrip
+	replayed  []bool
+	counter   int
+	mux       sync.Mutex
+}
+
+//RoundTrip records or replays HTTP trip
+func (t *vcrTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+		_ = request.Body.Close()
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	recorded := &bridge.HttpRequest{Method: request.Method, URL: request.URL.String(), Header: request.Header, Body: util.AsPayload(body)}
+	if t.mode == ModeReplay {
+		return t.replay(request, recorded)
+	}
+	return t.record(request, recorded)
+}
+
+func (t *vcrTransport) record(request *http.Request, recorded *bridge.HttpRequest) (*http.Response, error) {
+	response, err := t.transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if err = writeCassetteFile(path.Join(t.directory, fmt.Sprintf(cassetteRequestTemplate, t.counter)), recorded); err == nil {
+		err = writeCassetteFile(path.Join(t.directory, fmt.Sprintf(cassetteResponseTemplate, t.counter)), &bridge.HttpResponse{Code: response.StatusCode, Header: response.Header, Body: util.AsPayload(body)})
+	}
+	t.counter++
+	return response, err
+}
+
+//replay returns the first not yet replayed response recorded for matching method, URL and body
+func (t *vcrTransport) replay(request *http.Request, recorded *bridge.HttpRequest) (*http.Response, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	for i, trip := range t.trips {
+		if t.replayed[i] || trip.Request.Method != recorded.Method || trip.Request.URL != recorded.URL {
+			continue
+		}
+		if body, _ := util.FromPayload(trip.Request.Body); util.AsPayload(body) != recorded.Body {
+			continue
+		}
+		t.replayed[i] = true
+		body, err := util.FromPayload(trip.Response.Body)
+		if err != nil {
+			return nil, err
+		}
+		header := trip.Response.Header
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", trip.Response.Code, http.StatusText(trip.Response.Code)),
+			StatusCode:    trip.Response.Code,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       request,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded trip for %v %v in cassette: %v", recorded.Method, recorded.URL, t.directory)
+}
+
+func writeCassetteFile(filename string, source interface{}) error {
+	data, err := json.MarshalIndent(source, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+//newVCRTransport creates a recording or replaying transport, recording removes previously recorded trips
+func newVCRTransport(mode, directory string, transport http.RoundTripper) (*vcrTransport, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	var result = &vcrTransport{mode: mode, directory: directory, transport: transport}
+	if mode == ModeReplay {
+		trips, err := bridge.ReadRecordedHttpTripsWithTemplate(directory, cassetteRequestTemplate, cassetteResponseTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cassette: %v, %v", directory, err)
+		}
+		if len(trips) == 0 {
+			return nil, fmt.Errorf("cassette was empty: %v", directory)
+		}
+		result.trips = trips
+		result.replayed = make([]bool, len(trips))
+		return result, nil
+	}
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, err
+	}
+	for _, template := range []string{cassetteRequestTemplate, cassetteResponseTemplate} {
+		previous, _ := filepath.Glob(path.Join(directory, fmt.Sprintf(template, "*")))
+		for _, filename := range previous {
+			if err := os.Remove(filename); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,72 @@
+package http_test
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	runner "github.com/viant/endly/testing/runner/http"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestHttpRunnerService_RecordReplay(t *testing.T) {
+	var count = 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		count++
+		body, _ := ioutil.ReadAll(request.Body)
+		writer.Header().Set("Content-Type", "text/plain")
+		writer.WriteHeader(http.StatusCreated)
+		_, _ = writer.Write([]byte(fmt.Sprintf("%v %v %v #%v", request.Method, request.URL.Path, string(body), count)))
+	}))
+	cassette, err := ioutil.TempDir("", "cassette")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(cassette)
+	var newRequest = func(mode string) *runner.SendRequest {
+		return &runner.SendRequest{
+			Mode:     mode,
+			Cassette: cassette,
+			Requests: []*runner.Request{
+				{Method: "POST", URL: server.URL + "/users", Body: "alice"},
+				{Method: "POST", URL: server.URL + "/users", Body: "alice"},
+				{Method: "GET", URL: server.URL + "/users/1"},
+			},
+		}
+	}
+
+	recorded := &runner.SendResponse{}
+	err = endly.Run(nil, newRequest(runner.ModeRecord), recorded)
+	server.Close()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 3, count)
+
+	replayed := &runner.SendResponse{}
+	err = endly.Run(nil, newRequest(runner.ModeReplay), replayed)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 3, count)
+	if assert.Equal(t, 3, len(replayed.Responses)) {
+		assert.EqualValues(t, "POST /users alice #1", replayed.Responses[0].Body)
+		assert.EqualValues(t, "POST /users alice #2", replayed.Responses[1].Body)
+		assert.EqualValues(t, "GET /users/1  #3", replayed.Responses[2].Body)
+		for i, response := range replayed.Responses {
+			assert.Equal(t, http.StatusCreated, response.Code, i)
+			assert.EqualValues(t, recorded.Responses[i].Body, response.Body, i)
+		}
+	}
+
+	request := newRequest(runner.ModeReplay)
+	request.Requests[0].Body = "bob"
+	err = endly.Run(nil, request, &runner.SendResponse{})
+	assert.NotNil(t, err)
+
+	err = endly.Run(nil, &runner.SendRequest{Mode: runner.ModeReplay, Requests: request.Requests}, &runner.SendResponse{})
+	assert.NotNil(t, err)
+}
@@ -21,7 +21,7 @@ func (s *restService) sendRequest(context *endly.Context, request *Request) (*Re
 	repeater := request.Repeater.Init()
 
 	var extracted = make(map[string]interface{})
-	var state = context.State()
+	var state = context.SafeState()
 	var req = request.Request
 	if req != nil {
 		req = state.Expand(req)
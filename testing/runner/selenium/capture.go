@@ -0,0 +1,84 @@
+package selenium
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/criteria"
+	estorage "github.com/viant/endly/system/storage"
+	"github.com/viant/endly/testing/validator"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"strings"
+	"time"
+)
+
+const defaultCaptureURL = "/tmp/endly/selenium"
+
+var captureNameReplacer = strings.NewReplacer(":", "_", "/", "_", " ", "_")
+
+//upload uploads data to supplied URL
+func upload(context *endly.Context, URL string, data []byte) error {
+	resource := url.NewResource(URL)
+	fs, err := estorage.StorageService(context, resource)
+	if err != nil {
+		return err
+	}
+	resource, storageOptions, err := estorage.GetResourceWithOptions(context, resource)
+	if err != nil {
+		return err
+	}
+	return fs.Upload(context.Background(), resource.URL, 0644, bytes.NewReader(data), storageOptions...)
+}
+
+//assert validates actual data, it captures session screenshot and page source when validation failed
+func (s *service) assert(context *endly.Context, session *Session, request *RunRequest, actual interface{}) (*validator.AssertResponse, *FailureCapture, error) {
+	assertRequest, err := validator.NewAssertRequestFromContext(context, request, request.Expect, actual, "selenium", "assert selenium response")
+	if err != nil {
+		return nil, nil, err
+	}
+	validation, err := criteria.Assert(context, assertRequest.Name, assertRequest.Expected, actual)
+	if err != nil {
+		return nil, nil, err
+	}
+	validation.TagID = assertRequest.TagID
+	validation.Description = assertRequest.Description
+	var capture *FailureCapture
+	if validation.HasFailure() && !request.SkipCapture {
+		capture = s.capture(context, session, request.CaptureURL)
+		validation.Description += fmt.Sprintf(", screenshot: %v, page source: %v", capture.Screenshot, capture.PageSource)
+	}
+	return &validator.AssertResponse{Validation: validation}, capture, nil
+}
+
+//capture uploads session screenshot and page source to capture URL
+func (s *service) capture(context *endly.Context, session *Session, captureURL string) *FailureCapture {
+	if captureURL == "" {
+		captureURL = defaultCaptureURL
+	}
+	captureURL = url.NewResource(context.Expand(captureURL)).URL
+	name := fmt.Sprintf("%v_%v", captureNameReplacer.Replace(session.ID), time.Now().Format("20060102_150405.000"))
+	var result = &FailureCapture{
+		Screenshot: toolbox.URLPathJoin(captureURL, name+".png"),
+		PageSource: toolbox.URLPathJoin(captureURL, name+".html"),
+	}
+	var errors = make([]string, 0)
+	screenshot, err := session.driver.Screenshot()
+	if err == nil {
+		err = upload(context, result.Screenshot, screenshot)
+	}
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("failed to capture screenshot: %v", err))
+		result.Screenshot = ""
+	}
+	pageSource, err := session.driver.PageSource()
+	if err == nil {
+		err = upload(context, result.PageSource, []byte(pageSource))
+	}
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("failed to capture page source: %v", err))
+		result.PageSource = ""
+	}
+	result.Error = strings.Join(errors, ", ")
+	return result
+}
@@ -3,6 +3,7 @@ package selenium
 import (
 	"errors"
 	"fmt"
+	"github.com/viant/assertly"
 	"github.com/viant/endly/model"
 	"github.com/viant/endly/testing/validator"
 	"github.com/viant/endly/util"
@@ -132,6 +133,8 @@ type RunRequest struct {
 	ActionDelaysInMs int           `description:"slows down action with specified delay"`
 	Commands         []interface{} `description:"list of selenium command: {web element selector}.WebElementMethod(params),  or WebDriverMethod(params), or wait map "`
 	Expect           interface{}   `description:"If specified it will validated response as actual"`
+	CaptureURL       string        `description:"failed expect screenshot and page source destination, default /tmp/endly/selenium"`
+	SkipCapture      bool          `description:"disables screenshot and page source capture on failed expect"`
 }
 
 func (r *RunRequest) asWaitAction(parser *parser, candidate interface{}) (*Action, error) {
@@ -223,6 +226,22 @@ type RunResponse struct {
 	Data         map[string]interface{}
 	LookupErrors []string
 	Assert       *validator.AssertResponse
+	Capture      *FailureCapture `json:",omitempty"`
+}
+
+//Assertion returns response validation
+func (r *RunResponse) Assertion() []*assertly.Validation {
+	if r.Assert == nil || r.Assert.Validation == nil {
+		return nil
+	}
+	return []*assertly.Validation{r.Assert.Validation}
+}
+
+//FailureCapture represents page state captured when expect validation failed
+type FailureCapture struct {
+	Screenshot string `description:"PNG screenshot URL"`
+	PageSource string `description:"HTML page source URL"`
+	Error      string `json:",omitempty"`
 }
 
 //MethodCall represents selenium call.
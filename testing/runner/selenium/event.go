@@ -20,6 +20,17 @@ func (r *RunResponse) Messages() []*msg.Message {
 	}
 	result = append(result,
 		msg.NewMessage(msg.NewStyled("Response", msg.MessageStyleGeneric), msg.NewStyled("selenium", msg.MessageStyleGeneric), dataMessages...))
+	if r.Capture != nil {
+		var captureMessages = []*msg.Styled{
+			msg.NewStyled(fmt.Sprintf("screenshot = %v", r.Capture.Screenshot), msg.MessageStyleOutput),
+			msg.NewStyled(fmt.Sprintf("page source = %v", r.Capture.PageSource), msg.MessageStyleOutput),
+		}
+		if r.Capture.Error != "" {
+			captureMessages = append(captureMessages, msg.NewStyled(r.Capture.Error, msg.MessageStyleError))
+		}
+		result = append(result,
+			msg.NewMessage(msg.NewStyled("Failure", msg.MessageStyleError), msg.NewStyled("capture", msg.MessageStyleError), captureMessages...))
+	}
 	if len(r.LookupErrors) == 0 {
 		return result
 	}
@@ -9,7 +9,6 @@ import (
 	"github.com/viant/endly/deployment/sdk"
 	"github.com/viant/endly/system/exec"
 	"github.com/viant/endly/system/process"
-	"github.com/viant/endly/testing/validator"
 	"github.com/viant/endly/util"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
@@ -66,9 +65,7 @@ func (s *service) run(context *endly.Context, request *RunRequest) (*RunResponse
 		Data:         make(map[string]interface{}),
 		LookupErrors: make([]string, 0),
 	}
-	sessions := Sessions(context)
-	_, hasSession := sessions[request.SessionID]
-
+	_, hasSession := getSessions(context).get(request.SessionID)
 	if !hasSession {
 		openResponse, err := s.openSession(context, &OpenSessionRequest{
 			RemoteSelenium: request.RemoteSelenium,
@@ -84,7 +81,13 @@ func (s *service) run(context *endly.Context, request *RunRequest) (*RunResponse
 	if len(request.Actions) == 0 {
 		return response, nil
 	}
-	var state = context.State()
+	seleniumSession, err := s.session(context, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	seleniumSession.mutex.Lock()
+	defer seleniumSession.mutex.Unlock()
+	var state = context.SafeState()
 
 	actionDelay := time.Duration(request.ActionDelaysInMs) * time.Millisecond
 	for _, action := range request.Actions {
@@ -124,9 +127,8 @@ func (s *service) run(context *endly.Context, request *RunRequest) (*RunResponse
 			}
 		}
 	}
-	var err error
 	if request.Expect != nil {
-		response.Assert, err = validator.Assert(context, request, request.Expect, response.Data, "selenium", "assert selenium response")
+		response.Assert, response.Capture, err = s.assert(context, seleniumSession, request, response.Data)
 	}
 	return response, err
 }
@@ -222,6 +224,8 @@ func (s *service) close(context *endly.Context, request *CloseSessionRequest) (*
 	if err != nil {
 		return nil, err
 	}
+	seleniumSession.mutex.Lock()
+	defer seleniumSession.mutex.Unlock()
 	err = seleniumSession.driver.Close()
 	return response, err
 }
@@ -328,8 +332,7 @@ func (s *service) start(context *endly.Context, request *StartRequest) (*StartRe
 }
 
 func (s *service) session(context *endly.Context, sessionID string) (*Session, error) {
-	sessions := Sessions(context)
-	if seleniumSession, ok := sessions[sessionID]; ok {
+	if seleniumSession, ok := getSessions(context).get(sessionID); ok {
 		return seleniumSession, nil
 	}
 	return nil, fmt.Errorf("failed to lookup seleniun session id: %v, make sure you first run SeleniumOpenSessionRequest", sessionID)
@@ -345,29 +348,25 @@ func (s *service) openSession(context *endly.Context, request *OpenSessionReques
 	if sessionID == "" {
 		sessionID = resource.Host()
 	}
-	sessions := Sessions(context)
-	seleniumSession, ok := sessions[sessionID]
-	if ok {
+	seleniumSession := getSessions(context).getOrCreate(sessionID)
+	seleniumSession.mutex.Lock()
+	defer seleniumSession.mutex.Unlock()
+	if seleniumSession.driver != nil {
 		if seleniumSession.Browser == request.Browser {
 			return seleniumSession, nil
 		}
 		seleniumSession.driver.Close()
-	} else {
-		seleniumSession = &Session{
-			ID:      sessionID,
-			Browser: request.Browser,
-		}
 	}
+	seleniumSession.Browser = request.Browser
 	caps := selenium.Capabilities{"browserName": request.Browser}
 	seleniumEndpoint := fmt.Sprintf("http://%v/wd/hub", resource.ParsedURL.Host)
-	seleniumSession.driver, err = selenium.NewRemote(caps, seleniumEndpoint)
-
+	driver, err := selenium.NewRemote(caps, seleniumEndpoint)
 	if err != nil {
 		return nil, err
 	}
-	sessions[sessionID] = seleniumSession
+	seleniumSession.driver = driver
 	context.Deffer(func() {
-		seleniumSession.driver.Quit()
+		driver.Quit()
 	})
 	return seleniumSession, nil
 }
@@ -3,6 +3,7 @@ package selenium
 import (
 	"github.com/tebeka/selenium"
 	"github.com/viant/endly"
+	"sync"
 )
 
 //Session represents a selenium session
@@ -10,23 +11,63 @@ type Session struct {
 	ID      string
 	Browser string
 	driver  selenium.WebDriver
+	mutex   *sync.Mutex //serializes web driver access, so that sessions can run concurrently
 }
 
 //SeleniumSessions reprents selenium sessions.
 type sessions struct {
 	Sessions map[string]*Session
+	mutex    *sync.RWMutex
 }
 
 var sessionKey = (*sessions)(nil)
 
-func Sessions(context *endly.Context) map[string]*Session {
+var sessionsMutex = &sync.Mutex{}
+
+//getSessions returns context sessions registry
+func getSessions(context *endly.Context) *sessions {
+	sessionsMutex.Lock()
+	defer sessionsMutex.Unlock()
 	var result *sessions
 	if !context.Contains(sessionKey) {
 		result = &sessions{
 			Sessions: make(map[string]*Session),
+			mutex:    &sync.RWMutex{},
 		}
-		context.Put(sessionKey, result)
+		_ = context.Put(sessionKey, result)
+		return result
 	}
 	context.GetInto(sessionKey, &result)
-	return result.Sessions
+	return result
+}
+
+//get returns an opened session
+func (s *sessions) get(ID string) (*Session, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	result, ok := s.Sessions[ID]
+	if ok && result.driver == nil {
+		return nil, false
+	}
+	return result, ok
+}
+
+//getOrCreate returns existing or newly registered session
+func (s *sessions) getOrCreate(ID string) *Session {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	result, ok := s.Sessions[ID]
+	if !ok {
+		result = &Session{ID: ID}
+		s.Sessions[ID] = result
+	}
+	if result.mutex == nil {
+		result.mutex = &sync.Mutex{}
+	}
+	return result
+}
+
+//Sessions returns selenium sessions
+func Sessions(context *endly.Context) map[string]*Session {
+	return getSessions(context).Sessions
 }
@@ -0,0 +1,127 @@
+package selenium
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/tebeka/selenium"
+	"github.com/viant/endly"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//fakeDriver emulates web driver, it tracks concurrent calls
+type fakeDriver struct {
+	selenium.WebDriver
+	url        string
+	active     int32
+	concurrent int32
+}
+
+func (d *fakeDriver) CurrentURL() (string, error) {
+	if atomic.AddInt32(&d.active, 1) > 1 {
+		atomic.StoreInt32(&d.concurrent, 1)
+	}
+	defer atomic.AddInt32(&d.active, -1)
+	time.Sleep(5 * time.Millisecond)
+	return d.url, nil
+}
+
+func (d *fakeDriver) Screenshot() ([]byte, error) {
+	return []byte("png"), nil
+}
+
+func (d *fakeDriver) PageSource() (string, error) {
+	return "<html>" + d.url + "</html>", nil
+}
+
+func TestService_RunConcurrentSessions(t *testing.T) {
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+	service := New().(*service)
+	_ = Sessions(context)
+	var drivers = make(map[string]*fakeDriver)
+	for _, ID := range []string{"chrome", "firefox"} {
+		drivers[ID] = &fakeDriver{url: "http://127.0.0.1/" + ID}
+		getSessions(context).getOrCreate(ID).driver = drivers[ID]
+	}
+	waitGroup := &sync.WaitGroup{}
+	var errors = make(chan error, 8)
+	for i := 0; i < 4; i++ {
+		for _, ID := range []string{"chrome", "firefox"} {
+			waitGroup.Add(1)
+			go func(context *endly.Context, ID string) {
+				defer waitGroup.Done()
+				response, err := service.run(context, &RunRequest{
+					SessionID: ID,
+					Actions:   []*Action{NewAction("", "", "CurrentURL"), NewAction("", "", "CurrentURL")},
+					Expect:    map[string]interface{}{"CurrentURL": "http://127.0.0.1/" + ID},
+				})
+				if err == nil && response.Assert.HasFailure() {
+					err = fmt.Errorf("%v: unexpected failure: %v", ID, response.Assert.Failures)
+				}
+				errors <- err
+			}(context.Clone(), ID)
+		}
+	}
+	waitGroup.Wait()
+	close(errors)
+	for err := range errors {
+		assert.Nil(t, err)
+	}
+	for ID, driver := range drivers {
+		assert.EqualValues(t, 0, driver.concurrent, ID)
+	}
+}
+
+func TestService_RunCaptureOnFailure(t *testing.T) {
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+	service := New().(*service)
+	getSessions(context).getOrCreate("127.0.0.1:4444").driver = &fakeDriver{url: "http://127.0.0.1/login"}
+	captureDir := path.Join(os.TempDir(), "test", "endly", "selenium")
+	_ = os.RemoveAll(captureDir)
+	defer os.RemoveAll(captureDir)
+
+	request := &RunRequest{
+		SessionID:  "127.0.0.1:4444",
+		Actions:    []*Action{NewAction("", "", "CurrentURL")},
+		Expect:     map[string]interface{}{"CurrentURL": "/dashboard/"},
+		CaptureURL: captureDir,
+	}
+	response, err := service.run(context, request)
+	if !assert.Nil(t, err) || !assert.NotNil(t, response.Capture) {
+		return
+	}
+	assert.True(t, response.Assert.HasFailure())
+	assert.True(t, strings.Contains(response.Assertion()[0].Description, response.Capture.Screenshot), response.Assertion()[0].Description)
+	screenshot, err := ioutil.ReadFile(strings.Replace(response.Capture.Screenshot, "file://", "", 1))
+	if assert.Nil(t, err) {
+		assert.Equal(t, "png", string(screenshot))
+	}
+	pageSource, err := ioutil.ReadFile(strings.Replace(response.Capture.PageSource, "file://", "", 1))
+	if assert.Nil(t, err) {
+		assert.Equal(t, "<html>http://127.0.0.1/login</html>", string(pageSource))
+	}
+	assert.True(t, strings.HasPrefix(path.Base(response.Capture.Screenshot), "127.0.0.1_4444_"))
+
+	request.Expect = map[string]interface{}{"CurrentURL": "/login/"}
+	response, err = service.run(context, request)
+	if assert.Nil(t, err) {
+		assert.False(t, response.Assert.HasFailure())
+		assert.Nil(t, response.Capture)
+	}
+
+	request.Expect = map[string]interface{}{"CurrentURL": "/dashboard/"}
+	request.SkipCapture = true
+	response, err = service.run(context, request)
+	if assert.Nil(t, err) {
+		assert.True(t, response.Assert.HasFailure())
+		assert.Nil(t, response.Capture)
+	}
+}
@@ -18,7 +18,7 @@ type service struct {
 }
 
 func (s *service) Assert(context *endly.Context, request *AssertRequest) (response *AssertResponse, err error) {
-	var state = context.State()
+	var state = context.SafeState()
 	var actual = request.Actual
 	var expect = request.Expect
 	response = &AssertResponse{}
@@ -7,7 +7,8 @@ import (
 
 //RegisterRequest represents a register udf request
 type RegisterRequest struct {
-	UDFs []*endly.UdfProvider `description:"collection of predefined udf provider name with custom parameters and new registration id"`
+	UDFs       []*endly.UdfProvider `description:"collection of predefined udf provider name with custom parameters and new registration id"`
+	Validators []*endly.UdfProvider `description:"collection of udf providers registered as validators, used in expected data as <ds:id[args]>, empty provider uses already registered udf with the same id"`
 }
 
 func NewRegisterRequestFromURL(URL string) (*RegisterRequest, error) {
@@ -17,4 +18,7 @@ func NewRegisterRequestFromURL(URL string) (*RegisterRequest, error) {
 }
 
 //RegisterRequest represents a register response
-type RegisterResponse struct{}
+type RegisterResponse struct {
+	UDFs       []string `description:"registered udf ids"`
+	Validators []string `description:"registered validator ids"`
+}
@@ -0,0 +1,64 @@
+package udf
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"plugin"
+)
+
+//expressionArgKey represents expression UDF source placeholder key, i.e. ${arg}
+const expressionArgKey = "arg"
+
+//NewExpressionUDF returns expression based udf, expression can reference udf source with $arg, i.e. $Md5(${arg})-suffix
+func NewExpressionUDF(args ...interface{}) (func(source interface{}, state data.Map) (interface{}, error), error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("expression was empty")
+	}
+	expression := args[0]
+	return func(source interface{}, state data.Map) (interface{}, error) {
+		var localState = data.NewMap()
+		if state != nil {
+			localState = state.Clone()
+		}
+		localState.Put(expressionArgKey, source)
+		return localState.Expand(expression), nil
+	}, nil
+}
+
+//NewPluginUDF returns Go plugin backed udf, it takes plugin location, exported symbol name,
+//symbol has to be either func(interface{}, data.Map) (interface{}, error) or a provider func(...interface{}) (func(interface{}, data.Map) (interface{}, error), error)
+//followed by optional provider parameters
+func NewPluginUDF(args ...interface{}) (func(source interface{}, state data.Map) (interface{}, error), error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("expected plugin location and symbol name, but had: %v", args)
+	}
+	location, symbolName := toolbox.AsString(args[0]), toolbox.AsString(args[1])
+	aPlugin, err := plugin.Open(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin %v, %v", location, err)
+	}
+	symbol, err := aPlugin.Lookup(symbolName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup %v in plugin %v, %v", symbolName, location, err)
+	}
+	switch fn := symbol.(type) {
+	case func(source interface{}, state data.Map) (interface{}, error):
+		return fn, nil
+	case *func(source interface{}, state data.Map) (interface{}, error):
+		return *fn, nil
+	case func(args ...interface{}) (func(source interface{}, state data.Map) (interface{}, error), error):
+		return fn(args[2:]...)
+	}
+	return nil, fmt.Errorf("unsupported plugin %v symbol %v type: %T", location, symbolName, symbol)
+}
+
+//Register registers udf in global registry and supplied context state, making it available for subsequent expansion and validation
+func Register(context *endly.Context, id string, udf func(source interface{}, state data.Map) (interface{}, error)) {
+	endly.RegisterUdf(id, udf)
+	if context != nil {
+		state := context.SafeState()
+		state.Put(id, udf)
+	}
+}
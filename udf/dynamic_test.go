@@ -0,0 +1,35 @@
+package udf
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"testing"
+)
+
+func TestNewExpressionUDF(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	state := context.State()
+	state.Put("version", "1.0")
+	err := RegisterProvidersWithContext(context, []*endly.UdfProvider{
+		{
+			ID:       "AppVersion",
+			Provider: "Expression",
+			Params:   []interface{}{"${arg}-v${version}"},
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "myapp-v1.0", context.Expand("$AppVersion(myapp)"))
+	transformed, err := TransformWithUDF(context, "AppVersion", "", "app")
+	assert.Nil(t, err)
+	assert.Equal(t, "app-v1.0", transformed)
+}
+
+func TestNewPluginUDF(t *testing.T) {
+	_, err := NewPluginUDF("/tmp/endly_missing_plugin.so", "Udf")
+	assert.NotNil(t, err)
+	_, err = NewPluginUDF("/tmp/endly_missing_plugin.so")
+	assert.NotNil(t, err)
+}
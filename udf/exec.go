@@ -0,0 +1,60 @@
+package udf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"os/exec"
+	"strings"
+)
+
+//ExecInput represents exec udf process input, written as JSON to process stdin
+type ExecInput struct {
+	Source interface{}
+}
+
+//ExecOutput represents exec udf process output, read as JSON from process stdout
+type ExecOutput struct {
+	Result interface{}
+	Error  string
+}
+
+//NewExecUDF returns external process backed udf, it takes command followed by optional command arguments,
+//for each call process receives ExecInput JSON on stdin and has to write ExecOutput JSON to stdout, i.e. {"Result":"abc"}
+func NewExecUDF(args ...interface{}) (func(source interface{}, state data.Map) (interface{}, error), error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("command was empty")
+	}
+	command := toolbox.AsString(args[0])
+	if _, err := exec.LookPath(command); err != nil {
+		return nil, fmt.Errorf("failed to lookup command %v, %v", command, err)
+	}
+	var commandArgs = make([]string, 0)
+	for _, arg := range args[1:] {
+		commandArgs = append(commandArgs, toolbox.AsString(arg))
+	}
+	return func(source interface{}, state data.Map) (interface{}, error) {
+		input, err := json.Marshal(&ExecInput{Source: source})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %v input, %v", command, err)
+		}
+		cmd := exec.Command(command, commandArgs...)
+		cmd.Stdin = bytes.NewReader(input)
+		stderr := new(bytes.Buffer)
+		cmd.Stderr = stderr
+		stdout, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to run %v, %v %v", command, err, strings.TrimSpace(stderr.String()))
+		}
+		output := &ExecOutput{}
+		if err = json.Unmarshal(stdout, output); err != nil {
+			return nil, fmt.Errorf("failed to decode %v output: %s, %v", command, stdout, err)
+		}
+		if output.Error != "" {
+			return nil, fmt.Errorf("%v: %v", command, output.Error)
+		}
+		return output.Result, nil
+	}, nil
+}
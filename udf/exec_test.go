@@ -0,0 +1,62 @@
+package udf
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/assertly"
+	"github.com/viant/endly"
+	"testing"
+)
+
+func TestNewExecUDF(t *testing.T) {
+	udf, err := NewExecUDF("sed", `s/{"Source":\(.*\)}/{"Result":\1}/`)
+	if !assert.Nil(t, err) {
+		return
+	}
+	result, err := udf(map[string]interface{}{"id": 1}, nil)
+	if assert.Nil(t, err) {
+		assert.EqualValues(t, map[string]interface{}{"id": 1.0}, result)
+	}
+
+	udf, err = NewExecUDF("sh", "-c", `echo '{"Error":"invalid input"}'`)
+	if assert.Nil(t, err) {
+		_, err = udf("abc", nil)
+		assert.NotNil(t, err)
+	}
+	_, err = NewExecUDF("endly_missing_command")
+	assert.NotNil(t, err)
+}
+
+func TestService_RegisterValidators(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	defer context.Close()
+	response := &RegisterResponse{}
+	err := endly.Run(context, &RegisterRequest{
+		UDFs: []*endly.UdfProvider{
+			{ID: "HasDomain", Provider: "Expression", Params: []interface{}{"$AsBool(true)"}},
+		},
+		Validators: []*endly.UdfProvider{
+			{ID: "isEmail", Provider: "Exec", Params: []interface{}{"sh", "-c", `grep -q '@' && echo '{"Result":true}' || echo '{"Result":false}'`}},
+			{ID: "HasDomain"},
+		},
+	}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"isEmail", "HasDomain"}, response.Validators)
+
+	validation, err := assertly.Assert(map[string]interface{}{"email": "<ds:isEmail>", "domain": "<ds:HasDomain[\"acme.com\"]>"},
+		map[string]interface{}{"email": "dev@acme.com", "domain": "acme.com"}, assertly.NewDataPath("/"))
+	if assert.Nil(t, err) {
+		assert.Equal(t, 0, validation.FailedCount, validation.Report())
+		assert.Equal(t, 2, validation.PassedCount)
+	}
+	validation, err = assertly.Assert(map[string]interface{}{"email": "<ds:isEmail>"},
+		map[string]interface{}{"email": "dev"}, assertly.NewDataPath("/"))
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, validation.FailedCount)
+	}
+
+	err = endly.Run(context, &RegisterRequest{Validators: []*endly.UdfProvider{{ID: "missingUdf"}}}, response)
+	assert.NotNil(t, err)
+}
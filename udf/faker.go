@@ -0,0 +1,251 @@
+package udf
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+var firstNames = []string{"James", "Mary", "Robert", "Patricia", "John", "Jennifer", "Michael", "Linda", "David", "Elizabeth",
+	"William", "Barbara", "Richard", "Susan", "Joseph", "Jessica", "Thomas", "Sarah", "Charles", "Karen", "Daniel", "Nancy",
+	"Matthew", "Lisa", "Anthony", "Betty", "Mark", "Sandra", "Steven", "Ashley", "Andrew", "Emily", "Kevin", "Michelle"}
+
+var lastNames = []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+	"Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin", "Lee", "Perez",
+	"Thompson", "White", "Harris", "Sanchez", "Clark", "Ramirez", "Lewis", "Robinson", "Walker", "Young", "Allen", "King"}
+
+var emailDomains = []string{"example.com", "example.org", "example.net", "test.com"}
+
+var loremWords = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+	"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim", "ad", "minim", "veniam",
+	"quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip", "ex", "ea", "commodo", "consequat"}
+
+//faker represents fake data generator shared by faker UDFs
+type faker struct {
+	mutex     *sync.Mutex
+	random    *rand.Rand
+	sequences map[string]int64
+}
+
+func (f *faker) intn(n int) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.random.Intn(n)
+}
+
+func (f *faker) float64() float64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.random.Float64()
+}
+
+func (f *faker) normFloat64() float64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.random.NormFloat64()
+}
+
+func (f *faker) pick(values []string) string {
+	return values[f.intn(len(values))]
+}
+
+func (f *faker) next(name string, start int64) int64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	value, ok := f.sequences[name]
+	if !ok {
+		value = start - 1
+	}
+	value++
+	f.sequences[name] = value
+	return value
+}
+
+func (f *faker) seed(seed int64) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.random = rand.New(rand.NewSource(seed))
+	f.sequences = make(map[string]int64)
+}
+
+var fake = &faker{
+	mutex:     &sync.Mutex{},
+	random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	sequences: make(map[string]int64),
+}
+
+//fakerArgs returns udf arguments, $Udf() and $Udf(value) are treated as empty and single argument respectively
+func fakerArgs(source interface{}) []interface{} {
+	if source == nil {
+		return []interface{}{}
+	}
+	if toolbox.IsSlice(source) {
+		return toolbox.AsSlice(source)
+	}
+	if text, ok := source.(string); ok && strings.TrimSpace(text) == "" {
+		return []interface{}{}
+	}
+	return []interface{}{source}
+}
+
+//fakerArg returns trimmed text argument at index or default value, non JSON list arguments i.e. [order, 100] come with brackets
+func fakerArg(args []interface{}, index int, defaultValue string) string {
+	if index >= len(args) {
+		return defaultValue
+	}
+	value := strings.Trim(toolbox.AsString(args[index]), " '\"[]")
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+//fakerNumberArgs returns numeric arguments with defaults
+func fakerNumberArgs(source interface{}, defaults ...float64) ([]float64, error) {
+	args := fakerArgs(source)
+	var result = make([]float64, len(defaults))
+	for i := range defaults {
+		result[i] = defaults[i]
+		if text := fakerArg(args, i, ""); text != "" {
+			value, err := toolbox.ToFloat(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid numeric argument %v: %v", i, text)
+			}
+			result[i] = value
+		}
+	}
+	return result, nil
+}
+
+//asFakerTime returns time for time expression (i.e. now, 2 days ago, 3 hours ahead) or RFC3339 timestamp
+func asFakerTime(expression string) (*time.Time, error) {
+	if result, err := toolbox.TimeAt(expression); err == nil {
+		return result, nil
+	}
+	return toolbox.ToTime(expression, time.RFC3339)
+}
+
+//FakeSeed reseeds fake data generator and resets sequences, so that generated values are repeatable, i.e. $FakeSeed(42)
+func FakeSeed(source interface{}, state data.Map) (interface{}, error) {
+	seed, err := toolbox.ToInt(fakerArg(fakerArgs(source), 0, "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed: %v", source)
+	}
+	fake.seed(int64(seed))
+	return seed, nil
+}
+
+//FakeFirstName returns random first name
+func FakeFirstName(source interface{}, state data.Map) (interface{}, error) {
+	return fake.pick(firstNames), nil
+}
+
+//FakeLastName returns random last name
+func FakeLastName(source interface{}, state data.Map) (interface{}, error) {
+	return fake.pick(lastNames), nil
+}
+
+//FakeName returns random full name
+func FakeName(source interface{}, state data.Map) (interface{}, error) {
+	return fake.pick(firstNames) + " " + fake.pick(lastNames), nil
+}
+
+//FakeEmail returns random email, it takes optional domain, i.e. $FakeEmail(acme.com)
+func FakeEmail(source interface{}, state data.Map) (interface{}, error) {
+	domain := fakerArg(fakerArgs(source), 0, fake.pick(emailDomains))
+	return fmt.Sprintf("%v.%v%v@%v", strings.ToLower(fake.pick(firstNames)), strings.ToLower(fake.pick(lastNames)), fake.intn(1000), domain), nil
+}
+
+//UUID returns new random UUID
+func UUID(source interface{}, state data.Map) (interface{}, error) {
+	result, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	return result.String(), nil
+}
+
+//Sequence returns next value of named sequence, it takes sequence name and optional start value (default 1), i.e. $Sequence([user, 100])
+func Sequence(source interface{}, state data.Map) (interface{}, error) {
+	args := fakerArgs(source)
+	start, err := toolbox.ToInt(fakerArg(args, 1, "1"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sequence start: %v", args[1])
+	}
+	return fake.next(fakerArg(args, 0, "default"), int64(start)), nil
+}
+
+//FakeTime returns random time within range, it takes optional from (default 1 year ago), to (default now) and date format (default RFC3339),
+//range bounds can be time expression or RFC3339 timestamp, i.e. $FakeTime(['7 days ago', now, 'yyyy-MM-dd'])
+func FakeTime(source interface{}, state data.Map) (interface{}, error) {
+	args := fakerArgs(source)
+	from, err := asFakerTime(fakerArg(args, 0, "1 year ago"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid from time: %v, %v", args[0], err)
+	}
+	to, err := asFakerTime(fakerArg(args, 1, "now"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid to time: %v, %v", args[1], err)
+	}
+	if to.Before(*from) {
+		return nil, fmt.Errorf("invalid time range: %v - %v", from, to)
+	}
+	result := from.Add(time.Duration(fake.float64() * float64(to.Sub(*from))))
+	layout := time.RFC3339
+	if dateFormat := fakerArg(args, 2, ""); dateFormat != "" {
+		layout = toolbox.DateFormatToLayout(dateFormat)
+	}
+	return result.Format(layout), nil
+}
+
+//FakeInt returns random int within inclusive range, it takes optional min (default 0) and max (default 100)
+func FakeInt(source interface{}, state data.Map) (interface{}, error) {
+	bounds, err := fakerNumberArgs(source, 0, 100)
+	if err != nil {
+		return nil, err
+	}
+	min, max := int(bounds[0]), int(bounds[1])
+	if max < min {
+		return nil, fmt.Errorf("invalid range: %v..%v", min, max)
+	}
+	return min + fake.intn(max-min+1), nil
+}
+
+//FakeFloat returns uniformly distributed random float within range, it takes optional min (default 0) and max (default 1)
+func FakeFloat(source interface{}, state data.Map) (interface{}, error) {
+	bounds, err := fakerNumberArgs(source, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if bounds[1] < bounds[0] {
+		return nil, fmt.Errorf("invalid range: %v..%v", bounds[0], bounds[1])
+	}
+	return bounds[0] + fake.float64()*(bounds[1]-bounds[0]), nil
+}
+
+//FakeNormal returns normally distributed random float, it takes optional mean (default 0) and standard deviation (default 1)
+func FakeNormal(source interface{}, state data.Map) (interface{}, error) {
+	params, err := fakerNumberArgs(source, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	return params[0] + fake.normFloat64()*params[1], nil
+}
+
+//Lorem returns lorem ipsum text, it takes optional number of words (default 10)
+func Lorem(source interface{}, state data.Map) (interface{}, error) {
+	params, err := fakerNumberArgs(source, 10)
+	if err != nil {
+		return nil, err
+	}
+	var words = make([]string, int(params[0]))
+	for i := range words {
+		words[i] = fake.pick(loremWords)
+	}
+	return strings.Join(words, " "), nil
+}
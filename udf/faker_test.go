@@ -0,0 +1,52 @@
+package udf
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFakerUDFs(t *testing.T) {
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+
+	assert.Regexp(t, regexp.MustCompile(`^\w+ \w+$`), context.Expand("$FakeName()"))
+	assert.Regexp(t, regexp.MustCompile(`^[a-z]+\.[a-z]+\d+@acme.com$`), context.Expand("$FakeEmail(acme.com)"))
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`), context.Expand("$UUID()"))
+	assert.NotEqual(t, context.Expand("$UUID()"), context.Expand("$UUID()"))
+	assert.Equal(t, 5, len(strings.Split(toolbox.AsString(context.Expand("$Lorem(5)")), " ")))
+
+	assert.EqualValues(t, 100, toolbox.AsInt(context.Expand("$Sequence([order, 100])")))
+	assert.EqualValues(t, 101, toolbox.AsInt(context.Expand("$Sequence([order, 100])")))
+	assert.EqualValues(t, 1, toolbox.AsInt(context.Expand("$Sequence(user)")))
+
+	for i := 0; i < 20; i++ {
+		value := toolbox.AsInt(context.Expand("$FakeInt([5, 7])"))
+		assert.True(t, value >= 5 && value <= 7, value)
+		float := toolbox.AsFloat(context.Expand("$FakeFloat([1.5, 2.5])"))
+		assert.True(t, float >= 1.5 && float <= 2.5, float)
+	}
+
+	timestamp, err := time.Parse("2006-01-02", toolbox.AsString(context.Expand("$FakeTime(['3 days ago', now, 'yyyy-MM-dd'])")))
+	if assert.Nil(t, err) {
+		assert.True(t, time.Since(timestamp) <= 4*24*time.Hour, timestamp)
+	}
+
+	_, _ = FakeSeed(42, nil)
+	first, _ := FakeName(nil, nil)
+	normal, _ := FakeNormal([]interface{}{10, 2}, nil)
+	_, _ = FakeSeed(42, nil)
+	second, _ := FakeName(nil, nil)
+	assert.Equal(t, first, second)
+	normalAgain, _ := FakeNormal([]interface{}{10, 2}, nil)
+	assert.Equal(t, normal, normalAgain)
+
+	_, err = FakeInt([]interface{}{10, 1}, nil)
+	assert.NotNil(t, err)
+	_, err = FakeTime([]interface{}{"now", "2 days ago"}, nil)
+	assert.NotNil(t, err)
+}
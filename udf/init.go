@@ -17,9 +17,25 @@ func init() {
 	endly.UdfRegistry["GZipContentCorrupter"] = GZipContentCorrupter
 	endly.UdfRegistry["AvroReader"] = NewAvroReader
 
+	endly.UdfRegistry["FakeSeed"] = FakeSeed
+	endly.UdfRegistry["FakeFirstName"] = FakeFirstName
+	endly.UdfRegistry["FakeLastName"] = FakeLastName
+	endly.UdfRegistry["FakeName"] = FakeName
+	endly.UdfRegistry["FakeEmail"] = FakeEmail
+	endly.UdfRegistry["FakeTime"] = FakeTime
+	endly.UdfRegistry["FakeInt"] = FakeInt
+	endly.UdfRegistry["FakeFloat"] = FakeFloat
+	endly.UdfRegistry["FakeNormal"] = FakeNormal
+	endly.UdfRegistry["Lorem"] = Lorem
+	endly.UdfRegistry["UUID"] = UUID
+	endly.UdfRegistry["Sequence"] = Sequence
+
 	endly.UdfRegistryProvider["AvroWriter"] = NewAvroWriter
 	endly.UdfRegistryProvider["ProtoReader"] = NewProtoReader
 	endly.UdfRegistryProvider["ProtoWriter"] = NewProtoWriter
 	endly.UdfRegistryProvider["CsvReader"] = NewCsvReader
+	endly.UdfRegistryProvider["Expression"] = NewExpressionUDF
+	endly.UdfRegistryProvider["Plugin"] = NewPluginUDF
+	endly.UdfRegistryProvider["Exec"] = NewExecUDF
 
 }
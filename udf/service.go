@@ -19,7 +19,7 @@ func (s *service) registerRoutes() {
 	s.Register(&endly.Route{
 		Action: "register",
 		RequestInfo: &endly.ActionInfo{
-			Description: "no operation action, helper for separating action.Init as self descriptive steps",
+			Description: "register custom UDF or validator with predefined, expression, Go plugin or exec udf provider",
 		},
 		RequestProvider: func() interface{} {
 			return &RegisterRequest{}
@@ -37,16 +37,26 @@ func (s *service) registerRoutes() {
 }
 
 func (s *service) register(context *endly.Context, request *RegisterRequest) (interface{}, error) {
-	state := context.State()
-	for _, udf := range request.UDFs {
+	state := context.SafeState()
+	for _, udf := range append(request.UDFs, request.Validators...) {
 		for i, item := range udf.Params {
 			udf.Params[i] = state.Expand(item)
 		}
 	}
-	if err := RegisterProviders(request.UDFs); err != nil {
+	if err := RegisterProvidersWithContext(context, request.UDFs); err != nil {
+		return nil, err
+	}
+	if err := RegisterValidatorProviders(request.Validators); err != nil {
 		return nil, err
 	}
-	return &RegisterResponse{}, nil
+	var response = &RegisterResponse{UDFs: make([]string, 0), Validators: make([]string, 0)}
+	for _, udf := range request.UDFs {
+		response.UDFs = append(response.UDFs, udf.ID)
+	}
+	for _, validator := range request.Validators {
+		response.Validators = append(response.Validators, validator.ID)
+	}
+	return response, nil
 }
 
 //New creates a new udf service.
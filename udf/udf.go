@@ -24,15 +24,21 @@ import (
 
 //TransformWithUDF transform payload with provided UDFs name.
 func TransformWithUDF(context *endly.Context, udfName, source string, payload interface{}) (interface{}, error) {
-	var state = context.State()
-	var udf, has = endly.UdfRegistry[udfName]
+	var state = context.SafeState()
+	var udf, has = endly.LookupUdf(udfName)
 	if !has {
-		udf, has = getUdfFromContext(udfName, state)
+		state.Read(func(state data.Map) {
+			udf, has = getUdfFromContext(udfName, state)
+		})
 	}
 	if !has {
 		return nil, fmt.Errorf("failed to lookup udf: %v for: %v", udfName, source)
 	}
-	transformed, err := udf(payload, state)
+	var transformed interface{}
+	var err error
+	state.Read(func(state data.Map) {
+		transformed, err = udf(payload, state)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to run udf: %v, %v", udfName, err)
 	}
@@ -237,21 +243,54 @@ func GZipContentCorrupter(source interface{}, state data.Map) (interface{}, erro
 
 //RegisterProviders register the supplied providers
 func RegisterProviders(providers []*endly.UdfProvider) error {
+	return RegisterProvidersWithContext(nil, providers)
+}
+
+//RegisterProvidersWithContext register providers udf, registered udf are also published to supplied context state
+func RegisterProvidersWithContext(context *endly.Context, providers []*endly.UdfProvider) error {
 	if len(providers) == 0 {
 		return nil
 	}
 
 	for _, meta := range providers {
-		provider, ok := endly.UdfRegistryProvider[meta.Provider]
-		if !ok {
-			var available = toolbox.MapKeysToStringSlice(endly.UdfRegistryProvider)
-			return fmt.Errorf("failed to lookup udf provider: %v, available: %v", meta.Provider, strings.Join(available, ","))
+		udf, err := newProviderUDF(meta)
+		if err != nil {
+			return err
 		}
-		udf, err := provider(meta.Params...)
+		Register(context, meta.ID, udf)
+	}
+	return nil
+}
+
+//RegisterValidatorProviders register providers udf as assertly validators, provider can be empty to use already registered udf with the same id
+func RegisterValidatorProviders(providers []*endly.UdfProvider) error {
+	for _, meta := range providers {
+		if meta.Provider == "" {
+			udf, ok := endly.LookupUdf(meta.ID)
+			if !ok {
+				return fmt.Errorf("failed to lookup udf: %v", meta.ID)
+			}
+			RegisterValidator(meta.ID, udf)
+			continue
+		}
+		udf, err := newProviderUDF(meta)
 		if err != nil {
-			return fmt.Errorf("failed to get udf from provider %v %v", meta.Provider, err)
+			return err
 		}
-		endly.UdfRegistry[meta.ID] = udf
+		RegisterValidator(meta.ID, udf)
 	}
 	return nil
 }
+
+func newProviderUDF(meta *endly.UdfProvider) (func(source interface{}, state data.Map) (interface{}, error), error) {
+	provider, ok := endly.UdfRegistryProvider[meta.Provider]
+	if !ok {
+		var available = toolbox.MapKeysToStringSlice(endly.UdfRegistryProvider)
+		return nil, fmt.Errorf("failed to lookup udf provider: %v, available: %v", meta.Provider, strings.Join(available, ","))
+	}
+	udf, err := provider(meta.Params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get udf from provider %v %v", meta.Provider, err)
+	}
+	return udf, nil
+}
@@ -0,0 +1,38 @@
+package udf
+
+import (
+	"github.com/viant/assertly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+//udfPredicate represents udf backed predicate, udf is called with actual value followed by validator arguments and has to return true for valid value
+type udfPredicate struct {
+	udf       func(source interface{}, state data.Map) (interface{}, error)
+	arguments []interface{}
+}
+
+//Apply returns true if udf accepts supplied value
+func (p *udfPredicate) Apply(value interface{}) bool {
+	var source = append([]interface{}{value}, p.arguments...)
+	result, err := p.udf(source, nil)
+	if err != nil {
+		return false
+	}
+	return toolbox.AsBoolean(result)
+}
+
+//udfPredicateProvider represents assertly value provider returning udf backed predicate
+type udfPredicateProvider struct {
+	udf func(source interface{}, state data.Map) (interface{}, error)
+}
+
+//Get returns udf predicate for supplied validator arguments
+func (p *udfPredicateProvider) Get(context toolbox.Context, arguments ...interface{}) (interface{}, error) {
+	return &udfPredicate{udf: p.udf, arguments: arguments}, nil
+}
+
+//RegisterValidator registers udf as assertly validator, that can be used in expected data as <ds:id> or <ds:id[arg1,argN]>
+func RegisterValidator(id string, udf func(source interface{}, state data.Map) (interface{}, error)) {
+	assertly.ValueProviderRegistry.Register(id, &udfPredicateProvider{udf: udf})
+}
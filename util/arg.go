@@ -68,3 +68,19 @@ func normalizeArgument(baseURLs []string, value string) (interface{}, error) {
 	}
 	return loaded, nil
 }
+
+//ApplyArguments sets key=value pairs into target, dotted keys set nested values, i.e. app.version=1.0
+func ApplyArguments(target data.Map, pairs []string, baseURLs ...string) error {
+	for _, pair := range pairs {
+		keyValuePair := strings.SplitN(pair, "=", 2)
+		if len(keyValuePair) != 2 || strings.TrimSpace(keyValuePair[0]) == "" {
+			return fmt.Errorf("invalid argument: %v, expected key=value", pair)
+		}
+		normalized, err := normalizeArgument(baseURLs, keyValuePair[1])
+		if err != nil {
+			return err
+		}
+		target.SetValue(strings.TrimSpace(keyValuePair[0]), normalized)
+	}
+	return nil
+}
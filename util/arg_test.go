@@ -0,0 +1,47 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/data"
+	"testing"
+)
+
+func TestApplyArguments(t *testing.T) {
+	var useCases = []struct {
+		description string
+		target      data.Map
+		pairs       []string
+		expect      map[string]interface{}
+		hasError    bool
+	}{
+		{
+			description: "override and add",
+			target:      data.Map{"app": "myapp", "env": "dev"},
+			pairs:       []string{"env=prod", "version=1.0=rc"},
+			expect:      map[string]interface{}{"app": "myapp", "env": "prod", "version": "1.0=rc"},
+		},
+		{
+			description: "nested key",
+			target:      data.Map{"db": map[string]interface{}{"host": "127.0.0.1", "port": "3306"}},
+			pairs:       []string{"db.host=db.example.com"},
+			expect:      map[string]interface{}{"db": map[string]interface{}{"host": "db.example.com", "port": "3306"}},
+		},
+		{
+			description: "invalid pair",
+			target:      data.Map{},
+			pairs:       []string{"env"},
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		err := ApplyArguments(useCase.target, useCase.pairs)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, map[string]interface{}(useCase.target), useCase.description)
+	}
+}
@@ -44,3 +44,24 @@ func BuildLowerCaseMapping(aMap map[string]interface{}) map[string]string {
 	}
 	return result
 }
+
+//MergeMap deeply merges source into dest, nested maps are merged, other source values override dest values
+func MergeMap(dest, source map[string]interface{}) {
+	for k, v := range source {
+		sourceMap, ok := v.(map[string]interface{})
+		if !ok {
+			dest[k] = v
+			continue
+		}
+		destMap, ok := dest[k].(map[string]interface{})
+		if !ok {
+			destMap = make(map[string]interface{})
+		} else {
+			merged := make(map[string]interface{}, len(destMap))
+			Append(merged, destMap, true)
+			destMap = merged
+		}
+		MergeMap(destMap, sourceMap)
+		dest[k] = destMap
+	}
+}
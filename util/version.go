@@ -0,0 +1,28 @@
+package util
+
+import (
+	"github.com/viant/toolbox"
+	"strings"
+)
+
+//CompareVersion compares dot separated versions, it returns -1 if v1 < v2, 0 if v1 == v2 and 1 if v1 > v2
+func CompareVersion(v1, v2 string) int {
+	fragments1 := strings.Split(strings.TrimPrefix(strings.TrimSpace(v1), "v"), ".")
+	fragments2 := strings.Split(strings.TrimPrefix(strings.TrimSpace(v2), "v"), ".")
+	for i := 0; i < len(fragments1) || i < len(fragments2); i++ {
+		var n1, n2 int
+		if i < len(fragments1) {
+			n1 = toolbox.AsInt(fragments1[i])
+		}
+		if i < len(fragments2) {
+			n2 = toolbox.AsInt(fragments2[i])
+		}
+		if n1 < n2 {
+			return -1
+		}
+		if n1 > n2 {
+			return 1
+		}
+	}
+	return 0
+}
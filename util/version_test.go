@@ -0,0 +1,24 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCompareVersion(t *testing.T) {
+	var useCases = []struct {
+		description string
+		v1          string
+		v2          string
+		expect      int
+	}{
+		{description: "equal", v1: "0.65.2", v2: "0.65.2", expect: 0},
+		{description: "prefix", v1: "v0.65.2", v2: "0.65.2", expect: 0},
+		{description: "missing patch", v1: "0.65", v2: "0.65.0", expect: 0},
+		{description: "older", v1: "0.9.1", v2: "0.65.0", expect: -1},
+		{description: "newer", v1: "1.0.0", v2: "0.65.2", expect: 1},
+	}
+	for _, useCase := range useCases {
+		assert.Equal(t, useCase.expect, CompareVersion(useCase.v1, useCase.v2), useCase.description)
+	}
+}
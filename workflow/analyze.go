@@ -0,0 +1,183 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/endly/util"
+)
+
+//variableDefinition represents location where variable is set
+type variableDefinition struct {
+	name   string
+	task   *model.Task
+	action *model.Action
+}
+
+//workflowAnalyzer finds unused variables and unreachable tasks
+type workflowAnalyzer struct {
+	*workflowValidator
+	definitions []*variableDefinition
+	defined     map[string]bool
+	reads       map[string]bool
+}
+
+func (a *workflowAnalyzer) define(task *model.Task, action *model.Action, variables model.Variables) {
+	for _, variable := range variables {
+		a.defineKey(task, action, variable.Name)
+	}
+}
+
+func (a *workflowAnalyzer) defineKey(task *model.Task, action *model.Action, key string) {
+	root := variableRoot(key)
+	if root == "" || a.defined[root] {
+		return
+	}
+	a.defined[root] = true
+	a.definitions = append(a.definitions, &variableDefinition{name: root, task: task, action: action})
+}
+
+func (a *workflowAnalyzer) read(source interface{}) {
+	for _, name := range variableReferences(source) {
+		a.reads[name] = true
+	}
+}
+
+func (a *workflowAnalyzer) readVariables(variables model.Variables) {
+	for _, variable := range variables {
+		a.read(variable.Value)
+		a.read(variable.When)
+		a.read(variable.Else)
+		a.read(variable.Default)
+		if variable.From != "" {
+			a.reads[variableRoot(variable.From)] = true
+		}
+	}
+}
+
+func (a *workflowAnalyzer) readNode(node *model.AbstractNode) {
+	if node == nil {
+		return
+	}
+	a.read(node.When)
+	a.readVariables(node.Init)
+	a.readVariables(node.Post)
+}
+
+func (a *workflowAnalyzer) readContract(contract model.StateContract) {
+	for _, field := range contract {
+		a.reads[variableRoot(field.Name)] = true
+	}
+}
+
+//collect collects variables set and read by tasks and actions
+func (a *workflowAnalyzer) collect(node *model.TasksNode) {
+	if node == nil {
+		return
+	}
+	for _, task := range node.Tasks {
+		if task.AbstractNode != nil {
+			a.define(task, nil, task.AbstractNode.Init)
+			a.define(task, nil, task.AbstractNode.Post)
+			a.readNode(task.AbstractNode)
+		}
+		for _, action := range task.Actions {
+			if action.AbstractNode != nil {
+				a.define(task, action, action.AbstractNode.Init)
+				a.define(task, action, action.AbstractNode.Post)
+				a.readNode(action.AbstractNode)
+			}
+			a.read(action.Skip)
+			a.read(action.ForEach)
+			if action.Repeater != nil {
+				a.readVariables(action.Repeater.Variables)
+				a.read(action.Repeater.Exit)
+				for _, extract := range action.Repeater.Extract {
+					a.defineKey(task, action, extract.Key)
+				}
+			}
+			if action.ServiceRequest != nil {
+				request, _ := util.NormalizeMap(action.Request, true)
+				a.read(request)
+			}
+		}
+		a.collect(task.TasksNode)
+	}
+}
+
+//checkUnused reports variables that are set but never read, workflow post variables are outputs
+func (a *workflowAnalyzer) checkUnused() {
+	for _, definition := range a.definitions {
+		if a.reads[definition.name] {
+			continue
+		}
+		a.addIssue(ValidationWarning, definition.task, definition.action, fmt.Sprintf("unused variable: %v is set but never read", definition.name))
+	}
+}
+
+//checkReachable reports tasks which run criteria can never be true, criteria using state keys set at runtime are skipped
+func (a *workflowAnalyzer) checkReachable(node *model.TasksNode) {
+	if node == nil {
+		return
+	}
+	for _, task := range node.Tasks {
+		if task.AbstractNode != nil && task.When != "" && a.isStatic(task.When) {
+			canRun, err := criteria.Evaluate(nil, a.state, task.When, "task.When", true)
+			if err != nil {
+				a.addIssue(ValidationError, task, nil, fmt.Sprintf("invalid when: %v", err))
+			} else if !canRun {
+				a.addIssue(ValidationWarning, task, nil, fmt.Sprintf("unreachable task: when '%v' is never true", task.When))
+			}
+		}
+		a.checkReachable(task.TasksNode)
+	}
+}
+
+//isStatic returns true if expression only uses state keys that are known before workflow runs
+func (a *workflowAnalyzer) isStatic(expression string) bool {
+	for _, name := range variableReferences(expression) {
+		if name != paramsStateKey && (a.declared[name] || !a.state.Has(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Service) analyzeWorkflow(context *endly.Context, request *AnalyzeRequest) (*ValidateResponse, error) {
+	workflow, err := s.Dao.Load(context, request.Source)
+	if err != nil {
+		var response = &ValidateResponse{Issues: []*ValidationIssue{{Level: ValidationError, Message: fmt.Sprintf("failed to load workflow: %v", err)}}}
+		if workflow != nil {
+			response.Workflow = workflow.Name
+		}
+		return response, nil
+	}
+	return s.analyze(context, workflow, request.Params)
+}
+
+//analyze validates workflow and reports unused variables and unreachable tasks
+func (s *Service) analyze(context *endly.Context, workflow *model.Workflow, params map[string]interface{}) (*ValidateResponse, error) {
+	validator, err := s.newValidator(context, workflow, params)
+	if err != nil {
+		return nil, err
+	}
+	validator.checkNode(nil, workflow.AbstractNode)
+	validator.checkTasks(workflow.TasksNode)
+	var analyzer = &workflowAnalyzer{
+		workflowValidator: validator,
+		definitions:       make([]*variableDefinition, 0),
+		defined:           make(map[string]bool),
+		reads:             make(map[string]bool),
+	}
+	if workflow.AbstractNode != nil {
+		analyzer.define(nil, nil, workflow.AbstractNode.Init)
+		analyzer.readNode(workflow.AbstractNode)
+	}
+	analyzer.readContract(workflow.Contract)
+	analyzer.readContract(workflow.Outputs)
+	analyzer.collect(workflow.TasksNode)
+	analyzer.checkUnused()
+	analyzer.checkReachable(workflow.TasksNode)
+	return validator.response, nil
+}
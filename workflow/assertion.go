@@ -0,0 +1,181 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/viant/assertly"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"sync"
+)
+
+var assertionCollectorKey = (*assertionCollector)(nil)
+
+//TagValidation represents validation counters aggregated by TagID
+type TagValidation struct {
+	TagID    string
+	Passed   int
+	Failed   int
+	Failures []string `json:",omitempty"`
+}
+
+//ValidationSummary represents consolidated workflow run validation result
+type ValidationSummary struct {
+	Passed int
+	Failed int
+	Tags   []*TagValidation
+}
+
+//assertionCollector collects validations published during workflow run
+type assertionCollector struct {
+	Listener msg.Listener
+	mux      *sync.Mutex
+	process  *model.Process
+	failFast bool
+	failure  string //first validation failure not yet reported with fail fast
+	active   []*model.Activity
+	tags     map[string]*TagValidation
+	summary  *ValidationSummary
+}
+
+//AsEventListener returns event listener that collects events and passes them to the next listener
+func (c *assertionCollector) AsEventListener() msg.Listener {
+	return func(event msg.Event) {
+		if c.Listener != nil {
+			c.Listener(event)
+		}
+		c.OnEvent(event)
+	}
+}
+
+//OnEvent collects validation events, running activities are tracked to resolve validation TagID
+func (c *assertionCollector) OnEvent(event msg.Event) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	switch value := event.Value().(type) {
+	case *model.Activity:
+		c.active = append(c.active, value)
+	case *model.ActivityEndEvent:
+		activity, ok := value.Response.(*model.Activity)
+		if !ok {
+			return
+		}
+		for i := len(c.active) - 1; i >= 0; i-- {
+			if c.active[i] == activity {
+				c.active = append(c.active[:i], c.active[i+1:]...)
+				break
+			}
+		}
+	case asserted:
+		for _, validation := range value.Assertion() {
+			if validation == nil {
+				continue
+			}
+			c.add(validation)
+		}
+	}
+}
+
+func (c *assertionCollector) add(validation *assertly.Validation) {
+	tagID := validation.TagID
+	if tagID == "" && len(c.active) > 0 {
+		activity := c.active[len(c.active)-1]
+		if activity.MetaTag != nil {
+			tagID = activity.TagID
+		}
+		if tagID == "" { //workflow without tags
+			tagID = activity.Task + "/" + activity.Service + "." + activity.Action
+		}
+	}
+	tag, ok := c.tags[tagID]
+	if !ok {
+		tag = &TagValidation{TagID: tagID}
+		c.tags[tagID] = tag
+		c.summary.Tags = append(c.summary.Tags, tag)
+	}
+	tag.Passed += validation.PassedCount
+	tag.Failed += validation.FailedCount
+	c.summary.Passed += validation.PassedCount
+	c.summary.Failed += validation.FailedCount
+	for _, failure := range validation.Failures {
+		message := failure.Message
+		if message == "" {
+			message = fmt.Sprintf("%v: expected: %v, actual: %v", failure.Path, failure.Expected, failure.Actual)
+		}
+		tag.Failures = append(tag.Failures, message)
+		if c.failFast && c.failure == "" {
+			c.failure = message
+			if tagID != "" {
+				c.failure = tagID + ": " + message
+			}
+		}
+	}
+}
+
+//takeFailure returns and clears pending fail fast validation failure
+func (c *assertionCollector) takeFailure() string {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	result := c.failure
+	c.failure = ""
+	return result
+}
+
+//complete returns validation summary
+func (c *assertionCollector) complete() *ValidationSummary {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.summary
+}
+
+func assertionCollectorFor(context *endly.Context) *assertionCollector {
+	if !context.Contains(assertionCollectorKey) {
+		return nil
+	}
+	var result *assertionCollector
+	context.GetInto(assertionCollectorKey, &result)
+	return result
+}
+
+//enableAssertionCollector collects top level workflow run validations, fail fast set by any workflow applies to the rest of the run
+func (s *Service) enableAssertionCollector(context *endly.Context, request *RunRequest, process *model.Process) error {
+	if collector := assertionCollectorFor(context); collector != nil {
+		if request.FailFast {
+			collector.mux.Lock()
+			collector.failFast = true
+			collector.mux.Unlock()
+		}
+		return nil
+	}
+	var collector = &assertionCollector{
+		Listener: context.Listener,
+		mux:      &sync.Mutex{},
+		process:  process,
+		failFast: request.FailFast,
+		tags:     make(map[string]*TagValidation),
+		summary:  &ValidationSummary{Tags: make([]*TagValidation, 0)},
+	}
+	context.Listener = collector.AsEventListener()
+	return context.Put(assertionCollectorKey, collector)
+}
+
+//checkFailFast returns an error if fail fast is enabled and validation failed
+func (s *Service) checkFailFast(context *endly.Context) error {
+	collector := assertionCollectorFor(context)
+	if collector == nil {
+		return nil
+	}
+	if failure := collector.takeFailure(); failure != "" {
+		return fmt.Errorf("validation failed: %v", failure)
+	}
+	return nil
+}
+
+//completeAssertions sets top level workflow run validation summary
+func (s *Service) completeAssertions(context *endly.Context, process *model.Process, response *RunResponse) {
+	collector := assertionCollectorFor(context)
+	if collector == nil || collector.process != process {
+		return
+	}
+	response.Summary = collector.complete()
+}
@@ -0,0 +1,37 @@
+package workflow_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	_ "github.com/viant/endly/testing/validator"
+	"github.com/viant/endly/workflow"
+	"github.com/viant/toolbox"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestService_RunValidationSummary(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	response := &workflow.RunResponse{}
+	err := endly.Run(manager.NewContext(nil), &workflow.RunRequest{URL: path.Join(parent, "test/assert/assert.yaml")}, response)
+	if !assert.Nil(t, err) || !assert.NotNil(t, response.Summary) {
+		return
+	}
+	assert.Equal(t, 5, response.Summary.Passed)
+	assert.Equal(t, 1, response.Summary.Failed)
+	if assert.Equal(t, 2, len(response.Summary.Tags)) {
+		assert.Equal(t, "check/validator.assert", response.Summary.Tags[0].TagID)
+		assert.Equal(t, 3, response.Summary.Tags[0].Passed)
+		assert.Equal(t, 1, response.Summary.Tags[0].Failed)
+		assert.Equal(t, 1, len(response.Summary.Tags[0].Failures))
+		assert.Equal(t, "verify/validator.assert", response.Summary.Tags[1].TagID)
+		assert.Equal(t, 0, response.Summary.Tags[1].Failed)
+	}
+
+	err = endly.Run(manager.NewContext(nil), &workflow.RunRequest{URL: path.Join(parent, "test/assert/assert.yaml"), FailFast: true}, response)
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "validation failed: check/validator.assert"), err.Error())
+	}
+}
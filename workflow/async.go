@@ -0,0 +1,63 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox/data"
+	"strings"
+	"sync"
+)
+
+//asyncResults collects async actions results and errors, results are merged into task result once all actions completed
+type asyncResults struct {
+	mux    *sync.Mutex
+	result data.Map
+	errors []string
+}
+
+//add adds async action result or error
+func (r *asyncResults) add(action *model.Action, result data.Map, err error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if err != nil {
+		name := action.TagID
+		if name == "" {
+			name = action.Service + "." + action.Action
+		}
+		r.errors = append(r.errors, fmt.Sprintf("%v: %v", name, err))
+		return
+	}
+	for k, v := range result {
+		r.result[k] = v
+	}
+}
+
+//apply copies collected results into supplied task result
+func (r *asyncResults) apply(result data.Map) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for k, v := range r.result {
+		result[k] = v
+	}
+}
+
+//err returns an error aggregating all failed async actions
+func (r *asyncResults) err() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	switch len(r.errors) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("async action failed: %v", r.errors[0])
+	}
+	return fmt.Errorf("%v async actions failed: %v", len(r.errors), strings.Join(r.errors, "; "))
+}
+
+func newAsyncResults() *asyncResults {
+	return &asyncResults{
+		mux:    &sync.Mutex{},
+		result: data.NewMap(),
+		errors: make([]string, 0),
+	}
+}
@@ -0,0 +1,81 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox/data"
+	"strings"
+	"testing"
+)
+
+func newAsyncTestTask(actions int, fail ...int) *model.Task {
+	var task = &model.Task{AbstractNode: &model.AbstractNode{Name: "async"}}
+	var failed = make(map[int]bool)
+	for _, i := range fail {
+		failed[i] = true
+	}
+	for i := 0; i < actions; i++ {
+		var action = &model.Action{
+			AbstractNode: &model.AbstractNode{
+				Name: fmt.Sprintf("a%v", i),
+				Post: model.Variables{
+					{Name: fmt.Sprintf("shared.key%v", i), Value: i},
+					{Name: "shared.last", Value: i},
+				},
+			},
+			ServiceRequest: &model.ServiceRequest{Service: ServiceID, Action: "nop", Request: &NopRequest{}},
+			MetaTag:        &model.MetaTag{TagID: fmt.Sprintf("tag%v", i)},
+			Async:          true,
+		}
+		if failed[i] {
+			action.ServiceRequest = &model.ServiceRequest{Service: ServiceID, Action: "fail", Request: &FailRequest{Message: fmt.Sprintf("err%v", i)}}
+		}
+		_ = action.Init()
+		task.Actions = append(task.Actions, action)
+	}
+	return task
+}
+
+func TestService_RunAsyncActions(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+
+	var useCases = []struct {
+		description string
+		task        *model.Task
+		errors      []string
+	}{
+		{
+			description: "concurrent post variables",
+			task:        newAsyncTestTask(8),
+		},
+		{
+			description: "aggregated errors",
+			task:        newAsyncTestTask(4, 1, 3),
+			errors:      []string{"2 async actions failed", "tag1", "err1", "tag3", "err3"},
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		var shared = map[string]interface{}{}
+		state := context.State()
+		state.Put("shared", shared)
+		process := model.NewProcess(nil, &model.Workflow{AbstractNode: &model.AbstractNode{Name: "test"}}, nil)
+		process.State = data.NewMap()
+		_, err := service.runTask(context, process, useCase.task)
+		context.Wait.Wait()
+		if len(useCase.errors) > 0 {
+			if !assert.NotNil(t, err, useCase.description) {
+				continue
+			}
+			for _, fragment := range useCase.errors {
+				assert.True(t, strings.Contains(err.Error(), fragment), useCase.description+" "+err.Error())
+			}
+			continue
+		}
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, 0, len(shared), "async actions should not modify parent state maps")
+	}
+}
@@ -0,0 +1,129 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+var auditLoggerKey = (*AuditLogger)(nil)
+
+//AuditRecord represents an executed action audit record
+type AuditRecord struct {
+	Timestamp time.Time
+	SessionID string
+	TagID     string
+	Service   string
+	Action    string
+	Request   interface{}
+	Status    string
+	Error     string `json:",omitempty"`
+}
+
+//AuditLogger represents an audit stream of fully expanded requests, one JSON record per line
+type AuditLogger struct {
+	mutex   *sync.Mutex
+	file    *os.File
+	secrets *endly.SecretValues
+}
+
+//Record writes supplied action audit record
+func (l *AuditLogger) Record(record *AuditRecord) error {
+	if l == nil {
+		return nil
+	}
+	if record.Request != nil {
+		var aMap = map[string]interface{}{}
+		if err := toolbox.DefaultConverter.AssignConverted(&aMap, record.Request); err == nil {
+			record.Request = toolbox.DeleteEmptyKeys(aMap)
+		}
+		record.Request = l.secrets.MaskValue(record.Request)
+	}
+	record.Error = l.secrets.Mask(record.Error)
+	buf, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	_, err = l.file.Write(append(buf, '\n'))
+	return err
+}
+
+//Close closes audit stream
+func (l *AuditLogger) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.file.Close()
+}
+
+//NewAuditLogger creates a new audit logger appending to supplied file, secret values are masked before record is written
+func NewAuditLogger(filename string, secrets *endly.SecretValues) (*AuditLogger, error) {
+	if parent, _ := path.Split(filename); parent != "" && !toolbox.FileExists(parent) {
+		if err := os.MkdirAll(parent, 0744); err != nil {
+			return nil, err
+		}
+	}
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %v, %v", filename, err)
+	}
+	return &AuditLogger{mutex: &sync.Mutex{}, file: file, secrets: secrets}, nil
+}
+
+//Auditor returns context audit logger or nil
+func Auditor(context *endly.Context) *AuditLogger {
+	if !context.Contains(auditLoggerKey) {
+		return nil
+	}
+	var result *AuditLogger
+	context.GetInto(auditLoggerKey, &result)
+	return result
+}
+
+func (s *Service) enableAuditIfNeeded(context *endly.Context, request *RunRequest) error {
+	if request.AuditLog == "" || Auditor(context) != nil {
+		return nil
+	}
+	auditor, err := NewAuditLogger(request.AuditLog, context.SecretValues())
+	if err != nil {
+		return err
+	}
+	context.Deffer(func() {
+		_ = auditor.Close()
+	})
+	return context.Put(auditLoggerKey, auditor)
+}
+
+func (s *Service) auditAction(context *endly.Context, activity *model.Activity, request interface{}, err error) {
+	auditor := Auditor(context)
+	if auditor == nil || activity == nil {
+		return
+	}
+	var record = &AuditRecord{
+		Timestamp: time.Now(),
+		SessionID: context.SessionID,
+		TagID:     activity.TagID,
+		Service:   activity.Service,
+		Action:    activity.Action,
+		Request:   request,
+		Status:    "ok",
+	}
+	if request == nil {
+		record.Request = activity.Request
+	}
+	if err != nil {
+		record.Status = "error"
+		record.Error = err.Error()
+	}
+	if e := auditor.Record(record); e != nil {
+		context.Publish(msg.NewErrorEvent(fmt.Sprintf("failed to write audit record: %v", e)))
+	}
+}
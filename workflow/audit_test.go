@@ -0,0 +1,109 @@
+package workflow
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func readAuditRecords(t *testing.T, filename string) []*AuditRecord {
+	content, err := ioutil.ReadFile(filename)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	var result = make([]*AuditRecord, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var record = &AuditRecord{}
+		if assert.Nil(t, json.Unmarshal([]byte(line), record)) {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+func TestAuditLogger_Record(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "audit")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(tempDir)
+	secrets := endly.NewSecretValues()
+	secrets.Add("s3cr3t&<pass>", `q"uo\te`)
+	filename := path.Join(tempDir, "audit.log")
+	auditor, err := NewAuditLogger(filename, secrets)
+	if !assert.Nil(t, err) {
+		return
+	}
+	err = auditor.Record(&AuditRecord{
+		TagID:   "t1",
+		Service: "exec",
+		Action:  "run",
+		Request: map[string]interface{}{
+			"Commands": []interface{}{"mysql -p s3cr3t&<pass>"},
+			"Env":      map[string]interface{}{"DB_PASSWORD": "dev", "DB_HOST": "127.0.0.1"},
+		},
+		Status: "error",
+		Error:  `access denied for s3cr3t&<pass>, q"uo\te`,
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, auditor.Close())
+
+	content, _ := ioutil.ReadFile(filename)
+	assert.False(t, strings.Contains(string(content), "s3cr3t"))
+	assert.False(t, strings.Contains(string(content), "uo"))
+	records := readAuditRecords(t, filename)
+	if !assert.Len(t, records, 1) {
+		return
+	}
+	assert.EqualValues(t, map[string]interface{}{
+		"Commands": []interface{}{"mysql -p *****"},
+		"Env":      map[string]interface{}{"DB_PASSWORD": endly.MaskedValue, "DB_HOST": "127.0.0.1"},
+	}, records[0].Request)
+	assert.Equal(t, "access denied for *****, *****", records[0].Error)
+}
+
+func TestService_AuditAction(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "audit")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(tempDir)
+	manager := endly.New()
+	service := New().(*Service)
+	context := manager.NewContext(nil)
+	context.SecretValues().Add("s3cr3t&<pass>")
+	var tasks = []*model.Task{
+		newTestTask("t1", "", "print", &PrintRequest{Message: "token: s3cr3t&<pass>"}),
+		newTestTask("t2", "", "fail", &FailRequest{Message: "test error"}),
+	}
+	for _, task := range tasks {
+		task.Actions[0].TagID = task.Name
+	}
+	filename := path.Join(tempDir, "audit.log")
+	request := &RunRequest{AuditLog: filename, SharedState: true, Tasks: "*", workflow: &model.Workflow{
+		Source:       url.NewResource("audit.yaml"),
+		AbstractNode: &model.AbstractNode{Name: "audit"},
+		TasksNode:    &model.TasksNode{Tasks: tasks},
+	}}
+	_, err = service.runWorkflow(context, request)
+	assert.NotNil(t, err)
+	context.Close()
+
+	records := readAuditRecords(t, filename)
+	if !assert.Len(t, records, 2) {
+		return
+	}
+	assert.Equal(t, "t1", records[0].TagID)
+	assert.Equal(t, "ok", records[0].Status)
+	assert.EqualValues(t, map[string]interface{}{"Message": "token: *****"}, records[0].Request)
+	assert.Equal(t, "t2", records[1].TagID)
+	assert.Equal(t, "error", records[1].Status)
+	assert.Contains(t, records[1].Error, "test error")
+}
@@ -0,0 +1,123 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"testing"
+)
+
+func TestService_RunTasks_Branching(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+
+	var withBranch = func(task *model.Task, onSuccess, onError string, taskSwitch *model.TaskSwitch) *model.Task {
+		task.OnSuccess = onSuccess
+		task.OnError = onError
+		task.Switch = taskSwitch
+		return task
+	}
+	var envSwitch = &model.TaskSwitch{
+		SourceKey: "env",
+		Cases:     []*model.TaskSwitchCase{{Value: "prod", Task: "t3"}},
+		Default:   "t2",
+	}
+	var useCases = []struct {
+		description string
+		state       map[string]interface{}
+		tasks       []*model.Task
+		completed   []string
+		skipped     []string
+		hasError    bool
+	}{
+		{
+			description: "linear execution",
+			tasks: []*model.Task{
+				newTestTask("t1", "", "nop", &NopRequest{}),
+				newTestTask("t2", "", "nop", &NopRequest{}),
+			},
+			completed: []string{"t1", "t2"},
+		},
+		{
+			description: "on success jump",
+			tasks: []*model.Task{
+				withBranch(newTestTask("t1", "", "nop", &NopRequest{}), "t3", "", nil),
+				newTestTask("t2", "", "nop", &NopRequest{}),
+				newTestTask("t3", "", "nop", &NopRequest{}),
+			},
+			completed: []string{"t1", "t3"},
+			skipped:   []string{"t2"},
+		},
+		{
+			description: "on error jump",
+			tasks: []*model.Task{
+				withBranch(newTestTask("t1", "", "fail", &FailRequest{Message: "test error"}), "t2", "cleanup", nil),
+				newTestTask("t2", "", "nop", &NopRequest{}),
+				newTestTask("cleanup", "", "nop", &NopRequest{}),
+			},
+			completed: []string{"cleanup"},
+			skipped:   []string{"t1", "t2"},
+		},
+		{
+			description: "switch matched case",
+			state:       map[string]interface{}{"env": "prod"},
+			tasks: []*model.Task{
+				withBranch(newTestTask("t1", "", "nop", &NopRequest{}), "", "", envSwitch),
+				newTestTask("t2", "", "nop", &NopRequest{}),
+				newTestTask("t3", "", "nop", &NopRequest{}),
+			},
+			completed: []string{"t1", "t3"},
+			skipped:   []string{"t2"},
+		},
+		{
+			description: "switch default",
+			state:       map[string]interface{}{"env": "dev"},
+			tasks: []*model.Task{
+				withBranch(newTestTask("t1", "", "nop", &NopRequest{}), "", "", envSwitch),
+				withBranch(newTestTask("t2", "", "nop", &NopRequest{}), "t4", "", nil),
+				newTestTask("t3", "", "nop", &NopRequest{}),
+				newTestTask("t4", "", "nop", &NopRequest{}),
+			},
+			completed: []string{"t1", "t2", "t4"},
+			skipped:   []string{"t3"},
+		},
+		{
+			description: "infinite loop guard",
+			tasks: []*model.Task{
+				withBranch(newTestTask("t1", "", "nop", &NopRequest{}), "t1", "", nil),
+			},
+			hasError: true,
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		for k, v := range useCase.state {
+			context.SetValue(k, v)
+		}
+		workflow := &model.Workflow{AbstractNode: &model.AbstractNode{Name: "test"}, TasksNode: &model.TasksNode{Tasks: useCase.tasks}}
+		assert.Nil(t, workflow.Validate(), useCase.description)
+		process := model.NewProcess(nil, workflow, nil)
+		process.State = context.State()
+		err := service.runTasks(context, process, workflow.TasksNode)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		for _, name := range useCase.completed {
+			assert.True(t, process.Completed[name], useCase.description+" "+name)
+		}
+		for _, name := range useCase.skipped {
+			assert.False(t, process.Completed[name], useCase.description+" "+name)
+		}
+	}
+}
+
+func TestWorkflow_Validate_Branching(t *testing.T) {
+	var task = newTestTask("t1", "", "nop", &NopRequest{})
+	task.OnError = "missing"
+	workflow := &model.Workflow{AbstractNode: &model.AbstractNode{Name: "test"}, TasksNode: &model.TasksNode{Tasks: []*model.Task{task}}}
+	assert.NotNil(t, workflow.Validate())
+}
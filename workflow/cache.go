@@ -0,0 +1,188 @@
+package workflow
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/afs"
+	aurl "github.com/viant/afs/url"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"path"
+	"time"
+)
+
+const defaultCacheDirectory = "cache"
+
+var actionCacheKey = (*actionCache)(nil)
+
+//CacheEntry represents cached action response
+type CacheEntry struct {
+	Key      string
+	TagID    string
+	Service  string
+	Action   string
+	Created  time.Time
+	Response map[string]interface{}
+}
+
+//CacheEvent represents action response cache hit or store event
+type CacheEvent struct {
+	TagID   string
+	Service string
+	Action  string
+	Key     string
+	URL     string
+	Hit     bool
+	Created time.Time
+}
+
+//Messages returns messages
+func (e *CacheEvent) Messages() []*msg.Message {
+	var info = fmt.Sprintf("stored %v", e.URL)
+	if e.Hit {
+		info = fmt.Sprintf("skipped, using response cached at %v: %v", e.Created.Format(time.RFC3339), e.URL)
+	}
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("%v.%v", e.Service, e.Action), msg.MessageStyleGeneric),
+			msg.NewStyled("cache", msg.MessageStyleGeneric),
+			msg.NewStyled(info, msg.MessageStyleOutput),
+		),
+	}
+}
+
+//actionCache represents action response cache shared by workflow and its sub workflows
+type actionCache struct {
+	baseURL string
+	fs      afs.Service
+	refresh bool
+}
+
+//cacheKey returns action response cache key, it is expanded request hash
+func cacheKey(activity *model.Activity, request interface{}) (string, error) {
+	var aMap = map[string]interface{}{}
+	if err := toolbox.DefaultConverter.AssignConverted(&aMap, request); err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(map[string]interface{}{
+		"Service": activity.Service,
+		"Action":  activity.Action,
+		"Request": aMap,
+	})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(encoded)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func (c *actionCache) entryURL(key string) string {
+	return aurl.Join(c.baseURL, key+".json")
+}
+
+//get returns cached response, or nil if response was not cached, has expired or refresh was requested
+func (c *actionCache) get(context *endly.Context, key string, policy *model.ActionCache) (*CacheEntry, error) {
+	if c.refresh {
+		return nil, nil
+	}
+	URL := c.entryURL(key)
+	ctx := context.Background()
+	if exists, _ := c.fs.Exists(ctx, URL); !exists {
+		return nil, nil
+	}
+	reader, err := c.fs.OpenURL(ctx, URL)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var entry = &CacheEntry{}
+	if err = json.Unmarshal(content, entry); err != nil {
+		return nil, fmt.Errorf("failed to decode cache entry %v, %v", URL, err)
+	}
+	if entry.Key != key {
+		return nil, nil
+	}
+	if policy.TTLMs > 0 && time.Since(entry.Created) > time.Duration(policy.TTLMs)*time.Millisecond {
+		return nil, nil
+	}
+	return entry, nil
+}
+
+func (c *actionCache) put(context *endly.Context, entry *CacheEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return c.fs.Upload(context.Background(), c.entryURL(entry.Key), 0644, bytes.NewReader(content))
+}
+
+func actionCacheFor(context *endly.Context) *actionCache {
+	if !context.Contains(actionCacheKey) {
+		return nil
+	}
+	var result *actionCache
+	context.GetInto(actionCacheKey, &result)
+	return result
+}
+
+//enableCacheIfNeeded sets action response cache location, cache dir in logging directory is used by default,
+//cloud storage cache location uses ambient credentials
+func (s *Service) enableCacheIfNeeded(context *endly.Context, request *RunRequest) error {
+	if actionCacheFor(context) != nil {
+		return nil
+	}
+	baseURL := context.Expand(request.CacheURL)
+	if baseURL == "" {
+		logDirectory := request.LogDirectory
+		if logDirectory == "" {
+			logDirectory = defaultLogDirectory
+		}
+		baseURL = path.Join(logDirectory, defaultCacheDirectory)
+	}
+	return context.Put(actionCacheKey, &actionCache{baseURL: url.NewResource(baseURL).URL, fs: afs.New(), refresh: request.NoCache})
+}
+
+//runCached returns cached action response if available, otherwise it runs action and caches its response
+func (s *Service) runCached(context *endly.Context, action *model.Action, activity *model.Activity, request interface{}, run func() (map[string]interface{}, error)) (map[string]interface{}, error) {
+	cache := actionCacheFor(context)
+	if action.Cache == nil || cache == nil {
+		return run()
+	}
+	key, err := cacheKey(activity, request)
+	if err != nil {
+		context.Publish(msg.NewOutputEvent(fmt.Sprintf("%v response is not cacheable: %v", activity.TagID, err), "warning", nil))
+		return run()
+	}
+	var event = &CacheEvent{TagID: activity.TagID, Service: activity.Service, Action: activity.Action, Key: key, URL: cache.entryURL(key)}
+	entry, err := cache.get(context, key, action.Cache)
+	if err != nil {
+		context.Publish(msg.NewOutputEvent(fmt.Sprintf("failed to read %v cached response: %v", activity.TagID, err), "warning", nil))
+	}
+	if entry != nil {
+		event.Hit, event.Created = true, entry.Created
+		context.Publish(event)
+		return entry.Response, nil
+	}
+	response, err := run()
+	if err != nil {
+		return response, err
+	}
+	entry = &CacheEntry{Key: key, TagID: activity.TagID, Service: activity.Service, Action: activity.Action, Created: time.Now(), Response: response}
+	if err := cache.put(context, entry); err != nil {
+		context.Publish(msg.NewOutputEvent(fmt.Sprintf("failed to cache %v response: %v", activity.TagID, err), "warning", nil))
+		return response, nil
+	}
+	event.Created = entry.Created
+	context.Publish(event)
+	return response, nil
+}
@@ -0,0 +1,63 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"testing"
+	"time"
+)
+
+func TestService_RunCached(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+	var count = 0
+	run := func() (map[string]interface{}, error) {
+		count++
+		return map[string]interface{}{"Path": "/tmp/jdk", "Count": count}, nil
+	}
+	activity := &model.Activity{Service: "storage", Action: "copy", MetaTag: &model.MetaTag{TagID: "jdk"}}
+	request := map[string]interface{}{"Dest": "/tmp/jdk"}
+
+	context := manager.NewContext(nil)
+	var hits = 0
+	context.SetListener(func(event msg.Event) {
+		if cacheEvent, ok := event.Value().(*CacheEvent); ok && cacheEvent.Hit {
+			hits++
+		}
+	})
+	if !assert.Nil(t, service.enableCacheIfNeeded(context, &RunRequest{CacheURL: "mem://localhost/cache"})) {
+		return
+	}
+	action := &model.Action{Cache: &model.ActionCache{}}
+
+	response, err := service.runCached(context, action, activity, request, run)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	response, err = service.runCached(context, action, activity, request, run)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 1, hits)
+	assert.EqualValues(t, "/tmp/jdk", response["Path"])
+
+	_, _ = service.runCached(context, action, activity, map[string]interface{}{"Dest": "/tmp/jdk17"}, run)
+	assert.Equal(t, 2, count, "changed request")
+
+	_, _ = service.runCached(context, &model.Action{}, activity, request, run)
+	assert.Equal(t, 3, count, "action without cache")
+
+	time.Sleep(5 * time.Millisecond)
+	_, _ = service.runCached(context, &model.Action{Cache: &model.ActionCache{TTLMs: 1}}, activity, request, run)
+	assert.Equal(t, 4, count, "expired response")
+
+	refreshContext := manager.NewContext(nil)
+	if !assert.Nil(t, service.enableCacheIfNeeded(refreshContext, &RunRequest{CacheURL: "mem://localhost/cache", NoCache: true})) {
+		return
+	}
+	_, _ = service.runCached(refreshContext, action, activity, request, run)
+	assert.Equal(t, 5, count, "no cache")
+	response, _ = service.runCached(context, action, activity, request, run)
+	assert.Equal(t, 5, count)
+	assert.EqualValues(t, 5, response["Count"], "refreshed response")
+}
@@ -0,0 +1,273 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//CheckpointSuffix represents workflow checkpoint file suffix
+	CheckpointSuffix    = ".checkpoint.json"
+	defaultLogDirectory = "logs"
+)
+
+var checkpointTrackerKey = (*checkpointTracker)(nil)
+
+//Checkpoint represents persisted workflow execution progress, used to resume a failed run
+type Checkpoint struct {
+	Workflow string
+	Tasks    []string               //completed tasks
+	TagIDs   []string               //completed actions TagIDs
+	State    map[string]interface{} //state snapshot
+	Error    string                 `json:",omitempty"`
+	Updated  time.Time
+}
+
+//CheckpointFilename returns workflow checkpoint file
+func CheckpointFilename(logDirectory, workflow string) string {
+	if logDirectory == "" {
+		logDirectory = defaultLogDirectory
+	}
+	return path.Join(logDirectory, workflow+CheckpointSuffix)
+}
+
+//LoadCheckpoint loads checkpoint from supplied file, it returns nil if file does not exist
+func LoadCheckpoint(filename string) (*Checkpoint, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var result = &Checkpoint{}
+	if err = json.Unmarshal(content, result); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %v, %v", filename, err)
+	}
+	return result, nil
+}
+
+//ResumeEvent represents resumed workflow event
+type ResumeEvent struct {
+	Workflow string
+	Tasks    []string
+	TagIDs   []string
+}
+
+//Messages returns messages
+func (e *ResumeEvent) Messages() []*msg.Message {
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(e.Workflow, msg.MessageStyleGeneric),
+			msg.NewStyled("resume", msg.MessageStyleGeneric),
+			msg.NewStyled(fmt.Sprintf("skipping completed tasks: %v", strings.Join(e.Tasks, ",")), msg.MessageStyleOutput),
+		),
+	}
+}
+
+//checkpointTracker tracks top level workflow progress
+type checkpointTracker struct {
+	mux        *sync.Mutex
+	filename   string
+	process    *model.Process
+	checkpoint *Checkpoint
+	resumed    *Checkpoint
+	skipTasks  map[string]bool
+	skipTagIDs map[string]bool
+}
+
+//tracks returns true if process or its fork runs tracked workflow
+func (t *checkpointTracker) tracks(process *model.Process) bool {
+	return t != nil && t.process.Workflow == process.Workflow
+}
+
+//skipTask returns true if a task completed by resumed run, the task is skipped once
+func (t *checkpointTracker) skipTask(process *model.Process, task *model.Task) bool {
+	if !t.tracks(process) {
+		return false
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if !t.skipTasks[task.Name] {
+		return false
+	}
+	delete(t.skipTasks, task.Name)
+	t.addTask(task.Name)
+	process.Completed[task.Name] = true
+	return true
+}
+
+//skipAction returns true if an action completed by resumed run
+func (t *checkpointTracker) skipAction(process *model.Process, action *model.Action) bool {
+	if !t.tracks(process) || action.TagID == "" {
+		return false
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if !t.skipTagIDs[action.TagID] {
+		return false
+	}
+	delete(t.skipTagIDs, action.TagID)
+	t.addTagID(action.TagID)
+	return true
+}
+
+func (t *checkpointTracker) addTask(name string) {
+	for _, candidate := range t.checkpoint.Tasks {
+		if candidate == name {
+			return
+		}
+	}
+	t.checkpoint.Tasks = append(t.checkpoint.Tasks, name)
+}
+
+func (t *checkpointTracker) addTagID(tagID string) {
+	for _, candidate := range t.checkpoint.TagIDs {
+		if candidate == tagID {
+			return
+		}
+	}
+	t.checkpoint.TagIDs = append(t.checkpoint.TagIDs, tagID)
+}
+
+//save persists checkpoint with current state snapshot, secret values are excluded
+func (t *checkpointTracker) save(context *endly.Context, err error) error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.checkpoint.Updated = time.Now()
+	t.checkpoint.Error = ""
+	if err != nil {
+		t.checkpoint.Error = err.Error()
+	}
+	var defaults = endly.NewDefaultState(context)
+	var state = make(map[string]interface{})
+	snapshot := context.SafeState().Clone()
+	for key, value := range snapshot.AsEncodableMap() {
+		if defaults.Has(key) || key == selfStateKey || value == "func()" {
+			continue
+		}
+		state[key] = value
+	}
+	//secrets are resolved again by resumed run
+	t.checkpoint.State = context.SecretValues().Redact(state)
+	content, err := json.Marshal(t.checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint %v, %v", t.filename, err)
+	}
+	if err = os.MkdirAll(path.Dir(t.filename), 0744); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.filename, content, 0600)
+}
+
+func checkpointTrackerFor(context *endly.Context) *checkpointTracker {
+	if !context.Contains(checkpointTrackerKey) {
+		return nil
+	}
+	var result *checkpointTracker
+	context.GetInto(checkpointTrackerKey, &result)
+	return result
+}
+
+//enableCheckpointIfNeeded tracks top level workflow progress if logging or resume is enabled, returned function stops tracking
+func (s *Service) enableCheckpointIfNeeded(context *endly.Context, request *RunRequest, process *model.Process) (func(), error) {
+	var release = func() {}
+	if !(request.EnableLogging || request.Resume) || checkpointTrackerFor(context) != nil {
+		return release, nil
+	}
+	var tracker = &checkpointTracker{
+		mux:        &sync.Mutex{},
+		filename:   CheckpointFilename(request.LogDirectory, process.Workflow.Name),
+		process:    process,
+		checkpoint: &Checkpoint{Workflow: process.Workflow.Name},
+		skipTasks:  make(map[string]bool),
+		skipTagIDs: make(map[string]bool),
+	}
+	if request.Resume {
+		resumed, err := LoadCheckpoint(tracker.filename)
+		if err != nil {
+			return release, err
+		}
+		if resumed != nil && resumed.Workflow == process.Workflow.Name {
+			tracker.resumed = resumed
+			for _, task := range resumed.Tasks {
+				tracker.skipTasks[task] = true
+			}
+			for _, tagID := range resumed.TagIDs {
+				tracker.skipTagIDs[tagID] = true
+			}
+		}
+	}
+	release = func() {
+		context.Remove(checkpointTrackerKey)
+	}
+	return release, context.Put(checkpointTrackerKey, tracker)
+}
+
+//restoreCheckpoint restores resumed run state snapshot
+func (s *Service) restoreCheckpoint(context *endly.Context, process *model.Process) {
+	tracker := checkpointTrackerFor(context)
+	if !tracker.tracks(process) || tracker.resumed == nil {
+		return
+	}
+	context.SafeState().Apply(tracker.resumed.State)
+	var tasks = append([]string{}, tracker.resumed.Tasks...)
+	sort.Strings(tasks)
+	context.Publish(&ResumeEvent{Workflow: process.Workflow.Name, Tasks: tasks, TagIDs: tracker.resumed.TagIDs})
+}
+
+//checkpointTask records completed task
+func (s *Service) checkpointTask(context *endly.Context, process *model.Process, task *model.Task) {
+	tracker := checkpointTrackerFor(context)
+	if !tracker.tracks(process) {
+		return
+	}
+	if workflow := process.Workflow; task.Name == workflow.DeferredTask || task.Name == workflow.OnErrorTask {
+		return
+	}
+	tracker.mux.Lock()
+	tracker.addTask(task.Name)
+	tracker.mux.Unlock()
+	if err := tracker.save(context, nil); err != nil {
+		context.Publish(msg.NewErrorEvent(fmt.Sprintf("failed to save checkpoint: %v", err)))
+	}
+}
+
+//checkpointAction records completed action
+func (s *Service) checkpointAction(context *endly.Context, process *model.Process, action *model.Action) {
+	tracker := checkpointTrackerFor(context)
+	if !tracker.tracks(process) || action.TagID == "" {
+		return
+	}
+	tracker.mux.Lock()
+	defer tracker.mux.Unlock()
+	tracker.addTagID(action.TagID)
+}
+
+//completeCheckpoint saves checkpoint of failed workflow or removes it once workflow succeeded
+func (s *Service) completeCheckpoint(context *endly.Context, process *model.Process, err error) {
+	tracker := checkpointTrackerFor(context)
+	if !tracker.tracks(process) {
+		return
+	}
+	if err == nil {
+		if e := os.Remove(tracker.filename); e != nil && !os.IsNotExist(e) {
+			context.Publish(msg.NewErrorEvent(fmt.Sprintf("failed to remove checkpoint: %v", e)))
+		}
+		return
+	}
+	if e := tracker.save(context, err); e != nil {
+		context.Publish(msg.NewErrorEvent(fmt.Sprintf("failed to save checkpoint: %v", e)))
+		return
+	}
+	context.Publish(msg.NewOutputEvent(fmt.Sprintf("saved %v, use resume option to continue", tracker.filename), "checkpoint", nil))
+}
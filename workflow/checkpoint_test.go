@@ -0,0 +1,89 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newCheckpointTestWorkflow(failing bool) *model.Workflow {
+	var tasks = []*model.Task{
+		newTestTask("t1", "", "nop", &NopRequest{}),
+		newTestTask("t2", "", "nop", &NopRequest{}),
+		newTestTask("t3", "", "nop", &NopRequest{}),
+	}
+	var last = newTestTask("t3", "", "nop", &NopRequest{})
+	if failing {
+		last = newTestTask("t3", "", "fail", &FailRequest{Message: "test error"})
+	}
+	tasks[2].Actions = append(tasks[2].Actions, last.Actions...)
+	tasks[1].Init = model.Variables{{Name: "built", Value: "app"}, {Name: "deployKey", Value: "k3y-abc123", Secret: true}}
+	for _, task := range tasks {
+		for i, action := range task.Actions {
+			action.TagID = task.Name + "_" + string(rune('a'+i))
+		}
+	}
+	return &model.Workflow{
+		Source:       url.NewResource("checkpoint.yaml"),
+		AbstractNode: &model.AbstractNode{Name: "checkpoint"},
+		TasksNode:    &model.TasksNode{Tasks: tasks},
+	}
+}
+
+func TestService_RunWorkflow_Resume(t *testing.T) {
+	logDirectory, err := ioutil.TempDir("", "checkpoint")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(logDirectory)
+	manager := endly.New()
+	service := New().(*Service)
+
+	var run = func(workflow *model.Workflow, resume bool) (map[string]bool, *endly.Context, error) {
+		var mux = &sync.Mutex{}
+		var executed = make(map[string]bool)
+		context := manager.NewContext(nil)
+		context.SetListener(func(event msg.Event) {
+			if activity, ok := event.Value().(*model.Activity); ok {
+				mux.Lock()
+				executed[activity.TagID] = true
+				mux.Unlock()
+			}
+		})
+		request := &RunRequest{EnableLogging: true, LogDirectory: logDirectory, Resume: resume, SharedState: true, Tasks: "*", workflow: workflow}
+		_, err := service.runWorkflow(context, request)
+		return executed, context, err
+	}
+
+	executed, _, err := run(newCheckpointTestWorkflow(true), false)
+	assert.NotNil(t, err)
+	assert.Equal(t, map[string]bool{"t1_a": true, "t2_a": true, "t3_a": true, "t3_b": true}, executed)
+
+	filename := CheckpointFilename(logDirectory, "checkpoint")
+	checkpoint, err := LoadCheckpoint(filename)
+	if !assert.Nil(t, err) || !assert.NotNil(t, checkpoint) {
+		return
+	}
+	assert.Equal(t, []string{"t1", "t2"}, checkpoint.Tasks)
+	assert.Equal(t, []string{"t1_a", "t2_a", "t3_a"}, checkpoint.TagIDs)
+	assert.Equal(t, "app", checkpoint.State["built"])
+	_, hasSecret := checkpoint.State["deployKey"]
+	assert.False(t, hasSecret)
+	content, _ := ioutil.ReadFile(filename)
+	assert.False(t, strings.Contains(string(content), "k3y-abc123"))
+	assert.NotEqual(t, "", checkpoint.Error)
+
+	executed, context, err := run(newCheckpointTestWorkflow(false), true)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"t3_b": true}, executed)
+	assert.Equal(t, "app", context.SafeState().Get("built"))
+	_, err = os.Stat(filename)
+	assert.True(t, os.IsNotExist(err))
+}
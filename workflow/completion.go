@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	runSummaryStateKey    = "runSummary"
+	defaultCompletionHook = "notify:send"
+
+	completionWhenAlways  = "always"
+	completionWhenSuccess = "success"
+	completionWhenFailure = "failure"
+)
+
+//CompletionHook represents an action run once top level workflow completes, run summary is available as $runSummary
+type CompletionHook struct {
+	When    string                 `description:"always (default), success or failure"`
+	Action  string                 `description:"service:action selector, notify:send by default"`
+	Request map[string]interface{} `description:"action request, expanded with $runSummary"`
+}
+
+//Init initialises hook
+func (h *CompletionHook) Init() {
+	h.When = strings.ToLower(h.When)
+	if h.When == "" {
+		h.When = completionWhenAlways
+	}
+	if h.Action == "" {
+		h.Action = defaultCompletionHook
+	}
+}
+
+//Validate checks if hook is valid
+func (h *CompletionHook) Validate() error {
+	switch h.When {
+	case completionWhenAlways, completionWhenSuccess, completionWhenFailure:
+		return nil
+	}
+	return fmt.Errorf("unsupported onCompletion when: %v", h.When)
+}
+
+//Matches returns true if hook should run for supplied summary
+func (h *CompletionHook) Matches(summary *RunSummary) bool {
+	switch h.When {
+	case completionWhenSuccess:
+		return summary.Status == completionWhenSuccess
+	case completionWhenFailure:
+		return summary.Status == completionWhenFailure
+	}
+	return true
+}
+
+//RunSummary represents top level workflow run summary passed to completion hooks
+type RunSummary struct {
+	Workflow          string
+	SessionID         string
+	Status            string
+	Error             string `json:",omitempty"`
+	StartTime         time.Time
+	DurationMs        int
+	Passed            int
+	Failed            int
+	FailedValidations []*TagValidation `json:",omitempty"`
+	EventLogURL       string           `json:",omitempty"`
+}
+
+//add adds validation summary counters and failed tags
+func (s *RunSummary) add(summary *ValidationSummary) {
+	if summary == nil {
+		return
+	}
+	s.Passed += summary.Passed
+	s.Failed += summary.Failed
+	for _, tag := range summary.Tags {
+		if tag.Failed > 0 {
+			s.FailedValidations = append(s.FailedValidations, tag)
+		}
+	}
+}
+
+//newRunSummary creates a run summary
+func newRunSummary(context *endly.Context, request *RunRequest, response *RunResponse, err error, started time.Time) *RunSummary {
+	var result = &RunSummary{
+		Workflow:          request.Name,
+		SessionID:         context.SessionID,
+		Status:            completionWhenSuccess,
+		StartTime:         started,
+		DurationMs:        int(time.Since(started) / time.Millisecond),
+		FailedValidations: make([]*TagValidation, 0),
+	}
+	if result.Workflow == "" && request.workflow != nil {
+		result.Workflow = request.workflow.Name
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if response != nil {
+		result.add(response.Summary)
+		for _, matrixResult := range response.Matrix {
+			result.add(matrixResult.Summary)
+		}
+	}
+	if result.Error != "" || result.Failed > 0 {
+		result.Status = completionWhenFailure
+	}
+	if request.EnableLogging {
+		result.EventLogURL = url.NewResource(path.Join(request.LogDirectory, context.SessionID)).URL
+	}
+	return result
+}
+
+//runCompletionHooks runs top level workflow completion hooks, hook failures are reported without failing the run
+func (s *Service) runCompletionHooks(context *endly.Context, request *RunRequest, response *RunResponse, err error, started time.Time) {
+	if len(request.OnCompletion) == 0 {
+		return
+	}
+	summary := newRunSummary(context, request, response, err, started)
+	var summaryMap = map[string]interface{}{}
+	if err := toolbox.DefaultConverter.AssignConverted(&summaryMap, summary); err != nil {
+		context.Publish(msg.NewErrorEvent(fmt.Sprintf("failed to build run summary: %v", err)))
+		return
+	}
+	hookContext := context.Clone()
+	state := hookContext.State()
+	state.Put(runSummaryStateKey, summaryMap)
+	for _, hook := range request.OnCompletion {
+		if !hook.Matches(summary) {
+			continue
+		}
+		selector := model.ActionSelector(hook.Action)
+		hookRequest, err := hookContext.AsRequest(selector.Service(), selector.Action(), hook.Request)
+		if err == nil {
+			err = endly.Run(hookContext, hookRequest, nil)
+		}
+		if err != nil {
+			context.Publish(msg.NewErrorEvent(fmt.Sprintf("onCompletion %v failed: %v", hook.Action, err)))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"testing"
+	"time"
+)
+
+func TestService_RunCompletionHooks(t *testing.T) {
+	service := New().(*Service)
+	var useCases = []struct {
+		description string
+		response    *RunResponse
+		err         error
+		expect      []string
+	}{
+		{
+			description: "success",
+			response:    &RunResponse{Summary: &ValidationSummary{Passed: 3}},
+			expect:      []string{"always success 0", "success"},
+		},
+		{
+			description: "failed validation",
+			response: &RunResponse{Summary: &ValidationSummary{Passed: 2, Failed: 1, Tags: []*TagValidation{
+				{TagID: "Test1", Passed: 2}, {TagID: "Test2", Failed: 1, Failures: []string{"/id: expected 1 but had 2"}},
+			}}},
+			expect: []string{"always failure 1", "failure Test2"},
+		},
+		{
+			description: "failed matrix run",
+			response:    &RunResponse{Matrix: []*MatrixResult{{Summary: &ValidationSummary{Failed: 2, Tags: []*TagValidation{{TagID: "Test1", Failed: 2}}}}}},
+			err:         errors.New("1 of 1 matrix runs failed"),
+			expect:      []string{"always failure 2", "failure Test1"},
+		},
+	}
+	for _, useCase := range useCases {
+		request := &RunRequest{Name: "e2e", OnCompletion: []*CompletionHook{
+			{Action: "workflow:print", Request: map[string]interface{}{"Message": "always $runSummary.Status $runSummary.Failed"}},
+			{When: "success", Action: "workflow:print", Request: map[string]interface{}{"Message": "success"}},
+			{When: "failure", Action: "workflow:print", Request: map[string]interface{}{"Message": "failure $runSummary.FailedValidations[0].TagID"}},
+		}}
+		for _, hook := range request.OnCompletion {
+			hook.Init()
+			assert.Nil(t, hook.Validate(), useCase.description)
+		}
+		context := endly.New().NewContext(nil)
+		var printed = make([]string, 0)
+		context.SetListener(func(event msg.Event) {
+			if printRequest, ok := event.Value().(*PrintRequest); ok {
+				printed = append(printed, printRequest.Message)
+			}
+		})
+		service.runCompletionHooks(context, request, useCase.response, useCase.err, time.Now())
+		assert.Equal(t, useCase.expect, printed, useCase.description)
+		state := context.State()
+		assert.False(t, state.Has(runSummaryStateKey), useCase.description)
+	}
+
+	hook := &CompletionHook{When: "never"}
+	hook.Init()
+	assert.NotNil(t, hook.Validate())
+	assert.Equal(t, defaultCompletionHook, hook.Action)
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/viant/endly/model"
 	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/model/ratelimit"
+	"github.com/viant/endly/model/tracing"
 	"github.com/viant/endly/util"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/url"
@@ -17,11 +19,36 @@ import (
 type RunRequest struct {
 	EnableLogging     bool                   `description:"flag to enable logging"`
 	LogDirectory      string                 `description:"log directory"`
+	LogRetention      *LogRetention          `description:"optional per session log directories retention policy"`
+	LogRotation       *LogRotation           `description:"optional session event log size based rotation and compression policy"`
+	Tracing           *tracing.Config        `description:"optional OpenTelemetry tracing config, workflow, task and action spans are exported with OTLP HTTP exporter, OTEL_EXPORTER_OTLP_ENDPOINT env enables tracing too"`
+	RateLimits        []*ratelimit.Limit     `description:"optional service actions rate limits and quota guards shared by all workflow actions, including parallel and matrix runs, throttled actions are queued with backoff"`
+	CacheURL          string                 `description:"optional action response cache URL, i.e. s3://bucket/endly/cache with ambient credentials, logDirectory/cache by default"`
+	NoCache           bool                   `description:"flag to ignore cached action responses, responses are still cached for subsequent runs"`
+	KeepWorkspace     bool                   `description:"flag to keep session workspace directories ($session.workspace) once workflow ends"`
+	LogFormat         string                 `description:"event log format: files (default) - JSON file per event, ndjson - newline delimited JSON events.ndjson with EventLogEntry schema"`
+	AuditLog          string                 `description:"optional audit log file, when specified every executed action expanded request is recorded with its TagID and status"`
+	TimeoutMs         int                    `description:"optional workflow timeout, when exceeded workflow is canceled and fails with timeout error"`
+	Session           string                 `description:"optional persistent session ID, session state and resources are saved at exit and re-attached by subsequent run with the same ID"`
+	StateDiff         bool                   `description:"flag to publish state diff (added/changed/removed keys) at each task boundary"`
+	TraceState        bool                   `description:"flag to publish redacted state snapshots and diff before and after each action that changed state"`
+	Resume            bool                   `description:"flag to resume previously failed run from checkpoint persisted in log directory, completed tasks and actions are skipped"`
+	RerunFailed       string                 `description:"previous run session ID or JSON report file, only failed actions (by TagID) and actions they depend on are run"`
+	StreamAddress     string                 `description:"optional address i.e. :8072, when specified workflow events are streamed as Server-Sent Events on /v1/endly/events"`
+	Debug             bool                   `description:"flag to start workflow paused, use step or continue request to proceed"`
+	Report            string                 `description:"optional coma separated report formats: junit,json, reports are written to log directory once workflow completes"`
+	MetricsFile       string                 `description:"optional file to write workflow run metrics in Prometheus text exposition format"`
+	DryRun            bool                   `description:"flag to validate workflow services, actions, requests and variables without executing any action"`
+	FailFast          bool                   `description:"strict mode flag, any validation failure aborts the run with an error"`
 	FailureCount      int                    `description:"max number of failures CLI reported per validation"`
 	SummaryFormat     string                 `description:"summary format: xml|json|yaml, summary file is not produced if this is empty"`
 	EventFilter       map[string]bool        `description:"optional CLI filter option,key is either package name or package name.request/event prefix "`
 	Async             bool                   `description:"flag to runWorkflow it asynchronously. Do not set it your self runner sets the flag for the first workflow"`
 	Params            map[string]interface{} `description:"workflow parameters, accessibly by paras.[Key], if PublishParameters is set, all parameters are place in context.state"`
+	OnCompletion      []*CompletionHook      `description:"optional actions run once top level workflow completes, i.e. notify:send with run summary available as $runSummary"`
+	Matrix            *Matrix                `description:"optional parameters matrix, workflow runs once per combination merged with params, results are aggregated in response matrix"`
+	Env               string                 `description:"optional environment profile name i.e. dev, staging, prod, selected profile is merged over params"`
+	Profiles          map[string]*Profile    `description:"environment profiles with params, targets and credentials overlays, keyed by profile name"`
 	PublishParameters bool                   `default:"true" description:"flag to publish parameters directly into context state"`
 	SharedState       bool                   `description:"by default workflow uses a separate cloned context copy, if this is flag context will be shared with a caller workflow state"`
 	URL               string                 `description:"workflow URL if workflow is not found in the registry, it is loaded"`
@@ -33,7 +60,8 @@ type RunRequest struct {
 	Tasks             string `required:"true" description:"coma separated task list, if empty or '*' runs all tasks sequentially"` //tasks to runWorkflow with coma separated list or '*', or empty string for all tasks
 	Interactive       bool
 	*model.InlineWorkflow
-	workflow *model.Workflow //inline workflow from pipeline
+	workflow   *model.Workflow //inline workflow from pipeline
+	appliedEnv string          //already applied env profile
 }
 
 //Init initialises request
@@ -47,9 +75,20 @@ func (r *RunRequest) Init() (err error) {
 	if r.Params, err = util.NormalizeMap(r.Params, true); err != nil {
 		return err
 	}
+	if err = r.ApplyProfile(); err != nil {
+		return err
+	}
 	if r.Tasks == "" || r.Tasks == "$tasks" {
 		r.Tasks = "*"
 	}
+	if r.Matrix != nil {
+		if err = r.Matrix.Init(); err != nil {
+			return err
+		}
+	}
+	for _, hook := range r.OnCompletion {
+		hook.Init()
+	}
 
 	if r.InlineWorkflow != nil && (len(r.InlineWorkflow.Pipeline) > 0) {
 		if r.AssetURL == "" {
@@ -91,6 +130,19 @@ func (r *RunRequest) Init() (err error) {
 
 //Validate checks if request is valid
 func (r *RunRequest) Validate() error {
+	if !isValidLogFormat(r.LogFormat) {
+		return fmt.Errorf("unsupported log format: %v, supported: %v, %v", r.LogFormat, LogFormatFiles, LogFormatNDJSON)
+	}
+	if r.Matrix != nil {
+		if err := r.Matrix.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, hook := range r.OnCompletion {
+		if err := hook.Validate(); err != nil {
+			return err
+		}
+	}
 	if r.workflow != nil {
 		return r.workflow.Validate()
 	}
@@ -125,8 +177,12 @@ func NewRunRequestFromURL(URL string) (*RunRequest, error) {
 
 //RunResponse represents workflow runWorkflow response
 type RunResponse struct {
-	Data      map[string]interface{} //  data populated by  .Post variable section.
-	SessionID string                 //session id
+	Data       map[string]interface{} //  data populated by  .Post variable section.
+	SessionID  string                 //session id
+	Validation *ValidateResponse      `json:",omitempty"` //dry run validation result
+	Metrics    *Metrics               `json:",omitempty"` //top level workflow run timing metrics
+	Summary    *ValidationSummary     `json:",omitempty"` //top level workflow run validation summary by TagID
+	Matrix     []*MatrixResult        `json:",omitempty"` //per combination results of matrix run
 }
 
 //RegisterRequest represents workflow register request
@@ -139,6 +195,154 @@ type RegisterResponse struct {
 	Source *url.Resource
 }
 
+// ValidateRequest represents workflow validation request, it checks workflow without executing any action
+type ValidateRequest struct {
+	Source *url.Resource          `required:"true" description:"workflow URL"`
+	Params map[string]interface{} `description:"workflow parameters used to resolve variables"`
+}
+
+//Validate checks if request is valid
+func (r *ValidateRequest) Validate() error {
+	if r.Source == nil {
+		return errors.New("source was empty")
+	}
+	return nil
+}
+
+//AnalyzeRequest represents workflow static analysis request
+type AnalyzeRequest struct {
+	Source *url.Resource          `required:"true" description:"workflow URL"`
+	Params map[string]interface{} `description:"workflow parameters used to resolve variables and task run criteria"`
+}
+
+//Validate checks if request is valid
+func (r *AnalyzeRequest) Validate() error {
+	if r.Source == nil {
+		return errors.New("source was empty")
+	}
+	return nil
+}
+
+// ValidationIssue represents workflow validation issue
+type ValidationIssue struct {
+	Level   string `description:"error or warning"`
+	Task    string `json:",omitempty"`
+	TagID   string `json:",omitempty"`
+	Message string
+}
+
+// ValidateResponse represents workflow validation response
+type ValidateResponse struct {
+	Workflow string
+	Valid    bool `description:"true if no error level issue was found"`
+	Issues   []*ValidationIssue
+}
+
+//Messages returns messages
+func (r *ValidateResponse) Messages() []*msg.Message {
+	var result = make([]*msg.Message, 0)
+	for _, issue := range r.Issues {
+		var style = msg.MessageStyleOutput
+		if issue.Level == ValidationError {
+			style = msg.MessageStyleError
+		}
+		var location = r.Workflow
+		if issue.Task != "" {
+			location += "." + issue.Task
+		}
+		if issue.TagID != "" {
+			location += " " + issue.TagID
+		}
+		result = append(result, msg.NewMessage(msg.NewStyled(location, msg.MessageStyleGeneric),
+			msg.NewStyled(issue.Level, style),
+			msg.NewStyled(issue.Message, style),
+		))
+	}
+	if len(result) == 0 {
+		result = append(result, msg.NewMessage(msg.NewStyled(r.Workflow, msg.MessageStyleGeneric),
+			msg.NewStyled("valid", msg.MessageStyleSuccess),
+		))
+	}
+	return result
+}
+
+// GraphRequest represents workflow graph export request
+type GraphRequest struct {
+	Source   *url.Resource          `required:"true" description:"workflow URL"`
+	Format   string                 `description:"graph format: dot (default) or mermaid"`
+	MaxDepth int                    `description:"max referenced sub-workflow depth, default 3, -1 - sub-workflows are not loaded"`
+	Params   map[string]interface{} `description:"workflow parameters used to resolve sub-workflow references"`
+}
+
+//Init initializes request
+func (r *GraphRequest) Init() error {
+	if r.Format == "" {
+		r.Format = GraphFormatDOT
+	}
+	r.Format = strings.ToLower(r.Format)
+	if r.MaxDepth == 0 {
+		r.MaxDepth = 3
+	}
+	return nil
+}
+
+//Validate checks if request is valid
+func (r *GraphRequest) Validate() error {
+	if r.Source == nil {
+		return errors.New("source was empty")
+	}
+	if r.Format != GraphFormatDOT && r.Format != GraphFormatMermaid {
+		return fmt.Errorf("unsupported graph format: %v, supported: %v, %v", r.Format, GraphFormatDOT, GraphFormatMermaid)
+	}
+	return nil
+}
+
+// GraphResponse represents workflow graph export response
+type GraphResponse struct {
+	Workflow     string
+	Format       string
+	Graph        string   `description:"workflow graph in requested format"`
+	SubWorkflows []string `json:",omitempty" description:"loaded referenced sub-workflows"`
+}
+
+//Messages returns messages
+func (r *GraphResponse) Messages() []*msg.Message {
+	return []*msg.Message{msg.NewMessage(msg.NewStyled(r.Workflow, msg.MessageStyleGeneric),
+		msg.NewStyled(r.Format, msg.MessageStyleGeneric),
+		msg.NewStyled(r.Graph, msg.MessageStyleOutput),
+	)}
+}
+
+// PauseRequest represents request to suspend running workflow before the next action
+type PauseRequest struct {
+	SessionID string `description:"running workflow session ID, if empty all running workflows are paused"`
+}
+
+// StepRequest represents request to run exactly count actions of paused workflow
+type StepRequest struct {
+	SessionID string `description:"running workflow session ID, if empty all running workflows are stepped"`
+	Count     int    `description:"number of actions to run before pausing, default 1"`
+}
+
+//Init initializes request
+func (r *StepRequest) Init() error {
+	if r.Count == 0 {
+		r.Count = 1
+	}
+	return nil
+}
+
+// ContinueRequest represents request to continue paused workflow
+type ContinueRequest struct {
+	SessionID string `description:"running workflow session ID, if empty all running workflows continue"`
+}
+
+// DebugResponse represents pause, step or continue response
+type DebugResponse struct {
+	SessionIDs []string `description:"matched running workflow session IDs"`
+	Paused     bool
+}
+
 // LoadRequest represents workflow load request from the specified source
 type LoadRequest struct {
 	Source *url.Resource
@@ -200,6 +404,35 @@ func (r *SwitchRequest) Validate() error {
 	return nil
 }
 
+//MacroRequest represents macro request, it instantiates named reusable group of actions within the current workflow
+type MacroRequest struct {
+	Name   string                 `description:"macro name, if URL is empty, macro is loaded from <workflow dir>/macro/<name>.yaml"`
+	URL    string                 `description:"macro definition URL, relative URL is resolved with current workflow directory"`
+	Params map[string]interface{} `description:"macro parameters published to the state while macro actions run, i.e. $app"`
+}
+
+//Init initialises request
+func (r *MacroRequest) Init() (err error) {
+	if r.Name == "" && r.URL != "" {
+		r.Name = model.WorkflowSelector(r.URL).Name()
+	}
+	r.Params, err = util.NormalizeMap(r.Params, true)
+	return err
+}
+
+//Validate checks if request is valid
+func (r *MacroRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name was empty")
+	}
+	return nil
+}
+
+//MacroResponse represents macro response
+type MacroResponse struct {
+	Data map[string]interface{} //macro actions results
+}
+
 // GotoRequest represents goto task action, this request will terminate current task execution to switch to specified task
 type GotoRequest struct {
 	Task string
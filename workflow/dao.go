@@ -1,11 +1,15 @@
 package workflow
 
 import (
+	"fmt"
 	"github.com/viant/endly"
 	"github.com/viant/endly/model"
 	"github.com/viant/neatly"
+	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
 	"github.com/viant/toolbox/url"
+	"path"
+	"strings"
 )
 
 var endlyRemoteRepo = "https://raw.githubusercontent.com/viant/endly/master/%v"
@@ -22,9 +26,15 @@ func (d *Dao) Load(context *endly.Context, source *url.Resource) (*model.Workflo
 	if err != nil {
 		return nil, err
 	}
-	result := &model.Workflow{}
-	var state = data.NewMap()
-	err = d.Dao.Load(state, resource, result)
+	var result *model.Workflow
+	switch strings.ToLower(path.Ext(resource.URL)) {
+	case ".yaml", ".yml", ".json":
+		result, err = d.loadDocument(resource)
+	default:
+		result = &model.Workflow{}
+		var state = data.NewMap()
+		err = d.Dao.Load(state, resource, result)
+	}
 	if err == nil {
 		if err = result.Init(); err == nil {
 			err = result.Validate()
@@ -33,6 +43,39 @@ func (d *Dao) Load(context *endly.Context, source *url.Resource) (*model.Workflo
 	return result, err
 }
 
+//loadDocument loads YAML or JSON workflow, either in inline pipeline or in workflow model format, JSON is decoded as YAML to preserve pipeline order
+func (d *Dao) loadDocument(resource *url.Resource) (*model.Workflow, error) {
+	baseURL, URI := toolbox.URLSplit(resource.URL)
+	name := strings.Replace(URI, path.Ext(URI), "", 1)
+	inline := &model.InlineWorkflow{}
+	if err := resource.YAMLDecode(inline); err != nil {
+		return nil, fmt.Errorf("failed to decode workflow: %v, %v", resource.URL, err)
+	}
+	var result *model.Workflow
+	var err error
+	if len(inline.Pipeline) > 0 {
+		if result, err = inline.AsWorkflow(name, baseURL); err != nil {
+			return nil, err
+		}
+	} else {
+		result = &model.Workflow{}
+		if err = resource.YAMLDecode(result); err != nil {
+			return nil, fmt.Errorf("failed to decode workflow: %v, %v", resource.URL, err)
+		}
+	}
+	if result.AbstractNode == nil {
+		result.AbstractNode = &model.AbstractNode{}
+	}
+	if result.TasksNode == nil {
+		result.TasksNode = &model.TasksNode{}
+	}
+	if result.Name == "" {
+		result.Name = name
+	}
+	result.Source = resource
+	return result, nil
+}
+
 //NewRepoResource returns new woorkflow repo resource, it takes context map and resource URI
 func (d *Dao) NewRepoResource(context data.Map, URI string) (*url.Resource, error) {
 	var resource, err = d.Dao.NewRepoResource(context, URI)
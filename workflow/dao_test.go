@@ -0,0 +1,72 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"path"
+	"testing"
+)
+
+func TestDao_Load_Document(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	dao := NewDao()
+	var useCases = []struct {
+		description string
+		URI         string
+		name        string
+		tasks       []string
+		hasInit     bool
+		deferred    string
+	}{
+		{
+			description: "native YAML workflow",
+			URI:         "native.yaml",
+			name:        "native",
+			tasks:       []string{"build", "deploy", "cleanup"},
+			hasInit:     true,
+			deferred:    "cleanup",
+		},
+		{
+			description: "native JSON workflow",
+			URI:         "native.json",
+			name:        "native",
+			tasks:       []string{"build", "deploy"},
+		},
+		{
+			description: "YAML pipeline workflow",
+			URI:         "pipeline.yaml",
+			name:        "pipeline",
+			tasks:       []string{"build", "deploy"},
+			hasInit:     true,
+		},
+		{
+			description: "JSON pipeline workflow",
+			URI:         "pipeline.json",
+			name:        "pipeline",
+			tasks:       []string{"build", "deploy"},
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		workflow, err := dao.Load(context, url.NewResource(path.Join(parent, "test/document", useCase.URI)))
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.name, workflow.Name, useCase.description)
+		var tasks = make([]string, 0)
+		for _, task := range workflow.Tasks {
+			tasks = append(tasks, task.Name)
+			if assert.Equal(t, 1, len(task.Actions), useCase.description) {
+				assert.Equal(t, "workflow", task.Actions[0].Service, useCase.description)
+				assert.Equal(t, "print", task.Actions[0].Action, useCase.description)
+			}
+		}
+		assert.Equal(t, useCase.tasks, tasks, useCase.description)
+		assert.Equal(t, useCase.hasInit, len(workflow.AbstractNode.Init) > 0, useCase.description)
+		assert.Equal(t, useCase.deferred, workflow.DeferredTask, useCase.description)
+		assert.NotNil(t, workflow.Source, useCase.description)
+	}
+}
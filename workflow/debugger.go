@@ -0,0 +1,158 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"sort"
+	"sync"
+)
+
+//PausedEvent represents workflow execution paused before the pending action
+type PausedEvent struct {
+	SessionID string
+	TagID     string
+	Service   string
+	Action    string
+	Request   interface{} //pending action expanded request
+}
+
+//Messages returns messages
+func (e *PausedEvent) Messages() []*msg.Message {
+	request, err := toolbox.AsIndentJSONText(e.Request)
+	if err != nil {
+		request = fmt.Sprintf("%v", e.Request)
+	}
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("%v %v.%v", e.TagID, e.Service, e.Action), msg.MessageStyleGeneric),
+			msg.NewStyled("paused", msg.MessageStyleGeneric),
+			msg.NewStyled(request, msg.MessageStyleInput),
+		),
+	}
+}
+
+//debugSession represents a session debugging state
+type debugSession struct {
+	mux     *sync.Mutex
+	paused  bool
+	steps   int           //actions allowed to run while paused
+	changed chan struct{} //closed once state changes
+}
+
+//proceed returns true if pending action can run, otherwise it returns state change notification channel
+func (s *debugSession) proceed() (bool, chan struct{}) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if !s.paused {
+		return true, nil
+	}
+	if s.steps > 0 {
+		s.steps--
+		return true, nil
+	}
+	return false, s.changed
+}
+
+func (s *debugSession) update(paused bool, steps int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.paused = paused
+	s.steps += steps
+	if !paused {
+		s.steps = 0
+	}
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+//debugger controls running workflows execution with pause, step and continue requests
+type debugger struct {
+	mux      *sync.Mutex
+	sessions map[string]*debugSession
+}
+
+//attach registers top level workflow session, returned function detaches it
+func (d *debugger) attach(sessionID string, paused bool) func() {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if _, has := d.sessions[sessionID]; has {
+		return func() {}
+	}
+	d.sessions[sessionID] = &debugSession{mux: &sync.Mutex{}, paused: paused, changed: make(chan struct{})}
+	return func() {
+		d.mux.Lock()
+		defer d.mux.Unlock()
+		delete(d.sessions, sessionID)
+	}
+}
+
+func (d *debugger) session(sessionID string) *debugSession {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.sessions[sessionID]
+}
+
+//matched returns sessions matching session ID, or all sessions if session ID is empty
+func (d *debugger) matched(sessionID string) (map[string]*debugSession, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if sessionID != "" {
+		session, ok := d.sessions[sessionID]
+		if !ok {
+			return nil, fmt.Errorf("failed to lookup running session: %v", sessionID)
+		}
+		return map[string]*debugSession{sessionID: session}, nil
+	}
+	var result = make(map[string]*debugSession)
+	for id, session := range d.sessions {
+		result[id] = session
+	}
+	return result, nil
+}
+
+func (d *debugger) update(sessionID string, paused bool, steps int) (*DebugResponse, error) {
+	sessions, err := d.matched(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	var response = &DebugResponse{SessionIDs: make([]string, 0), Paused: paused}
+	for id, session := range sessions {
+		session.update(paused, steps)
+		response.SessionIDs = append(response.SessionIDs, id)
+	}
+	sort.Strings(response.SessionIDs)
+	return response, nil
+}
+
+//wait blocks pending action while session is paused, once paused it publishes PausedEvent with the pending action expanded request
+func (d *debugger) wait(context *endly.Context, activity *model.Activity, request interface{}) error {
+	session := d.session(context.SessionID)
+	if session == nil {
+		return nil
+	}
+	var published bool
+	for {
+		proceed, changed := session.proceed()
+		if proceed {
+			return nil
+		}
+		if !published {
+			published = true
+			context.Publish(&PausedEvent{SessionID: context.SessionID, TagID: activity.TagID, Service: activity.Service, Action: activity.Action, Request: request})
+		}
+		select {
+		case <-changed:
+		case <-context.Done():
+			return context.Err()
+		}
+	}
+}
+
+func newDebugger() *debugger {
+	return &debugger{
+		mux:      &sync.Mutex{},
+		sessions: make(map[string]*debugSession),
+	}
+}
@@ -0,0 +1,103 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox/url"
+	"testing"
+	"time"
+)
+
+func TestService_Debugger(t *testing.T) {
+	manager := endly.New()
+	registered, err := manager.Service(ServiceID)
+	if !assert.Nil(t, err) {
+		return
+	}
+	service := registered.(*Service)
+	var useCases = []struct {
+		description string
+		debug       bool
+		tasks       []*model.Task
+		commands    []interface{} //command issued for each paused event
+		paused      []string
+	}{
+		{
+			description: "start paused, step then continue",
+			debug:       true,
+			tasks: []*model.Task{
+				newTestTask("t1", "", "nop", &NopRequest{}),
+				newTestTask("t2", "", "nop", &NopRequest{}),
+				newTestTask("t3", "", "nop", &NopRequest{}),
+			},
+			commands: []interface{}{&StepRequest{}, &ContinueRequest{}},
+			paused:   []string{"t1", "t2"},
+		},
+		{
+			description: "pause action breakpoint",
+			tasks: []*model.Task{
+				newTestTask("t1", "", "pause", &PauseRequest{}),
+				newTestTask("t2", "", "nop", &NopRequest{}),
+				newTestTask("t3", "", "nop", &NopRequest{}),
+			},
+			commands: []interface{}{&StepRequest{Count: 2}},
+			paused:   []string{"t2"},
+		},
+		{
+			description: "not paused",
+			tasks: []*model.Task{
+				newTestTask("t1", "", "nop", &NopRequest{}),
+			},
+			paused: []string{},
+		},
+	}
+	for _, useCase := range useCases {
+		for _, task := range useCase.tasks {
+			task.Actions[0].TagID = task.Name
+		}
+		var pausedEvents = make(chan *PausedEvent, 10)
+		context := manager.NewContext(nil)
+		context.SetListener(func(event msg.Event) {
+			if paused, ok := event.Value().(*PausedEvent); ok {
+				pausedEvents <- paused
+			}
+		})
+		workflow := &model.Workflow{
+			Source:       url.NewResource("debug.yaml"),
+			AbstractNode: &model.AbstractNode{Name: "debug"},
+			TasksNode:    &model.TasksNode{Tasks: useCase.tasks},
+		}
+		done := make(chan error, 1)
+		go func() {
+			_, err := service.runWorkflow(context, &RunRequest{Debug: useCase.debug, Tasks: "*", workflow: workflow})
+			done <- err
+		}()
+		var paused = make([]string, 0)
+		var commands = useCase.commands
+		var err error
+	loop:
+		for {
+			select {
+			case event := <-pausedEvents:
+				paused = append(paused, event.TagID)
+				assert.Equal(t, context.SessionID, event.SessionID, useCase.description)
+				if len(commands) > 0 {
+					response := service.Run(manager.NewContext(nil), commands[0])
+					assert.Equal(t, "", response.Error, useCase.description)
+					commands = commands[1:]
+				}
+			case err = <-done:
+				break loop
+			case <-time.After(5 * time.Second):
+				assert.Fail(t, "timeout", useCase.description)
+				context.Cancel()
+				break loop
+			}
+		}
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.paused, paused, useCase.description)
+		assert.Equal(t, 0, len(commands), useCase.description)
+	}
+}
@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox/data"
+	"strings"
+	"testing"
+)
+
+func TestService_RunDeferredTask(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+	var useCases = []struct {
+		description string
+		tasks       []*model.Task
+		terminated  bool
+		canceled    bool
+		completed   []string
+		errors      []string
+	}{
+		{
+			description: "deferred after success",
+			tasks: []*model.Task{
+				newTestTask("t1", "", "nop", &NopRequest{}),
+				newTestTask("cleanup", "", "nop", &NopRequest{}),
+			},
+			completed: []string{"t1", "cleanup"},
+		},
+		{
+			description: "deferred after error",
+			tasks: []*model.Task{
+				newTestTask("t1", "", "fail", &FailRequest{Message: "test error"}),
+				newTestTask("cleanup", "", "nop", &NopRequest{}),
+			},
+			completed: []string{"cleanup"},
+			errors:    []string{"test error"},
+		},
+		{
+			description: "deferred after terminated workflow",
+			tasks: []*model.Task{
+				newTestTask("t1", "", "nop", &NopRequest{}),
+				newTestTask("cleanup", "", "nop", &NopRequest{}),
+			},
+			terminated: true,
+			completed:  []string{"cleanup"},
+		},
+		{
+			description: "deferred after canceled context",
+			tasks: []*model.Task{
+				newTestTask("t1", "", "nop", &NopRequest{}),
+				newTestTask("cleanup", "", "nop", &NopRequest{}),
+			},
+			canceled:  true,
+			completed: []string{"cleanup"},
+			errors:    []string{"context canceled"},
+		},
+		{
+			description: "deferred error reported with task error",
+			tasks: []*model.Task{
+				newTestTask("t1", "", "fail", &FailRequest{Message: "test error"}),
+				newTestTask("cleanup", "", "fail", &FailRequest{Message: "cleanup error"}),
+			},
+			errors: []string{"test error", "cleanup error"},
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		cleanup := useCase.tasks[len(useCase.tasks)-1]
+		cleanup.TimeoutMs = 1000
+		process := model.NewProcess(nil, &model.Workflow{AbstractNode: &model.AbstractNode{Name: "test"}}, nil)
+		process.State = data.NewMap()
+		if useCase.terminated {
+			process.Terminate()
+		}
+		if useCase.canceled {
+			context.Cancel()
+		}
+		err := service.runTasks(context, process, &model.TasksNode{Tasks: useCase.tasks, DeferredTask: "cleanup"})
+		if len(useCase.errors) > 0 {
+			if assert.NotNil(t, err, useCase.description) {
+				for _, expect := range useCase.errors {
+					assert.True(t, strings.Contains(err.Error(), expect), useCase.description)
+				}
+			}
+		} else {
+			assert.Nil(t, err, useCase.description)
+		}
+		assert.Equal(t, len(useCase.completed), len(process.Completed), useCase.description)
+		for _, name := range useCase.completed {
+			assert.True(t, process.Completed[name], useCase.description+" "+name)
+		}
+		assert.Equal(t, useCase.terminated, process.IsTerminated(), useCase.description)
+		assert.Equal(t, useCase.canceled, context.Err() != nil, useCase.description)
+	}
+}
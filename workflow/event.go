@@ -50,3 +50,13 @@ type AsyncEvent struct {
 func NewAsyncEvent(action *model.Action) *AsyncEvent {
 	return &AsyncEvent{action}
 }
+
+//LogRetentionEvent represents removed session log directories event
+type LogRetentionEvent struct {
+	Removed []string
+}
+
+//NewLogRetentionEvent creates a new LogRetentionEvent.
+func NewLogRetentionEvent(removed []string) *LogRetentionEvent {
+	return &LogRetentionEvent{Removed: removed}
+}
@@ -0,0 +1,84 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"os"
+	"path"
+	"time"
+)
+
+const (
+	//LogFormatFiles represents default event log format: indented JSON file per event organized by tag and activity directories
+	LogFormatFiles = "files"
+	//LogFormatNDJSON represents newline delimited JSON event log format, one EventLogEntry per line
+	LogFormatNDJSON = "ndjson"
+	//EventLogSchemaVersion represents EventLogEntry schema version, incremented only with incompatible changes
+	EventLogSchemaVersion = 1
+	//EventLogFilename represents newline delimited JSON event log file name
+	EventLogFilename = "events.ndjson"
+)
+
+//EventLogEntry represents newline delimited JSON event log record
+type EventLogEntry struct {
+	SchemaVersion int         `json:"schemaVersion"`
+	Timestamp     time.Time   `json:"timestamp"`
+	SessionID     string      `json:"sessionID"`
+	TagID         string      `json:"tagID,omitempty"`
+	Type          string      `json:"type"`
+	Payload       interface{} `json:"payload,omitempty"`
+}
+
+//isValidLogFormat returns true if supplied event log format is supported
+func isValidLogFormat(format string) bool {
+	return format == "" || format == LogFormatFiles || format == LogFormatNDJSON
+}
+
+//eventPayload returns loggable event value with empty keys removed
+func eventPayload(event msg.Event) interface{} {
+	value := event.Value()
+	var aMap = map[string]interface{}{}
+	if err := toolbox.DefaultConverter.AssignConverted(&aMap, value); err == nil {
+		value = toolbox.DeleteEmptyKeys(aMap)
+	}
+	return value
+}
+
+//writeEntry appends event as EventLogEntry line to the session event log file
+func (l *Logger) writeEntry(event msg.Event) {
+	var entry = &EventLogEntry{
+		SchemaVersion: EventLogSchemaVersion,
+		Timestamp:     event.Timestamp().UTC(),
+		SessionID:     l.sessionID,
+		Type:          event.Type(),
+		Payload:       eventPayload(event),
+	}
+	if l.Len() > 0 {
+		entry.TagID = l.Last().TagID
+	}
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		l.handlerError(fmt.Errorf("failed to encode %v event: %v", entry.Type, err))
+		return
+	}
+	if l.mask != nil {
+		buf = []byte(l.mask(string(buf)))
+	}
+	if !toolbox.FileExists(l.directory) {
+		if err = os.MkdirAll(l.directory, 0744); err != nil {
+			l.handlerError(err)
+			return
+		}
+	}
+	file, err := os.OpenFile(path.Join(l.directory, EventLogFilename), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		l.handlerError(err)
+		return
+	}
+	defer func() { _ = file.Close() }()
+	buf = append(buf, '\n')
+	_, _ = file.Write(buf)
+	l.rotateIfNeeded(int64(len(buf)))
+}
@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestLogger_NDJSON(t *testing.T) {
+	directory, err := ioutil.TempDir("", "endly_event_log")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(directory) }()
+	logger := NewLogger(directory, nil)
+	logger.format = LogFormatNDJSON
+	logger.sessionID = "session1"
+	logger.mask = func(text string) string {
+		return strings.Replace(text, "secret", "***", -1)
+	}
+	logger.OnEvent(msg.NewEvent(&model.Activity{MetaTag: &model.MetaTag{TagID: "build"}, Service: "exec", Action: "run"}))
+	logger.OnEvent(msg.NewEvent(msg.NewOutputEvent("password: secret", "stdout", nil)))
+	logger.OnEvent(msg.NewEvent(model.NewActivityEndEvent(&model.Activity{})))
+
+	files, _ := ioutil.ReadDir(directory)
+	if !assert.Equal(t, 1, len(files)) {
+		return
+	}
+	assert.Equal(t, EventLogFilename, files[0].Name())
+	file, err := os.Open(path.Join(directory, EventLogFilename))
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer func() { _ = file.Close() }()
+	var entries = make([]*EventLogEntry, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := &EventLogEntry{}
+		if !assert.Nil(t, json.Unmarshal(scanner.Bytes(), entry), scanner.Text()) {
+			return
+		}
+		entries = append(entries, entry)
+	}
+	if !assert.Equal(t, 3, len(entries)) {
+		return
+	}
+	assert.Equal(t, []string{"model_Activity", "msg_OutputEvent", "model_ActivityEndEvent"}, []string{entries[0].Type, entries[1].Type, entries[2].Type})
+	for _, entry := range entries {
+		assert.Equal(t, EventLogSchemaVersion, entry.SchemaVersion)
+		assert.Equal(t, "session1", entry.SessionID)
+		assert.Equal(t, "build", entry.TagID)
+		assert.False(t, entry.Timestamp.IsZero())
+	}
+	payload, _ := json.Marshal(entries[1].Payload)
+	assert.True(t, strings.Contains(string(payload), "password: ***"), string(payload))
+}
+
+func TestRunRequest_Validate_LogFormat(t *testing.T) {
+	request := &RunRequest{Name: "test", URL: "test.yaml", LogFormat: "xml"}
+	assert.NotNil(t, request.Validate())
+	request.LogFormat = LogFormatNDJSON
+	assert.Nil(t, request.Validate())
+}
+
+func TestLogger_NDJSON_MaskEscapedSecret(t *testing.T) {
+	directory, err := ioutil.TempDir("", "endly_event_log")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(directory) }()
+	var secret = `p"a\ss<w>&rd-ö`
+	secrets := endly.NewSecretValues()
+	secrets.Add(secret)
+	logger := NewLogger(directory, nil)
+	logger.format = LogFormatNDJSON
+	logger.mask = secrets.Mask
+	logger.OnEvent(msg.NewEvent(&model.Activity{MetaTag: &model.MetaTag{TagID: "build"}, Service: "exec", Action: "run"}))
+	logger.OnEvent(msg.NewEvent(msg.NewOutputEvent("mysql -p "+secret, "stdout", nil)))
+
+	content, err := ioutil.ReadFile(path.Join(directory, EventLogFilename))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, strings.Contains(string(content), "mysql -p "+endly.MaskedValue), string(content))
+	assert.False(t, strings.Contains(string(content), `ss<w`), string(content))
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		assert.Nil(t, json.Unmarshal([]byte(line), &EventLogEntry{}), line)
+	}
+}
@@ -0,0 +1,183 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	vcgit "github.com/viant/endly/deployment/vc/git"
+	"github.com/viant/toolbox/url"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	neturl "net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+//GitCacheDirectory represents local directory where git workflow repositories are checked out
+var GitCacheDirectory = path.Join(os.Getenv("HOME"), ".endly", "cache", "git")
+
+var gitMux = &sync.Mutex{}
+
+//GitSource represents a workflow source located in a git repository, i.e.
+//git://github.com/org/repo@v1.2.0/workflow/build.yaml, git+ssh://git@github.com/org/repo@v1.2.0/build.yaml
+//or https://github.com/org/repo.git/workflow/build.yaml?ref=v1.2.0&sha=1a2b3c
+type GitSource struct {
+	Origin      string //repository clone URL
+	Ref         string //tag, branch or full commit hash, remote default branch if empty
+	Revision    string //optional expected commit hash or its prefix
+	Path        string //workflow path within repository
+	Credentials string //optional credentials
+}
+
+//IsGitSourceURL returns true if URL refers to a workflow in a git repository
+func IsGitSourceURL(URL string) bool {
+	index := strings.Index(URL, "://")
+	if index == -1 {
+		return false
+	}
+	scheme := URL[:index]
+	switch {
+	case scheme == "git", strings.HasPrefix(scheme, "git+"):
+		return true
+	case scheme == "http", scheme == "https":
+		return strings.Contains(URL, ".git/")
+	}
+	return false
+}
+
+//NewGitSource creates a git source for supplied URL
+func NewGitSource(URL string) (*GitSource, error) {
+	parsed, err := neturl.Parse(URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid git workflow URL: %v, %v", URL, err)
+	}
+	query := parsed.Query()
+	result := &GitSource{
+		Ref:         query.Get("ref"),
+		Revision:    strings.ToLower(query.Get("sha")),
+		Credentials: query.Get("credentials"),
+	}
+	scheme := parsed.Scheme
+	switch {
+	case scheme == "git":
+		scheme = "https"
+	case strings.HasPrefix(scheme, "git+"):
+		scheme = strings.TrimPrefix(scheme, "git+")
+	}
+	var repoPath = make([]string, 0)
+	var segments = strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i, segment := range segments {
+		if index := strings.Index(segment, "@"); index != -1 {
+			repoPath = append(repoPath, segment[:index])
+			result.Ref = segment[index+1:]
+			result.Path = strings.Join(segments[i+1:], "/")
+			break
+		}
+		repoPath = append(repoPath, segment)
+		if strings.HasSuffix(segment, ".git") {
+			result.Path = strings.Join(segments[i+1:], "/")
+			break
+		}
+	}
+	if result.Path == "" || len(repoPath) == len(segments) {
+		return nil, fmt.Errorf("invalid git workflow URL: %v, expected repo@ref/path or repo.git/path", URL)
+	}
+	origin := &neturl.URL{Scheme: scheme, User: parsed.User, Host: parsed.Host, Path: "/" + strings.Join(repoPath, "/")}
+	result.Origin = origin.String()
+	return result, nil
+}
+
+//cacheDirectory returns local checkout directory
+func (s *GitSource) cacheDirectory() string {
+	origin, _ := neturl.Parse(s.Origin)
+	ref := s.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	repo := strings.TrimSuffix(strings.Trim(origin.Path, "/"), ".git")
+	return path.Join(GitCacheDirectory, origin.Host, repo, strings.Replace(ref, "/", "_", -1))
+}
+
+//isImmutable returns true if ref is a commit hash or a tag present in supplied repository
+func (s *GitSource) isImmutable(repository *git.Repository) bool {
+	if isCommitHash(s.Ref) {
+		return true
+	}
+	if s.Ref == "" {
+		return false
+	}
+	_, err := repository.Reference(plumbing.NewTagReferenceName(s.Ref), false)
+	return err == nil
+}
+
+//resolve returns commit hash for the source ref, tags are preferred over remote branches
+func (s *GitSource) resolve(repository *git.Repository) (*plumbing.Hash, error) {
+	var candidates = []string{"HEAD"}
+	if s.Ref != "" {
+		candidates = []string{"refs/tags/" + s.Ref, "refs/remotes/origin/" + s.Ref, s.Ref}
+	}
+	for _, candidate := range candidates {
+		if hash, err := repository.ResolveRevision(plumbing.Revision(candidate)); err == nil {
+			return hash, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to resolve %v ref: %v", s.Origin, s.Ref)
+}
+
+//Fetch clones or updates local repository copy, checks out the pinned ref, validates revision and returns local workflow resource
+func (s *GitSource) Fetch(context *endly.Context) (*url.Resource, string, error) {
+	gitMux.Lock()
+	defer gitMux.Unlock()
+	var auth, err = s.auth(context)
+	if err != nil {
+		return nil, "", err
+	}
+	directory := s.cacheDirectory()
+	var repository *git.Repository
+	if s.Ref != "" { //default branch checkout is refreshed with every fetch
+		repository, err = git.PlainOpen(directory)
+	}
+	if repository == nil {
+		_ = os.RemoveAll(directory)
+		if repository, err = git.PlainClone(directory, false, &git.CloneOptions{URL: s.Origin, Auth: auth, Tags: git.AllTags}); err != nil {
+			return nil, "", fmt.Errorf("failed to clone %v, %v", s.Origin, err)
+		}
+	} else if !s.isImmutable(repository) {
+		err = repository.Fetch(&git.FetchOptions{RemoteName: "origin", Auth: auth, Tags: git.AllTags, Force: true})
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			return nil, "", fmt.Errorf("failed to fetch %v, %v", s.Origin, err)
+		}
+	}
+	hash, err := s.resolve(repository)
+	if err != nil {
+		return nil, "", err
+	}
+	revision := hash.String()
+	if s.Revision != "" && !strings.HasPrefix(revision, s.Revision) {
+		return nil, "", fmt.Errorf("%v@%v revision mismatch, expected: %v, but had: %v", s.Origin, s.Ref, s.Revision, revision)
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return nil, "", err
+	}
+	if err = worktree.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true}); err != nil {
+		return nil, "", fmt.Errorf("failed to checkout %v@%v, %v", s.Origin, s.Ref, err)
+	}
+	return url.NewResource(path.Join(directory, s.Path)), revision, nil
+}
+
+func (s *GitSource) auth(context *endly.Context) (transport.AuthMethod, error) {
+	if s.Credentials == "" {
+		return nil, nil
+	}
+	return vcgit.GetAuth(context, s.Credentials)
+}
+
+func isCommitHash(ref string) bool {
+	if len(ref) != 40 {
+		return false
+	}
+	return strings.Trim(strings.ToLower(ref), "0123456789abcdef") == ""
+}
@@ -0,0 +1,154 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewGitSource(t *testing.T) {
+	var useCases = []struct {
+		description string
+		URL         string
+		isGit       bool
+		expect      *GitSource
+	}{
+		{
+			description: "git scheme with ref",
+			URL:         "git://github.com/org/repo@v1.2.0/workflow/build.yaml",
+			isGit:       true,
+			expect:      &GitSource{Origin: "https://github.com/org/repo", Ref: "v1.2.0", Path: "workflow/build.yaml"},
+		},
+		{
+			description: "git ssh scheme with user",
+			URL:         "git+ssh://git@github.com/org/repo.git@release/1.2/build.yaml?sha=1A2b&credentials=git",
+			isGit:       true,
+			expect:      &GitSource{Origin: "ssh://git@github.com/org/repo.git", Ref: "release", Path: "1.2/build.yaml", Revision: "1a2b", Credentials: "git"},
+		},
+		{
+			description: "https with ref parameter",
+			URL:         "https://github.com/org/repo.git/workflow/build.yaml?ref=v1.2.0",
+			isGit:       true,
+			expect:      &GitSource{Origin: "https://github.com/org/repo.git", Ref: "v1.2.0", Path: "workflow/build.yaml"},
+		},
+		{
+			description: "plain https",
+			URL:         "https://github.com/org/repo/workflow/build.yaml",
+		},
+		{
+			description: "file",
+			URL:         "file:///tmp/build.yaml",
+		},
+	}
+	for _, useCase := range useCases {
+		assert.Equal(t, useCase.isGit, IsGitSourceURL(useCase.URL), useCase.description)
+		if !useCase.isGit {
+			continue
+		}
+		source, err := NewGitSource(useCase.URL)
+		if assert.Nil(t, err, useCase.description) {
+			assert.Equal(t, useCase.expect, source, useCase.description)
+		}
+	}
+	_, err := NewGitSource("git://github.com/org/repo/build.yaml")
+	assert.NotNil(t, err)
+}
+
+func commitGitTestFile(t *testing.T, worktree *git.Worktree, directory, content string) string {
+	assert.Nil(t, ioutil.WriteFile(path.Join(directory, "git.yaml"), []byte(content), 0644))
+	_, err := worktree.Add("git.yaml")
+	assert.Nil(t, err)
+	hash, err := worktree.Commit("update", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@localhost", When: time.Now()}})
+	assert.Nil(t, err)
+	return hash.String()
+}
+
+func TestGitSource_Fetch(t *testing.T) {
+	baseDirectory, err := ioutil.TempDir("", "endly_git")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(baseDirectory)
+	defer func(directory string) { GitCacheDirectory = directory }(GitCacheDirectory)
+	GitCacheDirectory = path.Join(baseDirectory, "cache")
+
+	repoDirectory := path.Join(baseDirectory, "lib.git")
+	repository, err := git.PlainInit(repoDirectory, false)
+	if !assert.Nil(t, err) {
+		return
+	}
+	worktree, _ := repository.Worktree()
+	workflowTemplate := "Name: git\nTasks:\n  - Name: print\n    Actions:\n      - Service: workflow\n        Action: nop\nPost:\n  - Name: version\n    Value: %v\n"
+	v1 := commitGitTestFile(t, worktree, repoDirectory, strings.Replace(workflowTemplate, "%v", "v1", 1))
+	head, _ := repository.Head()
+	_, err = repository.CreateTag("v1.0.0", head.Hash(), nil)
+	assert.Nil(t, err)
+	v2 := commitGitTestFile(t, worktree, repoDirectory, strings.Replace(workflowTemplate, "%v", "v2", 1))
+
+	var useCases = []struct {
+		description string
+		URL         string
+		revision    string
+		version     string
+		hasError    bool
+	}{
+		{
+			description: "tag",
+			URL:         "git+file://" + repoDirectory + "@v1.0.0/git.yaml?sha=" + v1[:8],
+			revision:    v1,
+			version:     "v1",
+		},
+		{
+			description: "commit",
+			URL:         "git+file://" + repoDirectory + "@" + v2 + "/git.yaml",
+			revision:    v2,
+			version:     "v2",
+		},
+		{
+			description: "default branch",
+			URL:         "git+file://" + repoDirectory + "/git.yaml",
+			revision:    v2,
+			version:     "v2",
+		},
+		{
+			description: "revision mismatch",
+			URL:         "git+file://" + repoDirectory + "@v1.0.0/git.yaml?sha=" + v2[:8],
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		context := endly.New().NewContext(nil) //workflow registry caches workflows by name
+		source, err := NewGitSource(useCase.URL)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		resource, revision, err := source.Fetch(context)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.revision, revision, useCase.description)
+		response := &RunResponse{}
+		err = endly.Run(context, &RunRequest{URL: resource.URL}, response)
+		if assert.Nil(t, err, useCase.description) {
+			assert.Equal(t, useCase.version, response.Data["version"], useCase.description)
+		}
+	}
+
+	context := endly.New().NewContext(nil)
+	response := &RunResponse{}
+	err = endly.Run(context, &RunRequest{URL: "git+file://" + repoDirectory + "@v1.0.0/git.yaml"}, response)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "v1", response.Data["version"])
+	}
+}
@@ -0,0 +1,347 @@
+package workflow
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/util"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"strings"
+)
+
+const (
+	//GraphFormatDOT represents Graphviz DOT graph format
+	GraphFormatDOT = "dot"
+	//GraphFormatMermaid represents Mermaid flowchart graph format
+	GraphFormatMermaid = "mermaid"
+)
+
+const (
+	graphNodeWorkflow = "workflow"
+	graphNodeTask     = "task"
+	graphNodeAction   = "action"
+)
+
+//graphNode represents workflow, task or action graph node
+type graphNode struct {
+	id    string
+	kind  string
+	label []string
+}
+
+//graphEdge represents graph edge, dashed edges represent conditional transitions and sub-workflow calls
+type graphEdge struct {
+	from   string
+	to     string
+	label  string
+	dashed bool
+}
+
+//graphCluster represents a workflow subgraph
+type graphCluster struct {
+	id    string
+	label string
+	nodes []*graphNode
+}
+
+//graphBuilder builds workflow graph, referenced sub-workflows are loaded up to max depth
+type graphBuilder struct {
+	service  *Service
+	context  *endly.Context
+	state    data.Map
+	maxDepth int
+	clusters []*graphCluster
+	edges    []*graphEdge
+	nodes    int
+	loaded   map[string]string //workflow name to its root node id
+	response *GraphResponse
+}
+
+func (b *graphBuilder) addNode(cluster *graphCluster, kind string, label ...string) string {
+	b.nodes++
+	var node = &graphNode{id: fmt.Sprintf("n%v", b.nodes), kind: kind, label: label}
+	cluster.nodes = append(cluster.nodes, node)
+	return node.id
+}
+
+func (b *graphBuilder) addEdge(from, to, label string, dashed bool) {
+	b.edges = append(b.edges, &graphEdge{from: from, to: to, label: label, dashed: dashed})
+}
+
+//addWorkflow adds workflow subgraph, it returns workflow root node id
+func (b *graphBuilder) addWorkflow(workflow *model.Workflow, depth int) string {
+	var cluster = &graphCluster{id: fmt.Sprintf("cluster_%v", len(b.clusters)), label: "workflow: " + workflow.Name}
+	b.clusters = append(b.clusters, cluster)
+	var label = []string{workflow.Name}
+	if workflow.AbstractNode != nil && workflow.When != "" {
+		label = append(label, "when: "+workflow.When)
+	}
+	root := b.addNode(cluster, graphNodeWorkflow, label...)
+	b.loaded[workflow.Name] = root
+	if workflow.TasksNode == nil {
+		return root
+	}
+	var tasks = make(map[string]string)
+	b.addTasks(cluster, root, workflow.TasksNode, tasks, depth)
+	b.addTransitions(workflow.TasksNode, tasks)
+	if id, ok := tasks[workflow.OnErrorTask]; ok {
+		b.addEdge(root, id, "onError", true)
+	}
+	if id, ok := tasks[workflow.DeferredTask]; ok {
+		b.addEdge(root, id, "deferred", true)
+	}
+	return root
+}
+
+//addTasks adds tasks sequence, consecutive tasks sharing the same group start from the same predecessors
+func (b *graphBuilder) addTasks(cluster *graphCluster, parent string, node *model.TasksNode, tasks map[string]string, depth int) {
+	var heads = []string{parent}
+	var tails = []string{parent}
+	var group string
+	for _, task := range node.Tasks {
+		id := b.addNode(cluster, graphNodeTask, taskGraphLabel(task)...)
+		tasks[task.Name] = id
+		if task.Group != "" && task.Group == group {
+			for _, from := range heads {
+				b.addEdge(from, id, "", false)
+			}
+			tails = append(tails, id)
+		} else {
+			for _, from := range tails {
+				b.addEdge(from, id, "", false)
+			}
+			heads = tails
+			tails = []string{id}
+		}
+		group = task.Group
+		if task.TasksNode != nil && len(task.Tasks) > 0 {
+			b.addTasks(cluster, id, task.TasksNode, tasks, depth)
+		}
+		b.addActions(cluster, id, task, depth)
+	}
+}
+
+//addActions adds task actions chain, async actions start from the task node
+func (b *graphBuilder) addActions(cluster *graphCluster, task string, node *model.Task, depth int) {
+	var previous = task
+	for _, action := range node.Actions {
+		id := b.addNode(cluster, graphNodeAction, actionGraphLabel(action)...)
+		if action.Async {
+			b.addEdge(task, id, "async", false)
+		} else {
+			b.addEdge(previous, id, "", false)
+			previous = id
+		}
+		b.addSubWorkflow(id, action, depth)
+	}
+}
+
+//addTransitions adds task OnSuccess, OnError and Switch transitions
+func (b *graphBuilder) addTransitions(node *model.TasksNode, tasks map[string]string) {
+	for _, task := range node.Tasks {
+		from := tasks[task.Name]
+		if id, ok := tasks[task.OnSuccess]; ok {
+			b.addEdge(from, id, "onSuccess", true)
+		}
+		if id, ok := tasks[task.OnError]; ok {
+			b.addEdge(from, id, "onError", true)
+		}
+		if task.Switch != nil {
+			for _, candidate := range task.Switch.Cases {
+				if id, ok := tasks[candidate.Task]; ok {
+					b.addEdge(from, id, fmt.Sprintf("%v == %v", task.Switch.SourceKey, toolbox.AsString(candidate.Value)), true)
+				}
+			}
+			if id, ok := tasks[task.Switch.Default]; ok {
+				b.addEdge(from, id, "default", true)
+			}
+		}
+		if task.TasksNode != nil {
+			b.addTransitions(task.TasksNode, tasks)
+		}
+	}
+}
+
+//addSubWorkflow adds workflow:run action referenced workflow
+func (b *graphBuilder) addSubWorkflow(from string, action *model.Action, depth int) {
+	if action.ServiceRequest == nil || action.Service != ServiceID || action.Action != "run" || depth >= b.maxDepth {
+		return
+	}
+	workflow, err := b.loadSubWorkflow(action)
+	if err != nil {
+		var cluster = b.clusters[len(b.clusters)-1]
+		id := b.addNode(cluster, graphNodeWorkflow, "unresolved workflow", err.Error())
+		b.addEdge(from, id, "run", true)
+		return
+	}
+	root, ok := b.loaded[workflow.Name]
+	if !ok {
+		b.response.SubWorkflows = append(b.response.SubWorkflows, workflow.Name)
+		root = b.addWorkflow(workflow, depth+1)
+	}
+	b.addEdge(from, root, "run", true)
+}
+
+func (b *graphBuilder) loadSubWorkflow(action *model.Action) (*model.Workflow, error) {
+	request, err := util.NormalizeMap(action.Request, true)
+	if err != nil {
+		return nil, err
+	}
+	var runRequest = &RunRequest{}
+	if err = toolbox.DefaultConverter.AssignConverted(runRequest, b.state.Expand(request)); err != nil {
+		return nil, err
+	}
+	if err = runRequest.Init(); err != nil {
+		return nil, err
+	}
+	if runRequest.workflow == nil && strings.Contains(runRequest.URL, "$") {
+		return nil, fmt.Errorf("unresolved workflow URL: %v", runRequest.URL)
+	}
+	return b.service.getWorkflow(b.context, runRequest)
+}
+
+func taskGraphLabel(task *model.Task) []string {
+	var result = []string{task.Name}
+	if task.Group != "" {
+		result = append(result, "group: "+task.Group)
+	}
+	if task.AbstractNode != nil && task.When != "" {
+		result = append(result, "when: "+task.When)
+	}
+	return result
+}
+
+func actionGraphLabel(action *model.Action) []string {
+	var result = make([]string, 0)
+	var name = ""
+	if action.ServiceRequest != nil {
+		name = action.Service + ":" + action.Action
+	}
+	if action.AbstractNode != nil && action.Name != "" && action.Name != action.Action {
+		name = action.Name + " (" + name + ")"
+	}
+	result = append(result, name)
+	if action.AbstractNode != nil && action.When != "" {
+		result = append(result, "when: "+action.When)
+	}
+	if action.Skip != "" {
+		result = append(result, "skip: "+action.Skip)
+	}
+	if action.ForEach != "" {
+		result = append(result, "forEach: "+action.ForEach)
+	}
+	if action.Repeater != nil && action.Repeat > 1 {
+		result = append(result, fmt.Sprintf("repeat: %v", action.Repeat))
+	}
+	return result
+}
+
+//DOT returns graph in Graphviz DOT format
+func (b *graphBuilder) DOT(name string) string {
+	var escape = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace
+	var shapes = map[string]string{
+		graphNodeWorkflow: "shape=doubleoctagon",
+		graphNodeTask:     "shape=box",
+		graphNodeAction:   "shape=box, style=rounded",
+	}
+	var buffer = new(bytes.Buffer)
+	fmt.Fprintf(buffer, "digraph \"%v\" {\n\trankdir=TB;\n", escape(name))
+	for _, cluster := range b.clusters {
+		fmt.Fprintf(buffer, "\tsubgraph %v {\n\t\tlabel=\"%v\";\n", cluster.id, escape(cluster.label))
+		for _, node := range cluster.nodes {
+			var label = make([]string, len(node.label))
+			for i, line := range node.label {
+				label[i] = escape(line)
+			}
+			fmt.Fprintf(buffer, "\t\t%v [label=\"%v\", %v];\n", node.id, strings.Join(label, `\n`), shapes[node.kind])
+		}
+		buffer.WriteString("\t}\n")
+	}
+	for _, edge := range b.edges {
+		var attributes = make([]string, 0)
+		if edge.label != "" {
+			attributes = append(attributes, fmt.Sprintf("label=\"%v\"", escape(edge.label)))
+		}
+		if edge.dashed {
+			attributes = append(attributes, "style=dashed")
+		}
+		var suffix = ""
+		if len(attributes) > 0 {
+			suffix = " [" + strings.Join(attributes, ", ") + "]"
+		}
+		fmt.Fprintf(buffer, "\t%v -> %v%v;\n", edge.from, edge.to, suffix)
+	}
+	buffer.WriteString("}\n")
+	return buffer.String()
+}
+
+//Mermaid returns graph in Mermaid flowchart format
+func (b *graphBuilder) Mermaid() string {
+	var escape = strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace
+	var shapes = map[string][2]string{
+		graphNodeWorkflow: {"{{", "}}"},
+		graphNodeTask:     {"[", "]"},
+		graphNodeAction:   {"(", ")"},
+	}
+	var buffer = new(bytes.Buffer)
+	buffer.WriteString("flowchart TD\n")
+	for _, cluster := range b.clusters {
+		fmt.Fprintf(buffer, "\tsubgraph %v [\"%v\"]\n", cluster.id, escape(cluster.label))
+		for _, node := range cluster.nodes {
+			var label = make([]string, len(node.label))
+			for i, line := range node.label {
+				label[i] = escape(line)
+			}
+			shape := shapes[node.kind]
+			fmt.Fprintf(buffer, "\t\t%v%v\"%v\"%v\n", node.id, shape[0], strings.Join(label, "<br/>"), shape[1])
+		}
+		buffer.WriteString("\tend\n")
+	}
+	for _, edge := range b.edges {
+		switch {
+		case edge.dashed && edge.label != "":
+			fmt.Fprintf(buffer, "\t%v -. \"%v\" .-> %v\n", edge.from, escape(edge.label), edge.to)
+		case edge.dashed:
+			fmt.Fprintf(buffer, "\t%v -.-> %v\n", edge.from, edge.to)
+		case edge.label != "":
+			fmt.Fprintf(buffer, "\t%v -- \"%v\" --> %v\n", edge.from, escape(edge.label), edge.to)
+		default:
+			fmt.Fprintf(buffer, "\t%v --> %v\n", edge.from, edge.to)
+		}
+	}
+	return buffer.String()
+}
+
+func (s *Service) graph(context *endly.Context, request *GraphRequest) (*GraphResponse, error) {
+	workflow, err := s.Dao.Load(context, request.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load workflow: %v, %v", request.Source.URL, err)
+	}
+	params, err := util.NormalizeMap(request.Params, true)
+	if err != nil {
+		return nil, err
+	}
+	var state = context.SafeState().Clone()
+	for key, value := range params {
+		state.Put(key, value)
+	}
+	state.Put(paramsStateKey, params)
+	var response = &GraphResponse{Workflow: workflow.Name, Format: request.Format}
+	var builder = &graphBuilder{
+		service:  s,
+		context:  context,
+		state:    state,
+		maxDepth: request.MaxDepth,
+		loaded:   make(map[string]string),
+		response: response,
+	}
+	builder.addWorkflow(workflow, 0)
+	if request.Format == GraphFormatMermaid {
+		response.Graph = builder.Mermaid()
+	} else {
+		response.Graph = builder.DOT(workflow.Name)
+	}
+	return response, nil
+}
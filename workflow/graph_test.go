@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestService_Graph(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	service := New().(*Service)
+	var useCases = []struct {
+		description  string
+		request      *GraphRequest
+		subWorkflows []string
+		expect       []string
+	}{
+		{
+			description:  "dot graph",
+			request:      &GraphRequest{Format: "DOT"},
+			subWorkflows: []string{"sub"},
+			expect: []string{
+				`digraph "main" {`,
+				`subgraph cluster_0 {`,
+				`label="workflow: main";`,
+				`label="workflow: sub";`,
+				`n1 [label="main", shape=doubleoctagon];`,
+				`[label="compile (workflow:nop)\nwhen: $app:/myapp/", shape=box, style=rounded];`,
+				`[label="test1\ngroup: test", shape=box];`,
+				`[label="sub", shape=doubleoctagon];`,
+				`[label="run", style=dashed];`,
+				`[label="onError", style=dashed];`,
+				`[label="env == prod", style=dashed];`,
+				`[label="default", style=dashed];`,
+				`[label="async"];`,
+				`when: $mode = \"fast\"`,
+			},
+		},
+		{
+			description:  "mermaid graph",
+			request:      &GraphRequest{Format: GraphFormatMermaid},
+			subWorkflows: []string{"sub"},
+			expect: []string{
+				"flowchart TD",
+				`subgraph cluster_0 ["workflow: main"]`,
+				`n1{{"main"}}`,
+				`("workflow:print")`,
+				`-. "run" .->`,
+				`-- "async" -->`,
+				`when: $mode = #quot;fast#quot;`,
+			},
+		},
+		{
+			description: "without sub-workflows",
+			request:     &GraphRequest{MaxDepth: -1},
+			expect:      []string{`digraph "main" {`},
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		useCase.request.Source = url.NewResource(path.Join(parent, "test/graph/main.yaml"))
+		useCase.request.Params = map[string]interface{}{"dir": path.Join(parent, "test/graph")}
+		if !assert.Nil(t, useCase.request.Init(), useCase.description) || !assert.Nil(t, useCase.request.Validate(), useCase.description) {
+			continue
+		}
+		response, err := service.graph(context, useCase.request)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.subWorkflows, response.SubWorkflows, useCase.description)
+		for _, fragment := range useCase.expect {
+			assert.True(t, strings.Contains(response.Graph, fragment), useCase.description+": "+fragment+"\n"+response.Graph)
+		}
+	}
+	invalid := &GraphRequest{Source: url.NewResource("main.yaml"), Format: "svg"}
+	_ = invalid.Init()
+	assert.NotNil(t, invalid.Validate())
+}
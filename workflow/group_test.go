@@ -0,0 +1,141 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestTask(name, group, action string, request interface{}) *model.Task {
+	var result = &model.Task{
+		AbstractNode: &model.AbstractNode{Name: name},
+		Group:        group,
+		Actions: []*model.Action{
+			{
+				AbstractNode:   &model.AbstractNode{Name: name},
+				ServiceRequest: &model.ServiceRequest{Service: ServiceID, Action: action, Request: request},
+			},
+		},
+	}
+	for _, action := range result.Actions {
+		_ = action.Init()
+	}
+	return result
+}
+
+func TestService_RunTaskGroup(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+	var useCases = []struct {
+		description string
+		tasks       []*model.Task
+		completed   []string
+		errorTasks  []string
+	}{
+		{
+			description: "concurrent tasks",
+			tasks: []*model.Task{
+				newTestTask("t1", "g1", "nop", &NopRequest{}),
+				newTestTask("t2", "g1", "nop", &NopRequest{}),
+				newTestTask("t3", "", "nop", &NopRequest{}),
+			},
+			completed: []string{"t1", "t2", "t3"},
+		},
+		{
+			description: "aggregated errors",
+			tasks: []*model.Task{
+				newTestTask("t1", "g1", "fail", &FailRequest{Message: "err1"}),
+				newTestTask("t2", "g1", "nop", &NopRequest{}),
+				newTestTask("t3", "g1", "fail", &FailRequest{Message: "err3"}),
+				newTestTask("t4", "", "nop", &NopRequest{}),
+			},
+			completed:  []string{"t2"},
+			errorTasks: []string{"t1", "t3"},
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		process := model.NewProcess(nil, &model.Workflow{AbstractNode: &model.AbstractNode{Name: "test"}}, nil)
+		process.State = context.State()
+		err := service.runTasks(context, process, &model.TasksNode{Tasks: useCase.tasks})
+		if len(useCase.errorTasks) > 0 {
+			if !assert.NotNil(t, err, useCase.description) {
+				continue
+			}
+			assert.True(t, strings.Contains(err.Error(), "group g1 failed"), useCase.description)
+			for _, name := range useCase.errorTasks {
+				assert.True(t, strings.Contains(err.Error(), name+":"), useCase.description)
+			}
+			assert.False(t, process.Completed["t4"], useCase.description)
+		} else {
+			assert.Nil(t, err, useCase.description)
+		}
+		for _, name := range useCase.completed {
+			assert.True(t, process.Completed[name], useCase.description+" "+name)
+		}
+	}
+}
+
+type taskScopeRequest struct {
+	Name string
+}
+
+func TestService_RunTaskGroup_TaskScopes(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	service, err := context.Service(ServiceID)
+	if !assert.Nil(t, err) {
+		return
+	}
+	var mux = &sync.Mutex{}
+	var ended = make(map[string]int)
+	service.(*Service).AbstractService.Register(&endly.Route{
+		Action: "taskScope",
+		RequestProvider: func() interface{} {
+			return &taskScopeRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return struct{}{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			req := request.(*taskScopeRequest)
+			registered := Last(context).OnTaskEnd(func() {
+				mux.Lock()
+				defer mux.Unlock()
+				ended[req.Name]++
+			})
+			if !registered {
+				return nil, fmt.Errorf("no task scope for %v", req.Name)
+			}
+			Last(context).State.Put("task", req.Name)
+			time.Sleep(10 * time.Millisecond)
+			return req, nil
+		},
+	})
+
+	manager.Register(service)
+
+	var tasks = make([]*model.Task, 0)
+	for _, name := range []string{"t1", "t2"} {
+		task := newTestTask(name, "g1", "taskScope", &taskScopeRequest{Name: name})
+		task.Actions = append(task.Actions, newTestTask(name+"-next", "", "taskScope", &taskScopeRequest{Name: name}).Actions...)
+		tasks = append(tasks, task)
+	}
+	process := model.NewProcess(nil, &model.Workflow{AbstractNode: &model.AbstractNode{Name: "test"}}, nil)
+	process.State = context.State()
+	Push(context, process)
+	defer Pop(context)
+	err = service.(*Service).runTasks(context, process, &model.TasksNode{Tasks: tasks})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]int{"t1": 2, "t2": 2}, ended)
+	assert.False(t, process.OnTaskEnd(func() {}))
+	assert.True(t, process.Completed["t1"])
+	assert.True(t, process.Completed["t2"])
+}
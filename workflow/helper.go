@@ -48,6 +48,16 @@ func Last(context *endly.Context) *model.Process {
 	return processes.Last()
 }
 
+//forkProcess returns async context clone and forked process, the fork is pushed to the clone own process stack and used as its self state
+func forkProcess(context *endly.Context, process *model.Process) (*endly.Context, *model.Process) {
+	fork := process.Fork()
+	forkContext := context.AsyncClone()
+	_ = forkContext.Replace(processesKey, processes(context).Clone())
+	Push(forkContext, fork)
+	forkContext.SafeState().Put(selfStateKey, fork.State)
+	return forkContext, fork
+}
+
 //LastWorkflow returns last workflow
 func LastWorkflow(context *endly.Context) *model.Process {
 	var processes = processes(context)
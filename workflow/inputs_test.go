@@ -0,0 +1,94 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox/url"
+	"strings"
+	"testing"
+)
+
+func newInputsTestWorkflow(outputs model.StateContract) *model.Workflow {
+	return &model.Workflow{
+		Source: url.NewResource("io.yaml"),
+		AbstractNode: &model.AbstractNode{
+			Name: "io",
+			Post: model.Variables{
+				{Name: "app", Value: "$params.app"},
+				{Name: "port", Value: "$params.port"},
+				{Name: "token", Value: "abc"},
+			},
+		},
+		TasksNode: &model.TasksNode{Tasks: []*model.Task{newTestTask("t1", "", "nop", &NopRequest{})}},
+		Inputs: model.StateContract{
+			{Name: "app", Type: "string", Required: true},
+			{Name: "port", Type: "int"},
+		},
+		Outputs: outputs,
+	}
+}
+
+func TestService_RunWorkflow_InputsOutputs(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+	var useCases = []struct {
+		description string
+		params      map[string]interface{}
+		outputs     model.StateContract
+		expect      map[string]interface{}
+		leaked      []string
+		errorPrefix string
+	}{
+		{
+			description: "all post data published without declared outputs",
+			params:      map[string]interface{}{"app": "myapp", "port": "8080"},
+			expect:      map[string]interface{}{"app": "myapp", "port": "8080", "token": "abc"},
+		},
+		{
+			description: "only declared outputs published",
+			params:      map[string]interface{}{"app": "myapp", "port": 8080},
+			outputs:     model.StateContract{{Name: "app", Type: "string", Required: true}},
+			expect:      map[string]interface{}{"app": "myapp"},
+			leaked:      []string{"port", "token"},
+		},
+		{
+			description: "missing required input",
+			params:      map[string]interface{}{"port": 8080},
+			errorPrefix: "invalid io workflow inputs: app was empty",
+		},
+		{
+			description: "invalid input type",
+			params:      map[string]interface{}{"app": "myapp", "port": "abc"},
+			errorPrefix: "invalid io workflow inputs: invalid port",
+		},
+		{
+			description: "missing required output",
+			params:      map[string]interface{}{"app": "myapp"},
+			outputs:     model.StateContract{{Name: "build", Required: true}},
+			errorPrefix: "invalid io workflow outputs: build was empty",
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		request := &RunRequest{Params: useCase.params, Tasks: "*", workflow: newInputsTestWorkflow(useCase.outputs)}
+		response, err := service.runWorkflow(context, request)
+		if useCase.errorPrefix != "" {
+			if assert.NotNil(t, err, useCase.description) {
+				assert.True(t, strings.HasPrefix(err.Error(), useCase.errorPrefix), useCase.description+" "+err.Error())
+			}
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, response.Data, useCase.description)
+		state := context.SafeState()
+		for key, value := range useCase.expect {
+			assert.EqualValues(t, value, state.Get(key), useCase.description)
+		}
+		for _, key := range useCase.leaked {
+			assert.False(t, state.Has(key), useCase.description+" "+key)
+		}
+	}
+}
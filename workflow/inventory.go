@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/inventory"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//requestKey returns actual request map key for supplied case insensitive name
+func requestKey(request map[string]interface{}, name string) (string, bool) {
+	for key := range request {
+		if strings.ToLower(key) == name {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+//expandGroupSources replaces inventory group source with group hosts sources, i.e. for validator/log:listen
+func expandGroupSources(context *endly.Context, request map[string]interface{}) error {
+	key, ok := requestKey(request, "source")
+	if !ok {
+		return nil
+	}
+	group, URIPath, ok := inventory.GroupReference(request[key])
+	if !ok {
+		return nil
+	}
+	hosts, err := inventory.Hosts(context, group)
+	if err != nil {
+		return err
+	}
+	var sources = make([]interface{}, 0)
+	for _, host := range hosts {
+		resource := host.Resource(URIPath)
+		sources = append(sources, map[string]interface{}{"URL": resource.URL, "Credentials": resource.Credentials})
+	}
+	delete(request, key)
+	request["Sources"] = sources
+	return nil
+}
+
+//groupTarget returns request target key, group name and URI path if request targets inventory group
+func groupTarget(request map[string]interface{}) (string, string, string, bool) {
+	key, ok := requestKey(request, "target")
+	if !ok {
+		return "", "", "", false
+	}
+	group, URIPath, ok := inventory.GroupReference(request[key])
+	return key, group, URIPath, ok
+}
+
+//runOnGroup runs activity request on each group host concurrently, $host is expanded with host variables,
+//host responses are aggregated by host name
+func (s *Service) runOnGroup(context *endly.Context, activity *model.Activity, request map[string]interface{}, targetKey, group, URIPath string) (map[string]interface{}, error) {
+	hosts, err := inventory.Hosts(context, group)
+	if err != nil {
+		return nil, err
+	}
+	var mux = &sync.Mutex{}
+	var responses = make(map[string]interface{})
+	var errors = make(map[string]interface{})
+	var waitGroup = &sync.WaitGroup{}
+	waitGroup.Add(len(hosts))
+	for _, host := range hosts {
+		go func(host *inventory.Host, hostContext *endly.Context) {
+			defer waitGroup.Done()
+			response, err := runOnHost(hostContext, activity, request, targetKey, URIPath, host)
+			mux.Lock()
+			defer mux.Unlock()
+			if err != nil {
+				errors[host.Name] = err.Error()
+				return
+			}
+			responses[host.Name] = response
+		}(host, context.AsyncClone())
+	}
+	waitGroup.Wait()
+	var result = map[string]interface{}{
+		"Group": group,
+		"Hosts": responses,
+	}
+	if len(errors) == 0 {
+		return result, nil
+	}
+	result["Errors"] = errors
+	var failed = make([]string, 0)
+	for name, message := range errors {
+		failed = append(failed, fmt.Sprintf("%v: %v", name, message))
+	}
+	sort.Strings(failed)
+	return result, fmt.Errorf("failed on %v of %v %v hosts: %v", len(errors), len(hosts), group, strings.Join(failed, "; "))
+}
+
+func runOnHost(context *endly.Context, activity *model.Activity, request map[string]interface{}, targetKey, URIPath string, host *inventory.Host) (map[string]interface{}, error) {
+	var hostState = data.NewMap()
+	hostState.Put(inventory.HostKey, host.AsMap())
+	hostRequest := toolbox.AsMap(hostState.Expand(request))
+	resource := host.Resource(URIPath)
+	hostRequest[targetKey] = map[string]interface{}{"URL": resource.URL, "Credentials": resource.Credentials}
+	serviceRequest, err := context.AsRequest(activity.Service, activity.Action, hostRequest)
+	if err != nil {
+		return nil, err
+	}
+	var serviceResponse = &endly.ServiceResponse{}
+	if err = endly.Run(context, serviceRequest, serviceResponse); err != nil {
+		return nil, err
+	}
+	var response = make(map[string]interface{})
+	err = toolbox.DefaultConverter.AssignConverted(&response, serviceResponse.Response)
+	return response, err
+}
@@ -0,0 +1,101 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/inventory"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestService_RunOnGroup(t *testing.T) {
+	service := New().(*Service)
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+	_, err := inventory.Load(context, &inventory.Inventory{Hosts: []*inventory.Host{
+		{Name: "web1", URL: "ssh://10.0.0.1:22", Roles: []string{"web"}, Vars: map[string]interface{}{"port": 8080}},
+		{Name: "web2", URL: "ssh://10.0.0.2:22", Roles: []string{"web"}, Vars: map[string]interface{}{"port": 8081}},
+		{Name: "db1", URL: "ssh://10.0.0.3:22", Roles: []string{"db"}},
+	}})
+	if !assert.Nil(t, err) {
+		return
+	}
+	request := map[string]interface{}{
+		"target": "inventory://web",
+		"In":     map[string]interface{}{"name": "${host.Name}", "endpoint": "http://${host.Hostname}:${host.port}"},
+	}
+	targetKey, group, URIPath, ok := groupTarget(request)
+	if !assert.True(t, ok) {
+		return
+	}
+	response, err := service.runOnGroup(context, &model.Activity{Service: "nop", Action: "nop"}, request, targetKey, group, URIPath)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]interface{}{
+		"web1": map[string]interface{}{"name": "web1", "endpoint": "http://10.0.0.1:8080"},
+		"web2": map[string]interface{}{"name": "web2", "endpoint": "http://10.0.0.2:8081"},
+	}, response["Hosts"])
+
+	_, err = service.runOnGroup(context, &model.Activity{Service: "workflow", Action: "fail"}, map[string]interface{}{"target": "inventory://db", "message": "down"}, "target", "db", "")
+	assert.NotNil(t, err)
+
+	logRequest := map[string]interface{}{"Source": map[string]interface{}{"URL": "inventory://web/var/log/app"}}
+	assert.Nil(t, expandGroupSources(context, logRequest))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"URL": "ssh://10.0.0.1:22/var/log/app", "Credentials": ""},
+		map[string]interface{}{"URL": "ssh://10.0.0.2:22/var/log/app", "Credentials": ""},
+	}, logRequest["Sources"])
+}
+
+func TestService_RunAction_OnGroup(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "group")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(tempDir)
+	service := New().(*Service)
+	context := endly.New().NewContext(nil)
+	defer context.Close()
+	_, err = inventory.Load(context, &inventory.Inventory{Hosts: []*inventory.Host{
+		{Name: "app1", URL: "ssh://10.0.0.1:22", Roles: []string{"app"}, Vars: map[string]interface{}{"style": 1}},
+		{Name: "app2", URL: "ssh://10.0.0.2:22", Roles: []string{"app"}, Vars: map[string]interface{}{"style": "bold"}},
+	}})
+	if !assert.Nil(t, err) {
+		return
+	}
+	var activities = make([]*model.Activity, 0)
+	context.SetListener(func(event msg.Event) {
+		if endEvent, ok := event.Value().(*model.ActivityEndEvent); ok {
+			if activity, ok := endEvent.Response.(*model.Activity); ok {
+				activities = append(activities, activity)
+			}
+		}
+	})
+	task := newTestTask("t1", "", "print", map[string]interface{}{"target": "inventory://app", "Message": "${host.Name}", "Style": "${host.style}"})
+	task.Actions[0].TagID = "t1"
+	filename := path.Join(tempDir, "audit.log")
+	_, err = service.runWorkflow(context, &RunRequest{AuditLog: filename, Tasks: "*", workflow: &model.Workflow{
+		Source:       url.NewResource("group.yaml"),
+		AbstractNode: &model.AbstractNode{Name: "group"},
+		TasksNode:    &model.TasksNode{Tasks: []*model.Task{task}},
+	}})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "failed on 1 of 2 app hosts")
+	}
+	if assert.Len(t, activities, 1) {
+		assert.Contains(t, activities[0].Response["Hosts"], "app1")
+		assert.Contains(t, activities[0].Response["Errors"], "app2")
+	}
+	records := readAuditRecords(t, filename)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "error", records[0].Status)
+		assert.EqualValues(t, "inventory://app", toolbox.AsMap(records[0].Request)["target"])
+	}
+}
@@ -25,6 +25,12 @@ type Logger struct {
 	activityPath     string
 	mutex            *sync.Mutex
 	activityEnded    bool
+	mask             func(text string) string
+	rotation         *LogRotation
+	written          int64 //bytes written since the last rotation
+	segments         int
+	format           string
+	sessionID        string
 }
 
 func (l *Logger) processEvent(event msg.Event) {
@@ -91,6 +97,10 @@ func (l *Logger) OnEvent(event msg.Event) {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 	l.processEvent(event)
+	if l.format == LogFormatNDJSON {
+		l.writeEntry(event)
+		return
+	}
 
 	activity := l.Last()
 	activityID := l.normalizeActivityPath(activity)
@@ -126,19 +136,30 @@ func (l *Logger) OnEvent(event msg.Event) {
 		return
 	}
 	defer func() { _ = file.Close() }()
-	value := event.Value()
-
-	var aMap = map[string]interface{}{}
-	if err := toolbox.DefaultConverter.AssignConverted(&aMap, value); err == nil {
-		value = toolbox.DeleteEmptyKeys(aMap)
-	}
-
-	buf, err := json.MarshalIndent(value, "", "\t")
+	buf, err := json.MarshalIndent(eventPayload(event), "", "\t")
 	if err != nil {
 		l.handlerError(err)
 		return
 	}
+	if l.mask != nil {
+		buf = []byte(l.mask(string(buf)))
+	}
 	_, _ = file.Write(buf)
+	l.rotateIfNeeded(int64(len(buf)))
+}
+
+//rotateIfNeeded rolls written event files into a segment once rotation size limit is exceeded
+func (l *Logger) rotateIfNeeded(written int64) {
+	l.written += written
+	limit := l.rotation.segmentLimit()
+	if limit == 0 || l.written < limit {
+		return
+	}
+	l.written = 0
+	l.segments++
+	if _, err := l.rotation.rollSegment(l.directory, l.segments); err != nil {
+		l.handlerError(fmt.Errorf("failed to rotate event log: %v", err))
+	}
 }
 
 //AsEventListener returns an event Listener
@@ -0,0 +1,77 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogger_MaskSecretVariables(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	logDirectory, err := ioutil.TempDir("", "endly_secret")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(logDirectory)
+	context := endly.New().NewContext(nil)
+	err = endly.Run(context, &RunRequest{URL: path.Join(parent, "test/secret/secret.yaml"), EnableLogging: true, LogDirectory: logDirectory}, &RunResponse{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "key: *****", context.MaskSecrets("key: k3y-abc123"))
+
+	var logged = make([]string, 0)
+	err = filepath.Walk(logDirectory, func(filename string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := ioutil.ReadFile(filename)
+		logged = append(logged, string(content))
+		return err
+	})
+	assert.Nil(t, err)
+	if !assert.True(t, len(logged) > 0) {
+		return
+	}
+	var eventLog = strings.Join(logged, "\n")
+	assert.False(t, strings.Contains(eventLog, "k3y-abc123"))
+	assert.True(t, strings.Contains(eventLog, "tester calls with ***"))
+}
+
+func TestLogger_MaskEscapedSecret(t *testing.T) {
+	directory, err := ioutil.TempDir("", "endly_escaped_secret")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(directory)
+	var secret = `p"a\ss<w>&rd-ö`
+	secrets := endly.NewSecretValues()
+	secrets.Add(secret)
+	logger := NewLogger(directory, nil)
+	logger.mask = secrets.Mask
+	logger.OnEvent(msg.NewEvent(&model.Activity{MetaTag: &model.MetaTag{TagID: "build"}, Service: "exec", Action: "run"}))
+	logger.OnEvent(msg.NewEvent(msg.NewOutputEvent("mysql -p "+secret, "stdout", nil)))
+
+	var logged = make([]string, 0)
+	err = filepath.Walk(directory, func(filename string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := ioutil.ReadFile(filename)
+		logged = append(logged, string(content))
+		return err
+	})
+	assert.Nil(t, err)
+	var eventLog = strings.Join(logged, "\n")
+	assert.True(t, strings.Contains(eventLog, "mysql -p "+endly.MaskedValue), eventLog)
+	assert.False(t, strings.Contains(eventLog, `ss<w>`), eventLog)
+	assert.False(t, strings.Contains(eventLog, `ss\u003cw`), eventLog)
+}
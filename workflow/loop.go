@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	loopIndexKey = "index"
+	loopItemKey  = "item"
+)
+
+//loopItem represents action ForEach iteration
+type loopItem struct {
+	index interface{}
+	item  interface{}
+}
+
+//loopItems returns ForEach iterations, a collection is looked up or expanded from state, a numeric value defines fixed count
+func loopItems(context *endly.Context, forEach string) ([]*loopItem, error) {
+	var state = context.SafeState()
+	var source interface{}
+	if state.Has(forEach) {
+		source = state.Get(forEach)
+	} else {
+		source = state.Expand(forEach)
+	}
+	var result = make([]*loopItem, 0)
+	switch {
+	case source == nil:
+	case toolbox.IsSlice(source):
+		for i, item := range toolbox.AsSlice(source) {
+			result = append(result, &loopItem{index: i, item: item})
+		}
+	case toolbox.IsMap(source):
+		aMap := toolbox.AsMap(source)
+		var keys = make([]string, 0, len(aMap))
+		for key := range aMap {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			result = append(result, &loopItem{index: key, item: aMap[key]})
+		}
+	default:
+		count, err := strconv.Atoi(strings.TrimSpace(toolbox.AsString(source)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid forEach: %v, expected collection or count but had: %v", forEach, source)
+		}
+		for i := 0; i < count; i++ {
+			result = append(result, &loopItem{index: i, item: i})
+		}
+	}
+	return result, nil
+}
+
+//runLoop runs supplied function once, or for each action ForEach item exposing $index and $item state keys
+func (s *Service) runLoop(context *endly.Context, process *model.Process, action *model.Action, run func() error) error {
+	if action.ForEach == "" {
+		return run()
+	}
+	items, err := loopItems(context, action.ForEach)
+	if err != nil {
+		return err
+	}
+	var state = context.SafeState()
+	var previous = make(map[string]interface{})
+	_ = state.Update(func(state data.Map) error {
+		for _, key := range []string{loopIndexKey, loopItemKey} {
+			if state.Has(key) {
+				previous[key] = state.Get(key)
+			}
+		}
+		return nil
+	})
+	defer func() {
+		_ = state.Update(func(state data.Map) error {
+			for _, key := range []string{loopIndexKey, loopItemKey} {
+				if value, ok := previous[key]; ok {
+					state.Put(key, value)
+				} else {
+					state.Delete(key)
+				}
+			}
+			return nil
+		})
+	}()
+	for _, item := range items {
+		if !process.CanRun() {
+			break
+		}
+		_ = state.Update(func(state data.Map) error {
+			state.Put(loopIndexKey, item.index)
+			state.Put(loopItemKey, item.item)
+			return nil
+		})
+		if err = run(); err != nil {
+			return fmt.Errorf("forEach[%v]: %v", item.index, err)
+		}
+	}
+	return nil
+}
+
+//putActionResult stores action response in task result, ForEach action responses are collected
+func putActionResult(result data.Map, action *model.Action, response map[string]interface{}) {
+	if len(response) == 0 {
+		return
+	}
+	if action.ForEach == "" {
+		result[action.ID()] = response
+		return
+	}
+	responses, _ := result[action.ID()].([]interface{})
+	result[action.ID()] = append(responses, response)
+}
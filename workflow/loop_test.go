@@ -0,0 +1,85 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"testing"
+)
+
+func TestService_RunLoop(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+	var useCases = []struct {
+		description string
+		state       map[string]interface{}
+		forEach     string
+		expect      []interface{}
+		hasError    bool
+	}{
+		{
+			description: "slice collection",
+			state:       map[string]interface{}{"items": []interface{}{"a", "b", "c"}},
+			forEach:     "items",
+			expect:      []interface{}{"0:a", "1:b", "2:c"},
+		},
+		{
+			description: "slice collection expression",
+			state:       map[string]interface{}{"items": []interface{}{"a", "b"}},
+			forEach:     "$items",
+			expect:      []interface{}{"0:a", "1:b"},
+		},
+		{
+			description: "map collection",
+			state:       map[string]interface{}{"items": map[string]interface{}{"k2": "v2", "k1": "v1"}},
+			forEach:     "items",
+			expect:      []interface{}{"k1:v1", "k2:v2"},
+		},
+		{
+			description: "fixed count",
+			forEach:     "2",
+			expect:      []interface{}{"0:0", "1:1"},
+		},
+		{
+			description: "count expression",
+			state:       map[string]interface{}{"count": 3},
+			forEach:     "$count",
+			expect:      []interface{}{"0:0", "1:1", "2:2"},
+		},
+		{
+			description: "invalid forEach",
+			forEach:     "abc",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		for k, v := range useCase.state {
+			context.SetValue(k, v)
+		}
+		context.SetValue("item", "outer")
+		task := newTestTask("t1", "", "nop", map[string]interface{}{"In": map[string]interface{}{"Value": "$index:$item"}})
+		task.Actions[0].Service = "nop"
+		task.Actions[0].ForEach = useCase.forEach
+		process := model.NewProcess(nil, &model.Workflow{AbstractNode: &model.AbstractNode{Name: "test"}}, nil)
+		process.State = data.NewMap()
+		result, err := service.runTask(context, process, task)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		responses := toolbox.AsSlice(result[task.Actions[0].ID()])
+		var actual = make([]interface{}, 0)
+		for _, response := range responses {
+			actual = append(actual, toolbox.AsMap(response)["Value"])
+		}
+		assert.EqualValues(t, useCase.expect, actual, useCase.description)
+		assert.EqualValues(t, "outer", context.SafeState().Get("item"), useCase.description)
+		assert.False(t, context.SafeState().Has("index"), useCase.description)
+	}
+}
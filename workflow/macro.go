@@ -0,0 +1,114 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"strings"
+)
+
+const macroFolder = "macro"
+
+//macroURL returns macro definition URL, relative URL is resolved with the current workflow directory
+func macroURL(process *model.Process, request *MacroRequest) string {
+	URL := request.URL
+	if URL == "" {
+		URL = fmt.Sprintf("%v/%v.yaml", macroFolder, request.Name)
+	}
+	if strings.Contains(URL, ":/") || strings.HasPrefix(URL, "/") || process.Workflow == nil || process.Workflow.Source == nil {
+		return URL
+	}
+	parentURL, _ := toolbox.URLSplit(process.Workflow.Source.URL)
+	return toolbox.URLPathJoin(parentURL, URL)
+}
+
+//publishMacroParams publishes macro parameters, returned function restores replaced values and removes added ones
+func publishMacroParams(context *endly.Context, params map[string]interface{}) func() {
+	var state = context.SafeState()
+	var added = make([]string, 0)
+	var replaced = make(map[string]interface{})
+	for key, value := range params {
+		if previous, has := state.GetValue(key); has {
+			replaced[key] = previous
+		} else {
+			added = append(added, key)
+		}
+		state.SetValue(key, value)
+	}
+	return func() {
+		state.Delete(added...)
+		for key, value := range replaced {
+			state.SetValue(key, value)
+		}
+	}
+}
+
+//Macro returns registered macro for supplied name
+func (s *Service) Macro(name string) (*model.Workflow, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	result, ok := s.macros[name]
+	return result, ok
+}
+
+//RegisterMacro registers macro, a workflow which tasks are instantiated within the caller workflow
+func (s *Service) RegisterMacro(name string, macro *model.Workflow) error {
+	if err := macro.Validate(); err != nil {
+		return fmt.Errorf("invalid macro %v: %v", name, err)
+	}
+	if macro.AbstractNode == nil {
+		macro.AbstractNode = &model.AbstractNode{Name: name}
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.macros[name] = macro
+	return nil
+}
+
+func (s *Service) loadMacro(context *endly.Context, process *model.Process, request *MacroRequest) (*model.Workflow, error) {
+	if macro, ok := s.Macro(request.Name); ok {
+		return macro, nil
+	}
+	URL := macroURL(process, request)
+	resource := GetResource(s.Dao, context.State(), URL)
+	if resource == nil {
+		return nil, fmt.Errorf("unable to locate macro: %v, %v", request.Name, URL)
+	}
+	macro, err := s.Dao.Load(context, resource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load macro: %v, %v", URL, err)
+	}
+	return macro, s.RegisterMacro(request.Name, macro)
+}
+
+//runMacro runs macro tasks within the current workflow process, macro parameters are published to the state for the macro run duration
+func (s *Service) runMacro(context *endly.Context, request *MacroRequest) (*MacroResponse, error) {
+	process := Last(context)
+	if process == nil {
+		return nil, errors.New("no active workflow")
+	}
+	macro, err := s.loadMacro(context, process, request)
+	if err != nil {
+		return nil, err
+	}
+	if err = macro.ValidateInputs(request.Params); err != nil {
+		return nil, err
+	}
+	var response = &MacroResponse{Data: make(map[string]interface{})}
+	defer publishMacroParams(context, request.Params)()
+	if task := process.Task; task != nil {
+		defer process.SetTask(task)
+	}
+	err = s.runNode(context, "macro", process, macro.AbstractNode, func(context *endly.Context, process *model.Process) (in, out data.Map, err error) {
+		err = s.runTasks(context, process, macro.TasksNode)
+		return context.State(), response.Data, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("macro %v failed: %v", request.Name, err)
+	}
+	context.SafeState().Apply(response.Data)
+	return response, nil
+}
@@ -0,0 +1,40 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestService_Macro(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	context := endly.New().NewContext(nil)
+	response := &RunResponse{}
+	err := endly.Run(context, &RunRequest{URL: path.Join(parent, "test/macro/main.yaml")}, response)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, "api", response.Data["deployed"])
+	assert.EqualValues(t, []interface{}{"web:8080", "api:8081"}, response.Data["checked"])
+	assert.EqualValues(t, "$app", response.Data["app"]) //macro params are restored once macro completes
+
+	service, err := context.Service(ServiceID)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, registered := service.(*Service).Macro("deploy")
+	assert.True(t, registered)
+
+	err = endly.Run(context, &RunRequest{URL: path.Join(parent, "test/macro/invalid.yaml")}, &RunResponse{})
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "invalid deploy workflow inputs"), err.Error())
+	}
+
+	err = endly.Run(context, &MacroRequest{Name: "deploy", Params: map[string]interface{}{"app": "web"}}, &MacroResponse{})
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "no active workflow"), err.Error())
+	}
+}
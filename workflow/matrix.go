@@ -0,0 +1,197 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/ratelimit"
+	"github.com/viant/endly/model/tracing"
+	"github.com/viant/endly/system/workspace"
+	"github.com/viant/endly/util"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//Matrix represents workflow parameters matrix, workflow runs once per parameters combination
+type Matrix struct {
+	Params      []map[string]interface{} `description:"explicit parameter sets"`
+	Values      map[string][]interface{} `description:"parameter value lists, their cartesian product is combined with each explicit parameter set"`
+	Concurrency int                      `description:"max number of concurrently running combinations, 1 by default"`
+}
+
+//MatrixResult represents a workflow run result for a parameters combination
+type MatrixResult struct {
+	Index   int
+	Params  map[string]interface{}
+	Status  string
+	Error   string                 `json:",omitempty"`
+	Data    map[string]interface{} `json:",omitempty"`
+	Metrics *Metrics               `json:",omitempty"`
+	Summary *ValidationSummary     `json:",omitempty"`
+}
+
+//Init initialises matrix
+func (m *Matrix) Init() (err error) {
+	for i, params := range m.Params {
+		if m.Params[i], err = util.NormalizeMap(params, true); err != nil {
+			return err
+		}
+	}
+	if m.Concurrency == 0 {
+		m.Concurrency = 1
+	}
+	return nil
+}
+
+//Validate checks if matrix is valid
+func (m *Matrix) Validate() error {
+	if len(m.Params) == 0 && len(m.Values) == 0 {
+		return errors.New("matrix params and values were empty")
+	}
+	for key, values := range m.Values {
+		if len(values) == 0 {
+			return fmt.Errorf("matrix values were empty: %v", key)
+		}
+	}
+	if m.Concurrency < 0 {
+		return fmt.Errorf("invalid matrix concurrency: %v", m.Concurrency)
+	}
+	return nil
+}
+
+//Combinations returns parameters combinations, each explicit parameter set is combined with values cartesian product,
+//product keys are sorted and the last key varies fastest
+func (m *Matrix) Combinations() []map[string]interface{} {
+	var result = make([]map[string]interface{}, 0)
+	var keys = make([]string, 0, len(m.Values))
+	for key := range m.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var product = []map[string]interface{}{{}}
+	for _, key := range keys {
+		var next = make([]map[string]interface{}, 0, len(product)*len(m.Values[key]))
+		for _, combination := range product {
+			for _, value := range m.Values[key] {
+				var extended = make(map[string]interface{}, len(combination)+1)
+				for k, v := range combination {
+					extended[k] = v
+				}
+				extended[key] = value
+				next = append(next, extended)
+			}
+		}
+		product = next
+	}
+	var sets = m.Params
+	if len(sets) == 0 {
+		sets = []map[string]interface{}{{}}
+	}
+	for _, set := range sets {
+		for _, combination := range product {
+			var params = make(map[string]interface{}, len(set)+len(combination))
+			for k, v := range set {
+				params[k] = v
+			}
+			for k, v := range combination {
+				params[k] = v
+			}
+			result = append(result, params)
+		}
+	}
+	return result
+}
+
+//matrixRequest returns a request running workflow for supplied combination, run wide options are managed by the matrix run
+func matrixRequest(request *RunRequest, params map[string]interface{}) *RunRequest {
+	var result = *request
+	result.Matrix = nil
+	result.Async = false
+	result.EnableLogging = false
+	result.StreamAddress = ""
+	result.AuditLog = ""
+	result.Report = ""
+	result.MetricsFile = ""
+	result.Resume = false
+	result.Debug = false
+	result.Params = make(map[string]interface{}, len(request.Params)+len(params))
+	for k, v := range request.Params {
+		result.Params[k] = v
+	}
+	for k, v := range params {
+		result.Params[k] = v
+	}
+	return &result
+}
+
+//runMatrix runs workflow once per matrix combination with a concurrency limit, each run uses its own context copy
+func (s *Service) runMatrix(context *endly.Context, request *RunRequest) (*RunResponse, error) {
+	response := &RunResponse{
+		Data:      make(map[string]interface{}),
+		SessionID: context.SessionID,
+	}
+	s.enableLoggingIfNeeded(context, request)
+	if err := s.enableStreamIfNeeded(context, request); err != nil {
+		return nil, err
+	}
+	if err := s.enableAuditIfNeeded(context, request); err != nil {
+		return nil, err
+	}
+	if _, err := s.getWorkflow(context, request); err != nil { //load workflow once before concurrent runs
+		return nil, err
+	}
+	releaseTracing, err := tracing.Enable(context, request.Tracing)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseTracing()
+	releaseLimits, err := ratelimit.Enable(context, request.RateLimits)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseLimits()
+	releaseWorkspace, err := workspace.Enable(context, request.KeepWorkspace)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseWorkspace()
+
+	combinations := request.Matrix.Combinations()
+	response.Matrix = make([]*MatrixResult, len(combinations))
+	limiter := make(chan bool, request.Matrix.Concurrency)
+	group := &sync.WaitGroup{}
+	group.Add(len(combinations))
+	for i, params := range combinations {
+		response.Matrix[i] = &MatrixResult{Index: i, Params: params}
+		limiter <- true
+		child := context.AsyncClone()
+		_ = child.Replace(processesKey, processes(context).Clone())
+		go func(result *MatrixResult, child *endly.Context) {
+			defer group.Done()
+			defer func() { <-limiter }()
+			runResponse, err := s.runWorkflow(child, matrixRequest(request, result.Params))
+			result.Status = "ok"
+			if err != nil {
+				result.Status = "error"
+				result.Error = err.Error()
+			}
+			if runResponse != nil {
+				result.Data = runResponse.Data
+				result.Metrics = runResponse.Metrics
+				result.Summary = runResponse.Summary
+			}
+		}(response.Matrix[i], child)
+	}
+	group.Wait()
+	var failed = make([]string, 0)
+	for _, result := range response.Matrix {
+		if result.Error != "" {
+			failed = append(failed, fmt.Sprintf("#%v %v: %v", result.Index, result.Params, result.Error))
+		}
+	}
+	if len(failed) > 0 {
+		return response, fmt.Errorf("%v of %v matrix runs failed: %v", len(failed), len(combinations), strings.Join(failed, "; "))
+	}
+	return response, nil
+}
@@ -0,0 +1,115 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestMatrix_Combinations(t *testing.T) {
+	var useCases = []struct {
+		description string
+		matrix      *Matrix
+		expect      []map[string]interface{}
+	}{
+		{
+			description: "cartesian product",
+			matrix:      &Matrix{Values: map[string][]interface{}{"locale": {"en", "fr"}, "browser": {"chrome", "firefox"}}},
+			expect: []map[string]interface{}{
+				{"browser": "chrome", "locale": "en"},
+				{"browser": "chrome", "locale": "fr"},
+				{"browser": "firefox", "locale": "en"},
+				{"browser": "firefox", "locale": "fr"},
+			},
+		},
+		{
+			description: "param sets",
+			matrix:      &Matrix{Params: []map[string]interface{}{{"env": "dev"}, {"env": "prod", "debug": true}}},
+			expect: []map[string]interface{}{
+				{"env": "dev"},
+				{"env": "prod", "debug": true},
+			},
+		},
+		{
+			description: "param sets combined with product",
+			matrix:      &Matrix{Params: []map[string]interface{}{{"env": "dev"}, {"env": "prod"}}, Values: map[string][]interface{}{"browser": {"chrome", "firefox"}}},
+			expect: []map[string]interface{}{
+				{"env": "dev", "browser": "chrome"},
+				{"env": "dev", "browser": "firefox"},
+				{"env": "prod", "browser": "chrome"},
+				{"env": "prod", "browser": "firefox"},
+			},
+		},
+	}
+	for _, useCase := range useCases {
+		assert.Nil(t, useCase.matrix.Init(), useCase.description)
+		assert.Nil(t, useCase.matrix.Validate(), useCase.description)
+		assert.Equal(t, useCase.expect, useCase.matrix.Combinations(), useCase.description)
+	}
+	assert.NotNil(t, (&Matrix{}).Validate())
+	assert.NotNil(t, (&Matrix{Values: map[string][]interface{}{"browser": {}}}).Validate())
+}
+
+func TestService_RunMatrix(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	service := New()
+	var useCases = []struct {
+		description  string
+		matrix       *Matrix
+		combinations []string
+		errors       []string
+	}{
+		{
+			description:  "parallel runs",
+			matrix:       &Matrix{Values: map[string][]interface{}{"browser": {"chrome", "firefox"}, "locale": {"en", "fr", "de"}}, Concurrency: 4},
+			combinations: []string{"chrome-en-test", "chrome-fr-test", "chrome-de-test", "firefox-en-test", "firefox-fr-test", "firefox-de-test"},
+		},
+		{
+			description:  "failed combination",
+			matrix:       &Matrix{Params: []map[string]interface{}{{"browser": "chrome", "stage": "prod"}, {"browser": "safari"}}, Values: map[string][]interface{}{"locale": {"en"}}},
+			combinations: []string{"chrome-en-prod", ""},
+			errors:       []string{"1 of 2 matrix runs failed", "#1", "unsupported browser safari"},
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		request := &RunRequest{
+			URL:               path.Join(parent, "test/matrix/matrix.yaml"),
+			Params:            map[string]interface{}{"stage": "test"},
+			Matrix:            useCase.matrix,
+			PublishParameters: true,
+		}
+		serviceResponse := service.Run(context, request)
+		err := serviceResponse.Err
+		response, ok := serviceResponse.Response.(*RunResponse)
+		if !assert.True(t, ok, useCase.description) {
+			continue
+		}
+		if len(useCase.errors) > 0 {
+			if assert.NotNil(t, err, useCase.description) {
+				for _, fragment := range useCase.errors {
+					assert.True(t, strings.Contains(err.Error(), fragment), useCase.description+": "+err.Error())
+				}
+			}
+		} else {
+			assert.Nil(t, err, useCase.description)
+		}
+		if !assert.Equal(t, len(useCase.combinations), len(response.Matrix), useCase.description) {
+			continue
+		}
+		for i, expect := range useCase.combinations {
+			result := response.Matrix[i]
+			assert.Equal(t, i, result.Index, useCase.description)
+			if expect == "" {
+				assert.Equal(t, "error", result.Status, useCase.description)
+				continue
+			}
+			assert.Equal(t, "ok", result.Status, useCase.description)
+			assert.Equal(t, expect, result.Data["combination"], useCase.description)
+		}
+	}
+}
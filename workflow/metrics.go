@@ -0,0 +1,344 @@
+package workflow
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+var metricsCollectorKey = (*metricsCollector)(nil)
+
+//ActionMetric represents action execution metrics aggregated by workflow, task and TagID
+type ActionMetric struct {
+	Workflow      string
+	Task          string `json:",omitempty"`
+	TagID         string `json:",omitempty"`
+	Service       string
+	Action        string
+	Runs          int
+	Retries       int //runs beyond the first one, i.e. repeated or looped action
+	Failed        int
+	DurationMs    int //total execution time
+	MaxDurationMs int
+	WaitMs        int //total sleep time
+}
+
+//TaskMetric represents task execution metrics aggregated by workflow and task name
+type TaskMetric struct {
+	Workflow   string
+	Name       string
+	Runs       int
+	Actions    int //total action runs
+	Failed     int
+	DurationMs int
+	WaitMs     int
+}
+
+//MetricsSummary represents aggregated workflow run metrics
+type MetricsSummary struct {
+	Workflow   string
+	Tasks      int //total task runs
+	Actions    int //total action runs
+	Retries    int
+	Failed     int //total failed actions
+	DurationMs int
+	WaitMs     int
+}
+
+//Metrics represents workflow run timing metrics
+type Metrics struct {
+	Summary *MetricsSummary
+	Tasks   []*TaskMetric
+	Actions []*ActionMetric
+}
+
+func promLabels(pairs ...string) string {
+	var labels = make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(pairs[i+1])
+		labels = append(labels, fmt.Sprintf(`%v="%v"`, pairs[i], value))
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+func promSeconds(ms int) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000.0)
+}
+
+//Prometheus returns metrics in Prometheus text exposition format
+func (m *Metrics) Prometheus() []byte {
+	var buffer = new(bytes.Buffer)
+	var gauge = func(name, help string, samples func(add func(labels string, value interface{}))) {
+		fmt.Fprintf(buffer, "# HELP %v %v\n# TYPE %v gauge\n", name, help, name)
+		samples(func(labels string, value interface{}) {
+			fmt.Fprintf(buffer, "%v%v %v\n", name, labels, value)
+		})
+	}
+	var workflowLabels = promLabels("workflow", m.Summary.Workflow)
+	gauge("endly_workflow_duration_seconds", "workflow run duration", func(add func(labels string, value interface{})) {
+		add(workflowLabels, promSeconds(m.Summary.DurationMs))
+	})
+	gauge("endly_workflow_wait_seconds", "workflow run total sleep time", func(add func(labels string, value interface{})) {
+		add(workflowLabels, promSeconds(m.Summary.WaitMs))
+	})
+	gauge("endly_workflow_actions", "workflow run total action runs", func(add func(labels string, value interface{})) {
+		add(workflowLabels, m.Summary.Actions)
+	})
+	gauge("endly_workflow_retries", "workflow run total action retries", func(add func(labels string, value interface{})) {
+		add(workflowLabels, m.Summary.Retries)
+	})
+	gauge("endly_workflow_failures", "workflow run total failed actions", func(add func(labels string, value interface{})) {
+		add(workflowLabels, m.Summary.Failed)
+	})
+	var taskLabels = func(task *TaskMetric) string {
+		return promLabels("workflow", task.Workflow, "task", task.Name)
+	}
+	gauge("endly_task_duration_seconds", "task total duration", func(add func(labels string, value interface{})) {
+		for _, task := range m.Tasks {
+			add(taskLabels(task), promSeconds(task.DurationMs))
+		}
+	})
+	gauge("endly_task_wait_seconds", "task total sleep time", func(add func(labels string, value interface{})) {
+		for _, task := range m.Tasks {
+			add(taskLabels(task), promSeconds(task.WaitMs))
+		}
+	})
+	gauge("endly_task_runs", "task runs", func(add func(labels string, value interface{})) {
+		for _, task := range m.Tasks {
+			add(taskLabels(task), task.Runs)
+		}
+	})
+	var actionLabels = func(action *ActionMetric) string {
+		return promLabels("workflow", action.Workflow, "task", action.Task, "tag_id", action.TagID, "service", action.Service, "action", action.Action)
+	}
+	gauge("endly_action_duration_seconds", "action total duration", func(add func(labels string, value interface{})) {
+		for _, action := range m.Actions {
+			add(actionLabels(action), promSeconds(action.DurationMs))
+		}
+	})
+	gauge("endly_action_max_duration_seconds", "action max single run duration", func(add func(labels string, value interface{})) {
+		for _, action := range m.Actions {
+			add(actionLabels(action), promSeconds(action.MaxDurationMs))
+		}
+	})
+	gauge("endly_action_wait_seconds", "action total sleep time", func(add func(labels string, value interface{})) {
+		for _, action := range m.Actions {
+			add(actionLabels(action), promSeconds(action.WaitMs))
+		}
+	})
+	gauge("endly_action_runs", "action runs", func(add func(labels string, value interface{})) {
+		for _, action := range m.Actions {
+			add(actionLabels(action), action.Runs)
+		}
+	})
+	gauge("endly_action_retries", "action runs beyond the first one", func(add func(labels string, value interface{})) {
+		for _, action := range m.Actions {
+			add(actionLabels(action), action.Retries)
+		}
+	})
+	gauge("endly_action_failures", "action failed runs", func(add func(labels string, value interface{})) {
+		for _, action := range m.Actions {
+			add(actionLabels(action), action.Failed)
+		}
+	})
+	return buffer.Bytes()
+}
+
+//metricsCollector collects workflow run actions and tasks timing
+type metricsCollector struct {
+	Listener   msg.Listener
+	mux        *sync.Mutex
+	process    *model.Process
+	startTime  time.Time
+	started    map[*model.Activity]*ActionMetric
+	last       *ActionMetric //the most recently run action, sleep time is attributed to it
+	actions    map[string]*ActionMetric
+	tasks      map[string]*TaskMetric
+	actionKeys []string
+	taskKeys   []string
+	summary    *MetricsSummary
+	done       bool
+}
+
+//AsEventListener returns event listener that collects events and passes them to the next listener
+func (c *metricsCollector) AsEventListener() msg.Listener {
+	return func(event msg.Event) {
+		if c.Listener != nil {
+			c.Listener(event)
+		}
+		c.OnEvent(event)
+	}
+}
+
+//OnEvent collects activity start/end and sleep events
+func (c *metricsCollector) OnEvent(event msg.Event) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.done {
+		return
+	}
+	switch value := event.Value().(type) {
+	case *model.Activity:
+		var metric = c.action(value)
+		metric.Runs++
+		if metric.Runs > 1 {
+			metric.Retries++
+			c.summary.Retries++
+		}
+		c.summary.Actions++
+		c.started[value] = metric
+		c.last = metric
+		if task := c.task(value.Caller, value.Task); task != nil {
+			task.Actions++
+		}
+	case *model.ActivityEndEvent:
+		activity, ok := value.Response.(*model.Activity)
+		if !ok {
+			return
+		}
+		metric, ok := c.started[activity]
+		if !ok {
+			return
+		}
+		delete(c.started, activity)
+		elapsedMs := int(time.Since(activity.StartTime) / time.Millisecond)
+		metric.DurationMs += elapsedMs
+		if elapsedMs > metric.MaxDurationMs {
+			metric.MaxDurationMs = elapsedMs
+		}
+		if activity.Error != "" {
+			metric.Failed++
+			c.summary.Failed++
+			if task := c.task(activity.Caller, activity.Task); task != nil {
+				task.Failed++
+			}
+		}
+	case *msg.SleepEvent:
+		c.summary.WaitMs += value.SleepTimeMs
+		if c.last == nil {
+			return
+		}
+		c.last.WaitMs += value.SleepTimeMs
+		if task := c.task(c.last.Workflow, c.last.Task); task != nil {
+			task.WaitMs += value.SleepTimeMs
+		}
+	}
+}
+
+func (c *metricsCollector) action(activity *model.Activity) *ActionMetric {
+	var key = strings.Join([]string{activity.Caller, activity.Task, activity.TagID, activity.Service, activity.Action}, "/")
+	if result, ok := c.actions[key]; ok {
+		return result
+	}
+	var result = &ActionMetric{Workflow: activity.Caller, Task: activity.Task, TagID: activity.TagID, Service: activity.Service, Action: activity.Action}
+	c.actions[key] = result
+	c.actionKeys = append(c.actionKeys, key)
+	return result
+}
+
+func (c *metricsCollector) task(workflow, name string) *TaskMetric {
+	if name == "" {
+		return nil
+	}
+	var key = workflow + "/" + name
+	if result, ok := c.tasks[key]; ok {
+		return result
+	}
+	var result = &TaskMetric{Workflow: workflow, Name: name}
+	c.tasks[key] = result
+	c.taskKeys = append(c.taskKeys, key)
+	return result
+}
+
+//startTask records task run, returned function records task duration
+func (c *metricsCollector) startTask(process *model.Process, task *model.Task) func() {
+	if c == nil || process.Workflow == nil {
+		return func() {}
+	}
+	var startTime = time.Now()
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	var metric = c.task(process.Workflow.Name, task.Name)
+	metric.Runs++
+	c.summary.Tasks++
+	return func() {
+		c.mux.Lock()
+		defer c.mux.Unlock()
+		metric.DurationMs += int(time.Since(startTime) / time.Millisecond)
+	}
+}
+
+//complete summarizes metrics, no events are collected afterwards
+func (c *metricsCollector) complete() *Metrics {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.done = true
+	c.summary.DurationMs = int(time.Since(c.startTime) / time.Millisecond)
+	var result = &Metrics{Summary: c.summary, Tasks: make([]*TaskMetric, 0), Actions: make([]*ActionMetric, 0)}
+	for _, key := range c.taskKeys {
+		result.Tasks = append(result.Tasks, c.tasks[key])
+	}
+	for _, key := range c.actionKeys {
+		result.Actions = append(result.Actions, c.actions[key])
+	}
+	return result
+}
+
+func metricsCollectorFor(context *endly.Context) *metricsCollector {
+	if !context.Contains(metricsCollectorKey) {
+		return nil
+	}
+	var result *metricsCollector
+	context.GetInto(metricsCollectorKey, &result)
+	return result
+}
+
+//enableMetrics collects top level workflow run metrics
+func (s *Service) enableMetrics(context *endly.Context, process *model.Process) error {
+	if metricsCollectorFor(context) != nil {
+		return nil
+	}
+	var collector = &metricsCollector{
+		Listener:   context.Listener,
+		mux:        &sync.Mutex{},
+		process:    process,
+		startTime:  time.Now(),
+		started:    make(map[*model.Activity]*ActionMetric),
+		actions:    make(map[string]*ActionMetric),
+		tasks:      make(map[string]*TaskMetric),
+		actionKeys: make([]string, 0),
+		taskKeys:   make([]string, 0),
+		summary:    &MetricsSummary{Workflow: process.Workflow.Name},
+	}
+	context.Listener = collector.AsEventListener()
+	return context.Put(metricsCollectorKey, collector)
+}
+
+//completeMetrics sets top level workflow run metrics, and writes them in Prometheus text format if metrics file was specified
+func (s *Service) completeMetrics(context *endly.Context, request *RunRequest, process *model.Process, response *RunResponse) {
+	collector := metricsCollectorFor(context)
+	if collector == nil || collector.process != process {
+		return
+	}
+	response.Metrics = collector.complete()
+	if request.MetricsFile == "" {
+		return
+	}
+	err := os.MkdirAll(path.Dir(request.MetricsFile), 0744)
+	if err == nil {
+		err = ioutil.WriteFile(request.MetricsFile, response.Metrics.Prometheus(), 0644)
+	}
+	if err != nil {
+		context.Publish(msg.NewErrorEvent(fmt.Sprintf("failed to write metrics: %v", err)))
+		return
+	}
+	context.Publish(msg.NewOutputEvent(request.MetricsFile, "metrics", nil))
+}
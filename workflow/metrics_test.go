@@ -0,0 +1,67 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestService_Metrics(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	metricsFile := path.Join(os.TempDir(), "endly_metrics_test.prom")
+	_ = os.Remove(metricsFile)
+	defer os.Remove(metricsFile)
+	response := &RunResponse{}
+	err := endly.Run(context, &RunRequest{URL: path.Join(parent, "test/metrics/metrics.yaml"), MetricsFile: metricsFile}, response)
+	if !assert.Nil(t, err) || !assert.NotNil(t, response.Metrics) {
+		return
+	}
+	metrics := response.Metrics
+	assert.Equal(t, "metrics", metrics.Summary.Workflow)
+	assert.Equal(t, 2, metrics.Summary.Tasks)
+	assert.Equal(t, 4, metrics.Summary.Actions)
+	assert.Equal(t, 2, metrics.Summary.Retries)
+	assert.Equal(t, 0, metrics.Summary.Failed)
+	assert.Equal(t, 30, metrics.Summary.WaitMs)
+	assert.True(t, metrics.Summary.DurationMs >= 30)
+
+	if assert.Equal(t, 2, len(metrics.Tasks)) {
+		assert.Equal(t, "build", metrics.Tasks[0].Name)
+		assert.Equal(t, 1, metrics.Tasks[0].Runs)
+		assert.Equal(t, 3, metrics.Tasks[0].Actions)
+		assert.Equal(t, 30, metrics.Tasks[0].WaitMs)
+		assert.True(t, metrics.Tasks[0].DurationMs >= 30)
+		assert.Equal(t, "deploy", metrics.Tasks[1].Name)
+		assert.Equal(t, 1, metrics.Tasks[1].Actions)
+	}
+	if assert.Equal(t, 2, len(metrics.Actions)) {
+		nop := metrics.Actions[0]
+		assert.Equal(t, "build", nop.Task)
+		assert.Equal(t, "nop", nop.Action)
+		assert.Equal(t, 3, nop.Runs)
+		assert.Equal(t, 2, nop.Retries)
+		assert.Equal(t, 30, nop.WaitMs)
+		assert.Equal(t, "print", metrics.Actions[1].Action)
+		assert.Equal(t, 1, metrics.Actions[1].Runs)
+	}
+
+	content, err := ioutil.ReadFile(metricsFile)
+	if assert.Nil(t, err) {
+		text := string(content)
+		assert.True(t, strings.Contains(text, "# TYPE endly_workflow_duration_seconds gauge"), text)
+		assert.True(t, strings.Contains(text, `endly_workflow_retries{workflow="metrics"} 2`), text)
+		assert.True(t, strings.Contains(text, `endly_task_wait_seconds{workflow="metrics",task="build"} 0.030`), text)
+		assert.True(t, strings.Contains(text, `endly_action_runs{workflow="metrics",task="build",tag_id="",service="workflow",action="nop"} 3`), text)
+	}
+}
+
+func TestPromLabels(t *testing.T) {
+	assert.Equal(t, `{workflow="a\"b\\c\nd",task=""}`, promLabels("workflow", "a\"b\\c\nd", "task", ""))
+}
@@ -0,0 +1,82 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/viant/endly/util"
+	"github.com/viant/toolbox/data"
+	"github.com/viant/toolbox/url"
+	"sort"
+	"strings"
+)
+
+//Profile represents environment overlay i.e. dev, staging or prod, merged over run request params
+type Profile struct {
+	Params      map[string]interface{}   `description:"params deeply merged over run request params"`
+	Targets     map[string]*url.Resource `description:"target params overlay, key is param name i.e. target, value resource URL and credentials"`
+	Credentials map[string]string        `description:"credentials params overlay, key is param name i.e. mysqlCredentials, value credentials name or location"`
+}
+
+//Init initialises profile
+func (p *Profile) Init() (err error) {
+	p.Params, err = util.NormalizeMap(p.Params, true)
+	return err
+}
+
+//Apply merges profile over supplied params
+func (p *Profile) Apply(params map[string]interface{}) {
+	util.MergeMap(params, p.Params)
+	for name, target := range p.Targets {
+		if target == nil {
+			continue
+		}
+		var keys = map[string]string{}
+		if existing, ok := params[name].(map[string]interface{}); ok {
+			keys = util.BuildLowerCaseMapping(existing)
+		}
+		var overlay = map[string]interface{}{}
+		for key, value := range map[string]string{"URL": target.URL, "Credentials": target.Credentials} {
+			if value == "" {
+				continue
+			}
+			if existingKey, ok := keys[strings.ToLower(key)]; ok { //keep base target key case, i.e. credentials
+				key = existingKey
+			}
+			overlay[key] = value
+		}
+		util.MergeMap(params, map[string]interface{}{name: overlay})
+	}
+	var aMap = data.Map(params)
+	for name, credentials := range p.Credentials {
+		aMap.SetValue(name, credentials)
+	}
+}
+
+//ApplyProfile merges selected Env profile over request params, profile is applied only once
+func (r *RunRequest) ApplyProfile() error {
+	if r.Env == "" || r.appliedEnv == r.Env {
+		return nil
+	}
+	profile, ok := r.Profiles[r.Env]
+	if !ok || profile == nil {
+		return fmt.Errorf("unknown env profile: %v, available: [%v]", r.Env, strings.Join(r.profileNames(), ","))
+	}
+	err := profile.Init()
+	if err != nil {
+		return err
+	}
+	if r.Params, err = util.NormalizeMap(r.Params, true); err != nil {
+		return err
+	}
+	profile.Apply(r.Params)
+	r.appliedEnv = r.Env
+	return nil
+}
+
+func (r *RunRequest) profileNames() []string {
+	var result = make([]string, 0, len(r.Profiles))
+	for name := range r.Profiles {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
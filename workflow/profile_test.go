@@ -0,0 +1,86 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/url"
+	"testing"
+)
+
+func TestRunRequest_ApplyProfile(t *testing.T) {
+	var profiles = map[string]*Profile{
+		"dev": {
+			Params: map[string]interface{}{"db": map[string]interface{}{"host": "127.0.0.1"}},
+		},
+		"prod": {
+			Params:      map[string]interface{}{"db": map[string]interface{}{"host": "db.prod"}, "replicas": 3},
+			Targets:     map[string]*url.Resource{"target": {URL: "ssh://prod-host/", Credentials: "prod"}},
+			Credentials: map[string]string{"db.credentials": "mysql-prod"},
+		},
+	}
+	var useCases = []struct {
+		description string
+		env         string
+		expect      map[string]interface{}
+		hasError    bool
+	}{
+		{
+			description: "no profile",
+			expect: map[string]interface{}{
+				"app":    "myapp",
+				"db":     map[string]interface{}{"host": "localhost", "port": 3306},
+				"target": map[string]interface{}{"URL": "ssh://127.0.0.1/", "credentials": "localhost"},
+			},
+		},
+		{
+			description: "params overlay",
+			env:         "dev",
+			expect: map[string]interface{}{
+				"app":    "myapp",
+				"db":     map[string]interface{}{"host": "127.0.0.1", "port": 3306},
+				"target": map[string]interface{}{"URL": "ssh://127.0.0.1/", "credentials": "localhost"},
+			},
+		},
+		{
+			description: "params, targets and credentials overlay",
+			env:         "prod",
+			expect: map[string]interface{}{
+				"app":      "myapp",
+				"replicas": 3,
+				"db":       map[string]interface{}{"host": "db.prod", "port": 3306, "credentials": "mysql-prod"},
+				"target":   map[string]interface{}{"URL": "ssh://prod-host/", "credentials": "prod"},
+			},
+		},
+		{
+			description: "unknown profile",
+			env:         "qa",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		request := &RunRequest{
+			Env:      useCase.env,
+			Profiles: profiles,
+			Params: map[string]interface{}{
+				"app":    "myapp",
+				"db":     map[string]interface{}{"host": "localhost", "port": 3306},
+				"target": map[string]interface{}{"URL": "ssh://127.0.0.1/", "credentials": "localhost"},
+			},
+		}
+		err := request.ApplyProfile()
+		if useCase.hasError {
+			if assert.NotNil(t, err, useCase.description) {
+				assert.Contains(t, err.Error(), "available: [dev,prod]", useCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.EqualValues(t, useCase.expect, request.Params, useCase.description)
+		request.Params["app"] = "override"
+		assert.Nil(t, request.ApplyProfile(), useCase.description)
+		assert.Equal(t, "override", request.Params["app"], "profile is applied once: "+useCase.description)
+	}
+	//profile params must not leak into other requests sharing profiles
+	assert.Equal(t, "127.0.0.1", profiles["dev"].Params["db"].(map[string]interface{})["host"])
+}
@@ -0,0 +1,355 @@
+package workflow
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/viant/assertly"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//ReportJUnit represents JUnit XML report format
+	ReportJUnit = "junit"
+	//ReportJSON represents JSON summary report format
+	ReportJSON = "json"
+)
+
+var reportCollectorKey = (*reportCollector)(nil)
+
+//asserted represents response with assertly validations
+type asserted interface {
+	Assertion() []*assertly.Validation
+}
+
+//Report represents workflow run report
+type Report struct {
+	Workflow  string
+	SessionID string
+	StartTime time.Time
+	ElapsedMs int
+	Passed    int //total passed validations
+	Failed    int //total failed validations
+	Errors    int //total failed actions
+	Error     string `json:",omitempty"`
+	Cases     []*ReportCase
+}
+
+//ReportCase represents executed action report
+type ReportCase struct {
+	TagID       string
+	Task        string `json:",omitempty"`
+	Service     string
+	Action      string
+	Description string `json:",omitempty"`
+	StartTime   time.Time
+	ElapsedMs   int
+	Passed      int
+	Failed      int
+	Failures    []string `json:",omitempty"`
+	Error       string   `json:",omitempty"`
+}
+
+//Name returns report case name
+func (c *ReportCase) Name() string {
+	if c.TagID != "" {
+		return c.TagID
+	}
+	return c.Service + "." + c.Action
+}
+
+//addValidation adds validation counters and failures
+func (c *ReportCase) addValidation(validation *assertly.Validation) {
+	c.Passed += validation.PassedCount
+	c.Failed += validation.FailedCount
+	for _, failure := range validation.Failures {
+		message := failure.Message
+		if message == "" {
+			message = fmt.Sprintf("%v: expected: %v, actual: %v", failure.Path, failure.Expected, failure.Actual)
+		}
+		c.Failures = append(c.Failures, message)
+	}
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+type junitTestcase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitTestsuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Time      string           `xml:"time,attr"`
+	Timestamp string           `xml:"timestamp,attr"`
+	Testcases []*junitTestcase `xml:"testcase"`
+}
+
+type junitTestsuites struct {
+	XMLName    xml.Name          `xml:"testsuites"`
+	Name       string            `xml:"name,attr"`
+	Tests      int               `xml:"tests,attr"`
+	Failures   int               `xml:"failures,attr"`
+	Errors     int               `xml:"errors,attr"`
+	Time       string            `xml:"time,attr"`
+	Testsuites []*junitTestsuite `xml:"testsuite"`
+}
+
+func formatSeconds(elapsedMs int) string {
+	return fmt.Sprintf("%.3f", float64(elapsedMs)/1000.0)
+}
+
+//JUnit returns JUnit XML report, actions with validations or errors are reported as test cases
+func (r *Report) JUnit() ([]byte, error) {
+	var suite = &junitTestsuite{
+		Name:      r.Workflow,
+		Time:      formatSeconds(r.ElapsedMs),
+		Timestamp: r.StartTime.Format("2006-01-02T15:04:05"),
+		Testcases: make([]*junitTestcase, 0),
+	}
+	for _, reportCase := range r.Cases {
+		if reportCase.Passed+reportCase.Failed == 0 && reportCase.Error == "" {
+			continue
+		}
+		var testcase = &junitTestcase{
+			Name:      reportCase.Name(),
+			Classname: r.Workflow + "." + reportCase.Service + "." + reportCase.Action,
+			Time:      formatSeconds(reportCase.ElapsedMs),
+		}
+		if reportCase.Failed > 0 {
+			suite.Failures++
+			testcase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%v failed, %v passed", reportCase.Failed, reportCase.Passed),
+				Type:    "validation",
+				Text:    strings.Join(reportCase.Failures, "\n"),
+			}
+		}
+		if reportCase.Error != "" {
+			suite.Errors++
+			testcase.Error = &junitFailure{Message: reportCase.Error, Type: "error", Text: reportCase.Error}
+		}
+		suite.Testcases = append(suite.Testcases, testcase)
+	}
+	suite.Tests = len(suite.Testcases)
+	var suites = &junitTestsuites{
+		Name:       r.Workflow,
+		Tests:      suite.Tests,
+		Failures:   suite.Failures,
+		Errors:     suite.Errors,
+		Time:       suite.Time,
+		Testsuites: []*junitTestsuite{suite},
+	}
+	content, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+//reportCollector collects top level workflow activities and validations
+type reportCollector struct {
+	Listener  msg.Listener
+	mux       *sync.Mutex
+	process   *model.Process
+	formats   []string
+	directory string
+	report    *Report
+	cases     map[*model.Activity]*ReportCase
+	active    []*ReportCase //running actions
+	done      bool
+}
+
+//AsEventListener returns event listener that collects events and passes them to the next listener
+func (c *reportCollector) AsEventListener() msg.Listener {
+	return func(event msg.Event) {
+		if c.Listener != nil {
+			c.Listener(event)
+		}
+		c.OnEvent(event)
+	}
+}
+
+//OnEvent collects activity start/end and validation events
+func (c *reportCollector) OnEvent(event msg.Event) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.done {
+		return
+	}
+	switch value := event.Value().(type) {
+	case *model.Activity:
+		var reportCase = &ReportCase{
+			Task:        value.Task,
+			Service:     value.Service,
+			Action:      value.Action,
+			Description: value.Description,
+			StartTime:   value.StartTime,
+		}
+		if value.MetaTag != nil {
+			reportCase.TagID = value.TagID
+		}
+		c.cases[value] = reportCase
+		c.active = append(c.active, reportCase)
+		c.report.Cases = append(c.report.Cases, reportCase)
+	case *model.ActivityEndEvent:
+		activity, ok := value.Response.(*model.Activity)
+		if !ok {
+			return
+		}
+		reportCase, ok := c.cases[activity]
+		if !ok {
+			return
+		}
+		delete(c.cases, activity)
+		reportCase.ElapsedMs = int(time.Since(reportCase.StartTime) / time.Millisecond)
+		reportCase.Error = activity.Error
+		for i := len(c.active) - 1; i >= 0; i-- {
+			if c.active[i] == reportCase {
+				c.active = append(c.active[:i], c.active[i+1:]...)
+				break
+			}
+		}
+	case asserted:
+		for _, validation := range value.Assertion() {
+			if validation == nil {
+				continue
+			}
+			c.caseFor(validation).addValidation(validation)
+		}
+	}
+}
+
+//caseFor returns running action case, or a new case for validation published outside of an action
+func (c *reportCollector) caseFor(validation *assertly.Validation) *ReportCase {
+	if len(c.active) > 0 {
+		return c.active[len(c.active)-1]
+	}
+	var result = &ReportCase{TagID: validation.TagID, Description: validation.Description, StartTime: time.Now()}
+	c.report.Cases = append(c.report.Cases, result)
+	return result
+}
+
+//complete summarizes report, no events are collected afterwards
+func (c *reportCollector) complete(err error) *Report {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.done = true
+	var report = c.report
+	report.ElapsedMs = int(time.Since(report.StartTime) / time.Millisecond)
+	if err != nil {
+		report.Error = err.Error()
+	}
+	for _, reportCase := range report.Cases {
+		report.Passed += reportCase.Passed
+		report.Failed += reportCase.Failed
+		if reportCase.Error != "" {
+			report.Errors++
+		}
+	}
+	return report
+}
+
+func reportCollectorFor(context *endly.Context) *reportCollector {
+	if !context.Contains(reportCollectorKey) {
+		return nil
+	}
+	var result *reportCollector
+	context.GetInto(reportCollectorKey, &result)
+	return result
+}
+
+//enableReportIfNeeded collects top level workflow activities and validations if report formats were specified
+func (s *Service) enableReportIfNeeded(context *endly.Context, request *RunRequest, process *model.Process) error {
+	if request.Report == "" || reportCollectorFor(context) != nil {
+		return nil
+	}
+	var formats = make([]string, 0)
+	for _, format := range strings.Split(request.Report, ",") {
+		switch format = strings.ToLower(strings.TrimSpace(format)); format {
+		case ReportJUnit, ReportJSON:
+			formats = append(formats, format)
+		case "":
+		default:
+			return fmt.Errorf("unsupported report format: %v, supported: %v,%v", format, ReportJUnit, ReportJSON)
+		}
+	}
+	var directory = request.LogDirectory
+	if directory == "" {
+		directory = defaultLogDirectory
+	}
+	var collector = &reportCollector{
+		Listener:  context.Listener,
+		mux:       &sync.Mutex{},
+		process:   process,
+		formats:   formats,
+		directory: directory,
+		report:    &Report{Workflow: process.Workflow.Name, SessionID: context.SessionID, StartTime: time.Now(), Cases: make([]*ReportCase, 0)},
+		cases:     make(map[*model.Activity]*ReportCase),
+	}
+	context.Listener = collector.AsEventListener()
+	return context.Put(reportCollectorKey, collector)
+}
+
+//completeReport writes top level workflow reports
+func (s *Service) completeReport(context *endly.Context, process *model.Process, err error) {
+	collector := reportCollectorFor(context)
+	if collector == nil || collector.process != process {
+		return
+	}
+	report := collector.complete(err)
+	filenames, e := writeReport(report, collector.directory, collector.formats)
+	if e != nil {
+		context.Publish(msg.NewErrorEvent(fmt.Sprintf("failed to write report: %v", e)))
+		return
+	}
+	for _, filename := range filenames {
+		context.Publish(msg.NewOutputEvent(filename, "report", nil))
+	}
+}
+
+//writeReport writes report in supplied formats, it returns written file names
+func writeReport(report *Report, directory string, formats []string) ([]string, error) {
+	if err := os.MkdirAll(directory, 0744); err != nil {
+		return nil, err
+	}
+	var result = make([]string, 0)
+	for _, format := range formats {
+		var content []byte
+		var err error
+		var filename string
+		switch format {
+		case ReportJUnit:
+			filename = path.Join(directory, report.Workflow+".junit.xml")
+			content, err = report.JUnit()
+		case ReportJSON:
+			filename = path.Join(directory, report.Workflow+".report.json")
+			content, err = json.MarshalIndent(report, "", "  ")
+		}
+		if err == nil {
+			err = ioutil.WriteFile(filename, content, 0644)
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to write %v report, %v", format, err)
+		}
+		result = append(result, filename)
+	}
+	return result, nil
+}
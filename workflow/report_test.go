@@ -0,0 +1,112 @@
+package workflow
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/assertly"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testAssertResponse struct {
+	validations []*assertly.Validation
+}
+
+func (r *testAssertResponse) Assertion() []*assertly.Validation {
+	return r.validations
+}
+
+func TestReportCollector_OnEvent(t *testing.T) {
+	collector := &reportCollector{
+		mux:    &sync.Mutex{},
+		report: &Report{Workflow: "test", StartTime: time.Now()},
+		cases:  make(map[*model.Activity]*ReportCase),
+	}
+	listener := collector.AsEventListener()
+	activity := &model.Activity{MetaTag: &model.MetaTag{TagID: "assert_01"}, Service: "validator", Action: "assert", StartTime: time.Now()}
+	listener(msg.NewEvent(activity))
+	listener(msg.NewEvent(&testAssertResponse{validations: []*assertly.Validation{
+		{PassedCount: 2, FailedCount: 1, Failures: []*assertly.Failure{{Path: "/id", Expected: 1, Actual: 2}}},
+	}}))
+	listener(msg.NewEvent(model.NewActivityEndEvent(activity)))
+	failed := &model.Activity{MetaTag: &model.MetaTag{TagID: "run_01"}, Service: "exec", Action: "run", StartTime: time.Now()}
+	listener(msg.NewEvent(failed))
+	failed.Error = "command failed"
+	listener(msg.NewEvent(model.NewActivityEndEvent(failed)))
+	listener(msg.NewEvent(&model.Activity{MetaTag: &model.MetaTag{}, Service: "workflow", Action: "print", StartTime: time.Now()}))
+
+	report := collector.complete(nil)
+	assert.Equal(t, 2, report.Passed)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Errors)
+	if assert.Equal(t, 3, len(report.Cases)) {
+		assert.Equal(t, []string{"/id: expected: 1, actual: 2"}, report.Cases[0].Failures)
+		assert.Equal(t, "command failed", report.Cases[1].Error)
+	}
+	content, err := report.JUnit()
+	if !assert.Nil(t, err) {
+		return
+	}
+	junit := string(content)
+	assert.True(t, strings.Contains(junit, `<testsuites name="test" tests="2" failures="1" errors="1"`), junit)
+	assert.True(t, strings.Contains(junit, `<testcase name="assert_01" classname="test.validator.assert"`), junit)
+	assert.True(t, strings.Contains(junit, `<failure message="1 failed, 2 passed" type="validation">/id: expected: 1, actual: 2</failure>`), junit)
+	assert.True(t, strings.Contains(junit, `<error message="command failed" type="error">command failed</error>`), junit)
+	assert.False(t, strings.Contains(junit, "workflow.print"), junit)
+}
+
+func TestService_RunWorkflow_Report(t *testing.T) {
+	logDirectory, err := ioutil.TempDir("", "report")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(logDirectory)
+	manager := endly.New()
+	service := New().(*Service)
+	var tasks = []*model.Task{
+		newTestTask("t1", "", "nop", &NopRequest{}),
+		newTestTask("t2", "", "fail", &FailRequest{Message: "test error"}),
+	}
+	for _, task := range tasks {
+		task.Actions[0].TagID = task.Name
+	}
+	workflow := &model.Workflow{
+		Source:       url.NewResource("report.yaml"),
+		AbstractNode: &model.AbstractNode{Name: "report"},
+		TasksNode:    &model.TasksNode{Tasks: tasks},
+	}
+	context := manager.NewContext(nil)
+	_, err = service.runWorkflow(context, &RunRequest{Report: "junit,json", LogDirectory: logDirectory, Tasks: "*", workflow: workflow})
+	assert.NotNil(t, err)
+
+	content, err := ioutil.ReadFile(path.Join(logDirectory, "report.report.json"))
+	if assert.Nil(t, err) {
+		report := &Report{}
+		if assert.Nil(t, json.Unmarshal(content, report)) {
+			assert.Equal(t, "report", report.Workflow)
+			assert.Equal(t, 1, report.Errors)
+			assert.True(t, strings.Contains(report.Error, "test error"))
+			if assert.Equal(t, 2, len(report.Cases)) {
+				assert.Equal(t, "t1", report.Cases[0].TagID)
+				assert.Equal(t, "", report.Cases[0].Error)
+				assert.True(t, strings.Contains(report.Cases[1].Error, "test error"))
+			}
+		}
+	}
+	content, err = ioutil.ReadFile(path.Join(logDirectory, "report.junit.xml"))
+	if assert.Nil(t, err) {
+		assert.True(t, strings.Contains(string(content), `<testcase name="t2" classname="report.workflow.fail"`), string(content))
+	}
+
+	_, err = service.runWorkflow(manager.NewContext(nil), &RunRequest{Report: "html", Tasks: "*", workflow: workflow})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,340 @@
+package workflow
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	validationEventType  = "assertly_Validation"
+	activityEventType    = "model_Activity"
+	activityEndEventType = "model_ActivityEndEvent"
+	maxEventLogLineSize  = 64 * 1024 * 1024
+)
+
+var rerunFilterKey = (*rerunFilter)(nil)
+
+//RerunEvent represents failed actions re-run event
+type RerunEvent struct {
+	Workflow string
+	Source   string
+	Failed   []string
+	Selected []string
+}
+
+//Messages returns messages
+func (e *RerunEvent) Messages() []*msg.Message {
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(e.Workflow, msg.MessageStyleGeneric),
+			msg.NewStyled("rerun", msg.MessageStyleGeneric),
+			msg.NewStyled(fmt.Sprintf("failed: %v, with dependencies: %v", strings.Join(e.Failed, ","), strings.Join(e.Selected, ",")), msg.MessageStyleOutput),
+		),
+	}
+}
+
+//FailedTagIDs returns failed actions TagIDs from JSON report file or from session event log in log directory
+func FailedTagIDs(logDirectory, source string) ([]string, error) {
+	if strings.HasSuffix(source, ".json") {
+		return failedFromReport(source)
+	}
+	if logDirectory == "" {
+		logDirectory = defaultLogDirectory
+	}
+	directory := path.Join(logDirectory, source)
+	if !toolbox.FileExists(directory) {
+		return nil, fmt.Errorf("failed to locate %v session event log: %v", source, directory)
+	}
+	filename := path.Join(directory, EventLogFilename)
+	if toolbox.FileExists(filename) {
+		return failedFromEventLog(filename)
+	}
+	return failedFromEventFiles(directory)
+}
+
+//failedFromReport returns TagIDs of report cases with failed validations or errors
+func failedFromReport(filename string) ([]string, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var report = &Report{}
+	if err = json.Unmarshal(content, report); err != nil {
+		return nil, fmt.Errorf("failed to decode report %v, %v", filename, err)
+	}
+	var failed = newTagIDs()
+	for _, reportCase := range report.Cases {
+		if reportCase.Failed > 0 || reportCase.Error != "" {
+			failed.add(reportCase.TagID)
+		}
+	}
+	return failed.items, nil
+}
+
+//failedFromEventLog returns TagIDs of failed validations and actions from ndjson event log
+func failedFromEventLog(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	var failed = newTagIDs()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventLogLineSize)
+	for scanner.Scan() {
+		var entry = &EventLogEntry{}
+		if err = json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, fmt.Errorf("failed to decode %v entry: %v", filename, err)
+		}
+		failed.add(failedTagID(entry.Type, entry.Payload, entry.TagID))
+	}
+	return failed.items, scanner.Err()
+}
+
+//failedFromEventFiles returns TagIDs of failed validations and actions from JSON file per event log,
+//validation TagID defaults to activity TagID logged in the same directory
+func failedFromEventFiles(directory string) ([]string, error) {
+	var failed = newTagIDs()
+	var activityTagIDs = make(map[string]string)
+	var events = make([]string, 0)
+	err := filepath.Walk(directory, func(filename string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(filename, ".json") {
+			return err
+		}
+		events = append(events, filename)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(events)
+	for _, filename := range events {
+		eventType := eventFileType(filename)
+		if eventType != activityEventType && eventType != validationEventType && eventType != activityEndEventType {
+			continue
+		}
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		var payload interface{}
+		if err = json.Unmarshal(content, &payload); err != nil {
+			return nil, fmt.Errorf("failed to decode event %v, %v", filename, err)
+		}
+		parent := path.Dir(filename)
+		if eventType == activityEventType {
+			if _, ok := activityTagIDs[parent]; !ok && toolbox.IsMap(payload) {
+				activityTagIDs[parent] = toolbox.AsString(toolbox.AsMap(payload)["TagID"])
+			}
+			continue
+		}
+		failed.add(failedTagID(eventType, payload, activityTagIDs[parent]))
+	}
+	return failed.items, nil
+}
+
+//eventFileType returns event type from event file name, i.e. 0003_assertly_Validation.json
+func eventFileType(filename string) string {
+	name := strings.TrimSuffix(path.Base(filename), ".json")
+	if index := strings.Index(name, "_"); index != -1 {
+		return name[index+1:]
+	}
+	return name
+}
+
+//failedTagID returns TagID of failed validation or action event, or empty string
+func failedTagID(eventType string, payload interface{}, defaultTagID string) string {
+	if !toolbox.IsMap(payload) {
+		return ""
+	}
+	aMap := toolbox.AsMap(payload)
+	switch eventType {
+	case validationEventType:
+		if toolbox.AsInt(aMap["FailedCount"]) == 0 {
+			return ""
+		}
+	case activityEndEventType:
+		if !toolbox.IsMap(aMap["Response"]) {
+			return ""
+		}
+		aMap = toolbox.AsMap(aMap["Response"])
+		if value, ok := aMap["Error"]; !ok || value == nil || toolbox.AsString(value) == "" {
+			return ""
+		}
+	default:
+		return ""
+	}
+	if tagID, ok := aMap["TagID"]; ok && tagID != nil && toolbox.AsString(tagID) != "" {
+		return toolbox.AsString(tagID)
+	}
+	return defaultTagID
+}
+
+//tagIDs represents unique TagIDs in insertion order
+type tagIDs struct {
+	items []string
+	index map[string]bool
+}
+
+func (t *tagIDs) add(tagID string) {
+	if tagID == "" || t.index[tagID] {
+		return
+	}
+	t.index[tagID] = true
+	t.items = append(t.items, tagID)
+}
+
+func newTagIDs() *tagIDs {
+	return &tagIDs{items: make([]string, 0), index: make(map[string]bool)}
+}
+
+//rerunFilter selects previously failed actions with their dependencies
+type rerunFilter struct {
+	mux      *sync.Mutex
+	source   string
+	failed   []string
+	selected map[string]bool
+	expanded map[*model.Workflow]bool
+}
+
+//expand selects workflow actions dependencies, dependency is action TagID or name
+func (f *rerunFilter) expand(context *endly.Context, workflow *model.Workflow) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if f.expanded[workflow] {
+		return
+	}
+	f.expanded[workflow] = true
+	var actions = make([]*model.Action, 0)
+	collectActions(workflow.TasksNode, &actions)
+	var byReference = make(map[string][]*model.Action)
+	for _, action := range actions {
+		byReference[action.TagID] = append(byReference[action.TagID], action)
+		if action.Name != "" && action.Name != action.TagID {
+			byReference[action.Name] = append(byReference[action.Name], action)
+		}
+	}
+	var pending = make([]*model.Action, 0)
+	for _, action := range actions {
+		if f.selected[action.TagID] {
+			pending = append(pending, action)
+		}
+	}
+	for len(pending) > 0 {
+		action := pending[0]
+		pending = pending[1:]
+		for _, reference := range action.DependsOn {
+			dependencies, ok := byReference[reference]
+			if !ok {
+				context.Publish(msg.NewOutputEvent(fmt.Sprintf("%v: unknown dependency: %v", action.TagID, reference), "warning", nil))
+				continue
+			}
+			for _, dependency := range dependencies {
+				if !f.selected[dependency.TagID] {
+					f.selected[dependency.TagID] = true
+					pending = append(pending, dependency)
+				}
+			}
+		}
+	}
+}
+
+//isContainer returns true if action runs nested workflow, it runs so that nested failed actions can be re-run
+func isContainer(action *model.Action) bool {
+	return action.ServiceRequest != nil && action.Service == ServiceID && action.Action == "run"
+}
+
+func (f *rerunFilter) selects(action *model.Action) bool {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.selected[action.TagID] || isContainer(action)
+}
+
+//skipAction returns true if action neither failed in previous run nor is required by failed action
+func (f *rerunFilter) skipAction(context *endly.Context, process *model.Process, action *model.Action) bool {
+	if f == nil {
+		return false
+	}
+	f.expand(context, process.Workflow)
+	return !f.selects(action)
+}
+
+//skipTask returns true if task has no selected action
+func (f *rerunFilter) skipTask(context *endly.Context, process *model.Process, task *model.Task) bool {
+	if f == nil {
+		return false
+	}
+	f.expand(context, process.Workflow)
+	var actions = make([]*model.Action, 0)
+	collectActions(&model.TasksNode{Tasks: []*model.Task{task}}, &actions)
+	for _, action := range actions {
+		if f.selects(action) {
+			return false
+		}
+	}
+	return true
+}
+
+//collectActions collects tasks node actions recursively
+func collectActions(node *model.TasksNode, actions *[]*model.Action) {
+	if node == nil {
+		return
+	}
+	for _, task := range node.Tasks {
+		*actions = append(*actions, task.Actions...)
+		collectActions(task.TasksNode, actions)
+	}
+}
+
+func rerunFilterFor(context *endly.Context) *rerunFilter {
+	if !context.Contains(rerunFilterKey) {
+		return nil
+	}
+	var result *rerunFilter
+	context.GetInto(rerunFilterKey, &result)
+	return result
+}
+
+//enableRerunIfNeeded selects previous run failed actions with their dependencies, returned function stops filtering
+func (s *Service) enableRerunIfNeeded(context *endly.Context, request *RunRequest, process *model.Process) (func(), error) {
+	var release = func() {}
+	if request.RerunFailed == "" || rerunFilterFor(context) != nil {
+		return release, nil
+	}
+	failed, err := FailedTagIDs(request.LogDirectory, request.RerunFailed)
+	if err != nil {
+		return release, err
+	}
+	var filter = &rerunFilter{
+		mux:      &sync.Mutex{},
+		source:   request.RerunFailed,
+		failed:   failed,
+		selected: make(map[string]bool),
+		expanded: make(map[*model.Workflow]bool),
+	}
+	for _, tagID := range failed {
+		filter.selected[tagID] = true
+	}
+	filter.expand(context, process.Workflow)
+	var selected = make([]string, 0)
+	for tagID := range filter.selected {
+		selected = append(selected, tagID)
+	}
+	sort.Strings(selected)
+	context.Publish(&RerunEvent{Workflow: process.Workflow.Name, Source: request.RerunFailed, Failed: failed, Selected: selected})
+	release = func() {
+		context.Remove(rerunFilterKey)
+	}
+	return release, context.Put(rerunFilterKey, filter)
+}
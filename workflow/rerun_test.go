@@ -0,0 +1,123 @@
+package workflow
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox/url"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"testing"
+)
+
+func newRerunTestWorkflow() *model.Workflow {
+	var tasks = []*model.Task{
+		newTestTask("setup", "", "nop", &NopRequest{}),
+		newTestTask("seed", "", "nop", &NopRequest{}),
+		newTestTask("case1", "", "nop", &NopRequest{}),
+		newTestTask("case2", "", "nop", &NopRequest{}),
+	}
+	for _, task := range tasks {
+		task.Actions[0].TagID = task.Name
+	}
+	tasks[1].Actions[0].DependsOn = []string{"setup"}
+	tasks[3].Actions[0].DependsOn = []string{"seed"}
+	return &model.Workflow{
+		Source:       url.NewResource("rerun.yaml"),
+		AbstractNode: &model.AbstractNode{Name: "rerun"},
+		TasksNode:    &model.TasksNode{Tasks: tasks},
+	}
+}
+
+func TestFailedTagIDs(t *testing.T) {
+	logDirectory, err := ioutil.TempDir("", "rerun")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(logDirectory)
+
+	report := &Report{Cases: []*ReportCase{
+		{TagID: "case1", Failed: 1},
+		{TagID: "case2", Passed: 2},
+		{TagID: "case3", Error: "test error"},
+	}}
+	content, _ := json.Marshal(report)
+	reportFile := path.Join(logDirectory, "rerun.report.json")
+	_ = ioutil.WriteFile(reportFile, content, 0644)
+
+	eventLog := path.Join(logDirectory, "s1", EventLogFilename)
+	_ = os.MkdirAll(path.Dir(eventLog), 0755)
+	var entries = []*EventLogEntry{
+		{TagID: "case1", Type: validationEventType, Payload: map[string]interface{}{"FailedCount": 0}},
+		{TagID: "case2", Type: validationEventType, Payload: map[string]interface{}{"FailedCount": 2}},
+		{Type: activityEndEventType, Payload: map[string]interface{}{"Response": map[string]interface{}{"TagID": "case3", "Error": "test error"}}},
+		{Type: activityEndEventType, Payload: map[string]interface{}{"Response": map[string]interface{}{"TagID": "case4"}}},
+	}
+	var lines = make([]byte, 0)
+	for _, entry := range entries {
+		line, _ := json.Marshal(entry)
+		lines = append(append(lines, line...), '\n')
+	}
+	_ = ioutil.WriteFile(eventLog, lines, 0644)
+
+	activityDir := path.Join(logDirectory, "s2", "rerun", "case1")
+	_ = os.MkdirAll(activityDir, 0755)
+	_ = ioutil.WriteFile(path.Join(activityDir, "0001_model_Activity.json"), []byte(`{"TagID":"case1"}`), 0644)
+	_ = ioutil.WriteFile(path.Join(activityDir, "0002_assertly_Validation.json"), []byte(`{"FailedCount":1}`), 0644)
+
+	var useCases = []struct {
+		description string
+		source      string
+		expect      []string
+		hasError    bool
+	}{
+		{description: "report", source: reportFile, expect: []string{"case1", "case3"}},
+		{description: "ndjson event log", source: "s1", expect: []string{"case2", "case3"}},
+		{description: "event files", source: "s2", expect: []string{"case1"}},
+		{description: "unknown session", source: "s3", hasError: true},
+	}
+	for _, useCase := range useCases {
+		failed, err := FailedTagIDs(logDirectory, useCase.source)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.expect, failed, useCase.description)
+	}
+}
+
+func TestService_RunWorkflow_RerunFailed(t *testing.T) {
+	logDirectory, err := ioutil.TempDir("", "rerun")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(logDirectory)
+	reportFile := path.Join(logDirectory, "rerun.report.json")
+	content, _ := json.Marshal(&Report{Cases: []*ReportCase{{TagID: "case2", Failed: 1}}})
+	_ = ioutil.WriteFile(reportFile, content, 0644)
+
+	manager := endly.New()
+	service := New().(*Service)
+	var mux = &sync.Mutex{}
+	var executed = make([]string, 0)
+	context := manager.NewContext(nil)
+	context.SetListener(func(event msg.Event) {
+		if activity, ok := event.Value().(*model.Activity); ok {
+			mux.Lock()
+			executed = append(executed, activity.TagID)
+			mux.Unlock()
+		}
+	})
+	request := &RunRequest{RerunFailed: reportFile, SharedState: true, Tasks: "*", workflow: newRerunTestWorkflow()}
+	_, err = service.runWorkflow(context, request)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"setup", "seed", "case2"}, executed)
+	assert.Nil(t, rerunFilterFor(context))
+}
@@ -0,0 +1,104 @@
+package workflow
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+//LogRetention represents per session event log directories retention policy
+type LogRetention struct {
+	MaxSizeMb   int `description:"max total size of all session log directories in MB, the oldest sessions are removed first, 0 - unlimited"`
+	MaxAgeHours int `description:"max age of session log directory in hours, 0 - unlimited"`
+	MaxSessions int `description:"max number of session log directories to keep, 0 - unlimited"`
+}
+
+//logEntry represents session log directory or event log segment
+type logEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+//IsEnabled returns true if any retention limit is set
+func (r *LogRetention) IsEnabled() bool {
+	return r != nil && (r.MaxSizeMb > 0 || r.MaxAgeHours > 0 || r.MaxSessions > 0)
+}
+
+//Apply removes session log directories exceeding retention limits, excluded session directory is never removed
+func (r *LogRetention) Apply(directory string, excluded string) ([]string, error) {
+	if !r.IsEnabled() {
+		return make([]string, 0), nil
+	}
+	sessions, err := listLogEntries(directory, func(file os.FileInfo) bool {
+		return file.IsDir() && file.Name() != excluded
+	})
+	if err != nil {
+		return make([]string, 0), err
+	}
+	return r.remove(sessions)
+}
+
+//remove removes entries exceeding retention limits, the oldest entries are removed first
+func (r *LogRetention) remove(entries []*logEntry) ([]string, error) {
+	var removed = make([]string, 0)
+	//newest first, entry names are sortable for entries modified at the same time
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].modTime.Equal(entries[j].modTime) {
+			return entries[i].path > entries[j].path
+		}
+		return entries[i].modTime.After(entries[j].modTime)
+	})
+	var totalSize int64
+	var maxSize = int64(r.MaxSizeMb) * 1024 * 1024
+	var now = time.Now()
+	for i, entry := range entries {
+		totalSize += entry.size
+		expired := r.MaxAgeHours > 0 && now.Sub(entry.modTime) > time.Duration(r.MaxAgeHours)*time.Hour
+		tooMany := r.MaxSessions > 0 && i >= r.MaxSessions
+		tooBig := maxSize > 0 && totalSize > maxSize
+		if !(expired || tooMany || tooBig) {
+			continue
+		}
+		if err := os.RemoveAll(entry.path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, entry.path)
+	}
+	return removed, nil
+}
+
+//listLogEntries returns matching directory entries with their total size and the latest modification time
+func listLogEntries(directory string, matches func(file os.FileInfo) bool) ([]*logEntry, error) {
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var result = make([]*logEntry, 0)
+	for _, file := range files {
+		if !matches(file) {
+			continue
+		}
+		entry := &logEntry{path: path.Join(directory, file.Name()), modTime: file.ModTime()}
+		_ = filepath.Walk(entry.path, func(_ string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info.ModTime().After(entry.modTime) {
+				entry.modTime = info.ModTime()
+			}
+			if !info.IsDir() {
+				entry.size += info.Size()
+			}
+			return nil
+		})
+		result = append(result, entry)
+	}
+	return result, nil
+}
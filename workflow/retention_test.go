@@ -0,0 +1,67 @@
+package workflow_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/workflow"
+)
+
+func TestLogRetention_Apply(t *testing.T) {
+	baseDirectory, err := ioutil.TempDir("", "endly_log_retention")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(baseDirectory)
+	var now = time.Now()
+	for i, session := range []string{"s1", "s2", "s3", "current"} {
+		sessionDirectory := path.Join(baseDirectory, session, "000_main")
+		_ = os.MkdirAll(sessionDirectory, 0744)
+		filename := path.Join(sessionDirectory, "0001_event.json")
+		_ = ioutil.WriteFile(filename, []byte(strings.Repeat("x", 1024*600)), 0644)
+		modTime := now.Add(-time.Duration(4-i)*time.Hour + 30*time.Minute)
+		_ = os.Chtimes(filename, modTime, modTime)
+		_ = os.Chtimes(sessionDirectory, modTime, modTime)
+		_ = os.Chtimes(path.Join(baseDirectory, session), modTime, modTime)
+	}
+
+	var useCases = []struct {
+		description string
+		retention   *workflow.LogRetention
+		expect      []string
+	}{
+		{
+			description: "disabled retention",
+			expect:      []string{"s1", "s2", "s3", "current"},
+		},
+		{
+			description: "max age",
+			retention:   &workflow.LogRetention{MaxAgeHours: 3},
+			expect:      []string{"s2", "s3", "current"},
+		},
+		{
+			description: "max size",
+			retention:   &workflow.LogRetention{MaxSizeMb: 1},
+			expect:      []string{"s3", "current"},
+		},
+	}
+
+	for _, useCase := range useCases {
+		_, err := useCase.retention.Apply(baseDirectory, "current")
+		assert.Nil(t, err, useCase.description)
+		files, _ := ioutil.ReadDir(baseDirectory)
+		var actual = make(map[string]bool)
+		for _, file := range files {
+			actual[file.Name()] = true
+		}
+		assert.Equal(t, len(useCase.expect), len(actual), useCase.description)
+		for _, expect := range useCase.expect {
+			assert.True(t, actual[expect], useCase.description+" "+expect)
+		}
+	}
+}
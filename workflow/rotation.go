@@ -0,0 +1,133 @@
+package workflow
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+//segmentPrefix represents rotated event log segment name prefix
+const segmentPrefix = "segment_"
+
+//LogRotation represents session event log size based rotation policy
+type LogRotation struct {
+	MaxSegmentSizeMb int  `description:"max size of event files written to session log directory in MB, once exceeded they are rolled into a segment, 0 - no rotation"`
+	Compress         bool `description:"flag to gzip rolled segment as segment_NNNN.tar.gz, otherwise segment_NNNN directory is used"`
+	MaxSegments      int  `description:"max number of rolled segments to keep per session, the oldest are removed first, 0 - unlimited"`
+}
+
+//IsEnabled returns true if rotation is enabled
+func (r *LogRotation) IsEnabled() bool {
+	return r != nil && r.MaxSegmentSizeMb > 0
+}
+
+//segmentLimit returns segment size limit in bytes
+func (r *LogRotation) segmentLimit() int64 {
+	if !r.IsEnabled() {
+		return 0
+	}
+	return int64(r.MaxSegmentSizeMb) * 1024 * 1024
+}
+
+//rollSegment moves all event files from directory into a new segment, it returns segment location
+func (r *LogRotation) rollSegment(directory string, index int) (string, error) {
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return "", err
+	}
+	var entries = make([]string, 0)
+	for _, file := range files {
+		if !strings.HasPrefix(file.Name(), segmentPrefix) {
+			entries = append(entries, file.Name())
+		}
+	}
+	var segment = path.Join(directory, fmt.Sprintf("%v%04d", segmentPrefix, index))
+	if r.Compress {
+		segment += ".tar.gz"
+		if err = archiveEntries(directory, entries, segment); err != nil {
+			return "", err
+		}
+		for _, entry := range entries {
+			if err = os.RemoveAll(path.Join(directory, entry)); err != nil {
+				return "", err
+			}
+		}
+	} else {
+		if err = os.MkdirAll(segment, 0744); err != nil {
+			return "", err
+		}
+		for _, entry := range entries {
+			if err = os.Rename(path.Join(directory, entry), path.Join(segment, entry)); err != nil {
+				return "", err
+			}
+		}
+	}
+	return segment, r.removeOldSegments(directory)
+}
+
+//removeOldSegments removes the oldest segments exceeding max segments with session log retention
+func (r *LogRotation) removeOldSegments(directory string) error {
+	if r.MaxSegments <= 0 {
+		return nil
+	}
+	segments, err := listLogEntries(directory, func(file os.FileInfo) bool {
+		return strings.HasPrefix(file.Name(), segmentPrefix)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = (&LogRetention{MaxSessions: r.MaxSegments}).remove(segments)
+	return err
+}
+
+//archiveEntries writes supplied directory entries to gzipped tar archive
+func archiveEntries(directory string, entries []string, archive string) (err error) {
+	file, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := file.Close(); err == nil {
+			err = e
+		}
+	}()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		err = filepath.Walk(path.Join(directory, entry), func(filename string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			if header.Name, err = filepath.Rel(directory, filename); err != nil {
+				return err
+			}
+			if err = tarWriter.WriteHeader(header); err != nil || info.IsDir() {
+				return err
+			}
+			source, err := os.Open(filename)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = source.Close() }()
+			_, err = io.Copy(tarWriter, source)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err = tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
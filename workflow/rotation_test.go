@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestLogger_Rotation(t *testing.T) {
+	var useCases = []struct {
+		description string
+		rotation    *LogRotation
+		expect      []string
+	}{
+		{
+			description: "segment directories",
+			rotation:    &LogRotation{MaxSegmentSizeMb: 1},
+			expect:      []string{"001_build", "segment_0001", "segment_0002"},
+		},
+		{
+			description: "compressed segments with max segments",
+			rotation:    &LogRotation{MaxSegmentSizeMb: 1, Compress: true, MaxSegments: 1},
+			expect:      []string{"001_build", "segment_0002.tar.gz"},
+		},
+	}
+	for _, useCase := range useCases {
+		directory, err := ioutil.TempDir("", "endly_log_rotation")
+		if !assert.Nil(t, err) {
+			return
+		}
+		logger := NewLogger(directory, nil)
+		logger.rotation = useCase.rotation
+		for i := 0; i < 2; i++ {
+			logger.OnEvent(msg.NewEvent(&model.Activity{MetaTag: &model.MetaTag{TagID: "build"}, Service: "exec", Action: "run"}))
+			logger.OnEvent(msg.NewEvent(model.NewActivityEndEvent(&model.Activity{})))
+			logger.rotateIfNeeded(2 * 1024 * 1024) //emulate large events
+		}
+		logger.OnEvent(msg.NewEvent(msg.NewOutputEvent("done", "test", nil)))
+
+		files, _ := ioutil.ReadDir(directory)
+		var actual = make([]string, 0)
+		for _, file := range files {
+			actual = append(actual, file.Name())
+		}
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+		if useCase.rotation.Compress {
+			entries := readArchive(t, path.Join(directory, useCase.expect[1]))
+			assert.True(t, len(entries) > 0, useCase.description)
+			for _, entry := range entries {
+				assert.True(t, strings.HasPrefix(entry, "00"), entry)
+			}
+		} else {
+			files, _ = ioutil.ReadDir(path.Join(directory, "segment_0001"))
+			assert.Equal(t, 1, len(files), useCase.description)
+		}
+		_ = os.RemoveAll(directory)
+	}
+}
+
+func readArchive(t *testing.T, filename string) []string {
+	var result = make([]string, 0)
+	file, err := os.Open(filename)
+	if !assert.Nil(t, err) {
+		return result
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if !assert.Nil(t, err) {
+		return result
+	}
+	reader := tar.NewReader(gzipReader)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return result
+		}
+		if !assert.Nil(t, err) {
+			return result
+		}
+		result = append(result, header.Name)
+	}
+}
@@ -1,25 +1,37 @@
 package workflow
 
 import (
+	stdcontext "context"
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/viant/endly"
 	"github.com/viant/endly/model"
 	"github.com/viant/endly/model/criteria"
 	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/model/ratelimit"
+	"github.com/viant/endly/model/tracing"
+	"github.com/viant/endly/system/workspace"
+	"github.com/viant/endly/util"
 	"github.com/viant/neatly"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
+	"github.com/viant/toolbox/url"
+	"go.opentelemetry.io/otel/attribute"
 	"log"
 	"os"
 	"path"
 	"strings"
 	"sync"
+	"time"
 )
 
 const (
 	//ServiceID represents workflow Service id
 	ServiceID = "workflow"
+	//maxTaskJumps protects against infinite task branching loop
+	maxTaskJumps = 1000
+	//runTimeoutGracePeriod is how long timed out run is given to stop before timeout error is returned
+	runTimeoutGracePeriod = time.Second
 )
 
 //Service represents a workflow service.
@@ -27,7 +39,9 @@ type Service struct {
 	*endly.AbstractService
 	Dao       *Dao
 	registry  map[string]*model.Workflow
+	macros    map[string]*model.Workflow
 	converter *toolbox.Converter
+	debugger  *debugger
 }
 
 func (s *Service) registerWorkflow(request *RegisterRequest) (*RegisterResponse, error) {
@@ -70,12 +84,24 @@ func (s *Service) addVariableEvent(name string, variables model.Variables, conte
 	if len(variables) == 0 {
 		return
 	}
+	context.SecretValues().Add(variables.SecretValues(out)...)
 	context.Publish(model.NewModifiedStateEvent(variables, in, out))
 }
 
 func (s *Service) loadWorkflowIfNeeded(context *endly.Context, request *RunRequest) (err error) {
 	if !s.HasWorkflow(request.Name) {
-		resource := GetResource(s.Dao, context.State(), request.URL)
+		var resource *url.Resource
+		if IsGitSourceURL(request.URL) {
+			source, err := NewGitSource(request.URL)
+			if err != nil {
+				return err
+			}
+			if resource, _, err = source.Fetch(context); err != nil {
+				return err
+			}
+		} else {
+			resource = GetResource(s.Dao, context.State(), request.URL)
+		}
 		if resource == nil {
 			return fmt.Errorf("unable to locate workflow: %v, %v", request.Name, request.URL)
 		}
@@ -97,7 +123,14 @@ func (s *Service) runAction(context *endly.Context, action *model.Action, proces
 	s.Mutex().Lock()
 	process.State.Put("index", action.TagIndex)
 	s.Mutex().Unlock()
-
+	defer s.traceActionState(context, process, action)()
+	endSpan := tracing.Start(context, "action "+action.Service+"."+action.Action,
+		attribute.String("endly.workflow", process.Workflow.Name),
+		attribute.String("endly.task", process.Task.Name),
+		attribute.String("endly.tag_id", action.TagID),
+		attribute.String("endly.service", action.Service),
+		attribute.String("endly.action", action.Action))
+	defer func() { endSpan(err) }()
 	defer func() {
 		var resultKey = action.Name
 		if resultKey == "" {
@@ -106,14 +139,16 @@ func (s *Service) runAction(context *endly.Context, action *model.Action, proces
 		if err != nil {
 			err = fmt.Errorf("%v: %v", action.TagID, err)
 		} else if len(response) > 0 {
-			state.Put(resultKey, response)
 			var variables = model.Variables{
 				{
 					Name:  resultKey,
 					Value: response,
 				},
 			}
-			_ = variables.Apply(state, state)
+			_ = context.SafeState().Update(func(state data.Map) error {
+				state.Put(resultKey, response)
+				return variables.Apply(state, state)
+			})
 			context.Publish(model.NewModifiedStateEvent(variables, state, state))
 		}
 	}()
@@ -122,37 +157,105 @@ func (s *Service) runAction(context *endly.Context, action *model.Action, proces
 		process.Push(activity)
 		startEvent := s.Begin(context, activity)
 		defer s.End(context)(startEvent, model.NewActivityEndEvent(activity))
+		defer func() {
+			if err != nil {
+				activity.Error = err.Error()
+			}
+		}()
 		defer process.Pop()
+		defer func() {
+			s.auditAction(context, activity, request, err)
+		}()
 
 		requestMap := toolbox.AsMap(activity.Request)
-		if err = runWithoutSelfIfNeeded(process, action, state, func() error {
+		if err = expandGroupSources(context, requestMap); err != nil {
+			return nil, nil, err
+		}
+		targetKey, group, URIPath, onGroup := groupTarget(requestMap)
+		if onGroup {
+			request = requestMap
+		} else if err = runWithoutSelfIfNeeded(process, action, state, func() error {
 			request, err = context.AsRequest(activity.Service, activity.Action, requestMap)
 			return err
 		}); err != nil {
 			return nil, nil, err
 		}
-		err = endly.Run(context, request, activity.ServiceResponse)
+		if err = s.debugger.wait(context, activity, request); err != nil {
+			return nil, nil, err
+		}
+		response, err = s.runCached(context, action, activity, request, func() (map[string]interface{}, error) {
+			if onGroup {
+				response, err := s.runOnGroup(context, activity, requestMap, targetKey, group, URIPath)
+				if err == nil {
+					err = s.checkFailFast(context)
+				}
+				activity.Response = response
+				return response, err
+			}
+			err := endly.Run(context, request, activity.ServiceResponse)
+			if err == nil {
+				err = s.checkFailFast(context)
+			}
+			if err != nil {
+				return nil, err
+			}
+			_ = toolbox.DefaultConverter.AssignConverted(&activity.Response, activity.ServiceResponse.Response)
+			if runResponse, ok := activity.ServiceResponse.Response.(*RunResponse); ok {
+				return runResponse.Data, nil
+			}
+			return activity.Response, nil
+		})
 		if err != nil {
 			return nil, nil, err
 		}
-
-		_ = toolbox.DefaultConverter.AssignConverted(&activity.Response, activity.ServiceResponse.Response)
-		response = activity.Response
-		if runResponse, ok := activity.ServiceResponse.Response.(*RunResponse); ok {
-			response = runResponse.Data
+		if len(activity.Response) == 0 {
+			activity.Response = response
 		}
 		return response, state, err
 	})
 	return response, err
 }
 
+//checkEndlyVersion warns if running endly version does not meet workflow minimum required version
+func (s *Service) checkEndlyVersion(context *endly.Context, workflow *model.Workflow) {
+	if workflow.Endly == "" {
+		return
+	}
+	manager, err := context.Manager()
+	if err != nil {
+		return
+	}
+	if current := manager.Version(); current != "" && util.CompareVersion(current, workflow.Endly) < 0 {
+		context.Publish(msg.NewOutputEvent(fmt.Sprintf("workflow %v requires endly %v or newer, but running %v", workflow.Name, workflow.Endly, current), "warning", nil))
+	}
+}
+
+func (s *Service) validateStateContract(context *endly.Context, process *model.Process) error {
+	if process.Workflow == nil || len(process.Workflow.Contract) == 0 {
+		return nil
+	}
+	if err := process.Workflow.Contract.Validate(context.State(), process.Completed); err != nil {
+		return fmt.Errorf("%v.%v: %v", process.Workflow.Name, process.Task.Name, err)
+	}
+	return nil
+}
+
 func (s *Service) runTask(context *endly.Context, process *model.Process, task *model.Task) (data.Map, error) {
 	process.SetTask(task)
 	var result = data.NewMap()
 	var state = context.State()
+	if err := s.validateStateContract(context, process); err != nil {
+		return nil, err
+	}
+	publishStateDiff := s.trackStateDiff(context, task.Name)
+	endSpan := tracing.Start(context, "task "+task.Name,
+		attribute.String("endly.workflow", process.Workflow.Name),
+		attribute.String("endly.task", task.Name))
+	defer metricsCollectorFor(context).startTask(process, task)()
+	defer process.BeginTask()()
 
 	asyncGroup := &sync.WaitGroup{}
-	var asyncError error
+	asyncResult := newAsyncResults()
 	asyncActions := task.AsyncActions()
 
 	err := s.runNode(context, "task", process, task.AbstractNode, func(context *endly.Context, process *model.Process) (in, out data.Map, err error) {
@@ -162,9 +265,15 @@ func (s *Service) runTask(context *endly.Context, process *model.Process, task *
 			}
 		}
 		if len(asyncActions) > 0 {
-			s.runAsyncActions(context, process, task, asyncActions, asyncGroup, &asyncError)
+			s.runAsyncActions(context, process, task, asyncActions, asyncGroup, asyncResult)
 		}
 		for i := 0; i < len(task.Actions); i++ {
+			if process.IsTerminated() {
+				break
+			}
+			if err := context.Err(); err != nil {
+				return nil, nil, err
+			}
 			action := task.Actions[i]
 			if action.Async {
 				continue
@@ -178,9 +287,7 @@ func (s *Service) runTask(context *endly.Context, process *model.Process, task *
 					if err != nil {
 						return nil, err
 					}
-					if len(response) > 0 {
-						result[action.ID()] = response
-					}
+					putActionResult(result, action, response)
 					return response, nil
 				}
 			}
@@ -194,11 +301,17 @@ func (s *Service) runTask(context *endly.Context, process *model.Process, task *
 				}
 				continue
 			}
-			var extractable = make(map[string]interface{})
-			err = action.Repeater.Run(s.AbstractService, "action", context, handler(task.Actions[i]), extractable)
+			if checkpointTrackerFor(context).skipAction(process, action) || rerunFilterFor(context).skipAction(context, process, action) {
+				continue
+			}
+			err = s.runLoop(context, process, action, func() error {
+				var extractable = make(map[string]interface{})
+				return action.Repeater.Run(s.AbstractService, "action", context, handler(action), extractable)
+			})
 			if err != nil {
 				return nil, nil, err
 			}
+			s.checkpointAction(context, process, action)
 		}
 
 		return state, result, nil
@@ -210,57 +323,68 @@ func (s *Service) runTask(context *endly.Context, process *model.Process, task *
 			asyncGroup.Wait()
 			return nil
 		})
-		if err == nil && asyncError != nil {
-			err = asyncError
+		if err == nil {
+			err = asyncResult.err()
 		}
+		asyncResult.apply(result)
 	}
-	state.Apply(result)
+	context.SafeState().Apply(result)
+	publishStateDiff()
+	if err == nil {
+		process.Completed[task.Name] = true
+		err = s.validateStateContract(context, process)
+	}
+	if err == nil {
+		s.checkpointTask(context, process, task)
+	}
+	endSpan(err)
 	return result, err
 }
 
-func (s *Service) runAsyncAction(parent, context *endly.Context, process *model.Process, action *model.Action, group *sync.WaitGroup) error {
-	defer group.Done()
+func (s *Service) runAsyncAction(context *endly.Context, process *model.Process, action *model.Action) (data.Map, error) {
+	listener := context.Listener
 	events := context.MakeAsyncSafe()
 	defer func() {
+		if listener == nil {
+			return
+		}
 		for _, event := range events.Events {
-			parent.Publish(event)
+			listener(event)
 		}
 	}()
-	var result = make(map[string]interface{})
+	var result = data.NewMap()
 	var handler = func(action *model.Action) func() (interface{}, error) {
 		return func() (interface{}, error) {
 			var response, err = s.runAction(context, action, process)
 			if err != nil {
 				return nil, err
 			}
-			if len(response) > 0 {
-				result[action.ID()] = response
-			}
+			putActionResult(result, action, response)
 			return response, nil
 		}
 	}
-	var extractable = make(map[string]interface{})
-	err := action.Repeater.Run(s.AbstractService, "action", context, handler(action), extractable)
-	if err != nil {
-		return err
-	}
-	return nil
+	err := s.runLoop(context, process, action, func() error {
+		var extractable = make(map[string]interface{})
+		return action.Repeater.Run(s.AbstractService, "action", context, handler(action), extractable)
+	})
+	return result, err
 }
 
-func (s *Service) runAsyncActions(context *endly.Context, process *model.Process, task *model.Task, asyncAction []*model.Action, group *sync.WaitGroup, asyncError *error) {
+//runAsyncActions runs each async action with its own deep copied context state and forked process, results and errors are collected per action
+func (s *Service) runAsyncActions(context *endly.Context, process *model.Process, task *model.Task, asyncAction []*model.Action, group *sync.WaitGroup, results *asyncResults) {
 	if len(asyncAction) > 0 {
 		group.Add(len(asyncAction))
-		var groupErr error
 		for i := range asyncAction {
 			context.Publish(NewAsyncEvent(asyncAction[i]))
-			go func(action *model.Action, actionContext *endly.Context) {
-				if err := s.runAsyncAction(context, actionContext, process, action, group); err != nil {
-					groupErr = err
+			actionContext, fork := forkProcess(context, process)
+			go func(action *model.Action, actionContext *endly.Context, fork *model.Process) {
+				defer group.Done()
+				result, err := s.runAsyncAction(actionContext, fork, action)
+				if fork.IsTerminated() {
+					process.Terminate()
 				}
-			}(asyncAction[i], context.Clone())
-		}
-		if groupErr != nil {
-			*asyncError = groupErr
+				results.add(action, result, err)
+			}(asyncAction[i], actionContext, fork)
 		}
 	}
 }
@@ -272,18 +396,33 @@ func (s *Service) applyVariables(candidates interface{}, process *model.Process,
 	}
 
 	var out = context.State()
-	err := variables.Apply(in, out)
+	err := context.SafeState().Update(func(state data.Map) error {
+		return variables.Apply(in, state)
+	})
 	s.addVariableEvent("Pipeline", variables, context, in, out)
 	return err
 }
 
 func (s *Service) run(context *endly.Context, request *RunRequest) (response *RunResponse, err error) {
+	runWorkflow := s.runWorkflow
+	if request.Matrix != nil {
+		runWorkflow = s.runMatrix
+	}
+	if Last(context) == nil {
+		started := time.Now()
+		run := runWorkflow
+		runWorkflow = func(context *endly.Context, request *RunRequest) (*RunResponse, error) {
+			response, err := run(context, request)
+			s.runCompletionHooks(context, request, response, err, started)
+			return response, err
+		}
+	}
 	if request.Async {
 		context.Wait.Add(1)
 		go func() {
 			defer context.Publish(NewEndEvent(context.SessionID))
 			defer context.Wait.Done()
-			_, err = s.runWorkflow(context, request)
+			_, err = runWorkflow(context, request)
 			if err != nil {
 				context.Publish(msg.NewErrorEvent(fmt.Sprintf("%v", err)))
 			}
@@ -291,13 +430,36 @@ func (s *Service) run(context *endly.Context, request *RunRequest) (response *Ru
 		return &RunResponse{}, nil
 	}
 	defer context.Publish(NewEndEvent(context.SessionID))
-	return s.runWorkflow(context, request)
+	return runWorkflow(context, request)
+}
+
+//dryRun validates workflow without executing any action, it fails if error level issue was found
+func (s *Service) dryRun(context *endly.Context, request *RunRequest, workflow *model.Workflow, response *RunResponse) (*RunResponse, error) {
+	validation, err := s.validate(context, workflow, request.Params)
+	if err != nil {
+		return nil, err
+	}
+	response.Validation = validation
+	context.Publish(validation)
+	if !validation.Valid {
+		return response, fmt.Errorf("workflow %v is invalid", workflow.Name)
+	}
+	return response, nil
 }
 
 func (s *Service) enableLoggingIfNeeded(context *endly.Context, request *RunRequest) {
 	if request.EnableLogging && !context.HasLogger {
+		if removed, err := request.LogRetention.Apply(request.LogDirectory, context.SessionID); err != nil {
+			log.Printf("failed to apply log retention: %v", err)
+		} else if len(removed) > 0 {
+			context.Publish(NewLogRetentionEvent(removed))
+		}
 		var logDirectory = path.Join(request.LogDirectory, context.SessionID)
 		logger := NewLogger(logDirectory, context.Listener)
+		logger.mask = context.MaskSecrets
+		logger.rotation = request.LogRotation
+		logger.format = request.LogFormat
+		logger.sessionID = context.SessionID
 		context.Listener = logger.AsEventListener()
 	}
 }
@@ -316,6 +478,7 @@ func (s *Service) publishParameters(request *RunRequest, context *endly.Context)
 
 func (s *Service) getWorkflow(context *endly.Context, request *RunRequest) (*model.Workflow, error) {
 	if request.workflow != nil {
+		context.SecretValues().Add(request.workflow.SecretLiterals()...)
 		context.Publish(NewLoadedEvent(request.workflow))
 		return request.workflow, nil
 	}
@@ -327,6 +490,7 @@ func (s *Service) getWorkflow(context *endly.Context, request *RunRequest) (*mod
 	if err != nil {
 		return nil, err
 	}
+	context.SecretValues().Add(workflow.SecretLiterals()...)
 	context.Publish(NewLoadedEvent(workflow))
 	return workflow, err
 }
@@ -338,17 +502,75 @@ func (s *Service) runWorkflow(upstreamContext *endly.Context, request *RunReques
 	}
 
 	s.enableLoggingIfNeeded(upstreamContext, request)
+	if err = s.enableStreamIfNeeded(upstreamContext, request); err != nil {
+		return nil, err
+	}
+	if err = s.enableAuditIfNeeded(upstreamContext, request); err != nil {
+		return nil, err
+	}
+	if err = s.enableStateDiffIfNeeded(upstreamContext, request); err != nil {
+		return nil, err
+	}
 	workflow, err := s.getWorkflow(upstreamContext, request)
 	if err != nil {
 		return nil, err
 	}
+	s.checkEndlyVersion(upstreamContext, workflow)
+	if request.DryRun {
+		return s.dryRun(upstreamContext, request, workflow, response)
+	}
 
 	defer Pop(upstreamContext)
 
 	upstreamProcess := Last(upstreamContext)
+	if upstreamProcess == nil {
+		defer s.debugger.attach(upstreamContext.SessionID, request.Debug)()
+	}
 	process := model.NewProcess(workflow.Source, workflow, upstreamProcess)
 	process.AddTagIDs(strings.Split(request.TagIDs, ",")...)
 	Push(upstreamContext, process)
+	releaseCheckpoint, err := s.enableCheckpointIfNeeded(upstreamContext, request, process)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseCheckpoint()
+	releaseRerun, err := s.enableRerunIfNeeded(upstreamContext, request, process)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseRerun()
+	if err = s.enableReportIfNeeded(upstreamContext, request, process); err != nil {
+		return nil, err
+	}
+	if err = s.enableMetrics(upstreamContext, process); err != nil {
+		return nil, err
+	}
+	if err = s.enableAssertionCollector(upstreamContext, request, process); err != nil {
+		return nil, err
+	}
+	releaseTracing, err := tracing.Enable(upstreamContext, request.Tracing)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseTracing()
+	releaseLimits, err := ratelimit.Enable(upstreamContext, request.RateLimits)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseLimits()
+	releaseWorkspace, err := workspace.Enable(upstreamContext, request.KeepWorkspace)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseWorkspace()
+	if err = s.enableCacheIfNeeded(upstreamContext, request); err != nil {
+		return nil, err
+	}
+	endSpan := tracing.Start(upstreamContext, "workflow "+workflow.Name,
+		attribute.String("endly.workflow", workflow.Name),
+		attribute.String("endly.tasks", request.Tasks),
+		attribute.String("endly.session_id", upstreamContext.SessionID))
+	defer func() { endSpan(err) }()
 
 	process.State = data.NewMap()
 	upstreamState := upstreamContext.State()
@@ -379,6 +601,9 @@ func (s *Service) runWorkflow(upstreamContext *endly.Context, request *RunReques
 
 	params := s.publishParameters(request, context)
 	process.State.Put(paramsStateKey, params)
+	if err = workflow.ValidateInputs(params); err != nil {
+		return nil, err
+	}
 	if len(workflow.Data) > 0 {
 		state := context.State()
 		state.Put(dataStateKey, workflow.Data)
@@ -409,10 +634,22 @@ func (s *Service) runWorkflow(upstreamContext *endly.Context, request *RunReques
 	}
 
 	filteredTasks := workflow.TasksNode.Select(taskSelector)
-	err = s.runNode(context, "workflow", process, workflow.AbstractNode, func(context *endly.Context, process *model.Process) (in, out data.Map, err error) {
-		err = s.runTasks(context, process, filteredTasks)
-		return state, response.Data, err
+	err = s.runWithTimeout(context, "workflow", workflow.Name, request.TimeoutMs, func() error {
+		return s.runNode(context, "workflow", process, workflow.AbstractNode, func(context *endly.Context, process *model.Process) (in, out data.Map, err error) {
+			s.restoreCheckpoint(context, process)
+			err = s.runTasks(context, process, filteredTasks)
+			return state, response.Data, err
+		})
 	})
+	outputs, outputErr := workflow.SelectOutputs(response.Data)
+	response.Data = outputs
+	if err == nil {
+		err = outputErr
+	}
+	s.completeCheckpoint(context, process, err)
+	s.completeReport(context, process, err)
+	s.completeMetrics(context, request, process, response)
+	s.completeAssertions(context, process, response)
 
 	if len(response.Data) > 0 {
 		for k, v := range response.Data {
@@ -422,67 +659,178 @@ func (s *Service) runWorkflow(upstreamContext *endly.Context, request *RunReques
 	return response, err
 }
 
+//runWithTimeout runs supplied function, if timeout is exceeded context is canceled and function is given runTimeoutGracePeriod to stop,
+//then timeout error is returned. Context is only restored once the function stops, so that a hung run that eventually returns
+//never continues with restored context, runs check context before each task and action.
+func (s *Service) runWithTimeout(context *endly.Context, nodeType, name string, timeoutMs int, run func() error) error {
+	if timeoutMs <= 0 {
+		return run()
+	}
+	timeout := time.Duration(timeoutMs) * time.Millisecond
+	restore := context.ScopedTimeout(timeout)
+	done := make(chan error, 1)
+	go func() {
+		done <- run()
+	}()
+	select {
+	case err := <-done:
+		restore()
+		return err
+	case <-context.Done():
+	}
+	err := context.Err()
+	if err == stdcontext.DeadlineExceeded {
+		err = fmt.Errorf("%v %v timed out after %v", nodeType, name, timeout)
+	}
+	select {
+	case <-done:
+		restore()
+	case <-time.After(runTimeoutGracePeriod):
+		go func() {
+			<-done
+			restore()
+		}()
+	}
+	return err
+}
+
 func (s *Service) runNode(context *endly.Context, nodeType string, process *model.Process, node *model.AbstractNode, runHandler func(context *endly.Context, process *model.Process) (in, out data.Map, err error)) error {
 	if !process.CanRun() {
 		return nil
 	}
-	original := context.Logging
-	context.Logging = node.Logging
-	defer func() {
-		context.Logging = original
-	}()
+	defer context.ScopedLogging(node.Logging)()
 	var state = context.State()
 	canRun, err := criteria.Evaluate(context, context.State(), node.When, fmt.Sprintf("%v.When", nodeType), true)
 	if err != nil || !canRun {
 		return err
 	}
-	err = node.Init.Apply(state, state)
+	err = context.SafeState().Update(func(state data.Map) error {
+		return node.Init.Apply(state, state)
+	})
 	s.addVariableEvent(fmt.Sprintf("%v.Init", nodeType), node.Init, context, state, state)
 	if err != nil {
-		return err
+		return nodeVariablesError(nodeType, node, "init", err)
 	}
-	in, out, err := runHandler(context, process)
+	var in, out data.Map
+	err = s.runWithTimeout(context, nodeType, node.Name, node.TimeoutMs, func() (err error) {
+		in, out, err = runHandler(context, process)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 	if len(in) == 0 {
 		in = data.NewMap()
 	}
-	err = node.Post.Apply(in, out)
+	err = context.SafeState().Update(func(state data.Map) error {
+		return node.Post.Apply(in, out)
+	})
 	s.addVariableEvent(fmt.Sprintf("%v.Post", nodeType), node.Post, context, in, out)
 	if err != nil {
-		return err
+		return nodeVariablesError(nodeType, node, "post", err)
 	}
 	s.Sleep(context, node.SleepTimeMs)
 	return nil
 }
 
+//nodeVariablesError returns variables application error with its origin, i.e. task build init: ...
+func nodeVariablesError(nodeType string, node *model.AbstractNode, stage string, err error) error {
+	var origin = nodeType
+	if node.Name != "" {
+		origin += " " + node.Name
+	}
+	return fmt.Errorf("%v %v: %v", origin, stage, err)
+}
+
+//runDeferredTask runs deferred task even if workflow has been terminated, canceled or timed out
 func (s *Service) runDeferredTask(context *endly.Context, process *model.Process, parent *model.TasksNode) error {
 	if parent.DeferredTask == "" {
 		return nil
 	}
-	task, _ := parent.Task(parent.DeferredTask)
-	_, err := s.runTask(context, process, task)
+	task, err := parent.Task(parent.DeferredTask)
+	if err != nil {
+		return err
+	}
+	restoreProcess := process.Release()
+	defer restoreProcess()
+	restoreContext := context.Detach()
+	defer restoreContext()
+	_, err = s.runTask(context, process, task)
 	return err
 }
 
+//putError stores process error in state 'error' and 'errorJSON' keys
+func (s *Service) putError(context *endly.Context, process *model.Process, err error) {
+	process.Error = err.Error()
+	if process.Activity != nil {
+		process.Request = process.Activity.Request
+		process.Response = process.Activity.Response
+		process.TaskName = process.Task.Name
+	}
+	var state = context.State()
+	var processErr = process.AsMap()
+	state.Put("error", processErr)
+	processErr = toolbox.DeleteEmptyKeys(processErr)
+	errorJSON, _ := toolbox.AsIndentJSONText(processErr)
+	state.Put("errorJSON", errorJSON)
+}
+
+//branchTarget returns a task to jump to once supplied task completed, an error handled by OnError branch is cleared
+func (s *Service) branchTarget(context *endly.Context, process *model.Process, task *model.Task, err error) (string, error) {
+	if err != nil {
+		if task.OnError == "" {
+			return "", err
+		}
+		s.putError(context, process, err)
+		return task.OnError, nil
+	}
+	if !process.CanRun() {
+		return "", nil
+	}
+	if task.Switch != nil {
+		if target := task.Switch.Match(getSwitchSource(context, task.Switch.SourceKey)); target != "" {
+			return target, nil
+		}
+	}
+	return task.OnSuccess, nil
+}
+
+//jump moves task iterator to a sibling target task, or schedules non sibling target task
+func (s *Service) jump(context *endly.Context, process *model.Process, tasks *model.TasksNode, target string, failed bool, i *int) error {
+	task, err := process.Workflow.Task(target)
+	if err != nil {
+		return err
+	}
+	if failed && !task.Fail { //Reset workflow fail status by default
+		context.Publish(&msg.ResetError{})
+	}
+	if index := siblingIndex(tasks, target); index != -1 {
+		*i = index - 1
+		return nil
+	}
+	process.Scheduled = task
+	return nil
+}
+
+//siblingIndex returns index of runnable task with supplied name or -1
+func siblingIndex(tasks *model.TasksNode, name string) int {
+	if name == tasks.OnErrorTask || name == tasks.DeferredTask {
+		return -1
+	}
+	for i, task := range tasks.Tasks {
+		if task.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
 func (s *Service) runOnErrorTask(context *endly.Context, process *model.Process, parent *model.TasksNode, err error) error {
 	if parent.OnErrorTask == "" {
 		return err
 	}
 	if err != nil {
-		process.Error = err.Error()
-		if process.Activity != nil {
-			process.Request = process.Activity.Request
-			process.Response = process.Activity.Response
-			process.TaskName = process.Task.Name
-		}
-		var state = context.State()
-		var processErr = process.AsMap()
-		state.Put("error", processErr)
-		processErr = toolbox.DeleteEmptyKeys(processErr)
-		errorJSON, err := toolbox.AsIndentJSONText(processErr)
-		state.Put("errorJSON", errorJSON)
+		s.putError(context, process, err)
 		task, e := parent.Task(parent.OnErrorTask)
 		if e != nil {
 			return fmt.Errorf("failed to catch: %v, %v", err, e)
@@ -503,16 +851,58 @@ func (s *Service) runTasks(context *endly.Context, process *model.Process, tasks
 		e := s.runDeferredTask(context, process, tasks)
 		if err == nil {
 			err = e
+		} else if e != nil {
+			err = fmt.Errorf("%v; deferred %v task failed: %v", err, tasks.DeferredTask, e)
 		}
 	}()
-	for _, task := range tasks.Tasks {
+	var jumps = 0
+	for i := 0; i < len(tasks.Tasks); i++ {
+		task := tasks.Tasks[i]
 		if task.Name == tasks.OnErrorTask || task.Name == tasks.DeferredTask {
 			continue
 		}
 		if process.IsTerminated() {
 			break
 		}
-		if _, err = s.runTask(context, process, task); err != nil {
+		if err = context.Err(); err != nil {
+			return err
+		}
+		var checkpoint, rerun = checkpointTrackerFor(context), rerunFilterFor(context)
+		var skipTask = func(task *model.Task) bool {
+			return checkpoint.skipTask(process, task) || rerun.skipTask(context, process, task)
+		}
+		if task.Group != "" {
+			var group = make([]*model.Task, 0)
+			if !skipTask(task) {
+				group = append(group, task)
+			}
+			for j := i + 1; j < len(tasks.Tasks) && tasks.Tasks[j].Group == task.Group; j++ {
+				if candidate := tasks.Tasks[j]; candidate.Name != tasks.OnErrorTask && candidate.Name != tasks.DeferredTask && !skipTask(candidate) {
+					group = append(group, candidate)
+				}
+				i++
+			}
+			err = s.runTaskGroup(context, process, task.Group, group)
+		} else if skipTask(task) {
+			continue
+		} else {
+			_, err = s.runTask(context, process, task)
+			var target string
+			var failed = err != nil
+			if target, err = s.branchTarget(context, process, task, err); target != "" {
+				if jumps++; jumps > maxTaskJumps {
+					return fmt.Errorf("exceeded max %v task jumps, last from %v to %v", maxTaskJumps, task.Name, target)
+				}
+				if err = s.jump(context, process, tasks, target, failed, &i); err != nil {
+					return err
+				}
+				if process.Scheduled != nil {
+					break
+				}
+				continue
+			}
+		}
+		if err != nil {
 			err = s.runOnErrorTask(context, process, tasks, err)
 		}
 		if err != nil {
@@ -527,6 +917,50 @@ func (s *Service) runTasks(context *endly.Context, process *model.Process, tasks
 	return err
 }
 
+//runTaskGroup runs tasks concurrently, each with forked process and context, then waits for all of them to complete
+func (s *Service) runTaskGroup(context *endly.Context, process *model.Process, group string, tasks []*model.Task) error {
+	var waitGroup = &sync.WaitGroup{}
+	var mux = &sync.Mutex{}
+	var errors = make([]string, 0)
+	var results = make([]data.Map, len(tasks))
+	var forks = make([]*model.Process, len(tasks))
+	var contexts = make([]*endly.Context, len(tasks))
+	for i := range tasks {
+		contexts[i], forks[i] = forkProcess(context, process)
+	}
+	waitGroup.Add(len(tasks))
+	for i := range tasks {
+		go func(i int) {
+			defer waitGroup.Done()
+			events := contexts[i].MakeAsyncSafe()
+			defer func() {
+				for _, event := range events.Events {
+					context.Publish(event)
+				}
+			}()
+			result, err := s.runTask(contexts[i], forks[i], tasks[i])
+			if err != nil {
+				mux.Lock()
+				errors = append(errors, fmt.Sprintf("%v: %v", tasks[i].Name, err))
+				mux.Unlock()
+				return
+			}
+			results[i] = result
+		}(i)
+	}
+	waitGroup.Wait()
+	for i := range tasks {
+		process.Join(forks[i])
+		if len(results[i]) > 0 {
+			context.SafeState().Apply(results[i])
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("group %v failed: %v", group, strings.Join(errors, "; "))
+	}
+	return nil
+}
+
 func buildParamsMap(request *RunRequest, context *endly.Context) data.Map {
 	var params = data.NewMap()
 	var state = context.State()
@@ -734,6 +1168,14 @@ const (
 	workflowServiceGotoExample = `{
 		"Task": "stop"
 	}`
+
+	workflowServiceMacroExample = `{
+		"Name": "deploy",
+		"Params": {
+			"app": "myapp",
+			"port": 8080
+		}
+	}`
 )
 
 func (s *Service) registerRoutes() {
@@ -785,6 +1227,120 @@ func (s *Service) registerRoutes() {
 		},
 	})
 
+	s.AbstractService.Register(&endly.Route{
+		Action: "pause",
+		RequestInfo: &endly.ActionInfo{
+			Description: "suspend running workflow before the next action",
+		},
+		RequestProvider: func() interface{} {
+			return &PauseRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &DebugResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*PauseRequest); ok {
+				return s.debugger.update(req.SessionID, true, 0)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.AbstractService.Register(&endly.Route{
+		Action: "step",
+		RequestInfo: &endly.ActionInfo{
+			Description: "run exactly count actions of paused workflow",
+		},
+		RequestProvider: func() interface{} {
+			return &StepRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &DebugResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*StepRequest); ok {
+				return s.debugger.update(req.SessionID, true, req.Count)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.AbstractService.Register(&endly.Route{
+		Action: "continue",
+		RequestInfo: &endly.ActionInfo{
+			Description: "continue paused workflow",
+		},
+		RequestProvider: func() interface{} {
+			return &ContinueRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &DebugResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ContinueRequest); ok {
+				return s.debugger.update(req.SessionID, false, 0)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.AbstractService.Register(&endly.Route{
+		Action: "validate",
+		RequestInfo: &endly.ActionInfo{
+			Description: "validate workflow services, actions, requests and variables without running it",
+		},
+		RequestProvider: func() interface{} {
+			return &ValidateRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ValidateResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ValidateRequest); ok {
+				return s.validateWorkflow(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.AbstractService.Register(&endly.Route{
+		Action: "analyze",
+		RequestInfo: &endly.ActionInfo{
+			Description: "statically analyze workflow for unused variables, undefined state keys, unreachable tasks and unknown services",
+		},
+		RequestProvider: func() interface{} {
+			return &AnalyzeRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ValidateResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*AnalyzeRequest); ok {
+				return s.analyzeWorkflow(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.AbstractService.Register(&endly.Route{
+		Action: "graph",
+		RequestInfo: &endly.ActionInfo{
+			Description: "export workflow tasks, actions, transitions and referenced sub-workflows as DOT or Mermaid graph",
+		},
+		RequestProvider: func() interface{} {
+			return &GraphRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &GraphResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*GraphRequest); ok {
+				return s.graph(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
 	s.AbstractService.Register(&endly.Route{
 		Action: "register",
 		RequestInfo: &endly.ActionInfo{
@@ -854,6 +1410,31 @@ func (s *Service) registerRoutes() {
 		},
 	})
 
+	s.AbstractService.Register(&endly.Route{
+		Action: "macro",
+		RequestInfo: &endly.ActionInfo{
+			Description: "instantiate named reusable group of actions with parameters within the current workflow",
+			Examples: []*endly.UseCase{
+				{
+					Description: "macro",
+					Data:        workflowServiceMacroExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &MacroRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &MacroResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*MacroRequest); ok {
+				return s.runMacro(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
 	s.AbstractService.Register(&endly.Route{
 		Action: "exit",
 		RequestInfo: &endly.ActionInfo{
@@ -988,6 +1569,8 @@ func New() endly.Service {
 		AbstractService: endly.NewAbstractService(ServiceID),
 		Dao:             NewDao(),
 		registry:        make(map[string]*model.Workflow),
+		macros:          make(map[string]*model.Workflow),
+		debugger:        newDebugger(),
 	}
 	result.AbstractService.Service = result
 	result.registerRoutes()
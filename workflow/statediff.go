@@ -0,0 +1,254 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox/data"
+	"gopkg.in/yaml.v2"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var stateDiffTrackerKey = (*stateDiffTracker)(nil)
+
+//StateChange represents a changed state key value
+type StateChange struct {
+	From interface{}
+	To   interface{}
+}
+
+//StateDiff represents state difference between two snapshots, keys are flattened with dot i.e. app.build.version
+type StateDiff struct {
+	Added   map[string]interface{}  `json:",omitempty"`
+	Changed map[string]*StateChange `json:",omitempty"`
+	Removed []string                `json:",omitempty"`
+}
+
+//HasChanges returns true if diff has any change
+func (d *StateDiff) HasChanges() bool {
+	return len(d.Added)+len(d.Changed)+len(d.Removed) > 0
+}
+
+//StateSnapshot represents flattened encodable state copy
+type StateSnapshot map[string]interface{}
+
+//Diff returns difference between this and next snapshot
+func (s StateSnapshot) Diff(next StateSnapshot) *StateDiff {
+	var result = &StateDiff{
+		Added:   make(map[string]interface{}),
+		Changed: make(map[string]*StateChange),
+		Removed: make([]string, 0),
+	}
+	for key, value := range next {
+		previous, has := s[key]
+		if !has {
+			result.Added[key] = value
+			continue
+		}
+		if !reflect.DeepEqual(previous, value) {
+			result.Changed[key] = &StateChange{From: previous, To: value}
+		}
+	}
+	for key := range s {
+		if _, has := next[key]; !has {
+			result.Removed = append(result.Removed, key)
+		}
+	}
+	sort.Strings(result.Removed)
+	return result
+}
+
+//NewStateSnapshot creates a state snapshot, functions (UDFs) are excluded
+func NewStateSnapshot(state data.Map) StateSnapshot {
+	var result = make(StateSnapshot)
+	var aMap = map[string]interface{}{}
+	if buf, err := json.Marshal(state.AsEncodableMap()); err == nil {
+		_ = json.Unmarshal(buf, &aMap)
+	}
+	flattenState("", aMap, result)
+	return result
+}
+
+func flattenState(prefix string, source map[string]interface{}, target StateSnapshot) {
+	for key, value := range source {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if aMap, ok := value.(map[string]interface{}); ok && len(aMap) > 0 {
+			flattenState(key, aMap, target)
+			continue
+		}
+		if value == "func()" {
+			continue
+		}
+		target[key] = value
+	}
+}
+
+//StateDiffEvent represents task state diff event
+type StateDiffEvent struct {
+	Task string
+	*StateDiff
+}
+
+//Messages returns state diff messages
+func (e *StateDiffEvent) Messages() []*msg.Message {
+	info := ""
+	if content, err := yaml.Marshal(e.StateDiff); err == nil {
+		info = string(content)
+	}
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(e.Task, msg.MessageStyleGeneric),
+			msg.NewStyled("state diff", msg.MessageStyleGeneric),
+			msg.NewStyled(info, msg.MessageStyleOutput),
+		),
+	}
+}
+
+//NewStateDiffEvent creates a new state diff event
+func NewStateDiffEvent(task string, diff *StateDiff) *StateDiffEvent {
+	return &StateDiffEvent{Task: task, StateDiff: diff}
+}
+
+//StateTraceEvent represents action state trace event with redacted state snapshots before and after the action
+type StateTraceEvent struct {
+	Task    string `json:",omitempty"`
+	TagID   string `json:",omitempty"`
+	Service string
+	Action  string
+	Before  StateSnapshot
+	After   StateSnapshot
+	Diff    *StateDiff
+}
+
+//Messages returns state trace messages, only the diff is printed
+func (e *StateTraceEvent) Messages() []*msg.Message {
+	info := ""
+	if content, err := yaml.Marshal(e.Diff); err == nil {
+		info = string(content)
+	}
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("%v %v.%v", e.TagID, e.Service, e.Action), msg.MessageStyleGeneric),
+			msg.NewStyled("state trace", msg.MessageStyleGeneric),
+			msg.NewStyled(info, msg.MessageStyleOutput),
+		),
+	}
+}
+
+type stateDiffTracker struct {
+	mask    func(value interface{}) interface{}
+	tasks   bool //publish state diff at task boundary
+	actions bool //publish state trace for each action
+}
+
+//maskSnapshot masks snapshot values with sensitive key or resolved secret
+func (t *stateDiffTracker) maskSnapshot(snapshot StateSnapshot) StateSnapshot {
+	for key, value := range snapshot {
+		snapshot[key] = t.maskValue(key, value)
+	}
+	return snapshot
+}
+
+//maskValue masks value with sensitive key or resolved secret
+func (t *stateDiffTracker) maskValue(key string, value interface{}) interface{} {
+	for _, fragment := range strings.Split(key, ".") {
+		if endly.IsSensitiveKey(fragment) {
+			return endly.MaskedValue
+		}
+	}
+	if t.mask == nil {
+		return value
+	}
+	return t.mask(value)
+}
+
+func (t *stateDiffTracker) maskDiff(diff *StateDiff) {
+	for key, value := range diff.Added {
+		diff.Added[key] = t.maskValue(key, value)
+	}
+	for key, change := range diff.Changed {
+		change.From = t.maskValue(key, change.From)
+		change.To = t.maskValue(key, change.To)
+	}
+}
+
+func stateDiffTrackerFor(context *endly.Context) *stateDiffTracker {
+	if !context.Contains(stateDiffTrackerKey) {
+		return nil
+	}
+	var result *stateDiffTracker
+	context.GetInto(stateDiffTrackerKey, &result)
+	return result
+}
+
+func (s *Service) enableStateDiffIfNeeded(context *endly.Context, request *RunRequest) error {
+	if !(request.StateDiff || request.TraceState) {
+		return nil
+	}
+	if tracker := stateDiffTrackerFor(context); tracker != nil {
+		tracker.tasks = tracker.tasks || request.StateDiff
+		tracker.actions = tracker.actions || request.TraceState
+		return nil
+	}
+	return context.Put(stateDiffTrackerKey, &stateDiffTracker{mask: context.MaskValue, tasks: request.StateDiff, actions: request.TraceState})
+}
+
+//trackStateDiff captures state snapshot and returns a function publishing state diff since the snapshot
+func (s *Service) trackStateDiff(context *endly.Context, task string) func() {
+	tracker := stateDiffTrackerFor(context)
+	if tracker == nil || !tracker.tasks {
+		return func() {}
+	}
+	before := NewStateSnapshot(context.State())
+	return func() {
+		diff := before.Diff(NewStateSnapshot(context.State()))
+		if !diff.HasChanges() {
+			return
+		}
+		tracker.maskDiff(diff)
+		context.Publish(NewStateDiffEvent(task, diff))
+	}
+}
+
+func safeStateSnapshot(context *endly.Context) StateSnapshot {
+	var result StateSnapshot
+	context.SafeState().Read(func(state data.Map) {
+		result = NewStateSnapshot(state)
+	})
+	return result
+}
+
+//traceActionState captures state snapshot and returns a function publishing action state trace if state changed
+func (s *Service) traceActionState(context *endly.Context, process *model.Process, action *model.Action) func() {
+	tracker := stateDiffTrackerFor(context)
+	if tracker == nil || !tracker.actions {
+		return func() {}
+	}
+	before := safeStateSnapshot(context)
+	return func() {
+		after := safeStateSnapshot(context)
+		diff := before.Diff(after)
+		if !diff.HasChanges() {
+			return
+		}
+		tracker.maskDiff(diff)
+		var event = &StateTraceEvent{
+			Service: context.Expand(action.Service),
+			Action:  context.Expand(action.Action),
+			Before:  tracker.maskSnapshot(before),
+			After:   tracker.maskSnapshot(after),
+			Diff:    diff,
+		}
+		if action.MetaTag != nil {
+			event.TagID = action.TagID
+		}
+		if process.Task != nil {
+			event.Task = process.Task.Name
+		}
+		context.Publish(event)
+	}
+}
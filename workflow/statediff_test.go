@@ -0,0 +1,72 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"path"
+	"sync"
+	"testing"
+)
+
+func TestStateSnapshot_Diff(t *testing.T) {
+	var state = data.NewMap()
+	state.Put("app", map[string]interface{}{"name": "endly", "version": "1.0"})
+	state.Put("removed", 1)
+	state.Put("fn", func() {})
+	before := NewStateSnapshot(state)
+	assert.Equal(t, 3, len(before))
+
+	state.Put("app", map[string]interface{}{"name": "endly", "version": "1.1"})
+	state.Delete("removed")
+	state.Put("dbPassword", "abc123")
+	state.Put("items", []interface{}{1, 2})
+	diff := before.Diff(NewStateSnapshot(state))
+	assert.True(t, diff.HasChanges())
+	assert.Equal(t, []string{"removed"}, diff.Removed)
+	assert.EqualValues(t, "1.0", diff.Changed["app.version"].From)
+	assert.EqualValues(t, "1.1", diff.Changed["app.version"].To)
+	assert.Equal(t, 2, len(diff.Added))
+
+	tracker := &stateDiffTracker{}
+	tracker.maskDiff(diff)
+	assert.EqualValues(t, endly.MaskedValue, diff.Added["dbPassword"])
+	assert.EqualValues(t, []interface{}{1.0, 2.0}, diff.Added["items"])
+}
+
+func TestService_TraceState(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	var events = make([]*StateTraceEvent, 0)
+	var mux = &sync.Mutex{}
+	context.SetListener(func(event msg.Event) {
+		if trace, ok := event.Value().(*StateTraceEvent); ok {
+			mux.Lock()
+			events = append(events, trace)
+			mux.Unlock()
+		}
+	})
+	err := endly.Run(context, &RunRequest{URL: path.Join(parent, "test/trace/trace.yaml"), TraceState: true}, &RunResponse{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	mux.Lock()
+	defer mux.Unlock()
+	if !assert.Equal(t, 1, len(events)) { //nop action does not change state
+		return
+	}
+	printTrace := events[0]
+	assert.Equal(t, "build", printTrace.Task)
+	assert.Equal(t, "print", printTrace.Action)
+	if assert.NotNil(t, printTrace.Diff.Changed["version"]) {
+		assert.EqualValues(t, "1.0", printTrace.Diff.Changed["version"].From)
+		assert.EqualValues(t, "1.1", printTrace.Diff.Changed["version"].To)
+	}
+	assert.EqualValues(t, endly.MaskedValue, printTrace.Diff.Added["apiToken"])
+	assert.EqualValues(t, endly.MaskedValue, printTrace.After["apiToken"])
+	assert.EqualValues(t, "1.0", printTrace.Before["version"])
+	assert.EqualValues(t, "1.1", printTrace.After["version"])
+}
@@ -0,0 +1,211 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//StreamURI represents workflow events Server-Sent Events endpoint URI
+	StreamURI              = "/v1/endly/events"
+	streamSubscriberBuffer = 256
+)
+
+var eventStreamKey = (*EventStream)(nil)
+
+//StreamEvent represents streamed workflow event
+type StreamEvent struct {
+	Type      string
+	Timestamp time.Time
+	Value     interface{} `json:",omitempty"`
+	Error     string      `json:",omitempty"` //value encoding error
+}
+
+type streamFrame struct {
+	eventType string
+	data      []byte
+}
+
+//EventStream broadcasts workflow events (activity start/end, validation, errors) to HTTP clients as Server-Sent Events
+type EventStream struct {
+	Listener    msg.Listener
+	mux         *sync.RWMutex
+	mask        func(text string) string
+	subscribers map[chan []byte]map[string]bool //subscriber channel with optional event types filter
+	history     []*streamFrame
+	historySize int
+	closed      bool
+}
+
+//WithHistory enables replaying up to size the most recent events to a new subscriber
+func (s *EventStream) WithHistory(size int) *EventStream {
+	s.historySize = size
+	s.history = make([]*streamFrame, 0)
+	return s
+}
+
+//AsEventListener returns event listener that streams events and passes them to the next listener
+func (s *EventStream) AsEventListener() msg.Listener {
+	return func(event msg.Event) {
+		if s.Listener != nil {
+			s.Listener(event)
+		}
+		s.OnEvent(event)
+	}
+}
+
+//OnEvent broadcasts event to subscribers, a slow subscriber misses events rather than blocking the workflow
+func (s *EventStream) OnEvent(event msg.Event) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var frame []byte
+	if s.historySize > 0 && !s.closed {
+		frame = s.encode(event)
+		s.history = append(s.history, &streamFrame{eventType: event.Type(), data: frame})
+		if len(s.history) > s.historySize {
+			s.history = s.history[len(s.history)-s.historySize:]
+		}
+	}
+	for subscriber, types := range s.subscribers {
+		if len(types) > 0 && !types[event.Type()] {
+			continue
+		}
+		if frame == nil {
+			frame = s.encode(event)
+		}
+		select {
+		case subscriber <- frame:
+		default:
+		}
+	}
+}
+
+func (s *EventStream) encode(event msg.Event) []byte {
+	var streamEvent = &StreamEvent{Type: event.Type(), Timestamp: event.Timestamp(), Value: event.Value()}
+	payload, err := json.Marshal(streamEvent)
+	if err != nil {
+		streamEvent.Value = nil
+		streamEvent.Error = err.Error()
+		payload, _ = json.Marshal(streamEvent)
+	}
+	var data = string(payload)
+	if s.mask != nil {
+		data = s.mask(data)
+	}
+	return []byte(fmt.Sprintf("event: %v\ndata: %v\n\n", event.Type(), data))
+}
+
+func (s *EventStream) subscribe(types map[string]bool) chan []byte {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var result = make(chan []byte, streamSubscriberBuffer+len(s.history))
+	for _, frame := range s.history {
+		if len(types) == 0 || types[frame.eventType] {
+			result <- frame.data
+		}
+	}
+	if s.closed {
+		close(result)
+		return result
+	}
+	s.subscribers[result] = types
+	return result
+}
+
+func (s *EventStream) unsubscribe(subscriber chan []byte) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if _, ok := s.subscribers[subscriber]; ok {
+		delete(s.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+//ServeHTTP streams events to HTTP client, optional types query parameter filters streamed event types i.e. ?types=model_Activity,msg_ErrorEvent
+func (s *EventStream) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		http.Error(writer, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	var types = make(map[string]bool)
+	for _, eventType := range strings.Split(request.URL.Query().Get("types"), ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			types[eventType] = true
+		}
+	}
+	subscriber := s.subscribe(types)
+	defer s.unsubscribe(subscriber)
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.Header().Set("Connection", "keep-alive")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case frame, ok := <-subscriber:
+			if !ok {
+				return
+			}
+			if _, err := writer.Write(frame); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-request.Context().Done():
+			return
+		}
+	}
+}
+
+//Close ends all subscribers streams
+func (s *EventStream) Close() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.closed = true
+	for subscriber := range s.subscribers {
+		close(subscriber)
+	}
+	s.subscribers = make(map[chan []byte]map[string]bool)
+}
+
+//NewEventStream creates a new event stream
+func NewEventStream(listener msg.Listener, mask func(text string) string) *EventStream {
+	return &EventStream{
+		Listener:    listener,
+		mux:         &sync.RWMutex{},
+		mask:        mask,
+		subscribers: make(map[chan []byte]map[string]bool),
+	}
+}
+
+//enableStreamIfNeeded starts HTTP server streaming context events if stream address was specified
+func (s *Service) enableStreamIfNeeded(context *endly.Context, request *RunRequest) error {
+	if request.StreamAddress == "" || context.Contains(eventStreamKey) {
+		return nil
+	}
+	listener, err := net.Listen("tcp", request.StreamAddress)
+	if err != nil {
+		return fmt.Errorf("failed to start event stream on %v, %v", request.StreamAddress, err)
+	}
+	stream := NewEventStream(context.Listener, context.MaskSecrets)
+	router := http.NewServeMux()
+	router.Handle(StreamURI, stream)
+	server := &http.Server{Handler: router}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	context.Listener = stream.AsEventListener()
+	context.Deffer(func() {
+		stream.Close()
+		_ = server.Close()
+	})
+	context.Publish(msg.NewOutputEvent(fmt.Sprintf("http://%v%v", listener.Addr(), StreamURI), "stream", nil))
+	return context.Put(eventStreamKey, stream)
+}
@@ -0,0 +1,77 @@
+package workflow
+
+import (
+	"bufio"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/model/msg"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventStream_ServeHTTP(t *testing.T) {
+	var useCases = []struct {
+		description string
+		query       string
+		expect      []string
+	}{
+		{
+			description: "all events streamed",
+			expect:      []string{"event: msg_OutputEvent", "event: msg_ErrorEvent"},
+		},
+		{
+			description: "filtered events streamed",
+			query:       "?types=msg_ErrorEvent",
+			expect:      []string{"event: msg_ErrorEvent"},
+		},
+	}
+	for _, useCase := range useCases {
+		var listened = 0
+		stream := NewEventStream(func(event msg.Event) {
+			listened++
+		}, func(text string) string {
+			return strings.Replace(text, "secret123", "***", -1)
+		})
+		server := httptest.NewServer(stream)
+		response, err := http.Get(server.URL + useCase.query)
+		if !assert.Nil(t, err, useCase.description) {
+			server.Close()
+			continue
+		}
+		assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"), useCase.description)
+		for i := 0; i < 100 && !hasSubscribers(stream); i++ {
+			time.Sleep(time.Millisecond)
+		}
+		listener := stream.AsEventListener()
+		listener(msg.NewEvent(msg.NewOutputEvent("password: secret123", "test", nil)))
+		listener(msg.NewEvent(msg.NewErrorEvent("test error")))
+		stream.Close()
+
+		var events = make([]string, 0)
+		var data = make([]string, 0)
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "event:") {
+				events = append(events, line)
+			} else if strings.HasPrefix(line, "data:") {
+				data = append(data, line)
+			}
+		}
+		_ = response.Body.Close()
+		server.Close()
+		assert.Equal(t, 2, listened, useCase.description)
+		assert.Equal(t, useCase.expect, events, useCase.description)
+		for _, line := range data {
+			assert.False(t, strings.Contains(line, "secret123"), useCase.description)
+		}
+	}
+}
+
+func hasSubscribers(stream *EventStream) bool {
+	stream.mux.RLock()
+	defer stream.mux.RUnlock()
+	return len(stream.subscribers) > 0
+}
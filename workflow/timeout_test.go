@@ -0,0 +1,89 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox/data"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_RunWithTimeout(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+	var useCases = []struct {
+		description string
+		timeoutMs   int
+		sleep       time.Duration
+		hasError    bool
+	}{
+		{description: "no timeout", timeoutMs: 0, sleep: 10 * time.Millisecond},
+		{description: "within timeout", timeoutMs: 200, sleep: 10 * time.Millisecond},
+		{description: "timeout exceeded", timeoutMs: 20, sleep: 2 * time.Second, hasError: true},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		started := time.Now()
+		err := service.runWithTimeout(context, "action", "test", useCase.timeoutMs, func() error {
+			select {
+			case <-time.After(useCase.sleep):
+			case <-context.Done():
+			}
+			return nil
+		})
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			assert.True(t, time.Since(started) < time.Second, useCase.description)
+		} else {
+			assert.Nil(t, err, useCase.description)
+		}
+		assert.Nil(t, context.Err(), useCase.description)
+	}
+}
+
+func TestService_RunTask_Timeout(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+	context := manager.NewContext(nil)
+	task := newTestTask("t1", "", "nop", &NopRequest{})
+	task.TimeoutMs = 50
+	task.Actions[0].AbstractNode.SleepTimeMs = 300
+	next := newTestTask("t1", "", "nop", &NopRequest{}).Actions[0]
+	next.AbstractNode.Init = model.Variables{{Name: "nextRan", Value: true}}
+	task.Actions = append(task.Actions, next)
+	process := model.NewProcess(nil, &model.Workflow{AbstractNode: &model.AbstractNode{Name: "test"}}, nil)
+	process.State = data.NewMap()
+	_, err := service.runTask(context, process, task)
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "timed out"), err.Error())
+	}
+	time.Sleep(400 * time.Millisecond)
+	assert.False(t, context.SafeState().Has("nextRan"), "action after timeout should not run")
+	assert.Nil(t, context.Err())
+}
+
+func TestService_RunTask_HungActionTimeout(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+	context := manager.NewContext(nil)
+	task := newTestTask("t1", "", "nop", &NopRequest{})
+	task.TimeoutMs = 50
+	task.Actions[0].AbstractNode.SleepTimeMs = int((runTimeoutGracePeriod + 500*time.Millisecond) / time.Millisecond) //sleep ignores context cancellation
+	next := newTestTask("t1", "", "nop", &NopRequest{}).Actions[0]
+	next.AbstractNode.Init = model.Variables{{Name: "nextRan", Value: true}}
+	task.Actions = append(task.Actions, next)
+	process := model.NewProcess(nil, &model.Workflow{AbstractNode: &model.AbstractNode{Name: "test"}}, nil)
+	process.State = data.NewMap()
+	started := time.Now()
+	_, err := service.runTask(context, process, task)
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "timed out"), err.Error())
+	}
+	assert.True(t, time.Since(started) < runTimeoutGracePeriod+400*time.Millisecond, "hung action should not block timeout error")
+	assert.NotNil(t, context.Err(), "context should stay canceled while hung action runs")
+	time.Sleep(time.Second)
+	assert.False(t, context.SafeState().Has("nextRan"), "action after timeout should not run")
+	assert.Nil(t, context.Err())
+}
@@ -0,0 +1,297 @@
+package workflow
+
+import (
+	"fmt"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/util"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"sort"
+	"strings"
+)
+
+const (
+	//ValidationError represents validation issue that would fail workflow run
+	ValidationError = "error"
+	//ValidationWarning represents validation issue that may fail workflow run
+	ValidationWarning = "warning"
+)
+
+//runtimeStateKeys represents state keys set by workflow engine at runtime
+var runtimeStateKeys = []string{selfStateKey, paramsStateKey, dataStateKey, tasksStateKey, loopIndexKey, loopItemKey, "error", "errorJSON", "output", "in", "out"}
+
+//workflowValidator checks workflow services, actions, requests and variables without executing it
+type workflowValidator struct {
+	context   *endly.Context
+	state     data.Map
+	declared  map[string]bool
+	undefined map[string]bool
+	response  *ValidateResponse
+}
+
+func (v *workflowValidator) addIssue(level string, task *model.Task, action *model.Action, message string) {
+	var issue = &ValidationIssue{Level: level, Message: message}
+	if task != nil {
+		issue.Task = task.Name
+	}
+	if action != nil && action.MetaTag != nil {
+		issue.TagID = action.TagID
+	}
+	if level == ValidationError {
+		v.response.Valid = false
+	}
+	v.response.Issues = append(v.response.Issues, issue)
+}
+
+func (v *workflowValidator) declareVariables(variables model.Variables) {
+	for _, variable := range variables {
+		v.declare(variable.Name)
+	}
+}
+
+func (v *workflowValidator) declare(name string) {
+	if root := variableRoot(name); root != "" {
+		v.declared[root] = true
+	}
+}
+
+//declareNode collects state keys that tasks and actions define at runtime
+func (v *workflowValidator) declareNode(node *model.TasksNode) {
+	if node == nil {
+		return
+	}
+	for _, task := range node.Tasks {
+		if task.AbstractNode != nil {
+			v.declareVariables(task.AbstractNode.Init)
+			v.declareVariables(task.AbstractNode.Post)
+		}
+		for _, action := range task.Actions {
+			if action.AbstractNode != nil {
+				v.declareVariables(action.AbstractNode.Init)
+				v.declareVariables(action.AbstractNode.Post)
+				v.declare(action.Name)
+			}
+			if action.ServiceRequest != nil {
+				v.declare(action.Action)
+			}
+			if action.Repeater != nil {
+				v.declareVariables(action.Repeater.Variables)
+				for _, extract := range action.Repeater.Extract {
+					v.declare(extract.Key)
+				}
+			}
+		}
+		v.declareNode(task.TasksNode)
+	}
+}
+
+//checkReferences reports variables referenced by source that are never defined
+func (v *workflowValidator) checkReferences(task *model.Task, action *model.Action, source interface{}) {
+	for _, name := range variableReferences(source) {
+		if v.declared[name] || v.state.Has(name) {
+			continue
+		}
+		key := name
+		if task != nil {
+			key = task.Name + "." + name
+		}
+		if v.undefined[key] {
+			continue
+		}
+		v.undefined[key] = true
+		v.addIssue(ValidationWarning, task, action, fmt.Sprintf("undefined variable: $%v", name))
+	}
+}
+
+func (v *workflowValidator) checkVariables(task *model.Task, action *model.Action, variables model.Variables) {
+	for _, variable := range variables {
+		v.checkReferences(task, action, variable.Value)
+		v.checkReferences(task, action, variable.When)
+		v.checkReferences(task, action, variable.Else)
+	}
+}
+
+func (v *workflowValidator) checkNode(task *model.Task, node *model.AbstractNode) {
+	if node == nil {
+		return
+	}
+	v.checkReferences(task, nil, node.When)
+	v.checkVariables(task, nil, node.Init)
+	v.checkVariables(task, nil, node.Post)
+}
+
+//checkAction checks that action service and route exist and that action request converts to typed request
+func (v *workflowValidator) checkAction(task *model.Task, action *model.Action) {
+	if action.AbstractNode != nil {
+		v.checkReferences(task, action, action.When)
+		v.checkVariables(task, action, action.AbstractNode.Init)
+		v.checkVariables(task, action, action.AbstractNode.Post)
+	}
+	v.checkReferences(task, action, action.Skip)
+	v.checkReferences(task, action, action.ForEach)
+	if action.Repeater != nil {
+		v.checkReferences(task, action, action.Repeater.Exit)
+	}
+	if action.ServiceRequest == nil {
+		return
+	}
+	request, err := util.NormalizeMap(action.Request, true)
+	if err != nil {
+		v.addIssue(ValidationError, task, action, fmt.Sprintf("invalid %v:%v request: %v", action.Service, action.Action, err))
+		return
+	}
+	v.checkReferences(task, action, request)
+	service, err := v.context.Service(action.Service)
+	if err != nil {
+		v.addIssue(ValidationError, task, action, fmt.Sprintf("unknown service: %v", action.Service))
+		return
+	}
+	route, err := service.Route(action.Action)
+	if err != nil {
+		v.addIssue(ValidationError, task, action, fmt.Sprintf("unknown %v service action: %v", action.Service, action.Action))
+		return
+	}
+	if len(request) == 0 || route.RequestProvider == nil {
+		return
+	}
+	expanded := v.state.Expand(request)
+	if len(variableReferences(expanded)) > 0 || !toolbox.IsMap(expanded) {
+		return //request is resolved at runtime
+	}
+	if err = convertRequest(route, toolbox.AsMap(expanded)); err != nil {
+		v.addIssue(ValidationError, task, action, fmt.Sprintf("invalid %v:%v request: %v", action.Service, action.Action, err))
+	}
+}
+
+func (v *workflowValidator) checkTasks(node *model.TasksNode) {
+	if node == nil {
+		return
+	}
+	for _, task := range node.Tasks {
+		v.checkNode(task, task.AbstractNode)
+		for _, action := range task.Actions {
+			v.checkAction(task, action)
+		}
+		v.checkTasks(task.TasksNode)
+	}
+}
+
+//convertRequest converts raw request into route typed request
+func convertRequest(route *endly.Route, rawRequest map[string]interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return toolbox.DefaultConverter.AssignConverted(route.RequestProvider(), rawRequest)
+}
+
+//variableRoot returns root state key of variable expression or name, i.e. app for ${app.name}
+func variableRoot(expression string) string {
+	expression = strings.TrimSpace(expression)
+	expression = strings.TrimPrefix(expression, "$")
+	expression = strings.Trim(expression, "{}")
+	expression = strings.TrimLeft(expression, "-+<>*!")
+	expression = strings.TrimRight(expression, "+-")
+	if index := strings.IndexAny(expression, ".[ "); index != -1 {
+		expression = expression[:index]
+	}
+	return expression
+}
+
+//variableReferences returns sorted root state keys referenced by source expressions
+func variableReferences(source interface{}) []string {
+	var unique = make(map[string]bool)
+	collectReferences(source, unique)
+	var result = make([]string, 0, len(unique))
+	for name := range unique {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func collectReferences(source interface{}, references map[string]bool) {
+	switch value := source.(type) {
+	case nil:
+	case string:
+		if !strings.Contains(value, "$") {
+			return
+		}
+		data.Parse(value, func(expression string, isUDF bool, argument interface{}) (interface{}, bool) {
+			if isUDF {
+				collectReferences(toolbox.AsString(argument), references)
+				return nil, false
+			}
+			if root := variableRoot(expression); root != "" && !toolbox.IsInt(root) {
+				references[root] = true
+			}
+			return nil, false
+		})
+	default:
+		if toolbox.IsMap(source) {
+			_ = toolbox.ProcessMap(source, func(key, value interface{}) bool {
+				collectReferences(value, references)
+				return true
+			})
+		} else if toolbox.IsSlice(source) {
+			toolbox.ProcessSlice(source, func(item interface{}) bool {
+				collectReferences(item, references)
+				return true
+			})
+		}
+	}
+}
+
+func (s *Service) validateWorkflow(context *endly.Context, request *ValidateRequest) (*ValidateResponse, error) {
+	workflow, err := s.Dao.Load(context, request.Source)
+	if err != nil {
+		var response = &ValidateResponse{Issues: []*ValidationIssue{{Level: ValidationError, Message: fmt.Sprintf("failed to load workflow: %v", err)}}}
+		if workflow != nil {
+			response.Workflow = workflow.Name
+		}
+		return response, nil
+	}
+	return s.validate(context, workflow, request.Params)
+}
+
+//validate checks loaded workflow with supplied params
+func (s *Service) validate(context *endly.Context, workflow *model.Workflow, params map[string]interface{}) (*ValidateResponse, error) {
+	validator, err := s.newValidator(context, workflow, params)
+	if err != nil {
+		return nil, err
+	}
+	validator.checkNode(nil, workflow.AbstractNode)
+	validator.checkTasks(workflow.TasksNode)
+	return validator.response, nil
+}
+
+//newValidator creates workflow validator with state keys declared by params, engine and workflow variables
+func (s *Service) newValidator(context *endly.Context, workflow *model.Workflow, params map[string]interface{}) (*workflowValidator, error) {
+	var response = &ValidateResponse{Workflow: workflow.Name, Valid: true, Issues: make([]*ValidationIssue, 0)}
+	var validator = &workflowValidator{
+		context:   context,
+		state:     context.SafeState().Clone(),
+		declared:  make(map[string]bool),
+		undefined: make(map[string]bool),
+		response:  response,
+	}
+	for _, key := range runtimeStateKeys {
+		validator.declare(key)
+	}
+	params, err := util.NormalizeMap(params, true)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range params {
+		validator.state.Put(key, value)
+	}
+	validator.state.Put(paramsStateKey, params)
+	if workflow.AbstractNode != nil {
+		validator.declareVariables(workflow.AbstractNode.Init)
+		validator.declareVariables(workflow.AbstractNode.Post)
+	}
+	validator.declareNode(workflow.TasksNode)
+	return validator, nil
+}
@@ -0,0 +1,177 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/url"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestService_ValidateWorkflow(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	service := New().(*Service)
+	var useCases = []struct {
+		description string
+		URI         string
+		params      map[string]interface{}
+		valid       bool
+		issues      []*ValidationIssue
+	}{
+		{
+			description: "valid workflow",
+			URI:         "valid.yaml",
+			params:      map[string]interface{}{"appName": "myapp", "version": "1.0"},
+			valid:       true,
+			issues:      []*ValidationIssue{},
+		},
+		{
+			description: "valid workflow with undefined variable",
+			URI:         "valid.yaml",
+			params:      map[string]interface{}{"appName": "myapp"},
+			valid:       true,
+			issues: []*ValidationIssue{
+				{Level: ValidationWarning, Task: "build", Message: "undefined variable: $version"},
+			},
+		},
+		{
+			description: "invalid workflow",
+			URI:         "invalid.yaml",
+			valid:       false,
+			issues: []*ValidationIssue{
+				{Level: ValidationError, Task: "build", TagID: "build_a", Message: "unknown service: unknown"},
+				{Level: ValidationError, Task: "build", TagID: "build_b", Message: "unknown workflow service action: unknown"},
+				{Level: ValidationError, Task: "build", TagID: "build_c", Message: "invalid workflow:run request"},
+				{Level: ValidationWarning, Task: "deploy", TagID: "deploy_a", Message: "undefined variable: $app"},
+			},
+		},
+		{
+			description: "missing workflow",
+			URI:         "missing.yaml",
+			valid:       false,
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		response, err := service.validateWorkflow(context, &ValidateRequest{Source: url.NewResource(path.Join(parent, "test/validate", useCase.URI)), Params: useCase.params})
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.valid, response.Valid, useCase.description)
+		if useCase.issues == nil {
+			continue
+		}
+		if !assert.Equal(t, len(useCase.issues), len(response.Issues), useCase.description) {
+			continue
+		}
+		for i, expect := range useCase.issues {
+			actual := response.Issues[i]
+			assert.Equal(t, expect.Level, actual.Level, useCase.description)
+			assert.Equal(t, expect.Task, actual.Task, useCase.description)
+			assert.Equal(t, expect.TagID, actual.TagID, useCase.description)
+			assert.True(t, strings.HasPrefix(actual.Message, expect.Message), useCase.description+" "+actual.Message)
+		}
+	}
+}
+
+func TestService_DryRun(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	var useCases = []struct {
+		description string
+		URI         string
+		hasError    bool
+	}{
+		{
+			description: "valid workflow",
+			URI:         "valid.yaml",
+		},
+		{
+			description: "invalid workflow",
+			URI:         "invalid.yaml",
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		request := &RunRequest{
+			URL:               path.Join(parent, "test/validate", useCase.URI),
+			Params:            map[string]interface{}{"appName": "myapp", "version": "1.0"},
+			PublishParameters: true,
+			DryRun:            true,
+		}
+		response := &RunResponse{}
+		err := endly.Run(context, request, response)
+		if useCase.hasError {
+			if assert.NotNil(t, err, useCase.description) {
+				assert.True(t, strings.Contains(err.Error(), "workflow invalid is invalid"), useCase.description)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, useCase.description) || !assert.NotNil(t, response.Validation, useCase.description) {
+			continue
+		}
+		assert.True(t, response.Validation.Valid, useCase.description)
+		assert.Equal(t, 0, len(response.Validation.Issues), useCase.description)
+		assert.Equal(t, 0, len(response.Data), useCase.description)
+	}
+}
+
+func TestService_AnalyzeWorkflow(t *testing.T) {
+	parent := toolbox.CallerDirectory(3)
+	manager := endly.New()
+	service := New().(*Service)
+	var useCases = []struct {
+		description string
+		params      map[string]interface{}
+		issues      []*ValidationIssue
+	}{
+		{
+			description: "dev params",
+			params:      map[string]interface{}{"appName": "myapp", "env": "dev", "stage": false},
+			issues: []*ValidationIssue{
+				{Level: ValidationWarning, Task: "deployProd", TagID: "deploy_a", Message: "undefined variable: $target"},
+				{Level: ValidationError, Task: "never", TagID: "never_a", Message: "unknown service: cloud"},
+				{Level: ValidationWarning, Message: "unused variable: unusedFlag"},
+				{Level: ValidationWarning, Task: "build", TagID: "build_a", Message: "unused variable: buildOutput"},
+				{Level: ValidationWarning, Task: "deployProd", Message: "unreachable task: when '$env = prod'"},
+				{Level: ValidationWarning, Task: "deployStage", Message: "unreachable task: when '$params.stage = true'"},
+				{Level: ValidationWarning, Task: "never", Message: "unreachable task: when '1 = 2'"},
+			},
+		},
+		{
+			description: "prod params",
+			params:      map[string]interface{}{"appName": "myapp", "env": "prod", "stage": true, "target": "prod"},
+			issues: []*ValidationIssue{
+				{Level: ValidationError, Task: "never", TagID: "never_a", Message: "unknown service: cloud"},
+				{Level: ValidationWarning, Message: "unused variable: unusedFlag"},
+				{Level: ValidationWarning, Task: "build", TagID: "build_a", Message: "unused variable: buildOutput"},
+				{Level: ValidationWarning, Task: "never", Message: "unreachable task: when '1 = 2'"},
+			},
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		response, err := service.analyzeWorkflow(context, &AnalyzeRequest{Source: url.NewResource(path.Join(parent, "test/analyze/analyze.yaml")), Params: useCase.params})
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		assert.False(t, response.Valid, useCase.description)
+		if !assert.Equal(t, len(useCase.issues), len(response.Issues), useCase.description) {
+			for _, issue := range response.Issues {
+				t.Logf("%v %v %v %v", issue.Level, issue.Task, issue.TagID, issue.Message)
+			}
+			continue
+		}
+		for i, expect := range useCase.issues {
+			actual := response.Issues[i]
+			assert.Equal(t, expect.Level, actual.Level, useCase.description)
+			assert.Equal(t, expect.Task, actual.Task, useCase.description)
+			assert.Equal(t, expect.TagID, actual.TagID, useCase.description)
+			assert.True(t, strings.HasPrefix(actual.Message, expect.Message), useCase.description+" "+actual.Message)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package workflow
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox/data"
+	"testing"
+)
+
+func TestService_RunTypedVariables(t *testing.T) {
+	manager := endly.New()
+	service := New().(*Service)
+	var useCases = []struct {
+		description string
+		task        func() *model.Task
+		expect      string
+	}{
+		{
+			description: "task init",
+			task: func() *model.Task {
+				task := newTestTask("build", "", "nop", &NopRequest{})
+				task.Init = model.Variables{{Name: "port", Value: "$params.port", Type: "int"}}
+				return task
+			},
+			expect: "task build init: variable 'port'",
+		},
+		{
+			description: "action post",
+			task: func() *model.Task {
+				task := newTestTask("build", "", "nop", &NopRequest{})
+				task.Actions[0].Name = "compile"
+				task.Actions[0].Post = model.Variables{{Name: "buildPath", From: "path", Required: true}}
+				return task
+			},
+			expect: "action compile post: variable 'buildPath' is required",
+		},
+	}
+	for _, useCase := range useCases {
+		context := manager.NewContext(nil)
+		context.SafeState().SetValue("params", map[string]interface{}{"port": "abc"})
+		process := model.NewProcess(nil, &model.Workflow{AbstractNode: &model.AbstractNode{Name: "test"}}, nil)
+		process.State = data.NewMap()
+		err := service.runTasks(context, process, &model.TasksNode{Tasks: []*model.Task{useCase.task()}})
+		if assert.NotNil(t, err, useCase.description) {
+			assert.Contains(t, err.Error(), useCase.expect, useCase.description)
+		}
+	}
+}